@@ -27,12 +27,15 @@ import (
 )
 
 const (
-	ValidateCRDWebhookConfigName    = "kubeedge-crds-validate-webhook-configuration"
-	ValidateDeviceWebhookName       = "validatedevice.kubeedge.io"
-	ValidateDeviceModelWebhookName  = "validatedevicemodel.kubeedge.io"
-	ValidateRuleWebhookName         = "validatedrule.kubeedge.io"
-	ValidateRuleEndpointWebhookName = "validatedruleendpoint.kubeedge.io"
-	ValidateNodeUpgradeWebhookName  = "validatenodeupgradejob.kubeedge.io"
+	ValidateCRDWebhookConfigName      = "kubeedge-crds-validate-webhook-configuration"
+	ValidateDeviceWebhookName         = "validatedevice.kubeedge.io"
+	ValidateDeviceModelWebhookName    = "validatedevicemodel.kubeedge.io"
+	ValidateRuleWebhookName           = "validatedrule.kubeedge.io"
+	ValidateRuleEndpointWebhookName   = "validatedruleendpoint.kubeedge.io"
+	ValidateNodeUpgradeWebhookName    = "validatenodeupgradejob.kubeedge.io"
+	ValidateImagePrePullWebhookName   = "validateimageprepulljob.kubeedge.io"
+	ValidateCommonJobWebhookName      = "validatecommonjob.kubeedge.io"
+	ValidateTaskCredentialWebhookName = "validatetaskcredential.kubeedge.io"
 
 	OfflineMigrationConfigName  = "mutate-offlinemigration"
 	OfflineMigrationWebhookName = "mutateofflinemigration.kubeedge.io"
@@ -64,7 +67,7 @@ func addToScheme(scheme *runtime.Scheme) {
 
 // AdmissionController implements the admission webhook for validation of configuration.
 type AdmissionController struct {
-	Client    *kubernetes.Clientset
+	Client    kubernetes.Interface
 	CrdClient *versioned.Clientset
 }
 
@@ -107,6 +110,9 @@ func Run(opt *options.AdmissionOptions) error {
 	http.HandleFunc("/offlinemigration", serveOfflineMigration)
 	http.HandleFunc("/nodeupgradejobs", serveNodeUpgradeJob)
 	http.HandleFunc("/mutating/nodeupgradejobs", serveMutatingNodeUpgradeJob)
+	http.HandleFunc("/imageprepulljobs", serveImagePrePullJob)
+	http.HandleFunc("/commonjobs", serveCommonJob)
+	http.HandleFunc("/taskcredentials", serveTaskCredential)
 
 	tlsConfig, err := configTLS(opt, restConfig)
 	if err != nil {
@@ -302,6 +308,86 @@ func (ac *AdmissionController) registerWebhooks(opt *options.AdmissionOptions, c
 				SideEffects:             &noneSideEffect,
 				AdmissionReviewVersions: []string{"v1"},
 			},
+			// ImagePrePullJob validating webhook
+			{
+				Name: ValidateImagePrePullWebhookName,
+				Rules: []admissionregistrationv1.RuleWithOperations{{
+					Operations: []admissionregistrationv1.OperationType{
+						admissionregistrationv1.Update,
+					},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{"operations.kubeedge.io"},
+						APIVersions: []string{"v1alpha1"},
+						Resources:   []string{"imageprepulljobs"},
+					},
+				}},
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{
+						Namespace: opt.AdmissionServiceNamespace,
+						Name:      opt.AdmissionServiceName,
+						Path:      strPtr("/imageprepulljobs"),
+						Port:      &opt.Port,
+					},
+					CABundle: cabundle,
+				},
+				FailurePolicy:           &failPolicy,
+				SideEffects:             &noneSideEffect,
+				AdmissionReviewVersions: []string{"v1"},
+			},
+			// validating webhook of the jobs embedding CommonJobSpec
+			{
+				Name: ValidateCommonJobWebhookName,
+				Rules: []admissionregistrationv1.RuleWithOperations{{
+					Operations: []admissionregistrationv1.OperationType{
+						admissionregistrationv1.Update,
+					},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{"operations.kubeedge.io"},
+						APIVersions: []string{"v1alpha1"},
+						Resources:   commonJobResources(),
+					},
+				}},
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{
+						Namespace: opt.AdmissionServiceNamespace,
+						Name:      opt.AdmissionServiceName,
+						Path:      strPtr("/commonjobs"),
+						Port:      &opt.Port,
+					},
+					CABundle: cabundle,
+				},
+				FailurePolicy:           &failPolicy,
+				SideEffects:             &noneSideEffect,
+				AdmissionReviewVersions: []string{"v1"},
+			},
+			// task credential validating webhook of the jobs of the operations group, all its resources are
+			// matched so that the task types registered out of tree in the group are validated as well
+			{
+				Name: ValidateTaskCredentialWebhookName,
+				Rules: []admissionregistrationv1.RuleWithOperations{{
+					Operations: []admissionregistrationv1.OperationType{
+						admissionregistrationv1.Create,
+						admissionregistrationv1.Update,
+					},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{"operations.kubeedge.io"},
+						APIVersions: []string{"v1alpha1"},
+						Resources:   []string{"*"},
+					},
+				}},
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{
+						Namespace: opt.AdmissionServiceNamespace,
+						Name:      opt.AdmissionServiceName,
+						Path:      strPtr("/taskcredentials"),
+						Port:      &opt.Port,
+					},
+					CABundle: cabundle,
+				},
+				FailurePolicy:           &failPolicy,
+				SideEffects:             &noneSideEffect,
+				AdmissionReviewVersions: []string{"v1"},
+			},
 		},
 	}
 	if err := registerValidateWebhook(ac.Client.AdmissionregistrationV1().ValidatingWebhookConfigurations(),
@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admissioncontroller
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// mutableCommonJobFields are the fields of CommonJobSpec which can be updated while the job runs,
+// the executor of the job picks them up at once
+var mutableCommonJobFields = []string{"concurrency", "failureTolerate", "rerun"}
+
+// commonJobs create the jobs embedding CommonJobSpec by their resource
+var commonJobs = map[string]func() runtime.Object{
+	"backupjobs":        func() runtime.Object { return &v1alpha1.BackupJob{} },
+	"restorejobs":       func() runtime.Object { return &v1alpha1.RestoreJob{} },
+	"configupdatejobs":  func() runtime.Object { return &v1alpha1.ConfigUpdateJob{} },
+	"noderestartjobs":   func() runtime.Object { return &v1alpha1.NodeRestartJob{} },
+	"diagnosejobs":      func() runtime.Object { return &v1alpha1.DiagnoseJob{} },
+	"osupgradejobs":     func() runtime.Object { return &v1alpha1.OSUpgradeJob{} },
+	"supportbundlejobs": func() runtime.Object { return &v1alpha1.SupportBundleJob{} },
+}
+
+// commonJobResources returns the resources of the jobs embedding CommonJobSpec, sorted so that the
+// registered webhook does not change between restarts
+func commonJobResources() []string {
+	resources := make([]string, 0, len(commonJobs))
+	for resource := range commonJobs {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+	return resources
+}
+
+func serveCommonJob(w http.ResponseWriter, r *http.Request) {
+	serve(w, r, admitCommonJob)
+}
+
+// admitCommonJob keeps the spec of a job embedding CommonJobSpec immutable once it is created,
+// except mutableCommonJobFields
+func admitCommonJob(review admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	if review.Request.Operation != admissionv1.Update {
+		return admissionResponse(nil)
+	}
+	newJob, ok := commonJobs[review.Request.Resource.Resource]
+	if !ok {
+		return admissionResponse(fmt.Errorf("resource %s is not a job with a common job spec", review.Request.Resource.Resource))
+	}
+	deserializer := codecs.UniversalDeserializer()
+	job := newJob()
+	if _, _, err := deserializer.Decode(review.Request.Object.Raw, nil, job); err != nil {
+		return admissionResponse(fmt.Errorf("validation failed with error: %v", err))
+	}
+	oldJob := newJob()
+	if _, _, err := deserializer.Decode(review.Request.OldObject.Raw, nil, oldJob); err != nil {
+		return admissionResponse(fmt.Errorf("validation failed with error: %v", err))
+	}
+
+	oldSpec := reflect.ValueOf(oldJob).Elem().FieldByName("Spec").Interface()
+	newSpec := reflect.ValueOf(job).Elem().FieldByName("Spec").Interface()
+	if changed := changedSpecFields(oldSpec, newSpec, mutableCommonJobFields); len(changed) != 0 {
+		return admissionResponse(fmt.Errorf("spec fields %s are immutable once the job is created, only %s can be updated",
+			strings.Join(changed, ", "), strings.Join(mutableCommonJobFields, ", ")))
+	}
+	return admissionResponse(nil)
+}
@@ -0,0 +1,65 @@
+package admissioncontroller
+
+import (
+	"encoding/json"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+func Test_admitCommonJobUpdate(t *testing.T) {
+	restart := func(mutate func(spec *v1alpha1.NodeRestartJobSpec)) runtime.RawExtension {
+		job := v1alpha1.NodeRestartJob{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "NodeRestartJob"},
+			ObjectMeta: metav1.ObjectMeta{Name: "restart"},
+		}
+		job.Spec.NodeNames = []string{"edge-1"}
+		mutate(&job.Spec)
+		raw, _ := json.Marshal(job)
+		return runtime.RawExtension{Raw: raw}
+	}
+	old := restart(func(*v1alpha1.NodeRestartJobSpec) {})
+	tests := []struct {
+		name     string
+		resource string
+		object   runtime.RawExtension
+		allowed  bool
+	}{
+		{name: "unchanged", object: old, allowed: true},
+		{
+			name: "tune concurrency and failure tolerance",
+			object: restart(func(spec *v1alpha1.NodeRestartJobSpec) {
+				tolerate := intstr.FromString("20%")
+				spec.Concurrency, spec.FailureTolerate = 5, &tolerate
+			}),
+			allowed: true,
+		},
+		{name: "rerun", object: restart(func(spec *v1alpha1.NodeRestartJobSpec) { spec.Rerun = 1 }), allowed: true},
+		{name: "change nodes", object: restart(func(spec *v1alpha1.NodeRestartJobSpec) { spec.NodeNames = []string{"edge-2"} })},
+		{name: "change metadata", object: restart(func(spec *v1alpha1.NodeRestartJobSpec) { spec.Metadata = map[string]string{"ticket": "1"} })},
+		{name: "change reboot", object: restart(func(spec *v1alpha1.NodeRestartJobSpec) { spec.Reboot = true })},
+		{name: "unknown resource", resource: "nodeupgradejobs", object: old},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resource := test.resource
+			if resource == "" {
+				resource = "noderestartjobs"
+			}
+			review := admissionv1.AdmissionReview{Request: &admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Resource:  metav1.GroupVersionResource{Group: v1alpha1.GroupName, Version: "v1alpha1", Resource: resource},
+				Object:    test.object,
+				OldObject: old,
+			}}
+			if resp := admitCommonJob(review); resp.Allowed != test.allowed {
+				t.Errorf("expected allowed %t, got %t: %v", test.allowed, resp.Allowed, resp.Result)
+			}
+		})
+	}
+}
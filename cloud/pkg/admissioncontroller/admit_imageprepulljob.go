@@ -0,0 +1,63 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admissioncontroller
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// mutablePrePullFields are the fields of the template of an ImagePrePullJob which can be updated
+// while it runs, the executor of the job picks them up at once
+var mutablePrePullFields = []string{"rerun", "concurrency", "failureTolerate"}
+
+func serveImagePrePullJob(w http.ResponseWriter, r *http.Request) {
+	serve(w, r, admitImagePrePullJob)
+}
+
+// admitImagePrePullJob keeps the spec of an ImagePrePullJob immutable once it is created, except
+// mutablePrePullFields of its template
+func admitImagePrePullJob(review admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	if review.Request.Operation != admissionv1.Update {
+		return admissionResponse(nil)
+	}
+	deserializer := codecs.UniversalDeserializer()
+	newJob := v1alpha1.ImagePrePullJob{}
+	if _, _, err := deserializer.Decode(review.Request.Object.Raw, nil, &newJob); err != nil {
+		return admissionResponse(fmt.Errorf("validation failed with error: %v", err))
+	}
+	oldJob := v1alpha1.ImagePrePullJob{}
+	if _, _, err := deserializer.Decode(review.Request.OldObject.Raw, nil, &oldJob); err != nil {
+		return admissionResponse(fmt.Errorf("validation failed with error: %v", err))
+	}
+
+	oldSpec, newSpec := oldJob.Spec, newJob.Spec
+	oldTemplate, newTemplate := oldSpec.ImagePrePullTemplate, newSpec.ImagePrePullTemplate
+	oldSpec.ImagePrePullTemplate, newSpec.ImagePrePullTemplate = v1alpha1.ImagePrePullTemplate{}, v1alpha1.ImagePrePullTemplate{}
+	changed := changedSpecFields(oldSpec, newSpec, nil)
+	changed = append(changed, changedSpecFields(oldTemplate, newTemplate, mutablePrePullFields)...)
+	if len(changed) != 0 {
+		return admissionResponse(fmt.Errorf("spec fields %s are immutable once the job is created, only %s can be updated",
+			strings.Join(changed, ", "), strings.Join(mutablePrePullFields, ", ")))
+	}
+	return admissionResponse(nil)
+}
@@ -0,0 +1,61 @@
+package admissioncontroller
+
+import (
+	"encoding/json"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+func Test_admitImagePrePullJobUpdate(t *testing.T) {
+	prePull := func(mutate func(spec *v1alpha1.ImagePrePullJobSpec)) runtime.RawExtension {
+		job := v1alpha1.ImagePrePullJob{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "ImagePrePullJob"},
+			ObjectMeta: metav1.ObjectMeta{Name: "prepull"},
+			Spec: v1alpha1.ImagePrePullJobSpec{ImagePrePullTemplate: v1alpha1.ImagePrePullTemplate{
+				Images:    []string{"nginx:1.25"},
+				NodeNames: []string{"edge-1"},
+			}},
+		}
+		mutate(&job.Spec)
+		raw, _ := json.Marshal(job)
+		return runtime.RawExtension{Raw: raw}
+	}
+	old := prePull(func(*v1alpha1.ImagePrePullJobSpec) {})
+	tests := []struct {
+		name    string
+		object  runtime.RawExtension
+		allowed bool
+	}{
+		{name: "unchanged", object: old, allowed: true},
+		{
+			name: "tune concurrency and failure tolerance",
+			object: prePull(func(spec *v1alpha1.ImagePrePullJobSpec) {
+				tolerate := intstr.FromString("20%")
+				spec.ImagePrePullTemplate.Concurrency, spec.ImagePrePullTemplate.FailureTolerate = 5, &tolerate
+			}),
+			allowed: true,
+		},
+		{name: "rerun", object: prePull(func(spec *v1alpha1.ImagePrePullJobSpec) { spec.ImagePrePullTemplate.Rerun = 1 }), allowed: true},
+		{name: "change images", object: prePull(func(spec *v1alpha1.ImagePrePullJobSpec) { spec.ImagePrePullTemplate.Images = []string{"nginx:1.26"} })},
+		{name: "change check items", object: prePull(func(spec *v1alpha1.ImagePrePullJobSpec) { spec.ImagePrePullTemplate.CheckItems = []string{"cpu"} })},
+		{name: "change nodes", object: prePull(func(spec *v1alpha1.ImagePrePullJobSpec) { spec.ImagePrePullTemplate.NodeNames = []string{"edge-2"} })},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			review := admissionv1.AdmissionReview{Request: &admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Object:    test.object,
+				OldObject: old,
+			}}
+			if resp := admitImagePrePullJob(review); resp.Allowed != test.allowed {
+				t.Errorf("expected allowed %t, got %t: %v", test.allowed, resp.Allowed, resp.Result)
+			}
+		})
+	}
+}
@@ -18,17 +18,22 @@ package admissioncontroller
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/blang/semver"
 	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog/v2"
 
+	taskutil "github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
 	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
 )
 
@@ -64,10 +69,21 @@ func admitNodeUpgradeJob(review admissionv1.AdmissionReview) *admissionv1.Admiss
 			return admissionResponse(fmt.Errorf("validation failed with error: %v", err))
 		}
 
-		// For update, we don't allow update spec fields once an Upgrade is created.
-		if !reflect.DeepEqual(oldUpgrade.Spec, newUpgrade.Spec) {
-			err := errors.New("spec fields are not allowed to update once it's created")
-			return admissionResponse(err)
+		// An Upgrade starts running once it is created, its spec fields are immutable from then on except
+		// mutableUpgradeFields. A dry run can be turned into the upgrade when it is rerun, and the canary
+		// can only be approved.
+		oldSpec, newSpec := oldUpgrade.Spec, newUpgrade.Spec
+		if oldSpec.Rerun != newSpec.Rerun {
+			oldSpec.DryRun, newSpec.DryRun = false, false
+		}
+		if oldSpec.Canary != nil && newSpec.Canary != nil {
+			oldCanary, newCanary := *oldSpec.Canary, *newSpec.Canary
+			oldCanary.Approve, newCanary.Approve = false, false
+			oldSpec.Canary, newSpec.Canary = &oldCanary, &newCanary
+		}
+		if changed := changedSpecFields(oldSpec, newSpec, mutableUpgradeFields); len(changed) != 0 {
+			return admissionResponse(fmt.Errorf("spec fields %s are immutable once the job is created, only %s can be updated",
+				strings.Join(changed, ", "), strings.Join(mutableUpgradeFields, ", ")))
 		}
 
 		return admissionResponse(validateNodeUpgradeJob(&newUpgrade))
@@ -81,25 +97,439 @@ func admitNodeUpgradeJob(review admissionv1.AdmissionReview) *admissionv1.Admiss
 	}
 }
 
+// mutableUpgradeFields are the spec fields of a NodeUpgradeJob which can be updated while it runs,
+// the executor of the job picks them up at once
+var mutableUpgradeFields = []string{"paused", "apply", "rerun", "concurrency", "rollingStrategy", "failureTolerate"}
+
+// changedSpecFields returns the json names of the fields which differ between the specs, the mutable
+// fields are ignored. The fields of the structs embedded inline, e.g. CommonJobSpec, are compared
+// as the fields of the spec.
+func changedSpecFields(oldSpec, newSpec interface{}, mutable []string) []string {
+	ignored := make(map[string]bool, len(mutable))
+	for _, name := range mutable {
+		ignored[name] = true
+	}
+	oldValue, newValue := reflect.ValueOf(oldSpec), reflect.ValueOf(newSpec)
+	var changed []string
+	for i := 0; i < oldValue.NumField(); i++ {
+		field := oldValue.Type().Field(i)
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.Anonymous && name == "" && options == "inline" && field.Type.Kind() == reflect.Struct {
+			changed = append(changed, changedSpecFields(oldValue.Field(i).Interface(), newValue.Field(i).Interface(), mutable)...)
+			continue
+		}
+		if name == "" || ignored[name] {
+			continue
+		}
+		if !reflect.DeepEqual(oldValue.Field(i).Interface(), newValue.Field(i).Interface()) {
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
+
 func validateNodeUpgradeJob(upgrade *v1alpha1.NodeUpgradeJob) error {
-	// version must be valid
-	if !strings.HasPrefix(upgrade.Spec.Version, "v") {
+	if len(upgrade.Spec.VersionMappings) != 0 {
+		if err := validateVersionMappings(&upgrade.Spec); err != nil {
+			return err
+		}
+	} else {
+		// version must be valid, a version referencing an input is validated once it is resolved
+		if !inputReference.MatchString(upgrade.Spec.Version) {
+			if err := validateVersion(upgrade.Spec.Version); err != nil {
+				return err
+			}
+		}
+
+		// we must specify NodeNames, LabelSelector or NodeGroups, and we can only specify one of
+		// NodeNames and LabelSelector
+		if len(upgrade.Spec.NodeNames) == 0 && upgrade.Spec.LabelSelector == nil && len(upgrade.Spec.NodeGroups) == 0 {
+			return fmt.Errorf("none of NodeNames, LabelSelctor and NodeGroups are specified")
+		}
+		if len(upgrade.Spec.NodeNames) != 0 && upgrade.Spec.LabelSelector != nil {
+			return fmt.Errorf("both NodeNames and LabelSelctor are specified")
+		}
+		if err := validateNodeGroups(upgrade.Spec.NodeGroups); err != nil {
+			return err
+		}
+	}
+
+	for key := range upgrade.Spec.Metadata {
+		if errs := validation.IsCIdentifier(key); len(errs) != 0 {
+			return fmt.Errorf("metadata key %q must be a valid environment variable name: %s", key, strings.Join(errs, ", "))
+		}
+	}
+
+	if upgrade.Spec.Preset != "" {
+		if _, ok := upgradePresets[upgrade.Spec.Preset]; !ok {
+			return fmt.Errorf("unknown preset %q", upgrade.Spec.Preset)
+		}
+	}
+
+	if err := validatePromotion(upgrade.Spec.Promotion); err != nil {
+		return err
+	}
+
+	if err := validateApply(upgrade.Spec.Apply); err != nil {
+		return err
+	}
+
+	if upgrade.Spec.Concurrency != nil {
+		if err := validateIntOrPercent("concurrency", upgrade.Spec.Concurrency); err != nil {
+			return err
+		}
+	}
+
+	if err := validateRollingStrategy(upgrade.Spec.RollingStrategy); err != nil {
+		return err
+	}
+
+	if err := validateFailureTolerate(upgrade.Spec.FailureTolerate); err != nil {
+		return err
+	}
+
+	if err := validateCanary(upgrade.Spec.Canary); err != nil {
+		return err
+	}
+
+	if err := validatePauseOn(upgrade.Spec.PauseOn); err != nil {
+		return err
+	}
+
+	if err := validateDrain(upgrade.Spec.DrainNodeBeforeUpgrade); err != nil {
+		return err
+	}
+
+	if err := validateOrdering(upgrade.Spec.Ordering, upgrade.Spec.OrderingSeed); err != nil {
+		return err
+	}
+
+	if err := validatePostCheck(upgrade.Spec.PostCheck); err != nil {
+		return err
+	}
+
+	if err := validateCohorts(upgrade.Spec.Cohorts); err != nil {
+		return err
+	}
+
+	if err := validateInputs(upgrade.Spec, upgrade.Spec.Inputs); err != nil {
+		return err
+	}
+
+	return validateVerification(upgrade.Spec.Verification)
+}
+
+func validateVersion(version string) error {
+	if !strings.HasPrefix(version, "v") {
 		return fmt.Errorf("version must begin with prefix 'v'")
 	}
 
-	_, err := semver.Parse(strings.TrimPrefix(upgrade.Spec.Version, "v"))
+	_, err := semver.Parse(strings.TrimPrefix(version, "v"))
 	if err != nil {
 		return fmt.Errorf("version is not a semver compatible version: %v", err)
 	}
+	return nil
+}
+
+// validateNodeGroups validates the names of the node groups selected by the job
+func validateNodeGroups(nodeGroups []string) error {
+	names := make(map[string]bool, len(nodeGroups))
+	for _, name := range nodeGroups {
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) != 0 {
+			return fmt.Errorf("node group name %q is invalid: %s", name, strings.Join(errs, ", "))
+		}
+		if names[name] {
+			return fmt.Errorf("node group %s is duplicated", name)
+		}
+		names[name] = true
+	}
+	return nil
+}
+
+// validateVersionMappings validates the version mappings, they replace the version and the node
+// selection of the job
+func validateVersionMappings(spec *v1alpha1.NodeUpgradeJobSpec) error {
+	if spec.Version != "" || len(spec.NodeNames) != 0 || spec.LabelSelector != nil || len(spec.NodeGroups) != 0 {
+		return fmt.Errorf("version, nodeNames, labelSelector and nodeGroups must not be specified with versionMappings")
+	}
+	if spec.Promotion != nil {
+		return fmt.Errorf("promotion is not supported with versionMappings")
+	}
+
+	names := make(map[string]bool, len(spec.VersionMappings))
+	for _, mapping := range spec.VersionMappings {
+		if errs := validation.IsDNS1123Label(mapping.Name); len(errs) != 0 {
+			return fmt.Errorf("version mapping name %q is invalid: %s", mapping.Name, strings.Join(errs, ", "))
+		}
+		if names[mapping.Name] {
+			return fmt.Errorf("version mapping name %s is duplicated", mapping.Name)
+		}
+		names[mapping.Name] = true
+
+		if mapping.LabelSelector == nil {
+			return fmt.Errorf("version mapping %s must specify labelSelector", mapping.Name)
+		}
+		if _, err := metav1.LabelSelectorAsSelector(mapping.LabelSelector); err != nil {
+			return fmt.Errorf("version mapping %s has an invalid labelSelector: %v", mapping.Name, err)
+		}
+		if err := validateVersion(mapping.Version); err != nil {
+			return fmt.Errorf("version mapping %s: %v", mapping.Name, err)
+		}
+	}
+	return nil
+}
 
-	// we must specify NodeNames or LabelSelector, and we can only specify only one
-	if len(upgrade.Spec.NodeNames) == 0 && upgrade.Spec.LabelSelector == nil {
-		return fmt.Errorf("both NodeNames and LabelSelctor are NOT specified")
+func validatePromotion(promotion *v1alpha1.PromotionSpec) error {
+	if promotion == nil {
+		return nil
 	}
-	if len(upgrade.Spec.NodeNames) != 0 && upgrade.Spec.LabelSelector != nil {
-		return fmt.Errorf("both NodeNames and LabelSelctor are specified")
+	if promotion.NodeGroup == "" {
+		return fmt.Errorf("promotion node group must be specified")
 	}
+	if promotion.SuccessThreshold != "" {
+		threshold, err := strconv.ParseFloat(promotion.SuccessThreshold, 64)
+		if err != nil || threshold < 0 || threshold > 1 {
+			return fmt.Errorf("promotion successThreshold %q must be a ratio between 0 and 1", promotion.SuccessThreshold)
+		}
+	}
+	if promotion.SoakSeconds < 0 {
+		return fmt.Errorf("promotion soakSeconds must not be negative")
+	}
+	return nil
+}
 
+func validateApply(apply *v1alpha1.ApplySpec) error {
+	if apply == nil {
+		return nil
+	}
+	for _, window := range apply.MaintenanceWindows {
+		if _, err := time.Parse(v1alpha1.MaintenanceWindowStartLayout, window.Start); err != nil {
+			return fmt.Errorf("maintenance window start %q must be a time of the day in the format HH:MM", window.Start)
+		}
+		if window.DurationMinutes < 1 || window.DurationMinutes > 24*60 {
+			return fmt.Errorf("maintenance window durationMinutes must be between 1 and 1440")
+		}
+	}
+	return nil
+}
+
+func validateRollingStrategy(strategy *v1alpha1.RollingStrategy) error {
+	if strategy == nil {
+		return nil
+	}
+	if strategy.MaxUnavailable == nil {
+		return fmt.Errorf("rolling strategy maxUnavailable must be specified")
+	}
+	return validateIntOrPercent("rolling strategy maxUnavailable", strategy.MaxUnavailable)
+}
+
+// validateIntOrPercent validates a number of nodes, at least 1, or a percentage of the nodes between 1% and 100%
+func validateIntOrPercent(name string, value *intstr.IntOrString) error {
+	if value.Type == intstr.Int {
+		if value.IntVal < 1 {
+			return fmt.Errorf("%s must be at least 1", name)
+		}
+		return nil
+	}
+	percent, err := strconv.Atoi(strings.TrimSuffix(value.StrVal, "%"))
+	if err != nil || !strings.HasSuffix(value.StrVal, "%") || percent < 1 || percent > 100 {
+		return fmt.Errorf("%s %q must be a percentage between 1%% and 100%%", name, value.StrVal)
+	}
+	return nil
+}
+
+// validatePauseOn validates the failure rate is a percentage between 1% and 100% and the external alerts are named
+func validatePauseOn(pauseOn *v1alpha1.PauseOnSpec) error {
+	if pauseOn == nil {
+		return nil
+	}
+	if pauseOn.FailedNodes != nil && *pauseOn.FailedNodes < 1 {
+		return fmt.Errorf("pauseOn failedNodes must be at least 1")
+	}
+	if rule := pauseOn.FailureRate; rule != nil {
+		percent, err := strconv.Atoi(strings.TrimSuffix(rule.Rate, "%"))
+		if err != nil || !strings.HasSuffix(rule.Rate, "%") || percent < 1 || percent > 100 {
+			return fmt.Errorf("pauseOn failureRate rate %q must be a percentage between 1%% and 100%%", rule.Rate)
+		}
+	}
+	for _, alert := range pauseOn.ExternalAlerts {
+		if alert == "" {
+			return fmt.Errorf("pauseOn externalAlerts must not be empty")
+		}
+	}
+	return nil
+}
+
+// validateFailureTolerate validates a number of nodes, a percentage of the nodes between 0% and 100%,
+// or a deprecated ratio between 0 and 1, the same as taskmanager reads it
+func validateFailureTolerate(tolerate *intstr.IntOrString) error {
+	if tolerate == nil {
+		return nil
+	}
+	_, err := taskutil.MaxFailedNodes(tolerate, 0)
+	return err
+}
+
+func validateCanary(canary *v1alpha1.CanarySpec) error {
+	if canary == nil {
+		return nil
+	}
+	if canary.Nodes < 0 {
+		return fmt.Errorf("canary nodes must not be negative")
+	}
+	if (canary.Nodes == 0) == (canary.LabelSelector == nil) {
+		return fmt.Errorf("canary must specify either nodes or labelSelector")
+	}
+	if canary.LabelSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(canary.LabelSelector); err != nil {
+			return fmt.Errorf("invalid canary label selector: %v", err)
+		}
+	}
+	return nil
+}
+
+// validateDrain validates how the nodes are drained before they are upgraded
+func validateDrain(drain *v1alpha1.DrainSpec) error {
+	if drain == nil {
+		return nil
+	}
+	if drain.GracePeriodSeconds != nil && *drain.GracePeriodSeconds < 0 {
+		return fmt.Errorf("drain gracePeriodSeconds must not be negative")
+	}
+	if drain.TimeoutSeconds != nil && *drain.TimeoutSeconds < 1 {
+		return fmt.Errorf("drain timeoutSeconds must be at least 1")
+	}
+	if drain.PodSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(drain.PodSelector); err != nil {
+			return fmt.Errorf("invalid drain pod selector: %v", err)
+		}
+	}
+	return nil
+}
+
+// validateOrdering validates the order the nodes are upgraded in, a seed only applies to the random order
+func validateOrdering(ordering v1alpha1.NodeOrdering, seed *int64) error {
+	switch ordering {
+	case "", v1alpha1.NodeOrderingDefault, v1alpha1.NodeOrderingReadiness:
+		if seed != nil {
+			return fmt.Errorf("orderingSeed is only supported with the Random ordering")
+		}
+	case v1alpha1.NodeOrderingRandom:
+	default:
+		return fmt.Errorf("unknown ordering %q", ordering)
+	}
+	return nil
+}
+
+func validateVerification(verification *v1alpha1.VerificationSpec) error {
+	if verification == nil {
+		return nil
+	}
+
+	names := make(map[string]bool, len(verification.Probes))
+	for _, probe := range verification.Probes {
+		if probe.Name == "" {
+			return fmt.Errorf("verification probe name must be specified")
+		}
+		if names[probe.Name] {
+			return fmt.Errorf("verification probe name %s is duplicated", probe.Name)
+		}
+		names[probe.Name] = true
+
+		actions := 0
+		if probe.Exec != nil {
+			actions++
+		}
+		if probe.HTTPGet != nil {
+			actions++
+		}
+		if probe.TCPSocket != nil {
+			actions++
+		}
+		if actions != 1 {
+			return fmt.Errorf("verification probe %s must specify exactly one of exec, httpGet and tcpSocket", probe.Name)
+		}
+	}
+	return nil
+}
+
+// validatePostCheck validates the selectors of the critical pods checked on the upgraded nodes
+func validatePostCheck(postCheck *v1alpha1.PostCheckSpec) error {
+	if postCheck == nil {
+		return nil
+	}
+	for _, critical := range postCheck.CriticalPods {
+		if critical.Namespace == "" || critical.LabelSelector == nil {
+			return fmt.Errorf("critical pods must specify their namespace and labelSelector")
+		}
+		if _, err := metav1.LabelSelectorAsSelector(critical.LabelSelector); err != nil {
+			return fmt.Errorf("invalid labelSelector of the critical pods in namespace %s: %v", critical.Namespace, err)
+		}
+	}
+	return nil
+}
+
+// validateCohorts validates the labels the nodes are given once the job is finished, a label value
+// referencing an input is validated once it is resolved
+func validateCohorts(cohorts *v1alpha1.NodeCohortSpec) error {
+	if cohorts == nil {
+		return nil
+	}
+	for _, labels := range []map[string]string{cohorts.Labels, cohorts.SucceededLabels, cohorts.FailedLabels} {
+		for key, value := range labels {
+			if errs := validation.IsQualifiedName(key); len(errs) != 0 {
+				return fmt.Errorf("invalid cohort label key %q: %s", key, strings.Join(errs, ", "))
+			}
+			if inputReference.MatchString(value) {
+				continue
+			}
+			if errs := validation.IsValidLabelValue(value); len(errs) != 0 {
+				return fmt.Errorf("invalid value %q of cohort label %s: %s", value, key, strings.Join(errs, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// inputReference matches a reference to an input in the spec of a job, e.g. $(inputs.nodes)
+var inputReference = regexp.MustCompile(`\$\(inputs\.([A-Za-z0-9_-]+)\)`)
+
+// inputName matches the name of an input
+var inputName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// jobKinds are the kinds of the jobs an input can reference
+var jobKinds = map[string]bool{"NodeUpgradeJob": true, "ImagePrePullJob": true, "SupportBundleJob": true, "ConfigUpdateJob": true, "NodeRestartJob": true,
+	"DiagnoseJob": true, "BackupJob": true, "RestoreJob": true,
+	"OSUpgradeJob": true}
+
+// validateInputs validates the inputs of the job and checks the spec references only the inputs it defines
+func validateInputs(spec interface{}, inputs []v1alpha1.JobInput) error {
+	names := make(map[string]bool, len(inputs))
+	for _, input := range inputs {
+		if !inputName.MatchString(input.Name) {
+			return fmt.Errorf("input name %q must consist of alphanumeric characters, '-' or '_'", input.Name)
+		}
+		if names[input.Name] {
+			return fmt.Errorf("input %s is duplicated", input.Name)
+		}
+		names[input.Name] = true
+		if !jobKinds[input.Kind] {
+			return fmt.Errorf("input %s references unknown job kind %q", input.Name, input.Kind)
+		}
+		if input.JobName == "" || input.Output == "" {
+			return fmt.Errorf("input %s must specify jobName and output", input.Name)
+		}
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal spec: %v", err)
+	}
+	for _, match := range inputReference.FindAllSubmatch(data, -1) {
+		if !names[string(match[1])] {
+			return fmt.Errorf("input %s is referenced but not defined", match[1])
+		}
+	}
 	return nil
 }
 
@@ -145,24 +575,51 @@ func mutatingNodeUpgradeJob(review admissionv1.AdmissionReview) *admissionv1.Adm
 	return &reviewResponse
 }
 
+// upgradePreset holds the values a preset expands into
+type upgradePreset struct {
+	concurrency     int32
+	failureTolerate string
+	timeoutSeconds  uint32
+}
+
+var upgradePresets = map[v1alpha1.UpgradePreset]upgradePreset{
+	v1alpha1.UpgradePresetConservative: {concurrency: 1, failureTolerate: "0%", timeoutSeconds: 600},
+	v1alpha1.UpgradePresetBalanced:     {concurrency: 5, failureTolerate: "10%", timeoutSeconds: 300},
+	v1alpha1.UpgradePresetFast:         {concurrency: 20, failureTolerate: "30%", timeoutSeconds: 300},
+}
+
 func generateNodeUpgradeJobPatch(spec v1alpha1.NodeUpgradeJobSpec) []patchValue {
 	patch := make([]patchValue, 0)
 
+	defaults := upgradePreset{concurrency: 1, timeoutSeconds: 300}
+	if preset, ok := upgradePresets[spec.Preset]; ok {
+		defaults = preset
+	}
+
 	// mutate .spec.concurrency to default value 1 if not specified
-	if spec.Concurrency == 0 {
+	if spec.Concurrency == nil {
 		patch = append(patch, patchValue{
 			Op:    "replace",
 			Path:  "/spec/concurrency",
-			Value: 1,
+			Value: defaults.concurrency,
 		})
 	}
 	// mutate .spec.timeoutSeconds to default value 300 if not specified
 	if spec.TimeoutSeconds == nil {
-		var defaultTimeoutSeconds uint32 = 300
+		timeoutSeconds := defaults.timeoutSeconds
 		patch = append(patch, patchValue{
 			Op:    "replace",
 			Path:  "/spec/timeoutSeconds",
-			Value: &defaultTimeoutSeconds,
+			Value: &timeoutSeconds,
+		})
+	}
+	// mutate .spec.failureTolerate to the value of the preset if not specified,
+	// without a preset it is left to the default of the task manager
+	if spec.FailureTolerate == nil && defaults.failureTolerate != "" {
+		patch = append(patch, patchValue{
+			Op:    "replace",
+			Path:  "/spec/failureTolerate",
+			Value: defaults.failureTolerate,
 		})
 	}
 
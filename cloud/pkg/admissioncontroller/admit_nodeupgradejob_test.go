@@ -0,0 +1,506 @@
+package admissioncontroller
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+func Test_generateNodeUpgradeJobPatch(t *testing.T) {
+	timeoutSeconds := uint32(300)
+	concurrency := intstr.FromInt(10)
+	failureTolerate := intstr.FromString("5%")
+	conservativeTimeout := uint32(600)
+
+	tests := []struct {
+		name string
+		spec v1alpha1.NodeUpgradeJobSpec
+		want []patchValue
+	}{
+		{
+			name: "no preset",
+			spec: v1alpha1.NodeUpgradeJobSpec{},
+			want: []patchValue{
+				{Op: "replace", Path: "/spec/concurrency", Value: int32(1)},
+				{Op: "replace", Path: "/spec/timeoutSeconds", Value: &timeoutSeconds},
+			},
+		},
+		{
+			name: "conservative preset",
+			spec: v1alpha1.NodeUpgradeJobSpec{Preset: v1alpha1.UpgradePresetConservative},
+			want: []patchValue{
+				{Op: "replace", Path: "/spec/concurrency", Value: int32(1)},
+				{Op: "replace", Path: "/spec/timeoutSeconds", Value: &conservativeTimeout},
+				{Op: "replace", Path: "/spec/failureTolerate", Value: "0%"},
+			},
+		},
+		{
+			name: "fast preset with overrides",
+			spec: v1alpha1.NodeUpgradeJobSpec{
+				Preset:          v1alpha1.UpgradePresetFast,
+				Concurrency:     &concurrency,
+				TimeoutSeconds:  &timeoutSeconds,
+				FailureTolerate: &failureTolerate,
+			},
+			want: []patchValue{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := generateNodeUpgradeJobPatch(test.spec); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("generateNodeUpgradeJobPatch() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func Test_validateNodeUpgradeJobPreset(t *testing.T) {
+	upgrade := &v1alpha1.NodeUpgradeJob{
+		Spec: v1alpha1.NodeUpgradeJobSpec{
+			Version:   "v1.17.0",
+			NodeNames: []string{"edge-1"},
+			Preset:    v1alpha1.UpgradePresetBalanced,
+		},
+	}
+	if err := validateNodeUpgradeJob(upgrade); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	upgrade.Spec.Preset = "reckless"
+	if err := validateNodeUpgradeJob(upgrade); err == nil {
+		t.Errorf("expected error for unknown preset")
+	}
+}
+
+func Test_validateVersionMappings(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/arch": "arm64"}}
+	tests := []struct {
+		name    string
+		spec    v1alpha1.NodeUpgradeJobSpec
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			spec: v1alpha1.NodeUpgradeJobSpec{VersionMappings: []v1alpha1.VersionMapping{
+				{Name: "arm", LabelSelector: selector, Version: "v1.16.3"},
+				{Name: "x86", LabelSelector: &metav1.LabelSelector{}, Version: "v1.17.0"},
+			}},
+		},
+		{
+			name: "with version",
+			spec: v1alpha1.NodeUpgradeJobSpec{Version: "v1.17.0", VersionMappings: []v1alpha1.VersionMapping{
+				{Name: "arm", LabelSelector: selector, Version: "v1.16.3"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "duplicated name",
+			spec: v1alpha1.NodeUpgradeJobSpec{VersionMappings: []v1alpha1.VersionMapping{
+				{Name: "arm", LabelSelector: selector, Version: "v1.16.3"},
+				{Name: "arm", LabelSelector: selector, Version: "v1.17.0"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "invalid name",
+			spec: v1alpha1.NodeUpgradeJobSpec{VersionMappings: []v1alpha1.VersionMapping{
+				{Name: "ARM", LabelSelector: selector, Version: "v1.16.3"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "no selector",
+			spec: v1alpha1.NodeUpgradeJobSpec{VersionMappings: []v1alpha1.VersionMapping{
+				{Name: "arm", Version: "v1.16.3"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "invalid version",
+			spec: v1alpha1.NodeUpgradeJobSpec{VersionMappings: []v1alpha1.VersionMapping{
+				{Name: "arm", LabelSelector: selector, Version: "1.16"},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateNodeUpgradeJob(&v1alpha1.NodeUpgradeJob{Spec: test.spec})
+			if (err != nil) != test.wantErr {
+				t.Errorf("validateNodeUpgradeJob() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateNodeGroups(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    v1alpha1.NodeUpgradeJobSpec
+		wantErr bool
+	}{
+		{
+			name: "node groups only",
+			spec: v1alpha1.NodeUpgradeJobSpec{Version: "v1.17.0", NodeGroups: []string{"hangzhou", "beijing"}},
+		},
+		{
+			name: "node groups with node names",
+			spec: v1alpha1.NodeUpgradeJobSpec{Version: "v1.17.0", NodeNames: []string{"edge-node"}, NodeGroups: []string{"hangzhou"}},
+		},
+		{
+			name:    "no node selected",
+			spec:    v1alpha1.NodeUpgradeJobSpec{Version: "v1.17.0"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid node group name",
+			spec:    v1alpha1.NodeUpgradeJobSpec{Version: "v1.17.0", NodeGroups: []string{"HangZhou"}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicated node group",
+			spec:    v1alpha1.NodeUpgradeJobSpec{Version: "v1.17.0", NodeGroups: []string{"hangzhou", "hangzhou"}},
+			wantErr: true,
+		},
+		{
+			name: "node groups with version mappings",
+			spec: v1alpha1.NodeUpgradeJobSpec{NodeGroups: []string{"hangzhou"}, VersionMappings: []v1alpha1.VersionMapping{
+				{Name: "arm", LabelSelector: &metav1.LabelSelector{}, Version: "v1.16.3"},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateNodeUpgradeJob(&v1alpha1.NodeUpgradeJob{Spec: test.spec})
+			if (err != nil) != test.wantErr {
+				t.Errorf("validateNodeUpgradeJob() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validatePromotion(t *testing.T) {
+	tests := []struct {
+		name      string
+		promotion *v1alpha1.PromotionSpec
+		wantErr   bool
+	}{
+		{name: "no promotion"},
+		{name: "valid", promotion: &v1alpha1.PromotionSpec{NodeGroup: "production-sites", SuccessThreshold: "0.98", SoakSeconds: 3600}},
+		{name: "no node group", promotion: &v1alpha1.PromotionSpec{SuccessThreshold: "0.98"}, wantErr: true},
+		{name: "invalid threshold", promotion: &v1alpha1.PromotionSpec{NodeGroup: "production-sites", SuccessThreshold: "98%"}, wantErr: true},
+		{name: "threshold above 1", promotion: &v1alpha1.PromotionSpec{NodeGroup: "production-sites", SuccessThreshold: "1.2"}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := validatePromotion(test.promotion); (err != nil) != test.wantErr {
+				t.Errorf("validatePromotion() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateRollingStrategy(t *testing.T) {
+	percent := func(value string) *intstr.IntOrString {
+		maxUnavailable := intstr.FromString(value)
+		return &maxUnavailable
+	}
+	count := func(value int) *intstr.IntOrString {
+		maxUnavailable := intstr.FromInt(value)
+		return &maxUnavailable
+	}
+	tests := []struct {
+		name     string
+		strategy *v1alpha1.RollingStrategy
+		wantErr  bool
+	}{
+		{name: "no strategy"},
+		{name: "percentage", strategy: &v1alpha1.RollingStrategy{MaxUnavailable: percent("10%")}},
+		{name: "count", strategy: &v1alpha1.RollingStrategy{MaxUnavailable: count(3)}},
+		{name: "no maxUnavailable", strategy: &v1alpha1.RollingStrategy{}, wantErr: true},
+		{name: "zero", strategy: &v1alpha1.RollingStrategy{MaxUnavailable: count(0)}, wantErr: true},
+		{name: "percentage above 100", strategy: &v1alpha1.RollingStrategy{MaxUnavailable: percent("120%")}, wantErr: true},
+		{name: "no percent sign", strategy: &v1alpha1.RollingStrategy{MaxUnavailable: percent("10")}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := validateRollingStrategy(test.strategy); (err != nil) != test.wantErr {
+				t.Errorf("validateRollingStrategy() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateCanary(t *testing.T) {
+	tests := []struct {
+		name    string
+		canary  *v1alpha1.CanarySpec
+		wantErr bool
+	}{
+		{name: "no canary"},
+		{name: "nodes", canary: &v1alpha1.CanarySpec{Nodes: 2}},
+		{name: "label selector", canary: &v1alpha1.CanarySpec{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}}}},
+		{name: "neither nodes nor label selector", canary: &v1alpha1.CanarySpec{}, wantErr: true},
+		{
+			name:    "both nodes and label selector",
+			canary:  &v1alpha1.CanarySpec{Nodes: 2, LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}}},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := validateCanary(test.canary); (err != nil) != test.wantErr {
+				t.Errorf("validateCanary() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validatePostCheck(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "agent"}}
+	tests := []struct {
+		name      string
+		postCheck *v1alpha1.PostCheckSpec
+		wantErr   bool
+	}{
+		{name: "no post check"},
+		{name: "no critical pods", postCheck: &v1alpha1.PostCheckSpec{}},
+		{
+			name:      "critical pods",
+			postCheck: &v1alpha1.PostCheckSpec{CriticalPods: []v1alpha1.CriticalPodSelector{{Namespace: "edge", LabelSelector: selector}}},
+		},
+		{
+			name:      "no namespace",
+			postCheck: &v1alpha1.PostCheckSpec{CriticalPods: []v1alpha1.CriticalPodSelector{{LabelSelector: selector}}},
+			wantErr:   true,
+		},
+		{
+			name: "invalid selector",
+			postCheck: &v1alpha1.PostCheckSpec{CriticalPods: []v1alpha1.CriticalPodSelector{{
+				Namespace: "edge",
+				LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "app", Operator: "Unknown"},
+				}},
+			}}},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := validatePostCheck(test.postCheck); (err != nil) != test.wantErr {
+				t.Errorf("validatePostCheck() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateCohorts(t *testing.T) {
+	tests := []struct {
+		name    string
+		cohorts *v1alpha1.NodeCohortSpec
+		wantErr bool
+	}{
+		{name: "no cohorts"},
+		{
+			name: "cohorts",
+			cohorts: &v1alpha1.NodeCohortSpec{
+				Labels:          map[string]string{"upgrade.kubeedge.io/job": "upgrade-1"},
+				SucceededLabels: map[string]string{"upgrade.kubeedge.io/version": "v1.19.0"},
+				FailedLabels:    map[string]string{"upgrade.kubeedge.io/failed": "true"},
+			},
+		},
+		{
+			name:    "input reference",
+			cohorts: &v1alpha1.NodeCohortSpec{SucceededLabels: map[string]string{"version": "$(inputs.version)"}},
+		},
+		{
+			name:    "invalid key",
+			cohorts: &v1alpha1.NodeCohortSpec{Labels: map[string]string{"upgrade/job/1": "upgrade-1"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid value",
+			cohorts: &v1alpha1.NodeCohortSpec{FailedLabels: map[string]string{"failed": "not valid"}},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := validateCohorts(test.cohorts); (err != nil) != test.wantErr {
+				t.Errorf("validateCohorts() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateInputs(t *testing.T) {
+	input := v1alpha1.JobInput{Name: "nodes", Kind: "NodeUpgradeJob", JobName: "dry-run", Output: "succeededNodes"}
+	tests := []struct {
+		name    string
+		mutate  func(spec *v1alpha1.NodeUpgradeJobSpec)
+		wantErr bool
+	}{
+		{name: "no inputs", mutate: func(spec *v1alpha1.NodeUpgradeJobSpec) {}},
+		{
+			name: "referenced input",
+			mutate: func(spec *v1alpha1.NodeUpgradeJobSpec) {
+				spec.Inputs = []v1alpha1.JobInput{input}
+				spec.NodeNames = []string{"$(inputs.nodes)"}
+			},
+		},
+		{
+			name: "undefined input",
+			mutate: func(spec *v1alpha1.NodeUpgradeJobSpec) {
+				spec.NodeNames = []string{"$(inputs.nodes)"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicated input",
+			mutate: func(spec *v1alpha1.NodeUpgradeJobSpec) {
+				spec.Inputs = []v1alpha1.JobInput{input, input}
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown kind",
+			mutate: func(spec *v1alpha1.NodeUpgradeJobSpec) {
+				unknown := input
+				unknown.Kind = "Deployment"
+				spec.Inputs = []v1alpha1.JobInput{unknown}
+			},
+			wantErr: true,
+		},
+		{
+			name: "version from input",
+			mutate: func(spec *v1alpha1.NodeUpgradeJobSpec) {
+				version := input
+				version.Name, version.Output = "version", "version"
+				spec.Inputs = []v1alpha1.JobInput{version}
+				spec.Version = "$(inputs.version)"
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			upgrade := &v1alpha1.NodeUpgradeJob{Spec: v1alpha1.NodeUpgradeJobSpec{Version: "v1.15.0", NodeNames: []string{"edge-1"}}}
+			test.mutate(&upgrade.Spec)
+			if err := validateNodeUpgradeJob(upgrade); (err != nil) != test.wantErr {
+				t.Errorf("validateNodeUpgradeJob() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func Test_admitNodeUpgradeJobUpdate(t *testing.T) {
+	upgrade := func(mutate func(spec *v1alpha1.NodeUpgradeJobSpec)) runtime.RawExtension {
+		job := v1alpha1.NodeUpgradeJob{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "NodeUpgradeJob"},
+			ObjectMeta: metav1.ObjectMeta{Name: "upgrade"},
+			Spec:       v1alpha1.NodeUpgradeJobSpec{Version: "v1.17.0", NodeNames: []string{"edge-1"}},
+		}
+		mutate(&job.Spec)
+		raw, _ := json.Marshal(job)
+		return runtime.RawExtension{Raw: raw}
+	}
+	old := upgrade(func(*v1alpha1.NodeUpgradeJobSpec) {})
+	tests := []struct {
+		name    string
+		object  runtime.RawExtension
+		allowed bool
+	}{
+		{name: "pause", object: upgrade(func(spec *v1alpha1.NodeUpgradeJobSpec) { spec.Paused = true }), allowed: true},
+		{name: "change version", object: upgrade(func(spec *v1alpha1.NodeUpgradeJobSpec) { spec.Version = "v1.17.1" })},
+		{name: "rerun", object: upgrade(func(spec *v1alpha1.NodeUpgradeJobSpec) { spec.Rerun = 1 }), allowed: true},
+		{name: "hold", object: upgrade(func(spec *v1alpha1.NodeUpgradeJobSpec) { spec.Apply = &v1alpha1.ApplySpec{Hold: true} }), allowed: true},
+		{
+			name: "tune concurrency and failure tolerance",
+			object: upgrade(func(spec *v1alpha1.NodeUpgradeJobSpec) {
+				concurrency, tolerate := intstr.FromInt(5), intstr.FromString("20%")
+				spec.Concurrency, spec.FailureTolerate = &concurrency, &tolerate
+			}),
+			allowed: true,
+		},
+		{
+			name: "tune rolling strategy",
+			object: upgrade(func(spec *v1alpha1.NodeUpgradeJobSpec) {
+				maxUnavailable := intstr.FromString("25%")
+				spec.RollingStrategy = &v1alpha1.RollingStrategy{MaxUnavailable: &maxUnavailable}
+			}),
+			allowed: true,
+		},
+		{name: "change image", object: upgrade(func(spec *v1alpha1.NodeUpgradeJobSpec) { spec.Image = "kubeedge/custom" })},
+		{name: "change check items", object: upgrade(func(spec *v1alpha1.NodeUpgradeJobSpec) { spec.CheckItems = []string{"cpu"} })},
+		{
+			name: "invalid maintenance window",
+			object: upgrade(func(spec *v1alpha1.NodeUpgradeJobSpec) {
+				spec.Apply = &v1alpha1.ApplySpec{MaintenanceWindows: []v1alpha1.MaintenanceWindow{{Start: "2am", DurationMinutes: 60}}}
+			}),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			review := admissionv1.AdmissionReview{Request: &admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Object:    test.object,
+				OldObject: old,
+			}}
+			if resp := admitNodeUpgradeJob(review); resp.Allowed != test.allowed {
+				t.Errorf("expected allowed %t, got %t: %v", test.allowed, resp.Allowed, resp.Result)
+			}
+		})
+	}
+	canary := upgrade(func(spec *v1alpha1.NodeUpgradeJobSpec) { spec.Canary = &v1alpha1.CanarySpec{Nodes: 1} })
+	for _, test := range []struct {
+		name    string
+		object  runtime.RawExtension
+		allowed bool
+	}{
+		{
+			name:    "approve",
+			object:  upgrade(func(spec *v1alpha1.NodeUpgradeJobSpec) { spec.Canary = &v1alpha1.CanarySpec{Nodes: 1, Approve: true} }),
+			allowed: true,
+		},
+		{name: "change canary nodes", object: upgrade(func(spec *v1alpha1.NodeUpgradeJobSpec) { spec.Canary = &v1alpha1.CanarySpec{Nodes: 2} })},
+	} {
+		review := admissionv1.AdmissionReview{Request: &admissionv1.AdmissionRequest{
+			Operation: admissionv1.Update,
+			Object:    test.object,
+			OldObject: canary,
+		}}
+		if resp := admitNodeUpgradeJob(review); resp.Allowed != test.allowed {
+			t.Errorf("%s: expected allowed %t, got %t: %v", test.name, test.allowed, resp.Allowed, resp.Result)
+		}
+	}
+}
+
+func Test_validatePauseOn(t *testing.T) {
+	zero := int32(0)
+	tests := []struct {
+		name    string
+		pauseOn *v1alpha1.PauseOnSpec
+		wantErr bool
+	}{
+		{name: "no pauseOn"},
+		{name: "failure rate", pauseOn: &v1alpha1.PauseOnSpec{FailureRate: &v1alpha1.FailureRateRule{Rate: "10%"}, ExternalAlerts: []string{"EdgeAppDown"}}},
+		{name: "zero failed nodes", pauseOn: &v1alpha1.PauseOnSpec{FailedNodes: &zero}, wantErr: true},
+		{name: "rate above 100", pauseOn: &v1alpha1.PauseOnSpec{FailureRate: &v1alpha1.FailureRateRule{Rate: "120%"}}, wantErr: true},
+		{name: "rate without percent sign", pauseOn: &v1alpha1.PauseOnSpec{FailureRate: &v1alpha1.FailureRateRule{Rate: "10"}}, wantErr: true},
+		{name: "empty alert", pauseOn: &v1alpha1.PauseOnSpec{ExternalAlerts: []string{""}}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := validatePauseOn(test.pauseOn); (err != nil) != test.wantErr {
+				t.Errorf("validatePauseOn() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
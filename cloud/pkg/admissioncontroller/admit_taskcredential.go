@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admissioncontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// taskCredentialSpec is the part of the spec of the jobs of the operations group which requests the
+// credentials of the tasks, the credential of an ImagePrePullJob is in its template
+type taskCredentialSpec struct {
+	Credential           *v1alpha1.TaskCredentialSpec `json:"credential,omitempty"`
+	ImagePrePullTemplate struct {
+		Credential *v1alpha1.TaskCredentialSpec `json:"credential,omitempty"`
+	} `json:"imagePrePullTemplate,omitempty"`
+}
+
+func serveTaskCredential(w http.ResponseWriter, r *http.Request) {
+	serve(w, r, admitTaskCredential)
+}
+
+// admitTaskCredential denies the jobs requesting the credential of a service account unless the
+// requester is allowed to create the tokens of the service account itself, so that cloudcore, which
+// mints the tokens, cannot be used to obtain the identities the requester has no access to
+func admitTaskCredential(review admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	credential, err := decodeTaskCredential(review.Request.Object.Raw)
+	if err != nil {
+		return admissionResponse(fmt.Errorf("validation failed with error: %v", err))
+	}
+	if credential == nil {
+		return admissionResponse(nil)
+	}
+	if review.Request.Operation == admissionv1.Update {
+		oldCredential, err := decodeTaskCredential(review.Request.OldObject.Raw)
+		if err != nil {
+			return admissionResponse(fmt.Errorf("validation failed with error: %v", err))
+		}
+		if reflect.DeepEqual(credential, oldCredential) {
+			return admissionResponse(nil)
+		}
+	}
+	return admissionResponse(reviewTaskCredential(review.Request.UserInfo, credential))
+}
+
+func decodeTaskCredential(raw []byte) (*v1alpha1.TaskCredentialSpec, error) {
+	job := struct {
+		Spec taskCredentialSpec `json:"spec"`
+	}{}
+	if err := json.Unmarshal(raw, &job); err != nil {
+		return nil, err
+	}
+	if job.Spec.Credential != nil {
+		return job.Spec.Credential, nil
+	}
+	return job.Spec.ImagePrePullTemplate.Credential, nil
+}
+
+// reviewTaskCredential checks whether the user is allowed to create the tokens of the service account
+// of the credential
+func reviewTaskCredential(user authenticationv1.UserInfo, credential *v1alpha1.TaskCredentialSpec) error {
+	namespace := credential.Namespace
+	if namespace == "" {
+		namespace = constants.DefaultTaskCredentialNamespace
+	}
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	sar := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			Groups: user.Groups,
+			UID:    user.UID,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   namespace,
+				Verb:        "create",
+				Resource:    "serviceaccounts",
+				Subresource: "token",
+				Name:        credential.ServiceAccountName,
+			},
+		},
+	}
+	result, err := controller.Client.AuthorizationV1().SubjectAccessReviews().Create(context.TODO(), sar, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to review the credential of service account %s/%s: %v", namespace, credential.ServiceAccountName, err)
+	}
+	if !result.Status.Allowed {
+		return fmt.Errorf("user %s is not allowed to create the tokens of service account %s/%s: %s",
+			user.Username, namespace, credential.ServiceAccountName, result.Status.Reason)
+	}
+	return nil
+}
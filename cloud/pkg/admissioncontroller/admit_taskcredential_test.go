@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admissioncontroller
+
+import (
+	"encoding/json"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+func Test_admitTaskCredential(t *testing.T) {
+	// alice may only create the tokens of ops/cordon
+	var reviewed []authorizationv1.ResourceAttributes
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		sar := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attributes := *sar.Spec.ResourceAttributes
+		reviewed = append(reviewed, attributes)
+		sar.Status.Allowed = sar.Spec.User == "alice" && attributes.Namespace == "ops" && attributes.Name == "cordon"
+		return true, sar, nil
+	})
+	origin := controller.Client
+	controller.Client = client
+	defer func() { controller.Client = origin }()
+
+	restart := func(credential *v1alpha1.TaskCredentialSpec) runtime.RawExtension {
+		job := v1alpha1.NodeRestartJob{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "NodeRestartJob"},
+			ObjectMeta: metav1.ObjectMeta{Name: "restart"},
+			Spec:       v1alpha1.NodeRestartJobSpec{CommonJobSpec: v1alpha1.CommonJobSpec{NodeNames: []string{"edge-1"}, Credential: credential}},
+		}
+		raw, _ := json.Marshal(job)
+		return runtime.RawExtension{Raw: raw}
+	}
+	prePull := func(credential *v1alpha1.TaskCredentialSpec) runtime.RawExtension {
+		job := v1alpha1.ImagePrePullJob{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "ImagePrePullJob"},
+			ObjectMeta: metav1.ObjectMeta{Name: "prepull"},
+			Spec: v1alpha1.ImagePrePullJobSpec{ImagePrePullTemplate: v1alpha1.ImagePrePullTemplate{
+				Images:     []string{"nginx:1.25"},
+				NodeNames:  []string{"edge-1"},
+				Credential: credential,
+			}},
+		}
+		raw, _ := json.Marshal(job)
+		return runtime.RawExtension{Raw: raw}
+	}
+	cordon := &v1alpha1.TaskCredentialSpec{ServiceAccountName: "cordon", Namespace: "ops"}
+	cloudcore := &v1alpha1.TaskCredentialSpec{ServiceAccountName: "cloudcore"}
+
+	tests := []struct {
+		name      string
+		operation admissionv1.Operation
+		user      string
+		object    runtime.RawExtension
+		oldObject runtime.RawExtension
+		allowed   bool
+		reviewed  bool
+	}{
+		{name: "no credential", operation: admissionv1.Create, user: "bob", object: restart(nil), allowed: true},
+		{name: "allowed requester", operation: admissionv1.Create, user: "alice", object: restart(cordon), allowed: true, reviewed: true},
+		{name: "denied requester", operation: admissionv1.Create, user: "bob", object: restart(cordon), reviewed: true},
+		{name: "cloudcore identity", operation: admissionv1.Create, user: "alice", object: restart(cloudcore), reviewed: true},
+		{name: "prepull template", operation: admissionv1.Create, user: "bob", object: prePull(cordon), reviewed: true},
+		{name: "unchanged credential", operation: admissionv1.Update, user: "bob", object: restart(cordon), oldObject: restart(cordon), allowed: true},
+		{name: "changed credential", operation: admissionv1.Update, user: "alice", object: restart(cloudcore), oldObject: restart(cordon), reviewed: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reviewed = nil
+			review := admissionv1.AdmissionReview{Request: &admissionv1.AdmissionRequest{
+				Operation: test.operation,
+				UserInfo:  authenticationv1.UserInfo{Username: test.user},
+				Object:    test.object,
+				OldObject: test.oldObject,
+			}}
+			if resp := admitTaskCredential(review); resp.Allowed != test.allowed {
+				t.Errorf("expected allowed %t, got %t: %v", test.allowed, resp.Allowed, resp.Result)
+			}
+			if test.reviewed != (len(reviewed) == 1) {
+				t.Fatalf("expected reviewed %t, got reviews %v", test.reviewed, reviewed)
+			}
+			if test.reviewed && (reviewed[0].Verb != "create" || reviewed[0].Resource != "serviceaccounts" || reviewed[0].Subresource != "token") {
+				t.Errorf("expected a review of creating the token of the service account, got %+v", reviewed[0])
+			}
+		})
+	}
+}
@@ -77,7 +77,9 @@ func getAuthorizerAttributes(router beehivemodel.MessageRoute, hubInfo cloudhubm
 func isKubeedgeResourceMessage(router beehivemodel.MessageRoute) bool {
 	switch router.Operation {
 	case beehivemodel.ResponseOperation, beehivemodel.ResponseErrorOperation, beehivemodel.UploadOperation,
-		taskutil.TaskPrePull, taskutil.TaskUpgrade, cloudhubmodel.OpKeepalive:
+		taskutil.TaskPrePull, taskutil.TaskUpgrade, taskutil.TaskSupportBundle, taskutil.TaskConfigUpdate, taskutil.TaskRestart,
+		taskutil.TaskDiagnose, taskutil.TaskBackup, taskutil.TaskRestore,
+		taskutil.TaskOSUpgrade, cloudhubmodel.OpKeepalive:
 		return true
 	}
 	switch router.Source {
@@ -36,6 +36,7 @@ import (
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
 	"github.com/kubeedge/kubeedge/cloud/pkg/synccontroller"
 	taskutil "github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	taskcontroller "github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
 	commonconst "github.com/kubeedge/kubeedge/common/constants"
 	v2 "github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/v2"
 	"github.com/kubeedge/kubeedge/pkg/apis/reliablesyncs/v1alpha1"
@@ -186,7 +187,12 @@ func (md *messageDispatcher) DispatchUpstream(message *beehivemodel.Message, inf
 		beehivecontext.Send(modules.RouterModuleName, *message)
 
 	case message.GetOperation() == taskutil.TaskPrePull ||
-		message.GetOperation() == taskutil.TaskUpgrade:
+		message.GetOperation() == taskutil.TaskUpgrade ||
+		taskcontroller.IsNodeMessage(message.GetOperation()):
+		if !isTaskOfNode(message, info.NodeID) {
+			klog.Errorf("drop %s task message %s sent by node %s", message.GetOperation(), message.GetResource(), info.NodeID)
+			return
+		}
 		beehivecontext.SendToGroup(modules.TaskManagerModuleGroup, *message)
 
 	case message.GetResource() == beehivemodel.ResourceTypeK8sCA:
@@ -203,6 +209,12 @@ func (md *messageDispatcher) DispatchUpstream(message *beehivemodel.Message, inf
 	}
 }
 
+// isTaskOfNode returns whether the task message is about the node which sent it. The node of the
+// task resource is trusted by TaskManager, a node may only pull, accept and report its own tasks.
+func isTaskOfNode(message *beehivemodel.Message, nodeID string) bool {
+	return taskutil.GetNodeName(message.GetResource()) == nodeID
+}
+
 func (md *messageDispatcher) PubToController(info *model.HubInfo, msg *beehivemodel.Message) error {
 	msg.SetResourceOperation(fmt.Sprintf("node/%s/%s", info.NodeID, msg.GetResource()), msg.GetOperation())
 	if model.IsFromEdge(msg) {
@@ -107,6 +107,47 @@ func TestNoAckRequired(t *testing.T) {
 	}
 }
 
+func TestIsTaskOfNode(t *testing.T) {
+	tests := []struct {
+		name    string
+		message *beehivemodel.Message
+		want    bool
+	}{
+		{
+			name:    "pull of the node",
+			message: beehivemodel.NewMessage("").SetResourceOperation("task/pull/node/edge-node", "pull"),
+			want:    true,
+		},
+		{
+			name:    "status of the node",
+			message: beehivemodel.NewMessage("").SetResourceOperation("task/upgrade-1/node/edge-node", "upgrade"),
+			want:    true,
+		},
+		{
+			name:    "pull of another node",
+			message: beehivemodel.NewMessage("").SetResourceOperation("task/pull/node/other-node", "pull"),
+			want:    false,
+		},
+		{
+			name:    "accept of another node",
+			message: beehivemodel.NewMessage("").SetResourceOperation("task/upgrade-1/node/other-node", "accept"),
+			want:    false,
+		},
+		{
+			name:    "malformed resource",
+			message: beehivemodel.NewMessage("").SetResourceOperation("task/pull", "pull"),
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTaskOfNode(tt.message, "edge-node"); got != tt.want {
+				t.Errorf("isTaskOfNode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetNodeID(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -18,6 +18,7 @@ package handler
 
 import (
 	"context"
+	"crypto/x509"
 	"time"
 
 	"github.com/avast/retry-go"
@@ -28,6 +29,8 @@ import (
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/dispatcher"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/session"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	nodeconnection "github.com/kubeedge/kubeedge/cloud/pkg/common/connection"
 	"github.com/kubeedge/kubeedge/cloud/pkg/edgecontroller/controller"
 	reliableclient "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
 	"github.com/kubeedge/viaduct/pkg/conn"
@@ -125,6 +128,14 @@ func (mh *messageHandler) HandleConnection(connection conn.Connection) {
 		klog.Errorf("The connection is rejected by CloudHub: node=%q, error=%v", nodeID, err)
 		return
 	}
+	// payloads sealed for the node are encrypted with the public key of its certificate
+	if peerCerts := connection.ConnectionState().PeerCertificates; len(peerCerts) > 0 {
+		go func(cert *x509.Certificate) {
+			if err := nodeconnection.ObservePeerCertificate(client.GetKubeClient(), nodeID, cert); err != nil {
+				klog.Errorf("failed to save the public key of node %s: %v", nodeID, err)
+			}
+		}(peerCerts[0])
+	}
 
 	if mh.SessionManager.ReachLimit() {
 		klog.Errorf("Fail to serve node %s, reach node limit", nodeID)
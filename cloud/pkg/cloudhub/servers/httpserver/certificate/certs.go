@@ -76,6 +76,14 @@ func EdgeCoreClientCert(request *restful.Request, response *restful.Response) {
 	resps.OK(response, certBlock.Bytes)
 }
 
+// VerifyNodeCert verifies that the request is sent by the edge node with its certificate signed by the CA
+func VerifyNodeCert(r *http.Request, nodeName string) error {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return errors.New("the certificate of the edge node is required")
+	}
+	return verifyCert(r.TLS.PeerCertificates[0], nodeName)
+}
+
 // verifyCert verifies the edge certificate by CA certificate when edge certificates rotate.
 func verifyCert(cert *x509.Certificate, nodeName string) error {
 	roots := x509.NewCertPool()
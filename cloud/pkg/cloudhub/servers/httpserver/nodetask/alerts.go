@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetask
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// alertWebhookPayload is the payload an Alertmanager webhook receiver posts, only the fields the
+// tasks pause on are decoded
+type alertWebhookPayload struct {
+	Alerts []struct {
+		Status string            `json:"status"`
+		Labels map[string]string `json:"labels"`
+	} `json:"alerts"`
+}
+
+// ReceiveAlerts pauses the NodeUpgradeJobs which pause on the firing alerts posted by an Alertmanager
+// webhook receiver, the caller must be allowed to update the status of all the NodeUpgradeJobs.
+func ReceiveAlerts(request *restful.Request, response *restful.Response) {
+	user, err := authenticate(request)
+	if err != nil {
+		writeError(response, http.StatusUnauthorized, err)
+		return
+	}
+	if err = authorize(user, "update", v1alpha1.Resource("nodeupgradejobs"), ""); err != nil {
+		writeError(response, http.StatusForbidden, err)
+		return
+	}
+	if taskExecutor == nil {
+		writeError(response, http.StatusServiceUnavailable, fmt.Errorf("taskmanager is not enabled"))
+		return
+	}
+	lr := &io.LimitedReader{
+		R: request.Request.Body,
+		N: millionByte + 1,
+	}
+	body, err := io.ReadAll(lr)
+	if err != nil {
+		writeError(response, http.StatusBadRequest, fmt.Errorf("failed to get req body: %v", err))
+		return
+	}
+	if lr.N <= 0 {
+		writeError(response, http.StatusBadRequest, fmt.Errorf("the request body can only be up to 1MB in size"))
+		return
+	}
+	payload := &alertWebhookPayload{}
+	if err = json.Unmarshal(body, payload); err != nil {
+		writeError(response, http.StatusBadRequest, fmt.Errorf("failed to unmarshal the alerts: %v", err))
+		return
+	}
+	var firing []string
+	for _, alert := range payload.Alerts {
+		if alert.Status == "firing" && alert.Labels["alertname"] != "" {
+			firing = append(firing, alert.Labels["alertname"])
+		}
+	}
+	paused := taskExecutor.FireAlerts(firing)
+	if len(paused) != 0 {
+		klog.Infof("alerts %v pause tasks %v", firing, paused)
+	}
+	if paused == nil {
+		paused = []string{}
+	}
+	if err = response.WriteAsJson(paused); err != nil {
+		klog.Errorf("failed to write the tasks paused on alerts %v: %v", firing, err)
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package nodetask
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+)
+
+// ListDeadLetters returns the task messages which got no response from the edge nodes,
+// the caller must be allowed to get the status of the task.
+func ListDeadLetters(request *restful.Request, response *restful.Response) {
+	taskType, taskID, ok := authorizeTask(request, response, "get")
+	if !ok {
+		return
+	}
+	letters, err := listDeadLetters(taskType, taskID)
+	if err != nil {
+		writeError(response, http.StatusInternalServerError, fmt.Errorf("failed to list dead letters of task %s: %v", taskID, err))
+		return
+	}
+	if letters == nil {
+		letters = []util.DeadLetter{}
+	}
+	if err = response.WriteAsJson(letters); err != nil {
+		klog.Errorf("failed to write dead letters of task %s: %v", taskID, err)
+	}
+}
+
+// RedriveResult is the result of redriving the dead letter of a node
+type RedriveResult struct {
+	NodeName string `json:"nodeName"`
+	// Redriven is set once the message is dispatched to the node again
+	Redriven bool `json:"redriven"`
+	// Error is why the dead letter failed to be redriven or removed from the store
+	Error string `json:"error,omitempty"`
+}
+
+// RedriveDeadLetters dispatches the dead letters of the task again and removes them from the store,
+// only the dead letter of the node in query parameter "node" is redriven if it is set. The caller
+// must be allowed to update the status of the task. Nothing is redriven while TaskManager is read-only,
+// on the replicas which are not the leader or for the tasks which are not running, e.g. finished ones
+// which are rerun instead. The node failed by timeout is moved back to the state it timed out in, so
+// that the result of the redriven message is accepted. Each node is redriven on its own, the result
+// of every node is returned and a dead letter is kept if its node cannot be redriven.
+func RedriveDeadLetters(request *restful.Request, response *restful.Response) {
+	taskType, taskID, ok := authorizeTask(request, response, "update")
+	if !ok {
+		return
+	}
+	if taskExecutor == nil {
+		writeError(response, http.StatusServiceUnavailable, fmt.Errorf("taskmanager is not enabled"))
+		return
+	}
+	if config.Config.ReadOnly {
+		writeError(response, http.StatusConflict, fmt.Errorf("taskmanager is read-only, no message is dispatched"))
+		return
+	}
+	letters, err := listDeadLetters(taskType, taskID)
+	if err != nil {
+		writeError(response, http.StatusInternalServerError, fmt.Errorf("failed to list dead letters of task %s: %v", taskID, err))
+		return
+	}
+
+	nodeName := request.QueryParameter("node")
+	results := []RedriveResult{}
+	for _, letter := range letters {
+		if nodeName != "" && letter.NodeName != nodeName {
+			continue
+		}
+		if err = taskExecutor.Redrive(taskType, taskID, letter); err != nil {
+			if errors.Is(err, util.ErrNotLeader) {
+				// no node is redriven on a replica which is not the leader
+				writeError(response, http.StatusServiceUnavailable, fmt.Errorf("failed to redrive dead letters of task %s: %v", taskID, err))
+				return
+			}
+			klog.Warningf("failed to redrive dead letter of task %s for node %s: %v", taskID, letter.NodeName, err)
+			results = append(results, RedriveResult{NodeName: letter.NodeName, Error: err.Error()})
+			continue
+		}
+		result := RedriveResult{NodeName: letter.NodeName, Redriven: true}
+		if err = deleteDeadLetter(taskType, taskID, letter.NodeName); err != nil {
+			klog.Warningf("failed to delete dead letter of task %s for node %s: %v", taskID, letter.NodeName, err)
+			result.Error = fmt.Sprintf("failed to delete dead letter: %v", err)
+		}
+		results = append(results, result)
+	}
+	if nodeName != "" && len(results) == 0 {
+		writeError(response, http.StatusNotFound, fmt.Errorf("no dead letter of task %s for node %s", taskID, nodeName))
+		return
+	}
+	if err = response.WriteAsJson(results); err != nil {
+		klog.Errorf("failed to write redrive results of task %s: %v", taskID, err)
+	}
+}
+
+// authorizeTask checks whether the caller is allowed to access the status of the task with the verb
+func authorizeTask(request *restful.Request, response *restful.Response, verb string) (string, string, bool) {
+	taskID := request.PathParameter("taskID")
+	taskType := request.PathParameter("taskType")
+
+	objects, ok := util.GetTaskObjects(taskType)
+	if !ok {
+		writeError(response, http.StatusNotFound, fmt.Errorf("unsupported task type %s", taskType))
+		return "", "", false
+	}
+	user, err := authenticate(request)
+	if err != nil {
+		writeError(response, http.StatusUnauthorized, err)
+		return "", "", false
+	}
+	if err = authorize(user, verb, objects.Resource, taskID); err != nil {
+		writeError(response, http.StatusForbidden, err)
+		return "", "", false
+	}
+	return taskType, taskID, true
+}
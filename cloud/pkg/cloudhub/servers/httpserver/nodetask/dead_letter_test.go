@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetask
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/client/clientset/versioned/fake"
+)
+
+// useDeadLetters replaces the store of the dead letters with the letters of the nodes until the test ends,
+// the deletion of the letters of the nodes in failDelete fails
+func useDeadLetters(t *testing.T, nodes []string, failDelete map[string]bool) map[string]bool {
+	list, del := listDeadLetters, deleteDeadLetter
+	t.Cleanup(func() {
+		listDeadLetters, deleteDeadLetter = list, del
+	})
+	letters := map[string]bool{}
+	for _, node := range nodes {
+		letters[node] = true
+	}
+	listDeadLetters = func(_, _ string) ([]util.DeadLetter, error) {
+		var result []util.DeadLetter
+		for _, node := range nodes {
+			if letters[node] {
+				result = append(result, util.DeadLetter{NodeName: node})
+			}
+		}
+		return result, nil
+	}
+	deleteDeadLetter = func(_, _, nodeName string) error {
+		if failDelete[nodeName] {
+			return fmt.Errorf("configmap is unavailable")
+		}
+		delete(letters, nodeName)
+		return nil
+	}
+	return letters
+}
+
+func TestRedriveDeadLetters(t *testing.T) {
+	executor := taskExecutor
+	defer func() {
+		taskExecutor = executor
+	}()
+	useClients(t, fakeAuthClient(true), fake.NewSimpleClientset())
+	path := "/task/" + util.TaskSupportBundle + "/name/bundle/deadletters/redrive"
+
+	for _, tc := range []struct {
+		name       string
+		path       string
+		redrive    map[string]error
+		failDelete map[string]bool
+		code       int
+		results    []RedriveResult
+		kept       []string
+	}{
+		{
+			name: "all nodes redriven",
+			path: path,
+			code: http.StatusOK,
+			results: []RedriveResult{
+				{NodeName: "edge-1", Redriven: true},
+				{NodeName: "edge-2", Redriven: true},
+				{NodeName: "edge-3", Redriven: true},
+			},
+		},
+		{
+			name:    "failing node does not stop the others",
+			path:    path,
+			redrive: map[string]error{"edge-1": errors.New("node edge-1 is finished")},
+			code:    http.StatusOK,
+			results: []RedriveResult{
+				{NodeName: "edge-1", Error: "node edge-1 is finished"},
+				{NodeName: "edge-2", Redriven: true},
+				{NodeName: "edge-3", Redriven: true},
+			},
+			kept: []string{"edge-1"},
+		},
+		{
+			name:       "dead letter which fails to be removed",
+			path:       path,
+			failDelete: map[string]bool{"edge-2": true},
+			code:       http.StatusOK,
+			results: []RedriveResult{
+				{NodeName: "edge-1", Redriven: true},
+				{NodeName: "edge-2", Redriven: true, Error: "failed to delete dead letter: configmap is unavailable"},
+				{NodeName: "edge-3", Redriven: true},
+			},
+			kept: []string{"edge-2"},
+		},
+		{
+			name:    "single node",
+			path:    path + "?node=edge-2",
+			code:    http.StatusOK,
+			results: []RedriveResult{{NodeName: "edge-2", Redriven: true}},
+			kept:    []string{"edge-1", "edge-3"},
+		},
+		{
+			name: "unknown node",
+			path: path + "?node=edge-4",
+			code: http.StatusNotFound,
+			kept: []string{"edge-1", "edge-2", "edge-3"},
+		},
+		{
+			name:    "not the leader",
+			path:    path,
+			redrive: map[string]error{"edge-1": util.ErrNotLeader},
+			code:    http.StatusServiceUnavailable,
+			kept:    []string{"edge-1", "edge-2", "edge-3"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			letters := useDeadLetters(t, []string{"edge-1", "edge-2", "edge-3"}, tc.failDelete)
+			SetTaskExecutor(&fakeTaskExecutor{redrive: tc.redrive})
+			resp := serveTaskRequest(RedriveDeadLetters, http.MethodPost, constants.DefaultTaskRedriveURL, tc.path, "valid")
+			if resp.Code != tc.code {
+				t.Fatalf("expected %d, got %d: %s", tc.code, resp.Code, resp.Body.String())
+			}
+			var kept []string
+			for _, node := range []string{"edge-1", "edge-2", "edge-3"} {
+				if letters[node] {
+					kept = append(kept, node)
+				}
+			}
+			if !reflect.DeepEqual(kept, tc.kept) {
+				t.Errorf("expected the dead letters of %v to be kept, got %v", tc.kept, kept)
+			}
+			if tc.code != http.StatusOK {
+				return
+			}
+			var results []RedriveResult
+			if err := json.Unmarshal(resp.Body.Bytes(), &results); err != nil {
+				t.Fatalf("failed to unmarshal the redrive results: %v", err)
+			}
+			if !reflect.DeepEqual(results, tc.results) {
+				t.Errorf("expected results %+v, got %+v", tc.results, results)
+			}
+		})
+	}
+
+	taskExecutor = nil
+	if resp := serveTaskRequest(RedriveDeadLetters, http.MethodPost, constants.DefaultTaskRedriveURL, path, "valid"); resp.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 before taskmanager is set, got %d", resp.Code)
+	}
+}
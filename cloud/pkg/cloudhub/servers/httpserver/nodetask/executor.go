@@ -0,0 +1,51 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetask
+
+import (
+	beehiveModel "github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+)
+
+// TaskExecutor is the executor machine of TaskManager which the handlers of node tasks work with,
+// cloudhub does not depend on TaskManager, which sets it once its executor machine is created
+type TaskExecutor interface {
+	// PullTasks takes the task messages held for the edge node in pull mode
+	PullTasks(nodeName string) ([]beehiveModel.Message, error)
+	// Redrive dispatches the dead letter of the task to the edge node again
+	Redrive(taskType, taskID string, letter util.DeadLetter) error
+	// FireAlerts pauses the tasks which pause on the firing alerts, it returns the paused tasks
+	FireAlerts(alerts []string) []string
+}
+
+var taskExecutor TaskExecutor
+
+// SetTaskExecutor sets the executor machine of TaskManager, the handlers which need it answer
+// with 503 until it is set
+func SetTaskExecutor(executor TaskExecutor) {
+	taskExecutor = executor
+}
+
+// the clients of cloudcore the handlers authorize the callers and read the tasks with,
+// and the store of the dead letters of the tasks
+var (
+	getKubeClient    = client.GetKubeClient
+	getCRDClient     = client.GetCRDClient
+	listDeadLetters  = util.ListDeadLetters
+	deleteDeadLetter = util.DeleteDeadLetter
+)
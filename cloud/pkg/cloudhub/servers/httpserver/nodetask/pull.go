@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetask
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/klog/v2"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	beehiveModel "github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/certificate"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	taskcontroller "github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
+)
+
+// PullTasks returns the task messages held for the edge node in pull mode, the node polls for them
+// with its certificate over HTTPS rather than through its edgehub connection. The replicas of cloudcore
+// which do not hold the messages answer with 503, the node polls again at its next interval.
+func PullTasks(request *restful.Request, response *restful.Response) {
+	nodeID := request.PathParameter("nodeID")
+	if err := certificate.VerifyNodeCert(request.Request, nodeID); err != nil {
+		writeError(response, http.StatusUnauthorized, err)
+		return
+	}
+	if taskExecutor == nil {
+		writeError(response, http.StatusServiceUnavailable, fmt.Errorf("taskmanager is not enabled"))
+		return
+	}
+	msgs, err := taskExecutor.PullTasks(nodeID)
+	if err != nil {
+		writeError(response, http.StatusServiceUnavailable, fmt.Errorf("failed to pull tasks of node %s: %v", nodeID, err))
+		return
+	}
+	if err = response.WriteAsJson(msgs); err != nil {
+		klog.Errorf("failed to write tasks pulled by node %s: %v", nodeID, err)
+	}
+}
+
+// ReceiveTaskMessage receives the task message the edge node sends over HTTPS in pull mode, i.e. the
+// receipts, results and probe answers of its tasks. The message is handled the same as the ones sent
+// through the edgehub connection of the node.
+func ReceiveTaskMessage(request *restful.Request, response *restful.Response) {
+	nodeID := request.PathParameter("nodeID")
+	if err := certificate.VerifyNodeCert(request.Request, nodeID); err != nil {
+		writeError(response, http.StatusUnauthorized, err)
+		return
+	}
+	lr := &io.LimitedReader{
+		R: request.Request.Body,
+		N: millionByte + 1,
+	}
+	body, err := io.ReadAll(lr)
+	if err != nil {
+		writeError(response, http.StatusBadRequest, fmt.Errorf("failed to get req body: %v", err))
+		return
+	}
+	if lr.N <= 0 {
+		writeError(response, http.StatusBadRequest, fmt.Errorf("the request body can only be up to 3MB in size"))
+		return
+	}
+	msg := beehiveModel.Message{}
+	if err = json.Unmarshal(body, &msg); err != nil {
+		writeError(response, http.StatusBadRequest, fmt.Errorf("failed to unmarshal task message: %v", err))
+		return
+	}
+	if !taskcontroller.IsNodeMessage(msg.GetOperation()) {
+		writeError(response, http.StatusBadRequest, fmt.Errorf("unsupported task message operation %s", msg.GetOperation()))
+		return
+	}
+	if util.GetNodeName(msg.GetResource()) != nodeID {
+		writeError(response, http.StatusForbidden, fmt.Errorf("task message %s is not of node %s", msg.GetResource(), nodeID))
+		return
+	}
+	msg.SetRoute(modules.CloudHubModuleName, modules.CloudHubModuleGroup)
+	beehiveContext.SendToGroup(modules.TaskManagerModuleGroup, msg)
+
+	if _, err = response.Write([]byte("ok")); err != nil {
+		klog.Errorf("failed to answer the task message of node %s: %v", nodeID, err)
+	}
+}
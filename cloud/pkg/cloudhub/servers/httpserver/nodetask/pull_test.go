@@ -0,0 +1,217 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetask
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/kubeedge/beehive/pkg/common"
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	beehiveModel "github.com/kubeedge/beehive/pkg/core/model"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/common/constants"
+)
+
+type fakeTaskExecutor struct {
+	pending map[string][]beehiveModel.Message
+	err     error
+	// redrive returns the error of redriving the dead letter of the node
+	redrive  map[string]error
+	redriven []string
+	paused   []string
+}
+
+func (f *fakeTaskExecutor) Redrive(_, _ string, letter util.DeadLetter) error {
+	if err := f.redrive[letter.NodeName]; err != nil {
+		return err
+	}
+	f.redriven = append(f.redriven, letter.NodeName)
+	return nil
+}
+
+func (f *fakeTaskExecutor) FireAlerts(_ []string) []string {
+	return f.paused
+}
+
+func (f *fakeTaskExecutor) PullTasks(nodeName string) ([]beehiveModel.Message, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	msgs := f.pending[nodeName]
+	delete(f.pending, nodeName)
+	return msgs, nil
+}
+
+// newNodeCert returns the certificate of the edge node signed by a new CA, which is set as the CA of cloudhub
+func newNodeCert(t *testing.T, nodeName string) *x509.Certificate {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate CA key: %v", err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "KubeEdge"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("failed to create CA: %v", err)
+	}
+	hubconfig.Config.Ca = caDER
+	ca, _ := x509.ParseCertificate(caDER)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate node key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{Organization: []string{"system:nodes"}, CommonName: "system:node:" + nodeName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("failed to create node certificate: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return cert
+}
+
+func serveNodeRequest(handler restful.RouteFunction, method, route, path string, body []byte, cert *x509.Certificate) *httptest.ResponseRecorder {
+	ws := new(restful.WebService)
+	ws.Route(ws.Method(method).Path(route).To(handler))
+	container := restful.NewContainer()
+	container.Add(ws)
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	if cert != nil {
+		req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	}
+	recorder := httptest.NewRecorder()
+	container.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestPullTasks(t *testing.T) {
+	ca, executor := hubconfig.Config.Ca, taskExecutor
+	defer func() {
+		hubconfig.Config.Ca, taskExecutor = ca, executor
+	}()
+	cert := newNodeCert(t, "edge-1")
+	task := beehiveModel.NewMessage("").SetResourceOperation("task/upgrade/node/edge-1", util.TaskUpgrade)
+	fake := &fakeTaskExecutor{pending: map[string][]beehiveModel.Message{"edge-1": {*task}}}
+	path := "/task/node/edge-1/pull"
+
+	taskExecutor = nil
+	if resp := serveNodeRequest(PullTasks, http.MethodGet, constants.DefaultTaskPullURL, path, nil, cert); resp.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 before taskmanager is set, got %d", resp.Code)
+	}
+	SetTaskExecutor(fake)
+	if resp := serveNodeRequest(PullTasks, http.MethodGet, constants.DefaultTaskPullURL, path, nil, nil); resp.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without the certificate of the node, got %d", resp.Code)
+	}
+	other := "/task/node/edge-2/pull"
+	if resp := serveNodeRequest(PullTasks, http.MethodGet, constants.DefaultTaskPullURL, other, nil, cert); resp.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for pulling the tasks of another node, got %d", resp.Code)
+	}
+
+	resp := serveNodeRequest(PullTasks, http.MethodGet, constants.DefaultTaskPullURL, path, nil, cert)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected the tasks to be pulled, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var msgs []beehiveModel.Message
+	if err := json.Unmarshal(resp.Body.Bytes(), &msgs); err != nil {
+		t.Fatalf("failed to unmarshal the pulled tasks: %v", err)
+	}
+	if len(msgs) != 1 || msgs[0].GetID() != task.GetID() {
+		t.Errorf("expected the pending task to be pulled, got %v", msgs)
+	}
+
+	fake.err = errors.New("not the leader")
+	if resp = serveNodeRequest(PullTasks, http.MethodGet, constants.DefaultTaskPullURL, path, nil, cert); resp.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 from a replica which does not hold the tasks, got %d", resp.Code)
+	}
+}
+
+func TestReceiveTaskMessage(t *testing.T) {
+	ca := hubconfig.Config.Ca
+	defer func() {
+		hubconfig.Config.Ca = ca
+	}()
+	cert := newNodeCert(t, "edge-1")
+	beehiveContext.InitContext([]string{common.MsgCtxTypeChannel})
+	beehiveContext.AddModule(&common.ModuleInfo{ModuleName: modules.TaskManagerModuleName, ModuleType: common.MsgCtxTypeChannel})
+	beehiveContext.AddModuleGroup(modules.TaskManagerModuleName, modules.TaskManagerModuleGroup)
+	path := "/task/node/edge-1/messages"
+
+	for _, tc := range []struct {
+		name     string
+		resource string
+		op       string
+		code     int
+	}{
+		{name: "receipt of the node", resource: "task/upgrade/node/edge-1", op: util.TaskAccept, code: http.StatusOK},
+		{name: "message of another node", resource: "task/upgrade/node/edge-2", op: util.TaskAccept, code: http.StatusForbidden},
+		{name: "not a task message", resource: "task/upgrade/node/edge-1", op: "update", code: http.StatusBadRequest},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := beehiveModel.NewMessage("").SetResourceOperation(tc.resource, tc.op).FillBody("accepted")
+			body, _ := json.Marshal(msg)
+			resp := serveNodeRequest(ReceiveTaskMessage, http.MethodPost, constants.DefaultTaskMessageURL, path, body, cert)
+			if resp.Code != tc.code {
+				t.Fatalf("expected %d, got %d: %s", tc.code, resp.Code, resp.Body.String())
+			}
+			if tc.code != http.StatusOK {
+				return
+			}
+			received, err := beehiveContext.Receive(modules.TaskManagerModuleName)
+			if err != nil {
+				t.Fatalf("failed to receive the task message: %v", err)
+			}
+			if received.GetID() != msg.GetID() || received.GetResource() != tc.resource {
+				t.Errorf("expected the task message to be sent to taskmanager, got %v", received)
+			}
+		})
+	}
+
+	if resp := serveNodeRequest(ReceiveTaskMessage, http.MethodPost, constants.DefaultTaskMessageURL, path, []byte("{}"), nil); resp.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without the certificate of the node, got %d", resp.Code)
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetask
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/supportbundlecontroller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+)
+
+// GetSupportBundle returns the archive packaged by a finished SupportBundleJob on this cloudcore,
+// the caller must be allowed to get the status of the job.
+func GetSupportBundle(request *restful.Request, response *restful.Response) {
+	taskType, taskID, ok := authorizeTask(request, response, "get")
+	if !ok {
+		return
+	}
+	if taskType != util.TaskSupportBundle {
+		writeError(response, http.StatusNotFound, fmt.Errorf("task type %s has no bundle", taskType))
+		return
+	}
+	file, err := os.Open(supportbundlecontroller.ArchivePath(taskID))
+	if os.IsNotExist(err) {
+		writeError(response, http.StatusNotFound, fmt.Errorf("bundle of task %s is not packaged by this cloudcore", taskID))
+		return
+	}
+	if err != nil {
+		writeError(response, http.StatusInternalServerError, fmt.Errorf("failed to open bundle of task %s: %v", taskID, err))
+		return
+	}
+	defer file.Close()
+
+	response.AddHeader("Content-Type", "application/gzip")
+	response.AddHeader("Content-Disposition", fmt.Sprintf("attachment; filename=%q", taskID+".tar.gz"))
+	if _, err = io.Copy(response, file); err != nil {
+		klog.Errorf("failed to write bundle of task %s: %v", taskID, err)
+	}
+}
+
+// GetNodeSupportBundle returns the bundle collected from an edge node by a SupportBundleJob and
+// stored on this cloudcore, the caller must be allowed to get the status of the job.
+func GetNodeSupportBundle(request *restful.Request, response *restful.Response) {
+	taskType, taskID, ok := authorizeTask(request, response, "get")
+	if !ok {
+		return
+	}
+	if taskType != util.TaskSupportBundle {
+		writeError(response, http.StatusNotFound, fmt.Errorf("task type %s has no bundle", taskType))
+		return
+	}
+	nodeID := request.PathParameter("nodeID")
+	file, err := os.Open(supportbundlecontroller.NodeBundlePath(taskID, nodeID))
+	if os.IsNotExist(err) {
+		writeError(response, http.StatusNotFound, fmt.Errorf("bundle of node %s in task %s is not stored by this cloudcore", nodeID, taskID))
+		return
+	}
+	if err != nil {
+		writeError(response, http.StatusInternalServerError, fmt.Errorf("failed to open bundle of node %s in task %s: %v", nodeID, taskID, err))
+		return
+	}
+	defer file.Close()
+
+	response.AddHeader("Content-Type", "application/gzip")
+	response.AddHeader("Content-Disposition", fmt.Sprintf("attachment; filename=%q", nodeID+".tar.gz"))
+	if _, err = io.Copy(response, file); err != nil {
+		klog.Errorf("failed to write bundle of node %s in task %s: %v", nodeID, taskID, err)
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package nodetask
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+)
+
+// GetTaskStatus returns the status of task, the caller is authenticated with its bearer token
+// and the task is only visible when the caller is allowed to get its status subresource. The
+// status is read with the client of cloudcore once the SubjectAccessReview allows the caller.
+func GetTaskStatus(request *restful.Request, response *restful.Response) {
+	taskID := request.PathParameter("taskID")
+	taskType := request.PathParameter("taskType")
+
+	objects, ok := util.GetTaskObjects(taskType)
+	if !ok {
+		writeError(response, http.StatusNotFound, fmt.Errorf("unsupported task type %s", taskType))
+		return
+	}
+
+	user, err := authenticate(request)
+	if err != nil {
+		writeError(response, http.StatusUnauthorized, err)
+		return
+	}
+	if err = authorize(user, "get", objects.Resource, taskID); err != nil {
+		writeError(response, http.StatusForbidden, err)
+		return
+	}
+
+	job, err := objects.Client(getCRDClient()).Get(context.TODO(), taskID)
+	if apierrors.IsNotFound(err) {
+		writeError(response, http.StatusNotFound, fmt.Errorf("%s task %s is not found", taskType, taskID))
+		return
+	}
+	if err != nil {
+		writeError(response, http.StatusInternalServerError, fmt.Errorf("failed to get %s task %s: %v", taskType, taskID, err))
+		return
+	}
+
+	if err = response.WriteAsJson(objects.Status(job).Status); err != nil {
+		klog.Errorf("failed to write status of task %s: %v", taskID, err)
+	}
+}
+
+func authenticate(request *restful.Request) (*authenticationv1.UserInfo, error) {
+	auth := request.HeaderParameter("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return nil, fmt.Errorf("bearer token is required")
+	}
+	token := strings.TrimPrefix(auth, "Bearer ")
+
+	review := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}
+	ret, err := getKubeClient().AuthenticationV1().TokenReviews().Create(context.TODO(), review, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to review token: %v", err)
+	}
+	if !ret.Status.Authenticated {
+		return nil, fmt.Errorf("token is not authenticated: %s", ret.Status.Error)
+	}
+	return &ret.Status.User, nil
+}
+
+func authorize(user *authenticationv1.UserInfo, verb string, resource schema.GroupResource, name string) error {
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:        verb,
+				Group:       resource.Group,
+				Resource:    resource.Resource,
+				Subresource: "status",
+				Name:        name,
+			},
+			User:   user.Username,
+			Groups: user.Groups,
+			UID:    user.UID,
+			Extra:  extra,
+		},
+	}
+	ret, err := getKubeClient().AuthorizationV1().SubjectAccessReviews().Create(context.TODO(), review, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("user %s permission check failed: %v", user.Username, err)
+	}
+	if !ret.Status.Allowed {
+		return fmt.Errorf("user %q is not allowed to %s the status of %s %s", user.Username, verb, resource, name)
+	}
+	return nil
+}
+
+func writeError(response *restful.Response, status int, err error) {
+	if err := response.WriteError(status, err); err != nil {
+		klog.Warning(err.Error())
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetask
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/common/constants"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+	"github.com/kubeedge/kubeedge/pkg/client/clientset/versioned/fake"
+)
+
+// fakeAuthClient returns the kube client which authenticates the bearer token "valid" as user "ops",
+// the SubjectAccessReviews of user "ops" are allowed if allowed is set
+func fakeAuthClient(allowed bool) *k8sfake.Clientset {
+	kubeClient := k8sfake.NewSimpleClientset()
+	kubeClient.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		if review.Spec.Token == "valid" {
+			review.Status = authenticationv1.TokenReviewStatus{Authenticated: true, User: authenticationv1.UserInfo{Username: "ops"}}
+		} else {
+			review.Status = authenticationv1.TokenReviewStatus{Error: "invalid token"}
+		}
+		return true, review, nil
+	})
+	kubeClient.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		review.Status.Allowed = allowed && review.Spec.User == "ops"
+		return true, review, nil
+	})
+	return kubeClient
+}
+
+// useClients replaces the clients of cloudcore the handlers use until the test ends
+func useClients(t *testing.T, kubeClient kubernetes.Interface, crdClient crdClientset.Interface) {
+	kube, crd := getKubeClient, getCRDClient
+	t.Cleanup(func() {
+		getKubeClient, getCRDClient = kube, crd
+	})
+	getKubeClient = func() kubernetes.Interface { return kubeClient }
+	getCRDClient = func() crdClientset.Interface { return crdClient }
+}
+
+func serveTaskRequest(handler restful.RouteFunction, method, route, path, token string) *httptest.ResponseRecorder {
+	ws := new(restful.WebService)
+	ws.Route(ws.Method(method).Path(route).To(handler))
+	container := restful.NewContainer()
+	container.Add(ws)
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	recorder := httptest.NewRecorder()
+	container.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestGetTaskStatus(t *testing.T) {
+	job := &v1alpha1.SupportBundleJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "bundle"},
+		Status:     v1alpha1.SupportBundleJobStatus{CommonJobStatus: v1alpha1.CommonJobStatus{State: api.TaskSuccessful}},
+	}
+	crdClient := fake.NewSimpleClientset(job)
+	path := "/task/" + util.TaskSupportBundle + "/name/bundle/status"
+
+	for _, tc := range []struct {
+		name    string
+		path    string
+		token   string
+		allowed bool
+		code    int
+	}{
+		{name: "missing token", path: path, allowed: true, code: http.StatusUnauthorized},
+		{name: "invalid token", path: path, token: "invalid", allowed: true, code: http.StatusUnauthorized},
+		{name: "denied by SubjectAccessReview", path: path, token: "valid", code: http.StatusForbidden},
+		{name: "unknown job", path: "/task/" + util.TaskSupportBundle + "/name/missing/status", token: "valid", allowed: true, code: http.StatusNotFound},
+		{name: "unsupported task type", path: "/task/unknown/name/bundle/status", token: "valid", allowed: true, code: http.StatusNotFound},
+		{name: "allowed", path: path, token: "valid", allowed: true, code: http.StatusOK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			useClients(t, fakeAuthClient(tc.allowed), crdClient)
+			resp := serveTaskRequest(GetTaskStatus, http.MethodGet, constants.DefaultTaskStatusURL, tc.path, tc.token)
+			if resp.Code != tc.code {
+				t.Fatalf("expected %d, got %d: %s", tc.code, resp.Code, resp.Body.String())
+			}
+			if tc.code != http.StatusOK {
+				return
+			}
+			status := v1alpha1.SupportBundleJobStatus{}
+			if err := json.Unmarshal(resp.Body.Bytes(), &status); err != nil {
+				t.Fatalf("failed to unmarshal the status: %v", err)
+			}
+			if status.State != api.TaskSuccessful {
+				t.Errorf("expected the status of the job, got %+v", status)
+			}
+		})
+	}
+}
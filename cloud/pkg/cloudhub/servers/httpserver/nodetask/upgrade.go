@@ -41,11 +41,6 @@ const (
 // UpgradeEdge upgrade the edgecore version
 func UpgradeEdge(request *restful.Request, response *restful.Response) {
 	resp := commontypes.NodeUpgradeJobResponse{}
-
-	taskID := resp.UpgradeID
-	taskType := util.TaskUpgrade
-	nodeID := resp.NodeName
-
 	lr := &io.LimitedReader{
 		R: request.Request.Body,
 		N: millionByte + 1,
@@ -72,6 +67,10 @@ func UpgradeEdge(request *restful.Request, response *restful.Response) {
 		}
 		return
 	}
+	// the task is identified by the response, it is known only once the body is read
+	taskID := resp.UpgradeID
+	taskType := util.TaskUpgrade
+	nodeID := resp.NodeName
 	newResp := commontypes.NodeTaskResponse{
 		NodeName: resp.NodeName,
 		Event:    "Upgrade",
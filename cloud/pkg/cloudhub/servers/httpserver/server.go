@@ -61,5 +61,13 @@ func routes() *restful.WebService {
 	ws.Route(ws.GET(constants.DefaultCAURL).To(certshandler.GetCA))
 	ws.Route(ws.POST(constants.DefaultNodeUpgradeURL).To(nodetaskhandler.UpgradeEdge))
 	ws.Route(ws.POST(constants.DefaultTaskStateReportURL).To(nodetaskhandler.ReportStatus))
+	ws.Route(ws.GET(constants.DefaultTaskStatusURL).To(nodetaskhandler.GetTaskStatus))
+	ws.Route(ws.GET(constants.DefaultTaskDeadLettersURL).To(nodetaskhandler.ListDeadLetters))
+	ws.Route(ws.POST(constants.DefaultTaskRedriveURL).To(nodetaskhandler.RedriveDeadLetters))
+	ws.Route(ws.GET(constants.DefaultTaskBundleURL).To(nodetaskhandler.GetSupportBundle))
+	ws.Route(ws.POST(constants.DefaultTaskAlertsURL).To(nodetaskhandler.ReceiveAlerts))
+	ws.Route(ws.GET(constants.DefaultTaskNodeBundleURL).To(nodetaskhandler.GetNodeSupportBundle))
+	ws.Route(ws.GET(constants.DefaultTaskPullURL).To(nodetaskhandler.PullTasks))
+	ws.Route(ws.POST(constants.DefaultTaskMessageURL).To(nodetaskhandler.ReceiveTaskMessage))
 	return ws
 }
@@ -31,6 +31,7 @@ import (
 	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/connection"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/messagelayer"
 	deviceconst "github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/constants"
 	edgeconst "github.com/kubeedge/kubeedge/cloud/pkg/edgecontroller/constants"
@@ -343,6 +344,7 @@ func (ns *NodeSession) sendMessageWithRetry(copyMsg, msg *beehivemodel.Message)
 	retryCount := 0
 	ticker := time.NewTimer(sendRetryInterval)
 
+	sendTime := time.Now()
 	err := ns.connection.WriteMessageAsync(copyMsg)
 	if err != nil {
 		return err
@@ -351,6 +353,10 @@ func (ns *NodeSession) sendMessageWithRetry(copyMsg, msg *beehivemodel.Message)
 	for {
 		select {
 		case <-ackChan:
+			// the round trip time is only accurate when the ack is for the first attempt
+			if retryCount == 0 {
+				connection.ObserveRTT(ns.nodeID, time.Since(sendTime))
+			}
 			ns.saveSuccessPoint(msg)
 			return nil
 
@@ -23,6 +23,7 @@ import (
 
 	"k8s.io/klog/v2"
 
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/connection"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
 )
 
@@ -60,6 +61,7 @@ func (sm *Manager) AddSession(session *NodeSession) {
 
 	sm.NodeSessions.Store(nodeID, session)
 	monitor.ConnectedNodes.Set(float64(atomic.AddInt32(&sm.NodeNumber, 1)))
+	connection.ObserveConnect(nodeID)
 }
 
 // DeleteSession delete the node session from session manager
@@ -78,6 +80,7 @@ func (sm *Manager) DeleteSession(session *NodeSession) {
 
 	sm.NodeSessions.Delete(session.nodeID)
 	monitor.ConnectedNodes.Set(float64(atomic.AddInt32(&sm.NodeNumber, -1)))
+	connection.ObserveDisconnect(session.nodeID)
 }
 
 // GetSession get the node session for the node
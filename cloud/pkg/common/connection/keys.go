@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+)
+
+// PublicKeyDataKey is the key of the PEM encoded public key in the ConfigMap of a node
+const PublicKeyDataKey = "publicKey"
+
+var (
+	// savedPublicKeys are the keys this cloudhub instance has saved by node, a key is only written
+	// to its ConfigMap again once the node connects with another one
+	savedPublicKeys   = map[string]string{}
+	savedPublicKeysMu sync.Mutex
+)
+
+// PublicKeyConfigMapName returns the name of the ConfigMap of the kubeedge namespace which stores
+// the public key of the node
+func PublicKeyConfigMapName(nodeID string) string {
+	return nodeID + "-public-key"
+}
+
+// ObservePeerCertificate saves the public key of the certificate the node is authenticated with in the
+// ConfigMap of the node, so that every cloudcore instance seals payloads for the node, not only the one
+// it is connected to. The ConfigMap is owned by the node and garbage-collected with it.
+func ObservePeerCertificate(kubeClient kubernetes.Interface, nodeID string, cert *x509.Certificate) error {
+	der, err := x509.MarshalPKIXPublicKey(cert.PublicKey)
+	if err != nil {
+		return fmt.Errorf("failed to marshal public key of node %s: %v", nodeID, err)
+	}
+	key := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	savedPublicKeysMu.Lock()
+	saved := savedPublicKeys[nodeID] == key
+	savedPublicKeysMu.Unlock()
+	if saved {
+		return nil
+	}
+	owned, err := savePublicKey(kubeClient, nodeID, key)
+	if err != nil {
+		return err
+	}
+	// the key is saved again once the node object exists, so the ConfigMap gets its owner
+	if owned {
+		savedPublicKeysMu.Lock()
+		savedPublicKeys[nodeID] = key
+		savedPublicKeysMu.Unlock()
+	}
+	return nil
+}
+
+// savePublicKey writes the key to the ConfigMap of the node, owned is false if the node object does
+// not exist yet and the ConfigMap is not owned by it
+func savePublicKey(kubeClient kubernetes.Interface, nodeID, key string) (owned bool, err error) {
+	meta := metav1.ObjectMeta{Name: PublicKeyConfigMapName(nodeID), Namespace: constants.SystemNamespace}
+	node, err := kubeClient.CoreV1().Nodes().Get(context.TODO(), nodeID, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		meta.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "v1",
+			Kind:       "Node",
+			Name:       node.Name,
+			UID:        node.UID,
+		}}
+		owned = true
+	case !apierrors.IsNotFound(err):
+		return false, fmt.Errorf("failed to get node %s: %v", nodeID, err)
+	}
+
+	configMaps := kubeClient.CoreV1().ConfigMaps(constants.SystemNamespace)
+	cm, err := configMaps.Get(context.TODO(), meta.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{ObjectMeta: meta, Data: map[string]string{PublicKeyDataKey: key}}
+		_, err = configMaps.Create(context.TODO(), cm, metav1.CreateOptions{})
+		return owned, err
+	}
+	if err != nil {
+		return false, err
+	}
+	if cm.Data[PublicKeyDataKey] == key && (!owned || len(cm.OwnerReferences) != 0) {
+		return owned, nil
+	}
+	cm.Data = map[string]string{PublicKeyDataKey: key}
+	if owned {
+		cm.OwnerReferences = meta.OwnerReferences
+	}
+	_, err = configMaps.Update(context.TODO(), cm, metav1.UpdateOptions{})
+	return owned, err
+}
+
+// GetPublicKey returns the public key of the certificate the node last connected with to any
+// cloudcore instance
+func GetPublicKey(kubeClient kubernetes.Interface, nodeID string) (crypto.PublicKey, error) {
+	cm, err := kubeClient.CoreV1().ConfigMaps(constants.SystemNamespace).Get(context.TODO(), PublicKeyConfigMapName(nodeID), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("public key of node %s is unknown, the node has not connected to cloudcore", nodeID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get public key of node %s: %v", nodeID, err)
+	}
+	block, _ := pem.Decode([]byte(cm.Data[PublicKeyDataKey]))
+	if block == nil {
+		return nil, fmt.Errorf("public key of node %s is malformed", nodeID)
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+)
+
+func TestPublicKeySharedByInstances(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	savedPublicKeys = map[string]string{}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	if _, err = GetPublicKey(kubeClient, "edge-1"); err == nil {
+		t.Errorf("expected the key of a node never connected to be unknown")
+	}
+	// the node connects before its node object is created, the ConfigMap is not owned yet
+	if err = ObservePeerCertificate(kubeClient, "edge-1", &x509.Certificate{PublicKey: &key.PublicKey}); err != nil {
+		t.Fatalf("failed to save public key: %v", err)
+	}
+	// another instance seals payloads for the node with the saved key
+	publicKey, err := GetPublicKey(kubeClient, "edge-1")
+	if err != nil {
+		t.Fatalf("failed to get public key: %v", err)
+	}
+	if !reflect.DeepEqual(publicKey, &key.PublicKey) {
+		t.Errorf("expected the public key of the certificate, got %v", publicKey)
+	}
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "edge-1", UID: "uid-1"}}
+	if _, err = kubeClient.CoreV1().Nodes().Create(context.TODO(), node, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	if err = ObservePeerCertificate(kubeClient, "edge-1", &x509.Certificate{PublicKey: &key.PublicKey}); err != nil {
+		t.Fatalf("failed to save public key: %v", err)
+	}
+	cm, err := kubeClient.CoreV1().ConfigMaps(constants.SystemNamespace).Get(context.TODO(), PublicKeyConfigMapName("edge-1"), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get ConfigMap: %v", err)
+	}
+	if len(cm.OwnerReferences) != 1 || cm.OwnerReferences[0].UID != "uid-1" {
+		t.Errorf("expected the ConfigMap to be owned by the node, got %v", cm.OwnerReferences)
+	}
+}
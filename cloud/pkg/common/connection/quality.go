@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// HistoryWindow is how long the connection history of edge nodes is kept
+const HistoryWindow = time.Hour
+
+// ForgetWindow is how long a node stays disconnected before its connection quality is dropped,
+// the nodes deleted from the cluster or moved to another cloudhub instance are not kept forever
+const ForgetWindow = 24 * time.Hour
+
+// rttWeight is the weight of the latest sample in the smoothed round trip time
+const rttWeight = 0.2
+
+// Quality is the connection quality of an edge node observed by this cloudhub instance
+type Quality struct {
+	// Known is false if the node never connected to this cloudhub instance
+	Known bool
+	// Connected indicates whether the node is connected now
+	Connected bool
+	// RTT is the smoothed round trip time of messages acked by the node
+	RTT time.Duration
+	// Connects are the times the node connected within HistoryWindow
+	Connects []time.Time
+	// Disconnects are the times the node disconnected within HistoryWindow
+	Disconnects []time.Time
+
+	// disconnectedAt is the last time the node disconnected, it is kept beyond HistoryWindow
+	disconnectedAt time.Time
+}
+
+// DisconnectsSince returns how many times the node disconnected since the given time
+func (q Quality) DisconnectsSince(since time.Time) int {
+	count := 0
+	for _, t := range q.Disconnects {
+		if !t.Before(since) {
+			count++
+		}
+	}
+	return count
+}
+
+// DisconnectedDuration returns how long the node was disconnected between from and to, the part
+// of the window before the kept history is assumed to be in the state of the oldest event.
+func (q Quality) DisconnectedDuration(from, to time.Time) time.Duration {
+	type event struct {
+		at        time.Time
+		connected bool
+	}
+	events := make([]event, 0, len(q.Connects)+len(q.Disconnects))
+	for _, t := range q.Connects {
+		events = append(events, event{at: t, connected: true})
+	}
+	for _, t := range q.Disconnects {
+		events = append(events, event{at: t, connected: false})
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].at.Before(events[j].at)
+	})
+
+	// the state before the first event is the opposite of it, without events it is the current one
+	connected := q.Connected
+	if len(events) != 0 {
+		connected = !events[0].connected
+	}
+	var disconnected time.Duration
+	last := from
+	for _, e := range events {
+		if !e.at.After(last) {
+			connected = e.connected
+			continue
+		}
+		if e.at.After(to) {
+			break
+		}
+		if !connected {
+			disconnected += e.at.Sub(last)
+		}
+		last, connected = e.at, e.connected
+	}
+	if !connected && to.After(last) {
+		disconnected += to.Sub(last)
+	}
+	return disconnected
+}
+
+var (
+	nodes = map[string]*Quality{}
+	lock  sync.RWMutex
+	// lastForget is the last time the nodes disconnected longer than ForgetWindow were dropped
+	lastForget time.Time
+)
+
+// ObserveConnect records that the node connected
+func ObserveConnect(nodeID string) {
+	lock.Lock()
+	defer lock.Unlock()
+	forgetDisconnected(time.Now())
+	q := getOrCreate(nodeID)
+	q.Connected = true
+	q.Connects = append(prune(q.Connects), time.Now())
+}
+
+// ObserveDisconnect records that the node disconnected
+func ObserveDisconnect(nodeID string) {
+	lock.Lock()
+	defer lock.Unlock()
+	forgetDisconnected(time.Now())
+	q := getOrCreate(nodeID)
+	q.Connected = false
+	q.disconnectedAt = time.Now()
+	q.Disconnects = append(prune(q.Disconnects), q.disconnectedAt)
+}
+
+// ObserveRTT records the round trip time of a message acked by the node
+func ObserveRTT(nodeID string, rtt time.Duration) {
+	lock.Lock()
+	defer lock.Unlock()
+	q := getOrCreate(nodeID)
+	if q.RTT == 0 {
+		q.RTT = rtt
+		return
+	}
+	q.RTT = time.Duration(rttWeight*float64(rtt) + (1-rttWeight)*float64(q.RTT))
+}
+
+// GetQuality returns the connection quality of the node
+func GetQuality(nodeID string) Quality {
+	lock.RLock()
+	defer lock.RUnlock()
+	q, ok := nodes[nodeID]
+	if !ok {
+		return Quality{}
+	}
+	return Quality{
+		Known:       true,
+		Connected:   q.Connected,
+		RTT:         q.RTT,
+		Connects:    prune(append([]time.Time(nil), q.Connects...)),
+		Disconnects: prune(append([]time.Time(nil), q.Disconnects...)),
+	}
+}
+
+func getOrCreate(nodeID string) *Quality {
+	q, ok := nodes[nodeID]
+	if !ok {
+		q = &Quality{Known: true}
+		nodes[nodeID] = q
+	}
+	return q
+}
+
+// forgetDisconnected drops the nodes disconnected longer than ForgetWindow, the nodes are checked
+// at most once per HistoryWindow. A dropped node is unknown until it connects again.
+func forgetDisconnected(now time.Time) {
+	if now.Sub(lastForget) < HistoryWindow {
+		return
+	}
+	lastForget = now
+	for nodeID, q := range nodes {
+		if !q.Connected && now.Sub(q.disconnectedAt) > ForgetWindow {
+			delete(nodes, nodeID)
+		}
+	}
+}
+
+func prune(times []time.Time) []time.Time {
+	deadline := time.Now().Add(-HistoryWindow)
+	i := 0
+	for i < len(times) && times[i].Before(deadline) {
+		i++
+	}
+	return times[i:]
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"testing"
+	"time"
+)
+
+func TestForgetDisconnected(t *testing.T) {
+	now := time.Now()
+	lock.Lock()
+	nodes = map[string]*Quality{
+		"connected":    {Known: true, Connected: true},
+		"disconnected": {Known: true, disconnectedAt: now.Add(-time.Hour)},
+		"gone":         {Known: true, disconnectedAt: now.Add(-ForgetWindow - time.Minute)},
+	}
+	lastForget = time.Time{}
+	lock.Unlock()
+
+	ObserveConnect("edge")
+	for _, node := range []string{"connected", "disconnected", "edge"} {
+		if !GetQuality(node).Known {
+			t.Errorf("expected node %s to be kept", node)
+		}
+	}
+	if GetQuality("gone").Known {
+		t.Errorf("expected node gone to be dropped")
+	}
+
+	// the nodes are not checked again within HistoryWindow
+	lock.Lock()
+	nodes["gone"] = &Quality{Known: true, disconnectedAt: now.Add(-ForgetWindow - time.Minute)}
+	lock.Unlock()
+	ObserveDisconnect("edge")
+	if !GetQuality("gone").Known {
+		t.Errorf("expected node gone to be kept until the next check")
+	}
+}
@@ -36,6 +36,8 @@ const (
 
 	// CloudHubSubsystem - subsystem name used by CloudHub
 	CloudHubSubsystem = "CloudHub"
+	// TaskManagerSubsystem - subsystem name used by TaskManager
+	TaskManagerSubsystem = "TaskManager"
 )
 
 var (
@@ -47,6 +49,172 @@ var (
 			Help:      "Number of nodes that connected to the cloudHub instance",
 		},
 	)
+
+	TaskFailedNodes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Subsystem: TaskManagerSubsystem,
+			Name:      "task_failed_nodes",
+			Help:      "Number of failed nodes of the active task",
+		},
+		[]string{"task_type", "task_name"},
+	)
+
+	TaskToleratedFailedNodes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Subsystem: TaskManagerSubsystem,
+			Name:      "task_tolerated_failed_nodes",
+			Help:      "Number of failed nodes that the active task tolerates before it aborts",
+		},
+		[]string{"task_type", "task_name"},
+	)
+
+	TaskNodeTransitions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: TaskManagerSubsystem,
+			Name:      "task_node_transitions_total",
+			Help:      "Number of state transitions of the nodes of tasks",
+		},
+		[]string{"task_type", "state", "action"},
+	)
+
+	TaskNodeDownloadedBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: TaskManagerSubsystem,
+			Name:      "task_node_downloaded_bytes_total",
+			Help:      "Number of bytes downloaded by the edge nodes of finished tasks",
+		},
+		[]string{"task_type", "node_group"},
+	)
+
+	TaskNodeDurationSeconds = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: TaskManagerSubsystem,
+			Name:      "task_node_duration_seconds_total",
+			Help:      "Execution time of the edge nodes of finished tasks",
+		},
+		[]string{"task_type", "node_group"},
+	)
+
+	TaskNodeRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: TaskManagerSubsystem,
+			Name:      "task_node_retries_total",
+			Help:      "Number of dispatch retries of the edge nodes of finished tasks",
+		},
+		[]string{"task_type", "node_group"},
+	)
+
+	TaskActiveExecutors = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Subsystem: TaskManagerSubsystem,
+			Name:      "active_executors",
+			Help:      "Number of tasks being executed",
+		},
+	)
+
+	TaskNodes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Subsystem: TaskManagerSubsystem,
+			Name:      "task_nodes",
+			Help:      "Number of the nodes of the active task which succeeded, failed or are in progress",
+		},
+		[]string{"task_type", "task_name", "phase"},
+	)
+
+	TaskNodeStageDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricNamespace,
+			Subsystem: TaskManagerSubsystem,
+			Name:      "task_node_stage_duration_seconds",
+			Help:      "Duration of the stages of tasks on the edge nodes, from the dispatch to the status report",
+			Buckets:   []float64{1, 5, 15, 30, 60, 120, 300, 600, 1200, 1800, 3600},
+		},
+		[]string{"task_type", "stage"},
+	)
+
+	TaskNodeTimeouts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: TaskManagerSubsystem,
+			Name:      "task_node_timeouts_total",
+			Help:      "Number of the stages of tasks the edge nodes failed by timeout",
+		},
+		[]string{"task_type", "stage"},
+	)
+
+	TaskDownstreamQueueLength = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Subsystem: TaskManagerSubsystem,
+			Name:      "downstream_queue_length",
+			Help:      "Number of task messages waiting to be sent to the edge nodes",
+		},
+	)
+
+	TaskDownstreamHeldMessages = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Subsystem: TaskManagerSubsystem,
+			Name:      "downstream_held_messages",
+			Help:      "Number of task messages held by the rate limits or the full downstream queue",
+		},
+	)
+
+	TaskDownstreamThrottled = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: TaskManagerSubsystem,
+			Name:      "downstream_throttled_total",
+			Help:      "Number of task messages delayed by the rate limits",
+		},
+	)
+
+	TaskDownstreamRetries = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: TaskManagerSubsystem,
+			Name:      "downstream_retries_total",
+			Help:      "Number of times a task message is queued again because the downstream queue is full",
+		},
+	)
+
+	TaskTransitionExportDropped = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: TaskManagerSubsystem,
+			Name:      "transition_export_dropped_total",
+			Help:      "Number of node transitions dropped because the export queue is full or the store failed for too long",
+		},
+	)
+
+	TaskProbeRoundTripSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricNamespace,
+			Subsystem: TaskManagerSubsystem,
+			Name:      "task_probe_round_trip_seconds",
+			Help:      "Round trip of the preflight probes of tasks to the edge nodes over the task channel",
+			Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"task_type"},
+	)
+
+	TaskProbeUnreachableNodes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: TaskManagerSubsystem,
+			Name:      "task_probe_unreachable_nodes_total",
+			Help:      "Number of edge nodes failed because they did not answer the preflight probe of a task",
+		},
+		[]string{"task_type"},
+	)
 )
 
 var registerOnce sync.Once
@@ -56,6 +224,23 @@ func registerMetrics() {
 	registerOnce.Do(func() {
 		prometheus.MustRegister(
 			ConnectedNodes,
+			TaskFailedNodes,
+			TaskToleratedFailedNodes,
+			TaskNodeTransitions,
+			TaskNodeDownloadedBytes,
+			TaskNodeDurationSeconds,
+			TaskNodeRetries,
+			TaskActiveExecutors,
+			TaskNodes,
+			TaskNodeStageDurationSeconds,
+			TaskNodeTimeouts,
+			TaskDownstreamQueueLength,
+			TaskDownstreamHeldMessages,
+			TaskDownstreamThrottled,
+			TaskDownstreamRetries,
+			TaskTransitionExportDropped,
+			TaskProbeRoundTripSeconds,
+			TaskProbeUnreachableNodes,
 		)
 	})
 }
@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupcontroller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/manager"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+)
+
+// BackupController is the controller of BackupJobs
+type BackupController = controller.JobController[*v1alpha1.BackupJob, *v1alpha1.BackupJobList]
+
+// backupJobType is the type of BackupJobs handled by the controller
+var backupJobType = controller.JobType[*v1alpha1.BackupJob, *v1alpha1.BackupJobList]{
+	Name:          util.TaskBackup,
+	Kind:          "BackupJob",
+	Rule:          api.BackupRule,
+	StageSequence: api.BackupStageSequence,
+	Client: func(crdClient crdClientset.Interface) util.TypedTaskClient[*v1alpha1.BackupJob, *v1alpha1.BackupJobList] {
+		return crdClient.OperationsV1alpha1().BackupJobs()
+	},
+	Request:          request,
+	HandleNodeReport: handleNodeReport,
+}
+
+func init() {
+	controller.RegisterPipeline(util.TaskBackup, controller.Pipeline{
+		Stages:      []api.State{api.BackingUpState},
+		Concurrency: 1,
+	})
+	util.RegisterTaskObjects(util.TaskBackup, util.NewTypedTaskObjects(v1alpha1.Resource("backupjobs"),
+		backupJobType.Client,
+		func(meta metav1.ObjectMeta, spec v1alpha1.BackupJobSpec) *v1alpha1.BackupJob {
+			return &v1alpha1.BackupJob{ObjectMeta: meta, Spec: spec}
+		},
+		func(job *v1alpha1.BackupJob) util.TaskObjectStatus {
+			return util.JobObjectStatus(job.Status.CommonJobStatus, job.Status)
+		},
+		func(job *v1alpha1.BackupJob) *int32 {
+			return job.Spec.TTLSecondsAfterFinished
+		}))
+}
+
+func NewBackupController(messageChan chan util.TaskMessage) (*BackupController, error) {
+	cache, err := manager.NewTaskCache(
+		informers.GetInformersManager().GetKubeEdgeInformerFactory().Operations().V1alpha1().BackupJobs().Informer())
+	if err != nil {
+		klog.Warningf("Create backup controller failed with error: %s", err)
+		return nil, err
+	}
+	return NewController(controller.NewBaseController(util.TaskBackup, messageChan, cache,
+		informers.GetInformersManager().GetKubeInformerFactory(), client.GetKubeClient(), client.GetCRDClient())), nil
+}
+
+// NewController returns the controller of BackupJobs with the clients and the task cache of the base
+func NewController(base *controller.BaseController) *BackupController {
+	return controller.NewJobController(backupJobType, base)
+}
+
+// request returns the message requesting the edge nodes to back up their edgecore
+func request(job *v1alpha1.BackupJob) interface{} {
+	return commontypes.BackupJobRequest{Location: job.Spec.Location}
+}
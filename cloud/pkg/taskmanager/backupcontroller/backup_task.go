@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupcontroller
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/klog/v2"
+
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// handleNodeReport records the backup reported by the node in the backups of the job
+func handleNodeReport(job *v1alpha1.BackupJob, nodeStatus *v1alpha1.TaskStatus, event fsm.Event) {
+	if nodeStatus.State != api.TaskSuccessful || event.ExternalMessage == "" {
+		return
+	}
+	var backup v1alpha1.NodeBackup
+	if err := json.Unmarshal([]byte(event.ExternalMessage), &backup); err != nil {
+		klog.Warningf("failed to decode the backup of node %s in BackupJob %s: %v", nodeStatus.NodeName, job.Name, err)
+		nodeStatus.Reason = fmt.Sprintf("failed to decode the backup: %v", err)
+		return
+	}
+	backup.NodeName = nodeStatus.NodeName
+	job.Status.Backups = setBackup(job.Status.Backups, backup)
+}
+
+// setBackup replaces the backup of the same node, or appends it
+func setBackup(backups []v1alpha1.NodeBackup, backup v1alpha1.NodeBackup) []v1alpha1.NodeBackup {
+	for i := range backups {
+		if backups[i].NodeName == backup.NodeName {
+			backups[i] = backup
+			return backups
+		}
+	}
+	return append(backups, backup)
+}
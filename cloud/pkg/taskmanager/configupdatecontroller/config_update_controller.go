@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configupdatecontroller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/manager"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+)
+
+// ConfigUpdateController is the controller of ConfigUpdateJobs
+type ConfigUpdateController = controller.JobController[*v1alpha1.ConfigUpdateJob, *v1alpha1.ConfigUpdateJobList]
+
+// configUpdateJobType is the type of ConfigUpdateJobs handled by the controller
+var configUpdateJobType = controller.JobType[*v1alpha1.ConfigUpdateJob, *v1alpha1.ConfigUpdateJobList]{
+	Name:          util.TaskConfigUpdate,
+	Kind:          "ConfigUpdateJob",
+	Rule:          api.ConfigUpdateRule,
+	StageSequence: api.ConfigUpdateStageSequence,
+	Client: func(crdClient crdClientset.Interface) util.TypedTaskClient[*v1alpha1.ConfigUpdateJob, *v1alpha1.ConfigUpdateJobList] {
+		return crdClient.OperationsV1alpha1().ConfigUpdateJobs()
+	},
+	Request: request,
+	PostCheck: func(job *v1alpha1.ConfigUpdateJob) *v1alpha1.PostCheckSpec {
+		return job.Spec.PostCheck
+	},
+}
+
+func init() {
+	// the nodes apply the config one at a time and are verified from cloud, they succeed once Ready by default
+	controller.RegisterPipeline(util.TaskConfigUpdate, controller.Pipeline{
+		Stages:        []api.State{api.ApplyingState},
+		VerifiesNodes: true,
+		Concurrency:   1,
+		PostCheck:     &v1alpha1.PostCheckSpec{},
+	})
+	util.RegisterTaskObjects(util.TaskConfigUpdate, util.NewTypedTaskObjects(v1alpha1.Resource("configupdatejobs"),
+		configUpdateJobType.Client,
+		func(meta metav1.ObjectMeta, spec v1alpha1.ConfigUpdateJobSpec) *v1alpha1.ConfigUpdateJob {
+			return &v1alpha1.ConfigUpdateJob{ObjectMeta: meta, Spec: spec}
+		},
+		func(job *v1alpha1.ConfigUpdateJob) util.TaskObjectStatus {
+			return util.JobObjectStatus(job.Status.CommonJobStatus, job.Status)
+		},
+		func(job *v1alpha1.ConfigUpdateJob) *int32 {
+			return job.Spec.TTLSecondsAfterFinished
+		}))
+}
+
+func NewConfigUpdateController(messageChan chan util.TaskMessage) (*ConfigUpdateController, error) {
+	cache, err := manager.NewTaskCache(
+		informers.GetInformersManager().GetKubeEdgeInformerFactory().Operations().V1alpha1().ConfigUpdateJobs().Informer())
+	if err != nil {
+		klog.Warningf("Create config update controller failed with error: %s", err)
+		return nil, err
+	}
+	return NewController(controller.NewBaseController(util.TaskConfigUpdate, messageChan, cache,
+		informers.GetInformersManager().GetKubeInformerFactory(), client.GetKubeClient(), client.GetCRDClient())), nil
+}
+
+// NewController returns the controller of ConfigUpdateJobs with the clients and the task cache of the base
+func NewController(base *controller.BaseController) *ConfigUpdateController {
+	return controller.NewJobController(configUpdateJobType, base)
+}
+
+// request returns the message requesting the edge nodes to apply the config patch, each node is
+// verified from cloud once it applied the patch, with the post check of the job or by waiting for
+// the node to be Ready
+func request(job *v1alpha1.ConfigUpdateJob) interface{} {
+	return commontypes.ConfigUpdateJobRequest{Patch: job.Spec.Patch.Raw}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnosecontroller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/manager"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+)
+
+// DiagnoseController is the controller of DiagnoseJobs
+type DiagnoseController = controller.JobController[*v1alpha1.DiagnoseJob, *v1alpha1.DiagnoseJobList]
+
+// diagnoseJobType is the type of DiagnoseJobs handled by the controller
+var diagnoseJobType = controller.JobType[*v1alpha1.DiagnoseJob, *v1alpha1.DiagnoseJobList]{
+	Name:          util.TaskDiagnose,
+	Kind:          "DiagnoseJob",
+	Rule:          api.DiagnoseRule,
+	StageSequence: api.DiagnoseStageSequence,
+	Client: func(crdClient crdClientset.Interface) util.TypedTaskClient[*v1alpha1.DiagnoseJob, *v1alpha1.DiagnoseJobList] {
+		return crdClient.OperationsV1alpha1().DiagnoseJobs()
+	},
+	Request:          request,
+	HandleNodeReport: handleNodeReport,
+	HandleFinish:     handleFinish,
+}
+
+func init() {
+	controller.RegisterPipeline(util.TaskDiagnose, controller.Pipeline{
+		Stages:      []api.State{api.DiagnosingState},
+		Concurrency: 1,
+	})
+	util.RegisterTaskObjects(util.TaskDiagnose, util.NewTypedTaskObjects(v1alpha1.Resource("diagnosejobs"),
+		diagnoseJobType.Client,
+		func(meta metav1.ObjectMeta, spec v1alpha1.DiagnoseJobSpec) *v1alpha1.DiagnoseJob {
+			return &v1alpha1.DiagnoseJob{ObjectMeta: meta, Spec: spec}
+		},
+		func(job *v1alpha1.DiagnoseJob) util.TaskObjectStatus {
+			return util.JobObjectStatus(job.Status.CommonJobStatus, job.Status)
+		},
+		func(job *v1alpha1.DiagnoseJob) *int32 {
+			return job.Spec.TTLSecondsAfterFinished
+		}))
+}
+
+func NewDiagnoseController(messageChan chan util.TaskMessage) (*DiagnoseController, error) {
+	cache, err := manager.NewTaskCache(
+		informers.GetInformersManager().GetKubeEdgeInformerFactory().Operations().V1alpha1().DiagnoseJobs().Informer())
+	if err != nil {
+		klog.Warningf("Create diagnose controller failed with error: %s", err)
+		return nil, err
+	}
+	return NewController(controller.NewBaseController(util.TaskDiagnose, messageChan, cache,
+		informers.GetInformersManager().GetKubeInformerFactory(), client.GetKubeClient(), client.GetCRDClient())), nil
+}
+
+// NewController returns the controller of DiagnoseJobs with the clients and the task cache of the base
+func NewController(base *controller.BaseController) *DiagnoseController {
+	return controller.NewJobController(diagnoseJobType, base)
+}
+
+// request returns the message requesting the edge nodes to run the checks of the job and report
+// their findings
+func request(job *v1alpha1.DiagnoseJob) interface{} {
+	checks := make([]string, 0, len(job.Spec.Checks))
+	for _, check := range job.Spec.Checks {
+		checks = append(checks, string(check))
+	}
+	return commontypes.DiagnoseJobRequest{Checks: checks}
+}
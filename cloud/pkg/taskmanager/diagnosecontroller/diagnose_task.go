@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnosecontroller
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// handleNodeReport records the findings reported by the node in the diagnoses of the job
+func handleNodeReport(job *v1alpha1.DiagnoseJob, nodeStatus *v1alpha1.TaskStatus, event fsm.Event) {
+	if nodeStatus.State != api.TaskSuccessful || event.ExternalMessage == "" {
+		return
+	}
+	var findings []v1alpha1.DiagnoseFinding
+	if err := json.Unmarshal([]byte(event.ExternalMessage), &findings); err != nil {
+		klog.Warningf("failed to decode the findings of node %s in DiagnoseJob %s: %v", nodeStatus.NodeName, job.Name, err)
+		nodeStatus.Reason = fmt.Sprintf("failed to decode the findings: %v", err)
+		return
+	}
+	job.Status.Diagnoses = setDiagnosis(job.Status.Diagnoses, newNodeDiagnosis(nodeStatus.NodeName, findings))
+}
+
+// handleFinish lists the unhealthy nodes in the outputs of the job
+func handleFinish(job *v1alpha1.DiagnoseJob, status *v1alpha1.CommonJobStatus) {
+	status.Outputs["unhealthyNodes"] = unhealthyNodes(job.Status.Diagnoses)
+}
+
+// newNodeDiagnosis returns the diagnosis of the node, the node is healthy if none of the checks failed
+func newNodeDiagnosis(nodeName string, findings []v1alpha1.DiagnoseFinding) v1alpha1.NodeDiagnosis {
+	diagnosis := v1alpha1.NodeDiagnosis{NodeName: nodeName, Healthy: true, Findings: findings}
+	for _, finding := range findings {
+		if finding.Result == v1alpha1.DiagnoseFailed {
+			diagnosis.Healthy = false
+		}
+	}
+	return diagnosis
+}
+
+// setDiagnosis replaces the diagnosis of the same node, or appends it
+func setDiagnosis(diagnoses []v1alpha1.NodeDiagnosis, diagnosis v1alpha1.NodeDiagnosis) []v1alpha1.NodeDiagnosis {
+	for i := range diagnoses {
+		if diagnoses[i].NodeName == diagnosis.NodeName {
+			diagnoses[i] = diagnosis
+			return diagnoses
+		}
+	}
+	return append(diagnoses, diagnosis)
+}
+
+// unhealthyNodes returns the comma separated names of the nodes which failed any check
+func unhealthyNodes(diagnoses []v1alpha1.NodeDiagnosis) string {
+	var nodes []string
+	for _, diagnosis := range diagnoses {
+		if !diagnosis.Healthy {
+			nodes = append(nodes, diagnosis.NodeName)
+		}
+	}
+	sort.Strings(nodes)
+	return strings.Join(nodes, ",")
+}
@@ -21,7 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"strconv"
+	"reflect"
 	"sync"
 	"time"
 
@@ -53,6 +53,32 @@ type ImagePrePullController struct {
 
 var cache *manager.TaskCache
 
+func init() {
+	controller.RegisterPipeline(util.TaskPrePull, controller.Pipeline{
+		Stages:      []api.State{api.TaskChecking, api.PullingState},
+		Concurrency: 1,
+	})
+	util.RegisterTaskObjects(util.TaskPrePull, util.NewTypedTaskObjects(v1alpha1.Resource("imageprepulljobs"),
+		func(crdClient crdClientset.Interface) util.TypedTaskClient[*v1alpha1.ImagePrePullJob, *v1alpha1.ImagePrePullJobList] {
+			return crdClient.OperationsV1alpha1().ImagePrePullJobs()
+		},
+		func(meta metav1.ObjectMeta, spec v1alpha1.ImagePrePullJobSpec) *v1alpha1.ImagePrePullJob {
+			return &v1alpha1.ImagePrePullJob{ObjectMeta: meta, Spec: spec}
+		},
+		func(job *v1alpha1.ImagePrePullJob) util.TaskObjectStatus {
+			status := util.TaskObjectStatus{State: job.Status.State, Reason: job.Status.Reason, Time: job.Status.Time, Status: job.Status}
+			for _, node := range job.Status.Status {
+				if node.TaskStatus != nil {
+					status.NodeStatus = append(status.NodeStatus, *node.TaskStatus)
+				}
+			}
+			return status
+		},
+		func(job *v1alpha1.ImagePrePullJob) *int32 {
+			return job.Spec.ImagePrePullTemplate.TTLSecondsAfterFinished
+		}))
+}
+
 func NewImagePrePullController(messageChan chan util.TaskMessage) (*ImagePrePullController, error) {
 	var err error
 	cache, err = manager.NewTaskCache(
@@ -135,6 +161,10 @@ func (ndc *ImagePrePullController) StageCompleted(taskID string, state api.State
 	return taskFSM.TaskStagCompleted(state)
 }
 
+func (ndc *ImagePrePullController) KnownState(taskID string, state api.State) bool {
+	return NewImagePrePullTaskFSM(taskID).KnownState(state)
+}
+
 func (ndc *ImagePrePullController) GetNodeStatus(name string) ([]v1alpha1.TaskStatus, error) {
 	imagePrePull, err := ndc.CrdClient.OperationsV1alpha1().ImagePrePullJobs().Get(context.TODO(), name, metav1.GetOptions{})
 	if err != nil {
@@ -157,9 +187,17 @@ func (ndc *ImagePrePullController) UpdateNodeStatus(name string, nodeStatus []v1
 		return err
 	}
 	status := imagePrePull.Status
+	// keep the image status reported by the nodes
+	imageStatus := make(map[string][]v1alpha1.ImageStatus, len(status.Status))
+	for _, s := range status.Status {
+		if s.TaskStatus != nil {
+			imageStatus[s.TaskStatus.NodeName] = s.ImageStatus
+		}
+	}
 	statusList := make([]v1alpha1.ImagePrePullStatus, len(nodeStatus))
 	for i := 0; i < len(nodeStatus); i++ {
 		statusList[i].TaskStatus = &nodeStatus[i]
+		statusList[i].ImageStatus = imageStatus[nodeStatus[i].NodeName]
 	}
 	status.Status = statusList
 	err = patchStatus(imagePrePull, status, ndc.CrdClient)
@@ -169,6 +207,31 @@ func (ndc *ImagePrePullController) UpdateNodeStatus(name string, nodeStatus []v1
 	return nil
 }
 
+// RecordExcludedNodes records the nodes selected by the ImagePrePullJob which do not pull the images
+func (ndc *ImagePrePullController) RecordExcludedNodes(name string, excluded []v1alpha1.ExcludedNode) error {
+	imagePrePull, err := ndc.CrdClient.OperationsV1alpha1().ImagePrePullJobs().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	status := imagePrePull.Status
+	status.ExcludedNodes = excluded
+	return patchStatus(imagePrePull, status, ndc.CrdClient)
+}
+
+// RecordFailureThreshold records the number of failed nodes the ImagePrePullJob fails at
+func (ndc *ImagePrePullController) RecordFailureThreshold(name string, threshold int32) error {
+	imagePrePull, err := ndc.CrdClient.OperationsV1alpha1().ImagePrePullJobs().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if imagePrePull.Status.FailureThreshold == threshold {
+		return nil
+	}
+	status := imagePrePull.Status
+	status.FailureThreshold = threshold
+	return patchStatus(imagePrePull, status, ndc.CrdClient)
+}
+
 func patchStatus(imagePrePullJob *v1alpha1.ImagePrePullJob, status v1alpha1.ImagePrePullJobStatus, crdClient crdClientset.Interface) error {
 	oldData, err := json.Marshal(imagePrePullJob)
 	if err != nil {
@@ -198,6 +261,21 @@ func (ndc *ImagePrePullController) Start() error {
 	return nil
 }
 
+// Resync sends the unfinished ImagePrePullJobs to the executors again, they are handled as if they were added
+func (ndc *ImagePrePullController) Resync() error {
+	jobs, err := ndc.CrdClient.OperationsV1alpha1().ImagePrePullJobs().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range jobs.Items {
+		if fsm.TaskFinish(jobs.Items[i].Status.State) {
+			continue
+		}
+		ndc.TaskManager.Events() <- watch.Event{Type: watch.Added, Object: &jobs.Items[i]}
+	}
+	return nil
+}
+
 func (ndc *ImagePrePullController) startSync() {
 	imagePrePullList, err := ndc.CrdClient.OperationsV1alpha1().ImagePrePullJobs().List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
@@ -243,11 +321,33 @@ func (ndc *ImagePrePullController) imagePrePullJobAdded(imagePrePull *v1alpha1.I
 
 	// If all or partial edge nodes image pull is pulling or completed, we don't need to send pull message
 	if fsm.TaskFinish(imagePrePull.Status.State) {
+		if util.RerunRequested(imagePrePull.Status.State, imagePrePull.Spec.ImagePrePullTemplate.Rerun, imagePrePull.Status.ObservedRerun) {
+			ndc.rerunPrePull(imagePrePull)
+			return
+		}
 		klog.Warning("The ImagePrePullJob is completed, don't send pull message again")
 		return
 	}
 
-	ndc.processPrePull(imagePrePull)
+	resolved := ndc.resolveInputs(imagePrePull)
+	if resolved == nil {
+		return
+	}
+	ndc.processPrePull(resolved)
+	if util.IsCancelRequested(imagePrePull) {
+		ndc.cancelPrePull(imagePrePull)
+	}
+}
+
+// cancelPrePull requests the executor of the ImagePrePullJob to cancel it
+func (ndc *ImagePrePullController) cancelPrePull(imagePrePull *v1alpha1.ImagePrePullJob) {
+	klog.Infof("cancel ImagePrePullJob %s", imagePrePull.Name)
+	ndc.MessageChan <- util.TaskMessage{
+		Type:   util.TaskPrePull,
+		Name:   imagePrePull.Name,
+		Cancel: true,
+		Drain:  util.IsGracefulCancel(imagePrePull),
+	}
 }
 
 // processPrePull do the pre pull operation on node
@@ -259,28 +359,46 @@ func (ndc *ImagePrePullController) processPrePull(imagePrePull *v1alpha1.ImagePr
 		RetryTimes: imagePrePullTemplateInfo.RetryTimes,
 		CheckItems: imagePrePullTemplateInfo.CheckItems,
 	}
-	tolerate, err := strconv.ParseFloat(imagePrePull.Spec.ImagePrePullTemplate.FailureTolerate, 64)
-	if err != nil {
-		klog.Errorf("convert FailureTolerate to float64 failed: %v", err)
-		tolerate = 0.1
-	}
 
-	concurrency := imagePrePull.Spec.ImagePrePullTemplate.Concurrency
-	if concurrency <= 0 {
-		concurrency = 1
-	}
 	klog.V(4).Infof("deal task message: %v", imagePrePull)
 	ndc.MessageChan <- util.TaskMessage{
-		Type:            util.TaskPrePull,
-		CheckItem:       imagePrePull.Spec.ImagePrePullTemplate.CheckItems,
-		Name:            imagePrePull.Name,
-		TimeOutSeconds:  imagePrePull.Spec.ImagePrePullTemplate.TimeoutSeconds,
-		Concurrency:     concurrency,
-		FailureTolerate: tolerate,
-		NodeNames:       imagePrePull.Spec.ImagePrePullTemplate.NodeNames,
-		LabelSelector:   imagePrePull.Spec.ImagePrePullTemplate.LabelSelector,
-		Status:          v1alpha1.TaskStatus{},
-		Msg:             imagePrePullRequest,
+		Type:                  util.TaskPrePull,
+		CheckItem:             imagePrePull.Spec.ImagePrePullTemplate.CheckItems,
+		Name:                  imagePrePull.Name,
+		TimeOutSeconds:        imagePrePull.Spec.ImagePrePullTemplate.TimeoutSeconds,
+		Concurrency:           imagePrePull.Spec.ImagePrePullTemplate.Concurrency,
+		DispatchJitterSeconds: imagePrePull.Spec.ImagePrePullTemplate.DispatchJitterSeconds,
+		Preflight:             imagePrePull.Spec.ImagePrePullTemplate.Preflight,
+		ActiveDeadline:        util.ActiveDeadline(imagePrePull.Spec.ImagePrePullTemplate.ActiveDeadlineSeconds),
+		FailureTolerate:       imagePrePull.Spec.ImagePrePullTemplate.FailureTolerate,
+		NodeNames:             imagePrePull.Spec.ImagePrePullTemplate.NodeNames,
+		LabelSelector:         imagePrePull.Spec.ImagePrePullTemplate.LabelSelector,
+		Status:                v1alpha1.TaskStatus{},
+		Msg:                   imagePrePullRequest,
+		Labels:                imagePrePull.Labels,
+		Metadata:              imagePrePull.Spec.ImagePrePullTemplate.Metadata,
+		RenderPayload:         imagePrePull.Spec.ImagePrePullTemplate.RenderPayload,
+		Notifications:         imagePrePull.Spec.ImagePrePullTemplate.Notifications,
+		Credential:            imagePrePull.Spec.ImagePrePullTemplate.Credential,
+		Owner:                 util.NewTaskOwnerReference(imagePrePull, "ImagePrePullJob"),
+	}
+}
+
+// rerunPrePull resets the status of the finished ImagePrePullJob and runs it again with its current spec
+func (ndc *ImagePrePullController) rerunPrePull(imagePrePull *v1alpha1.ImagePrePullJob) {
+	klog.Infof("rerun ImagePrePullJob %s, rerun %d", imagePrePull.Name, imagePrePull.Spec.ImagePrePullTemplate.Rerun)
+	rerun := imagePrePull.DeepCopy()
+	status := v1alpha1.ImagePrePullJobStatus{
+		ObservedRerun: imagePrePull.Spec.ImagePrePullTemplate.Rerun,
+		StartTime:     time.Now().UTC().Format(util.ISO8601UTC),
+	}
+	if err := patchStatus(rerun, status, ndc.CrdClient); err != nil {
+		klog.Errorf("failed to reset the status of ImagePrePullJob %s to rerun it: %v", imagePrePull.Name, err)
+		return
+	}
+	ndc.TaskManager.CacheMap.Store(imagePrePull.Name, rerun)
+	if resolved := ndc.resolveInputs(rerun); resolved != nil {
+		ndc.processPrePull(resolved)
 	}
 }
 
@@ -288,6 +406,7 @@ func (ndc *ImagePrePullController) processPrePull(imagePrePull *v1alpha1.ImagePr
 func (ndc *ImagePrePullController) imagePrePullJobDeleted(imagePrePull *v1alpha1.ImagePrePullJob) {
 	// just need to delete from cache map
 	ndc.TaskManager.CacheMap.Delete(imagePrePull.Name)
+	util.StopWaitingForInputs("ImagePrePullJob", imagePrePull.Name)
 	klog.Errorf("image pre pull job %s delete", imagePrePull.Name)
 	ndc.MessageChan <- util.TaskMessage{
 		Type:     util.TaskPrePull,
@@ -309,6 +428,42 @@ func (ndc *ImagePrePullController) imagePrePullJobUpdated(pullJob *v1alpha1.Imag
 
 	// store in cache map
 	ndc.TaskManager.CacheMap.Store(pullJob.Name, pullJob)
+	if util.RerunRequested(pullJob.Status.State, pullJob.Spec.ImagePrePullTemplate.Rerun, pullJob.Status.ObservedRerun) {
+		ndc.rerunPrePull(pullJob)
+		return
+	}
+	if fsm.TaskFinish(pullJob.Status.State) && !reflect.DeepEqual(old.Spec, pullJob.Spec) {
+		klog.Infof("ImagePrePullJob %s is finished, the change of its spec is ignored until it is rerun", pullJob.Name)
+	}
+
+	if util.CancelRequested(old, pullJob) && !fsm.TaskFinish(pullJob.Status.State) {
+		ndc.cancelPrePull(pullJob)
+		return
+	}
+	oldTemplate, template := old.Spec.ImagePrePullTemplate, pullJob.Spec.ImagePrePullTemplate
+	if (oldTemplate.Concurrency != template.Concurrency || !reflect.DeepEqual(oldTemplate.FailureTolerate, template.FailureTolerate)) &&
+		!fsm.TaskFinish(pullJob.Status.State) {
+		klog.Infof("tune ImagePrePullJob %s", pullJob.Name)
+		ndc.MessageChan <- util.TaskMessage{
+			Type:            util.TaskPrePull,
+			Name:            pullJob.Name,
+			Concurrency:     template.Concurrency,
+			FailureTolerate: template.FailureTolerate,
+			SetTuning:       true,
+		}
+		return
+	}
+	if force, err := util.ForceCompletionRequested(old, pullJob); err != nil {
+		klog.Errorf("ImagePrePullJob %s: %v", pullJob.Name, err)
+	} else if force != nil && !fsm.TaskFinish(pullJob.Status.State) {
+		klog.Infof("force node %s of ImagePrePullJob %s to %s", force.NodeName, pullJob.Name, force.State)
+		ndc.MessageChan <- util.TaskMessage{
+			Type:          util.TaskPrePull,
+			Name:          pullJob.Name,
+			ForceComplete: force,
+		}
+		return
+	}
 
 	node := checkUpdateNode(old, pullJob)
 	if node == nil {
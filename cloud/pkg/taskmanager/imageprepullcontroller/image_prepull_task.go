@@ -24,7 +24,9 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/config"
 	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	cloudcorev1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
 	fsmapi "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
 	v1alpha12 "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
 	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
@@ -58,31 +60,51 @@ func updatePrePullNodeState(id, nodeName string, state v1alpha12.State, event fs
 	task := v.(*v1alpha1.ImagePrePullJob)
 	newTask := task.DeepCopy()
 	status := newTask.Status.DeepCopy()
-	for i, nodeStatus := range status.Status {
-		if nodeStatus.NodeName == nodeName {
+	nodeStatus := v1alpha1.TaskStatus{
+		NodeName: nodeName,
+		State:    state,
+		Event:    event.Type,
+		Action:   event.Action,
+		Time:     time.Now().Format(util.ISO8601UTC),
+		Reason:   event.Msg,
+
+		CommandDigest: event.CommandDigest,
+	}
+	if fsm.TaskFinish(state) {
+		nodeStatus.Environment = event.Environment
+		nodeStatus.Metadata = event.Metadata
+	}
+	persisted := nodeStatus
+	util.PruneNodeStatus(&persisted, config.Config.PrunedNodeStatusFields)
+	costs := make([]*v1alpha1.NodeCost, 0, len(status.Status))
+	for i := range status.Status {
+		if status.Status[i].NodeName == nodeName {
 			var imagesStatus []v1alpha1.ImageStatus
-			err := json.Unmarshal([]byte(event.ExternalMessage), &imagesStatus)
-			if err != nil {
-				klog.Warningf("Failed to unmarshal images status: %v", err)
+			if !util.IsNodeStatusFieldPruned(state, cloudcorev1alpha1.NodeStatusFieldImageStatus, config.Config.PrunedNodeStatusFields) {
+				if err := json.Unmarshal([]byte(event.ExternalMessage), &imagesStatus); err != nil {
+					klog.Warningf("Failed to unmarshal images status: %v", err)
+				}
 			}
+			nodeStatus.Cost = util.AccountNodeCost(id, nodeName, util.NodeGroup(nodeName), status.Status[i].Cost, event)
+			persisted.Cost = nodeStatus.Cost
+			util.KeepCommandDigest(&persisted, status.Status[i].TaskStatus)
+			util.RecordNodeHistory(&persisted, status.Status[i].TaskStatus, config.Config.TransitionHistory)
 			status.Status[i] = v1alpha1.ImagePrePullStatus{
-				TaskStatus: &v1alpha1.TaskStatus{
-					NodeName: nodeName,
-					State:    state,
-					Event:    event.Type,
-					Action:   event.Action,
-					Time:     time.Now().Format(util.ISO8601UTC),
-					Reason:   event.Msg,
-				},
+				TaskStatus:  &persisted,
 				ImageStatus: imagesStatus,
 			}
-			break
 		}
+		costs = append(costs, status.Status[i].Cost)
 	}
+	status.Cost = util.AggregateCost(costs)
 	err := patchStatus(newTask, *status, client.GetCRDClient())
 	if err != nil {
 		return err
 	}
+	util.RecordNodeTransition(task, "ImagePrePullJob", util.TaskPrePull, nodeStatus)
+	if fsm.TaskFinish(state) {
+		util.RecordNodeCost(util.TaskPrePull, nodeStatus.Cost)
+	}
 	return nil
 }
 
@@ -123,11 +145,20 @@ func updateUpgradeTaskState(id, _ string, state v1alpha12.State, event fsm.Event
 	status.Reason = event.Msg
 	status.State = state
 	status.Time = time.Now().Format(util.ISO8601UTC)
+	if fsm.TaskFinish(state) {
+		status.Outputs = prePullOutputs(newTask, *status)
+	}
 
 	err := patchStatus(newTask, *status, client.GetCRDClient())
 
 	if err != nil {
 		return err
 	}
+	if fsm.TaskFinish(state) && !fsm.TaskFinish(task.Status.State) {
+		go util.LabelNodeCohorts(task.Name, newTask.Spec.ImagePrePullTemplate.Cohorts, status.Inputs, prePullNodeStatus(*status))
+		util.NotifyTaskFinished(newTask, "ImagePrePullJob", newTask.Spec.ImagePrePullTemplate.Notifications, state, event.Msg, prePullNodeStatus(*status))
+	}
+	util.RecordTaskTransition(task, "ImagePrePullJob", task.Status.State, state, event)
+	util.NotifyTaskState(util.TaskPrePull, task.Name, state, event.Msg)
 	return nil
 }
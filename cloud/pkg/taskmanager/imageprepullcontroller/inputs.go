@@ -0,0 +1,135 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageprepullcontroller
+
+import (
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// resolveInputs returns the ImagePrePullJob with the references to its inputs substituted, the inputs
+// are resolved once and recorded in its status. It returns nil if the job waits for the jobs its inputs
+// reference, or if the job failed because its inputs cannot be resolved.
+func (ndc *ImagePrePullController) resolveInputs(imagePrePull *v1alpha1.ImagePrePullJob) *v1alpha1.ImagePrePullJob {
+	if len(imagePrePull.Spec.ImagePrePullTemplate.Inputs) == 0 {
+		return imagePrePull
+	}
+	values := imagePrePull.Status.Inputs
+	if values == nil {
+		var pending string
+		var err error
+		values, pending, err = util.ResolveInputs(imagePrePull.Spec.ImagePrePullTemplate.Inputs)
+		if err != nil {
+			ndc.finishUnresolved(imagePrePull, api.TaskFailed, err.Error())
+			return nil
+		}
+		if pending != "" {
+			ndc.waitForInputs(imagePrePull, pending)
+			return nil
+		}
+	}
+	util.StopWaitingForInputs("ImagePrePullJob", imagePrePull.Name)
+
+	resolved := imagePrePull.DeepCopy()
+	if err := util.SubstituteInputs(&resolved.Spec, values); err != nil {
+		ndc.finishUnresolved(imagePrePull, api.TaskFailed, err.Error())
+		return nil
+	}
+	if imagePrePull.Status.Inputs == nil {
+		status := imagePrePull.Status
+		status.Inputs = values
+		if err := patchStatus(imagePrePull.DeepCopy(), status, ndc.CrdClient); err != nil {
+			klog.Errorf("failed to record the inputs of ImagePrePullJob %s: %v", imagePrePull.Name, err)
+			return nil
+		}
+		resolved.Status.Inputs = values
+		klog.Infof("ImagePrePullJob %s runs with inputs %v", imagePrePull.Name, values)
+	}
+	return resolved
+}
+
+// waitForInputs checks the inputs of the ImagePrePullJob again later, the job waiting for its inputs
+// is cancelled at once if it is requested
+func (ndc *ImagePrePullController) waitForInputs(imagePrePull *v1alpha1.ImagePrePullJob, reason string) {
+	if util.IsCancelRequested(imagePrePull) {
+		ndc.finishUnresolved(imagePrePull, api.TaskCancelled, "the job is cancelled while waiting for its inputs: "+reason)
+		return
+	}
+	name := imagePrePull.Name
+	klog.Infof("ImagePrePullJob %s waits for its inputs: %s", name, reason)
+	retry := func() {
+		value, ok := ndc.TaskManager.CacheMap.Load(name)
+		if !ok {
+			return
+		}
+		ndc.TaskManager.Events() <- watch.Event{Type: watch.Added, Object: value.(*v1alpha1.ImagePrePullJob)}
+	}
+	if util.WaitForInputs("ImagePrePullJob", name, retry) {
+		util.RecordWaitingForInputs(imagePrePull, "ImagePrePullJob", reason)
+	}
+}
+
+// finishUnresolved finishes the ImagePrePullJob whose inputs are not resolved before it started
+func (ndc *ImagePrePullController) finishUnresolved(imagePrePull *v1alpha1.ImagePrePullJob, state api.State, reason string) {
+	util.StopWaitingForInputs("ImagePrePullJob", imagePrePull.Name)
+	klog.Warningf("ImagePrePullJob %s is %s: %s", imagePrePull.Name, state, reason)
+	event := fsm.Event{Type: "Inputs", Action: api.ActionFailure, Msg: reason}
+	status := imagePrePull.Status
+	status.State = state
+	status.Event = event.Type
+	status.Action = event.Action
+	status.Reason = reason
+	status.Time = time.Now().Format(util.ISO8601UTC)
+	status.Outputs = prePullOutputs(imagePrePull, status)
+	if err := patchStatus(imagePrePull.DeepCopy(), status, ndc.CrdClient); err != nil {
+		klog.Errorf("failed to finish ImagePrePullJob %s: %v", imagePrePull.Name, err)
+		return
+	}
+	util.RecordTaskTransition(imagePrePull, "ImagePrePullJob", imagePrePull.Status.State, state, event)
+	util.NotifyTaskState(util.TaskPrePull, imagePrePull.Name, state, reason)
+	util.NotifyTaskFinished(imagePrePull, "ImagePrePullJob", imagePrePull.Spec.ImagePrePullTemplate.Notifications, state, reason,
+		prePullNodeStatus(status))
+}
+
+// prePullOutputs returns the outputs of the finished ImagePrePullJob
+func prePullOutputs(imagePrePull *v1alpha1.ImagePrePullJob, status v1alpha1.ImagePrePullJobStatus) map[string]string {
+	outputs := util.NodeOutputs(prePullNodeStatus(status))
+	resolved := imagePrePull.DeepCopy()
+	if err := util.SubstituteInputs(&resolved.Spec, status.Inputs); err == nil {
+		outputs[util.OutputImages] = strings.Join(resolved.Spec.ImagePrePullTemplate.Images, ",")
+	}
+	return outputs
+}
+
+// prePullNodeStatus returns the task status of each node of the ImagePrePullJob
+func prePullNodeStatus(status v1alpha1.ImagePrePullJobStatus) []v1alpha1.TaskStatus {
+	nodes := make([]v1alpha1.TaskStatus, 0, len(status.Status))
+	for _, node := range status.Status {
+		if node.TaskStatus != nil {
+			nodes = append(nodes, *node.TaskStatus)
+		}
+	}
+	return nodes
+}
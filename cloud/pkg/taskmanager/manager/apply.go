@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// ApplyExecutor passes the updated apply spec of the task to its running executor
+func ApplyExecutor(msg util.TaskMessage) {
+	executorMachine.Lock()
+	e, ok := executorMachine.executors[fmt.Sprintf("%s::%s", msg.Type, msg.Name)]
+	executorMachine.Unlock()
+	if !ok {
+		klog.Warningf("task %s to apply is not running", msg.Name)
+		return
+	}
+	select {
+	case e.applyChan <- msg.Apply:
+	default:
+		klog.Warningf("failed to update how task %s is applied, the executor is busy", msg.Name)
+	}
+}
+
+// isApplyState returns whether the node in the state is applied the upgrade, i.e. EdgeCore is
+// backed up, upgraded and restarted
+func isApplyState(state api.State) bool {
+	return state == api.BackingUpState || state == api.UpgradingState
+}
+
+// applyHeld returns whether the node has to wait before it is applied the staged upgrade. It waits
+// as long as the upgrade is held or out of the maintenance windows, the dispatch is resumed when the
+// next window opens.
+func (e *Executor) applyHeld(node v1alpha1.TaskStatus) bool {
+	if e.apply == nil || !isApplyState(node.State) {
+		return false
+	}
+	if e.apply.Hold {
+		klog.V(4).Infof("task %s is held, stop applying it at node %s", e.task.Name, node.NodeName)
+		return true
+	}
+	open, next := util.InMaintenanceWindow(e.apply.MaintenanceWindows, time.Now())
+	if open {
+		return false
+	}
+	if !e.windowOpens.Equal(next) {
+		e.stopWindowTimer()
+		e.windowOpens = next
+		e.windowTimer = time.NewTimer(time.Until(next))
+		remaining := e.remainingNodes()
+		klog.Infof("task %s waits for the maintenance window opening at %s to be applied to %d nodes", e.task.Name, next.Format(util.ISO8601UTC), remaining)
+		util.RecordTaskEvent(e.task, v1.EventTypeNormal, "WaitingForMaintenanceWindow",
+			"The nodes are staged, the upgrade is applied to the %d remaining nodes in the maintenance window opening at %s", remaining, next.Format(util.ISO8601UTC))
+		e.recordMaintenanceWindow(&v1alpha1.MaintenanceWindowStatus{
+			PausedUntil:    next.Format(util.ISO8601UTC),
+			RemainingNodes: remaining,
+		})
+	}
+	return true
+}
+
+// remainingNodes returns the number of nodes of the task which have not finished
+func (e *Executor) remainingNodes() int32 {
+	var remaining int32
+	for _, node := range e.nodes {
+		if !fsm.TaskFinish(node.State) {
+			remaining++
+		}
+	}
+	return remaining
+}
+
+// recordMaintenanceWindow records the maintenance window the task is paused until in its status,
+// the record is removed if window is nil. The task resumes from its node status after a restart.
+func (e *Executor) recordMaintenanceWindow(window *v1alpha1.MaintenanceWindowStatus) {
+	if err := e.controller.RecordMaintenanceWindow(e.task.Name, window); err != nil {
+		klog.Errorf("failed to record the maintenance window of task %s: %v", e.task.Name, err)
+	}
+}
+
+// windowOpened returns the channel notified once the maintenance window the task waits for opens
+func (e *Executor) windowOpened() <-chan time.Time {
+	if e.windowTimer == nil {
+		return nil
+	}
+	return e.windowTimer.C
+}
+
+func (e *Executor) stopWindowTimer() {
+	if e.windowTimer != nil {
+		e.windowTimer.Stop()
+		e.windowTimer = nil
+	}
+	e.windowOpens = time.Time{}
+}
+
+// setApply updates how the task is applied, it returns true if the dispatch has to continue
+func (e *Executor) setApply(apply *v1alpha1.ApplySpec) bool {
+	wasHeld := e.apply != nil && e.apply.Hold
+	e.apply = apply
+	if !e.windowOpens.IsZero() {
+		e.recordMaintenanceWindow(nil)
+	}
+	e.stopWindowTimer()
+	if apply != nil && apply.Hold {
+		if !wasHeld {
+			klog.Infof("hold task %s, the staged nodes are not applied", e.task.Name)
+			util.RecordTaskEvent(e.task, v1.EventTypeNormal, "Held", "The upgrade is held, the nodes are staged only")
+		}
+		return false
+	}
+	if wasHeld {
+		klog.Infof("release task %s, the staged nodes are applied", e.task.Name)
+		util.RecordTaskEvent(e.task, v1.EventTypeNormal, "Released", "The upgrade is released, the staged nodes are applied")
+	}
+	return true
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// ApproveExecutor passes the approval of the canary of the task to its running executor
+func ApproveExecutor(msg util.TaskMessage) {
+	executorMachine.Lock()
+	e, ok := executorMachine.executors[fmt.Sprintf("%s::%s", msg.Type, msg.Name)]
+	executorMachine.Unlock()
+	if !ok {
+		klog.Warningf("task %s to approve is not running", msg.Name)
+		return
+	}
+	select {
+	case e.approveChan <- msg.Canary != nil && msg.Canary.Approve:
+	default:
+		klog.Warningf("failed to approve task %s, the executor is busy", msg.Name)
+	}
+}
+
+// initCanary selects the canary nodes of the task and moves them to the front, so that they are
+// upgraded before the task waits for approval.
+func (e *Executor) initCanary() {
+	if e.task.Canary == nil {
+		return
+	}
+	e.approved = e.task.Canary.Approve
+	lister := informers.GetInformersManager().GetKubeInformerFactory().Core().V1().Nodes().Lister()
+	nodes := make([]v1.Node, 0, len(e.nodes))
+	for _, status := range e.nodes {
+		node, err := lister.Get(status.NodeName)
+		if err != nil {
+			klog.Warningf("failed to get node %s of task %s: %v", status.NodeName, e.task.Name, err)
+			continue
+		}
+		nodes = append(nodes, *node)
+	}
+	names, err := util.CanaryNodes(e.task.Canary, nodes)
+	if err != nil {
+		klog.Errorf("failed to select the canary nodes of task %s: %v", e.task.Name, err)
+	}
+	e.setCanary(names)
+}
+
+// setCanary sets the canary nodes of the task and moves them to the front
+func (e *Executor) setCanary(names []string) {
+	e.canary = make(map[string]bool, len(names))
+	for _, name := range names {
+		e.canary[name] = true
+	}
+	util.CanaryFirst(e.nodes, e.canary)
+	if len(names) == 0 {
+		klog.Warningf("task %s selects no canary node, it waits for approval before upgrading any node", e.task.Name)
+		util.RecordTaskEvent(e.task, v1.EventTypeWarning, "NoCanaryNodes", "No canary node is selected, the task waits for approval before upgrading any node")
+		return
+	}
+	klog.Infof("task %s upgrades the canary nodes %v first", e.task.Name, names)
+}
+
+// approvalRequired returns whether the node waits for the approval of the task before it is upgraded
+func (e *Executor) approvalRequired(node v1alpha1.TaskStatus) bool {
+	return e.canary != nil && !e.approved && node.State == api.UpgradingState && !e.canary[node.NodeName]
+}
+
+// waitApproval moves the task to WaitingApproval once its canary nodes are upgraded
+func (e *Executor) waitApproval() {
+	if e.waitingApproval {
+		return
+	}
+	e.waitingApproval = true
+	var upgraded, failed int
+	for _, node := range e.nodes {
+		if !e.canary[node.NodeName] {
+			continue
+		}
+		switch node.State {
+		case api.TaskSuccessful:
+			upgraded++
+		case api.TaskFailed:
+			failed++
+		}
+	}
+	msg := fmt.Sprintf("%d canary nodes are upgraded, %d failed, approve the task to upgrade the other nodes", upgraded, failed)
+	if _, err := e.controller.ReportTaskStatus(e.task.Name, fsm.Event{
+		Type:   api.EventCanaryUpgraded,
+		Action: api.ActionSuccess,
+		Msg:    msg,
+	}); err != nil {
+		klog.Warningf("failed to report task %s waiting for approval: %v", e.task.Name, err)
+	}
+	klog.Infof("task %s waits for approval: %s", e.task.Name, msg)
+	util.RecordTaskEvent(e.task, v1.EventTypeNormal, "WaitingApproval", "%s", msg)
+}
+
+// approve approves the task or withdraws its approval, it returns true if the dispatch has to continue
+func (e *Executor) approve(approved bool) bool {
+	if e.canary == nil || e.approved == approved {
+		return false
+	}
+	e.approved = approved
+	if !approved {
+		klog.Infof("the approval of task %s is withdrawn", e.task.Name)
+		util.RecordTaskEvent(e.task, v1.EventTypeNormal, "ApprovalWithdrawn", "The approval is withdrawn, no other node is upgraded")
+		return false
+	}
+	klog.Infof("task %s is approved, upgrade the other nodes", e.task.Name)
+	util.RecordTaskEvent(e.task, v1.EventTypeNormal, "Approved", "The task is approved, the other nodes are upgraded")
+	if e.waitingApproval {
+		e.waitingApproval = false
+		e.reportApproval()
+	}
+	return true
+}
+
+// reportApproval moves the task waiting for approval back to Upgrading
+func (e *Executor) reportApproval() {
+	if _, err := e.controller.ReportTaskStatus(e.task.Name, fsm.Event{
+		Type:   api.EventApprove,
+		Action: api.ActionSuccess,
+		Msg:    "the task is approved",
+	}); err != nil {
+		klog.V(4).Infof("task %s is not waiting for approval: %v", e.task.Name, err)
+	}
+}
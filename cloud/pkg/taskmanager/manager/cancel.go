@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+var cancelEvent = fsm.Event{
+	Type:   api.EventCancel,
+	Action: api.ActionSuccess,
+	Msg:    "the task is cancelled",
+}
+
+var cancelIdleEvent = fsm.Event{
+	Type:   api.EventCancelIdle,
+	Action: api.ActionSuccess,
+	Msg:    "the task is cancelled",
+}
+
+// cancel stops dispatching the task and moves the task and its unfinished nodes to Cancelling.
+// The nodes running the task are asked to cancel it and confirm, or they finish it if the running
+// nodes are drained. The other nodes are cancelled right away. It returns true if the cancellation
+// is already finished.
+func (e *Executor) cancel(drain bool) bool {
+	if e.cancelled {
+		return false
+	}
+	klog.Infof("cancel task %s, drain running nodes: %t", e.task.Name, drain)
+	util.RecordTaskEvent(e.task, v1.EventTypeNormal, "Cancelling", "The task is being cancelled")
+	if _, err := e.controller.ReportTaskStatus(e.task.Name, cancelEvent); err != nil {
+		klog.Warningf("failed to report cancelling of task %s: %v", e.task.Name, err)
+	}
+	return e.stopNodes(drain)
+}
+
+// stopNodes stops dispatching the task and cancels its unfinished nodes, the task is finished
+// once no node is running it. It returns true if the task is already finished.
+func (e *Executor) stopNodes(drain bool) bool {
+	e.workers.Lock()
+	e.cancelled = true
+	e.drain = drain
+	e.workers.shuttingDown = true
+	e.workers.Unlock()
+	for _, node := range e.nodes {
+		if fsm.TaskFinish(node.State) {
+			continue
+		}
+		e.workers.Lock()
+		_, running := e.workers.jobs[node.NodeName]
+		e.workers.Unlock()
+		if !running {
+			e.cancelNode(node.NodeName)
+			continue
+		}
+		if drain {
+			continue
+		}
+		if _, err := e.controller.ReportNodeStatus(e.task.Name, node.NodeName, cancelEvent); err != nil {
+			klog.Warningf("failed to cancel node %s of task %s: %v", node.NodeName, e.task.Name, err)
+			continue
+		}
+		e.dispatchCancel(node.NodeName)
+	}
+
+	if e.workers.running() != 0 {
+		return false
+	}
+	e.finishCancel()
+	return true
+}
+
+// cancelNode moves the node which is not running the task to Cancelled at once, there is nothing
+// on the node whose cancellation must be confirmed.
+func (e *Executor) cancelNode(nodeName string) {
+	if _, err := e.controller.ReportNodeStatus(e.task.Name, nodeName, cancelIdleEvent); err != nil {
+		klog.Warningf("failed to cancel node %s of task %s: %v", nodeName, e.task.Name, err)
+	}
+}
+
+// dispatchCancel asks the edge node to cancel the task, the node confirms with the Cancel event
+func (e *Executor) dispatchCancel(nodeName string) {
+	msg := model.NewMessage("")
+	resource := buildTaskResource(e.task.Type, e.task.Name, nodeName)
+	msg.BuildRouter(modules.TaskManagerModuleName, modules.TaskManagerModuleGroup, resource, e.task.Type).
+		FillBody(commontypes.NodeTaskRequest{
+			TaskID: e.task.Name,
+			Type:   e.task.Type,
+			State:  string(api.TaskCancelling),
+		})
+	executorMachine.dispatch(nodeName, e.task.Name, *msg)
+}
+
+// finishCancel moves the task to Cancelled once no node is running it, or to DeadlineExceeded
+// if the nodes were cancelled because the task exceeded its deadline.
+func (e *Executor) finishCancel() {
+	if e.expired {
+		e.finishExpired()
+		return
+	}
+	if _, err := e.controller.ReportTaskStatus(e.task.Name, cancelEvent); err != nil {
+		klog.Warningf("failed to report cancellation of task %s: %v", e.task.Name, err)
+	}
+	util.RecordTaskEvent(e.task, v1.EventTypeNormal, "Cancelled", "The task is cancelled")
+	DeleteExecutor(e.task)
+	klog.Infof("task %s is cancelled", e.task.Name)
+}
+
+// StopExecutor stops the running executor of the deleted task, the nodes running it are no longer
+// waited for nor dispatched again.
+func StopExecutor(msg util.TaskMessage) {
+	executorMachine.Lock()
+	e, ok := executorMachine.executors[fmt.Sprintf("%s::%s", msg.Type, msg.Name)]
+	executorMachine.Unlock()
+	if !ok {
+		return
+	}
+	e.stop()
+}
+
+// stop closes the stop channel of the executor, its goroutines give up the task
+func (e *Executor) stop() {
+	e.workers.shutDown(true)
+	e.stopOnce.Do(func() {
+		close(e.stopChan)
+	})
+}
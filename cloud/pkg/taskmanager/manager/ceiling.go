@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"errors"
+	"sync"
+
+	"k8s.io/klog/v2"
+)
+
+// errCeilingReached is returned when the nodes in flight across all the tasks reach the ceiling
+var errCeilingReached = errors.New("the nodes in flight across the tasks reach the ceiling")
+
+// ceiling bounds the nodes in flight across all the tasks. The tasks waiting for a free slot take
+// it in turn, a task taking a slot waits behind the others for the next one.
+type ceiling struct {
+	sync.Mutex
+	running int
+	// tasks counts the nodes in flight of each executor
+	tasks map[*Executor]int
+	// waiting are the executors waiting for a free slot in order
+	waiting []*Executor
+}
+
+var inFlight = &ceiling{tasks: map[*Executor]int{}}
+
+// acquire takes a slot for a node of the executor, limit 0 means no ceiling. The executor waits in
+// turn if no slot is free or other executors are waiting before it.
+func (c *ceiling) acquire(e *Executor, limit int) bool {
+	if limit <= 0 {
+		return true
+	}
+	c.Lock()
+	defer c.Unlock()
+	if c.running >= limit || (len(c.waiting) != 0 && c.waiting[0] != e) {
+		if c.indexOf(e) < 0 {
+			klog.V(2).Infof("task %s waits for a free slot, %d nodes are in flight", e.task.Name, c.running)
+			c.waiting = append(c.waiting, e)
+		}
+		return false
+	}
+	if len(c.waiting) != 0 {
+		c.waiting = c.waiting[1:]
+	}
+	c.running++
+	c.tasks[e]++
+	if c.running < limit {
+		c.notify()
+	}
+	return true
+}
+
+// occupy takes a slot for a node of the executor regardless of the ceiling, the node was admitted before
+func (c *ceiling) occupy(e *Executor) {
+	c.Lock()
+	defer c.Unlock()
+	c.running++
+	c.tasks[e]++
+}
+
+// release frees the slot of a node of the executor which is no longer in flight
+func (c *ceiling) release(e *Executor) {
+	c.Lock()
+	defer c.Unlock()
+	if c.tasks[e] == 0 {
+		return
+	}
+	c.tasks[e]--
+	if c.tasks[e] == 0 {
+		delete(c.tasks, e)
+	}
+	c.running--
+	c.notify()
+}
+
+// leave stops the executor waiting for a free slot
+func (c *ceiling) leave(e *Executor) {
+	c.Lock()
+	defer c.Unlock()
+	c.remove(e)
+}
+
+// forget frees the slots of the executor which stopped and stops it waiting
+func (c *ceiling) forget(e *Executor) {
+	c.Lock()
+	defer c.Unlock()
+	c.running -= c.tasks[e]
+	delete(c.tasks, e)
+	c.remove(e)
+	c.notify()
+}
+
+func (c *ceiling) remove(e *Executor) {
+	i := c.indexOf(e)
+	if i < 0 {
+		return
+	}
+	c.waiting = append(c.waiting[:i], c.waiting[i+1:]...)
+	if i == 0 {
+		c.notify()
+	}
+}
+
+func (c *ceiling) indexOf(e *Executor) int {
+	for i, waiting := range c.waiting {
+		if waiting == e {
+			return i
+		}
+	}
+	return -1
+}
+
+// notify wakes up the executor whose turn it is to take a free slot
+func (c *ceiling) notify() {
+	if len(c.waiting) == 0 {
+		return
+	}
+	select {
+	case c.waiting[0].slotChan <- struct{}{}:
+	default:
+	}
+}
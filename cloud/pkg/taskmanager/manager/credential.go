@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/config"
+	"github.com/kubeedge/kubeedge/common/constants"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/envelope"
+)
+
+const defaultCredentialExpirationSeconds = 600
+
+// mintCredential mints a token of the service account of the spec for the node, the token is sealed
+// for the node so that only the node can read it. Only the tokens of the service accounts allowed by
+// CredentialServiceAccounts are minted, TaskManager must not lend its own rights to the tasks.
+func mintCredential(taskName, nodeName string, spec v1alpha1.TaskCredentialSpec) (*envelope.Envelope, error) {
+	namespace := credentialNamespace(spec)
+	if !credentialAllowed(namespace, spec.ServiceAccountName) {
+		return nil, fmt.Errorf("service account %s/%s is not allowed to mint credentials of tasks", namespace, spec.ServiceAccountName)
+	}
+	token, err := client.GetKubeClient().CoreV1().ServiceAccounts(namespace).CreateToken(context.TODO(),
+		spec.ServiceAccountName, tokenRequest(spec), metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to mint token of service account %s/%s: %v", namespace, spec.ServiceAccountName, err)
+	}
+	klog.V(4).Infof("minted token of service account %s/%s for node %s of task %s, it expires at %s",
+		namespace, spec.ServiceAccountName, nodeName, taskName, token.Status.ExpirationTimestamp)
+	return sealItem(nodeName, commontypes.TaskCredential{
+		Token:               token.Status.Token,
+		ExpirationTimestamp: token.Status.ExpirationTimestamp,
+	})
+}
+
+func credentialNamespace(spec v1alpha1.TaskCredentialSpec) string {
+	if spec.Namespace == "" {
+		return constants.DefaultTaskCredentialNamespace
+	}
+	return spec.Namespace
+}
+
+// credentialAllowed returns whether the tokens of the service account can be minted for the tasks
+func credentialAllowed(namespace, name string) bool {
+	for _, allowed := range config.Config.CredentialServiceAccounts {
+		if allowed == namespace+"/"+name {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenRequest returns the request of the token of the spec
+func tokenRequest(spec v1alpha1.TaskCredentialSpec) *authenticationv1.TokenRequest {
+	expiration := int64(defaultCredentialExpirationSeconds)
+	if spec.ExpirationSeconds != nil {
+		expiration = *spec.ExpirationSeconds
+	}
+	return &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences:         spec.Audiences,
+			ExpirationSeconds: &expiration,
+		},
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// redispatchMessage returns a copy of the undelivered task message with a new ID,
+// the edge node is asked to replay its result in case it has already executed the task.
+func redispatchMessage(msg model.Message) model.Message {
+	retry := msg
+	retry.Header.ID = uuid.New().String()
+	retry.Header.Timestamp = time.Now().UnixNano() / 1e6
+	switch req := msg.GetContent().(type) {
+	case commontypes.NodeTaskRequest:
+		req.Reconcile = true
+		retry.FillBody(req)
+	case map[string]interface{}:
+		// the content of a dead letter loaded from the store is decoded as a map
+		content := make(map[string]interface{}, len(req)+1)
+		for k, v := range req {
+			content[k] = v
+		}
+		content["Reconcile"] = true
+		retry.FillBody(content)
+	}
+	return retry
+}
+
+// purgeRecords removes the records of the deleted task which are not garbage-collected with it
+func purgeRecords(taskType, taskName string) {
+	if err := util.GetRecordStore().Purge(taskType, taskName); err != nil {
+		klog.Warningf("failed to purge records of task %s: %v", taskName, err)
+	}
+}
+
+// saveDeadLetter persists the task message which got no response from the edge node
+func (e *Executor) saveDeadLetter(node v1alpha1.TaskStatus, msg model.Message, attempts int, cause error) {
+	err := util.SaveDeadLetter(e.task, util.DeadLetter{
+		NodeName: node.NodeName,
+		State:    string(node.State),
+		Reason:   fmt.Sprintf("no response from edge node after %d dispatches, %v", attempts, cause),
+		Time:     time.Now().UTC().Format(util.ISO8601UTC),
+		Message:  msg,
+	})
+	if err != nil {
+		klog.Errorf("failed to save dead letter of task %s for node %s: %v", e.task.Name, node.NodeName, err)
+	}
+}
+
+// redrivenNode is a node moved back to the state of its dead letter, the letter is dispatched to it again
+type redrivenNode struct {
+	node v1alpha1.TaskStatus
+	msg  model.Message
+}
+
+// ErrTaskNotRunning is returned by Redrive for the tasks which are finished or not started, a finished
+// task is rerun to dispatch its nodes again
+var ErrTaskNotRunning = errors.New("the task is not running, rerun it to dispatch its nodes again")
+
+// redriveRequeueInterval is how often a redriven node checks whether the executor of its task still runs
+// while the executor is too busy to take it
+var redriveRequeueInterval = time.Second
+
+// Redrive dispatches the dead letter of the task to the edge node again. The node failed by the timeout
+// of the letter is moved back to the state it timed out in first, so that the result the edge node
+// reports is accepted by the FSM. The running executor of the task waits for the node again, the
+// letters of the tasks which are not running on this replica are not redriven.
+func (em *ExecutorMachine) Redrive(taskType, taskID string, letter util.DeadLetter) error {
+	if !em.leading.Load() {
+		return util.ErrNotLeader
+	}
+	key := fmt.Sprintf("%s::%s", taskType, taskID)
+	em.Lock()
+	e, running := em.executors[key]
+	em.Unlock()
+	if !running {
+		return ErrTaskNotRunning
+	}
+	node, err := resetNode(e.controller, taskID, letter)
+	if err != nil {
+		return err
+	}
+	klog.Infof("redrive message of task %s to node %s in state %s", taskID, letter.NodeName, node.State)
+	redriven := redrivenNode{node: node, msg: redispatchMessage(letter.Message)}
+	select {
+	case e.redriveChan <- redriven:
+	default:
+		klog.Warningf("the executor of task %s is busy, node %s is redriven once it is taken", taskID, letter.NodeName)
+		go em.requeueRedrive(key, e, redriven)
+	}
+	return nil
+}
+
+// requeueRedrive hands the redriven node to the busy executor once it takes it. The node is given up
+// if the executor stops or finishes meanwhile.
+func (em *ExecutorMachine) requeueRedrive(key string, e *Executor, redriven redrivenNode) {
+	ticker := time.NewTicker(redriveRequeueInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case e.redriveChan <- redriven:
+			return
+		case <-e.stopChan:
+			return
+		case <-ticker.C:
+			em.Lock()
+			running := em.executors[key] == e
+			em.Unlock()
+			if !running {
+				klog.Warningf("task %s is no longer running, node %s is not redriven", e.task.Name, redriven.node.NodeName)
+				return
+			}
+		}
+	}
+}
+
+// resetNode moves the node of the dead letter back to the state it timed out in, it fails unless the
+// node is failed by the timeout
+func resetNode(c controller.Controller, taskID string, letter util.DeadLetter) (v1alpha1.TaskStatus, error) {
+	nodeStatus, err := c.GetNodeStatus(taskID)
+	if err != nil {
+		return v1alpha1.TaskStatus{}, fmt.Errorf("failed to get node status of task %s: %v", taskID, err)
+	}
+	for i := range nodeStatus {
+		if nodeStatus[i].NodeName != letter.NodeName {
+			continue
+		}
+		if nodeStatus[i].Event != api.EventTimeOut || !fsm.TaskFinish(nodeStatus[i].State) {
+			return v1alpha1.TaskStatus{}, fmt.Errorf("node %s of task %s is %s, only the nodes failed by timeout are redriven",
+				letter.NodeName, taskID, nodeStatus[i].State)
+		}
+		nodeStatus[i].State = api.State(letter.State)
+		nodeStatus[i].Event, nodeStatus[i].Action = "", ""
+		nodeStatus[i].Reason = "the dead letter is redriven"
+		nodeStatus[i].Time = time.Now().UTC().Format(util.ISO8601UTC)
+		if err = c.UpdateNodeStatus(taskID, nodeStatus); err != nil {
+			return v1alpha1.TaskStatus{}, fmt.Errorf("failed to reset node %s of task %s: %v", letter.NodeName, taskID, err)
+		}
+		return nodeStatus[i], nil
+	}
+	return v1alpha1.TaskStatus{}, fmt.Errorf("node %s is not part of task %s", letter.NodeName, taskID)
+}
+
+// redrive waits for the redriven node again and dispatches its letter. The node runs beyond the
+// concurrency of the task, it was admitted before it timed out.
+func (e *Executor) redrive(redriven redrivenNode) {
+	nodeName := redriven.node.NodeName
+	index := e.nodeIndex(nodeName)
+	if index < 0 || e.cancelled {
+		executorMachine.dispatch(nodeName, e.task.Name, redriven.msg)
+		return
+	}
+	e.nodes[index] = redriven.node
+	delete(e.failedNodes, nodeName)
+	if holder, err := util.LockNode(nodeName, e.task.Type, e.task.Name); err != nil || holder != "" {
+		klog.Warningf("node %s of task %s is redriven without its lock, held by %q: %v", nodeName, e.task.Name, holder, err)
+	}
+	e.workers.Lock()
+	e.workers.jobs[nodeName] = index
+	e.workers.Unlock()
+	inFlight.occupy(e)
+	e.receipts.reset(nodeName)
+	msg := redriven.msg
+	go e.handelTimeOutJob(index, &msg)
+	executorMachine.dispatch(nodeName, e.task.Name, msg)
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+var deadlineEvent = fsm.Event{
+	Type:   api.EventDeadlineExceeded,
+	Action: api.ActionFailure,
+	Msg:    "the task did not finish before its deadline",
+}
+
+// expire stops the task which exceeded its deadline. The nodes running the task are asked to cancel
+// it and the other unfinished nodes are cancelled right away, the task is finished DeadlineExceeded
+// once no node is running it. A task which is already cancelled finishes Cancelled. It returns true
+// if the task is already finished.
+func (e *Executor) expire() bool {
+	if e.cancelled {
+		return false
+	}
+	e.expired = true
+	klog.Infof("task %s exceeded its active deadline %s", e.task.Name, e.task.ActiveDeadline)
+	util.RecordTaskEvent(e.task, v1.EventTypeWarning, "DeadlineExceeded",
+		"The task did not finish within its active deadline %s, the unfinished nodes are cancelled", e.task.ActiveDeadline)
+	return e.stopNodes(false)
+}
+
+// finishExpired moves the task which exceeded its deadline to DeadlineExceeded
+func (e *Executor) finishExpired() {
+	if _, err := e.controller.ReportTaskStatus(e.task.Name, deadlineEvent); err != nil {
+		klog.Warningf("failed to report the deadline exceeded of task %s: %v", e.task.Name, err)
+	}
+	DeleteExecutor(e.task)
+	klog.Infof("task %s is finished, it exceeded its deadline", e.task.Name)
+}
@@ -22,6 +22,7 @@ import (
 	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
 	"github.com/kubeedge/beehive/pkg/core/model"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/messagelayer"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
 )
 
 type DownstreamController struct {
@@ -46,10 +47,10 @@ func (dc *DownstreamController) syncTask() {
 			klog.Info("stop sync tasks")
 			return
 		case msg := <-dc.downStreamChan:
+			monitor.TaskDownstreamQueueLength.Set(float64(len(dc.downStreamChan)))
 			err := dc.messageLayer.Send(msg)
 			if err != nil {
 				klog.Errorf("Failed to send upgrade message %v due to error %v", msg.GetID(), err)
-				return
 			}
 		}
 	}
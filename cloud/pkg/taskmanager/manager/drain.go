@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// drainRequired returns whether the node is drained before the job is dispatched to it, the nodes
+// are drained right before they are upgraded
+func (e *Executor) drainRequired(node v1alpha1.TaskStatus) bool {
+	return e.task.DrainNodeBeforeUpgrade != nil && node.State == api.UpgradingState
+}
+
+// drainAndDispatch drains the node in the background and hands the job back to the executor loop
+// to be dispatched once its pods are evicted, the node fails to be upgraded if it can not be drained.
+func (e *Executor) drainAndDispatch(index int, msg *model.Message, err error) {
+	nodeName := e.nodes[index].NodeName
+	go func() {
+		klog.Infof("drain node %s before task %s upgrades it", nodeName, e.task.Name)
+		drainErr := util.DrainNode(client.GetKubeClient(), nodeName, e.task.Name, e.task.DrainNodeBeforeUpgrade)
+		if drainErr == nil {
+			util.RecordTaskEvent(e.task, v1.EventTypeNormal, "NodeDrained", "Node %s is drained before it is upgraded", nodeName)
+			e.deferDispatch(deferredJob{index: index, msg: msg, err: err})
+			return
+		}
+		klog.Errorf("failed to drain node %s of task %s: %v", nodeName, e.task.Name, drainErr)
+		util.RecordTaskEvent(e.task, v1.EventTypeWarning, "DrainFailed", "Failed to drain node %s: %v", nodeName, drainErr)
+		if _, err := e.controller.ReportNodeStatus(e.task.Name, nodeName, fsm.Event{
+			Type:   "Upgrade",
+			Action: api.ActionFailure,
+			Msg:    fmt.Sprintf("failed to drain the node before upgrading it: %v", drainErr),
+		}); err != nil {
+			klog.Warningf("failed to report node %s of task %s failed to drain: %v", nodeName, e.task.Name, err)
+		}
+	}()
+}
+
+// uncordon makes the node drained by the task schedulable again once it is upgraded
+func (e *Executor) uncordon(nodeName string) {
+	go func() {
+		if err := util.UncordonNode(client.GetKubeClient(), nodeName, e.task.Name); err != nil {
+			klog.Warningf("failed to uncordon node %s of task %s: %v", nodeName, e.task.Name, err)
+			util.RecordTaskEvent(e.task, v1.EventTypeWarning, "UncordonFailed", "Failed to uncordon node %s: %v", nodeName, err)
+		}
+	}()
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// recordPlan records how the task which is a dry run would operate on its nodes
+func (e *Executor) recordPlan() {
+	plan := v1alpha1.ExecutionPlan{
+		Nodes:      make([]string, 0, len(e.nodes)),
+		BatchSize:  int32(e.workers.number),
+		CheckItems: e.task.CheckItem,
+	}
+	for _, node := range e.nodes {
+		plan.Nodes = append(plan.Nodes, node.NodeName)
+		if e.canary[node.NodeName] {
+			plan.CanaryNodes = append(plan.CanaryNodes, node.NodeName)
+		}
+	}
+	if err := e.controller.RecordPlan(e.task.Name, plan); err != nil {
+		klog.Warningf("failed to record the plan of task %s: %v", e.task.Name, err)
+	}
+}
+
+// checked returns whether the nodes of the task completed the Checking stage, the nodes which
+// passed the checks are BackingUp then, unless the dry run already finished them.
+func (e *Executor) checked() bool {
+	for _, node := range e.nodes {
+		if node.State == api.BackingUpState || node.Event == api.EventDryRun {
+			return true
+		}
+	}
+	return false
+}
+
+// finishDryRun finishes the task once its nodes are checked, the nodes which passed the checks
+// succeed without being operated on.
+func (e *Executor) finishDryRun() {
+	var passed int
+	for i, node := range e.nodes {
+		if node.Event == api.EventDryRun {
+			passed++
+			continue
+		}
+		if node.State != api.BackingUpState {
+			continue
+		}
+		// keep the verdict the node reported along with its checks
+		msg := "the checks passed, the node is not operated on by the dry run"
+		if node.Reason != "" {
+			msg = fmt.Sprintf("%s: %s", msg, node.Reason)
+		}
+		state, err := e.controller.ReportNodeStatus(e.task.Name, node.NodeName, fsm.Event{
+			Type:   api.EventDryRun,
+			Action: api.ActionSuccess,
+			Msg:    msg,
+		})
+		if err != nil {
+			klog.Errorf("failed to finish the dry run of task %s on node %s: %v", e.task.Name, node.NodeName, err)
+			continue
+		}
+		e.nodes[i].State, e.nodes[i].Event = state, api.EventDryRun
+		passed++
+		if err = util.UnmarkNodeInProgress(node.NodeName, e.task.Type, e.task.Name); err != nil {
+			klog.Warningf("failed to unmark node %s in progress of task %s: %v", node.NodeName, e.task.Name, err)
+		}
+	}
+
+	action := api.ActionSuccess
+	if len(e.failedNodes) != 0 {
+		action = api.ActionPartialSuccess
+	}
+	msg := fmt.Sprintf("%d of %d nodes passed the checks", passed, len(e.nodes))
+	if _, err := e.controller.ReportTaskStatus(e.task.Name, fsm.Event{
+		Type:   api.EventDryRun,
+		Action: action,
+		Msg:    msg,
+	}); err != nil {
+		klog.Errorf("failed to report the dry run of task %s: %v", e.task.Name, err)
+	}
+	util.RecordTaskEvent(e.task, v1.EventTypeNormal, "DryRunCompleted", "The dry run is completed, %s", msg)
+	DeleteExecutor(e.task)
+	klog.Infof("the dry run of task %s is finished, %s", e.task.Name, msg)
+}
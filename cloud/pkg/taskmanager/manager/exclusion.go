@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// recordExcludedNodes records why the nodes selected by the task are excluded, the task fails
+// with the reasons if no node is left to operate on.
+func recordExcludedNodes(c controller.Controller, message util.TaskMessage, selected int, excluded []v1alpha1.ExcludedNode) error {
+	summary := util.ExclusionSummary(excluded)
+	if len(excluded) != 0 {
+		recorded := excluded
+		if len(recorded) > util.MaxExcludedNodes {
+			recorded = recorded[:util.MaxExcludedNodes]
+		}
+		if err := c.RecordExcludedNodes(message.Name, recorded); err != nil {
+			klog.Warningf("failed to record the excluded nodes of task %s: %v", message.Name, err)
+		}
+	}
+	if selected != 0 {
+		if len(excluded) != 0 {
+			klog.Infof("task %s excludes %d nodes: %s", message.Name, len(excluded), summary)
+			util.RecordTaskEvent(message, v1.EventTypeWarning, "NodesExcluded",
+				"%d selected nodes are excluded: %s", len(excluded), summary)
+		}
+		return nil
+	}
+
+	if len(excluded) == 0 {
+		summary = "no node matches the node names, the label selector or the node groups"
+	}
+	msg := fmt.Sprintf("no node is selected: %s", summary)
+	util.RecordTaskEvent(message, v1.EventTypeWarning, "NoNodeSelected", "%s", msg)
+	if _, err := c.ReportTaskStatus(message.Name, fsm.Event{
+		Type:   string(api.TaskInit),
+		Action: api.ActionFailure,
+		Msg:    msg,
+	}); err != nil {
+		klog.Warningf("failed to report task %s failure: %v", message.Name, err)
+	}
+	return fmt.Errorf("task %s: %s", message.Name, msg)
+}
@@ -17,13 +17,20 @@ limitations under the License.
 package manager
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
@@ -31,14 +38,20 @@ import (
 	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
 	"github.com/kubeedge/beehive/pkg/core/model"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/connection"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/config"
 	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/nodeupgradecontroller"
 	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
 	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
 	"github.com/kubeedge/kubeedge/common/constants"
 	commontypes "github.com/kubeedge/kubeedge/common/types"
+	cloudcorev1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
 	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
 	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/envelope"
 	"github.com/kubeedge/kubeedge/pkg/util/fsm"
 )
 
@@ -52,6 +65,91 @@ type Executor struct {
 	maxFailedNodes float64
 	failedNodes    map[string]bool
 	workers        workers
+	// reconcileNodes are nodes loaded from an existing status whose result may
+	// have been lost while cloudcore was down, the edge is asked to replay it.
+	reconcileNodes map[string]bool
+	// warnedThresholds are the failure budget warning thresholds already reached
+	warnedThresholds map[int32]bool
+	// toleranceExceeded is set once the failed nodes exceeded the failure tolerance and the sinks of
+	// the task are notified
+	toleranceExceeded bool
+	// receipts are the task states accepted by the edge nodes
+	receipts *receipts
+	// cancelChan receives the cancellation request of the task, true drains the running nodes
+	cancelChan chan bool
+	cancelled  bool
+	// drain lets the nodes running the cancelled task finish it
+	drain bool
+	// stopChan is closed when the task object is deleted or the executor is restarted, the executor gives up the task
+	stopChan chan struct{}
+	stopOnce sync.Once
+	// progress is when the executor last dispatched a node or handled the status of one, in monotonic nanoseconds,
+	// busy is whether it had nodes in flight then and crashed is set if it panicked, they are read by the watchdog
+	progress atomic.Int64
+	busy     atomic.Bool
+	crashed  atomic.Bool
+	// removedChan receives the names of the nodes deleted from the cluster
+	removedChan chan string
+	// forceChan receives the nodes an admin forces to complete
+	forceChan chan *util.ForceCompletion
+	// redriveChan receives the nodes moved back to the state of their dead letters, with the letters
+	redriveChan chan redrivenNode
+	// forced are the nodes forced to complete, their status updates are not handled
+	forced map[string]bool
+	// pauseChan receives the pause and resume requests of the task
+	pauseChan chan bool
+	// paused stops dispatching new nodes
+	paused bool
+	// alertChan receives the firing external alerts the task pauses on
+	alertChan chan string
+	// pauseOnBaseline is the number of failed nodes when the task started or was last resumed, the
+	// outcomes are the nodes which finished since, within the window of the PauseOn failure rate
+	pauseOnBaseline int
+	outcomes        []nodeOutcome
+	// queue estimates when the nodes waiting for dispatch start
+	queue queue
+	// expired is set once the task exceeded its deadline, its nodes are cancelled
+	expired bool
+	// revertReason is set once the task exceeded its failure tolerance and the nodes it upgraded
+	// are to be rolled back, reverting is set once they are being rolled back
+	revertReason string
+	reverting    bool
+	// applyChan receives the updates of how the task is applied
+	applyChan chan *v1alpha1.ApplySpec
+	// apply gates the dispatch of the nodes to the disruptive stages of the task
+	apply *v1alpha1.ApplySpec
+	// windowTimer fires when the maintenance window the task waits for to be applied opens at windowOpens
+	windowTimer *time.Timer
+	windowOpens time.Time
+	// canary are the nodes upgraded before the task waits for approval, it is nil if the task has no canary
+	canary map[string]bool
+	// approveChan receives the approval of the task and its withdrawal
+	approveChan chan bool
+	// approved lets the nodes which are not canary be upgraded
+	approved bool
+	// waitingApproval is set once the canary nodes are upgraded and the task waits for approval
+	waitingApproval bool
+	// membersStale is set if the task is paused before any node is dispatched, the members of its
+	// node groups are resolved again before the first node is dispatched
+	membersStale bool
+	// slotChan is notified when it is the turn of the task to take a free slot below the ceiling
+	// of the nodes in flight across the tasks
+	slotChan chan struct{}
+	// tuneChan receives the concurrency and the failure tolerance updated while the task runs, it holds
+	// the latest tuning only
+	tuneChan chan *util.TaskMessage
+	// probeChan receives the names of the nodes which answered the preflight probe
+	probeChan chan string
+	// unreachable are the nodes failed by the preflight probe, their status updates are not handled
+	unreachable map[string]bool
+	// requeueChan is notified when the nodes locked by other tasks are to be dispatched again
+	requeueChan chan struct{}
+	// dispatchChan receives the deferred jobs to be dispatched by the executor loop
+	dispatchChan chan deferredJob
+	// lockTimer notifies requeueChan, it is set while the locked nodes wait
+	lockTimer *time.Timer
+	// verifying are the nodes whose post check is started
+	verifying map[string]bool
 }
 
 func NewExecutorMachine(messageChan chan util.TaskMessage, downStreamChan chan model.Message) (*ExecutorMachine, error) {
@@ -60,6 +158,22 @@ func NewExecutorMachine(messageChan chan util.TaskMessage, downStreamChan chan m
 		executors:      map[string]*Executor{},
 		messageChan:    messageChan,
 		downStreamChan: downStreamChan,
+		pending:        &pendingTasks{tasks: map[string][]pendingTask{}},
+	}
+	executorMachine.outbox = newOutbox(config.Config.DownstreamRateLimit, executorMachine.queueDownstream)
+	executorMachine.leading.Store(!leaderElectionEnabled())
+	if leaderElectionEnabled() {
+		connected := func(nodeName string) bool {
+			return connection.GetQuality(nodeName).Connected
+		}
+		executorMachine.relay = newRelay(executorMachine.kubeClient, connected, executorMachine.leading.Load,
+			executorMachine.enqueueDownstream, executorMachine.handleRelayed)
+	}
+	if err := executorMachine.watchNodeRemoval(); err != nil {
+		return nil, fmt.Errorf("failed to watch node removal: %v", err)
+	}
+	if err := executorMachine.watchTaskTypeConfigs(); err != nil {
+		return nil, fmt.Errorf("failed to watch task type configs: %v", err)
 	}
 	return executorMachine, nil
 }
@@ -73,6 +187,14 @@ func (em *ExecutorMachine) Start() error {
 	klog.Info("Start ExecutorMachine")
 
 	go em.syncTask()
+	go em.watchExecutors()
+	go em.collectFinishedTasks()
+	if leaderElectionEnabled() {
+		if err := em.relay.run(beehiveContext.Done()); err != nil {
+			return fmt.Errorf("failed to relay task messages: %v", err)
+		}
+		go em.runLeaderElection()
+	}
 
 	return nil
 }
@@ -85,9 +207,41 @@ func (em *ExecutorMachine) syncTask() {
 			klog.Info("stop sync tasks")
 			return
 		case msg := <-em.messageChan:
+			if !em.leading.Load() {
+				// the task is adopted from its recorded status once this replica is elected
+				klog.V(4).Infof("drop %s task %s message, the executors run on the leader", msg.Type, msg.Name)
+				break
+			}
 			if msg.ShutDown {
 				klog.Errorf("delete executor %s ", msg.Name)
+				StopExecutor(msg)
 				DeleteExecutor(msg)
+				go purgeRecords(msg.Type, msg.Name)
+				util.ResolveAlerts(msg.Type, msg.Name)
+				break
+			}
+			if msg.Cancel {
+				CancelExecutor(msg)
+				break
+			}
+			if msg.ForceComplete != nil {
+				ForceCompleteExecutor(msg)
+				break
+			}
+			if msg.SetPaused {
+				PauseExecutor(msg)
+				break
+			}
+			if msg.SetApply {
+				ApplyExecutor(msg)
+				break
+			}
+			if msg.SetApprove {
+				ApproveExecutor(msg)
+				break
+			}
+			if msg.SetTuning {
+				TuneExecutor(msg)
 				break
 			}
 			err := GetExecutor(msg).HandleMessage(msg.Status)
@@ -104,6 +258,16 @@ type ExecutorMachine struct {
 	executors      map[string]*Executor
 	messageChan    chan util.TaskMessage
 	downStreamChan chan model.Message
+	// pending holds the task messages waiting to be pulled by edge nodes in pull mode
+	pending *pendingTasks
+	// outbox sends the task messages to downStreamChan at the configured rates
+	outbox *outbox
+	// leading is set while the executors run on this replica, i.e. it is the elected leader
+	// or the leader election is disabled
+	leading atomic.Bool
+	// relay carries the task messages of the edge nodes connected to the other replicas, it is set
+	// if the leader election is enabled
+	relay *relay
 	sync.Mutex
 }
 
@@ -126,25 +290,79 @@ func GetExecutor(msg util.TaskMessage) *Executor {
 
 func DeleteExecutor(msg util.TaskMessage) {
 	executorMachine.Lock()
-	defer executorMachine.Unlock()
 	delete(executorMachine.executors, fmt.Sprintf("%s::%s", msg.Type, msg.Name))
+	executorMachine.pending.remove(msg.Name)
+	executorMachine.outbox.forget(msg.Name)
+	monitor.TaskFailedNodes.DeleteLabelValues(msg.Type, msg.Name)
+	monitor.TaskToleratedFailedNodes.DeleteLabelValues(msg.Type, msg.Name)
+	monitor.TaskNodes.DeletePartialMatch(prometheus.Labels{"task_type": msg.Type, "task_name": msg.Name})
+	monitor.TaskActiveExecutors.Set(float64(len(executorMachine.executors)))
+	inProgress := nodesInProgress(msg.Type, msg.Name)
+	executorMachine.Unlock()
+	// the nodes are unlocked after the executor machine is released, the other executors do not
+	// wait for the round trips to the apiserver
+	clearNodesInProgress(msg.Type, msg.Name, inProgress)
+}
+
+// CancelExecutor requests the running executor of the task to cancel it
+func CancelExecutor(msg util.TaskMessage) {
+	executorMachine.Lock()
+	e, ok := executorMachine.executors[fmt.Sprintf("%s::%s", msg.Type, msg.Name)]
+	executorMachine.Unlock()
+	if !ok {
+		klog.Warningf("task %s to cancel is not running", msg.Name)
+		return
+	}
+	select {
+	case e.cancelChan <- msg.Drain:
+	default:
+	}
+}
+
+// clearNodesInProgress removes the in-progress annotation of the nodes still operated on by the task
+func clearNodesInProgress(taskType, taskName string, inProgress map[string]bool) {
+	for nodeName := range inProgress {
+		if err := util.UnmarkNodeInProgress(nodeName, taskType, taskName); err != nil {
+			klog.Warningf("failed to unmark node %s in progress of task %s: %v", nodeName, taskName, err)
+		}
+	}
+}
+
+// nodesInProgress returns the nodes annotated as operated on by the task
+func nodesInProgress(taskType, taskName string) map[string]bool {
+	nodes, err := informers.GetInformersManager().GetKubeInformerFactory().Core().V1().Nodes().Lister().List(labels.Everything())
+	if err != nil {
+		klog.Warningf("failed to list nodes in progress of task %s: %v", taskName, err)
+		return nil
+	}
+	inProgress := map[string]bool{}
+	for _, node := range nodes {
+		if util.LockedBy(node.Annotations, taskType, taskName) {
+			inProgress[node.Name] = true
+		}
+	}
+	return inProgress
 }
 
 func (e *Executor) HandleMessage(status v1alpha1.TaskStatus) error {
 	if e == nil {
 		return fmt.Errorf("executor is nil")
 	}
-	e.statusChan <- &status
+	select {
+	case e.statusChan <- &status:
+	case <-e.stopChan:
+		return fmt.Errorf("executor of task %s is stopped", e.task.Name)
+	}
 	return nil
 }
 
-func (e *Executor) initMessage(node v1alpha1.TaskStatus) *model.Message {
-	// delete it in 1.18
-	if e.task.Type == util.TaskUpgrade {
+func (e *Executor) initMessage(node v1alpha1.TaskStatus) (*model.Message, error) {
+	// delete it in 1.18, the history message upgrades the node at once so it is never sent by a dry run
+	if e.task.Type == util.TaskUpgrade && !e.task.DryRun {
 		msg := e.initHistoryMessage(node)
 		if msg != nil {
 			klog.Warningf("send history message to node")
-			return msg
+			return msg, nil
 		}
 	}
 
@@ -152,19 +370,77 @@ func (e *Executor) initMessage(node v1alpha1.TaskStatus) *model.Message {
 	resource := buildTaskResource(e.task.Type, e.task.Name, node.NodeName)
 
 	taskReq := commontypes.NodeTaskRequest{
-		TaskID: e.task.Name,
-		Type:   e.task.Type,
-		State:  string(node.State),
+		TaskID:    e.task.Name,
+		Type:      e.task.Type,
+		State:     string(node.State),
+		Reconcile: e.reconcileNodes[node.NodeName],
+		Metadata:  e.task.Metadata,
 	}
+	delete(e.reconcileNodes, node.NodeName)
 	taskReq.Item = e.task.Msg
 	if node.State == api.TaskChecking {
 		taskReq.Item = commontypes.NodePreCheckRequest{
-			CheckItem: e.task.CheckItem,
+			CheckItem:         e.task.CheckItem,
+			DryRun:            e.task.DryRun,
+			RotateCertificate: e.task.RotateCertificate,
+			RegistryProbe:     e.registryProbe(),
+		}
+	} else {
+		if e.task.RenderPayload {
+			// resolve the per-node variables in payload templates
+			edgeNode, err := informers.GetInformersManager().GetKubeInformerFactory().Core().V1().Nodes().Lister().Get(node.NodeName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get node %s: %v", node.NodeName, err)
+			}
+			if taskReq.Item, err = util.RenderPayload(taskReq.Item, edgeNode); err != nil {
+				return nil, err
+			}
+		}
+		if isSealedPayloadType(e.task.Type) {
+			var err error
+			taskReq.SealedItem, err = sealItem(node.NodeName, taskReq.Item)
+			if err != nil {
+				return nil, err
+			}
+			taskReq.Item = nil
+		}
+	}
+	if e.task.Credential != nil {
+		var err error
+		taskReq.SealedCredential, err = mintCredential(e.task.Name, node.NodeName, *e.task.Credential)
+		if err != nil {
+			return nil, err
 		}
 	}
 	msg.BuildRouter(modules.TaskManagerModuleName, modules.TaskManagerModuleGroup, resource, e.task.Type).
 		FillBody(taskReq)
-	return msg
+	return msg, nil
+}
+
+func isSealedPayloadType(taskType string) bool {
+	for _, t := range config.Config.SealedPayloadTypes {
+		if t == taskType {
+			return true
+		}
+	}
+	return false
+}
+
+// sealItem encrypts the payload with the public key of the certificate the edge node connected with
+func sealItem(nodeName string, item interface{}) (*envelope.Envelope, error) {
+	publicKey, err := connection.GetPublicKey(client.GetKubeClient(), nodeName)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(item)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %v", err)
+	}
+	sealed, err := envelope.Seal(publicKey, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to seal payload for node %s: %v", nodeName, err)
+	}
+	return sealed, nil
 }
 
 func (e *Executor) initHistoryMessage(node v1alpha1.TaskStatus) *model.Message {
@@ -203,31 +479,99 @@ func initExecutor(message util.TaskMessage) (*Executor, error) {
 	if err != nil {
 		return nil, err
 	}
+	// the task runs with the defaults of its type for the fields it does not set
+	message = taskPipeline(message.Type).Apply(message)
 	nodeStatus, err := controller.GetNodeStatus(message.Name)
 	if err != nil {
 		return nil, err
 	}
+	reconcileNodes := map[string]bool{}
+	var reverting bool
+	for _, node := range nodeStatus {
+		if !fsm.TaskFinish(node.State) {
+			reconcileNodes[node.NodeName] = true
+		}
+		// the upgraded nodes were being rolled back before cloudcore restarted
+		if node.State == api.RevertingState || node.State == api.TaskReverted {
+			reverting = true
+		}
+	}
 	if len(nodeStatus) == 0 {
-		nodeList := controller.ValidateNode(message)
-		if len(nodeList) == 0 {
-			return nil, fmt.Errorf("no node need to be upgrade")
+		nodeList, excluded := controller.ValidateNode(message)
+		if err = recordExcludedNodes(controller, message, len(nodeList), excluded); err != nil {
+			return nil, err
+		}
+		if err = controller.AnalyzeImpact(message.Name, nodeList); err != nil {
+			klog.Warningf("analyze impact of task %s failed: %s", message.Name, err.Error())
 		}
 		nodeStatus = make([]v1alpha1.TaskStatus, len(nodeList))
 		for i, node := range nodeList {
 			nodeStatus[i] = v1alpha1.TaskStatus{NodeName: node.Name}
 		}
+		if message.OrderingSeed != nil {
+			// the order is recorded with the node status, it is kept when cloudcore restarts
+			util.ShuffleNodes(nodeStatus, *message.OrderingSeed)
+		}
+		if message.OrderByReadiness {
+			sortNodesByReadiness(nodeStatus, nodeList, connection.GetQuality, recentFailures.count)
+		}
 		err = controller.UpdateNodeStatus(message.Name, nodeStatus)
 		if err != nil {
 			return nil, err
 		}
+	} else {
+		// the recorded status may be partial if it was truncated or edited manually,
+		// reconcile it with the resolved nodes so that no node is silently skipped
+		var backfilled []string
+		nodeList, _ := controller.ValidateNode(message)
+		nodeStatus, backfilled = backfillNodeStatus(nodeStatus, nodeList)
+		if len(backfilled) != 0 {
+			klog.Warningf("task %s status misses nodes %v, backfill them", message.Name, backfilled)
+			if err = controller.UpdateNodeStatus(message.Name, nodeStatus); err != nil {
+				return nil, err
+			}
+		}
+	}
+	paceNodes(message, nodeStatus, config.Config.DispatchPacing, connection.GetQuality)
+	if len(reconcileNodes) != 0 {
+		// the executor is rebuilt from the recorded status after cloudcore restarted, the nodes
+		// it was operating on are dispatched again first so that the task resumes where it stopped
+		resumed := resumeNodes(nodeStatus, nodesInProgress(message.Type, message.Name))
+		if len(resumed) != 0 {
+			klog.Infof("resume task %s from nodes %v", message.Name, resumed)
+			util.RecordTaskEvent(message, v1.EventTypeNormal, "Resumed",
+				"Resume the task on %d nodes in progress", len(resumed))
+		}
 	}
 	e := &Executor{
-		task:           message,
-		statusChan:     make(chan *v1alpha1.TaskStatus, 10),
-		nodes:          nodeStatus,
-		controller:     controller,
-		maxFailedNodes: float64(len(nodeStatus)) * (message.FailureTolerate),
-		failedNodes:    map[string]bool{},
+		task:             message,
+		statusChan:       make(chan *v1alpha1.TaskStatus, 10),
+		nodes:            nodeStatus,
+		controller:       controller,
+		failedNodes:      map[string]bool{},
+		reconcileNodes:   reconcileNodes,
+		warnedThresholds: map[int32]bool{},
+		receipts:         &receipts{states: map[string]api.State{}},
+		cancelChan:       make(chan bool, 1),
+		stopChan:         make(chan struct{}),
+		removedChan:      make(chan string, 10),
+		forceChan:        make(chan *util.ForceCompletion, 10),
+		redriveChan:      make(chan redrivenNode, 10),
+		forced:           map[string]bool{},
+		pauseChan:        make(chan bool, 10),
+		paused:           message.Paused,
+		alertChan:        make(chan string, 10),
+		applyChan:        make(chan *v1alpha1.ApplySpec, 10),
+		apply:            message.Apply,
+		approveChan:      make(chan bool, 10),
+		slotChan:         make(chan struct{}, 1),
+		tuneChan:         make(chan *util.TaskMessage, 1),
+		probeChan:        make(chan string, len(nodeStatus)),
+		unreachable:      map[string]bool{},
+		requeueChan:      make(chan struct{}, 1),
+		dispatchChan:     make(chan deferredJob, 10),
+		verifying:        map[string]bool{},
+		reverting:        reverting,
 		workers: workers{
 			number:       int(message.Concurrency),
 			jobs:         make(map[string]int),
@@ -235,85 +579,391 @@ func initExecutor(message util.TaskMessage) (*Executor, error) {
 			Mutex:        sync.Mutex{},
 		},
 	}
+	e.updateFailureBudget()
+	monitor.TaskFailedNodes.WithLabelValues(message.Type, message.Name).Set(0)
+	e.initCanary()
+	e.resizeWorkers()
+	if message.DryRun {
+		e.recordPlan()
+	}
+	if readOnly() {
+		e.observe()
+	}
+	e.markProgress()
 	go e.start()
+	executorMachine.Lock()
 	executorMachine.executors[fmt.Sprintf("%s::%s", message.Type, message.Name)] = e
+	monitor.TaskActiveExecutors.Set(float64(len(executorMachine.executors)))
+	executorMachine.Unlock()
 	return e, nil
 }
 
+// handleUnknownState records the state the controller can not classify and applies the unknown state policy
+func (e *Executor) handleUnknownState(status v1alpha1.TaskStatus) {
+	klog.Warningf("node %s of task %s reported unknown state %q", status.NodeName, e.task.Name, status.State)
+	util.RecordTaskEvent(e.task, v1.EventTypeWarning, "UnknownState",
+		"Node %s reported unknown state %q, policy %s", status.NodeName, status.State, config.Config.UnknownStatePolicy)
+
+	state := api.TaskUnknown
+	if config.Config.UnknownStatePolicy == cloudcorev1alpha1.UnknownStatePolicyFail {
+		state = api.TaskFailed
+	}
+	nodeStatus, err := e.controller.GetNodeStatus(e.task.Name)
+	if err != nil {
+		klog.Errorf("failed to get node status of task %s: %v", e.task.Name, err)
+		return
+	}
+	for i := range nodeStatus {
+		if nodeStatus[i].NodeName != status.NodeName || nodeStatus[i].State != status.State {
+			continue
+		}
+		nodeStatus[i].State = state
+		nodeStatus[i].Reason = fmt.Sprintf("unknown state %q", status.State)
+		nodeStatus[i].Time = time.Now().UTC().Format(util.ISO8601UTC)
+		if err = e.controller.UpdateNodeStatus(e.task.Name, nodeStatus); err != nil {
+			klog.Errorf("failed to mark node %s of task %s %s: %v", status.NodeName, e.task.Name, state, err)
+		}
+		return
+	}
+}
+
+// resumeNodes moves the unfinished nodes the task was operating on to the front, keeping their
+// order, and returns their names. A node is in progress if it left its initial state or if it is
+// annotated in progress, as it may have been dispatched without reporting any state yet.
+func resumeNodes(nodeStatus []v1alpha1.TaskStatus, inProgress map[string]bool) []string {
+	resumed := map[string]bool{}
+	var names []string
+	for _, node := range nodeStatus {
+		if fsm.TaskFinish(node.State) {
+			continue
+		}
+		if (node.State != "" && node.State != api.TaskInit) || inProgress[node.NodeName] {
+			resumed[node.NodeName] = true
+			names = append(names, node.NodeName)
+		}
+	}
+	sort.SliceStable(nodeStatus, func(i, j int) bool {
+		return resumed[nodeStatus[i].NodeName] && !resumed[nodeStatus[j].NodeName]
+	})
+	return names
+}
+
+// backfillNodeStatus appends an empty TaskStatus for each node missing in nodeStatus,
+// the names of backfilled nodes are returned.
+func backfillNodeStatus(nodeStatus []v1alpha1.TaskStatus, nodes []v1.Node) ([]v1alpha1.TaskStatus, []string) {
+	recorded := make(map[string]bool, len(nodeStatus))
+	for _, status := range nodeStatus {
+		recorded[status.NodeName] = true
+	}
+	var backfilled []string
+	for _, node := range nodes {
+		if recorded[node.Name] {
+			continue
+		}
+		recorded[node.Name] = true
+		nodeStatus = append(nodeStatus, v1alpha1.TaskStatus{NodeName: node.Name})
+		backfilled = append(backfilled, node.Name)
+	}
+	return nodeStatus, backfilled
+}
+
 func (e *Executor) start() {
+	defer e.recoverPanic()
+	defer e.stopWindowTimer()
+	defer inFlight.forget(e)
+	var deadline <-chan time.Time
+	if e.task.ActiveDeadline > 0 {
+		// the timer runs on the monotonic clock, the deadline counts from the time this executor started
+		timer := time.NewTimer(e.task.ActiveDeadline)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	if e.canary != nil && e.approved {
+		// the task may have been approved while cloudcore was down
+		e.reportApproval()
+	}
+	if !e.preflight() {
+		return
+	}
 	index, err := e.initWorker(0)
 	if err != nil {
 		klog.Errorf(err.Error())
 		return
 	}
+	// all the nodes may have completed the stage before cloudcore restarted, complete it
+	// now as no status update of the nodes is to come
+	var finished bool
+	if index, finished = e.advance(index); finished {
+		return
+	}
 	for {
 		select {
 		case <-beehiveContext.Done():
 			klog.Info("stop sync tasks")
 			return
+		case <-e.stopChan:
+			klog.Infof("task %s is deleted, stop executing it", e.task.Name)
+			return
+		case drain := <-e.cancelChan:
+			if e.cancel(drain) {
+				return
+			}
+		case <-deadline:
+			if e.expire() {
+				return
+			}
 		case status := <-e.statusChan:
 			if reflect.DeepEqual(*status, v1alpha1.TaskStatus{}) {
 				break
 			}
+			if !e.controller.KnownState(e.task.Name, status.State) {
+				e.handleUnknownState(*status)
+				break
+			}
+			if status.State == api.VerifyingState {
+				e.verify(*status)
+				break
+			}
 			if !e.controller.StageCompleted(e.task.Name, status.State) {
 				break
 			}
+			// the executor released the node when it marked it removed, skipped, unreachable or forced it to complete
+			if status.State == api.TaskNodeRemoved || status.State == api.TaskSkipped || e.forced[status.NodeName] || e.unreachable[status.NodeName] {
+				break
+			}
 			var endNode int
 			endNode, err = e.workers.endJob(status.NodeName)
 			if err != nil {
 				klog.Errorf(err.Error())
 				break
 			}
+			inFlight.release(e)
+			if duration, ok := e.queue.jobEnded(status.NodeName); ok {
+				monitor.TaskNodeStageDurationSeconds.WithLabelValues(e.task.Type, string(e.nodes[endNode].State)).Observe(duration.Seconds())
+			}
 
 			e.nodes[endNode] = *status
+			e.markProgress()
+			if fsm.TaskFinish(status.State) {
+				if err = util.UnmarkNodeInProgress(status.NodeName, e.task.Type, e.task.Name); err != nil {
+					klog.Warningf("failed to unmark node %s in progress of task %s: %v", status.NodeName, e.task.Name, err)
+				}
+			}
+			if status.State == api.TaskSuccessful && e.task.DrainNodeBeforeUpgrade != nil {
+				e.uncordon(status.NodeName)
+			}
+			if e.cancelled {
+				// a drained node which completed a stage is not dispatched the next one
+				if e.drain && !fsm.TaskFinish(status.State) {
+					e.cancelNode(status.NodeName)
+				}
+				if len(e.workers.jobs) == 0 {
+					e.finishCancel()
+					return
+				}
+				break
+			}
 			err = e.dealFailedNode(*status)
 			if err != nil {
 				klog.Warning(err.Error())
 				break
 			}
-
-			if index >= len(e.nodes) {
-				if len(e.workers.jobs) != 0 {
-					break
+			e.checkPauseOn(*status)
+			var finished bool
+			if index, finished = e.advance(index); finished {
+				return
+			}
+		case nodeName := <-e.removedChan:
+			if !e.removeNode(nodeName) {
+				break
+			}
+			e.resizeWorkers()
+			if e.cancelled {
+				if len(e.workers.jobs) == 0 {
+					e.finishCancel()
+					return
 				}
-				var state api.State
-				state, err = e.completedTaskStage()
-				if err != nil {
-					klog.Errorf(err.Error())
-					break
+				break
+			}
+			var finished bool
+			if index, finished = e.advance(index); finished {
+				return
+			}
+		case paused := <-e.pauseChan:
+			if !e.pause(paused) {
+				break
+			}
+			var finished bool
+			if index, finished = e.advance(index); finished {
+				return
+			}
+		case alert := <-e.alertChan:
+			e.autoPause(fmt.Sprintf("the external alert %s fired", alert))
+		case apply := <-e.applyChan:
+			if !e.setApply(apply) {
+				break
+			}
+			var finished bool
+			if index, finished = e.advance(index); finished {
+				return
+			}
+		case approved := <-e.approveChan:
+			if !e.approve(approved) {
+				break
+			}
+			var finished bool
+			if index, finished = e.advance(index); finished {
+				return
+			}
+		case tuning := <-e.tuneChan:
+			if !e.tune(tuning) || e.cancelled {
+				break
+			}
+			var finished bool
+			if index, finished = e.advance(index); finished {
+				return
+			}
+		case <-e.slotChan:
+			if e.cancelled {
+				break
+			}
+			var finished bool
+			if index, finished = e.advance(index); finished {
+				return
+			}
+		case <-e.requeueChan:
+			e.lockTimer = nil
+			if e.cancelled {
+				break
+			}
+			var finished bool
+			if index, finished = e.advance(index); finished {
+				return
+			}
+		case job := <-e.dispatchChan:
+			if e.startJob(job) {
+				break
+			}
+			if e.cancelled {
+				if len(e.workers.jobs) == 0 {
+					e.finishCancel()
+					return
 				}
-				if fsm.TaskFinish(state) {
-					DeleteExecutor(e.task)
-					klog.Infof("task %s is finish", e.task.Name)
+				break
+			}
+			var finished bool
+			if index, finished = e.advance(index); finished {
+				return
+			}
+		case <-e.windowOpened():
+			klog.Infof("the maintenance window of task %s opens, apply it", e.task.Name)
+			e.recordMaintenanceWindow(nil)
+			e.stopWindowTimer()
+			var finished bool
+			if index, finished = e.advance(index); finished {
+				return
+			}
+		case redriven := <-e.redriveChan:
+			e.redrive(redriven)
+		case force := <-e.forceChan:
+			node, ok := e.forceComplete(force)
+			if !ok {
+				break
+			}
+			if e.cancelled {
+				if len(e.workers.jobs) == 0 {
+					e.finishCancel()
 					return
 				}
-
-				// next stage
-				index = 0
+				break
 			}
-
-			index, err = e.initWorker(index)
-			if err != nil {
-				klog.Errorf(err.Error())
+			if err = e.dealFailedNode(node); err != nil {
+				klog.Warning(err.Error())
+				break
 			}
+			var finished bool
+			if index, finished = e.advance(index); finished {
+				return
+			}
+		}
+	}
+}
+
+// advance dispatches the nodes from index to the free workers, and completes the stage once all
+// nodes finished it. It returns the index of the next node to dispatch and whether the task is finished.
+func (e *Executor) advance(index int) (int, bool) {
+	if e.revertReason != "" && !e.reverting {
+		e.revert()
+		index = 0
+	}
+	if index >= len(e.nodes) {
+		if len(e.workers.jobs) != 0 {
+			return index, false
+		}
+		if e.reverting {
+			e.finishRevert()
+			return index, true
 		}
+		if e.task.DryRun && e.checked() {
+			e.finishDryRun()
+			return index, true
+		}
+		state, err := e.completedTaskStage()
+		if err != nil {
+			klog.Errorf(err.Error())
+			return index, false
+		}
+		if fsm.TaskFinish(state) {
+			DeleteExecutor(e.task)
+			klog.Infof("task %s is finish", e.task.Name)
+			return index, true
+		}
+
+		// next stage
+		index = 0
+	}
+
+	index, err := e.initWorker(index)
+	if err != nil {
+		klog.Errorf(err.Error())
+	}
+	if index < len(e.nodes) && len(e.workers.jobs) == 0 && e.approvalRequired(e.nodes[index]) {
+		e.waitApproval()
 	}
+	e.publishQueue(index)
+	return index, false
 }
 
 func (e *Executor) dealFailedNode(node v1alpha1.TaskStatus) error {
-	if node.State == api.TaskFailed {
+	if e.reverting || e.revertReason != "" {
+		// the task fails once the upgraded nodes are rolled back
+		return nil
+	}
+	if node.State == api.TaskFailed && !e.failedNodes[node.NodeName] {
 		e.failedNodes[node.NodeName] = true
+		recentFailures.record(node.NodeName, time.Now())
+		e.checkFailureBudget()
 	}
-	if float64(len(e.failedNodes)) < e.maxFailedNodes {
+	if len(e.failedNodes) < int(util.FailureThreshold(e.maxFailedNodes)) {
 		return nil
 	}
-	e.workers.shuttingDown = true
-	if len(e.workers.jobs) > 0 {
-		klog.Warningf("wait for all workers(%d/%d) for task %s to finish running ", len(e.workers.jobs), e.workers.number, e.task.Name)
+	if !e.toleranceExceeded {
+		e.toleranceExceeded = true
+		e.notifyFailureToleranceExceeded()
+	}
+	e.workers.shutDown(true)
+	if running := e.workers.running(); running > 0 {
+		klog.Warningf("wait for all workers(%d/%d) for task %s to finish running ", running, e.workers.number, e.task.Name)
 		return nil
 	}
 
 	errMsg := fmt.Sprintf("the number of failed nodes is %d/%d, which exceeds the failure tolerance threshold.", len(e.failedNodes), len(e.nodes))
+	if e.revertRequired() {
+		// the upgraded nodes are rolled back when the task advances
+		klog.Warningf("task %s: %s roll back the upgraded nodes", e.task.Name, errMsg)
+		e.revertReason = errMsg
+		return nil
+	}
 	_, err := e.controller.ReportTaskStatus(e.task.Name, fsm.Event{
 		Type:   node.Event,
 		Action: api.ActionFailure,
@@ -325,17 +975,80 @@ func (e *Executor) dealFailedNode(node v1alpha1.TaskStatus) error {
 	return fmt.Errorf(errMsg)
 }
 
+// updateFailureBudget sizes the failure budget of the task from its failure tolerance and its nodes,
+// and records the number of failed nodes the task fails at.
+func (e *Executor) updateFailureBudget() {
+	maxFailedNodes, err := util.MaxFailedNodes(e.task.FailureTolerate, len(e.nodes))
+	if err != nil {
+		klog.Warningf("task %s has an invalid failure tolerance, use the default %s: %v", e.task.Name, util.DefaultFailureTolerate.String(), err)
+		util.RecordTaskEvent(e.task, v1.EventTypeWarning, "InvalidFailureTolerate",
+			"%v, the default %s is used", err, util.DefaultFailureTolerate.String())
+		maxFailedNodes, _ = util.MaxFailedNodes(nil, len(e.nodes))
+	}
+	e.maxFailedNodes = maxFailedNodes
+	monitor.TaskToleratedFailedNodes.WithLabelValues(e.task.Type, e.task.Name).Set(maxFailedNodes)
+	if err = e.controller.RecordFailureThreshold(e.task.Name, util.FailureThreshold(maxFailedNodes)); err != nil {
+		klog.Warningf("failed to record the failure threshold of task %s: %v", e.task.Name, err)
+	}
+}
+
+// checkFailureBudget exports how much of the failure budget the task consumed,
+// and emits a warning event each time a configured threshold is reached.
+func (e *Executor) checkFailureBudget() {
+	failed := len(e.failedNodes)
+	monitor.TaskFailedNodes.WithLabelValues(e.task.Type, e.task.Name).Set(float64(failed))
+	if e.maxFailedNodes <= 0 {
+		return
+	}
+	consumed := float64(failed) * 100 / e.maxFailedNodes
+	for _, threshold := range config.Config.FailureBudgetWarningThresholds {
+		if consumed < float64(threshold) || e.warnedThresholds[threshold] {
+			continue
+		}
+		e.warnedThresholds[threshold] = true
+		klog.Warningf("task %s consumed %d%% of the failure budget", e.task.Name, int(consumed))
+		util.RecordTaskEvent(e.task, v1.EventTypeWarning, "FailureBudgetConsumed",
+			"%d failed nodes, %d%% of the failure budget (%.1f nodes) is consumed", failed, int(consumed), e.maxFailedNodes)
+		util.FireAlert(e.task.Type, e.task.Name, util.AlertTaskFailureBudget, util.AlertSeverityWarning,
+			fmt.Sprintf("%s task %s consumed %d%% of the failure budget", e.task.Type, e.task.Name, int(consumed)))
+	}
+}
+
+// notifyFailureToleranceExceeded notifies the sinks of the task that its failed nodes exceeded its
+// failure tolerance, before the task fails or rolls back the upgraded nodes
+func (e *Executor) notifyFailureToleranceExceeded() {
+	kind := e.task.Type
+	if e.task.Owner != nil {
+		kind = e.task.Owner.Kind
+	}
+	nodes := util.CountNotificationNodes(e.nodes)
+	nodes.Failed = len(e.failedNodes)
+	util.Notify(util.TaskObjectReference(e.task), e.task.Notifications, util.TaskNotification{
+		Type:  util.NotificationFailureToleranceExceeded,
+		Kind:  kind,
+		Name:  e.task.Name,
+		Nodes: nodes,
+		Time:  time.Now().UTC(),
+	})
+}
+
+// completedTaskStage moves the task to its next stage once all the nodes completed the current one,
+// the task finishes PartiallySucceeded instead of Successful if some nodes failed.
 func (e *Executor) completedTaskStage() (api.State, error) {
 	var event = e.nodes[0].Event
 	for _, node := range e.nodes {
-		if node.State != api.TaskFailed {
+		if node.State != api.TaskFailed && node.State != api.TaskNodeRemoved && node.State != api.TaskSkipped {
 			event = node.Event
 			break
 		}
 	}
+	action := api.ActionSuccess
+	if len(e.failedNodes) != 0 {
+		action = api.ActionPartialSuccess
+	}
 	state, err := e.controller.ReportTaskStatus(e.task.Name, fsm.Event{
 		Type:   event,
-		Action: api.ActionSuccess,
+		Action: action,
 	})
 	if err != nil {
 		return "", err
@@ -344,6 +1057,19 @@ func (e *Executor) completedTaskStage() (api.State, error) {
 }
 
 func (e *Executor) initWorker(index int) (int, error) {
+	var throttled bool
+	defer func() {
+		if !throttled {
+			inFlight.leave(e)
+		}
+	}()
+	// locked are the nodes deferred as they are operated on by other tasks
+	locked := map[string]bool{}
+	defer func() {
+		if len(locked) != 0 {
+			e.retryLockedNodes(len(locked))
+		}
+	}()
 	for ; index < len(e.nodes); index++ {
 		node := e.nodes[index]
 		if e.controller.StageCompleted(e.task.Name, node.State) {
@@ -353,15 +1079,54 @@ func (e *Executor) initWorker(index int) (int, error) {
 			}
 			continue
 		}
+		if e.reverting && node.State != api.RevertingState {
+			// only the upgraded nodes are dispatched again to roll them back
+			continue
+		}
+		if readOnly() {
+			klog.V(4).Infof("TaskManager is read-only, stop dispatching task %s at node %s", e.task.Name, node.NodeName)
+			break
+		}
+		if e.paused {
+			klog.V(4).Infof("task %s is paused, stop dispatching at node %s", e.task.Name, node.NodeName)
+			e.holdMembers()
+			break
+		}
+		if e.membersStale && e.refreshMembers() {
+			// the nodes changed, dispatch them from the first one
+			return e.initWorker(0)
+		}
+		if e.applyHeld(node) {
+			break
+		}
+		if e.approvalRequired(node) {
+			break
+		}
+		if e.skipPendingRemoval(index) {
+			continue
+		}
 		err := e.workers.addJob(node, index, e)
+		if err == errNodeLocked {
+			if locked[node.NodeName] {
+				// the nodes left are all locked
+				break
+			}
+			locked[node.NodeName] = true
+			e.deferNode(index)
+			index--
+			continue
+		}
 		if err != nil {
 			klog.V(4).Info(err.Error())
+			throttled = err == errCeilingReached
 			break
 		}
 	}
 	return index, nil
 }
 
+// workers are the nodes running the task. The lock of the workers also guards shuttingDown and the
+// cancelled and drain of the executor, the goroutines waiting for the jobs read them.
 type workers struct {
 	number int
 	jobs   map[string]int
@@ -369,41 +1134,214 @@ type workers struct {
 	shuttingDown bool
 }
 
+// stopped returns whether the workers are shut down, they take no new job
+func (w *workers) stopped() bool {
+	w.Lock()
+	defer w.Unlock()
+	return w.shuttingDown
+}
+
+// shutDown stops the workers taking new jobs, or lets them take new jobs again
+func (w *workers) shutDown(down bool) {
+	w.Lock()
+	w.shuttingDown = down
+	w.Unlock()
+}
+
+// running returns the number of the running jobs
+func (w *workers) running() int {
+	w.Lock()
+	defer w.Unlock()
+	return len(w.jobs)
+}
+
 func (w *workers) addJob(node v1alpha1.TaskStatus, index int, e *Executor) error {
+	w.Lock()
 	if w.shuttingDown {
+		w.Unlock()
 		return fmt.Errorf("workers is stopped")
 	}
-	w.Lock()
 	if len(w.jobs) >= w.number {
 		w.Unlock()
 		return fmt.Errorf("workers are all running, %v/%v", len(w.jobs), w.number)
 	}
+	if !inFlight.acquire(e, int(config.Config.MaxInFlightNodes)) {
+		w.Unlock()
+		return errCeilingReached
+	}
 	w.jobs[node.NodeName] = index
 	w.Unlock()
-	msg := e.initMessage(node)
-	go e.handelTimeOutJob(index)
-	executorMachine.downStreamChan <- *msg
+	holder, err := util.LockNode(node.NodeName, e.task.Type, e.task.Name)
+	if err != nil {
+		klog.Warningf("failed to lock node %s for task %s: %v", node.NodeName, e.task.Name, err)
+	}
+	if holder != "" {
+		klog.Infof("node %s is operated on by task %s, defer it in task %s", node.NodeName, holder, e.task.Name)
+		w.Lock()
+		delete(w.jobs, node.NodeName)
+		w.Unlock()
+		inFlight.release(e)
+		return errNodeLocked
+	}
+	e.markProgress()
+	e.queue.jobStarted(node.NodeName)
+	if node.State == api.VerifyingState {
+		// the node reported it is upgraded before cloudcore restarted, it is verified again
+		e.verify(node)
+		return nil
+	}
+	msg, err := e.initMessage(node)
+	job := deferredJob{index: index, msg: msg, err: err, drain: e.drainRequired(node)}
+	jitter := dispatchJitter(e.task.DispatchJitterSeconds)
+	if jitter == 0 {
+		e.startJob(job)
+		return nil
+	}
+	klog.V(4).Infof("dispatch task %s to node %s in %s", e.task.Name, node.NodeName, jitter)
+	time.AfterFunc(jitter, func() {
+		e.deferDispatch(job)
+	})
 	return nil
 }
 
-func (e *Executor) handelTimeOutJob(index int) {
-	lastState := e.nodes[index].State
-	timeoutSecond := *e.task.TimeOutSeconds
-	if timeoutSecond == 0 {
-		timeoutSecond = TimeOutSecond
+// deferredJob is a job whose dispatch is deferred, by the dispatch jitter or by draining its node.
+// It is handed back to the executor loop to be dispatched.
+type deferredJob struct {
+	index int
+	msg   *model.Message
+	err   error
+	// drain is set if the node is to be drained before the job is dispatched
+	drain bool
+}
+
+// deferDispatch hands the deferred job back to the executor loop, it is dropped if the task is deleted
+func (e *Executor) deferDispatch(job deferredJob) {
+	select {
+	case e.dispatchChan <- job:
+	case <-e.stopChan:
 	}
-	err := wait.Poll(1*time.Second, time.Duration(timeoutSecond)*time.Second, func() (bool, error) {
-		if lastState != e.nodes[index].State || fsm.TaskFinish(e.nodes[index].State) {
-			return true, nil
+}
+
+// startJob drains the node of the job first if required, or else dispatches the job to the node.
+// It returns false if the job is dropped and its node released.
+func (e *Executor) startJob(job deferredJob) bool {
+	if job.drain {
+		e.drainAndDispatch(job.index, job.msg, job.err)
+		return true
+	}
+	return e.dispatchJob(job.index, job.msg, job.err)
+}
+
+// dispatchJob sends the message of the job to the node and watches its timeout. The job is dropped
+// if the task is cancelled or the node finished meanwhile, it returns false if so.
+func (e *Executor) dispatchJob(index int, msg *model.Message, err error) bool {
+	if e.cancelled || fsm.TaskFinish(e.nodes[index].State) {
+		e.dropJob(index)
+		return false
+	}
+	nodeName := e.nodes[index].NodeName
+	if err != nil {
+		// the message cannot be sent, e.g. no credential can be minted for the node, so the node is
+		// failed at once instead of once the job times out
+		klog.Errorf("failed to init message of task %s for node %s: %v", e.task.Name, nodeName, err)
+		if _, reportErr := e.controller.ReportNodeStatus(e.task.Name, nodeName, fsm.Event{
+			Type:   api.EventTimeOut,
+			Action: api.ActionFailure,
+			Msg:    fmt.Sprintf("failed to init the message of the node: %v", err),
+		}); reportErr != nil {
+			klog.Warningf("failed to fail node %s of task %s: %v", nodeName, e.task.Name, reportErr)
+			go e.handelTimeOutJob(index, nil)
 		}
-		klog.V(4).Infof("node %s stage is not completed", e.nodes[index].NodeName)
-		return false, nil
-	})
+		return true
+	}
+	go e.handelTimeOutJob(index, msg)
+	executorMachine.dispatch(nodeName, e.task.Name, *msg)
+	return true
+}
+
+// dropJob releases the node of the job which is not dispatched. If the task is cancelled with its
+// running nodes drained, the node is cancelled on behalf of the edge node which never received the
+// job, otherwise the edge node was already asked to cancel it by stopNodes.
+func (e *Executor) dropJob(index int) {
+	node := e.nodes[index]
+	klog.V(4).Infof("drop the job of task %s for node %s", e.task.Name, node.NodeName)
+	e.releaseNode(node.NodeName)
+	if e.cancelled && e.drain && !fsm.TaskFinish(node.State) {
+		e.cancelNode(node.NodeName)
+	}
+}
+
+// registryProbe returns the images the task pulls on the nodes and how long the nodes have to pull
+// them, or nil if the task pulls no image
+func (e *Executor) registryProbe() *commontypes.RegistryProbe {
+	switch req := e.task.Msg.(type) {
+	case commontypes.NodeUpgradeJobRequest:
+		return &commontypes.RegistryProbe{
+			Images:         []string{req.Image},
+			TimeoutSeconds: uint32(e.stageTimeout(api.UpgradingState).Seconds()),
+		}
+	case commontypes.ImagePrePullJobRequest:
+		return &commontypes.RegistryProbe{
+			Images:         req.Images,
+			Secret:         req.Secret,
+			TimeoutSeconds: uint32(e.stageTimeout(api.PullingState).Seconds()),
+		}
+	}
+	return nil
+}
+
+// stageTimeout returns how long the node is waited for in the stage of the state, the timeout
+// of the stage if the task sets one, or else the timeout of the task.
+func (e *Executor) stageTimeout(state api.State) time.Duration {
+	if timeout, ok := e.task.StageTimeouts[state]; ok {
+		return time.Duration(timeout) * time.Second
+	}
+	timeoutSecond := uint32(TimeOutSecond)
+	if e.task.TimeOutSeconds != nil && *e.task.TimeOutSeconds != 0 {
+		timeoutSecond = *e.task.TimeOutSeconds
+	}
+	return time.Duration(timeoutSecond) * time.Second
+}
+
+// handelTimeOutJob fails the node if it does not respond in time. The message is
+// dispatched again up to DispatchRetries times before, and it is saved as a dead letter
+// once all the dispatches are unanswered. If AcceptTimeoutSeconds is set, a dispatch
+// which the edge node does not accept in time is considered lost without waiting
+// for the timeout of the stage. In pull mode the node which does not pull the
+// message within the timeout of the stage is failed without dispatching it again.
+func (e *Executor) handelTimeOutJob(index int, msg *model.Message) {
+	lastState := e.nodes[index].State
+	nodeName := e.nodes[index].NodeName
+	timeout := e.stageTimeout(lastState)
+	acceptTimeout := time.Duration(config.Config.AcceptTimeoutSeconds) * time.Second
+	var err error
+	attempts := 1
+	start := time.Now()
+	for {
+		err = e.waitJob(index, lastState, acceptTimeout, timeout)
+		if err == errStopped {
+			return
+		}
+		if err == nil || err == errNotPulled || e.isCancelled() || msg == nil || attempts > int(config.Config.DispatchRetries) {
+			break
+		}
+		klog.Warningf("node %s did not respond to task %s, dispatch it again (%d/%d): %v",
+			nodeName, e.task.Name, attempts, config.Config.DispatchRetries, err)
+		e.receipts.reset(nodeName)
+		util.RecordNodeRetry(e.task.Name, nodeName)
+		executorMachine.dispatch(nodeName, e.task.Name, redispatchMessage(*msg))
+		attempts++
+	}
 	if err != nil {
+		monitor.TaskNodeTimeouts.WithLabelValues(e.task.Type, string(lastState)).Inc()
+		if msg != nil {
+			e.saveDeadLetter(e.nodes[index], *msg, attempts, err)
+		}
 		_, err = e.controller.ReportNodeStatus(e.task.Name, e.nodes[index].NodeName, fsm.Event{
 			Type:   api.EventTimeOut,
 			Action: api.ActionFailure,
-			Msg:    fmt.Sprintf("node task %s execution timeout, %s", lastState, err.Error()),
+			Msg: fmt.Sprintf("node task %s execution timeout, %s%s", lastState, err.Error(),
+				connectivitySummary(connection.GetQuality(nodeName), start, time.Now())),
 		})
 		if err != nil {
 			klog.Warningf(err.Error())
@@ -411,17 +1349,78 @@ func (e *Executor) handelTimeOutJob(index int) {
 	}
 }
 
+// connectivitySummary describes the connection of the node during the window in which it did not
+// respond, it tells connectivity loss apart from failures on the node. It is empty if the node never
+// connected to this cloudhub instance.
+func connectivitySummary(quality connection.Quality, from, to time.Time) string {
+	if !quality.Known {
+		return ""
+	}
+	window := to.Sub(from).Round(time.Second)
+	disconnected := quality.DisconnectedDuration(from, to).Round(time.Second)
+	if disconnected == 0 {
+		return fmt.Sprintf("; node stayed connected during the %s window", window)
+	}
+	return fmt.Sprintf("; node was disconnected %s of the %s window", disconnected, window)
+}
+
+var errNotAccepted = errors.New("the task was never accepted by the edge node")
+
+// errNotPulled is returned when the edge node does not pull the task in time in pull mode
+var errNotPulled = errors.New("the task was never pulled by the edge node")
+
+// errStopped is returned while waiting for a node of a task which was deleted
+var errStopped = errors.New("the task is deleted")
+
+// waitJob waits until the node leaves lastState. It gives up early if the edge node has
+// not accepted the task within acceptTimeout, acceptTimeout 0 disables the check. In pull
+// mode the timeouts start once the node pulled the task, which is waited for up to timeout.
+func (e *Executor) waitJob(index int, lastState api.State, acceptTimeout, timeout time.Duration) error {
+	if err := e.waitPulled(index, lastState, timeout); err != nil {
+		return err
+	}
+	nodeName := e.nodes[index].NodeName
+	start := time.Now()
+	err := wait.Poll(1*time.Second, timeout, func() (bool, error) {
+		select {
+		case <-e.stopChan:
+			return false, errStopped
+		default:
+		}
+		if lastState != e.nodes[index].State || fsm.TaskFinish(e.nodes[index].State) {
+			return true, nil
+		}
+		accepted := e.receipts.accepted(nodeName, lastState)
+		if acceptTimeout > 0 && !accepted && time.Since(start) >= acceptTimeout {
+			return false, errNotAccepted
+		}
+		klog.V(4).Infof("node %s stage is not completed", nodeName)
+		return false, nil
+	})
+	if err == wait.ErrWaitTimeout && e.receipts.accepted(nodeName, lastState) {
+		return fmt.Errorf("the task was accepted by the edge node but not completed, %v", err)
+	}
+	return err
+}
+
 func (w *workers) endJob(job string) (int, error) {
+	w.Lock()
+	defer w.Unlock()
 	index, ok := w.jobs[job]
 	if !ok {
 		return index, fmt.Errorf("end job %s error, job not exist", job)
 	}
-	w.Lock()
 	delete(w.jobs, job)
-	w.Unlock()
 	return index, nil
 }
 
+// isCancelled returns whether the task is cancelled, it is read by the goroutines waiting for the jobs
+func (e *Executor) isCancelled() bool {
+	e.workers.Lock()
+	defer e.workers.Unlock()
+	return e.cancelled
+}
+
 func buildTaskResource(task, taskID, nodeID string) string {
 	resource := strings.Join([]string{task, taskID, "node", nodeID}, constants.ResourceSep)
 	return resource
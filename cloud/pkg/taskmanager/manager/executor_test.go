@@ -0,0 +1,1867 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/connection"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/config"
+	_ "github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/imageprepullcontroller"
+	_ "github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/noderestartcontroller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
+	"github.com/kubeedge/kubeedge/common/constants"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	cloudcorev1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+	"github.com/kubeedge/kubeedge/pkg/client/clientset/versioned/fake"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+func TestBackfillNodeStatus(t *testing.T) {
+	nodeStatus := []v1alpha1.TaskStatus{
+		{NodeName: "edge-1", State: api.TaskSuccessful},
+		{NodeName: "edge-2", State: api.NodeUpgrading},
+	}
+	nodes := []v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "edge-2"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "edge-3"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "edge-4"}},
+	}
+
+	status, backfilled := backfillNodeStatus(nodeStatus, nodes)
+	expected := []v1alpha1.TaskStatus{
+		{NodeName: "edge-1", State: api.TaskSuccessful},
+		{NodeName: "edge-2", State: api.NodeUpgrading},
+		{NodeName: "edge-3"},
+		{NodeName: "edge-4"},
+	}
+	if !reflect.DeepEqual(status, expected) {
+		t.Errorf("expected status %v, got %v", expected, status)
+	}
+	if !reflect.DeepEqual(backfilled, []string{"edge-3", "edge-4"}) {
+		t.Errorf("expected backfilled nodes [edge-3 edge-4], got %v", backfilled)
+	}
+
+	_, backfilled = backfillNodeStatus(expected, nodes)
+	if len(backfilled) != 0 {
+		t.Errorf("expected no backfilled nodes, got %v", backfilled)
+	}
+}
+
+func TestResumeNodes(t *testing.T) {
+	nodeStatus := []v1alpha1.TaskStatus{
+		{NodeName: "edge-1", State: api.TaskSuccessful},
+		{NodeName: "edge-2"},
+		{NodeName: "edge-3", State: api.NodeUpgrading},
+		{NodeName: "edge-4"},
+		{NodeName: "edge-5", State: api.TaskInit},
+	}
+
+	resumed := resumeNodes(nodeStatus, map[string]bool{"edge-1": true, "edge-4": true})
+	if !reflect.DeepEqual(resumed, []string{"edge-3", "edge-4"}) {
+		t.Errorf("expected resumed nodes [edge-3 edge-4], got %v", resumed)
+	}
+	var order []string
+	for _, node := range nodeStatus {
+		order = append(order, node.NodeName)
+	}
+	expected := []string{"edge-3", "edge-4", "edge-1", "edge-2", "edge-5"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected order %v, got %v", expected, order)
+	}
+}
+
+func TestPendingTasks(t *testing.T) {
+	pending := &pendingTasks{tasks: map[string][]pendingTask{}}
+	pending.add("edge-1", "task-1", *model.NewMessage("msg-1"))
+	pending.add("edge-1", "task-2", *model.NewMessage("msg-2"))
+	pending.add("edge-2", "task-1", *model.NewMessage("msg-3"))
+
+	pending.remove("task-1")
+	if _, ok := pending.tasks["edge-2"]; ok {
+		t.Errorf("expected pending tasks of edge-2 removed")
+	}
+
+	if !pending.holds("edge-1", "task-2") || pending.holds("edge-1", "task-1") {
+		t.Errorf("expected only task-2 held for edge-1, got %v", pending.tasks["edge-1"])
+	}
+	tasks := pending.take("edge-1")
+	if len(tasks) != 1 || tasks[0].taskID != "task-2" || tasks[0].msg.GetParentID() != "msg-2" {
+		t.Errorf("expected pending task task-2 of edge-1, got %v", tasks)
+	}
+	if tasks = pending.take("edge-1"); len(tasks) != 0 {
+		t.Errorf("expected no pending task after taken, got %v", tasks)
+	}
+}
+
+func TestWaitPulled(t *testing.T) {
+	mode, machine, interval := config.Config.DispatchMode, executorMachine, pullWaitInterval
+	defer func() {
+		config.Config.DispatchMode, executorMachine, pullWaitInterval = mode, machine, interval
+	}()
+	config.Config.DispatchMode = cloudcorev1alpha1.TaskDispatchModePull
+	executorMachine = &ExecutorMachine{pending: &pendingTasks{tasks: map[string][]pendingTask{}}}
+	pullWaitInterval = 10 * time.Millisecond
+	executorMachine.pending.add("edge-1", "upgrade-1", *model.NewMessage(""))
+	e := &Executor{
+		task:     util.TaskMessage{Type: util.TaskUpgrade, Name: "upgrade-1"},
+		nodes:    []v1alpha1.TaskStatus{{NodeName: "edge-1", State: api.TaskInit}},
+		stopChan: make(chan struct{}),
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- e.waitPulled(0, api.TaskInit, time.Hour)
+	}()
+	select {
+	case err := <-done:
+		t.Fatalf("expected waiting until the node pulls the task, returned %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	executorMachine.pending.take("edge-1")
+	if err := <-done; err != nil {
+		t.Errorf("expected the task to be pulled, got %v", err)
+	}
+
+	executorMachine.pending.add("edge-1", "upgrade-1", *model.NewMessage(""))
+	if err := e.waitPulled(0, api.TaskInit, 30*time.Millisecond); err != errNotPulled {
+		t.Errorf("expected waiting to time out, got %v", err)
+	}
+	if executorMachine.pending.holds("edge-1", "upgrade-1") {
+		t.Errorf("expected the message not pulled in time to be dropped")
+	}
+
+	// the node which does not pull the task in time is failed
+	timeout := uint32(1)
+	c := &statusController{BaseController: &controller.BaseController{}}
+	e.task.TimeOutSeconds = &timeout
+	e.controller = c
+	e.receipts = &receipts{states: map[string]api.State{}}
+	executorMachine.pending.add("edge-1", "upgrade-1", *model.NewMessage(""))
+	e.handelTimeOutJob(0, nil)
+	if !reflect.DeepEqual(c.reported, []string{"edge-1"}) {
+		t.Errorf("expected the node to time out, got %v", c.reported)
+	}
+
+	executorMachine.pending.add("edge-1", "upgrade-1", *model.NewMessage(""))
+	close(e.stopChan)
+	if err := e.waitPulled(0, api.TaskInit, time.Hour); err != errStopped {
+		t.Errorf("expected waiting to stop with the task, got %v", err)
+	}
+}
+
+func TestPullTasks(t *testing.T) {
+	mode := config.Config.DispatchMode
+	defer func() {
+		config.Config.DispatchMode = mode
+	}()
+	config.Config.DispatchMode = cloudcorev1alpha1.TaskDispatchModePull
+	em := &ExecutorMachine{
+		downStreamChan: make(chan model.Message, 1),
+		pending:        &pendingTasks{tasks: map[string][]pendingTask{}},
+	}
+	em.outbox = newOutbox(nil, em.queueDownstream)
+	msg := model.NewMessage("").SetResourceOperation(buildTaskResource(util.TaskUpgrade, "upgrade", "edge-1"), util.TaskUpgrade)
+	em.dispatch("edge-1", "upgrade", *msg)
+	select {
+	case sent := <-em.downStreamChan:
+		t.Fatalf("expected the message to be held until the node pulls it, sent %v", sent)
+	default:
+	}
+
+	// the replica which does not run the executors holds no message
+	if _, err := em.PullTasks("edge-1"); err != util.ErrNotLeader {
+		t.Errorf("expected pulling from a follower to fail with %v, got %v", util.ErrNotLeader, err)
+	}
+	em.leading.Store(true)
+	msgs, err := em.PullTasks("edge-1")
+	if err != nil {
+		t.Fatalf("failed to pull tasks: %v", err)
+	}
+	if len(msgs) != 1 || msgs[0].GetID() != msg.GetID() {
+		t.Errorf("expected the held message to be pulled, got %v", msgs)
+	}
+	if msgs, _ = em.PullTasks("edge-1"); len(msgs) != 0 {
+		t.Errorf("expected no message pulled twice, got %v", msgs)
+	}
+	select {
+	case sent := <-em.downStreamChan:
+		t.Errorf("expected the pulled message not to be sent through the edgehub connection, sent %v", sent)
+	default:
+	}
+}
+
+func TestSortNodesByConnection(t *testing.T) {
+	now := time.Now()
+	qualities := map[string]connection.Quality{
+		"flapping":     {Known: true, Connected: true, Disconnects: []time.Time{now, now, now}},
+		"disconnected": {Known: true, Connected: false},
+		"slow":         {Known: true, Connected: true, RTT: 2 * time.Second},
+		"healthy":      {Known: true, Connected: true, RTT: 10 * time.Millisecond},
+	}
+	nodes := []v1alpha1.TaskStatus{
+		{NodeName: "flapping"},
+		{NodeName: "slow"},
+		{NodeName: "disconnected"},
+		{NodeName: "unknown"},
+		{NodeName: "healthy"},
+		{NodeName: "started", State: api.NodeUpgrading},
+	}
+	pacing := &cloudcorev1alpha1.TaskManagerDispatchPacing{Enable: true, FlappingDisconnects: 3, SlowRTTMilliseconds: 1000}
+
+	sortNodesByConnection(nodes, pacing, func(nodeID string) connection.Quality {
+		return qualities[nodeID]
+	})
+	var names []string
+	for _, node := range nodes {
+		names = append(names, node.NodeName)
+	}
+	expected := []string{"started", "unknown", "healthy", "slow", "flapping", "disconnected"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected order %v, got %v", expected, names)
+	}
+
+	// the order of a seeded task is the recorded one, it is not paced
+	seed := int64(7)
+	seeded := []v1alpha1.TaskStatus{{NodeName: "disconnected"}, {NodeName: "healthy"}}
+	paceNodes(util.TaskMessage{OrderingSeed: &seed}, seeded, pacing, func(nodeID string) connection.Quality {
+		return qualities[nodeID]
+	})
+	if seeded[0].NodeName != "disconnected" {
+		t.Errorf("expected the seeded order to be kept, got %v", seeded)
+	}
+	paceNodes(util.TaskMessage{}, seeded, pacing, func(nodeID string) connection.Quality {
+		return qualities[nodeID]
+	})
+	if seeded[0].NodeName != "healthy" {
+		t.Errorf("expected the disconnected node to be paced, got %v", seeded)
+	}
+}
+
+func TestSortNodesByReadiness(t *testing.T) {
+	now := time.Now()
+	node := func(name string, annotations map[string]string, status v1.NodeStatus) v1.Node {
+		return v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations}, Status: status}
+	}
+	nodeList := []v1.Node{
+		node("low-battery", map[string]string{util.NodeBatteryLevelAnnotationKey: "10"}, v1.NodeStatus{}),
+		node("disk-pressure", nil, v1.NodeStatus{Conditions: []v1.NodeCondition{
+			{Type: v1.NodeDiskPressure, Status: v1.ConditionTrue},
+		}}),
+		node("ready", map[string]string{util.NodeBatteryLevelAnnotationKey: "100"}, v1.NodeStatus{}),
+		node("flapping", nil, v1.NodeStatus{}),
+		node("failed", nil, v1.NodeStatus{}),
+	}
+	qualities := map[string]connection.Quality{
+		"flapping": {Known: true, Connected: false, Disconnects: []time.Time{now, now, now}},
+	}
+	failures := map[string]int{"failed": 1}
+	nodes := make([]v1alpha1.TaskStatus, len(nodeList))
+	for i, n := range nodeList {
+		nodes[i] = v1alpha1.TaskStatus{NodeName: n.Name}
+	}
+
+	sortNodesByReadiness(nodes, nodeList, func(nodeID string) connection.Quality {
+		return qualities[nodeID]
+	}, func(nodeName string) int {
+		return failures[nodeName]
+	})
+	var names []string
+	for _, node := range nodes {
+		names = append(names, node.NodeName)
+	}
+	expected := []string{"ready", "failed", "low-battery", "disk-pressure", "flapping"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected order %v, got %v", expected, names)
+	}
+
+	history := &failureHistory{failures: map[string][]time.Time{}}
+	history.record("edge-1", now.Add(-2*failureWindow))
+	history.record("edge-1", now)
+	if count := history.count("edge-1"); count != 1 {
+		t.Errorf("expected 1 recent failure, got %d", count)
+	}
+}
+
+func TestDispatchJitter(t *testing.T) {
+	if jitter := dispatchJitter(0); jitter != 0 {
+		t.Errorf("expected no jitter if unset, got %s", jitter)
+	}
+	for i := 0; i < 100; i++ {
+		if jitter := dispatchJitter(2); jitter < 0 || jitter >= 2*time.Second {
+			t.Fatalf("expected jitter below 2s, got %s", jitter)
+		}
+	}
+}
+
+func TestStageTimeout(t *testing.T) {
+	check, upgrade, rollback, timeout := uint32(60), uint32(600), uint32(0), uint32(120)
+	e := &Executor{task: util.TaskMessage{
+		TimeOutSeconds: &timeout,
+		StageTimeouts: util.UpgradeStageTimeouts(&v1alpha1.StageTimeouts{
+			Check:    &check,
+			Upgrade:  &upgrade,
+			Rollback: &rollback,
+		}),
+	}}
+	for state, expected := range map[api.State]time.Duration{
+		api.TaskChecking:     time.Minute,
+		api.BackingUpState:   2 * time.Minute,
+		api.UpgradingState:   10 * time.Minute,
+		api.RollingBackState: 2 * time.Minute,
+	} {
+		if actual := e.stageTimeout(state); actual != expected {
+			t.Errorf("expected timeout %s of stage %s, got %s", expected, state, actual)
+		}
+	}
+
+	e.task.TimeOutSeconds = nil
+	if actual := e.stageTimeout(api.BackingUpState); actual != TimeOutSecond*time.Second {
+		t.Errorf("expected the default timeout, got %s", actual)
+	}
+}
+
+func TestRegistryProbe(t *testing.T) {
+	upgrade, timeout := uint32(600), uint32(120)
+	e := &Executor{task: util.TaskMessage{
+		TimeOutSeconds: &timeout,
+		StageTimeouts:  util.UpgradeStageTimeouts(&v1alpha1.StageTimeouts{Upgrade: &upgrade}),
+		Msg:            commontypes.NodeUpgradeJobRequest{Image: "kubeedge/installation-package:v1.19.0"},
+	}}
+	expected := &commontypes.RegistryProbe{Images: []string{"kubeedge/installation-package:v1.19.0"}, TimeoutSeconds: 600}
+	if probe := e.registryProbe(); !reflect.DeepEqual(probe, expected) {
+		t.Errorf("expected probe %+v, got %+v", expected, probe)
+	}
+
+	e.task.Msg = commontypes.ImagePrePullJobRequest{Images: []string{"nginx", "redis"}, Secret: "default/registry"}
+	expected = &commontypes.RegistryProbe{Images: []string{"nginx", "redis"}, Secret: "default/registry", TimeoutSeconds: 120}
+	if probe := e.registryProbe(); !reflect.DeepEqual(probe, expected) {
+		t.Errorf("expected probe %+v, got %+v", expected, probe)
+	}
+
+	e.task.Msg = commontypes.SupportBundleJobRequest{}
+	if probe := e.registryProbe(); probe != nil {
+		t.Errorf("expected no probe, got %+v", probe)
+	}
+}
+
+func TestRedispatchMessage(t *testing.T) {
+	msg := model.NewMessage("").BuildRouter("taskmanager", "taskmanager", "task/upgrade/node/edge-1", "upgrade").
+		FillBody(commontypes.NodeTaskRequest{TaskID: "upgrade", State: string(api.NodeUpgrading)})
+
+	retry := redispatchMessage(*msg)
+	if retry.GetID() == msg.GetID() {
+		t.Errorf("expected a new message ID")
+	}
+	if retry.GetResource() != msg.GetResource() {
+		t.Errorf("expected resource %s, got %s", msg.GetResource(), retry.GetResource())
+	}
+	req := retry.GetContent().(commontypes.NodeTaskRequest)
+	if !req.Reconcile {
+		t.Errorf("expected the redispatched message to be reconciled")
+	}
+	if msg.GetContent().(commontypes.NodeTaskRequest).Reconcile {
+		t.Errorf("expected the original message to be unchanged")
+	}
+
+	decoded := model.NewMessage("").FillBody(map[string]interface{}{"TaskID": "upgrade"})
+	redecoded := redispatchMessage(*decoded)
+	content := redecoded.GetContent().(map[string]interface{})
+	if content["Reconcile"] != true {
+		t.Errorf("expected the decoded message to be reconciled")
+	}
+	if _, ok := decoded.GetContent().(map[string]interface{})["Reconcile"]; ok {
+		t.Errorf("expected the decoded message to be unchanged")
+	}
+}
+
+func TestAccept(t *testing.T) {
+	executor := &Executor{receipts: &receipts{states: map[string]api.State{}}}
+	executorMachine = &ExecutorMachine{executors: map[string]*Executor{"upgrade::job": executor}}
+
+	executorMachine.Accept("upgrade", "job", "edge-1", api.NodeUpgrading)
+	executorMachine.Accept("prepull", "job", "edge-2", api.NodeUpgrading)
+	if !executor.receipts.accepted("edge-1", api.NodeUpgrading) {
+		t.Errorf("expected edge-1 to accept the upgrading state")
+	}
+	if executor.receipts.accepted("edge-1", api.TaskChecking) {
+		t.Errorf("expected edge-1 not to accept the checking state")
+	}
+	if executor.receipts.accepted("edge-2", api.NodeUpgrading) {
+		t.Errorf("expected the receipt of another task to be ignored")
+	}
+	executor.receipts.reset("edge-1")
+	if executor.receipts.accepted("edge-1", api.NodeUpgrading) {
+		t.Errorf("expected the receipt of edge-1 to be reset")
+	}
+}
+
+func TestStaleTasks(t *testing.T) {
+	// a task type registered out of tree, its task objects are served by the client of NodeRestartJobs
+	util.RegisterTaskObjects("firmware", util.NewTypedTaskObjects(v1alpha1.Resource("noderestartjobs"),
+		func(crdClient crdClientset.Interface) util.TypedTaskClient[*v1alpha1.NodeRestartJob, *v1alpha1.NodeRestartJobList] {
+			return crdClient.OperationsV1alpha1().NodeRestartJobs()
+		},
+		func(meta metav1.ObjectMeta, spec v1alpha1.NodeRestartJobSpec) *v1alpha1.NodeRestartJob {
+			return &v1alpha1.NodeRestartJob{ObjectMeta: meta, Spec: spec}
+		},
+		func(job *v1alpha1.NodeRestartJob) util.TaskObjectStatus {
+			return util.JobObjectStatus(job.Status.CommonJobStatus, job.Status)
+		},
+		func(job *v1alpha1.NodeRestartJob) *int32 {
+			return job.Spec.TTLSecondsAfterFinished
+		}))
+	crdClient := fake.NewSimpleClientset(
+		&v1alpha1.NodeUpgradeJob{ObjectMeta: metav1.ObjectMeta{Name: "upgrade-1"}},
+		&v1alpha1.ImagePrePullJob{ObjectMeta: metav1.ObjectMeta{Name: "prepull-1"}},
+		&v1alpha1.NodeRestartJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "restart-1"},
+			Status:     v1alpha1.NodeRestartJobStatus{CommonJobStatus: v1alpha1.CommonJobStatus{State: api.TaskSuccessful}},
+		},
+		&v1alpha1.NodeRestartJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "restart-2"},
+			Status:     v1alpha1.NodeRestartJobStatus{CommonJobStatus: v1alpha1.CommonJobStatus{State: api.TaskChecking}},
+		},
+	)
+	tasks := []commontypes.NodeTaskKey{
+		{Type: "upgrade", TaskID: "upgrade-1"},
+		{Type: "upgrade", TaskID: "upgrade-2"},
+		{Type: "prepull", TaskID: "prepull-1"},
+		{Type: "prepull", TaskID: "prepull-2"},
+		{Type: "restart", TaskID: "restart-1"},
+		{Type: "restart", TaskID: "restart-2"},
+		{Type: "firmware", TaskID: "restart-1"},
+		{Type: "firmware", TaskID: "restart-2"},
+		{Type: "custom", TaskID: "custom-1"},
+	}
+	expected := []commontypes.NodeTaskKey{
+		{Type: "upgrade", TaskID: "upgrade-2"},
+		{Type: "prepull", TaskID: "prepull-2"},
+		{Type: "restart", TaskID: "restart-1"},
+		{Type: "firmware", TaskID: "restart-1"},
+	}
+	if stale := staleTasks(crdClient, tasks); !reflect.DeepEqual(stale, expected) {
+		t.Errorf("expected stale tasks %v, got %v", expected, stale)
+	}
+}
+
+func TestConnectivitySummary(t *testing.T) {
+	from := time.Now().Add(-15 * time.Minute)
+	to := from.Add(15 * time.Minute)
+	tests := []struct {
+		name     string
+		quality  connection.Quality
+		expected string
+	}{
+		{
+			name:     "unknown node",
+			quality:  connection.Quality{},
+			expected: "",
+		},
+		{
+			name:     "stayed connected",
+			quality:  connection.Quality{Known: true, Connected: true, Connects: []time.Time{from.Add(-time.Minute)}},
+			expected: "; node stayed connected during the 15m0s window",
+		},
+		{
+			name: "reconnected twice",
+			quality: connection.Quality{
+				Known:       true,
+				Connected:   true,
+				Connects:    []time.Time{from.Add(-time.Minute), from.Add(5 * time.Minute), from.Add(14 * time.Minute)},
+				Disconnects: []time.Time{from.Add(time.Minute), from.Add(8 * time.Minute)},
+			},
+			expected: "; node was disconnected 10m0s of the 15m0s window",
+		},
+		{
+			name: "disconnected before the window",
+			quality: connection.Quality{
+				Known:       true,
+				Disconnects: []time.Time{from.Add(-time.Minute)},
+			},
+			expected: "; node was disconnected 15m0s of the 15m0s window",
+		},
+		{
+			name: "connected in the window",
+			quality: connection.Quality{
+				Known:     true,
+				Connected: true,
+				Connects:  []time.Time{from.Add(6 * time.Minute)},
+			},
+			expected: "; node was disconnected 6m0s of the 15m0s window",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := connectivitySummary(test.quality, from, to); got != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, got)
+			}
+		})
+	}
+}
+
+// statusController keeps the node status of a task in memory
+type statusController struct {
+	*controller.BaseController
+	nodeStatus []v1alpha1.TaskStatus
+	// reported are the nodes of the node events reported in order
+	reported []string
+	// taskEvents are the types of the task events reported in order
+	taskEvents []string
+	// members are the nodes resolved for the task
+	members []v1.Node
+	// plan is the plan recorded for the task
+	plan *v1alpha1.ExecutionPlan
+	// window is the maintenance window recorded for the task
+	window *v1alpha1.MaintenanceWindowStatus
+}
+
+func (c *statusController) ReportNodeStatus(_, nodeName string, _ fsm.Event) (api.State, error) {
+	c.reported = append(c.reported, nodeName)
+	return "", nil
+}
+
+func (c *statusController) ReportTaskStatus(_ string, event fsm.Event) (api.State, error) {
+	c.taskEvents = append(c.taskEvents, event.Type)
+	return "", nil
+}
+
+func (c *statusController) GetNodeStatus(string) ([]v1alpha1.TaskStatus, error) {
+	return append([]v1alpha1.TaskStatus(nil), c.nodeStatus...), nil
+}
+
+func (c *statusController) UpdateNodeStatus(_ string, nodeStatus []v1alpha1.TaskStatus) error {
+	c.nodeStatus = nodeStatus
+	return nil
+}
+
+func (c *statusController) ValidateNode(util.TaskMessage) ([]v1.Node, []v1alpha1.ExcludedNode) {
+	return c.members, nil
+}
+
+func (c *statusController) RecordPlan(_ string, plan v1alpha1.ExecutionPlan) error {
+	c.plan = &plan
+	return nil
+}
+
+func (c *statusController) RecordMaintenanceWindow(_ string, window *v1alpha1.MaintenanceWindowStatus) error {
+	c.window = window
+	return nil
+}
+
+func TestRemoveNode(t *testing.T) {
+	nodes := []v1alpha1.TaskStatus{
+		{NodeName: "edge-1", State: api.TaskChecking},
+		{NodeName: "edge-2", State: api.TaskSuccessful},
+		{NodeName: "edge-3", State: api.TaskInit},
+	}
+	c := &statusController{BaseController: &controller.BaseController{}, nodeStatus: append([]v1alpha1.TaskStatus(nil), nodes...)}
+	e := &Executor{
+		task:       util.TaskMessage{Type: util.TaskUpgrade, Name: "upgrade"},
+		nodes:      nodes,
+		controller: c,
+		workers:    workers{number: 1, jobs: map[string]int{"edge-1": 0}},
+	}
+
+	if e.removeNode("edge-4") || e.removeNode("edge-2") {
+		t.Errorf("nodes not in the task or finished must not be removed")
+	}
+	if !e.removeNode("edge-1") {
+		t.Fatalf("expected running node edge-1 to be removed")
+	}
+	if len(e.workers.jobs) != 0 {
+		t.Errorf("expected the worker of edge-1 to be released, got %v", e.workers.jobs)
+	}
+	if e.nodes[0].State != api.TaskNodeRemoved || c.nodeStatus[0].State != api.TaskNodeRemoved {
+		t.Errorf("expected edge-1 to be NodeRemoved, got %s and %s", e.nodes[0].State, c.nodeStatus[0].State)
+	}
+	if e.removeNode("edge-1") {
+		t.Errorf("a removed node must not be removed twice")
+	}
+}
+
+func TestResizeWorkers(t *testing.T) {
+	maxUnavailable := intstr.FromString("50%")
+	nodes := []v1alpha1.TaskStatus{
+		{NodeName: "edge-1", State: api.TaskChecking},
+		{NodeName: "edge-2", State: api.TaskInit},
+		{NodeName: "edge-3", State: api.TaskInit},
+		{NodeName: "edge-4", State: api.TaskInit},
+	}
+	c := &statusController{BaseController: &controller.BaseController{}, nodeStatus: append([]v1alpha1.TaskStatus(nil), nodes...)}
+	e := &Executor{
+		task:       util.TaskMessage{Type: util.TaskUpgrade, Name: "upgrade", MaxUnavailable: &maxUnavailable},
+		nodes:      nodes,
+		controller: c,
+		workers:    workers{number: 1, jobs: map[string]int{"edge-1": 0}},
+	}
+
+	e.resizeWorkers()
+	if e.workers.number != 2 {
+		t.Errorf("expected 2 of the 4 nodes to run at the same time, got %d", e.workers.number)
+	}
+	e.removeNode("edge-2")
+	e.removeNode("edge-3")
+	e.resizeWorkers()
+	if e.workers.number != 1 {
+		t.Errorf("expected 1 of the 2 nodes left to run at the same time, got %d", e.workers.number)
+	}
+}
+
+func TestPublishQueue(t *testing.T) {
+	nodes := []v1alpha1.TaskStatus{
+		{NodeName: "edge-1"},
+		{NodeName: "edge-2"},
+		{NodeName: "edge-3"},
+		{NodeName: "edge-4"},
+	}
+	c := &statusController{BaseController: &controller.BaseController{}, nodeStatus: append([]v1alpha1.TaskStatus(nil), nodes...)}
+	e := &Executor{
+		task:       util.TaskMessage{Type: util.TaskUpgrade, Name: "upgrade"},
+		nodes:      nodes,
+		controller: c,
+		workers:    workers{number: 2, jobs: map[string]int{"edge-1": 0, "edge-2": 1}},
+	}
+
+	e.publishQueue(2)
+	if c.nodeStatus[0].QueuePosition != 0 || c.nodeStatus[2].QueuePosition != 1 || c.nodeStatus[3].QueuePosition != 2 {
+		t.Errorf("unexpected queue positions %+v", c.nodeStatus)
+	}
+	if c.nodeStatus[2].EstimatedStartTime != "" {
+		t.Errorf("expected no estimate before a node finished, got %s", c.nodeStatus[2].EstimatedStartTime)
+	}
+
+	e.queue.elapsed, e.queue.finished = 2*time.Minute, 1
+	now := time.Now()
+	if start := e.queue.estimate(3, 2, now); !start.Equal(now.Add(4 * time.Minute)) {
+		t.Errorf("expected the third waiting node to start in the second wave, got %s", start.Sub(now))
+	}
+	delete(e.workers.jobs, "edge-1")
+	e.workers.jobs["edge-3"] = 2
+	e.publishQueue(3)
+	if c.nodeStatus[2].QueuePosition != 0 || c.nodeStatus[3].QueuePosition != 1 || c.nodeStatus[3].EstimatedStartTime == "" {
+		t.Errorf("unexpected queue after dispatch %+v", c.nodeStatus)
+	}
+}
+
+func TestPause(t *testing.T) {
+	e := &Executor{
+		task:       util.TaskMessage{Type: util.TaskUpgrade, Name: "upgrade"},
+		nodes:      []v1alpha1.TaskStatus{{NodeName: "edge-1"}, {NodeName: "edge-2"}},
+		controller: &statusController{BaseController: &controller.BaseController{}},
+		workers:    workers{number: 1, jobs: map[string]int{}},
+		paused:     true,
+	}
+
+	if index, err := e.initWorker(0); err != nil || index != 0 || len(e.workers.jobs) != 0 {
+		t.Errorf("expected a paused task not to dispatch, got index %d, jobs %v, err %v", index, e.workers.jobs, err)
+	}
+	if e.pause(true) {
+		t.Errorf("expected pausing a paused task to be a no-op")
+	}
+	if !e.pause(false) || e.paused {
+		t.Errorf("expected the task to be resumed")
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	config.Config.ReadOnly = true
+	defer func() { config.Config.ReadOnly = false }()
+	timeout := int32(1)
+	e := &Executor{
+		task:       util.TaskMessage{Type: util.TaskUpgrade, Name: "upgrade", Preflight: &v1alpha1.PreflightSpec{TimeoutSeconds: &timeout}},
+		nodes:      []v1alpha1.TaskStatus{{NodeName: "edge-1"}, {NodeName: "edge-2"}},
+		controller: &statusController{BaseController: &controller.BaseController{}},
+		workers:    workers{number: 2, jobs: map[string]int{}},
+	}
+	executorMachine = &ExecutorMachine{
+		downStreamChan: make(chan model.Message, 2),
+		executors:      map[string]*Executor{"upgrade::upgrade": e},
+	}
+	executorMachine.outbox = newOutbox(nil, executorMachine.queueDownstream)
+
+	if !e.preflight() || len(executorMachine.downStreamChan) != 0 {
+		t.Errorf("expected the nodes not to be probed, got %d probes", len(executorMachine.downStreamChan))
+	}
+	if index, err := e.initWorker(0); err != nil || index != 0 || len(e.workers.jobs) != 0 {
+		t.Errorf("expected a read-only TaskManager not to dispatch, got index %d, jobs %v, err %v", index, e.workers.jobs, err)
+	}
+}
+
+func TestRefreshMembers(t *testing.T) {
+	tolerate := intstr.FromString("50%")
+	c := &statusController{BaseController: &controller.BaseController{}}
+	e := &Executor{
+		task:       util.TaskMessage{Type: util.TaskUpgrade, Name: "upgrade", NodeGroups: []string{"hangzhou"}, FailureTolerate: &tolerate},
+		nodes:      []v1alpha1.TaskStatus{{NodeName: "edge-1"}, {NodeName: "edge-2"}},
+		controller: c,
+		workers:    workers{number: 1, jobs: map[string]int{}},
+		paused:     true,
+	}
+
+	if _, err := e.initWorker(0); err != nil || !e.membersStale {
+		t.Fatalf("expected the members of the paused task to be resolved again, err %v", err)
+	}
+	c.members = []v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "edge-2"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "edge-3"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "edge-4"}},
+	}
+	if !e.refreshMembers() || e.membersStale {
+		t.Fatalf("expected the members of the task to change")
+	}
+	want := []v1alpha1.TaskStatus{{NodeName: "edge-2"}, {NodeName: "edge-3"}, {NodeName: "edge-4"}}
+	if !reflect.DeepEqual(e.nodes, want) || !reflect.DeepEqual(c.nodeStatus, want) {
+		t.Errorf("expected the nodes %v, got %v recorded as %v", want, e.nodes, c.nodeStatus)
+	}
+	if e.maxFailedNodes != 1.5 {
+		t.Errorf("expected the failure tolerance to follow the nodes, got %v", e.maxFailedNodes)
+	}
+	if e.refreshMembers() {
+		t.Errorf("expected the same members not to change the task")
+	}
+
+	e.nodes[0].State = api.TaskChecking
+	e.holdMembers()
+	if e.membersStale {
+		t.Errorf("expected the members not to change once a node is dispatched")
+	}
+}
+
+func TestCeiling(t *testing.T) {
+	c := &ceiling{tasks: map[*Executor]int{}}
+	a := &Executor{task: util.TaskMessage{Name: "a"}, slotChan: make(chan struct{}, 1)}
+	b := &Executor{task: util.TaskMessage{Name: "b"}, slotChan: make(chan struct{}, 1)}
+
+	if !c.acquire(a, 0) || c.running != 0 {
+		t.Fatalf("expected no ceiling to be enforced with limit 0")
+	}
+	if !c.acquire(a, 2) || !c.acquire(a, 2) {
+		t.Fatalf("expected the task to take the free slots")
+	}
+	if c.acquire(a, 2) || c.acquire(b, 2) {
+		t.Fatalf("expected the tasks to wait once the ceiling is reached")
+	}
+
+	c.release(a)
+	select {
+	case <-a.slotChan:
+	default:
+		t.Fatalf("expected the first waiting task to be notified")
+	}
+	if c.acquire(b, 2) {
+		t.Errorf("expected the task to wait for its turn")
+	}
+	if !c.acquire(a, 2) {
+		t.Fatalf("expected the task to take the free slot in its turn")
+	}
+	if c.acquire(a, 2) || c.indexOf(a) != 1 {
+		t.Errorf("expected the task to wait behind the other tasks for the next slot, waiting %d", c.indexOf(a))
+	}
+
+	c.forget(a)
+	if c.running != 0 || c.indexOf(a) >= 0 {
+		t.Errorf("expected the slots of the stopped task to be freed, %d running", c.running)
+	}
+	select {
+	case <-b.slotChan:
+	default:
+		t.Fatalf("expected the next waiting task to be notified")
+	}
+	if !c.acquire(b, 2) || len(c.waiting) != 0 {
+		t.Errorf("expected the task to take the freed slot")
+	}
+}
+
+func TestCancelDrain(t *testing.T) {
+	c := &statusController{BaseController: &controller.BaseController{}}
+	e := &Executor{
+		task: util.TaskMessage{Type: util.TaskUpgrade, Name: "upgrade"},
+		nodes: []v1alpha1.TaskStatus{
+			{NodeName: "edge-1", State: api.NodeUpgrading},
+			{NodeName: "edge-2"},
+			{NodeName: "edge-3", State: api.TaskSuccessful},
+		},
+		controller: c,
+		workers:    workers{number: 1, jobs: map[string]int{"edge-1": 0}},
+	}
+
+	if e.cancel(true) {
+		t.Fatalf("expected the cancellation to wait for the running node")
+	}
+	if !e.cancelled || !e.drain || !e.workers.shuttingDown {
+		t.Errorf("expected the task to stop dispatching and drain")
+	}
+	if !reflect.DeepEqual(c.reported, []string{"edge-2"}) {
+		t.Errorf("expected only the idle node to be cancelled, got %v", c.reported)
+	}
+	if e.cancel(true) {
+		t.Errorf("expected the cancellation to be requested once")
+	}
+}
+
+func TestCancelRunningJobs(t *testing.T) {
+	timeout := uint32(1)
+	c := &statusController{BaseController: &controller.BaseController{}}
+	e := &Executor{
+		task:       util.TaskMessage{Type: util.TaskUpgrade, Name: "upgrade", TimeOutSeconds: &timeout},
+		nodes:      []v1alpha1.TaskStatus{{NodeName: "edge-1", State: api.NodeUpgrading}},
+		controller: c,
+		workers:    workers{number: 1, jobs: map[string]int{"edge-1": 0}},
+		receipts:   &receipts{states: map[string]api.State{}},
+		stopChan:   make(chan struct{}),
+	}
+
+	// the running job is waited for by its own goroutine while the task is cancelled
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		e.handelTimeOutJob(0, nil)
+	}()
+	if e.cancel(true) {
+		t.Errorf("expected the cancellation to wait for the running node")
+	}
+	if !e.workers.stopped() || e.workers.running() != 1 {
+		t.Errorf("expected the workers to take no new job while the node is running")
+	}
+	<-done
+	if !reflect.DeepEqual(c.reported, []string{"edge-1"}) {
+		t.Errorf("expected the running node to time out, got %v", c.reported)
+	}
+}
+
+func TestExpire(t *testing.T) {
+	c := &statusController{BaseController: &controller.BaseController{}}
+	executorMachine = &ExecutorMachine{downStreamChan: make(chan model.Message, 1)}
+	executorMachine.outbox = newOutbox(nil, executorMachine.queueDownstream)
+	e := &Executor{
+		task: util.TaskMessage{Type: util.TaskUpgrade, Name: "upgrade", ActiveDeadline: time.Minute},
+		nodes: []v1alpha1.TaskStatus{
+			{NodeName: "edge-1", State: api.NodeUpgrading},
+			{NodeName: "edge-2"},
+			{NodeName: "edge-3", State: api.TaskSuccessful},
+		},
+		controller: c,
+		workers:    workers{number: 1, jobs: map[string]int{"edge-1": 0}},
+	}
+
+	if e.expire() {
+		t.Fatalf("expected the task to wait for the running node to cancel")
+	}
+	if !e.expired || !e.cancelled || e.drain || !e.workers.shuttingDown {
+		t.Errorf("expected the task to stop dispatching and cancel the running node")
+	}
+	if !reflect.DeepEqual(c.reported, []string{"edge-1", "edge-2"}) {
+		t.Errorf("expected the unfinished nodes to be cancelled, got %v", c.reported)
+	}
+	select {
+	case msg := <-executorMachine.downStreamChan:
+		if msg.GetResource() != buildTaskResource(util.TaskUpgrade, "upgrade", "edge-1") {
+			t.Errorf("expected the cancellation to be dispatched to edge-1, got %s", msg.GetResource())
+		}
+	default:
+		t.Errorf("expected the cancellation to be dispatched to the running node")
+	}
+	if e.expire() || e.cancel(false) {
+		t.Errorf("expected the expired task not to be stopped again")
+	}
+}
+
+func TestRevert(t *testing.T) {
+	c := &statusController{BaseController: &controller.BaseController{}}
+	e := &Executor{
+		task: util.TaskMessage{Type: util.TaskUpgrade, Name: "upgrade", RollbackPolicy: v1alpha1.RollbackPolicyAutomatic},
+		nodes: []v1alpha1.TaskStatus{
+			{NodeName: "edge-1", State: api.TaskSuccessful},
+			{NodeName: "edge-2", State: api.TaskFailed},
+			{NodeName: "edge-3"},
+		},
+		controller:     c,
+		failedNodes:    map[string]bool{},
+		maxFailedNodes: 0,
+		workers:        workers{number: 1, jobs: map[string]int{}},
+	}
+
+	if err := e.dealFailedNode(e.nodes[1]); err != nil {
+		t.Fatalf("expected the task to roll back instead of failing, got %v", err)
+	}
+	if e.revertReason == "" || !e.revertRequired() {
+		t.Fatalf("expected the upgraded nodes to be rolled back")
+	}
+	e.revert()
+	if !e.reverting || e.workers.shuttingDown || e.revertRequired() {
+		t.Errorf("expected the task to dispatch the reverted nodes")
+	}
+	if !reflect.DeepEqual(c.reported, []string{"edge-1"}) {
+		t.Errorf("expected only the upgraded node to be reverted, got %v", c.reported)
+	}
+	if e.nodes[0].State != api.RevertingState || e.nodes[2].State != "" {
+		t.Errorf("unexpected node states %v", e.nodes)
+	}
+
+	e.task.RollbackPolicy = v1alpha1.RollbackPolicyNone
+	e.reverting = false
+	if e.revertRequired() {
+		t.Errorf("expected no rollback without the automatic rollback policy")
+	}
+}
+
+func TestApplyHeld(t *testing.T) {
+	c := &statusController{BaseController: &controller.BaseController{}}
+	e := &Executor{
+		task: util.TaskMessage{Type: util.TaskUpgrade, Name: "upgrade"},
+		nodes: []v1alpha1.TaskStatus{
+			{NodeName: "edge-1", State: api.BackingUpState},
+			{NodeName: "edge-2", State: api.TaskChecking},
+		},
+		controller: c,
+		apply:      &v1alpha1.ApplySpec{Hold: true},
+	}
+	defer e.stopWindowTimer()
+
+	if !e.applyHeld(e.nodes[0]) || e.applyHeld(e.nodes[1]) {
+		t.Errorf("expected only the node to apply to be held")
+	}
+	if !e.setApply(&v1alpha1.ApplySpec{}) || e.applyHeld(e.nodes[0]) {
+		t.Errorf("expected the released task to be applied at any time")
+	}
+
+	closed := time.Now().UTC().Add(time.Hour).Format(v1alpha1.MaintenanceWindowStartLayout)
+	e.setApply(&v1alpha1.ApplySpec{MaintenanceWindows: []v1alpha1.MaintenanceWindow{{Start: closed, DurationMinutes: 1}}})
+	if !e.applyHeld(e.nodes[0]) || e.windowOpened() == nil {
+		t.Errorf("expected the task to wait for the maintenance window")
+	}
+	if c.window == nil || c.window.RemainingNodes != 2 || c.window.PausedUntil != e.windowOpens.Format(util.ISO8601UTC) {
+		t.Errorf("expected the task to record the maintenance window it waits for with 2 remaining nodes, got %v", c.window)
+	}
+	if e.setApply(&v1alpha1.ApplySpec{Hold: true}) || e.windowOpened() != nil {
+		t.Errorf("expected the held task to stop waiting for the maintenance window")
+	}
+	if c.window != nil {
+		t.Errorf("expected the maintenance window record to be removed, got %v", c.window)
+	}
+}
+
+func TestPauseOnReason(t *testing.T) {
+	now := time.Now()
+	three, window, minNodes := int32(3), int32(60), int32(4)
+	pauseOn := &v1alpha1.PauseOnSpec{
+		FailedNodes: &three,
+		FailureRate: &v1alpha1.FailureRateRule{Rate: "50%", WindowSeconds: &window, MinNodes: &minNodes},
+	}
+	outcome := func(ago time.Duration, failed bool) nodeOutcome {
+		return nodeOutcome{at: now.Add(-ago), failed: failed}
+	}
+	tests := []struct {
+		name     string
+		failed   int
+		outcomes []nodeOutcome
+		pause    bool
+	}{
+		{name: "no failed node", outcomes: []nodeOutcome{outcome(0, false)}},
+		{name: "failed nodes", failed: 3, pause: true},
+		{name: "too few nodes finished", failed: 2, outcomes: []nodeOutcome{outcome(0, true), outcome(0, true), outcome(0, false)}},
+		{name: "failure rate", failed: 2, outcomes: []nodeOutcome{outcome(0, true), outcome(0, true), outcome(0, false), outcome(0, false)}, pause: true},
+		{name: "failures out of window", failed: 2, outcomes: []nodeOutcome{outcome(time.Hour, true), outcome(time.Hour, true),
+			outcome(0, false), outcome(0, false), outcome(0, false), outcome(0, false)}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if reason := pauseOnReason(pauseOn, test.failed, test.outcomes, now); (reason != "") != test.pause {
+				t.Errorf("expected pause %v, got reason %q", test.pause, reason)
+			}
+		})
+	}
+
+	pauseOn.ExternalAlerts = []string{"EdgeAppDown"}
+	if alert := matchAlert(pauseOn, []string{"Watchdog", "EdgeAppDown"}); alert != "EdgeAppDown" {
+		t.Errorf("expected alert EdgeAppDown, got %q", alert)
+	}
+	if alert := matchAlert(nil, []string{"EdgeAppDown"}); alert != "" {
+		t.Errorf("expected no alert, got %q", alert)
+	}
+}
+
+func TestCanary(t *testing.T) {
+	c := &statusController{BaseController: &controller.BaseController{}}
+	e := &Executor{
+		task: util.TaskMessage{Type: util.TaskUpgrade, Name: "upgrade"},
+		nodes: []v1alpha1.TaskStatus{
+			{NodeName: "edge-1", State: api.UpgradingState},
+			{NodeName: "edge-2", State: api.UpgradingState},
+			{NodeName: "edge-3", State: api.TaskSuccessful},
+		},
+		controller: c,
+	}
+	if e.approvalRequired(e.nodes[0]) {
+		t.Errorf("a task without canary must not wait for approval")
+	}
+
+	e.setCanary([]string{"edge-3"})
+	if e.nodes[0].NodeName != "edge-3" {
+		t.Errorf("expected the canary node to be upgraded first, got %v", e.nodes)
+	}
+	if e.approvalRequired(e.nodes[0]) || !e.approvalRequired(e.nodes[1]) {
+		t.Errorf("expected only the nodes which are not canary to wait for approval")
+	}
+	e.waitApproval()
+	e.waitApproval()
+	if !reflect.DeepEqual(c.taskEvents, []string{api.EventCanaryUpgraded}) {
+		t.Errorf("expected the task to wait for approval once, got %v", c.taskEvents)
+	}
+
+	if !e.approve(true) || e.approvalRequired(e.nodes[1]) {
+		t.Errorf("expected the approved task to upgrade the other nodes")
+	}
+	if !reflect.DeepEqual(c.taskEvents, []string{api.EventCanaryUpgraded, api.EventApprove}) {
+		t.Errorf("expected the approved task to resume upgrading, got %v", c.taskEvents)
+	}
+	if e.approve(false) || !e.approvalRequired(e.nodes[1]) {
+		t.Errorf("expected the nodes to wait again once the approval is withdrawn")
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	c := &statusController{BaseController: &controller.BaseController{}}
+	e := &Executor{
+		task: util.TaskMessage{Type: util.TaskUpgrade, Name: "upgrade", DryRun: true, CheckItem: []string{"cpu", "disk"}},
+		nodes: []v1alpha1.TaskStatus{
+			{NodeName: "edge-2", State: api.TaskChecking},
+			{NodeName: "edge-1", State: api.TaskChecking},
+		},
+		controller: c,
+		workers:    workers{number: 2, jobs: map[string]int{}},
+		canary:     map[string]bool{"edge-1": true},
+	}
+
+	e.recordPlan()
+	want := &v1alpha1.ExecutionPlan{
+		Nodes:       []string{"edge-2", "edge-1"},
+		CanaryNodes: []string{"edge-1"},
+		BatchSize:   2,
+		CheckItems:  []string{"cpu", "disk"},
+	}
+	if !reflect.DeepEqual(c.plan, want) {
+		t.Errorf("expected the plan %v, got %v", want, c.plan)
+	}
+
+	if e.checked() {
+		t.Errorf("expected the nodes being checked not to complete the dry run")
+	}
+	e.nodes[0].State = api.TaskFailed
+	e.nodes[1].State = api.BackingUpState
+	if !e.checked() {
+		t.Errorf("expected the dry run to complete once the nodes are checked")
+	}
+}
+
+func TestRestartStalled(t *testing.T) {
+	newExecutor := func(name string, idle time.Duration, busy bool) *Executor {
+		e := &Executor{
+			task:     util.TaskMessage{Type: util.TaskUpgrade, Name: name, Status: v1alpha1.TaskStatus{NodeName: "edge-1"}},
+			stopChan: make(chan struct{}),
+		}
+		e.progress.Store(monotonicNanos(time.Now().Add(-idle)))
+		e.busy.Store(busy)
+		return e
+	}
+	stalled := newExecutor("stalled", time.Hour, true)
+	running := newExecutor("running", time.Minute, true)
+	waiting := newExecutor("waiting", time.Hour, false)
+	crashed := newExecutor("crashed", 0, false)
+	crashed.crashed.Store(true)
+	messageChan := make(chan util.TaskMessage, 4)
+	em := &ExecutorMachine{
+		executors: map[string]*Executor{
+			"upgrade::stalled": stalled,
+			"upgrade::running": running,
+			"upgrade::waiting": waiting,
+			"upgrade::crashed": crashed,
+		},
+		messageChan: messageChan,
+	}
+
+	em.restartStalled(time.Now())
+	restarted := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		select {
+		case msg := <-messageChan:
+			if !reflect.DeepEqual(msg.Status, v1alpha1.TaskStatus{}) {
+				t.Errorf("expected the task to be restarted without a status, got %v", msg.Status)
+			}
+			restarted[msg.Name] = true
+		case <-time.After(time.Second):
+			t.Fatalf("expected 2 executors to be restarted, got %v", restarted)
+		}
+	}
+	if !restarted["stalled"] || !restarted["crashed"] {
+		t.Errorf("expected the stalled and the crashed executors to be restarted, got %v", restarted)
+	}
+	if len(em.executors) != 2 || em.executors["upgrade::running"] != running || em.executors["upgrade::waiting"] != waiting {
+		t.Errorf("expected the running and the waiting executors to be kept, got %v", em.executors)
+	}
+	if err := stalled.HandleMessage(v1alpha1.TaskStatus{}); err == nil {
+		t.Errorf("expected the stopped executor not to handle messages")
+	}
+}
+
+func TestStopLeading(t *testing.T) {
+	upgrade := &Executor{task: util.TaskMessage{Type: util.TaskUpgrade, Name: "upgrade"}, stopChan: make(chan struct{})}
+	prePull := &Executor{task: util.TaskMessage{Type: util.TaskPrePull, Name: "prepull"}, stopChan: make(chan struct{})}
+	em := &ExecutorMachine{
+		executors: map[string]*Executor{"upgrade::upgrade": upgrade, "prepull::prepull": prePull},
+		pending:   &pendingTasks{tasks: map[string][]pendingTask{}},
+	}
+	em.pending.add("edge-1", "upgrade", model.Message{})
+	em.leading.Store(true)
+
+	em.stopLeading("replica-1")
+	if em.leading.Load() {
+		t.Errorf("expected the replica not to be leading")
+	}
+	if len(em.executors) != 0 {
+		t.Errorf("expected the executors to be given up, got %v", em.executors)
+	}
+	for _, e := range []*Executor{upgrade, prePull} {
+		if err := e.HandleMessage(v1alpha1.TaskStatus{}); err == nil {
+			t.Errorf("expected the executor of task %s to be stopped", e.task.Name)
+		}
+	}
+	if pending := em.pending.take("edge-1"); len(pending) != 0 {
+		t.Errorf("expected the pending messages to be dropped, got %v", pending)
+	}
+}
+
+func TestRelayToFollower(t *testing.T) {
+	kubeClient := k8sfake.NewSimpleClientset()
+	newReplica := func(leading bool, connected string) *ExecutorMachine {
+		em := &ExecutorMachine{
+			kubeClient:     kubeClient,
+			executors:      map[string]*Executor{},
+			downStreamChan: make(chan model.Message, 1),
+			pending:        &pendingTasks{tasks: map[string][]pendingTask{}},
+		}
+		em.outbox = newOutbox(nil, em.queueDownstream)
+		em.leading.Store(leading)
+		em.relay = newRelay(kubeClient, func(nodeName string) bool {
+			return nodeName == connected
+		}, em.leading.Load, em.enqueueDownstream, em.handleRelayed)
+		return em
+	}
+	// edge-1 is connected to the follower, the executors run on the leader
+	leader, follower := newReplica(true, ""), newReplica(false, "edge-1")
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	for _, em := range []*ExecutorMachine{leader, follower} {
+		if err := em.relay.run(stopCh); err != nil {
+			t.Fatalf("failed to run the relay: %v", err)
+		}
+	}
+	c := &fsmController{statusController{BaseController: &controller.BaseController{}, nodeStatus: []v1alpha1.TaskStatus{
+		{NodeName: "edge-1", State: api.UpgradingState},
+	}}}
+	e := &Executor{
+		task:       util.TaskMessage{Type: util.TaskUpgrade, Name: "upgrade"},
+		nodes:      c.nodeStatus,
+		controller: c,
+		receipts:   &receipts{states: map[string]api.State{}},
+	}
+	leader.executors["upgrade::upgrade"] = e
+	resource := buildTaskResource(util.TaskUpgrade, "upgrade", "edge-1")
+	task := model.NewMessage("").
+		BuildRouter(modules.TaskManagerModuleName, modules.TaskManagerModuleGroup, resource, util.TaskUpgrade).
+		FillBody(commontypes.NodeTaskRequest{TaskID: "upgrade", Type: util.TaskUpgrade, State: string(api.UpgradingState)})
+	// the leader sends the task of the node through the follower holding its session
+	leader.dispatch("edge-1", "upgrade", *task)
+	select {
+	case msg := <-follower.downStreamChan:
+		if msg.GetID() != task.GetID() {
+			t.Errorf("expected the task message to be sent to the node, got %v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the task message to be sent to the node by the follower")
+	}
+	select {
+	case msg := <-leader.downStreamChan:
+		t.Errorf("expected the leader not to send to the node connected to the follower, got %v", msg)
+	default:
+	}
+
+	// the receipt of the node reaches the executor on the leader
+	receipt := model.NewMessage("").SetResourceOperation(resource, util.TaskAccept)
+	receipt.Content, _ = json.Marshal(commontypes.NodeTaskReceipt{NodeName: "edge-1", Type: util.TaskUpgrade, State: string(api.UpgradingState)})
+	handleExecutorMessage(follower, "upgrade", "edge-1", *receipt)
+	for deadline := time.Now().Add(5 * time.Second); !e.receipts.accepted("edge-1", api.UpgradingState); {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the receipt of the node to be relayed to the leader")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// the result of the node is recorded in the status of the task by the follower
+	report := model.NewMessage("").SetResourceOperation(resource, util.TaskUpgrade)
+	if handleExecutorMessage(follower, "upgrade", "edge-1", *report) {
+		t.Errorf("expected the result of the node not to be relayed")
+	}
+	state, err := c.ReportNodeStatus("upgrade", "edge-1", fsm.Event{Type: "Upgrade", Action: api.ActionSuccess})
+	if err != nil || state != api.TaskSuccessful {
+		t.Errorf("expected the node to finish its task, got %s: %v", state, err)
+	}
+
+	for _, direction := range []string{relayDownstream, relayUpstream} {
+		name := relayConfigMapName("edge-1", direction)
+		for deadline := time.Now().Add(5 * time.Second); ; {
+			cm, err := kubeClient.CoreV1().ConfigMaps(constants.SystemNamespace).Get(context.TODO(), name, metav1.GetOptions{})
+			if err == nil && len(cm.Data) == 0 {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected the relayed messages to be removed from %s, got %v: %v", name, cm, err)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+func TestRelayThroughOutbox(t *testing.T) {
+	// the apiserver is slow, relaying a message must not hold the lock of the outbox while it is written
+	delay := 500 * time.Millisecond
+	kubeClient := k8sfake.NewSimpleClientset()
+	kubeClient.PrependReactor("*", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetVerb() != "list" && action.GetVerb() != "watch" {
+			time.Sleep(delay)
+		}
+		return false, nil, nil
+	})
+	em := &ExecutorMachine{
+		kubeClient:     kubeClient,
+		executors:      map[string]*Executor{},
+		downStreamChan: make(chan model.Message, 1),
+		pending:        &pendingTasks{tasks: map[string][]pendingTask{}},
+	}
+	em.outbox = newOutbox(nil, em.queueDownstream)
+	em.relay = newRelay(kubeClient, func(string) bool { return false }, em.leading.Load, em.enqueueDownstream, em.handleRelayed)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	if err := em.relay.run(stopCh); err != nil {
+		t.Fatalf("failed to run the relay: %v", err)
+	}
+
+	start := time.Now()
+	for _, task := range []string{"upgrade", "prepull", "restart"} {
+		resource := buildTaskResource(util.TaskUpgrade, task, "edge-1")
+		em.sendDownstream(task, *model.NewMessage("").SetResourceOperation(resource, util.TaskUpgrade))
+	}
+	if elapsed := time.Since(start); elapsed >= delay {
+		t.Errorf("expected the messages to be relayed in background, sending them took %s", elapsed)
+	}
+	em.outbox.Lock()
+	held := len(em.outbox.queues)
+	em.outbox.Unlock()
+	if held != 0 {
+		t.Errorf("expected no message to be held by the outbox, got %d tasks", held)
+	}
+
+	name := relayConfigMapName("edge-1", relayDownstream)
+	for deadline := time.Now().Add(10 * time.Second); ; {
+		cm, err := kubeClient.CoreV1().ConfigMaps(constants.SystemNamespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err == nil && len(cm.Data) == 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the messages to be relayed to %s, got %v: %v", name, cm, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestOutbox(t *testing.T) {
+	downstream := make(chan model.Message, 1)
+	send := func(msg model.Message) bool {
+		select {
+		case downstream <- msg:
+			return true
+		default:
+			return false
+		}
+	}
+	o := newOutbox(&cloudcorev1alpha1.TaskManagerDownstreamRateLimit{TaskQPS: 20, TaskBurst: 1}, send)
+
+	first := model.NewMessage("").SetResourceOperation("first", "upgrade")
+	second := model.NewMessage("").SetResourceOperation("second", "upgrade")
+	other := model.NewMessage("").SetResourceOperation("other", "prepull")
+	o.add("upgrade", *first)
+	o.add("upgrade", *second)
+	// the queue is full, the message is held without blocking
+	o.add("prepull", *other)
+
+	var got []string
+	for len(got) < 3 {
+		select {
+		case msg := <-downstream:
+			got = append(got, msg.GetResource())
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected 3 messages to be sent, got %v", got)
+		}
+	}
+	if got[0] != "first" {
+		t.Errorf("expected the first message to be sent right away, got %v", got)
+	}
+	index := map[string]int{}
+	for i, resource := range got {
+		index[resource] = i
+	}
+	if index["first"] > index["second"] {
+		t.Errorf("expected the messages of the task to be sent in order, got %v", got)
+	}
+
+	o.Lock()
+	o.queues["upgrade"] = []heldMessage{{msg: *first}}
+	o.Unlock()
+	o.forget("upgrade")
+	if queue := o.queues["upgrade"]; len(queue) != 0 || o.tasks["upgrade"] != nil {
+		t.Errorf("expected the held messages and the bucket of the task to be dropped")
+	}
+}
+
+func TestPreflight(t *testing.T) {
+	c := &statusController{BaseController: &controller.BaseController{}}
+	timeout := int32(1)
+	e := &Executor{
+		task: util.TaskMessage{Type: util.TaskUpgrade, Name: "upgrade", Preflight: &v1alpha1.PreflightSpec{TimeoutSeconds: &timeout}},
+		nodes: []v1alpha1.TaskStatus{
+			{NodeName: "edge-1"},
+			{NodeName: "edge-2"},
+			{NodeName: "edge-3", State: api.TaskSuccessful},
+		},
+		controller:  c,
+		stopChan:    make(chan struct{}),
+		probeChan:   make(chan string, 3),
+		unreachable: map[string]bool{},
+	}
+	executorMachine = &ExecutorMachine{
+		downStreamChan: make(chan model.Message, 3),
+		executors:      map[string]*Executor{"upgrade::upgrade": e},
+	}
+	executorMachine.outbox = newOutbox(nil, executorMachine.queueDownstream)
+	executorMachine.ProbeAnswered(util.TaskUpgrade, "upgrade", "edge-1")
+
+	if !e.preflight() {
+		t.Fatalf("expected the task to be dispatched after the probe")
+	}
+	if len(executorMachine.downStreamChan) != 2 {
+		t.Errorf("expected the unfinished nodes to be probed, got %d probes", len(executorMachine.downStreamChan))
+	}
+	if !reflect.DeepEqual(c.reported, []string{"edge-2"}) || !e.unreachable["edge-2"] || e.unreachable["edge-1"] {
+		t.Errorf("expected only edge-2 to be failed as unreachable, got %v", c.reported)
+	}
+	if e.nodes[1].Event != api.EventTimeOut || e.nodes[1].Action != api.ActionFailure {
+		t.Errorf("expected edge-2 to time out, got %s/%s", e.nodes[1].Event, e.nodes[1].Action)
+	}
+}
+
+func TestTune(t *testing.T) {
+	c := &statusController{BaseController: &controller.BaseController{}}
+	e := &Executor{
+		task: util.TaskMessage{Type: util.TaskUpgrade, Name: "upgrade", Concurrency: 1},
+		nodes: []v1alpha1.TaskStatus{
+			{NodeName: "edge-1", State: api.TaskFailed},
+			{NodeName: "edge-2", State: api.UpgradingState},
+			{NodeName: "edge-3"},
+			{NodeName: "edge-4"},
+		},
+		controller:  c,
+		failedNodes: map[string]bool{"edge-1": true},
+		workers:     workers{number: 1, jobs: map[string]int{"edge-2": 1}},
+	}
+
+	tolerate := intstr.FromInt(2)
+	if !e.tune(&util.TaskMessage{Concurrency: 3, FailureTolerate: &tolerate}) {
+		t.Fatalf("expected the task to keep running within its failure tolerance")
+	}
+	if e.workers.number != 3 || e.maxFailedNodes != 2 {
+		t.Errorf("expected 3 workers and 2 tolerated failed nodes, got %d and %v", e.workers.number, e.maxFailedNodes)
+	}
+
+	maxUnavailable := intstr.FromString("50%")
+	tolerate = intstr.FromInt(0)
+	e.tune(&util.TaskMessage{MaxUnavailable: &maxUnavailable, FailureTolerate: &tolerate})
+	if e.workers.number != 2 {
+		t.Errorf("expected 2 workers for half of the 4 nodes, got %d", e.workers.number)
+	}
+	if !e.workers.shuttingDown {
+		t.Errorf("expected the task to stop dispatching once the lowered failure tolerance is exceeded")
+	}
+}
+
+func TestTuneExecutor(t *testing.T) {
+	e := &Executor{tuneChan: make(chan *util.TaskMessage, 1)}
+	executorMachine = &ExecutorMachine{executors: map[string]*Executor{"upgrade::upgrade": e}}
+
+	// the executor is busy, the tunings are coalesced rather than dropped
+	for _, concurrency := range []int32{2, 3, 4} {
+		TuneExecutor(util.TaskMessage{Type: util.TaskUpgrade, Name: "upgrade", Concurrency: concurrency})
+	}
+	select {
+	case tuning := <-e.tuneChan:
+		if tuning.Concurrency != 4 {
+			t.Errorf("expected the latest tuning to be applied, got concurrency %d", tuning.Concurrency)
+		}
+	default:
+		t.Fatalf("expected the tuning to be passed to the executor")
+	}
+	select {
+	case tuning := <-e.tuneChan:
+		t.Errorf("expected the earlier tunings to be replaced, got concurrency %d", tuning.Concurrency)
+	default:
+	}
+}
+
+func TestDeferNode(t *testing.T) {
+	names := func(nodes []v1alpha1.TaskStatus) []string {
+		var names []string
+		for _, node := range nodes {
+			names = append(names, node.NodeName)
+		}
+		return names
+	}
+	e := &Executor{
+		nodes: []v1alpha1.TaskStatus{
+			{NodeName: "edge-1"}, {NodeName: "edge-2"}, {NodeName: "edge-3"}, {NodeName: "edge-4"},
+		},
+	}
+	e.deferNode(1)
+	if got := names(e.nodes); !reflect.DeepEqual(got, []string{"edge-1", "edge-3", "edge-4", "edge-2"}) {
+		t.Errorf("expected the locked node to be moved to the end, got %v", got)
+	}
+
+	e.canary = map[string]bool{"edge-1": true, "edge-3": true}
+	e.deferNode(0)
+	if got := names(e.nodes); !reflect.DeepEqual(got, []string{"edge-3", "edge-1", "edge-4", "edge-2"}) {
+		t.Errorf("expected the locked canary node to stay in front of the other nodes, got %v", got)
+	}
+	e.approved = true
+	e.deferNode(0)
+	if got := names(e.nodes); !reflect.DeepEqual(got, []string{"edge-1", "edge-4", "edge-2", "edge-3"}) {
+		t.Errorf("expected the locked node to be moved to the end once approved, got %v", got)
+	}
+}
+
+func TestEdgeCoreVersion(t *testing.T) {
+	for kubeletVersion, want := range map[string]string{
+		"v1.22.6-kubeedge-v1.10.0-beta.0.185+95378fb019912a": "v1.10.0",
+		"v1.28.6-kubeedge-v1.19.0":                           "v1.19.0",
+		"v1.28.6":                                            "v1.28.6",
+	} {
+		if got := edgeCoreVersion(kubeletVersion); got != want {
+			t.Errorf("expected EdgeCore %s in %s, got %s", want, kubeletVersion, got)
+		}
+	}
+}
+
+func TestSmokeTest(t *testing.T) {
+	probe := &v1alpha1.VerificationProbe{
+		Name:           "http",
+		HTTPGet:        &v1.HTTPGetAction{Path: "/healthz", Port: intstr.FromInt(8080)},
+		TimeoutSeconds: 2,
+	}
+	owner := &metav1.OwnerReference{Kind: "NodeUpgradeJob", Name: "upgrade-1"}
+	task := util.TaskMessage{Type: util.TaskUpgrade, Name: "upgrade-1", Labels: map[string]string{"team": "edge"}, Owner: owner}
+	pod := canaryPod(task, "edge-1", v1alpha1.SmokeTestSpec{Image: "busybox", Command: []string{"sleep", "3600"}, Probe: probe})
+	expectedLabels := map[string]string{
+		"team":                "edge",
+		util.TaskNameLabelKey: "upgrade-1",
+		util.TaskTypeLabelKey: util.TaskUpgrade,
+		smokeTestLabel:        "true",
+	}
+	if !reflect.DeepEqual(pod.Labels, expectedLabels) {
+		t.Errorf("expected the canary pod labels %v, got %v", expectedLabels, pod.Labels)
+	}
+	if len(pod.OwnerReferences) != 1 || pod.OwnerReferences[0] != *owner {
+		t.Errorf("expected the canary pod to be owned by the task, got %v", pod.OwnerReferences)
+	}
+	if pod.Spec.NodeName != "edge-1" || pod.Spec.RestartPolicy != v1.RestartPolicyNever || len(pod.Spec.Tolerations) != 1 {
+		t.Errorf("expected the canary pod to be bound to the node, got %+v", pod.Spec)
+	}
+	container := pod.Spec.Containers[0]
+	if container.ReadinessProbe == nil || container.ReadinessProbe.HTTPGet != probe.HTTPGet || container.ReadinessProbe.TimeoutSeconds != 2 {
+		t.Errorf("expected the readiness probe of the canary, got %+v", container.ReadinessProbe)
+	}
+
+	pod.Name, pod.Namespace = "smoke-test-abcde", metav1.NamespaceDefault
+	pod.Status.Phase = v1.PodPending
+	if got := canaryReason(pod, probe); got != "canary pod default/smoke-test-abcde is Pending" {
+		t.Errorf("unexpected reason of a pending canary: %s", got)
+	}
+	pod.Status.Phase = v1.PodRunning
+	pod.Status.ContainerStatuses = []v1.ContainerStatus{{Name: canaryContainerName}}
+	if got := canaryReason(pod, probe); got != "canary pod default/smoke-test-abcde does not pass probe http" {
+		t.Errorf("unexpected reason of a canary which is not ready: %s", got)
+	}
+	pod.Status.ContainerStatuses[0].Ready = true
+	if got := canaryReason(pod, probe); got != "" {
+		t.Errorf("expected the canary to pass, got %s", got)
+	}
+}
+
+func TestMarkProgress(t *testing.T) {
+	e := &Executor{
+		task: util.TaskMessage{Type: util.TaskUpgrade, Name: "metrics"},
+		nodes: []v1alpha1.TaskStatus{
+			{NodeName: "edge-1", State: api.TaskSuccessful},
+			{NodeName: "edge-2", State: api.TaskFailed},
+			{NodeName: "edge-3", State: api.UpgradingState},
+			{NodeName: "edge-4", State: api.TaskSuccessful},
+		},
+		workers: workers{number: 1, jobs: map[string]int{"edge-3": 2}},
+	}
+	e.markProgress()
+	if !e.busy.Load() || e.progress.Load() == 0 {
+		t.Errorf("expected the progress of the busy executor to be recorded")
+	}
+	for phase, expected := range map[string]float64{"succeeded": 2, "failed": 1, "in_progress": 1} {
+		if actual := testutil.ToFloat64(monitor.TaskNodes.WithLabelValues(util.TaskUpgrade, "metrics", phase)); actual != expected {
+			t.Errorf("expected %v %s nodes, got %v", expected, phase, actual)
+		}
+	}
+}
+
+func TestDropDeferredJob(t *testing.T) {
+	e := &Executor{
+		task:         util.TaskMessage{Type: util.TaskUpgrade, Name: "jitter"},
+		nodes:        []v1alpha1.TaskStatus{{NodeName: "edge-1", State: api.UpgradingState}},
+		workers:      workers{number: 1, jobs: map[string]int{"edge-1": 0}},
+		dispatchChan: make(chan deferredJob),
+		stopChan:     make(chan struct{}),
+		cancelled:    true,
+	}
+	if !inFlight.acquire(e, 100) {
+		t.Fatalf("expected a slot for the node in flight")
+	}
+
+	// the jitter timer hands the job back to the executor loop instead of dispatching it
+	go e.deferDispatch(deferredJob{index: 0})
+	job := <-e.dispatchChan
+	if e.startJob(job) {
+		t.Fatalf("expected the job of the cancelled task to be dropped")
+	}
+	if len(e.workers.jobs) != 0 {
+		t.Errorf("expected the node of the dropped job to be released, got jobs %v", e.workers.jobs)
+	}
+	if _, ok := inFlight.tasks[e]; ok {
+		t.Errorf("expected the in-flight slot of the dropped job to be released")
+	}
+
+	// the job is not handed back once the task is deleted
+	close(e.stopChan)
+	done := make(chan struct{})
+	go func() {
+		e.deferDispatch(deferredJob{index: 0})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Errorf("expected the deferred job to be dropped with the deleted task")
+	}
+}
+
+func TestTaskResource(t *testing.T) {
+	// the jobs of different types with the same name on the same node are told apart by the type
+	for _, taskType := range []string{util.TaskUpgrade, util.TaskPrePull, util.TaskSupportBundle} {
+		resource := buildTaskResource(taskType, "job", "edge-1")
+		if resource != taskType+"/job/node/edge-1" {
+			t.Errorf("unexpected resource %q of task type %s", resource, taskType)
+		}
+		taskID, nodeName, err := util.ParseTaskResource(resource)
+		if err != nil || taskID != "job" || nodeName != "edge-1" {
+			t.Errorf("expected task job on node edge-1 of resource %q, got %q, %q, %v", resource, taskID, nodeName, err)
+		}
+	}
+	if resource := buildUpgradeResource("job", "edge-1"); util.GetTaskID(resource) != "job" || util.GetNodeName(resource) != "edge-1" {
+		t.Errorf("unexpected upgrade resource %q", resource)
+	}
+
+	// the resource of the status reported by edge nodes
+	taskID, nodeName, err := util.ParseTaskResource("task/job/node/edge-1")
+	if err != nil || taskID != "job" || nodeName != "edge-1" {
+		t.Errorf("expected task job on node edge-1, got %q, %q, %v", taskID, nodeName, err)
+	}
+	for _, resource := range []string{"", "task/job", "task/job/edge-1", "task//node/edge-1", "task/job/node/", "task/job/nodes/edge-1", "task/job/node/edge-1/extra"} {
+		if _, _, err = util.ParseTaskResource(resource); err == nil {
+			t.Errorf("expected resource %q to be malformed", resource)
+		}
+		if util.GetNodeName(resource) != "" || util.GetTaskID(resource) != "" {
+			t.Errorf("expected no task and node of malformed resource %q", resource)
+		}
+	}
+}
+
+func TestDeleteExpiredTasks(t *testing.T) {
+	// a task type registered out of tree, its task objects are served by the client of SupportBundleJobs
+	util.RegisterTaskObjects("bundle", util.NewTypedTaskObjects(v1alpha1.Resource("supportbundlejobs"),
+		func(crdClient crdClientset.Interface) util.TypedTaskClient[*v1alpha1.SupportBundleJob, *v1alpha1.SupportBundleJobList] {
+			return crdClient.OperationsV1alpha1().SupportBundleJobs()
+		},
+		func(meta metav1.ObjectMeta, spec v1alpha1.SupportBundleJobSpec) *v1alpha1.SupportBundleJob {
+			return &v1alpha1.SupportBundleJob{ObjectMeta: meta, Spec: spec}
+		},
+		func(job *v1alpha1.SupportBundleJob) util.TaskObjectStatus {
+			return util.JobObjectStatus(job.Status.CommonJobStatus, job.Status)
+		},
+		func(job *v1alpha1.SupportBundleJob) *int32 {
+			return job.Spec.TTLSecondsAfterFinished
+		}))
+	now := time.Now()
+	ttl := int32(60)
+	finishedAt := now.Add(-2 * time.Minute).UTC().Format(util.ISO8601UTC)
+	upgrade := func(name string, ttl *int32, state api.State, promotion *v1alpha1.PromotionSpec) *v1alpha1.NodeUpgradeJob {
+		return &v1alpha1.NodeUpgradeJob{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1alpha1.NodeUpgradeJobSpec{TTLSecondsAfterFinished: ttl, Promotion: promotion},
+			Status:     v1alpha1.NodeUpgradeJobStatus{State: state, Time: finishedAt},
+		}
+	}
+	bundle := func(name, finishedAt string) *v1alpha1.SupportBundleJob {
+		return &v1alpha1.SupportBundleJob{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1alpha1.SupportBundleJobSpec{CommonJobSpec: v1alpha1.CommonJobSpec{TTLSecondsAfterFinished: &ttl}},
+			Status:     v1alpha1.SupportBundleJobStatus{CommonJobStatus: v1alpha1.CommonJobStatus{State: api.TaskFailed, Time: finishedAt}},
+		}
+	}
+	restart := &v1alpha1.NodeRestartJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "restart"},
+		Spec:       v1alpha1.NodeRestartJobSpec{CommonJobSpec: v1alpha1.CommonJobSpec{TTLSecondsAfterFinished: &ttl}},
+		Status:     v1alpha1.NodeRestartJobStatus{CommonJobStatus: v1alpha1.CommonJobStatus{State: api.TaskSuccessful, Time: finishedAt}},
+	}
+	crdClient := fake.NewSimpleClientset(
+		upgrade("expired", &ttl, api.TaskSuccessful, nil),
+		upgrade("no-ttl", nil, api.TaskSuccessful, nil),
+		upgrade("running", &ttl, api.UpgradingState, nil),
+		upgrade("promoting", &ttl, api.TaskSuccessful, &v1alpha1.PromotionSpec{NodeGroup: "group"}),
+		bundle("bundle", now.UTC().Format(util.ISO8601UTC)),
+		bundle("expired-bundle", finishedAt),
+		restart,
+	)
+
+	deleteExpiredTasks(crdClient, now)
+
+	list, err := crdClient.OperationsV1alpha1().NodeUpgradeJobs().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var remaining []string
+	for _, job := range list.Items {
+		remaining = append(remaining, job.Name)
+	}
+	expected := []string{"no-ttl", "promoting", "running"}
+	if !reflect.DeepEqual(remaining, expected) {
+		t.Errorf("expected the jobs %v to be kept, got %v", expected, remaining)
+	}
+	// the bundle finished just now
+	if _, err = crdClient.OperationsV1alpha1().SupportBundleJobs().Get(context.TODO(), "bundle", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the recently finished bundle to be kept: %v", err)
+	}
+	if _, err = crdClient.OperationsV1alpha1().SupportBundleJobs().Get(context.TODO(), "expired-bundle", metav1.GetOptions{}); err == nil {
+		t.Error("expected the expired task of the type registered out of tree to be deleted")
+	}
+	if _, err = crdClient.OperationsV1alpha1().NodeRestartJobs().Get(context.TODO(), "restart", metav1.GetOptions{}); err == nil {
+		t.Error("expected the expired restart to be deleted")
+	}
+}
+
+func TestTokenRequest(t *testing.T) {
+	spec := v1alpha1.TaskCredentialSpec{ServiceAccountName: "cordon"}
+	request := tokenRequest(spec)
+	if request.Spec.ExpirationSeconds == nil || *request.Spec.ExpirationSeconds != defaultCredentialExpirationSeconds {
+		t.Errorf("expected the default expiration, got %v", request.Spec.ExpirationSeconds)
+	}
+	if namespace := credentialNamespace(spec); namespace != constants.DefaultTaskCredentialNamespace {
+		t.Errorf("expected the default namespace, got %s", namespace)
+	}
+
+	expiration := int64(1800)
+	spec = v1alpha1.TaskCredentialSpec{ServiceAccountName: "cordon", Namespace: "ops", Audiences: []string{"metaserver"}, ExpirationSeconds: &expiration}
+	request = tokenRequest(spec)
+	if *request.Spec.ExpirationSeconds != 1800 || !reflect.DeepEqual(request.Spec.Audiences, []string{"metaserver"}) {
+		t.Errorf("unexpected token request %+v", request.Spec)
+	}
+	if namespace := credentialNamespace(spec); namespace != "ops" {
+		t.Errorf("expected namespace ops, got %s", namespace)
+	}
+}
+
+func TestMintCredentialRejected(t *testing.T) {
+	allowed := config.Config.CredentialServiceAccounts
+	defer func() { config.Config.CredentialServiceAccounts = allowed }()
+	config.Config.CredentialServiceAccounts = []string{"ops/cordon"}
+
+	if !credentialAllowed("ops", "cordon") {
+		t.Errorf("expected service account ops/cordon to be allowed")
+	}
+	// the service accounts not listed are rejected before any token is requested, no kube client
+	// is initialized here so a request would panic
+	for _, spec := range []v1alpha1.TaskCredentialSpec{
+		{ServiceAccountName: "cordon"},
+		{ServiceAccountName: "cloudcore", Namespace: "ops"},
+		{ServiceAccountName: "cloudcore", Namespace: "kubeedge"},
+	} {
+		if _, err := mintCredential("restart", "edge-1", spec); err == nil {
+			t.Errorf("expected minting the credential of %s/%s to be rejected", credentialNamespace(spec), spec.ServiceAccountName)
+		}
+	}
+}
+
+func TestDispatchJobInitError(t *testing.T) {
+	c := &statusController{}
+	e := &Executor{
+		task:       util.TaskMessage{Type: util.TaskUpgrade, Name: "credential"},
+		nodes:      []v1alpha1.TaskStatus{{NodeName: "edge-1", State: api.UpgradingState}},
+		controller: c,
+	}
+	if !e.dispatchJob(0, nil, errors.New("service account is not allowed")) {
+		t.Fatalf("expected the job to be handled")
+	}
+	// the node is failed at once instead of waiting for the job to time out
+	if !reflect.DeepEqual(c.reported, []string{"edge-1"}) {
+		t.Errorf("expected node edge-1 to be failed at once, got %v", c.reported)
+	}
+}
+
+// fsmController transits the node status of a task kept in memory with the upgrade rules
+type fsmController struct {
+	statusController
+}
+
+func (c *fsmController) ReportNodeStatus(taskID, nodeName string, event fsm.Event) (api.State, error) {
+	nodeFSM := &fsm.FSM{}
+	nodeFSM.NodeName(nodeName).ID(taskID).Guard(api.UpgradeRule).StageSequence(api.UpdateStageSequence).
+		CurrentFunc(func(_, nodeName string) (api.State, error) {
+			for _, status := range c.nodeStatus {
+				if status.NodeName == nodeName {
+					return status.State, nil
+				}
+			}
+			return "", errors.New("node not found")
+		}).
+		UpdateFunc(func(_, nodeName string, state api.State, event fsm.Event) error {
+			for i := range c.nodeStatus {
+				if c.nodeStatus[i].NodeName == nodeName {
+					c.nodeStatus[i].State, c.nodeStatus[i].Event, c.nodeStatus[i].Action = state, event.Type, event.Action
+				}
+			}
+			return nil
+		})
+	if err := nodeFSM.Transit(event); err != nil {
+		return "", err
+	}
+	return nodeFSM.CurrentState()
+}
+
+func TestRedriveTimedOutNode(t *testing.T) {
+	machine, interval := executorMachine, redriveRequeueInterval
+	defer func() {
+		executorMachine, redriveRequeueInterval = machine, interval
+	}()
+	executorMachine = &ExecutorMachine{executors: map[string]*Executor{}}
+	redriveRequeueInterval = 10 * time.Millisecond
+	c := &fsmController{statusController{BaseController: &controller.BaseController{}, nodeStatus: []v1alpha1.TaskStatus{
+		{NodeName: "edge-1", State: api.UpgradingState},
+		{NodeName: "edge-2", State: api.UpgradingState},
+	}}}
+	upgrade := fsm.Event{Type: "Upgrade", Action: api.ActionSuccess}
+	timeout := fsm.Event{Type: api.EventTimeOut, Action: api.ActionFailure}
+
+	if _, err := c.ReportNodeStatus("upgrade", "edge-1", timeout); err != nil {
+		t.Fatalf("failed to time out the node: %v", err)
+	}
+	if _, err := c.ReportNodeStatus("upgrade", "edge-1", upgrade); err == nil {
+		t.Fatalf("expected the result of the node failed by timeout to be rejected")
+	}
+
+	letter := util.DeadLetter{NodeName: "edge-1", State: string(api.UpgradingState), Message: *model.NewMessage("")}
+	if err := executorMachine.Redrive("redrive-test", "upgrade", letter); err != util.ErrNotLeader {
+		t.Errorf("expected the follower not to redrive the dead letter, got %v", err)
+	}
+	executorMachine.leading.Store(true)
+	if err := executorMachine.Redrive("redrive-test", "upgrade", letter); err != ErrTaskNotRunning {
+		t.Errorf("expected the dead letter of the task which is not running not to be redriven, got %v", err)
+	}
+
+	e := &Executor{
+		task:        util.TaskMessage{Type: "redrive-test", Name: "upgrade"},
+		controller:  c,
+		redriveChan: make(chan redrivenNode, 1),
+		stopChan:    make(chan struct{}),
+	}
+	executorMachine.executors["redrive-test::upgrade"] = e
+	if err := executorMachine.Redrive("redrive-test", "upgrade", letter); err != nil {
+		t.Fatalf("failed to redrive the dead letter: %v", err)
+	}
+	if redriven := <-e.redriveChan; redriven.node.State != api.UpgradingState || redriven.msg.GetID() == letter.Message.GetID() {
+		t.Errorf("expected the node to be redriven in its state with a new message ID, got %v", redriven)
+	}
+	state, err := c.ReportNodeStatus("upgrade", "edge-1", upgrade)
+	if err != nil {
+		t.Fatalf("expected the result of the redriven node to be accepted: %v", err)
+	}
+	if state != api.TaskSuccessful {
+		t.Errorf("expected the redriven node to succeed, got %s", state)
+	}
+	if err := executorMachine.Redrive("redrive-test", "upgrade", letter); err == nil {
+		t.Errorf("expected the node not failed by timeout not to be redriven")
+	}
+
+	// the node is handed to the busy executor once it takes it
+	if _, err := c.ReportNodeStatus("upgrade", "edge-2", timeout); err != nil {
+		t.Fatalf("failed to time out the node: %v", err)
+	}
+	e.redriveChan <- redrivenNode{}
+	letter.NodeName = "edge-2"
+	if err := executorMachine.Redrive("redrive-test", "upgrade", letter); err != nil {
+		t.Fatalf("failed to redrive the dead letter: %v", err)
+	}
+	<-e.redriveChan
+	select {
+	case redriven := <-e.redriveChan:
+		if redriven.node.NodeName != "edge-2" {
+			t.Errorf("expected edge-2 to be redriven, got %v", redriven.node)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("expected the redriven node to be requeued to the busy executor")
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// ForceCompleteExecutor requests the running executor of the task to complete a node manually
+func ForceCompleteExecutor(msg util.TaskMessage) {
+	executorMachine.Lock()
+	e, ok := executorMachine.executors[fmt.Sprintf("%s::%s", msg.Type, msg.Name)]
+	executorMachine.Unlock()
+	if !ok {
+		klog.Warningf("task %s to force node %s to complete is not running", msg.Name, msg.ForceComplete.NodeName)
+		return
+	}
+	select {
+	case e.forceChan <- msg.ForceComplete:
+	default:
+		klog.Warningf("failed to force node %s of task %s to complete, the executor is busy", msg.ForceComplete.NodeName, msg.Name)
+	}
+}
+
+// forceComplete moves the unfinished node to the state requested by an admin and releases its worker,
+// the justification is recorded as the reason of the node. It returns the status of the node and
+// false if the node is not part of the task or already finished it.
+func (e *Executor) forceComplete(force *util.ForceCompletion) (v1alpha1.TaskStatus, bool) {
+	index := e.nodeIndex(force.NodeName)
+	if index < 0 || fsm.TaskFinish(e.nodes[index].State) {
+		klog.Warningf("node %s is not an unfinished node of task %s, ignore forcing it to complete", force.NodeName, e.task.Name)
+		util.RecordTaskEvent(e.task, v1.EventTypeWarning, "ForceCompleteIgnored",
+			"Node %s is not an unfinished node of the task", force.NodeName)
+		return v1alpha1.TaskStatus{}, false
+	}
+	klog.Warningf("node %s of task %s is forced to %s: %s", force.NodeName, e.task.Name, force.State, force.Reason)
+
+	e.releaseNode(force.NodeName)
+	e.forced[force.NodeName] = true
+	if err := util.UnmarkNodeInProgress(force.NodeName, e.task.Type, e.task.Name); err != nil {
+		klog.Warningf("failed to unmark node %s in progress of task %s: %v", force.NodeName, e.task.Name, err)
+	}
+	util.RecordTaskEvent(e.task, v1.EventTypeWarning, "NodeForceCompleted",
+		"Node %s is forced to %s: %s", force.NodeName, force.State, force.Reason)
+	e.markNode(index, force.State, fmt.Sprintf("forced to %s: %s", force.State, force.Reason))
+	return e.nodes[index], true
+}
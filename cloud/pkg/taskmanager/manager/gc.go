@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/common/types"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// PurgeStaleTasks replies to the inventory of the edge node with the tasks whose objects
+// no longer exist or are finished, the edge node purges their persisted state.
+func (em *ExecutorMachine) PurgeStaleTasks(nodeName string, inventory types.NodeTaskInventory) {
+	stale := staleTasks(client.GetCRDClient(), inventory.Tasks)
+	if len(stale) == 0 {
+		return
+	}
+	klog.Infof("node %s holds %d stale tasks, ask it to purge them", nodeName, len(stale))
+	msg := model.NewMessage("").
+		BuildRouter(modules.TaskManagerModuleName, modules.TaskManagerModuleGroup,
+			buildTaskResource("task", util.TaskPurge, nodeName), util.TaskPurge).
+		FillBody(types.NodeTaskInventory{NodeName: nodeName, Tasks: stale})
+	em.dispatch(nodeName, "", *msg)
+}
+
+// staleTasks returns the tasks whose objects are not found or finished, cloud has recorded the final
+// results of the finished ones so the edge node no longer needs to buffer them. The tasks of types
+// unknown to cloud and the tasks which cannot be checked are kept.
+func staleTasks(crdClient crdClientset.Interface, tasks []types.NodeTaskKey) []types.NodeTaskKey {
+	var stale []types.NodeTaskKey
+	for _, task := range tasks {
+		state, known, err := taskState(crdClient, task)
+		if !known {
+			continue
+		}
+		if apierrors.IsNotFound(err) || (err == nil && fsm.TaskFinish(state)) {
+			stale = append(stale, task)
+		} else if err != nil {
+			klog.Warningf("failed to check task %s/%s: %v", task.Type, task.TaskID, err)
+		}
+	}
+	return stale
+}
+
+// taskState returns the state of the object of the task, known is false if the task type is not registered
+func taskState(crdClient crdClientset.Interface, task types.NodeTaskKey) (state api.State, known bool, err error) {
+	objects, ok := util.GetTaskObjects(task.Type)
+	if !ok {
+		return "", false, nil
+	}
+	object, err := objects.Client(crdClient).Get(context.TODO(), task.TaskID)
+	if err != nil {
+		return "", true, err
+	}
+	return objects.Status(object).State, true, nil
+}
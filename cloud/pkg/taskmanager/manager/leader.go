@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/klog/v2"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
+	"github.com/kubeedge/kubeedge/common/constants"
+)
+
+// leaderElectionEnabled returns whether the executors run on the elected replica of cloudcore only
+func leaderElectionEnabled() bool {
+	return config.Config.LeaderElection != nil && config.Config.LeaderElection.Enable
+}
+
+// runLeaderElection takes part in the election of the replica running the executors until cloudcore
+// stops. The replica which is elected rebuilds the executors of the unfinished tasks from their recorded
+// status, the replica which loses the lease gives up its executors to the next leader.
+func (em *ExecutorMachine) runLeaderElection() {
+	election := config.Config.LeaderElection
+	hostname, err := os.Hostname()
+	if err != nil {
+		klog.Exitf("failed to get hostname for the taskmanager leader election: %v", err)
+	}
+	identity := hostname + "_" + uuid.New().String()
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Namespace: constants.SystemNamespace,
+			Name:      election.LeaseName,
+		},
+		Client:     em.kubeClient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+	}
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   time.Duration(election.LeaseDurationSeconds) * time.Second,
+		RenewDeadline:   time.Duration(election.RenewDeadlineSeconds) * time.Second,
+		RetryPeriod:     time.Duration(election.RetryPeriodSeconds) * time.Second,
+		ReleaseOnCancel: true,
+		Name:            election.LeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(context.Context) {
+				em.startLeading(identity)
+			},
+			OnStoppedLeading: func() {
+				em.stopLeading(identity)
+			},
+		},
+	})
+	if err != nil {
+		klog.Exitf("invalid taskmanager leader election config: %v", err)
+	}
+
+	ctx := beehiveContext.GetContext()
+	for ctx.Err() == nil {
+		// Run returns once the lease is lost, the replica stands for the election again
+		elector.Run(ctx)
+	}
+}
+
+// startLeading lets the executors run on this replica, the executors of the unfinished tasks are
+// rebuilt from their recorded status, so the nodes the previous leader dispatched are adopted
+func (em *ExecutorMachine) startLeading(identity string) {
+	klog.Infof("%s is elected to run the executors of the tasks", identity)
+	em.leading.Store(true)
+	if err := controller.ResyncAllControllers(); err != nil {
+		// the tasks not resynced are adopted on their next update
+		klog.Errorf("failed to adopt the unfinished tasks: %v", err)
+	}
+}
+
+// stopLeading stops the executors of this replica, the tasks keep running on the edge nodes and
+// are adopted by the next leader
+func (em *ExecutorMachine) stopLeading(identity string) {
+	em.leading.Store(false)
+	em.Lock()
+	executors := em.executors
+	em.executors = map[string]*Executor{}
+	monitor.TaskActiveExecutors.Set(0)
+	em.Unlock()
+	for _, e := range executors {
+		e.stop()
+		inFlight.forget(e)
+		em.pending.remove(e.task.Name)
+	}
+	klog.Warningf("%s lost the lease, gave up the executors of %d tasks", identity, len(executors))
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// holdMembers marks the members of the node groups of the task to be resolved again once it is resumed,
+// as long as no node is dispatched the nodes joining or leaving the groups while it is paused are taken into account.
+func (e *Executor) holdMembers() {
+	if len(e.task.NodeGroups) == 0 || len(e.workers.jobs) != 0 {
+		return
+	}
+	for _, node := range e.nodes {
+		if node.State != "" {
+			return
+		}
+	}
+	e.membersStale = true
+}
+
+// refreshMembers resolves the nodes of the task again, it returns true if they changed
+func (e *Executor) refreshMembers() bool {
+	e.membersStale = false
+	nodes, excluded := e.controller.ValidateNode(e.task)
+	if len(nodes) == 0 {
+		klog.Warningf("task %s selects no node any more, keep its %d nodes", e.task.Name, len(e.nodes))
+		return false
+	}
+	current := make(map[string]v1alpha1.TaskStatus, len(e.nodes))
+	for _, node := range e.nodes {
+		current[node.NodeName] = node
+	}
+	changed := len(nodes) != len(e.nodes)
+	statuses := make([]v1alpha1.TaskStatus, len(nodes))
+	for i, node := range nodes {
+		status, ok := current[node.Name]
+		if !ok {
+			status = v1alpha1.TaskStatus{NodeName: node.Name}
+			changed = true
+		}
+		statuses[i] = status
+	}
+	if !changed {
+		return false
+	}
+
+	klog.Infof("the node groups of task %s changed, it runs on %d nodes instead of %d", e.task.Name, len(statuses), len(e.nodes))
+	util.RecordTaskEvent(e.task, v1.EventTypeNormal, "MembersChanged",
+		"The node groups changed while the task was paused, it runs on %d nodes instead of %d", len(statuses), len(e.nodes))
+	e.nodes = statuses
+	if err := e.controller.UpdateNodeStatus(e.task.Name, e.nodes); err != nil {
+		klog.Warningf("failed to update the nodes of task %s: %v", e.task.Name, err)
+	}
+	if err := recordExcludedNodes(e.controller, e.task, len(nodes), excluded); err != nil {
+		klog.Warningf("failed to record the excluded nodes of task %s: %v", e.task.Name, err)
+	}
+	e.updateFailureBudget()
+	e.initCanary()
+	e.resizeWorkers()
+	if e.task.DryRun {
+		e.recordPlan()
+	}
+	return true
+}
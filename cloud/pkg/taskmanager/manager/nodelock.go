@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"errors"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+)
+
+// nodeLockRetryInterval is how long the nodes locked by other tasks wait before they are tried again
+const nodeLockRetryInterval = 30 * time.Second
+
+// errNodeLocked is returned when the node to dispatch is operated on by another task
+var errNodeLocked = errors.New("the node is operated on by another task")
+
+// deferNode moves the node at index, which is not dispatched yet, behind the nodes after it so that
+// they are dispatched first. The canary nodes waiting for approval stay in front of the other nodes.
+func (e *Executor) deferNode(index int) {
+	node := e.nodes[index]
+	canary := e.canary != nil && !e.approved
+	last := index
+	for last+1 < len(e.nodes) && (!canary || e.canary[e.nodes[last+1].NodeName] == e.canary[node.NodeName]) {
+		last++
+	}
+	copy(e.nodes[index:last], e.nodes[index+1:last+1])
+	e.nodes[last] = node
+}
+
+// retryLockedNodes notifies requeueChan to dispatch the locked nodes again after nodeLockRetryInterval
+func (e *Executor) retryLockedNodes(locked int) {
+	if e.lockTimer != nil {
+		return
+	}
+	klog.Infof("%d nodes of task %s are operated on by other tasks, retry them in %s", locked, e.task.Name, nodeLockRetryInterval)
+	util.RecordTaskEvent(e.task, v1.EventTypeWarning, "NodesLocked",
+		"%d nodes are operated on by other tasks, retry them in %s", locked, nodeLockRetryInterval)
+	e.lockTimer = time.AfterFunc(nodeLockRetryInterval, func() {
+		select {
+		case e.requeueChan <- struct{}{}:
+		default:
+		}
+	})
+}
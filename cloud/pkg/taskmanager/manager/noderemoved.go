@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+const nodeRemovedReason = "the node was removed from the cluster"
+
+// watchNodeRemoval notifies the running executors of the nodes deleted from the cluster
+func (em *ExecutorMachine) watchNodeRemoval() error {
+	nodeInformer := informers.GetInformersManager().GetKubeInformerFactory().Core().V1().Nodes().Informer()
+	_, err := nodeInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			node, ok := obj.(*v1.Node)
+			if !ok {
+				klog.Warningf("object type: %T unsupported", obj)
+				return
+			}
+			em.nodeRemoved(node.Name)
+		},
+	})
+	return err
+}
+
+func (em *ExecutorMachine) nodeRemoved(nodeName string) {
+	em.Lock()
+	defer em.Unlock()
+	for _, e := range em.executors {
+		select {
+		case e.removedChan <- nodeName:
+		default:
+			// the node times out as usual, the informer is not blocked by a busy executor
+			klog.Warningf("failed to notify task %s of the removal of node %s", e.task.Name, nodeName)
+		}
+	}
+}
+
+// removeNode marks the node NodeRemoved if it has not finished the task and releases its worker.
+// It returns false if the node is not part of the task or already finished it.
+func (e *Executor) removeNode(nodeName string) bool {
+	index := e.nodeIndex(nodeName)
+	if index < 0 || fsm.TaskFinish(e.nodes[index].State) {
+		return false
+	}
+	klog.Warningf("node %s of task %s is removed from the cluster", nodeName, e.task.Name)
+
+	e.releaseNode(nodeName)
+	util.RecordTaskEvent(e.task, v1.EventTypeWarning, "NodeRemoved", "Node %s is removed from the cluster", nodeName)
+	e.markNode(index, api.TaskNodeRemoved, nodeRemovedReason)
+	return true
+}
+
+// nodeIndex returns the index of the node in the task, it is -1 if the node is not part of the task
+func (e *Executor) nodeIndex(nodeName string) int {
+	for i := range e.nodes {
+		if e.nodes[i].NodeName == nodeName {
+			return i
+		}
+	}
+	return -1
+}
+
+// releaseNode frees the worker of the node which is completed by the executor instead of the edge
+func (e *Executor) releaseNode(nodeName string) {
+	e.workers.Lock()
+	_, ok := e.workers.jobs[nodeName]
+	delete(e.workers.jobs, nodeName)
+	e.workers.Unlock()
+	if ok {
+		inFlight.release(e)
+		e.markProgress()
+	}
+	delete(e.queue.dispatched, nodeName)
+}
+
+// markNode moves the node at index to the state and persists it in the node status of the task
+func (e *Executor) markNode(index int, state api.State, reason string) {
+	nodeName := e.nodes[index].NodeName
+	e.nodes[index].State = state
+	e.nodes[index].Reason = reason
+	e.nodes[index].Time = time.Now().UTC().Format(util.ISO8601UTC)
+
+	nodeStatus, err := e.controller.GetNodeStatus(e.task.Name)
+	if err != nil {
+		klog.Errorf("failed to get node status of task %s: %v", e.task.Name, err)
+		return
+	}
+	for i := range nodeStatus {
+		if nodeStatus[i].NodeName != nodeName {
+			continue
+		}
+		nodeStatus[i].State = state
+		nodeStatus[i].Reason = reason
+		nodeStatus[i].Time = e.nodes[index].Time
+		if err = e.controller.UpdateNodeStatus(e.task.Name, nodeStatus); err != nil {
+			klog.Errorf("failed to mark node %s of task %s %s: %v", nodeName, e.task.Name, state, err)
+		}
+		return
+	}
+}
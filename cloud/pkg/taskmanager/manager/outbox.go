@@ -0,0 +1,218 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/klog/v2"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+)
+
+const (
+	// downstreamRetryInterval is how long a message waits before it is queued again while the
+	// downstream queue is full, it doubles up to maxDownstreamRetryInterval
+	downstreamRetryInterval    = 50 * time.Millisecond
+	maxDownstreamRetryInterval = time.Second
+)
+
+// outbox sends the task messages to the downstream queue without blocking the executors. The
+// messages are limited by the token bucket of their task and the global one, the messages held by
+// the buckets or by the full queue are sent in order for each task.
+type outbox struct {
+	sync.Mutex
+	// queues are the messages held for each task, a task has a queue while its messages are held
+	queues map[string][]heldMessage
+	// tasks are the token buckets of the tasks
+	tasks     map[string]*rate.Limiter
+	global    *rate.Limiter
+	taskLimit rate.Limit
+	taskBurst int
+	// send writes the message to the downstream queue, it returns false if the queue is full
+	send func(model.Message) bool
+}
+
+type heldMessage struct {
+	msg model.Message
+	// admitted is set if the message already took the tokens of the buckets
+	admitted bool
+}
+
+// newOutbox returns the outbox writing the messages with send, the rates are not limited if
+// limits is nil
+func newOutbox(limits *v1alpha1.TaskManagerDownstreamRateLimit, send func(model.Message) bool) *outbox {
+	o := &outbox{
+		queues:    map[string][]heldMessage{},
+		tasks:     map[string]*rate.Limiter{},
+		global:    rate.NewLimiter(rate.Inf, 0),
+		taskLimit: rate.Inf,
+		send:      send,
+	}
+	if limits == nil {
+		return o
+	}
+	if limits.QPS > 0 {
+		o.global = rate.NewLimiter(rate.Limit(limits.QPS), burst(limits.Burst, limits.QPS))
+	}
+	if limits.TaskQPS > 0 {
+		o.taskLimit = rate.Limit(limits.TaskQPS)
+		o.taskBurst = burst(limits.TaskBurst, limits.TaskQPS)
+	}
+	return o
+}
+
+func burst(burst, qps int32) int {
+	if burst > 0 {
+		return int(burst)
+	}
+	return int(qps)
+}
+
+// add sends the message of the task, it is sent right away if the task has no message held, the
+// buckets have tokens and the queue is not full, or else it is held and sent in background
+func (o *outbox) add(taskID string, msg model.Message) {
+	o.Lock()
+	if _, held := o.queues[taskID]; held {
+		o.queues[taskID] = append(o.queues[taskID], heldMessage{msg: msg})
+		o.Unlock()
+		monitor.TaskDownstreamHeldMessages.Inc()
+		return
+	}
+	admitted := o.admit(taskID, time.Now())
+	if admitted && o.send(msg) {
+		o.Unlock()
+		return
+	}
+	o.queues[taskID] = []heldMessage{{msg: msg, admitted: admitted}}
+	o.Unlock()
+	monitor.TaskDownstreamHeldMessages.Inc()
+	go o.run(taskID)
+}
+
+// admit takes the tokens of the message of the task if both buckets have one now
+func (o *outbox) admit(taskID string, now time.Time) bool {
+	task := o.taskLimiter(taskID)
+	taskReservation := task.ReserveN(now, 1)
+	if taskReservation.DelayFrom(now) != 0 {
+		taskReservation.CancelAt(now)
+		return false
+	}
+	globalReservation := o.global.ReserveN(now, 1)
+	if globalReservation.DelayFrom(now) != 0 {
+		globalReservation.CancelAt(now)
+		taskReservation.CancelAt(now)
+		return false
+	}
+	return true
+}
+
+// taskLimiter returns the token bucket of the task, it must be called with the lock held
+func (o *outbox) taskLimiter(taskID string) *rate.Limiter {
+	limiter, ok := o.tasks[taskID]
+	if !ok {
+		limiter = rate.NewLimiter(o.taskLimit, o.taskBurst)
+		o.tasks[taskID] = limiter
+	}
+	return limiter
+}
+
+// run sends the held messages of the task in order until none is left or cloudcore stops
+func (o *outbox) run(taskID string) {
+	ctx := beehiveContext.GetContext()
+	for {
+		o.Lock()
+		queue := o.queues[taskID]
+		if len(queue) == 0 {
+			delete(o.queues, taskID)
+			o.Unlock()
+			return
+		}
+		held := queue[0]
+		o.queues[taskID] = queue[1:]
+		task := o.taskLimiter(taskID)
+		o.Unlock()
+
+		if !held.admitted {
+			if err := o.wait(ctx, task); err != nil {
+				return
+			}
+		}
+		if err := o.deliver(ctx, held.msg); err != nil {
+			return
+		}
+		monitor.TaskDownstreamHeldMessages.Dec()
+	}
+}
+
+// wait waits for the tokens of the buckets of the task and the global one
+func (o *outbox) wait(ctx context.Context, task *rate.Limiter) error {
+	throttled := false
+	for _, limiter := range []*rate.Limiter{task, o.global} {
+		reservation := limiter.Reserve()
+		delay := reservation.Delay()
+		if delay == 0 {
+			continue
+		}
+		throttled = true
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			reservation.Cancel()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if throttled {
+		monitor.TaskDownstreamThrottled.Inc()
+	}
+	return nil
+}
+
+// deliver writes the message to the downstream queue, it is retried with backoff while the queue is full
+func (o *outbox) deliver(ctx context.Context, msg model.Message) error {
+	interval := downstreamRetryInterval
+	for !o.send(msg) {
+		monitor.TaskDownstreamRetries.Inc()
+		klog.V(4).Infof("downstream queue is full, send message %s again in %s", msg.GetID(), interval)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+		interval = min(interval*2, maxDownstreamRetryInterval)
+	}
+	return nil
+}
+
+// forget drops the held messages and the token bucket of the deleted task
+func (o *outbox) forget(taskID string) {
+	o.Lock()
+	defer o.Unlock()
+	if queue, held := o.queues[taskID]; held {
+		monitor.TaskDownstreamHeldMessages.Sub(float64(len(queue)))
+		o.queues[taskID] = nil
+	}
+	delete(o.tasks, taskID)
+}
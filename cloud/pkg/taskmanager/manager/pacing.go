@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/connection"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	cloudcorev1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// flappingWindow is the period in which the disconnects of a node are counted
+const flappingWindow = 10 * time.Minute
+
+const (
+	rankStarted = iota
+	rankHealthy
+	rankSlow
+	rankFlapping
+)
+
+// paceNodes sorts the nodes by connection unless the task orders them itself. The seeded and the
+// readiness orders are recorded with the node status, the nodes must be dispatched in that order
+// so that the recorded seed reproduces it.
+func paceNodes(task util.TaskMessage, nodes []v1alpha1.TaskStatus, pacing *cloudcorev1alpha1.TaskManagerDispatchPacing,
+	getQuality func(nodeID string) connection.Quality) {
+	if task.OrderingSeed != nil || task.OrderByReadiness {
+		return
+	}
+	sortNodesByConnection(nodes, pacing, getQuality)
+}
+
+// sortNodesByConnection moves the nodes not started yet with poor connections to the end
+// of the task, so that they are less likely to time out while other nodes are waiting.
+// Nodes already started keep their order at the front.
+func sortNodesByConnection(nodes []v1alpha1.TaskStatus, pacing *cloudcorev1alpha1.TaskManagerDispatchPacing,
+	getQuality func(nodeID string) connection.Quality) {
+	if pacing == nil || !pacing.Enable {
+		return
+	}
+	ranks := make(map[string]int, len(nodes))
+	since := time.Now().Add(-flappingWindow)
+	for _, node := range nodes {
+		if node.State != "" {
+			ranks[node.NodeName] = rankStarted
+			continue
+		}
+		quality := getQuality(node.NodeName)
+		switch {
+		case quality.Known && !quality.Connected,
+			quality.DisconnectsSince(since) >= int(pacing.FlappingDisconnects):
+			ranks[node.NodeName] = rankFlapping
+		case pacing.SlowRTTMilliseconds > 0 && quality.RTT > time.Duration(pacing.SlowRTTMilliseconds)*time.Millisecond:
+			ranks[node.NodeName] = rankSlow
+		default:
+			ranks[node.NodeName] = rankHealthy
+		}
+		if ranks[node.NodeName] != rankHealthy {
+			klog.V(2).Infof("node %s has poor connection (rtt %s, connected %t), dispatch it later",
+				node.NodeName, quality.RTT, quality.Connected)
+		}
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return ranks[nodes[i].NodeName] < ranks[nodes[j].NodeName]
+	})
+}
+
+// dispatchJitter returns a random delay below the given seconds, it spreads the dispatches
+// of a task so that the nodes do not hit the upstream infrastructure at the same time.
+func dispatchJitter(seconds int32) time.Duration {
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(time.Duration(seconds) * time.Second)))
+}
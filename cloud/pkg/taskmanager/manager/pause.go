@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+)
+
+// PauseExecutor requests the running executor of the task to pause or resume it
+func PauseExecutor(msg util.TaskMessage) {
+	executorMachine.Lock()
+	e, ok := executorMachine.executors[fmt.Sprintf("%s::%s", msg.Type, msg.Name)]
+	executorMachine.Unlock()
+	if !ok {
+		klog.Warningf("task %s to pause or resume is not running", msg.Name)
+		return
+	}
+	select {
+	case e.pauseChan <- msg.Paused:
+	default:
+		klog.Warningf("failed to set task %s paused: %t, the executor is busy", msg.Name, msg.Paused)
+	}
+}
+
+// pause stops or resumes dispatching new nodes, the nodes already dispatched run to completion.
+// It returns true if the task is resumed and the dispatch has to continue.
+func (e *Executor) pause(paused bool) bool {
+	if e.paused == paused {
+		return false
+	}
+	e.paused = paused
+	if paused {
+		klog.Infof("pause task %s, %d nodes are still running", e.task.Name, len(e.workers.jobs))
+		util.RecordTaskEvent(e.task, v1.EventTypeNormal, "Paused", "The task is paused, no new node is dispatched")
+		return false
+	}
+	klog.Infof("resume task %s", e.task.Name)
+	e.resetPauseOn()
+	util.RecordTaskEvent(e.task, v1.EventTypeNormal, "Resumed", "The task is resumed")
+	return true
+}
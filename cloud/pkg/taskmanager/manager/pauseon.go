@@ -0,0 +1,186 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+const (
+	defaultFailureRateWindowSeconds = 600
+	defaultFailureRateMinNodes      = 1
+)
+
+// nodeOutcome is the outcome of a node which finished the task, the failure rate of PauseOn is
+// computed from the outcomes within its window
+type nodeOutcome struct {
+	at     time.Time
+	failed bool
+}
+
+// taskPauser is implemented by the controllers of the tasks which can be paused, PauseTask sets
+// Paused in the spec of the task
+type taskPauser interface {
+	PauseTask(name string) error
+}
+
+// FireAlerts pauses the running tasks which pause on one of the firing external alerts, it returns
+// the names of the tasks asked to pause
+func (em *ExecutorMachine) FireAlerts(alerts []string) []string {
+	if readOnly() {
+		return nil
+	}
+	em.Lock()
+	executors := make([]*Executor, 0, len(em.executors))
+	for _, e := range em.executors {
+		executors = append(executors, e)
+	}
+	em.Unlock()
+	var paused []string
+	for _, e := range executors {
+		alert := matchAlert(e.task.PauseOn, alerts)
+		if alert == "" {
+			continue
+		}
+		select {
+		case e.alertChan <- alert:
+			paused = append(paused, e.task.Name)
+		default:
+			klog.Warningf("failed to pause task %s on alert %s, the executor is busy", e.task.Name, alert)
+		}
+	}
+	return paused
+}
+
+// matchAlert returns the first of the firing alerts the task pauses on, it is empty if none
+func matchAlert(pauseOn *v1alpha1.PauseOnSpec, alerts []string) string {
+	if pauseOn == nil {
+		return ""
+	}
+	for _, alert := range alerts {
+		for _, name := range pauseOn.ExternalAlerts {
+			if alert == name {
+				return alert
+			}
+		}
+	}
+	return ""
+}
+
+// checkPauseOn records the outcome of the node which finished the task, and pauses the task once
+// one of its PauseOn conditions matches
+func (e *Executor) checkPauseOn(node v1alpha1.TaskStatus) {
+	pauseOn := e.task.PauseOn
+	if pauseOn == nil || (node.State != api.TaskSuccessful && node.State != api.TaskFailed) {
+		return
+	}
+	now := time.Now()
+	if pauseOn.FailureRate != nil {
+		window := failureRateWindow(pauseOn.FailureRate)
+		outcomes := e.outcomes[:0]
+		for _, outcome := range e.outcomes {
+			if now.Sub(outcome.at) <= window {
+				outcomes = append(outcomes, outcome)
+			}
+		}
+		e.outcomes = append(outcomes, nodeOutcome{at: now, failed: node.State == api.TaskFailed})
+	}
+	if e.paused || e.cancelled {
+		return
+	}
+	if reason := pauseOnReason(pauseOn, len(e.failedNodes)-e.pauseOnBaseline, e.outcomes, now); reason != "" {
+		e.autoPause(reason)
+	}
+}
+
+// pauseOnReason returns why the task pauses, it is empty if no condition matches. failed is the number
+// of nodes which failed since the task started or resumed, outcomes are the nodes which finished since.
+func pauseOnReason(pauseOn *v1alpha1.PauseOnSpec, failed int, outcomes []nodeOutcome, now time.Time) string {
+	if pauseOn.FailedNodes != nil && failed >= int(*pauseOn.FailedNodes) {
+		return fmt.Sprintf("%d nodes failed, it pauses on %d failed nodes", failed, *pauseOn.FailedNodes)
+	}
+	rule := pauseOn.FailureRate
+	if rule == nil {
+		return ""
+	}
+	rate, err := strconv.Atoi(strings.TrimSuffix(rule.Rate, "%"))
+	if err != nil {
+		klog.Warningf("invalid pauseOn failure rate %q: %v", rule.Rate, err)
+		return ""
+	}
+	window := failureRateWindow(rule)
+	var finished, failedInWindow int
+	for _, outcome := range outcomes {
+		if now.Sub(outcome.at) > window {
+			continue
+		}
+		finished++
+		if outcome.failed {
+			failedInWindow++
+		}
+	}
+	minNodes := defaultFailureRateMinNodes
+	if rule.MinNodes != nil {
+		minNodes = int(*rule.MinNodes)
+	}
+	if finished < minNodes || failedInWindow == 0 || failedInWindow*100 < rate*finished {
+		return ""
+	}
+	return fmt.Sprintf("%d of the %d nodes finished in the last %s failed, it pauses on a failure rate of %s",
+		failedInWindow, finished, window, rule.Rate)
+}
+
+func failureRateWindow(rule *v1alpha1.FailureRateRule) time.Duration {
+	if rule.WindowSeconds == nil {
+		return defaultFailureRateWindowSeconds * time.Second
+	}
+	return time.Duration(*rule.WindowSeconds) * time.Second
+}
+
+// autoPause pauses the task once one of its PauseOn conditions matched. Paused is set in the spec
+// of the task as well, so that the task stays paused until it is resumed.
+func (e *Executor) autoPause(reason string) {
+	if e.paused || e.cancelled {
+		return
+	}
+	klog.Warningf("pause task %s, %s", e.task.Name, reason)
+	util.RecordTaskEvent(e.task, v1.EventTypeWarning, "AutoPaused", "The task pauses on its conditions, %s", reason)
+	e.pause(true)
+	pauser, ok := e.controller.(taskPauser)
+	if !ok {
+		return
+	}
+	if err := pauser.PauseTask(e.task.Name); err != nil {
+		klog.Warningf("failed to set task %s paused: %v", e.task.Name, err)
+	}
+}
+
+// resetPauseOn evaluates the PauseOn conditions of the task from now on, it is called once the task is resumed
+func (e *Executor) resetPauseOn() {
+	e.pauseOnBaseline = len(e.failedNodes)
+	e.outcomes = nil
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+)
+
+// skipPendingRemoval marks the node at index Skipped instead of dispatching it if the cluster
+// autoscaling tools are about to remove it, so that no effort is wasted on a disappearing node.
+func (e *Executor) skipPendingRemoval(index int) bool {
+	if e.workers.stopped() {
+		return false
+	}
+	nodeName := e.nodes[index].NodeName
+	node, err := informers.GetInformersManager().GetKubeInformerFactory().Core().V1().Nodes().Lister().Get(nodeName)
+	if err != nil {
+		return false
+	}
+	marker, ok := util.PendingRemoval(node)
+	if !ok {
+		return false
+	}
+	klog.Warningf("skip node %s of task %s, it is pending removal: %s", nodeName, e.task.Name, marker)
+	util.RecordTaskEvent(e.task, v1.EventTypeWarning, "SkippedPendingRemoval",
+		"Node %s is skipped, it is pending removal: %s", nodeName, marker)
+	e.markNode(index, api.TaskSkipped, "the node is pending removal: "+marker)
+	e.resizeWorkers()
+	return true
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	operationslisters "github.com/kubeedge/kubeedge/pkg/client/listers/operations/v1alpha1"
+)
+
+// taskTypeConfigs lists the TaskTypeConfigs which override the default pipelines of the task types,
+// it is nil until the TaskTypeConfigs are watched
+var taskTypeConfigs operationslisters.TaskTypeConfigLister
+
+// watchTaskTypeConfigs records whether the TaskTypeConfigs are accepted as they change. The overrides
+// are read when they are applied, a change applies to the tasks whose executor starts afterwards.
+func (em *ExecutorMachine) watchTaskTypeConfigs() error {
+	informer := informers.GetInformersManager().GetKubeEdgeInformerFactory().Operations().V1alpha1().TaskTypeConfigs()
+	taskTypeConfigs = informer.Lister()
+	_, err := informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			recordTaskTypeConfig(obj)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			recordTaskTypeConfig(obj)
+		},
+	})
+	return err
+}
+
+// taskPipeline returns the pipeline the tasks of the type run with, the default pipeline registered by
+// the type overridden by the TaskTypeConfig named after it, if any and valid
+func taskPipeline(taskType string) controller.Pipeline {
+	pipeline, _ := controller.GetPipeline(taskType)
+	if taskTypeConfigs == nil {
+		return pipeline
+	}
+	config, err := taskTypeConfigs.Get(taskType)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.Warningf("failed to get TaskTypeConfig %s: %v", taskType, err)
+		}
+		return pipeline
+	}
+	overridden, err := pipeline.Override(config.Spec)
+	if err != nil {
+		klog.Warningf("TaskTypeConfig %s is not accepted, the default pipeline is used: %v", taskType, err)
+		return pipeline
+	}
+	return overridden
+}
+
+// taskTypeConfigStatus validates the override against the task type it is named after
+func taskTypeConfigStatus(config *v1alpha1.TaskTypeConfig) v1alpha1.TaskTypeConfigStatus {
+	status := v1alpha1.TaskTypeConfigStatus{ObservedGeneration: config.Generation}
+	if !controller.IsRegistered(config.Name) {
+		status.Reason = fmt.Sprintf("no task type %s is registered", config.Name)
+		return status
+	}
+	pipeline, _ := controller.GetPipeline(config.Name)
+	for _, stage := range pipeline.Stages {
+		status.Stages = append(status.Stages, string(stage))
+	}
+	if _, err := pipeline.Override(config.Spec); err != nil {
+		status.Reason = err.Error()
+		return status
+	}
+	status.Accepted = true
+	return status
+}
+
+// recordTaskTypeConfig records whether the TaskTypeConfig is accepted in its status, nothing is
+// recorded in read-only mode
+func recordTaskTypeConfig(obj interface{}) {
+	config, ok := obj.(*v1alpha1.TaskTypeConfig)
+	if !ok {
+		klog.Warningf("object type: %T unsupported", obj)
+		return
+	}
+	status := taskTypeConfigStatus(config)
+	if reflect.DeepEqual(status, config.Status) || readOnly() {
+		return
+	}
+	if status.Accepted {
+		klog.Infof("TaskTypeConfig %s overrides the default pipeline of the task type", config.Name)
+	} else {
+		klog.Warningf("TaskTypeConfig %s is not accepted: %s", config.Name, status.Reason)
+	}
+	update := config.DeepCopy()
+	update.Status = status
+	_, err := client.GetCRDClient().OperationsV1alpha1().TaskTypeConfigs().UpdateStatus(context.TODO(), update, metav1.UpdateOptions{})
+	if err != nil {
+		klog.Warningf("failed to update the status of TaskTypeConfig %s: %v", config.Name, err)
+	}
+}
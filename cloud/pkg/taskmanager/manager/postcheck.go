@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+const (
+	// defaultPostCheckTimeoutSeconds bounds the post check if its timeout is not set
+	defaultPostCheckTimeoutSeconds = 300
+	// postCheckInterval is how often the node is checked until it is verified
+	postCheckInterval = 5 * time.Second
+)
+
+// verify starts the post check of the node which reported it is upgraded, once per node. The node
+// keeps its worker until it is verified.
+func (e *Executor) verify(node v1alpha1.TaskStatus) {
+	if e.task.PostCheck == nil || e.verifying[node.NodeName] {
+		return
+	}
+	e.verifying[node.NodeName] = true
+	klog.Infof("verify node %s of task %s", node.NodeName, e.task.Name)
+	go e.postCheck(node.NodeName, e.task.Version, *e.task.PostCheck)
+}
+
+// postCheck checks the node until it is verified or the post check times out, and finishes the node
+// with the verdict
+func (e *Executor) postCheck(nodeName, version string, postCheck v1alpha1.PostCheckSpec) {
+	timeout := time.Duration(defaultPostCheckTimeoutSeconds) * time.Second
+	if postCheck.TimeoutSeconds != nil {
+		timeout = time.Duration(*postCheck.TimeoutSeconds) * time.Second
+	}
+	var canary *smokeTest
+	if postCheck.SmokeTest != nil {
+		canary = &smokeTest{task: e.task, nodeName: nodeName, spec: *postCheck.SmokeTest}
+		defer canary.cleanup()
+	}
+	var reason string
+	err := wait.PollUntilContextTimeout(beehiveContext.GetContext(), postCheckInterval, timeout, true, func(context.Context) (bool, error) {
+		reason = checkUpgradedNode(nodeName, version, postCheck.CriticalPods)
+		if reason == "" && canary != nil {
+			reason = canary.check()
+		}
+		return reason == "", nil
+	})
+	event := fsm.Event{
+		Type:   api.EventPostCheck,
+		Action: api.ActionSuccess,
+		Msg:    "the node is verified",
+	}
+	if err != nil {
+		if reason == "" {
+			reason = err.Error()
+		}
+		event.Action = api.ActionFailure
+		event.Msg = fmt.Sprintf("the node is not verified within %s: %s", timeout, reason)
+		util.RecordTaskEvent(e.task, v1.EventTypeWarning, "PostCheckFailed", "Node %s is not verified: %s", nodeName, reason)
+	}
+	if _, err = e.controller.ReportNodeStatus(e.task.Name, nodeName, event); err != nil {
+		klog.Warningf("failed to report the post check of node %s of task %s: %v", nodeName, e.task.Name, err)
+	}
+}
+
+// checkUpgradedNode returns why the node is not verified yet, it is empty once the node is Ready,
+// reports EdgeCore of the version and runs the critical pods. The version is not checked if it is empty.
+func checkUpgradedNode(nodeName, version string, criticalPods []v1alpha1.CriticalPodSelector) string {
+	node, err := informers.GetInformersManager().GetKubeInformerFactory().Core().V1().Nodes().Lister().Get(nodeName)
+	if err != nil {
+		return fmt.Sprintf("failed to get the node: %v", err)
+	}
+	ready := false
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			ready = condition.Status == v1.ConditionTrue
+		}
+	}
+	if !ready {
+		return "the node is not Ready"
+	}
+	if reported := edgeCoreVersion(node.Status.NodeInfo.KubeletVersion); version != "" && reported != version {
+		return fmt.Sprintf("the node reports EdgeCore %s, not %s", reported, version)
+	}
+	for _, critical := range criticalPods {
+		selector, err := metav1.LabelSelectorAsSelector(critical.LabelSelector)
+		if err != nil {
+			return fmt.Sprintf("invalid selector of the critical pods in namespace %s: %v", critical.Namespace, err)
+		}
+		pods, err := client.GetKubeClient().CoreV1().Pods(critical.Namespace).List(context.TODO(), metav1.ListOptions{
+			LabelSelector: selector.String(),
+			FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+		})
+		if err != nil {
+			return fmt.Sprintf("failed to list the critical pods in namespace %s: %v", critical.Namespace, err)
+		}
+		if len(pods.Items) == 0 {
+			return fmt.Sprintf("no critical pod %s in namespace %s runs on the node", selector, critical.Namespace)
+		}
+		for _, pod := range pods.Items {
+			if pod.Status.Phase != v1.PodRunning {
+				return fmt.Sprintf("critical pod %s/%s is %s", pod.Namespace, pod.Name, pod.Status.Phase)
+			}
+		}
+	}
+	return ""
+}
+
+// edgeCoreVersion returns the version of EdgeCore in the kubelet version reported by the edge node,
+// e.g. v1.10.0 for v1.22.6-kubeedge-v1.10.0-beta.0.185+95378fb019912a
+func edgeCoreVersion(kubeletVersion string) string {
+	strs := strings.Split(kubeletVersion, "-")
+	if len(strs) < 3 {
+		return kubeletVersion
+	}
+	return strs[2]
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/common/types"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// defaultPreflightTimeoutSeconds bounds the preflight probe if its timeout is not set
+const defaultPreflightTimeoutSeconds = 10
+
+// ProbeAnswered records the answer of the edge node to the preflight probe of the task
+func (em *ExecutorMachine) ProbeAnswered(taskType, taskID, nodeName string) {
+	em.Lock()
+	e, ok := em.executors[fmt.Sprintf("%s::%s", taskType, taskID)]
+	em.Unlock()
+	if !ok {
+		klog.V(4).Infof("ignore probe answer of node %s for inactive task %s", nodeName, taskID)
+		return
+	}
+	select {
+	case e.probeChan <- nodeName:
+	default:
+		// the probes are over, the answer came too late
+		klog.V(4).Infof("ignore late probe answer of node %s for task %s", nodeName, taskID)
+	}
+}
+
+// preflight probes the nodes of the task over the task channel before it is dispatched to them, and
+// fails the nodes which do not answer in time so that they take no worker. Only the task which has
+// not dispatched any node yet is probed, the nodes of a task resumed after cloudcore restarted are not,
+// nor the nodes of a task observed by a read-only TaskManager. It returns false if the task is stopped
+// meanwhile.
+func (e *Executor) preflight() bool {
+	if e.task.Preflight == nil || len(e.reconcileNodes) != 0 || readOnly() {
+		return true
+	}
+	timeout := preflightTimeout(e.task.Preflight)
+	sent := map[string]time.Time{}
+	for _, node := range e.nodes {
+		if fsm.TaskFinish(node.State) {
+			continue
+		}
+		msg := model.NewMessage("").
+			BuildRouter(modules.TaskManagerModuleName, modules.TaskManagerModuleGroup,
+				buildTaskResource(e.task.Type, e.task.Name, node.NodeName), util.TaskProbe).
+			FillBody(types.NodeTaskProbe{NodeName: node.NodeName, Type: e.task.Type, TaskID: e.task.Name})
+		sent[node.NodeName] = time.Now()
+		executorMachine.dispatch(node.NodeName, e.task.Name, *msg)
+	}
+	if len(sent) == 0 {
+		return true
+	}
+	klog.Infof("probe %d nodes of task %s, wait %s for their answers", len(sent), e.task.Name, timeout)
+
+	probed := len(sent)
+	var slowest time.Duration
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for waiting := true; waiting && len(sent) != 0; {
+		select {
+		case <-beehiveContext.Done():
+			return false
+		case <-e.stopChan:
+			return false
+		case nodeName := <-e.probeChan:
+			start, ok := sent[nodeName]
+			if !ok {
+				break
+			}
+			delete(sent, nodeName)
+			rtt := time.Since(start)
+			slowest = max(slowest, rtt)
+			monitor.TaskProbeRoundTripSeconds.WithLabelValues(e.task.Type).Observe(rtt.Seconds())
+			klog.V(4).Infof("node %s answered the probe of task %s in %s", nodeName, e.task.Name, rtt)
+		case <-timer.C:
+			waiting = false
+		}
+	}
+
+	reason := fmt.Sprintf("the node did not answer the preflight probe within %s", timeout)
+	for i := range e.nodes {
+		if _, ok := sent[e.nodes[i].NodeName]; ok {
+			e.markUnreachable(i, reason)
+		}
+	}
+	monitor.TaskProbeUnreachableNodes.WithLabelValues(e.task.Type).Add(float64(len(sent)))
+	if len(sent) == 0 {
+		util.RecordTaskEvent(e.task, v1.EventTypeNormal, "Probed",
+			"All the %d nodes answered the preflight probe, the slowest in %s", probed, slowest.Round(time.Millisecond))
+		return true
+	}
+	klog.Warningf("%d/%d nodes of task %s did not answer the preflight probe", len(sent), probed, e.task.Name)
+	util.RecordTaskEvent(e.task, v1.EventTypeWarning, "NodesUnreachable",
+		"%d of the %d nodes did not answer the preflight probe within %s, they are failed", len(sent), probed, timeout)
+	return true
+}
+
+// markUnreachable fails the node at index as if it timed out in its initial state, it is counted
+// against the failure tolerance of the task when the task is dispatched. The node is dispatched as
+// usual if it cannot be failed.
+func (e *Executor) markUnreachable(index int, reason string) {
+	nodeName := e.nodes[index].NodeName
+	state, err := e.controller.ReportNodeStatus(e.task.Name, nodeName, fsm.Event{
+		Type:   api.EventTimeOut,
+		Action: api.ActionFailure,
+		Msg:    reason,
+	})
+	if err != nil {
+		klog.Warningf("failed to fail unreachable node %s of task %s: %v", nodeName, e.task.Name, err)
+		return
+	}
+	e.unreachable[nodeName] = true
+	e.nodes[index].State = state
+	e.nodes[index].Event = api.EventTimeOut
+	e.nodes[index].Action = api.ActionFailure
+	e.nodes[index].Reason = reason
+}
+
+// preflightTimeout returns how long the nodes have to answer the probe
+func preflightTimeout(preflight *v1alpha1.PreflightSpec) time.Duration {
+	if preflight.TimeoutSeconds == nil {
+		return defaultPreflightTimeoutSeconds * time.Second
+	}
+	return time.Duration(*preflight.TimeoutSeconds) * time.Second
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// pullWaitInterval is how often a dispatched job checks whether the node pulled its message
+var pullWaitInterval = time.Second
+
+type pendingTask struct {
+	taskID string
+	msg    model.Message
+}
+
+// pendingTasks holds the task messages waiting to be pulled by edge nodes, keyed by node name
+type pendingTasks struct {
+	tasks map[string][]pendingTask
+	sync.Mutex
+}
+
+func (p *pendingTasks) add(nodeName, taskID string, msg model.Message) {
+	p.Lock()
+	defer p.Unlock()
+	p.tasks[nodeName] = append(p.tasks[nodeName], pendingTask{taskID: taskID, msg: msg})
+}
+
+func (p *pendingTasks) take(nodeName string) []pendingTask {
+	p.Lock()
+	defer p.Unlock()
+	tasks := p.tasks[nodeName]
+	delete(p.tasks, nodeName)
+	return tasks
+}
+
+// holds returns whether the message of the task is waiting to be pulled by the node
+func (p *pendingTasks) holds(nodeName, taskID string) bool {
+	p.Lock()
+	defer p.Unlock()
+	for _, task := range p.tasks[nodeName] {
+		if task.taskID == taskID {
+			return true
+		}
+	}
+	return false
+}
+
+// removeNode drops the pending messages of the task for the node
+func (p *pendingTasks) removeNode(nodeName, taskID string) {
+	p.Lock()
+	defer p.Unlock()
+	var remained []pendingTask
+	for _, task := range p.tasks[nodeName] {
+		if task.taskID != taskID {
+			remained = append(remained, task)
+		}
+	}
+	if len(remained) == 0 {
+		delete(p.tasks, nodeName)
+		return
+	}
+	p.tasks[nodeName] = remained
+}
+
+// remove drops the pending messages of the task, it is called when the task is deleted
+func (p *pendingTasks) remove(taskID string) {
+	p.Lock()
+	defer p.Unlock()
+	for nodeName, tasks := range p.tasks {
+		var remained []pendingTask
+		for _, task := range tasks {
+			if task.taskID != taskID {
+				remained = append(remained, task)
+			}
+		}
+		if len(remained) == 0 {
+			delete(p.tasks, nodeName)
+			continue
+		}
+		p.tasks[nodeName] = remained
+	}
+}
+
+// dispatch sends the task message to the edge node directly in push mode,
+// in pull mode the message is held until the edge node pulls it.
+func (em *ExecutorMachine) dispatch(nodeName, taskID string, msg model.Message) {
+	if config.Config.DispatchMode == v1alpha1.TaskDispatchModePull {
+		klog.V(4).Infof("hold message of task %s until node %s pulls it", taskID, nodeName)
+		em.pending.add(nodeName, taskID, msg)
+		return
+	}
+	em.sendDownstream(taskID, msg)
+}
+
+// sendDownstream sends the message of the task to the edge node at the rates the task messages are
+// limited to, it does not block if the messages are held by the limits or the full queue
+func (em *ExecutorMachine) sendDownstream(taskID string, msg model.Message) {
+	em.outbox.add(taskID, msg)
+}
+
+// queueDownstream queues the message to be sent to the edge node, it returns false if the queue is full.
+// The message of a node which is not connected to this replica is relayed to the replica holding its
+// session by the relay worker, it is called with the lock of the outbox held and never waits for the apiserver.
+func (em *ExecutorMachine) queueDownstream(msg model.Message) bool {
+	if nodeName := util.GetNodeName(msg.GetResource()); em.relay != nil && nodeName != "" && !em.relay.connected(nodeName) {
+		return em.relay.send(nodeName, relayDownstream, msg)
+	}
+	return em.enqueueDownstream(msg)
+}
+
+// enqueueDownstream queues the message to be sent to the edge node connected to this replica, it returns
+// false if the queue is full
+func (em *ExecutorMachine) enqueueDownstream(msg model.Message) bool {
+	select {
+	case em.downStreamChan <- msg:
+		monitor.TaskDownstreamQueueLength.Set(float64(len(em.downStreamChan)))
+		return true
+	default:
+		return false
+	}
+}
+
+// waitPulled waits until the node pulls the message of the task in pull mode, so that the timeout
+// of the stage starts once the node fetched the task rather than when it was dispatched. The node
+// is waited for up to timeout, the message is no longer held for a node which did not pull it in time.
+func (e *Executor) waitPulled(index int, lastState api.State, timeout time.Duration) error {
+	if config.Config.DispatchMode != v1alpha1.TaskDispatchModePull {
+		return nil
+	}
+	nodeName := e.nodes[index].NodeName
+	start := time.Now()
+	for executorMachine.pending.holds(nodeName, e.task.Name) {
+		if lastState != e.nodes[index].State || fsm.TaskFinish(e.nodes[index].State) {
+			return nil
+		}
+		if time.Since(start) >= timeout {
+			executorMachine.pending.removeNode(nodeName, e.task.Name)
+			return errNotPulled
+		}
+		select {
+		case <-e.stopChan:
+			return errStopped
+		case <-time.After(pullWaitInterval):
+		}
+	}
+	return nil
+}
+
+// PullTasks takes the pending task messages of the edge node which polls for its tasks over the
+// HTTPS server of cloudhub. The messages are held by the leader, util.ErrNotLeader is returned on the
+// other replicas so that the node polls again.
+func (em *ExecutorMachine) PullTasks(nodeName string) ([]model.Message, error) {
+	if !em.leading.Load() {
+		return nil, util.ErrNotLeader
+	}
+	tasks := em.pending.take(nodeName)
+	msgs := make([]model.Message, 0, len(tasks))
+	for _, task := range tasks {
+		klog.V(4).Infof("node %s pulls message of task %s", nodeName, task.taskID)
+		msgs = append(msgs, task.msg)
+	}
+	return msgs, nil
+}
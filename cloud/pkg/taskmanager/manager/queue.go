@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+)
+
+// queue tracks how long the jobs of a task take, to estimate when the waiting nodes start
+type queue struct {
+	// dispatched are the times the running jobs were dispatched by node name
+	dispatched map[string]time.Time
+	elapsed    time.Duration
+	finished   int
+	// positions are the published queue positions by node name
+	positions map[string]int32
+}
+
+// jobStarted records the dispatch of the job of the node
+func (q *queue) jobStarted(nodeName string) {
+	if q.dispatched == nil {
+		q.dispatched = map[string]time.Time{}
+	}
+	q.dispatched[nodeName] = time.Now()
+}
+
+// jobEnded accounts the time the job of the node took and returns it, if the job was dispatched
+func (q *queue) jobEnded(nodeName string) (time.Duration, bool) {
+	start, ok := q.dispatched[nodeName]
+	if !ok {
+		return 0, false
+	}
+	delete(q.dispatched, nodeName)
+	elapsed := time.Since(start)
+	q.elapsed += elapsed
+	q.finished++
+	return elapsed, true
+}
+
+// estimate returns the estimated start time of the node at the queue position, the waiting
+// nodes start in waves of the given concurrency. It is zero until a job finished.
+func (q *queue) estimate(position int32, concurrency int, now time.Time) time.Time {
+	if q.finished == 0 || concurrency <= 0 {
+		return time.Time{}
+	}
+	average := q.elapsed / time.Duration(q.finished)
+	waves := (int(position) + concurrency - 1) / concurrency
+	return now.Add(time.Duration(waves) * average)
+}
+
+// waitingNodes returns the queue positions of the nodes from index on which wait for a free
+// worker in the current stage, no node waits once the workers are shut down.
+func (e *Executor) waitingNodes(index int) map[string]int32 {
+	positions := map[string]int32{}
+	e.workers.Lock()
+	defer e.workers.Unlock()
+	if e.workers.shuttingDown {
+		return positions
+	}
+	var position int32
+	for i := index; i < len(e.nodes); i++ {
+		node := e.nodes[i]
+		if _, running := e.workers.jobs[node.NodeName]; running || e.controller.StageCompleted(e.task.Name, node.State) {
+			continue
+		}
+		position++
+		positions[node.NodeName] = position
+	}
+	return positions
+}
+
+// publishQueue records the queue position and estimated start time of the waiting nodes in
+// the node status, the status is updated only if the positions changed since last published.
+func (e *Executor) publishQueue(index int) {
+	positions := e.waitingNodes(index)
+	if len(positions) == 0 && len(e.queue.positions) == 0 {
+		return
+	}
+	if len(positions) == len(e.queue.positions) {
+		changed := false
+		for name, position := range positions {
+			if e.queue.positions[name] != position {
+				changed = true
+				break
+			}
+		}
+		if !changed {
+			return
+		}
+	}
+
+	nodeStatus, err := e.controller.GetNodeStatus(e.task.Name)
+	if err != nil {
+		klog.Errorf("failed to get node status of task %s: %v", e.task.Name, err)
+		return
+	}
+	now := time.Now()
+	for i := range nodeStatus {
+		position := positions[nodeStatus[i].NodeName]
+		nodeStatus[i].QueuePosition = position
+		nodeStatus[i].EstimatedStartTime = ""
+		if start := e.queue.estimate(position, e.workers.number, now); position > 0 && !start.IsZero() {
+			nodeStatus[i].EstimatedStartTime = start.UTC().Format(util.ISO8601UTC)
+		}
+	}
+	if err = e.controller.UpdateNodeStatus(e.task.Name, nodeStatus); err != nil {
+		klog.Errorf("failed to publish queue positions of task %s: %v", e.task.Name, err)
+		return
+	}
+	e.queue.positions = positions
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/connection"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// failureWindow is the period in which the failures of a node lower its readiness
+const failureWindow = 24 * time.Hour
+
+// minDiskHeadroom is the allocatable ephemeral storage below which a node is short of disk
+var minDiskHeadroom = resource.MustParse("2Gi")
+
+// failureHistory records when the nodes failed a task
+type failureHistory struct {
+	sync.Mutex
+	failures map[string][]time.Time
+}
+
+var recentFailures = &failureHistory{failures: map[string][]time.Time{}}
+
+func (h *failureHistory) record(nodeName string, at time.Time) {
+	h.Lock()
+	defer h.Unlock()
+	h.failures[nodeName] = append(h.prune(nodeName, at), at)
+}
+
+// count returns the number of times the node failed within failureWindow
+func (h *failureHistory) count(nodeName string) int {
+	h.Lock()
+	defer h.Unlock()
+	return len(h.prune(nodeName, time.Now()))
+}
+
+func (h *failureHistory) prune(nodeName string, now time.Time) []time.Time {
+	failures := h.failures[nodeName]
+	deadline := now.Add(-failureWindow)
+	i := 0
+	for i < len(failures) && failures[i].Before(deadline) {
+		i++
+	}
+	failures = failures[i:]
+	if len(failures) == 0 {
+		delete(h.failures, nodeName)
+	} else {
+		h.failures[nodeName] = failures
+	}
+	return failures
+}
+
+// readinessScore scores how likely the node is to complete a task, from 0 to 100. The score is
+// lowered by a lost or unstable connection, a lack of disk headroom, the recent failures of the
+// node and a low battery if the node reports it.
+func readinessScore(node *v1.Node, quality connection.Quality, failures int) int {
+	score := 100
+	if quality.Known && !quality.Connected {
+		score -= 40
+	}
+	score -= min(len(quality.Disconnects)*10, 30)
+
+	diskPressure := false
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeDiskPressure && condition.Status == v1.ConditionTrue {
+			diskPressure = true
+		}
+	}
+	storage, ok := node.Status.Allocatable[v1.ResourceEphemeralStorage]
+	switch {
+	case diskPressure:
+		score -= 30
+	case ok && storage.Cmp(minDiskHeadroom) < 0:
+		score -= 20
+	}
+
+	score -= min(failures*15, 30)
+
+	if level, err := strconv.Atoi(node.Annotations[util.NodeBatteryLevelAnnotationKey]); err == nil && level >= 0 && level < 100 {
+		score -= (100 - level) * 30 / 100
+	}
+	return max(score, 0)
+}
+
+// sortNodesByReadiness sorts the nodes of a task from the most ready to the least ready, so that
+// the riskiest nodes are operated on last and do not abort the task early.
+func sortNodesByReadiness(nodes []v1alpha1.TaskStatus, nodeList []v1.Node,
+	getQuality func(nodeID string) connection.Quality, getFailures func(nodeName string) int) {
+	scores := make(map[string]int, len(nodeList))
+	for i := range nodeList {
+		node := &nodeList[i]
+		scores[node.Name] = readinessScore(node, getQuality(node.Name), getFailures(node.Name))
+		klog.V(2).Infof("node %s has readiness score %d", node.Name, scores[node.Name])
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return scores[nodes[i].NodeName] > scores[nodes[j].NodeName]
+	})
+}
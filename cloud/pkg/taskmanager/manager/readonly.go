@@ -0,0 +1,43 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+)
+
+// readOnly returns whether TaskManager only observes the tasks, their nodes are selected and their
+// plans recorded but nothing is dispatched to the edge nodes
+func readOnly() bool {
+	return config.Config.ReadOnly
+}
+
+// observe records the plan of the task in place of dispatching it in read-only mode. The task keeps
+// waiting for dispatch until TaskManager is switched to active mode and cloudcore restarted.
+func (e *Executor) observe() {
+	if !e.task.DryRun {
+		// the plan of a dry run is recorded anyway
+		e.recordPlan()
+	}
+	klog.Infof("TaskManager is read-only, task %s is planned on %d nodes and not dispatched", e.task.Name, e.remainingNodes())
+	util.RecordTaskEvent(e.task, v1.EventTypeNormal, "ReadOnly",
+		"TaskManager is read-only, the task is planned on %d nodes and not dispatched", e.remainingNodes())
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/klog/v2"
+
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+)
+
+// receipts records the task states the edge nodes have accepted, keyed by node name
+type receipts struct {
+	states map[string]api.State
+	sync.Mutex
+}
+
+func (r *receipts) accept(nodeName string, state api.State) {
+	r.Lock()
+	defer r.Unlock()
+	r.states[nodeName] = state
+}
+
+func (r *receipts) accepted(nodeName string, state api.State) bool {
+	r.Lock()
+	defer r.Unlock()
+	return r.states[nodeName] == state
+}
+
+// reset forgets the receipt of the node, it is called before the task is dispatched again
+func (r *receipts) reset(nodeName string) {
+	r.Lock()
+	defer r.Unlock()
+	delete(r.states, nodeName)
+}
+
+// Accept records the receipt of the edge node which has validated and queued the task in the state
+func (em *ExecutorMachine) Accept(taskType, taskID, nodeName string, state api.State) {
+	em.Lock()
+	e, ok := em.executors[fmt.Sprintf("%s::%s", taskType, taskID)]
+	em.Unlock()
+	if !ok {
+		klog.V(4).Infof("ignore receipt of node %s for inactive task %s", nodeName, taskID)
+		return
+	}
+	klog.V(4).Infof("node %s accepted task %s in state %s", nodeName, taskID, state)
+	e.receipts.accept(nodeName, state)
+}
@@ -0,0 +1,288 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sinformer "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/common/constants"
+)
+
+const (
+	// relayLabel labels the ConfigMaps relaying the task messages between the replicas of cloudcore,
+	// its value is the direction of the messages
+	relayLabel = "kubeedge.io/task-relay"
+	// relayNodeAnnotation is the annotation of a relay ConfigMap with the node of its messages
+	relayNodeAnnotation = "kubeedge.io/task-relay-node"
+
+	// relayDownstream relays the messages of the leader to the replica the edge node is connected to
+	relayDownstream = "downstream"
+	// relayUpstream relays the messages an edge node sent to another replica to the leader
+	relayUpstream = "upstream"
+
+	// relayResync is how often the relayed messages are tried again, e.g. once their node connects
+	// to a replica or a replica is elected
+	relayResync = 30 * time.Second
+	// relayTTL is how long a relayed message is kept, the executors dispatch the messages which are
+	// not answered within their timeouts again
+	relayTTL = 10 * time.Minute
+	// relayQueueSize is how many messages wait to be put into their relay ConfigMaps
+	relayQueueSize = 100
+)
+
+// relay carries the task messages between the replicas of cloudcore when the executors run on the
+// elected leader only. The messages are kept in a ConfigMap per node and direction until the replica
+// the node is connected to sends them to the node, or the leader handles the ones the node sent to
+// another replica. The ConfigMaps are owned by the nodes and garbage-collected with them.
+type relay struct {
+	kubeClient kubernetes.Interface
+	// connected returns whether the edge node is connected to this replica
+	connected func(nodeName string) bool
+	// leading returns whether this replica runs the executors
+	leading func() bool
+	// downstream queues the message to the edge node connected to this replica, it returns false if
+	// the message is to be sent again
+	downstream func(msg model.Message) bool
+	// upstream handles the message an edge node sent to another replica
+	upstream func(msg model.Message)
+	// queue holds the messages to be put into their relay ConfigMaps by the relay worker, so that
+	// the callers holding locks, e.g. the outbox, do not wait for the apiserver
+	queue chan relayedMessage
+}
+
+// relayedMessage is a message waiting to be put into the relay ConfigMap of its node and direction
+type relayedMessage struct {
+	nodeName  string
+	direction string
+	msg       model.Message
+}
+
+// newRelay returns the relay of the task messages between the replicas of cloudcore
+func newRelay(kubeClient kubernetes.Interface, connected func(string) bool, leading func() bool,
+	downstream func(model.Message) bool, upstream func(model.Message)) *relay {
+	return &relay{
+		kubeClient: kubeClient,
+		connected:  connected,
+		leading:    leading,
+		downstream: downstream,
+		upstream:   upstream,
+		queue:      make(chan relayedMessage, relayQueueSize),
+	}
+}
+
+func relayConfigMapName(nodeName, direction string) string {
+	return nodeName + "-task-" + direction
+}
+
+// run handles the relayed messages until stopCh is closed, it returns once the relayed messages are listed
+func (r *relay) run(stopCh <-chan struct{}) error {
+	factory := k8sinformer.NewSharedInformerFactoryWithOptions(r.kubeClient, relayResync,
+		k8sinformer.WithNamespace(constants.SystemNamespace),
+		k8sinformer.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = relayLabel
+		}))
+	_, err := factory.Core().V1().ConfigMaps().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			r.sync(obj)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			r.sync(obj)
+		},
+	})
+	if err != nil {
+		return err
+	}
+	go r.work(stopCh)
+	factory.Start(stopCh)
+	for informerType, synced := range factory.WaitForCacheSync(stopCh) {
+		if !synced {
+			return fmt.Errorf("failed to sync %v", informerType)
+		}
+	}
+	return nil
+}
+
+// send hands the message to the relay worker without blocking, it returns false if the queue of the
+// worker is full and the message is to be sent again
+func (r *relay) send(nodeName, direction string, msg model.Message) bool {
+	select {
+	case r.queue <- relayedMessage{nodeName: nodeName, direction: direction, msg: msg}:
+		return true
+	default:
+		return false
+	}
+}
+
+// work puts the queued messages into their relay ConfigMaps until stopCh is closed. A message which
+// fails to be put is dropped, the executors dispatch the messages which are not answered again.
+func (r *relay) work(stopCh <-chan struct{}) {
+	for {
+		select {
+		case <-stopCh:
+			return
+		case relayed := <-r.queue:
+			if err := r.put(relayed.nodeName, relayed.direction, relayed.msg); err != nil {
+				klog.Errorf("failed to relay message %s to node %s: %v", relayed.msg.GetID(), relayed.nodeName, err)
+			}
+		}
+	}
+}
+
+// put adds the message to the ConfigMap relaying the messages of the node in the direction
+func (r *relay) put(nodeName, direction string, msg model.Message) error {
+	// the content received from the edge node is raw JSON, it is kept as is rather than base64 encoded
+	if data, ok := msg.Content.([]byte); ok {
+		if json.Valid(data) {
+			msg.Content = json.RawMessage(data)
+		} else {
+			msg.Content = string(data)
+		}
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	configMaps := r.kubeClient.CoreV1().ConfigMaps(constants.SystemNamespace)
+	name := relayConfigMapName(nodeName, direction)
+	retriable := func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err) || apierrors.IsNotFound(err)
+	}
+	return retry.OnError(retry.DefaultRetry, retriable, func() error {
+		cm, err := configMaps.Get(context.TODO(), name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			cm = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        name,
+					Namespace:   constants.SystemNamespace,
+					Labels:      map[string]string{relayLabel: direction},
+					Annotations: map[string]string{relayNodeAnnotation: nodeName},
+				},
+				Data: map[string]string{msg.GetID(): string(data)},
+			}
+			if node, err := r.kubeClient.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{}); err == nil {
+				cm.OwnerReferences = []metav1.OwnerReference{{
+					APIVersion: "v1",
+					Kind:       "Node",
+					Name:       node.Name,
+					UID:        node.UID,
+				}}
+			}
+			_, err = configMaps.Create(context.TODO(), cm, metav1.CreateOptions{})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[msg.GetID()] = string(data)
+		_, err = configMaps.Update(context.TODO(), cm, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// sync handles the messages of the relay ConfigMap which are for this replica in the order they were
+// created, the handled and the expired messages are removed from it
+func (r *relay) sync(obj interface{}) {
+	cm, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		return
+	}
+	nodeName, direction := cm.Annotations[relayNodeAnnotation], cm.Labels[relayLabel]
+	switch {
+	case len(cm.Data) == 0:
+		return
+	case direction == relayDownstream && r.connected(nodeName):
+	case direction == relayUpstream && r.leading():
+	default:
+		return
+	}
+
+	messages := make([]model.Message, 0, len(cm.Data))
+	var handled []string
+	for key, data := range cm.Data {
+		var msg model.Message
+		if err := json.Unmarshal([]byte(data), &msg); err != nil {
+			klog.Warningf("drop malformed message %s relayed for node %s: %v", key, nodeName, err)
+			handled = append(handled, key)
+			continue
+		}
+		messages = append(messages, msg)
+	}
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].GetTimestamp() < messages[j].GetTimestamp()
+	})
+	for _, msg := range messages {
+		if time.Since(time.UnixMilli(msg.GetTimestamp())) > relayTTL {
+			klog.Warningf("drop expired message %s relayed for node %s", msg.GetID(), nodeName)
+		} else if direction == relayUpstream {
+			r.upstream(msg)
+		} else if !r.downstream(msg) {
+			// the queue is full, the rest is sent again on the next resync
+			break
+		}
+		handled = append(handled, msg.GetID())
+	}
+	if err := r.remove(cm.Name, handled); err != nil {
+		klog.Errorf("failed to remove the handled messages relayed for node %s: %v", nodeName, err)
+	}
+}
+
+// remove removes the messages from the relay ConfigMap
+func (r *relay) remove(name string, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	configMaps := r.kubeClient.CoreV1().ConfigMaps(constants.SystemNamespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := configMaps.Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			delete(cm.Data, key)
+		}
+		_, err = configMaps.Update(context.TODO(), cm, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// handleRelayed handles the message an edge node sent to another replica
+func (em *ExecutorMachine) handleRelayed(msg model.Message) {
+	taskID, nodeName, err := util.ParseTaskResource(msg.GetResource())
+	if err != nil {
+		klog.Errorf("drop relayed task message %s: %v", msg.GetID(), err)
+		return
+	}
+	handleExecutorMessage(em, taskID, nodeName, msg)
+}
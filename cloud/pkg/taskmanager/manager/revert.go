@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+var revertEvent = fsm.Event{
+	Type:   api.EventRevert,
+	Action: api.ActionSuccess,
+	Msg:    "the task exceeded its failure tolerance, roll back the node",
+}
+
+// revertRequired returns whether the nodes upgraded by the task are to be rolled back
+// as it exceeded its failure tolerance
+func (e *Executor) revertRequired() bool {
+	if e.task.Type != util.TaskUpgrade || e.task.RollbackPolicy != v1alpha1.RollbackPolicyAutomatic || e.reverting {
+		return false
+	}
+	for _, node := range e.nodes {
+		if node.State == api.TaskSuccessful {
+			return true
+		}
+	}
+	return false
+}
+
+// revert moves the task and the nodes it upgraded to Reverting, the nodes are dispatched again to roll
+// them back. The other nodes are not dispatched anymore.
+func (e *Executor) revert() {
+	e.reverting = true
+	e.workers.shutDown(false)
+	klog.Infof("task %s rolls back the upgraded nodes", e.task.Name)
+	util.RecordTaskEvent(e.task, v1.EventTypeWarning, "Reverting", "%s Roll back the upgraded nodes", e.revertReason)
+	if _, err := e.controller.ReportTaskStatus(e.task.Name, fsm.Event{
+		Type:   api.EventRevert,
+		Action: api.ActionSuccess,
+		Msg:    e.revertReason,
+	}); err != nil {
+		klog.Warningf("failed to report reverting of task %s: %v", e.task.Name, err)
+	}
+	for i, node := range e.nodes {
+		if node.State != api.TaskSuccessful {
+			continue
+		}
+		if _, err := e.controller.ReportNodeStatus(e.task.Name, node.NodeName, revertEvent); err != nil {
+			klog.Warningf("failed to revert node %s of task %s: %v", node.NodeName, e.task.Name, err)
+			continue
+		}
+		e.nodes[i].State = api.RevertingState
+	}
+}
+
+// finishRevert fails the task once the nodes it upgraded are rolled back
+func (e *Executor) finishRevert() {
+	var reverted, failed int
+	for _, node := range e.nodes {
+		switch node.State {
+		case api.TaskReverted:
+			reverted++
+		case api.RevertingState, api.TaskFailed:
+			failed++
+		}
+	}
+	reason := e.revertReason
+	if reason == "" {
+		reason = "the task exceeded its failure tolerance."
+	}
+	msg := fmt.Sprintf("%s %d upgraded nodes are rolled back", reason, reverted)
+	if _, err := e.controller.ReportTaskStatus(e.task.Name, fsm.Event{
+		Type:   api.EventRevert,
+		Action: api.ActionFailure,
+		Msg:    msg,
+	}); err != nil {
+		klog.Errorf("failed to report the failure of task %s: %v", e.task.Name, err)
+	}
+	util.RecordTaskEvent(e.task, v1.EventTypeWarning, "Reverted", "%d upgraded nodes are rolled back, %d failed nodes", reverted, failed)
+	DeleteExecutor(e.task)
+	klog.Infof("task %s failed, %d upgraded nodes are rolled back", e.task.Name, reverted)
+}
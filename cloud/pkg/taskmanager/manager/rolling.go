@@ -0,0 +1,48 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+)
+
+// resizeWorkers sizes the batch of the task from its maxUnavailable and the nodes left in the task,
+// the nodes removed from the cluster or skipped are not counted. The batch is sized by the
+// concurrency of the task if it has no maxUnavailable.
+func (e *Executor) resizeWorkers() {
+	var nodes int
+	for _, node := range e.nodes {
+		if node.State != api.TaskNodeRemoved && node.State != api.TaskSkipped {
+			nodes++
+		}
+	}
+	size, err := util.BatchSize(e.task.Concurrency, e.task.MaxUnavailable, nodes)
+	if err != nil {
+		klog.Warningf("keep the batch size of task %s: %v", e.task.Name, err)
+		return
+	}
+	e.workers.Lock()
+	defer e.workers.Unlock()
+	if size == e.workers.number {
+		return
+	}
+	klog.Infof("task %s runs %d of its %d nodes at the same time", e.task.Name, size, nodes)
+	e.workers.number = size
+}
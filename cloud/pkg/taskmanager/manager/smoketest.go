@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+const (
+	// smokeTestLabel labels the canary pods run by the post checks
+	smokeTestLabel = "operations.kubeedge.io/smoke-test"
+	// smokeTestTaskAnnotation records the task whose post check runs the canary pod
+	smokeTestTaskAnnotation = "operations.kubeedge.io/task"
+	// canaryContainerName is the name of the container of the canary pods
+	canaryContainerName = "canary"
+)
+
+// smokeTest is the smoke test of the post check of a node, the canary pod is created once the node is
+// otherwise verified and deleted once the post check finishes
+type smokeTest struct {
+	task     util.TaskMessage
+	nodeName string
+	spec     v1alpha1.SmokeTestSpec
+	// canary is the name of the canary pod, it is empty until the pod is created
+	canary string
+}
+
+func (s *smokeTest) namespace() string {
+	if s.spec.Namespace == "" {
+		return metav1.NamespaceDefault
+	}
+	return s.spec.Namespace
+}
+
+// check returns why the canary has not passed yet, it creates the canary pod if it is not created
+func (s *smokeTest) check() string {
+	if s.canary == "" {
+		pod, err := client.GetKubeClient().CoreV1().Pods(s.namespace()).Create(context.TODO(), canaryPod(s.task, s.nodeName, s.spec), metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Sprintf("failed to create the canary pod: %v", err)
+		}
+		s.canary = pod.Name
+		klog.Infof("run canary pod %s/%s on node %s for task %s", pod.Namespace, pod.Name, s.nodeName, s.task.Name)
+		return fmt.Sprintf("canary pod %s/%s is created", pod.Namespace, pod.Name)
+	}
+	pod, err := client.GetKubeClient().CoreV1().Pods(s.namespace()).Get(context.TODO(), s.canary, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Sprintf("failed to get canary pod %s/%s: %v", s.namespace(), s.canary, err)
+	}
+	return canaryReason(pod, s.spec.Probe)
+}
+
+// cleanup deletes the canary pod, if it is created
+func (s *smokeTest) cleanup() {
+	if s.canary == "" {
+		return
+	}
+	err := client.GetKubeClient().CoreV1().Pods(s.namespace()).Delete(context.TODO(), s.canary, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		klog.Warningf("failed to delete canary pod %s/%s of task %s: %v", s.namespace(), s.canary, s.task.Name, err)
+	}
+}
+
+// canaryPod returns the canary pod bound to the node. It tolerates every taint, so that the taints of
+// the node under operation do not keep it from running, and it is not restarted, so that a canary which
+// exits fails the smoke test. It carries the labels of the task and is owned by it, so that it is
+// garbage collected together with the task if the post check does not delete it.
+func canaryPod(task util.TaskMessage, nodeName string, spec v1alpha1.SmokeTestSpec) *v1.Pod {
+	container := v1.Container{
+		Name:            canaryContainerName,
+		Image:           spec.Image,
+		Command:         spec.Command,
+		ImagePullPolicy: v1.PullIfNotPresent,
+	}
+	if probe := spec.Probe; probe != nil {
+		container.ReadinessProbe = &v1.Probe{
+			ProbeHandler: v1.ProbeHandler{
+				Exec:      probe.Exec,
+				HTTPGet:   probe.HTTPGet,
+				TCPSocket: probe.TCPSocket,
+			},
+			InitialDelaySeconds: probe.InitialDelaySeconds,
+			TimeoutSeconds:      probe.TimeoutSeconds,
+			PeriodSeconds:       probe.PeriodSeconds,
+			FailureThreshold:    probe.FailureThreshold,
+		}
+	}
+	meta := util.TaskObjectMeta("", "", task)
+	meta.GenerateName = "smoke-test-"
+	meta.Labels[smokeTestLabel] = "true"
+	meta.Annotations = map[string]string{smokeTestTaskAnnotation: task.Name}
+	return &v1.Pod{
+		ObjectMeta: meta,
+		Spec: v1.PodSpec{
+			NodeName:      nodeName,
+			RestartPolicy: v1.RestartPolicyNever,
+			Tolerations:   []v1.Toleration{{Operator: v1.TolerationOpExists}},
+			Containers:    []v1.Container{container},
+		},
+	}
+}
+
+// canaryReason returns why the canary pod has not passed yet, it is empty once the pod is Running and
+// its container is ready, i.e. it passes the probe if any
+func canaryReason(pod *v1.Pod, probe *v1alpha1.VerificationProbe) string {
+	if pod.Status.Phase != v1.PodRunning {
+		return fmt.Sprintf("canary pod %s/%s is %s", pod.Namespace, pod.Name, pod.Status.Phase)
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == canaryContainerName && status.Ready {
+			return ""
+		}
+	}
+	if probe != nil {
+		return fmt.Sprintf("canary pod %s/%s does not pass probe %s", pod.Namespace, pod.Name, probe.Name)
+	}
+	return fmt.Sprintf("canary pod %s/%s is not ready", pod.Namespace, pod.Name)
+}
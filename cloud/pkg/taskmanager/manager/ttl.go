@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// ttlCheckInterval is how often the finished tasks are checked for expiry
+const ttlCheckInterval = time.Minute
+
+// collectFinishedTasks deletes the task objects whose ttlSecondsAfterFinished expired until cloudcore stops
+func (em *ExecutorMachine) collectFinishedTasks() {
+	ticker := time.NewTicker(ttlCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-beehiveContext.Done():
+			return
+		case <-ticker.C:
+			if !em.leading.Load() {
+				continue
+			}
+			deleteExpiredTasks(client.GetCRDClient(), time.Now())
+		}
+	}
+}
+
+// deleteExpiredTasks deletes the task objects which finished longer than their ttlSecondsAfterFinished
+// ago, the task objects of all the registered task types are checked, including the types registered
+// out of tree. The objects are deleted only if they did not change since they were listed, e.g. they
+// are not rerun.
+func deleteExpiredTasks(crdClient crdClientset.Interface, now time.Time) {
+	for _, taskType := range util.TaskObjectTypes() {
+		objects, ok := util.GetTaskObjects(taskType)
+		if !ok || objects.TTLSecondsAfterFinished == nil {
+			continue
+		}
+		taskClient := objects.Client(crdClient)
+		list, err := taskClient.List(context.TODO(), metav1.ListOptions{ResourceVersion: "0"})
+		if err != nil {
+			klog.Errorf("failed to list %s: %v", objects.Resource, err)
+			continue
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			klog.Errorf("failed to extract the list of %s: %v", objects.Resource, err)
+			continue
+		}
+		for _, item := range items {
+			object, ok := item.(util.TaskObject)
+			if !ok {
+				continue
+			}
+			status := objects.Status(object)
+			if taskExpired(objects.TTLSecondsAfterFinished(object), status.State, status.Time, object.GetCreationTimestamp(), now) {
+				deleteExpiredTask(objects.Resource.String(), taskClient, object)
+			}
+		}
+	}
+}
+
+func deleteExpiredTask(resource string, taskClient util.TaskObjectClient, object util.TaskObject) {
+	err := taskClient.Delete(context.TODO(), object.GetName(), object.GetResourceVersion())
+	switch {
+	case err == nil:
+		klog.Infof("delete %s %s, its ttlSecondsAfterFinished expired", resource, object.GetName())
+	case apierrors.IsNotFound(err), apierrors.IsConflict(err):
+		// the task is already deleted or changed, it is checked again in the next round
+	default:
+		klog.Errorf("failed to delete expired %s %s: %v", resource, object.GetName(), err)
+	}
+}
+
+// taskExpired returns whether the task in the state finished longer than ttl ago, the task finished
+// at the time of its last state transition, or at its creation if the time is not recorded
+func taskExpired(ttl *int32, state api.State, finishedAt string, created metav1.Time, now time.Time) bool {
+	if ttl == nil || !fsm.TaskFinish(state) {
+		return false
+	}
+	finished, err := time.Parse(util.ISO8601UTC, finishedAt)
+	if err != nil {
+		finished = created.Time
+	}
+	return !now.Before(finished.Add(time.Duration(*ttl) * time.Second))
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+)
+
+// TuneExecutor passes the concurrency and the failure tolerance updated while the task runs to its executor,
+// the defaults of the task type apply if the task does not set them. A tuning the executor did not take
+// yet is replaced, the executor applies the latest one.
+func TuneExecutor(msg util.TaskMessage) {
+	msg = taskPipeline(msg.Type).Apply(msg)
+	executorMachine.Lock()
+	e, ok := executorMachine.executors[fmt.Sprintf("%s::%s", msg.Type, msg.Name)]
+	executorMachine.Unlock()
+	if !ok {
+		klog.Warningf("task %s to tune is not running", msg.Name)
+		return
+	}
+	for {
+		select {
+		case e.tuneChan <- &msg:
+			return
+		default:
+		}
+		select {
+		case <-e.tuneChan:
+			klog.V(4).Infof("the pending tuning of task %s is replaced", msg.Name)
+		default:
+		}
+	}
+}
+
+// tune applies the concurrency and the failure tolerance updated while the task runs. A lowered
+// concurrency lets the running nodes finish, fewer nodes are dispatched from then on. A task which
+// already exceeded its failure tolerance is not resumed by raising it. It returns false if the task
+// fails with the lowered failure tolerance.
+func (e *Executor) tune(msg *util.TaskMessage) bool {
+	e.task.Concurrency = msg.Concurrency
+	e.task.MaxUnavailable = msg.MaxUnavailable
+	e.task.FailureTolerate = msg.FailureTolerate
+	e.resizeWorkers()
+	e.workers.Lock()
+	concurrency := e.workers.number
+	e.workers.Unlock()
+	e.updateFailureBudget()
+	klog.Infof("task %s is tuned, it runs %d nodes at the same time and fails at %d failed nodes",
+		e.task.Name, concurrency, util.FailureThreshold(e.maxFailedNodes))
+	util.RecordTaskEvent(e.task, v1.EventTypeNormal, "Tuned",
+		"The task runs %d nodes at the same time and fails at %d failed nodes", concurrency, util.FailureThreshold(e.maxFailedNodes))
+
+	// the nodes which failed so far may exceed the lowered failure tolerance
+	for _, node := range e.nodes {
+		if !e.failedNodes[node.NodeName] {
+			continue
+		}
+		if err := e.dealFailedNode(node); err != nil {
+			klog.Warning(err.Error())
+			return false
+		}
+		break
+	}
+	return true
+}
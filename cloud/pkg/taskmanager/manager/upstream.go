@@ -18,6 +18,7 @@ package manager
 
 import (
 	"encoding/json"
+	"time"
 
 	k8sinformer "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
@@ -32,6 +33,7 @@ import (
 	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
 	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
 	"github.com/kubeedge/kubeedge/common/types"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
 	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
 	"github.com/kubeedge/kubeedge/pkg/util/fsm"
 )
@@ -94,9 +96,17 @@ func (uc *UpstreamController) updateTaskStatus() {
 		case msg := <-uc.taskStatusChan:
 			klog.V(4).Infof("Message: %s, operation is: %s, and resource is: %s", msg.GetID(), msg.GetOperation(), msg.GetResource())
 
-			// get nodeID and upgradeID from Upgrade msg:
-			nodeID := util.GetNodeName(msg.GetResource())
-			taskID := util.GetTaskID(msg.GetResource())
+			// the task is identified by the type in the operation and the ID in the resource, a node
+			// may report the status of several tasks of different types with the same ID at once
+			taskID, nodeID, err := util.ParseTaskResource(msg.GetResource())
+			if err != nil {
+				klog.Errorf("drop task message %s: %v", msg.GetID(), err)
+				continue
+			}
+
+			if handleExecutorMessage(GetExecutorMachine(), taskID, nodeID, msg) {
+				continue
+			}
 
 			data, err := msg.GetContentData()
 			if err != nil {
@@ -116,11 +126,20 @@ func (uc *UpstreamController) updateTaskStatus() {
 				klog.Errorf("Failed to unmarshal node upgrade response: %v", err)
 				continue
 			}
+			if resp.NodeName != "" && resp.NodeName != nodeID {
+				klog.Errorf("drop %s task %s status of node %s reported for node %s", msg.GetOperation(), taskID, resp.NodeName, nodeID)
+				continue
+			}
 			event := fsm.Event{
 				Type:            resp.Event,
 				Action:          resp.Action,
 				Msg:             resp.Reason,
 				ExternalMessage: resp.ExternalMessage,
+				Environment:     resp.Environment,
+				BytesDownloaded: resp.BytesDownloaded,
+				Metadata:        resp.Metadata,
+				CommandDigest:   resp.CommandDigest,
+				Elapsed:         time.Duration(resp.ElapsedSeconds) * time.Second,
 			}
 
 			_, err = c.ReportNodeStatus(taskID, nodeID, event)
@@ -132,6 +151,52 @@ func (uc *UpstreamController) updateTaskStatus() {
 	}
 }
 
+// handleExecutorMessage handles the messages of the edge nodes which are answered by the executors rather
+// than reported to the controllers, it returns false for the other messages. The messages received by a
+// replica which is not the leader are relayed to the leader, the executors run on it only.
+func handleExecutorMessage(em *ExecutorMachine, taskID, nodeID string, msg model.Message) bool {
+	switch msg.GetOperation() {
+	case util.TaskInventory, util.TaskAccept, util.TaskProbe:
+	default:
+		return false
+	}
+	if em.relay != nil && !em.leading.Load() {
+		if err := em.relay.put(nodeID, relayUpstream, msg); err != nil {
+			klog.Errorf("failed to relay %s message of node %s to the leader: %v", msg.GetOperation(), nodeID, err)
+		}
+		return true
+	}
+	data, err := msg.GetContentData()
+	if err != nil {
+		klog.Errorf("failed to get %s content data of node %s: %v", msg.GetOperation(), nodeID, err)
+		return true
+	}
+	switch msg.GetOperation() {
+	case util.TaskInventory:
+		inventory := types.NodeTaskInventory{}
+		if err = json.Unmarshal(data, &inventory); err != nil {
+			klog.Errorf("Failed to unmarshal node task inventory: %v", err)
+			return true
+		}
+		em.PurgeStaleTasks(nodeID, inventory)
+	case util.TaskAccept:
+		receipt := types.NodeTaskReceipt{}
+		if err = json.Unmarshal(data, &receipt); err != nil {
+			klog.Errorf("Failed to unmarshal node task receipt: %v", err)
+			return true
+		}
+		em.Accept(receipt.Type, taskID, nodeID, api.State(receipt.State))
+	case util.TaskProbe:
+		probe := types.NodeTaskProbe{}
+		if err = json.Unmarshal(data, &probe); err != nil {
+			klog.Errorf("Failed to unmarshal node task probe: %v", err)
+			return true
+		}
+		em.ProbeAnswered(probe.Type, taskID, nodeID)
+	}
+	return true
+}
+
 // NewUpstreamController create UpstreamController from config
 func NewUpstreamController(dc *DownstreamController) (*UpstreamController, error) {
 	uc := &UpstreamController{
@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// watchdogInterval is how often the executors are checked for stalls
+const watchdogInterval = 30 * time.Second
+
+// monotonicEpoch is the origin of the progress of the executors, the progress is measured from it on
+// the monotonic clock so that a step of the wall clock does not make the executors look stalled
+var monotonicEpoch = time.Now()
+
+// monotonicNanos returns the time t in nanoseconds since monotonicEpoch
+func monotonicNanos(t time.Time) int64 {
+	return int64(t.Sub(monotonicEpoch))
+}
+
+// watchExecutors restarts the executors which are stalled until cloudcore stops
+func (em *ExecutorMachine) watchExecutors() {
+	if config.Config.StalledExecutorSeconds <= 0 {
+		return
+	}
+	ticker := time.NewTicker(watchdogInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-beehiveContext.Done():
+			return
+		case <-ticker.C:
+			if em.leading.Load() {
+				em.restartStalled(time.Now())
+			}
+		}
+	}
+}
+
+// restartStalled restarts the executors which panicked or had nodes in flight without making
+// progress for longer than their stall threshold
+func (em *ExecutorMachine) restartStalled(now time.Time) {
+	em.Lock()
+	executors := make(map[string]*Executor, len(em.executors))
+	for key, e := range em.executors {
+		executors[key] = e
+	}
+	em.Unlock()
+	for key, e := range executors {
+		if e.crashed.Load() {
+			em.restartExecutor(key, e, "the executor panicked")
+			continue
+		}
+		idle := time.Duration(monotonicNanos(now) - e.progress.Load())
+		if e.busy.Load() && idle > e.stallThreshold() {
+			em.restartExecutor(key, e, fmt.Sprintf("the executor made no progress for %s", idle.Round(time.Second)))
+		}
+	}
+}
+
+// restartExecutor stops the stalled executor and starts a new one for its task, it is rebuilt
+// from the recorded status of the task and resumes the nodes in progress first
+func (em *ExecutorMachine) restartExecutor(key string, e *Executor, reason string) {
+	em.Lock()
+	if em.executors[key] != e {
+		// the executor is finished or already restarted
+		em.Unlock()
+		return
+	}
+	delete(em.executors, key)
+	monitor.TaskActiveExecutors.Set(float64(len(em.executors)))
+	em.Unlock()
+	e.stop()
+	inFlight.forget(e)
+
+	klog.Warningf("executor of task %s is stalled, %s, restart it", e.task.Name, reason)
+	util.RecordTaskEvent(e.task, v1.EventTypeWarning, "StalledExecutor",
+		"The executor is stalled, %s, restart it from the recorded status", reason)
+	task := e.task
+	task.Status = v1alpha1.TaskStatus{}
+	go func() {
+		select {
+		case em.messageChan <- task:
+		case <-beehiveContext.Done():
+		}
+	}()
+}
+
+// stallThreshold is how long the executor may have nodes in flight without making progress, it
+// covers the longest stage of the task dispatched the configured number of times
+func (e *Executor) stallThreshold() time.Duration {
+	threshold := time.Duration(config.Config.StalledExecutorSeconds) * time.Second
+	longest := e.stageTimeout("")
+	for state := range e.task.StageTimeouts {
+		if timeout := e.stageTimeout(state); timeout > longest {
+			longest = timeout
+		}
+	}
+	if waits := longest*time.Duration(config.Config.DispatchRetries+1) + watchdogInterval; waits > threshold {
+		return waits
+	}
+	return threshold
+}
+
+// markProgress records that the executor dispatched a node or handled the status of one,
+// the metrics of the nodes of the task are updated
+func (e *Executor) markProgress() {
+	e.workers.Lock()
+	inProgress := len(e.workers.jobs)
+	e.workers.Unlock()
+	e.busy.Store(inProgress != 0)
+	e.progress.Store(monotonicNanos(time.Now()))
+
+	var succeeded, failed int
+	for _, node := range e.nodes {
+		switch node.State {
+		case api.TaskSuccessful:
+			succeeded++
+		case api.TaskFailed:
+			failed++
+		}
+	}
+	monitor.TaskNodes.WithLabelValues(e.task.Type, e.task.Name, "succeeded").Set(float64(succeeded))
+	monitor.TaskNodes.WithLabelValues(e.task.Type, e.task.Name, "failed").Set(float64(failed))
+	monitor.TaskNodes.WithLabelValues(e.task.Type, e.task.Name, "in_progress").Set(float64(inProgress))
+}
+
+// recoverPanic keeps a panic of the executor from crashing cloudcore, the executor is marked
+// crashed and restarted by the watchdog
+func (e *Executor) recoverPanic() {
+	if r := recover(); r != nil {
+		klog.Errorf("executor of task %s panicked: %v\n%s", e.task.Name, r, debug.Stack())
+		e.crashed.Store(true)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderestartcontroller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/manager"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+)
+
+// NodeRestartController is the controller of NodeRestartJobs
+type NodeRestartController = controller.JobController[*v1alpha1.NodeRestartJob, *v1alpha1.NodeRestartJobList]
+
+// nodeRestartJobType is the type of NodeRestartJobs handled by the controller
+var nodeRestartJobType = controller.JobType[*v1alpha1.NodeRestartJob, *v1alpha1.NodeRestartJobList]{
+	Name:          util.TaskRestart,
+	Kind:          "NodeRestartJob",
+	Rule:          api.RestartRule,
+	StageSequence: api.RestartStageSequence,
+	Client: func(crdClient crdClientset.Interface) util.TypedTaskClient[*v1alpha1.NodeRestartJob, *v1alpha1.NodeRestartJobList] {
+		return crdClient.OperationsV1alpha1().NodeRestartJobs()
+	},
+	Request: request,
+	PostCheck: func(job *v1alpha1.NodeRestartJob) *v1alpha1.PostCheckSpec {
+		return job.Spec.PostCheck
+	},
+}
+
+func init() {
+	// the nodes restart one at a time and are verified from cloud, they succeed once Ready by default
+	controller.RegisterPipeline(util.TaskRestart, controller.Pipeline{
+		Stages:        []api.State{api.RestartingState},
+		VerifiesNodes: true,
+		Concurrency:   1,
+		PostCheck:     &v1alpha1.PostCheckSpec{},
+	})
+	util.RegisterTaskObjects(util.TaskRestart, util.NewTypedTaskObjects(v1alpha1.Resource("noderestartjobs"),
+		nodeRestartJobType.Client,
+		func(meta metav1.ObjectMeta, spec v1alpha1.NodeRestartJobSpec) *v1alpha1.NodeRestartJob {
+			return &v1alpha1.NodeRestartJob{ObjectMeta: meta, Spec: spec}
+		},
+		func(job *v1alpha1.NodeRestartJob) util.TaskObjectStatus {
+			return util.JobObjectStatus(job.Status.CommonJobStatus, job.Status)
+		},
+		func(job *v1alpha1.NodeRestartJob) *int32 {
+			return job.Spec.TTLSecondsAfterFinished
+		}))
+}
+
+func NewNodeRestartController(messageChan chan util.TaskMessage) (*NodeRestartController, error) {
+	cache, err := manager.NewTaskCache(
+		informers.GetInformersManager().GetKubeEdgeInformerFactory().Operations().V1alpha1().NodeRestartJobs().Informer())
+	if err != nil {
+		klog.Warningf("Create node restart controller failed with error: %s", err)
+		return nil, err
+	}
+	return NewController(controller.NewBaseController(util.TaskRestart, messageChan, cache,
+		informers.GetInformersManager().GetKubeInformerFactory(), client.GetKubeClient(), client.GetCRDClient())), nil
+}
+
+// NewController returns the controller of NodeRestartJobs with the clients and the task cache of the base
+func NewController(base *controller.BaseController) *NodeRestartController {
+	return controller.NewJobController(nodeRestartJobType, base)
+}
+
+// request returns the message requesting the edge nodes to restart edgecore or reboot, each node is
+// verified from cloud once edgecore is back, with the post check of the job or by waiting for the
+// node to be Ready
+func request(job *v1alpha1.NodeRestartJob) interface{} {
+	return commontypes.NodeRestartJobRequest{Reboot: job.Spec.Reboot}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeupgradecontroller
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// setUpgradeConditions updates the conditions of the status of the NodeUpgradeJob from its state
+// and its spec, the transition time of a condition is kept unless its status changes.
+func setUpgradeConditions(upgrade *v1alpha1.NodeUpgradeJob, status *v1alpha1.NodeUpgradeJobStatus) {
+	state := status.State
+	finished := fsm.TaskFinish(state)
+	reason := stateReason(state)
+	set := func(conditionType v1alpha1.NodeUpgradeJobConditionType, ok bool, reason, message string) {
+		conditionStatus := metav1.ConditionFalse
+		if ok {
+			conditionStatus = metav1.ConditionTrue
+		}
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               string(conditionType),
+			Status:             conditionStatus,
+			ObservedGeneration: upgrade.Generation,
+			Reason:             reason,
+			Message:            message,
+		})
+	}
+
+	paused := upgrade.Spec.Paused && !finished
+	switch {
+	case paused:
+		set(v1alpha1.NodeUpgradeJobProgressing, false, "Paused", "no new node is dispatched")
+	case !finished && status.MaintenanceWindow != nil:
+		set(v1alpha1.NodeUpgradeJobProgressing, false, "WaitingForMaintenanceWindow", fmt.Sprintf("paused until the next window at %s, %d nodes remaining",
+			status.MaintenanceWindow.PausedUntil, status.MaintenanceWindow.RemainingNodes))
+	case finished || state == api.WaitingApprovalState:
+		set(v1alpha1.NodeUpgradeJobProgressing, false, reason, status.Reason)
+	default:
+		set(v1alpha1.NodeUpgradeJobProgressing, true, reason, status.Reason)
+	}
+	set(v1alpha1.NodeUpgradeJobFailed, state == api.TaskFailed || state == api.TaskDeadlineExceeded, reason, failedMessage(status))
+	set(v1alpha1.NodeUpgradeJobComplete, state == api.TaskSuccessful || state == api.TaskPartiallySucceeded, reason, "")
+	if paused {
+		set(v1alpha1.NodeUpgradeJobPaused, true, "Paused", "")
+	} else {
+		set(v1alpha1.NodeUpgradeJobPaused, false, "NotPaused", "")
+	}
+
+	switch {
+	case upgrade.Spec.Canary == nil:
+		meta.RemoveStatusCondition(&status.Conditions, string(v1alpha1.NodeUpgradeJobApproved))
+	case upgrade.Spec.Canary.Approve:
+		set(v1alpha1.NodeUpgradeJobApproved, true, "Approved", "")
+	case state == api.WaitingApprovalState:
+		set(v1alpha1.NodeUpgradeJobApproved, false, "WaitingApproval", "the canary nodes are upgraded")
+	default:
+		set(v1alpha1.NodeUpgradeJobApproved, false, "NotApproved", "")
+	}
+}
+
+// stateReason returns the reason of the conditions of the job in the state
+func stateReason(state api.State) string {
+	if state == "" {
+		return "Pending"
+	}
+	return string(state)
+}
+
+// failedMessage returns why the job failed, it is empty if the job did not fail
+func failedMessage(status *v1alpha1.NodeUpgradeJobStatus) string {
+	if status.State != api.TaskFailed && status.State != api.TaskDeadlineExceeded {
+		return ""
+	}
+	return status.Reason
+}
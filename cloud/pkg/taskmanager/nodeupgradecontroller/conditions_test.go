@@ -0,0 +1,226 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeupgradecontroller
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// conditionSummary is the status and the reason of a condition
+type conditionSummary struct {
+	Status metav1.ConditionStatus
+	Reason string
+}
+
+func summarizeConditions(conditions []metav1.Condition) map[v1alpha1.NodeUpgradeJobConditionType]conditionSummary {
+	result := map[v1alpha1.NodeUpgradeJobConditionType]conditionSummary{}
+	for _, condition := range conditions {
+		result[v1alpha1.NodeUpgradeJobConditionType(condition.Type)] = conditionSummary{Status: condition.Status, Reason: condition.Reason}
+	}
+	return result
+}
+
+func TestSetUpgradeConditions(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     v1alpha1.NodeUpgradeJobSpec
+		status   v1alpha1.NodeUpgradeJobStatus
+		expected map[v1alpha1.NodeUpgradeJobConditionType]conditionSummary
+	}{
+		{
+			name: "pending",
+			expected: map[v1alpha1.NodeUpgradeJobConditionType]conditionSummary{
+				v1alpha1.NodeUpgradeJobProgressing: {metav1.ConditionTrue, "Pending"},
+				v1alpha1.NodeUpgradeJobFailed:      {metav1.ConditionFalse, "Pending"},
+				v1alpha1.NodeUpgradeJobComplete:    {metav1.ConditionFalse, "Pending"},
+				v1alpha1.NodeUpgradeJobPaused:      {metav1.ConditionFalse, "NotPaused"},
+			},
+		},
+		{
+			name:   "upgrading",
+			status: v1alpha1.NodeUpgradeJobStatus{State: api.UpgradingState},
+			expected: map[v1alpha1.NodeUpgradeJobConditionType]conditionSummary{
+				v1alpha1.NodeUpgradeJobProgressing: {metav1.ConditionTrue, "Upgrading"},
+				v1alpha1.NodeUpgradeJobFailed:      {metav1.ConditionFalse, "Upgrading"},
+				v1alpha1.NodeUpgradeJobComplete:    {metav1.ConditionFalse, "Upgrading"},
+				v1alpha1.NodeUpgradeJobPaused:      {metav1.ConditionFalse, "NotPaused"},
+			},
+		},
+		{
+			name:   "paused",
+			spec:   v1alpha1.NodeUpgradeJobSpec{Paused: true},
+			status: v1alpha1.NodeUpgradeJobStatus{State: api.UpgradingState},
+			expected: map[v1alpha1.NodeUpgradeJobConditionType]conditionSummary{
+				v1alpha1.NodeUpgradeJobProgressing: {metav1.ConditionFalse, "Paused"},
+				v1alpha1.NodeUpgradeJobFailed:      {metav1.ConditionFalse, "Upgrading"},
+				v1alpha1.NodeUpgradeJobComplete:    {metav1.ConditionFalse, "Upgrading"},
+				v1alpha1.NodeUpgradeJobPaused:      {metav1.ConditionTrue, "Paused"},
+			},
+		},
+		{
+			name: "waiting for maintenance window",
+			status: v1alpha1.NodeUpgradeJobStatus{
+				State:             api.UpgradingState,
+				MaintenanceWindow: &v1alpha1.MaintenanceWindowStatus{PausedUntil: "2024-01-02T01:00:00Z", RemainingNodes: 3},
+			},
+			expected: map[v1alpha1.NodeUpgradeJobConditionType]conditionSummary{
+				v1alpha1.NodeUpgradeJobProgressing: {metav1.ConditionFalse, "WaitingForMaintenanceWindow"},
+				v1alpha1.NodeUpgradeJobFailed:      {metav1.ConditionFalse, "Upgrading"},
+				v1alpha1.NodeUpgradeJobComplete:    {metav1.ConditionFalse, "Upgrading"},
+				v1alpha1.NodeUpgradeJobPaused:      {metav1.ConditionFalse, "NotPaused"},
+			},
+		},
+		{
+			name:   "waiting for approval",
+			spec:   v1alpha1.NodeUpgradeJobSpec{Canary: &v1alpha1.CanarySpec{Nodes: 1}},
+			status: v1alpha1.NodeUpgradeJobStatus{State: api.WaitingApprovalState},
+			expected: map[v1alpha1.NodeUpgradeJobConditionType]conditionSummary{
+				v1alpha1.NodeUpgradeJobProgressing: {metav1.ConditionFalse, "WaitingApproval"},
+				v1alpha1.NodeUpgradeJobFailed:      {metav1.ConditionFalse, "WaitingApproval"},
+				v1alpha1.NodeUpgradeJobComplete:    {metav1.ConditionFalse, "WaitingApproval"},
+				v1alpha1.NodeUpgradeJobPaused:      {metav1.ConditionFalse, "NotPaused"},
+				v1alpha1.NodeUpgradeJobApproved:    {metav1.ConditionFalse, "WaitingApproval"},
+			},
+		},
+		{
+			name:   "approved",
+			spec:   v1alpha1.NodeUpgradeJobSpec{Canary: &v1alpha1.CanarySpec{Nodes: 1, Approve: true}},
+			status: v1alpha1.NodeUpgradeJobStatus{State: api.UpgradingState},
+			expected: map[v1alpha1.NodeUpgradeJobConditionType]conditionSummary{
+				v1alpha1.NodeUpgradeJobProgressing: {metav1.ConditionTrue, "Upgrading"},
+				v1alpha1.NodeUpgradeJobFailed:      {metav1.ConditionFalse, "Upgrading"},
+				v1alpha1.NodeUpgradeJobComplete:    {metav1.ConditionFalse, "Upgrading"},
+				v1alpha1.NodeUpgradeJobPaused:      {metav1.ConditionFalse, "NotPaused"},
+				v1alpha1.NodeUpgradeJobApproved:    {metav1.ConditionTrue, "Approved"},
+			},
+		},
+		{
+			name:   "successful",
+			spec:   v1alpha1.NodeUpgradeJobSpec{Paused: true},
+			status: v1alpha1.NodeUpgradeJobStatus{State: api.TaskSuccessful},
+			expected: map[v1alpha1.NodeUpgradeJobConditionType]conditionSummary{
+				v1alpha1.NodeUpgradeJobProgressing: {metav1.ConditionFalse, "Successful"},
+				v1alpha1.NodeUpgradeJobFailed:      {metav1.ConditionFalse, "Successful"},
+				v1alpha1.NodeUpgradeJobComplete:    {metav1.ConditionTrue, "Successful"},
+				v1alpha1.NodeUpgradeJobPaused:      {metav1.ConditionFalse, "NotPaused"},
+			},
+		},
+		{
+			name:   "partially succeeded",
+			status: v1alpha1.NodeUpgradeJobStatus{State: api.TaskPartiallySucceeded},
+			expected: map[v1alpha1.NodeUpgradeJobConditionType]conditionSummary{
+				v1alpha1.NodeUpgradeJobProgressing: {metav1.ConditionFalse, "PartiallySucceeded"},
+				v1alpha1.NodeUpgradeJobFailed:      {metav1.ConditionFalse, "PartiallySucceeded"},
+				v1alpha1.NodeUpgradeJobComplete:    {metav1.ConditionTrue, "PartiallySucceeded"},
+				v1alpha1.NodeUpgradeJobPaused:      {metav1.ConditionFalse, "NotPaused"},
+			},
+		},
+		{
+			name:   "failed",
+			status: v1alpha1.NodeUpgradeJobStatus{State: api.TaskFailed, Reason: "2 nodes failed"},
+			expected: map[v1alpha1.NodeUpgradeJobConditionType]conditionSummary{
+				v1alpha1.NodeUpgradeJobProgressing: {metav1.ConditionFalse, "Failed"},
+				v1alpha1.NodeUpgradeJobFailed:      {metav1.ConditionTrue, "Failed"},
+				v1alpha1.NodeUpgradeJobComplete:    {metav1.ConditionFalse, "Failed"},
+				v1alpha1.NodeUpgradeJobPaused:      {metav1.ConditionFalse, "NotPaused"},
+			},
+		},
+		{
+			name:   "deadline exceeded",
+			status: v1alpha1.NodeUpgradeJobStatus{State: api.TaskDeadlineExceeded},
+			expected: map[v1alpha1.NodeUpgradeJobConditionType]conditionSummary{
+				v1alpha1.NodeUpgradeJobProgressing: {metav1.ConditionFalse, "DeadlineExceeded"},
+				v1alpha1.NodeUpgradeJobFailed:      {metav1.ConditionTrue, "DeadlineExceeded"},
+				v1alpha1.NodeUpgradeJobComplete:    {metav1.ConditionFalse, "DeadlineExceeded"},
+				v1alpha1.NodeUpgradeJobPaused:      {metav1.ConditionFalse, "NotPaused"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			upgrade := &v1alpha1.NodeUpgradeJob{ObjectMeta: metav1.ObjectMeta{Generation: 2}, Spec: test.spec}
+			status := test.status
+			setUpgradeConditions(upgrade, &status)
+			if result := summarizeConditions(status.Conditions); !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("Got = %v, Want = %v", result, test.expected)
+			}
+			for _, condition := range status.Conditions {
+				if condition.ObservedGeneration != 2 {
+					t.Errorf("expected condition %s to observe generation 2, got %d", condition.Type, condition.ObservedGeneration)
+				}
+			}
+		})
+	}
+}
+
+func TestSetUpgradeConditionsTransition(t *testing.T) {
+	upgrade := &v1alpha1.NodeUpgradeJob{Spec: v1alpha1.NodeUpgradeJobSpec{Canary: &v1alpha1.CanarySpec{Nodes: 1}}}
+	status := &v1alpha1.NodeUpgradeJobStatus{State: api.UpgradingState}
+	setUpgradeConditions(upgrade, status)
+
+	// move the transition times back to tell which conditions change
+	past := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	for i := range status.Conditions {
+		status.Conditions[i].LastTransitionTime = past
+	}
+
+	status.State = api.TaskFailed
+	status.Reason = "canary node failed"
+	setUpgradeConditions(upgrade, status)
+
+	tests := []struct {
+		conditionType v1alpha1.NodeUpgradeJobConditionType
+		transited     bool
+		message       string
+	}{
+		{conditionType: v1alpha1.NodeUpgradeJobProgressing, transited: true, message: "canary node failed"},
+		{conditionType: v1alpha1.NodeUpgradeJobFailed, transited: true, message: "canary node failed"},
+		{conditionType: v1alpha1.NodeUpgradeJobComplete},
+		{conditionType: v1alpha1.NodeUpgradeJobPaused},
+		{conditionType: v1alpha1.NodeUpgradeJobApproved},
+	}
+	for _, test := range tests {
+		t.Run(string(test.conditionType), func(t *testing.T) {
+			condition := meta.FindStatusCondition(status.Conditions, string(test.conditionType))
+			if condition == nil {
+				t.Fatalf("expected condition %s to be set", test.conditionType)
+			}
+			if transited := !condition.LastTransitionTime.Equal(&past); transited != test.transited {
+				t.Errorf("expected the transition time to be updated: %t, got %s", test.transited, condition.LastTransitionTime)
+			}
+			if condition.Message != test.message {
+				t.Errorf("Got = %q, Want = %q", condition.Message, test.message)
+			}
+		})
+	}
+
+	// the approval condition is removed along with the canary
+	upgrade.Spec.Canary = nil
+	setUpgradeConditions(upgrade, status)
+	if meta.FindStatusCondition(status.Conditions, string(v1alpha1.NodeUpgradeJobApproved)) != nil {
+		t.Errorf("expected the Approved condition to be removed without the canary")
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeupgradecontroller
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// resolveInputs returns the NodeUpgradeJob with the references to its inputs substituted, the inputs
+// are resolved once and recorded in its status so that the job resumed after cloudcore restarted runs
+// with the same values. It returns nil if the job waits for the jobs its inputs reference, it is added
+// again once they are checked again, or if the job failed because its inputs cannot be resolved.
+func (ndc *NodeUpgradeController) resolveInputs(upgrade *v1alpha1.NodeUpgradeJob) *v1alpha1.NodeUpgradeJob {
+	if len(upgrade.Spec.Inputs) == 0 {
+		return upgrade
+	}
+	values := upgrade.Status.Inputs
+	if values == nil {
+		var pending string
+		var err error
+		values, pending, err = util.ResolveInputs(upgrade.Spec.Inputs)
+		if err != nil {
+			ndc.finishUnresolved(upgrade, api.TaskFailed, err.Error())
+			return nil
+		}
+		if pending != "" {
+			ndc.waitForInputs(upgrade, pending)
+			return nil
+		}
+	}
+	util.StopWaitingForInputs("NodeUpgradeJob", upgrade.Name)
+
+	resolved := upgrade.DeepCopy()
+	if err := util.SubstituteInputs(&resolved.Spec, values); err != nil {
+		ndc.finishUnresolved(upgrade, api.TaskFailed, err.Error())
+		return nil
+	}
+	if upgrade.Status.Inputs == nil {
+		status := upgrade.Status
+		status.Inputs = values
+		if err := patchStatus(upgrade.DeepCopy(), status, ndc.CrdClient); err != nil {
+			klog.Errorf("failed to record the inputs of NodeUpgradeJob %s: %v", upgrade.Name, err)
+			return nil
+		}
+		resolved.Status.Inputs = values
+		klog.Infof("NodeUpgradeJob %s runs with inputs %v", upgrade.Name, values)
+	}
+	return resolved
+}
+
+// waitForInputs checks the inputs of the NodeUpgradeJob again later, the job waiting for its inputs
+// is cancelled at once if it is requested
+func (ndc *NodeUpgradeController) waitForInputs(upgrade *v1alpha1.NodeUpgradeJob, reason string) {
+	if util.IsCancelRequested(upgrade) {
+		ndc.finishUnresolved(upgrade, api.TaskCancelled, "the job is cancelled while waiting for its inputs: "+reason)
+		return
+	}
+	name := upgrade.Name
+	klog.Infof("NodeUpgradeJob %s waits for its inputs: %s", name, reason)
+	retry := func() {
+		value, ok := ndc.TaskManager.CacheMap.Load(name)
+		if !ok {
+			return
+		}
+		ndc.TaskManager.Events() <- watch.Event{Type: watch.Added, Object: value.(*v1alpha1.NodeUpgradeJob)}
+	}
+	if util.WaitForInputs("NodeUpgradeJob", name, retry) {
+		util.RecordWaitingForInputs(upgrade, "NodeUpgradeJob", reason)
+	}
+}
+
+// finishUnresolved finishes the NodeUpgradeJob whose inputs are not resolved before it started
+func (ndc *NodeUpgradeController) finishUnresolved(upgrade *v1alpha1.NodeUpgradeJob, state api.State, reason string) {
+	util.StopWaitingForInputs("NodeUpgradeJob", upgrade.Name)
+	klog.Warningf("NodeUpgradeJob %s is %s: %s", upgrade.Name, state, reason)
+	event := fsm.Event{Type: "Inputs", Action: api.ActionFailure, Msg: reason}
+	status := upgrade.Status
+	status.State = state
+	status.Event = event.Type
+	status.Action = event.Action
+	status.Reason = reason
+	status.Time = time.Now().Format(util.ISO8601UTC)
+	status.Outputs = upgradeOutputs(upgrade, status)
+	if err := patchStatus(upgrade.DeepCopy(), status, ndc.CrdClient); err != nil {
+		klog.Errorf("failed to finish NodeUpgradeJob %s: %v", upgrade.Name, err)
+		return
+	}
+	util.RecordTaskTransition(upgrade, "NodeUpgradeJob", upgrade.Status.State, state, event)
+	util.NotifyTaskState(util.TaskUpgrade, upgrade.Name, state, reason)
+	util.NotifyTaskFinished(upgrade, "NodeUpgradeJob", upgrade.Spec.Notifications, state, reason, status.Status)
+}
+
+// upgradeOutputs returns the outputs of the finished NodeUpgradeJob
+func upgradeOutputs(upgrade *v1alpha1.NodeUpgradeJob, status v1alpha1.NodeUpgradeJobStatus) map[string]string {
+	outputs := util.NodeOutputs(status.Status)
+	outputs[util.OutputVersion] = util.ExpandInputs(upgrade.Spec.Version, status.Inputs)
+	return outputs
+}
@@ -21,7 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"strconv"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
@@ -33,12 +33,14 @@ import (
 	apimachineryType "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 
 	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
 	keclient "github.com/kubeedge/kubeedge/cloud/pkg/common/client"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/config"
 	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
 	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
 	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/manager"
@@ -58,6 +60,32 @@ type NodeUpgradeController struct {
 
 var cache *manager.TaskCache
 
+func init() {
+	controller.RegisterPipeline(util.TaskUpgrade, controller.Pipeline{
+		Stages:        []api.State{api.TaskChecking, api.BackingUpState, api.UpgradingState, api.RollingBackState, api.RevertingState},
+		VerifiesNodes: true,
+		Concurrency:   1,
+	})
+	util.RegisterTaskObjects(util.TaskUpgrade, util.NewTypedTaskObjects(v1alpha1.Resource("nodeupgradejobs"),
+		func(crdClient crdClientset.Interface) util.TypedTaskClient[*v1alpha1.NodeUpgradeJob, *v1alpha1.NodeUpgradeJobList] {
+			return crdClient.OperationsV1alpha1().NodeUpgradeJobs()
+		},
+		func(meta metav1.ObjectMeta, spec v1alpha1.NodeUpgradeJobSpec) *v1alpha1.NodeUpgradeJob {
+			return &v1alpha1.NodeUpgradeJob{ObjectMeta: meta, Spec: spec}
+		},
+		func(job *v1alpha1.NodeUpgradeJob) util.TaskObjectStatus {
+			return util.TaskObjectStatus{State: job.Status.State, Reason: job.Status.Reason, Time: job.Status.Time,
+				NodeStatus: job.Status.Status, Status: job.Status}
+		},
+		func(job *v1alpha1.NodeUpgradeJob) *int32 {
+			// the job is kept until its promotion is decided
+			if job.Spec.Promotion != nil && !job.Spec.DryRun && job.Status.Promotion == nil {
+				return nil
+			}
+			return job.Spec.TTLSecondsAfterFinished
+		}))
+}
+
 func NewNodeUpgradeController(messageChan chan util.TaskMessage) (*NodeUpgradeController, error) {
 	var err error
 	cache, err = manager.NewTaskCache(
@@ -66,6 +94,10 @@ func NewNodeUpgradeController(messageChan chan util.TaskMessage) (*NodeUpgradeCo
 		klog.Warningf("Create NodeUpgradeJob manager failed with error: %s", err)
 		return nil, err
 	}
+	// pods and replicasets are used to analyze the workloads affected by an upgrade
+	kubeInformer := informers.GetInformersManager().GetKubeInformerFactory()
+	kubeInformer.Core().V1().Pods().Informer()
+	kubeInformer.Apps().V1().ReplicaSets().Informer()
 	return &NodeUpgradeController{
 		BaseController: &controller.BaseController{
 			Informer:    informers.GetInformersManager().GetKubeInformerFactory(),
@@ -78,6 +110,7 @@ func NewNodeUpgradeController(messageChan chan util.TaskMessage) (*NodeUpgradeCo
 }
 
 func (ndc *NodeUpgradeController) ReportNodeStatus(taskID, nodeID string, event fsm.Event) (api.State, error) {
+	event = postCheckEvent(taskID, event)
 	nodeFSM := NewUpgradeNodeFSM(taskID, nodeID)
 	err := nodeFSM.AllowTransit(event)
 	if err != nil {
@@ -101,6 +134,19 @@ func (ndc *NodeUpgradeController) ReportNodeStatus(taskID, nodeID string, event
 	return state, nil
 }
 
+// postCheckEvent turns the upgrade reported by the node into the start of its post check if the job has one
+func postCheckEvent(taskID string, event fsm.Event) fsm.Event {
+	if event.Type != "Upgrade" || event.Action != api.ActionSuccess {
+		return event
+	}
+	v, ok := cache.CacheMap.Load(taskID)
+	if !ok || v.(*v1alpha1.NodeUpgradeJob).Spec.PostCheck == nil {
+		return event
+	}
+	event.Type = api.EventPostCheck
+	return event
+}
+
 func checkStatusChanged(nodeFSM *fsm.FSM, state api.State) {
 	err := wait.Poll(100*time.Millisecond, time.Second, func() (bool, error) {
 		nowState, err := nodeFSM.CurrentState()
@@ -135,20 +181,22 @@ func (ndc *NodeUpgradeController) ReportTaskStatus(taskID string, event fsm.Even
 	return taskFSM.CurrentState()
 }
 
-func (ndc *NodeUpgradeController) ValidateNode(taskMessage util.TaskMessage) []v1.Node {
+func (ndc *NodeUpgradeController) ValidateNode(taskMessage util.TaskMessage) ([]v1.Node, []v1alpha1.ExcludedNode) {
 	var validateNodes []v1.Node
-	nodes := ndc.BaseController.ValidateNode(taskMessage)
+	nodes, excluded := ndc.BaseController.ValidateNode(taskMessage)
 	req, ok := taskMessage.Msg.(commontypes.NodeUpgradeJobRequest)
 	if !ok {
 		klog.Errorf("convert message to commontypes.NodeUpgradeJobRequest failed")
-		return nil
+		return nil, excluded
 	}
 	for _, node := range nodes {
-		if needUpgrade(node, req.Version) {
-			validateNodes = append(validateNodes, node)
+		if reason, msg := util.UpgradeExclusion(node, req.Version); reason != "" {
+			excluded = append(excluded, v1alpha1.ExcludedNode{NodeName: node.Name, Reason: reason, Message: msg})
+			continue
 		}
+		validateNodes = append(validateNodes, node)
 	}
-	return validateNodes
+	return validateNodes, excluded
 }
 
 func (ndc *NodeUpgradeController) StageCompleted(taskID string, state api.State) bool {
@@ -156,6 +204,10 @@ func (ndc *NodeUpgradeController) StageCompleted(taskID string, state api.State)
 	return taskFSM.TaskStagCompleted(state)
 }
 
+func (ndc *NodeUpgradeController) KnownState(taskID string, state api.State) bool {
+	return NewUpgradeTaskFSM(taskID).KnownState(state)
+}
+
 func (ndc *NodeUpgradeController) GetNodeStatus(name string) ([]v1alpha1.TaskStatus, error) {
 	nodeUpgrade, err := ndc.CrdClient.OperationsV1alpha1().NodeUpgradeJobs().Get(context.TODO(), name, metav1.GetOptions{})
 	if err != nil {
@@ -164,6 +216,75 @@ func (ndc *NodeUpgradeController) GetNodeStatus(name string) ([]v1alpha1.TaskSta
 	return nodeUpgrade.Status.Status, nil
 }
 
+// AnalyzeImpact records the workloads that will be disrupted by upgrading the nodes
+func (ndc *NodeUpgradeController) AnalyzeImpact(name string, nodes []v1.Node) error {
+	workloads, err := ndc.AffectedWorkloads(nodes)
+	if err != nil {
+		return err
+	}
+	for _, workload := range workloads {
+		klog.Infof("NodeUpgradeJob %s affects %s %s/%s on nodes %v", name, workload.Kind, workload.Namespace, workload.Name, workload.NodeNames)
+	}
+	nodeUpgrade, err := ndc.CrdClient.OperationsV1alpha1().NodeUpgradeJobs().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	status := nodeUpgrade.Status
+	status.AffectedWorkloads = workloads
+	return patchStatus(nodeUpgrade, status, ndc.CrdClient)
+}
+
+// RecordExcludedNodes records the nodes selected by the NodeUpgradeJob which are not upgraded
+func (ndc *NodeUpgradeController) RecordExcludedNodes(name string, excluded []v1alpha1.ExcludedNode) error {
+	nodeUpgrade, err := ndc.CrdClient.OperationsV1alpha1().NodeUpgradeJobs().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	status := nodeUpgrade.Status
+	status.ExcludedNodes = excluded
+	return patchStatus(nodeUpgrade, status, ndc.CrdClient)
+}
+
+// RecordFailureThreshold records the number of failed nodes the NodeUpgradeJob fails at
+func (ndc *NodeUpgradeController) RecordFailureThreshold(name string, threshold int32) error {
+	nodeUpgrade, err := ndc.CrdClient.OperationsV1alpha1().NodeUpgradeJobs().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if nodeUpgrade.Status.FailureThreshold == threshold {
+		return nil
+	}
+	status := nodeUpgrade.Status
+	status.FailureThreshold = threshold
+	return patchStatus(nodeUpgrade, status, ndc.CrdClient)
+}
+
+// RecordMaintenanceWindow records the maintenance window the NodeUpgradeJob is paused until,
+// the record is removed if window is nil
+func (ndc *NodeUpgradeController) RecordMaintenanceWindow(name string, window *v1alpha1.MaintenanceWindowStatus) error {
+	nodeUpgrade, err := ndc.CrdClient.OperationsV1alpha1().NodeUpgradeJobs().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(nodeUpgrade.Status.MaintenanceWindow, window) {
+		return nil
+	}
+	status := nodeUpgrade.Status
+	status.MaintenanceWindow = window
+	return patchStatus(nodeUpgrade, status, ndc.CrdClient)
+}
+
+// RecordPlan records how the NodeUpgradeJob which is a dry run would upgrade its nodes
+func (ndc *NodeUpgradeController) RecordPlan(name string, plan v1alpha1.ExecutionPlan) error {
+	nodeUpgrade, err := ndc.CrdClient.OperationsV1alpha1().NodeUpgradeJobs().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	status := nodeUpgrade.Status
+	status.Plan = &plan
+	return patchStatus(nodeUpgrade, status, ndc.CrdClient)
+}
+
 func (ndc *NodeUpgradeController) GetNodeVersion(name string) (string, error) {
 	node, err := ndc.Informer.Core().V1().Nodes().Lister().Get(name)
 	if err != nil {
@@ -187,11 +308,19 @@ func (ndc *NodeUpgradeController) UpdateNodeStatus(name string, nodeStatus []v1a
 	return nil
 }
 
+// patchStatus patches the status of the NodeUpgradeJob, its conditions and its progress are
+// updated from the status
 func patchStatus(nodeUpgrade *v1alpha1.NodeUpgradeJob, status v1alpha1.NodeUpgradeJobStatus, crdClient crdClientset.Interface) error {
 	oldData, err := json.Marshal(nodeUpgrade)
 	if err != nil {
 		return fmt.Errorf("failed to marshal the old NodeUpgradeJob(%s): %v", nodeUpgrade.Name, err)
 	}
+	status.Conditions = append([]metav1.Condition(nil), status.Conditions...)
+	if fsm.TaskFinish(status.State) {
+		status.MaintenanceWindow = nil
+	}
+	setUpgradeConditions(nodeUpgrade, &status)
+	status.Progress = util.NodeProgress(status.Status)
 	nodeUpgrade.Status = status
 	newData, err := json.Marshal(nodeUpgrade)
 	if err != nil {
@@ -216,14 +345,34 @@ func (ndc *NodeUpgradeController) Start() error {
 	return nil
 }
 
+// Resync sends the unfinished NodeUpgradeJobs to the executors again, they are handled as if they were added
+func (ndc *NodeUpgradeController) Resync() error {
+	jobs, err := ndc.CrdClient.OperationsV1alpha1().NodeUpgradeJobs().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range jobs.Items {
+		if fsm.TaskFinish(jobs.Items[i].Status.State) {
+			continue
+		}
+		ndc.TaskManager.Events() <- watch.Event{Type: watch.Added, Object: &jobs.Items[i]}
+	}
+	return nil
+}
+
 func (ndc *NodeUpgradeController) startSync() {
 	nodeUpgradeList, err := ndc.CrdClient.OperationsV1alpha1().NodeUpgradeJobs().List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
 		klog.Errorf(err.Error())
 		os.Exit(2)
 	}
-	for _, nodeUpgrade := range nodeUpgradeList.Items {
+	for i, nodeUpgrade := range nodeUpgradeList.Items {
 		if fsm.TaskFinish(nodeUpgrade.Status.State) {
+			// the promotion of the finished job may have been soaking when cloudcore stopped, its timer is armed again
+			if nodeUpgrade.Spec.Promotion != nil && nodeUpgrade.Status.Promotion == nil {
+				ndc.TaskManager.CacheMap.Store(nodeUpgrade.Name, &nodeUpgradeList.Items[i])
+				ndc.schedulePromotion(&nodeUpgradeList.Items[i])
+			}
 			continue
 		}
 		ndc.nodeUpgradeJobAdded(&nodeUpgrade)
@@ -258,14 +407,128 @@ func (ndc *NodeUpgradeController) nodeUpgradeJobAdded(upgrade *v1alpha1.NodeUpgr
 	klog.V(4).Infof("add NodeUpgradeJob: %v", upgrade)
 	// store in cache map
 	ndc.TaskManager.CacheMap.Store(upgrade.Name, upgrade)
+	if name, ok := upgrade.Labels[util.VersionMappingOfLabelKey]; ok {
+		ndc.trackVersionMappings(name)
+	}
 
 	// If all or partial edge nodes upgrade is upgrading or completed, we don't need to send upgrade message
 	if fsm.TaskFinish(upgrade.Status.State) {
+		if util.RerunRequested(upgrade.Status.State, upgrade.Spec.Rerun, upgrade.Status.ObservedRerun) {
+			ndc.rerunUpgrade(upgrade)
+			return
+		}
 		klog.Warning("The nodeUpgradeJob is completed, don't send upgrade message again")
+		ndc.schedulePromotion(upgrade)
+		return
+	}
+
+	resolved := ndc.resolveInputs(upgrade)
+	if resolved == nil {
+		return
+	}
+	if len(resolved.Spec.VersionMappings) != 0 {
+		ndc.runVersionMappings(resolved)
 		return
 	}
+	ndc.processUpgrade(resolved)
+	if util.IsCancelRequested(upgrade) {
+		ndc.cancelUpgrade(upgrade)
+	}
+}
+
+// cancelUpgrade requests the executor of the NodeUpgradeJob to cancel it
+func (ndc *NodeUpgradeController) cancelUpgrade(upgrade *v1alpha1.NodeUpgradeJob) {
+	klog.Infof("cancel NodeUpgradeJob %s", upgrade.Name)
+	ndc.MessageChan <- util.TaskMessage{
+		Type:   util.TaskUpgrade,
+		Name:   upgrade.Name,
+		Cancel: true,
+		Drain:  util.IsGracefulCancel(upgrade),
+	}
+}
+
+// pauseUpgrade requests the executor of the NodeUpgradeJob to pause or resume it
+func (ndc *NodeUpgradeController) pauseUpgrade(upgrade *v1alpha1.NodeUpgradeJob) {
+	klog.Infof("set NodeUpgradeJob %s paused: %t", upgrade.Name, upgrade.Spec.Paused)
+	ndc.MessageChan <- util.TaskMessage{
+		Type:      util.TaskUpgrade,
+		Name:      upgrade.Name,
+		Paused:    upgrade.Spec.Paused,
+		SetPaused: true,
+	}
+	ndc.updateConditions(upgrade.Name)
+}
 
-	ndc.processUpgrade(upgrade)
+// PauseTask pauses the NodeUpgradeJob by setting Paused, it is called once a PauseOn condition of the job matches
+func (ndc *NodeUpgradeController) PauseTask(name string) error {
+	jobs := ndc.CrdClient.OperationsV1alpha1().NodeUpgradeJobs()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		upgrade, err := jobs.Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if upgrade.Spec.Paused {
+			return nil
+		}
+		upgrade.Spec.Paused = true
+		_, err = jobs.Update(context.TODO(), upgrade, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// applyUpgrade passes how the NodeUpgradeJob is applied to its executor
+func (ndc *NodeUpgradeController) applyUpgrade(upgrade *v1alpha1.NodeUpgradeJob) {
+	klog.Infof("update how NodeUpgradeJob %s is applied", upgrade.Name)
+	ndc.MessageChan <- util.TaskMessage{
+		Type:     util.TaskUpgrade,
+		Name:     upgrade.Name,
+		Apply:    upgrade.Spec.Apply,
+		SetApply: true,
+	}
+}
+
+// approveUpgrade passes the approval of the canary of the NodeUpgradeJob to its executor
+func (ndc *NodeUpgradeController) approveUpgrade(upgrade *v1alpha1.NodeUpgradeJob) {
+	klog.Infof("set NodeUpgradeJob %s approved: %t", upgrade.Name, upgrade.Spec.Canary != nil && upgrade.Spec.Canary.Approve)
+	ndc.MessageChan <- util.TaskMessage{
+		Type:       util.TaskUpgrade,
+		Name:       upgrade.Name,
+		Canary:     upgrade.Spec.Canary,
+		SetApprove: true,
+	}
+	ndc.updateConditions(upgrade.Name)
+}
+
+// tuneUpgrade passes the concurrency, the rolling strategy and the failure tolerance of the running
+// NodeUpgradeJob to its executor
+func (ndc *NodeUpgradeController) tuneUpgrade(upgrade *v1alpha1.NodeUpgradeJob) {
+	klog.Infof("tune NodeUpgradeJob %s", upgrade.Name)
+	concurrency, maxUnavailable := util.UpgradeConcurrency(upgrade.Spec)
+	ndc.MessageChan <- util.TaskMessage{
+		Type:            util.TaskUpgrade,
+		Name:            upgrade.Name,
+		Concurrency:     concurrency,
+		MaxUnavailable:  maxUnavailable,
+		FailureTolerate: upgrade.Spec.FailureTolerate,
+		SetTuning:       true,
+	}
+}
+
+// updateConditions updates the conditions of the NodeUpgradeJob after its spec changed
+func (ndc *NodeUpgradeController) updateConditions(name string) {
+	nodeUpgrade, err := ndc.CrdClient.OperationsV1alpha1().NodeUpgradeJobs().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		klog.Errorf("failed to get NodeUpgradeJob %s: %v", name, err)
+		return
+	}
+	status := nodeUpgrade.Status.DeepCopy()
+	setUpgradeConditions(nodeUpgrade, status)
+	if reflect.DeepEqual(status.Conditions, nodeUpgrade.Status.Conditions) {
+		return
+	}
+	if err = patchStatus(nodeUpgrade, *status, ndc.CrdClient); err != nil {
+		klog.Errorf("failed to update the conditions of NodeUpgradeJob %s: %v", name, err)
+	}
 }
 
 // processUpgrade do the upgrade operation on node
@@ -291,51 +554,95 @@ func (ndc *NodeUpgradeController) processUpgrade(upgrade *v1alpha1.NodeUpgradeJo
 		Version:   upgrade.Spec.Version,
 		Image:     image,
 	}
-
-	tolerate, err := strconv.ParseFloat(upgrade.Spec.FailureTolerate, 64)
-	if err != nil {
-		klog.Errorf("convert FailureTolerate to float64 failed: %v", err)
-		tolerate = 0.1
+	if upgrade.Spec.Verification != nil {
+		upgradeReq.VerificationProbes = upgrade.Spec.Verification.Probes
 	}
 
-	concurrency := upgrade.Spec.Concurrency
-	if concurrency <= 0 {
-		concurrency = 1
+	concurrency, maxUnavailable := util.UpgradeConcurrency(upgrade.Spec)
+	orderingSeed := util.OrderingSeed(upgrade.Spec.Ordering, upgrade.Spec.OrderingSeed, upgrade.Status.OrderingSeed)
+	if orderingSeed != nil && upgrade.Status.OrderingSeed == nil {
+		status := upgrade.Status
+		status.OrderingSeed = orderingSeed
+		if err = patchStatus(upgrade.DeepCopy(), status, ndc.CrdClient); err != nil {
+			klog.Errorf("failed to record the ordering seed of NodeUpgradeJob %s: %v", upgrade.Name, err)
+			return
+		}
+		klog.Infof("NodeUpgradeJob %s upgrades its nodes in random order, seed %d", upgrade.Name, *orderingSeed)
+	}
+	checkItems := util.VersionCheckItems(config.Config.VersionCheckItems, upgrade.Spec.Version, upgrade.Spec.CheckItems)
+	if len(checkItems) != len(upgrade.Spec.CheckItems) {
+		klog.Infof("NodeUpgradeJob %s runs the check items %v of version %s", upgrade.Name, checkItems[len(upgrade.Spec.CheckItems):], upgrade.Spec.Version)
 	}
 	klog.V(4).Infof("deal task message: %v", upgrade)
 	ndc.MessageChan <- util.TaskMessage{
-		Type:            util.TaskUpgrade,
-		CheckItem:       upgrade.Spec.CheckItems,
-		Name:            upgrade.Name,
-		TimeOutSeconds:  upgrade.Spec.TimeoutSeconds,
-		Concurrency:     concurrency,
-		FailureTolerate: tolerate,
-		NodeNames:       upgrade.Spec.NodeNames,
-		LabelSelector:   upgrade.Spec.LabelSelector,
-		Status:          v1alpha1.TaskStatus{},
-		Msg:             upgradeReq,
+		Type:                   util.TaskUpgrade,
+		CheckItem:              checkItems,
+		RotateCertificate:      upgrade.Spec.RotateExpiringCertificate,
+		Name:                   upgrade.Name,
+		TimeOutSeconds:         upgrade.Spec.TimeoutSeconds,
+		StageTimeouts:          util.UpgradeStageTimeouts(upgrade.Spec.Timeouts),
+		Concurrency:            concurrency,
+		MaxUnavailable:         maxUnavailable,
+		Paused:                 upgrade.Spec.Paused,
+		PauseOn:                upgrade.Spec.PauseOn,
+		Apply:                  upgrade.Spec.Apply,
+		Canary:                 upgrade.Spec.Canary,
+		DrainNodeBeforeUpgrade: upgrade.Spec.DrainNodeBeforeUpgrade,
+		OrderingSeed:           orderingSeed,
+		OrderByReadiness:       upgrade.Spec.Ordering == v1alpha1.NodeOrderingReadiness,
+		DryRun:                 upgrade.Spec.DryRun,
+		ActiveDeadline:         util.ActiveDeadline(upgrade.Spec.ActiveDeadlineSeconds),
+		DispatchJitterSeconds:  upgrade.Spec.DispatchJitterSeconds,
+		Preflight:              upgrade.Spec.Preflight,
+		PostCheck:              upgrade.Spec.PostCheck,
+		Version:                upgrade.Spec.Version,
+		FailureTolerate:        upgrade.Spec.FailureTolerate,
+		RollbackPolicy:         upgrade.Spec.RollbackPolicy,
+		NodeNames:              upgrade.Spec.NodeNames,
+		LabelSelector:          upgrade.Spec.LabelSelector,
+		NodeGroups:             upgrade.Spec.NodeGroups,
+		Status:                 v1alpha1.TaskStatus{},
+		Msg:                    upgradeReq,
+		Labels:                 upgrade.Labels,
+		Metadata:               upgrade.Spec.Metadata,
+		RenderPayload:          upgrade.Spec.RenderPayload,
+		Notifications:          upgrade.Spec.Notifications,
+		Credential:             upgrade.Spec.Credential,
+		Owner:                  util.NewTaskOwnerReference(upgrade, "NodeUpgradeJob"),
 	}
 }
 
-func needUpgrade(node v1.Node, upgradeVersion string) bool {
-	if util.FilterVersion(node.Status.NodeInfo.KubeletVersion, upgradeVersion) {
-		klog.Warningf("Node(%s) version(%s) already on the expected version %s.", node.Name, node.Status.NodeInfo.KubeletVersion, upgradeVersion)
-		return false
+// rerunUpgrade resets the status of the finished NodeUpgradeJob and runs it again with its current spec
+func (ndc *NodeUpgradeController) rerunUpgrade(upgrade *v1alpha1.NodeUpgradeJob) {
+	klog.Infof("rerun NodeUpgradeJob %s, rerun %d", upgrade.Name, upgrade.Spec.Rerun)
+	stopPromotion(upgrade.Name)
+	rerun := upgrade.DeepCopy()
+	status := v1alpha1.NodeUpgradeJobStatus{
+		ObservedRerun: upgrade.Spec.Rerun,
+		StartTime:     time.Now().UTC().Format(util.ISO8601UTC),
+	}
+	if err := patchStatus(rerun, status, ndc.CrdClient); err != nil {
+		klog.Errorf("failed to reset the status of NodeUpgradeJob %s to rerun it: %v", upgrade.Name, err)
+		return
 	}
-
-	// if node is in Upgrading state, don't need upgrade
-	if _, ok := node.Labels[util.NodeUpgradeJobStatusKey]; ok {
-		klog.Warningf("Node(%s) is in upgrade state", node.Name)
-		return false
+	ndc.TaskManager.CacheMap.Store(upgrade.Name, rerun)
+	resolved := ndc.resolveInputs(rerun)
+	if resolved == nil {
+		return
 	}
-
-	return true
+	if len(resolved.Spec.VersionMappings) != 0 {
+		ndc.runVersionMappings(resolved)
+		return
+	}
+	ndc.processUpgrade(resolved)
 }
 
 // nodeUpgradeJobDeleted is used to process deleted NodeUpgradeJob in apiserver
 func (ndc *NodeUpgradeController) nodeUpgradeJobDeleted(upgrade *v1alpha1.NodeUpgradeJob) {
 	// just need to delete from cache map
 	ndc.TaskManager.CacheMap.Delete(upgrade.Name)
+	stopPromotion(upgrade.Name)
+	util.StopWaitingForInputs("NodeUpgradeJob", upgrade.Name)
 	klog.Errorf("upgrade job %s delete", upgrade.Name)
 	ndc.MessageChan <- util.TaskMessage{
 		Type:     util.TaskUpgrade,
@@ -357,6 +664,55 @@ func (ndc *NodeUpgradeController) nodeUpgradeJobUpdated(upgrade *v1alpha1.NodeUp
 
 	// store in cache map
 	ndc.TaskManager.CacheMap.Store(upgrade.Name, upgrade)
+	if name, ok := upgrade.Labels[util.VersionMappingOfLabelKey]; ok {
+		ndc.trackVersionMappings(name)
+	}
+	if util.RerunRequested(upgrade.Status.State, upgrade.Spec.Rerun, upgrade.Status.ObservedRerun) {
+		ndc.rerunUpgrade(upgrade)
+		return
+	}
+	if fsm.TaskFinish(upgrade.Status.State) && !reflect.DeepEqual(old.Spec, upgrade.Spec) {
+		klog.Infof("NodeUpgradeJob %s is finished, the change of its spec is ignored until it is rerun", upgrade.Name)
+	}
+	ndc.schedulePromotion(upgrade)
+	if len(upgrade.Spec.VersionMappings) != 0 {
+		ndc.updateVersionMappingJobs(old, upgrade)
+		ndc.trackVersionMappings(upgrade.Name)
+		return
+	}
+
+	if util.CancelRequested(old, upgrade) && !fsm.TaskFinish(upgrade.Status.State) {
+		ndc.cancelUpgrade(upgrade)
+		return
+	}
+	if old.Spec.Paused != upgrade.Spec.Paused && !fsm.TaskFinish(upgrade.Status.State) {
+		ndc.pauseUpgrade(upgrade)
+		return
+	}
+	if !reflect.DeepEqual(old.Spec.Apply, upgrade.Spec.Apply) && !fsm.TaskFinish(upgrade.Status.State) {
+		ndc.applyUpgrade(upgrade)
+		return
+	}
+	if !reflect.DeepEqual(old.Spec.Canary, upgrade.Spec.Canary) && !fsm.TaskFinish(upgrade.Status.State) {
+		ndc.approveUpgrade(upgrade)
+		return
+	}
+	if (!reflect.DeepEqual(old.Spec.Concurrency, upgrade.Spec.Concurrency) || !reflect.DeepEqual(old.Spec.RollingStrategy, upgrade.Spec.RollingStrategy) ||
+		!reflect.DeepEqual(old.Spec.FailureTolerate, upgrade.Spec.FailureTolerate)) && !fsm.TaskFinish(upgrade.Status.State) {
+		ndc.tuneUpgrade(upgrade)
+		return
+	}
+	if force, err := util.ForceCompletionRequested(old, upgrade); err != nil {
+		klog.Errorf("NodeUpgradeJob %s: %v", upgrade.Name, err)
+	} else if force != nil && !fsm.TaskFinish(upgrade.Status.State) {
+		klog.Infof("force node %s of NodeUpgradeJob %s to %s", force.NodeName, upgrade.Name, force.State)
+		ndc.MessageChan <- util.TaskMessage{
+			Type:          util.TaskUpgrade,
+			Name:          upgrade.Name,
+			ForceComplete: force,
+		}
+		return
+	}
 
 	node := checkUpdateNode(old, upgrade)
 	if node == nil {
@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeupgradecontroller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// promotions are the timers of the NodeUpgradeJobs whose promotion is scheduled by name,
+// a timer is kept after it fired so that the promotion is decided only once.
+var promotions sync.Map
+
+// schedulePromotion promotes the completed NodeUpgradeJob to the next node group once it soaked,
+// the promotion is decided at once if it is aborted.
+func (ndc *NodeUpgradeController) schedulePromotion(upgrade *v1alpha1.NodeUpgradeJob) {
+	promotion := upgrade.Spec.Promotion
+	// a dry run upgraded no node, there is nothing to promote
+	if promotion == nil || upgrade.Spec.DryRun || upgrade.Status.Promotion != nil || !fsm.TaskFinish(upgrade.Status.State) {
+		return
+	}
+	name := upgrade.Name
+	if value, ok := promotions.Load(name); ok {
+		// an aborted promotion which is still soaking is decided at once
+		if !util.IsPromotionAborted(upgrade) || !value.(*time.Timer).Stop() {
+			return
+		}
+		promotions.Delete(name)
+	}
+
+	var delay time.Duration
+	if !util.IsPromotionAborted(upgrade) {
+		completed, err := time.Parse(util.ISO8601UTC, upgrade.Status.Time)
+		if err != nil {
+			completed = time.Now()
+		}
+		delay = time.Until(completed.Add(time.Duration(promotion.SoakSeconds) * time.Second))
+		klog.Infof("NodeUpgradeJob %s is promoted to node group %s in %s", name, promotion.NodeGroup, delay.Round(time.Second))
+	}
+	promotions.Store(name, time.AfterFunc(delay, func() {
+		ndc.promote(name)
+	}))
+}
+
+// stopPromotion cancels the scheduled promotion of the deleted NodeUpgradeJob
+func stopPromotion(name string) {
+	if value, ok := promotions.LoadAndDelete(name); ok {
+		value.(*time.Timer).Stop()
+	}
+}
+
+// promote creates the NodeUpgradeJob of the next node group if the job meets the success threshold
+// of its promotion, and records the outcome in the status of the job.
+func (ndc *NodeUpgradeController) promote(name string) {
+	value, ok := ndc.TaskManager.CacheMap.Load(name)
+	if !ok {
+		return
+	}
+	upgrade := value.(*v1alpha1.NodeUpgradeJob).DeepCopy()
+	if upgrade.Status.Promotion != nil {
+		return
+	}
+
+	status := &v1alpha1.PromotionStatus{Time: time.Now().Format(util.ISO8601UTC)}
+	if util.IsPromotionAborted(upgrade) {
+		status.Phase = v1alpha1.PromotionAborted
+		status.Reason = "the promotion is aborted"
+	} else if upgrade.Status.State == api.TaskCancelled || upgrade.Status.State == api.TaskDeadlineExceeded {
+		status.Phase = v1alpha1.PromotionHalted
+		status.Reason = fmt.Sprintf("the job finished %s", upgrade.Status.State)
+	} else if err := util.CheckPromotion(upgrade.Spec.Promotion, upgrade.Status.Status); err != nil {
+		status.Phase = v1alpha1.PromotionHalted
+		status.Reason = err.Error()
+	} else {
+		promoted := promotedJob(upgrade)
+		_, err = ndc.CrdClient.OperationsV1alpha1().NodeUpgradeJobs().Create(context.TODO(), promoted, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			status.Phase = v1alpha1.PromotionHalted
+			status.Reason = fmt.Sprintf("failed to create NodeUpgradeJob %s: %v", promoted.Name, err)
+		} else {
+			status.Phase = v1alpha1.PromotionPromoted
+			status.Job = promoted.Name
+		}
+	}
+	klog.Infof("promotion of NodeUpgradeJob %s to node group %s: %s %s",
+		name, upgrade.Spec.Promotion.NodeGroup, status.Phase, status.Reason)
+
+	jobStatus := upgrade.Status
+	jobStatus.Promotion = status
+	if err := patchStatus(upgrade, jobStatus, ndc.CrdClient); err != nil {
+		klog.Errorf("failed to record the promotion of NodeUpgradeJob %s: %v", name, err)
+	}
+}
+
+// promotedJob returns the NodeUpgradeJob which runs the upgrade on the nodes of the next node group.
+// The approval of the canary and the rerun of the job are not carried over, the promoted job waits for
+// its own canary to be approved.
+func promotedJob(upgrade *v1alpha1.NodeUpgradeJob) *v1alpha1.NodeUpgradeJob {
+	group := upgrade.Spec.Promotion.NodeGroup
+	labels := make(map[string]string, len(upgrade.Labels)+1)
+	for k, v := range upgrade.Labels {
+		labels[k] = v
+	}
+	labels[util.PromotedFromLabelKey] = upgrade.Name
+
+	spec := *upgrade.Spec.DeepCopy()
+	spec.NodeNames = nil
+	spec.LabelSelector = nil
+	spec.NodeGroups = []string{group}
+	spec.Promotion = nil
+	spec.Paused = false
+	spec.Rerun = 0
+	if spec.Canary != nil {
+		spec.Canary.Approve = false
+	}
+	return &v1alpha1.NodeUpgradeJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   fmt.Sprintf("%s-%s", upgrade.Name, group),
+			Labels: labels,
+		},
+		Spec: spec,
+	}
+}
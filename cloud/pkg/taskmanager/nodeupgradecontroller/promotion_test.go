@@ -0,0 +1,61 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeupgradecontroller
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+func TestPromotedJob(t *testing.T) {
+	upgrade := &v1alpha1.NodeUpgradeJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "upgrade", Labels: map[string]string{"team": "edge"}},
+		Spec: v1alpha1.NodeUpgradeJobSpec{
+			Version:       "v1.16.0",
+			NodeNames:     []string{"edge-1"},
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"site": "a"}},
+			NodeGroups:    []string{"canary"},
+			Canary:        &v1alpha1.CanarySpec{Nodes: 1, Approve: true},
+			Promotion:     &v1alpha1.PromotionSpec{NodeGroup: "production"},
+			Paused:        true,
+			Rerun:         2,
+		},
+	}
+
+	promoted := promotedJob(upgrade)
+	if promoted.Name != "upgrade-production" || promoted.Labels[util.PromotedFromLabelKey] != "upgrade" || promoted.Labels["team"] != "edge" {
+		t.Errorf("unexpected promoted job %s with labels %v", promoted.Name, promoted.Labels)
+	}
+	spec := promoted.Spec
+	if spec.NodeNames != nil || spec.LabelSelector != nil || !reflect.DeepEqual(spec.NodeGroups, []string{"production"}) {
+		t.Errorf("expected the promoted job to run on the next node group only, got %+v", spec)
+	}
+	if spec.Promotion != nil || spec.Paused || spec.Rerun != 0 || spec.Canary == nil || spec.Canary.Approve {
+		t.Errorf("expected the promotion, pause, rerun and approval not to be carried over, got %+v", spec)
+	}
+	if spec.Version != "v1.16.0" || spec.Canary.Nodes != 1 {
+		t.Errorf("expected the upgrade to be carried over, got %+v", spec)
+	}
+	if !upgrade.Spec.Canary.Approve || upgrade.Spec.Rerun != 2 {
+		t.Errorf("the promoted job must not modify the original one")
+	}
+}
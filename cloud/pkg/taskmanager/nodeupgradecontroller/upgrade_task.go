@@ -21,6 +21,8 @@ import (
 	"time"
 
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/config"
 	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
 	fsmapi "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
 	v1alpha12 "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
@@ -55,23 +57,42 @@ func updateUpgradeNodeState(id, nodeName string, state v1alpha12.State, event fs
 	task := v.(*v1alpha1.NodeUpgradeJob)
 	newTask := task.DeepCopy()
 	status := newTask.Status.DeepCopy()
-	for i, nodeStatus := range status.Status {
-		if nodeStatus.NodeName == nodeName {
-			status.Status[i] = v1alpha1.TaskStatus{
-				NodeName: nodeName,
-				State:    state,
-				Event:    event.Type,
-				Action:   event.Action,
-				Time:     time.Now().Format(util.ISO8601UTC),
-				Reason:   event.Msg,
-			}
-			break
+	nodeStatus := v1alpha1.TaskStatus{
+		NodeName: nodeName,
+		State:    state,
+		Event:    event.Type,
+		Action:   event.Action,
+		Time:     time.Now().Format(util.ISO8601UTC),
+		Reason:   event.Msg,
+
+		CommandDigest: event.CommandDigest,
+	}
+	if fsm.TaskFinish(state) {
+		nodeStatus.Environment = event.Environment
+		nodeStatus.Metadata = event.Metadata
+	}
+	persisted := nodeStatus
+	util.PruneNodeStatus(&persisted, config.Config.PrunedNodeStatusFields)
+	costs := make([]*v1alpha1.NodeCost, 0, len(status.Status))
+	for i := range status.Status {
+		if status.Status[i].NodeName == nodeName {
+			nodeStatus.Cost = util.AccountNodeCost(id, nodeName, util.NodeGroup(nodeName), status.Status[i].Cost, event)
+			persisted.Cost = nodeStatus.Cost
+			util.KeepCommandDigest(&persisted, &status.Status[i])
+			util.RecordNodeHistory(&persisted, &status.Status[i], config.Config.TransitionHistory)
+			status.Status[i] = persisted
 		}
+		costs = append(costs, status.Status[i].Cost)
 	}
+	status.Cost = util.AggregateCost(costs)
 	err := patchStatus(newTask, *status, client.GetCRDClient())
 	if err != nil {
 		return err
 	}
+	util.RecordNodeTransition(task, "NodeUpgradeJob", util.TaskUpgrade, nodeStatus)
+	if fsm.TaskFinish(state) {
+		util.RecordNodeCost(util.TaskUpgrade, nodeStatus.Cost)
+	}
 	return nil
 }
 
@@ -112,11 +133,27 @@ func updateUpgradeTaskState(id, _ string, state v1alpha12.State, event fsm.Event
 	status.Reason = event.Msg
 	status.State = state
 	status.Time = time.Now().Format(util.ISO8601UTC)
+	if fsm.TaskFinish(state) {
+		lister := informers.GetInformersManager().GetKubeInformerFactory().Core().V1().Nodes().Lister()
+		status.FailureClusters = util.FailureClusters(status.Status, lister, config.Config.FailureClusterLabels)
+		status.Outputs = upgradeOutputs(newTask, *status)
+	}
 
 	err := patchStatus(newTask, *status, client.GetCRDClient())
 
 	if err != nil {
 		return err
 	}
+	if len(status.FailureClusters) != 0 && !fsm.TaskFinish(task.Status.State) {
+		util.RecordFailureClusters(task, "NodeUpgradeJob", status.FailureClusters)
+	}
+	if fsm.TaskFinish(state) && !fsm.TaskFinish(task.Status.State) {
+		if !newTask.Spec.DryRun {
+			go util.LabelNodeCohorts(task.Name, newTask.Spec.Cohorts, status.Inputs, status.Status)
+		}
+		util.NotifyTaskFinished(newTask, "NodeUpgradeJob", newTask.Spec.Notifications, state, event.Msg, status.Status)
+	}
+	util.RecordTaskTransition(task, "NodeUpgradeJob", task.Status.State, state, event)
+	util.NotifyTaskState(util.TaskUpgrade, task.Name, state, event.Msg)
 	return nil
 }
@@ -0,0 +1,263 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeupgradecontroller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// runVersionMappings creates the NodeUpgradeJob of each version mapping of the job, the nodes are
+// assigned to the mappings once, when the jobs are created. The existing jobs of the mappings are rerun
+// if the job was rerun.
+func (ndc *NodeUpgradeController) runVersionMappings(upgrade *v1alpha1.NodeUpgradeJob) {
+	if len(upgrade.Status.VersionMappings) != 0 {
+		// the jobs of the mappings were created before cloudcore restarted
+		ndc.trackVersionMappings(upgrade.Name)
+		return
+	}
+
+	nodes, err := ndc.Informer.Core().V1().Nodes().Lister().List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list nodes for the version mappings of NodeUpgradeJob %s: %v", upgrade.Name, err)
+		return
+	}
+	var edgeNodes []v1.Node
+	for _, node := range nodes {
+		if util.IsEdgeNode(node) {
+			edgeNodes = append(edgeNodes, *node)
+		}
+	}
+	assigned, err := util.AssignVersionMappings(upgrade.Spec.VersionMappings, edgeNodes)
+	if err != nil {
+		status := *upgrade.Status.DeepCopy()
+		status.State = api.TaskFailed
+		status.Reason = err.Error()
+		status.Time = time.Now().Format(util.ISO8601UTC)
+		if err = patchStatus(upgrade.DeepCopy(), status, ndc.CrdClient); err != nil {
+			klog.Errorf("failed to fail NodeUpgradeJob %s: %v", upgrade.Name, err)
+		}
+		return
+	}
+
+	mappings := make([]v1alpha1.VersionMappingStatus, 0, len(upgrade.Spec.VersionMappings))
+	for i, mapping := range upgrade.Spec.VersionMappings {
+		status := v1alpha1.VersionMappingStatus{
+			Name:    mapping.Name,
+			Version: mapping.Version,
+			Nodes:   int32(len(assigned[i])),
+		}
+		if len(assigned[i]) == 0 {
+			status.Reason = "no node is selected by the version mapping"
+			mappings = append(mappings, status)
+			continue
+		}
+		job := versionMappingJob(upgrade, mapping, assigned[i])
+		status.Job = job.Name
+		if nodeCount, err := ndc.createVersionMappingJob(job); err != nil {
+			klog.Errorf("failed to run version mapping %s of NodeUpgradeJob %s: %v", mapping.Name, upgrade.Name, err)
+			status.State = api.TaskFailed
+			status.Reason = err.Error()
+		} else {
+			status.Nodes = nodeCount
+		}
+		mappings = append(mappings, status)
+	}
+	klog.Infof("NodeUpgradeJob %s runs %d version mappings", upgrade.Name, len(mappings))
+	ndc.recordVersionMappings(upgrade, mappings)
+}
+
+// createVersionMappingJob creates the NodeUpgradeJob of a version mapping and returns the number of its nodes,
+// the job is rerun instead if it already exists.
+func (ndc *NodeUpgradeController) createVersionMappingJob(job *v1alpha1.NodeUpgradeJob) (int32, error) {
+	jobs := ndc.CrdClient.OperationsV1alpha1().NodeUpgradeJobs()
+	_, err := jobs.Create(context.TODO(), job, metav1.CreateOptions{})
+	if err == nil {
+		return int32(len(job.Spec.NodeNames)), nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return 0, fmt.Errorf("failed to create NodeUpgradeJob %s: %v", job.Name, err)
+	}
+
+	existing, err := jobs.Get(context.TODO(), job.Name, metav1.GetOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get NodeUpgradeJob %s: %v", job.Name, err)
+	}
+	if existing.Labels[util.VersionMappingOfLabelKey] != job.Labels[util.VersionMappingOfLabelKey] {
+		return 0, fmt.Errorf("NodeUpgradeJob %s already exists", job.Name)
+	}
+	if existing.Spec.Rerun != job.Spec.Rerun {
+		existing.Spec.Rerun = job.Spec.Rerun
+		if existing, err = jobs.Update(context.TODO(), existing, metav1.UpdateOptions{}); err != nil {
+			return 0, fmt.Errorf("failed to rerun NodeUpgradeJob %s: %v", job.Name, err)
+		}
+		// the finished state of the job is not tracked until it reruns
+		ndc.TaskManager.CacheMap.Store(existing.Name, existing)
+	}
+	return int32(len(existing.Spec.NodeNames)), nil
+}
+
+// trackVersionMappings records the state of the jobs of the version mappings in the status of the job
+func (ndc *NodeUpgradeController) trackVersionMappings(name string) {
+	value, ok := ndc.TaskManager.CacheMap.Load(name)
+	if !ok {
+		return
+	}
+	upgrade := value.(*v1alpha1.NodeUpgradeJob)
+	if len(upgrade.Status.VersionMappings) == 0 || fsm.TaskFinish(upgrade.Status.State) {
+		return
+	}
+
+	mappings := append([]v1alpha1.VersionMappingStatus(nil), upgrade.Status.VersionMappings...)
+	for i := range mappings {
+		if mappings[i].Job == "" {
+			continue
+		}
+		value, ok := ndc.TaskManager.CacheMap.Load(mappings[i].Job)
+		if !ok {
+			continue
+		}
+		job := value.(*v1alpha1.NodeUpgradeJob)
+		if job.Labels[util.VersionMappingOfLabelKey] != name {
+			continue
+		}
+		if util.RerunRequested(job.Status.State, job.Spec.Rerun, job.Status.ObservedRerun) {
+			mappings[i].State, mappings[i].Reason = "", ""
+			continue
+		}
+		mappings[i].State, mappings[i].Reason = job.Status.State, job.Status.Reason
+	}
+	ndc.recordVersionMappings(upgrade, mappings)
+}
+
+// recordVersionMappings updates the status of the version mappings of the job, the job
+// finishes with the last of them.
+func (ndc *NodeUpgradeController) recordVersionMappings(upgrade *v1alpha1.NodeUpgradeJob, mappings []v1alpha1.VersionMappingStatus) {
+	status := *upgrade.Status.DeepCopy()
+	status.VersionMappings = mappings
+	status.State, status.Reason = util.VersionMappingsState(mappings)
+	if status.State == "" {
+		status.State = api.UpgradingState
+	}
+	if reflect.DeepEqual(status, upgrade.Status) {
+		return
+	}
+	if status.State != upgrade.Status.State {
+		klog.Infof("NodeUpgradeJob %s is %s: %s", upgrade.Name, status.State, status.Reason)
+	}
+	status.Time = time.Now().Format(util.ISO8601UTC)
+	if fsm.TaskFinish(status.State) {
+		status.Outputs = ndc.versionMappingOutputs(mappings)
+	}
+
+	recorded := upgrade.DeepCopy()
+	if err := patchStatus(recorded, status, ndc.CrdClient); err != nil {
+		klog.Errorf("failed to record the version mappings of NodeUpgradeJob %s: %v", upgrade.Name, err)
+		return
+	}
+	ndc.TaskManager.CacheMap.Store(recorded.Name, recorded)
+}
+
+// versionMappingOutputs returns the outputs of the finished job from the nodes of the jobs of its
+// version mappings
+func (ndc *NodeUpgradeController) versionMappingOutputs(mappings []v1alpha1.VersionMappingStatus) map[string]string {
+	var nodes []v1alpha1.TaskStatus
+	for _, mapping := range mappings {
+		if value, ok := ndc.TaskManager.CacheMap.Load(mapping.Job); ok && mapping.Job != "" {
+			nodes = append(nodes, value.(*v1alpha1.NodeUpgradeJob).Status.Status...)
+		}
+	}
+	return util.NodeOutputs(nodes)
+}
+
+// updateVersionMappingJobs pauses, resumes, applies, approves or cancels the jobs of the version mappings
+// along with the job
+func (ndc *NodeUpgradeController) updateVersionMappingJobs(old, upgrade *v1alpha1.NodeUpgradeJob) {
+	cancel := util.CancelRequested(old, upgrade)
+	update := old.Spec.Paused != upgrade.Spec.Paused || !reflect.DeepEqual(old.Spec.Apply, upgrade.Spec.Apply) ||
+		!reflect.DeepEqual(old.Spec.Canary, upgrade.Spec.Canary)
+	if fsm.TaskFinish(upgrade.Status.State) || (!cancel && !update) {
+		return
+	}
+	jobs := ndc.CrdClient.OperationsV1alpha1().NodeUpgradeJobs()
+	for _, mapping := range upgrade.Status.VersionMappings {
+		if mapping.Job == "" || fsm.TaskFinish(mapping.State) {
+			continue
+		}
+		job, err := jobs.Get(context.TODO(), mapping.Job, metav1.GetOptions{})
+		if err != nil {
+			klog.Errorf("failed to get NodeUpgradeJob %s of version mapping %s: %v", mapping.Job, mapping.Name, err)
+			continue
+		}
+		if cancel {
+			if job.Annotations == nil {
+				job.Annotations = map[string]string{}
+			}
+			job.Annotations[util.TaskCancelAnnotationKey] = upgrade.Annotations[util.TaskCancelAnnotationKey]
+		}
+		job.Spec.Paused = upgrade.Spec.Paused
+		job.Spec.Apply = upgrade.Spec.Apply.DeepCopy()
+		job.Spec.Canary = upgrade.Spec.Canary.DeepCopy()
+		if _, err = jobs.Update(context.TODO(), job, metav1.UpdateOptions{}); err != nil {
+			klog.Errorf("failed to update NodeUpgradeJob %s of version mapping %s: %v", mapping.Job, mapping.Name, err)
+		}
+	}
+}
+
+// versionMappingJob returns the NodeUpgradeJob which upgrades the nodes assigned to the version mapping
+func versionMappingJob(upgrade *v1alpha1.NodeUpgradeJob, mapping v1alpha1.VersionMapping, nodeNames []string) *v1alpha1.NodeUpgradeJob {
+	labels := make(map[string]string, len(upgrade.Labels)+1)
+	for k, v := range upgrade.Labels {
+		labels[k] = v
+	}
+	labels[util.VersionMappingOfLabelKey] = upgrade.Name
+
+	spec := *upgrade.Spec.DeepCopy()
+	spec.Version = mapping.Version
+	if mapping.Image != "" {
+		spec.Image = mapping.Image
+	}
+	spec.NodeNames = nodeNames
+	spec.LabelSelector = nil
+	spec.NodeGroups = nil
+	spec.VersionMappings = nil
+	// the inputs of the job are substituted in the spec already
+	spec.Inputs = nil
+	// the job of the mapping is deleted together with its parent
+	spec.TTLSecondsAfterFinished = nil
+	return &v1alpha1.NodeUpgradeJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            fmt.Sprintf("%s-%s", upgrade.Name, mapping.Name),
+			Labels:          labels,
+			OwnerReferences: []metav1.OwnerReference{*util.NewTaskOwnerReference(upgrade, "NodeUpgradeJob")},
+		},
+		Spec: spec,
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osupgradecontroller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/manager"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+)
+
+// defaultOSUpgradeTimeoutSeconds bounds the upgrade of each node if the job sets no timeout, it leaves
+// time for the download of the artifact and for the host to reboot
+const defaultOSUpgradeTimeoutSeconds = 3600
+
+// OSUpgradeController is the controller of OSUpgradeJobs
+type OSUpgradeController = controller.JobController[*v1alpha1.OSUpgradeJob, *v1alpha1.OSUpgradeJobList]
+
+// osUpgradeJobType is the type of OSUpgradeJobs handled by the controller
+var osUpgradeJobType = controller.JobType[*v1alpha1.OSUpgradeJob, *v1alpha1.OSUpgradeJobList]{
+	Name:          util.TaskOSUpgrade,
+	Kind:          "OSUpgradeJob",
+	Rule:          api.OSUpgradeRule,
+	StageSequence: api.OSUpgradeStageSequence,
+	Client: func(crdClient crdClientset.Interface) util.TypedTaskClient[*v1alpha1.OSUpgradeJob, *v1alpha1.OSUpgradeJobList] {
+		return crdClient.OperationsV1alpha1().OSUpgradeJobs()
+	},
+	Request: request,
+	PostCheck: func(job *v1alpha1.OSUpgradeJob) *v1alpha1.PostCheckSpec {
+		return job.Spec.PostCheck
+	},
+}
+
+func init() {
+	// the nodes are upgraded one at a time and verified from cloud, they succeed once Ready by default
+	controller.RegisterPipeline(util.TaskOSUpgrade, controller.Pipeline{
+		Stages:         []api.State{api.OSUpgradingState},
+		VerifiesNodes:  true,
+		TimeoutSeconds: defaultOSUpgradeTimeoutSeconds,
+		Concurrency:    1,
+		PostCheck:      &v1alpha1.PostCheckSpec{},
+	})
+	util.RegisterTaskObjects(util.TaskOSUpgrade, util.NewTypedTaskObjects(v1alpha1.Resource("osupgradejobs"),
+		osUpgradeJobType.Client,
+		func(meta metav1.ObjectMeta, spec v1alpha1.OSUpgradeJobSpec) *v1alpha1.OSUpgradeJob {
+			return &v1alpha1.OSUpgradeJob{ObjectMeta: meta, Spec: spec}
+		},
+		func(job *v1alpha1.OSUpgradeJob) util.TaskObjectStatus {
+			return util.JobObjectStatus(job.Status.CommonJobStatus, job.Status)
+		},
+		func(job *v1alpha1.OSUpgradeJob) *int32 {
+			return job.Spec.TTLSecondsAfterFinished
+		}))
+}
+
+func NewOSUpgradeController(messageChan chan util.TaskMessage) (*OSUpgradeController, error) {
+	cache, err := manager.NewTaskCache(
+		informers.GetInformersManager().GetKubeEdgeInformerFactory().Operations().V1alpha1().OSUpgradeJobs().Informer())
+	if err != nil {
+		klog.Warningf("Create OS upgrade controller failed with error: %s", err)
+		return nil, err
+	}
+	return NewController(controller.NewBaseController(util.TaskOSUpgrade, messageChan, cache,
+		informers.GetInformersManager().GetKubeInformerFactory(), client.GetKubeClient(), client.GetCRDClient())), nil
+}
+
+// NewController returns the controller of OSUpgradeJobs with the clients and the task cache of the base
+func NewController(base *controller.BaseController) *OSUpgradeController {
+	return controller.NewJobController(osUpgradeJobType, base)
+}
+
+// request returns the message requesting the edge nodes to apply the artifact with the hook, each
+// node is verified from cloud once it is back, with the post check of the job or by waiting for the
+// node to be Ready
+func request(job *v1alpha1.OSUpgradeJob) interface{} {
+	return commontypes.OSUpgradeJobRequest{
+		ArtifactURL:    job.Spec.Artifact.URL,
+		ArtifactSHA256: job.Spec.Artifact.SHA256,
+		Version:        job.Spec.Artifact.Version,
+		Hook:           job.Spec.Hook.Name,
+		HookSHA256:     job.Spec.Hook.SHA256,
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restorecontroller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/manager"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+)
+
+// RestoreController is the controller of RestoreJobs
+type RestoreController = controller.JobController[*v1alpha1.RestoreJob, *v1alpha1.RestoreJobList]
+
+// restoreJobType is the type of RestoreJobs handled by the controller
+var restoreJobType = controller.JobType[*v1alpha1.RestoreJob, *v1alpha1.RestoreJobList]{
+	Name:          util.TaskRestore,
+	Kind:          "RestoreJob",
+	Rule:          api.RestoreRule,
+	StageSequence: api.RestoreStageSequence,
+	Client: func(crdClient crdClientset.Interface) util.TypedTaskClient[*v1alpha1.RestoreJob, *v1alpha1.RestoreJobList] {
+		return crdClient.OperationsV1alpha1().RestoreJobs()
+	},
+	Request: request,
+}
+
+func init() {
+	controller.RegisterPipeline(util.TaskRestore, controller.Pipeline{
+		Stages:      []api.State{api.RestoringState},
+		Concurrency: 1,
+	})
+	util.RegisterTaskObjects(util.TaskRestore, util.NewTypedTaskObjects(v1alpha1.Resource("restorejobs"),
+		restoreJobType.Client,
+		func(meta metav1.ObjectMeta, spec v1alpha1.RestoreJobSpec) *v1alpha1.RestoreJob {
+			return &v1alpha1.RestoreJob{ObjectMeta: meta, Spec: spec}
+		},
+		func(job *v1alpha1.RestoreJob) util.TaskObjectStatus {
+			return util.JobObjectStatus(job.Status.CommonJobStatus, job.Status)
+		},
+		func(job *v1alpha1.RestoreJob) *int32 {
+			return job.Spec.TTLSecondsAfterFinished
+		}))
+}
+
+func NewRestoreController(messageChan chan util.TaskMessage) (*RestoreController, error) {
+	cache, err := manager.NewTaskCache(
+		informers.GetInformersManager().GetKubeEdgeInformerFactory().Operations().V1alpha1().RestoreJobs().Informer())
+	if err != nil {
+		klog.Warningf("Create restore controller failed with error: %s", err)
+		return nil, err
+	}
+	return NewController(controller.NewBaseController(util.TaskRestore, messageChan, cache,
+		informers.GetInformersManager().GetKubeInformerFactory(), client.GetKubeClient(), client.GetCRDClient())), nil
+}
+
+// NewController returns the controller of RestoreJobs with the clients and the task cache of the base
+func NewController(base *controller.BaseController) *RestoreController {
+	return controller.NewJobController(restoreJobType, base)
+}
+
+// request returns the message requesting the edge nodes to restore the backup of the BackupJob and
+// restart edgecore
+func request(job *v1alpha1.RestoreJob) interface{} {
+	return commontypes.RestoreJobRequest{BackupJob: job.Spec.BackupJobName, Location: job.Spec.Location}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sdk is the entry for implementing task types out of tree, e.g. a proprietary
+// firmware updater, without forking KubeEdge. A task type consists of four parts:
+//
+// API: a cluster scoped CRD in its own API group whose status holds a []TaskStatus of
+// operations.kubeedge.io/v1alpha1. The clientset, informers and deepcopy functions can be
+// generated with hack/generate-groups.sh the same as the in-tree operations API.
+//
+// FSM: the stages of the task type declared in a StageGraph, in order, with the event the
+// nodes report once they finish each stage. The stages may be optional, internal to cloud
+// such as the verification of the nodes, or move the failed nodes to another stage. The rule
+// of the node state machine, keyed by "State/Event/Action" with the next state as value, and
+// the stage sequence which maps each state to the state that completes its stage are derived
+// from it, Rules add the transitions which do not follow the stages. See UpgradeStages in
+// pkg/apis/fsm/v1alpha1 for reference. NewNodeFSM builds the state machine of a node from them.
+//
+// Cloud controller: an implementation of Controller, usually embedding the BaseController
+// returned by NewBaseController. It watches the CRD, sends a TaskMessage on the message
+// channel for each task to run, and persists the node states reported by the executors.
+// Register its factory with RegisterTaskType in an init function, along with the TaskObjects
+// which read, create and patch the task objects and extract their status, and import the package
+// in the cloudcore build, the controller is created when TaskManager starts, and the
+// messages reported by edge nodes for the task type are routed to it.
+//
+// Pipeline: the stages of the task type, in order, and the defaults its tasks run with when
+// they do not set them, i.e. the check items, the timeouts, the concurrency, the failure
+// tolerance and the post check. Register it with RegisterPipeline along with the controller
+// factory. Cluster admins override the defaults with a TaskTypeConfig named after the task
+// type, without rebuilding cloudcore.
+//
+// Edge executor: an implementation of taskexecutor.Executor in edge/pkg/edgehub/task/taskexecutor,
+// usually built with taskexecutor.NewBaseExecutor from one method per state, taking
+// taskexecutor.CommonMethods for the Init and Checking states. Register it with
+// taskexecutor.Register under the same task type in an init function and import the
+// package in the edgecore build. The event returned by each method drives the FSM on cloud.
+//
+// Tests: the Responder of package fakeedge answers the requests of the task type on behalf of
+// edge nodes with scripted outcomes and latencies, so the cloud controller and the FSM can be
+// tested without a real edge node.
+package sdk
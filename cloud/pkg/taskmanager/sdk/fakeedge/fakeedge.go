@@ -0,0 +1,260 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fakeedge is a fake edge agent for the integration tests of task types. It takes
+// the place of CloudHub on the beehive context, answers the requests TaskManager sends to
+// edge nodes with scripted outcomes after scripted latencies, and forwards the receipts and
+// results to TaskManager the same way CloudHub forwards the messages of real edge nodes.
+//
+// A test initializes the beehive channel context with InitContext, starts TaskManager and a
+// Responder, scripts the outcomes of the nodes with SetOutcome and creates the task objects.
+package fakeedge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/common"
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/common/types"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+)
+
+// DefaultEvents are the events the in-tree edge executors report for each state,
+// the state itself is reported as event for the states not listed.
+var DefaultEvents = map[api.State]string{
+	api.TaskInit:         "Init",
+	api.TaskChecking:     "Check",
+	api.BackingUpState:   "Backup",
+	api.UpgradingState:   "Upgrade",
+	api.RollingBackState: "Rollback",
+	api.RevertingState:   "Rollback",
+	api.PullingState:     "Pull",
+	api.TaskCancelling:   api.EventCancel,
+}
+
+// Outcome is the scripted answer of an edge node to the request of a state
+type Outcome struct {
+	// Event is the type of the reported event, DefaultEvents is used if it is empty
+	Event string
+	// Action is the reported action, ActionSuccess is used if it is empty
+	Action api.Action
+	// Reason is reported as the message of the event
+	Reason string
+	// Latency is the time the node takes before it reports the result
+	Latency time.Duration
+	// BytesDownloaded is reported as the size of the images pulled in the state
+	BytesDownloaded int64
+	// Drop discards the request without receipt nor result, as if the node was unreachable
+	Drop bool
+	// NoResult sends the receipt of the request but no result, as if the node hung
+	NoResult bool
+}
+
+// Request is a task request received for an edge node
+type Request struct {
+	NodeName string
+	types.NodeTaskRequest
+}
+
+// Responder answers the task requests for the edge nodes
+type Responder struct {
+	sync.Mutex
+	// outcomes are keyed by node name/state, an empty node name or state matches all
+	outcomes map[string]Outcome
+	requests []Request
+	purged   []string
+}
+
+// NewResponder returns a responder which answers all requests successfully without latency
+func NewResponder() *Responder {
+	return &Responder{outcomes: map[string]Outcome{}}
+}
+
+// InitContext initializes the beehive channel context with the TaskManager and CloudHub modules,
+// it is called once by a test before TaskManager and the responder are started.
+func InitContext() {
+	beehiveContext.InitContext([]string{common.MsgCtxTypeChannel})
+	for _, module := range []string{modules.TaskManagerModuleName, modules.CloudHubModuleName} {
+		beehiveContext.AddModule(&common.ModuleInfo{
+			ModuleName: module,
+			ModuleType: common.MsgCtxTypeChannel,
+		})
+	}
+	beehiveContext.AddModuleGroup(modules.TaskManagerModuleName, modules.TaskManagerModuleGroup)
+	beehiveContext.AddModuleGroup(modules.CloudHubModuleName, modules.CloudHubModuleGroup)
+}
+
+// SetOutcome scripts the outcome of the requests of the state on the node. An empty node name
+// applies to all nodes and an empty state to all states, the most specific outcome is used.
+func (r *Responder) SetOutcome(nodeName string, state api.State, outcome Outcome) {
+	r.Lock()
+	defer r.Unlock()
+	r.outcomes[nodeName+"/"+string(state)] = outcome
+}
+
+// Requests returns the requests received so far
+func (r *Responder) Requests() []Request {
+	r.Lock()
+	defer r.Unlock()
+	return append([]Request(nil), r.requests...)
+}
+
+// Purged returns the nodes which received a purge request so far
+func (r *Responder) Purged() []string {
+	r.Lock()
+	defer r.Unlock()
+	return append([]string(nil), r.purged...)
+}
+
+// Start answers the messages sent to CloudHub until ctx is done
+func (r *Responder) Start(ctx context.Context) {
+	go func() {
+		for {
+			msg, err := beehiveContext.Receive(modules.CloudHubModuleName)
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+			if err != nil {
+				klog.Warningf("fake edge failed to receive message: %v", err)
+				continue
+			}
+			if err = r.handle(ctx, msg); err != nil {
+				klog.Warningf("fake edge failed to handle message %s: %v", msg.GetID(), err)
+			}
+		}
+	}()
+}
+
+func (r *Responder) handle(ctx context.Context, msg model.Message) error {
+	if msg.GetSource() != modules.TaskManagerModuleName {
+		return nil
+	}
+	nodeName := util.GetNodeName(msg.GetResource())
+	if msg.GetOperation() == util.TaskPurge {
+		r.Lock()
+		r.purged = append(r.purged, nodeName)
+		r.Unlock()
+		return nil
+	}
+	data, err := msg.GetContentData()
+	if err != nil {
+		return fmt.Errorf("failed to get content data: %v", err)
+	}
+	if msg.GetOperation() == util.TaskProbe {
+		return r.answerProbe(nodeName, data)
+	}
+	req := types.NodeTaskRequest{}
+	if err = json.Unmarshal(data, &req); err != nil {
+		return fmt.Errorf("unmarshal failed: %v", err)
+	}
+
+	r.Lock()
+	r.requests = append(r.requests, Request{NodeName: nodeName, NodeTaskRequest: req})
+	outcome := r.outcome(nodeName, api.State(req.State))
+	r.Unlock()
+	if outcome.Drop {
+		return nil
+	}
+
+	report(req.TaskID, nodeName, util.TaskAccept, types.NodeTaskReceipt{
+		NodeName: nodeName,
+		Type:     req.Type,
+		State:    req.State,
+	})
+	if outcome.NoResult {
+		return nil
+	}
+	resp := types.NodeTaskResponse{
+		NodeName:        nodeName,
+		Event:           outcome.Event,
+		Action:          outcome.Action,
+		Reason:          outcome.Reason,
+		BytesDownloaded: outcome.BytesDownloaded,
+		// echo the custom metadata of the task like edgecore does
+		Metadata: req.Metadata,
+	}
+	if resp.Event == "" {
+		resp.Event = defaultEvent(api.State(req.State))
+	}
+	if resp.Action == "" {
+		resp.Action = api.ActionSuccess
+	}
+	if outcome.Latency == 0 {
+		report(req.TaskID, nodeName, req.Type, resp)
+		return nil
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-time.After(outcome.Latency):
+			report(req.TaskID, nodeName, req.Type, resp)
+		}
+	}()
+	return nil
+}
+
+// answerProbe answers the preflight probe right away, unless the requests of all states are dropped on the node
+func (r *Responder) answerProbe(nodeName string, data []byte) error {
+	probe := types.NodeTaskProbe{}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return fmt.Errorf("unmarshal failed: %v", err)
+	}
+	r.Lock()
+	outcome := r.outcome(nodeName, "")
+	r.Unlock()
+	if outcome.Drop {
+		return nil
+	}
+	probe.NodeName = nodeName
+	report(probe.TaskID, nodeName, util.TaskProbe, probe)
+	return nil
+}
+
+// outcome returns the most specific outcome scripted for the state on the node
+func (r *Responder) outcome(nodeName string, state api.State) Outcome {
+	for _, key := range []string{nodeName + "/" + string(state), nodeName + "/", "/" + string(state), "/"} {
+		if outcome, ok := r.outcomes[key]; ok {
+			return outcome
+		}
+	}
+	return Outcome{}
+}
+
+func defaultEvent(state api.State) string {
+	if event, ok := DefaultEvents[state]; ok {
+		return event
+	}
+	return string(state)
+}
+
+// report forwards a message of the edge node to TaskManager as CloudHub does, it is sent to the
+// module instead of the group to keep the receipt ahead of the result.
+func report(taskID, nodeName, operation string, body interface{}) {
+	msg := model.NewMessage("").SetRoute(modules.CloudHubModuleName, modules.CloudHubModuleGroup).
+		SetResourceOperation(fmt.Sprintf("task/%s/node/%s", taskID, nodeName), operation).FillBody(body)
+	beehiveContext.Send(modules.TaskManagerModuleName, *msg)
+}
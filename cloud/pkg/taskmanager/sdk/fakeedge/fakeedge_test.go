@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakeedge
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/common/types"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+)
+
+func sendRequest(nodeName string, state api.State) {
+	msg := model.NewMessage("").BuildRouter(modules.TaskManagerModuleName, modules.TaskManagerModuleGroup,
+		"upgrade/upgrade-1/node/"+nodeName, util.TaskUpgrade).
+		FillBody(types.NodeTaskRequest{TaskID: "upgrade-1", Type: util.TaskUpgrade, State: string(state)})
+	beehiveContext.Send(modules.CloudHubModuleName, *msg)
+}
+
+func receive(t *testing.T) model.Message {
+	t.Helper()
+	msg, err := beehiveContext.Receive(modules.TaskManagerModuleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func TestResponder(t *testing.T) {
+	InitContext()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	responder := NewResponder()
+	responder.SetOutcome("", "", Outcome{Latency: 10 * time.Millisecond})
+	responder.SetOutcome("edge-2", api.TaskChecking, Outcome{Action: api.ActionFailure, Reason: "disk full"})
+	responder.Start(ctx)
+
+	sendRequest("edge-1", api.TaskChecking)
+	if msg := receive(t); msg.GetOperation() != util.TaskAccept || util.GetNodeName(msg.GetResource()) != "edge-1" {
+		t.Fatalf("expected receipt of edge-1, got %s %s", msg.GetOperation(), msg.GetResource())
+	}
+	start := time.Now()
+	msg := receive(t)
+	if elapsed := time.Since(start); elapsed < 5*time.Millisecond {
+		t.Errorf("expected the result after the latency, got it after %s", elapsed)
+	}
+	resp := types.NodeTaskResponse{}
+	data, _ := msg.GetContentData()
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if msg.GetOperation() != util.TaskUpgrade || util.GetTaskID(msg.GetResource()) != "upgrade-1" ||
+		resp.Event != "Check" || resp.Action != api.ActionSuccess {
+		t.Errorf("unexpected result %s %s %+v", msg.GetOperation(), msg.GetResource(), resp)
+	}
+
+	sendRequest("edge-2", api.TaskChecking)
+	receive(t)
+	msg = receive(t)
+	data, _ = msg.GetContentData()
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.NodeName != "edge-2" || resp.Action != api.ActionFailure || resp.Reason != "disk full" {
+		t.Errorf("unexpected result %+v", resp)
+	}
+
+	if requests := responder.Requests(); len(requests) != 2 || requests[1].NodeName != "edge-2" ||
+		requests[1].State != string(api.TaskChecking) {
+		t.Errorf("unexpected requests %+v", requests)
+	}
+}
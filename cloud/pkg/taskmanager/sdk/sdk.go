@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sdk
+
+import (
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/manager"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+type (
+	// Controller is the cloud side of a task type
+	Controller = controller.Controller
+	// BaseController implements the common methods of Controller
+	BaseController = controller.BaseController
+	// ControllerFactory creates the Controller of a task type
+	ControllerFactory = controller.Factory
+	// TaskMessage is sent by a Controller to run or stop a task
+	TaskMessage = util.TaskMessage
+	// TaskObjects are the hooks of a task type on its task objects, e.g. how to get their status
+	TaskObjects = util.TaskObjects
+	// TaskObjectStatus is the status of a task object
+	TaskObjectStatus = util.TaskObjectStatus
+	// TaskCache caches the task objects of a task type
+	TaskCache = manager.TaskCache
+	// Event is reported by edge executors to drive the node FSM
+	Event = fsm.Event
+	// Pipeline is the default stages, checks and timeouts of a task type
+	Pipeline = controller.Pipeline
+	// StageGraph declares the stages of a task type, the rule and the stage sequence of its FSM are
+	// derived from it
+	StageGraph = api.StageGraph
+	// Stage is a stage of a StageGraph
+	Stage = api.Stage
+)
+
+// RegisterTaskType registers the controller factory of the task type and its hooks on the task
+// objects. Through the hooks the task objects of the type are served by the task status API,
+// deleted once their ttlSecondsAfterFinished expires, and the nodes they lock are released once
+// they are deleted. The rest of their lifecycle is up to the controller of the type: it sets the
+// deadline, the failure tolerance and the rerun of the tasks in the TaskMessage and records their
+// conditions, and the task credential webhook only validates the task objects of the operations
+// group. The hooks are usually built with util.NewTypedTaskObjects from the generated client of
+// the task CRD.
+func RegisterTaskType(name string, factory ControllerFactory, objects TaskObjects) {
+	controller.RegisterFactory(name, factory)
+	util.RegisterTaskObjects(name, objects)
+}
+
+// RegisterPipeline registers the default pipeline of the task type, the tasks of the type run with
+// its defaults for the fields they do not set
+func RegisterPipeline(name string, pipeline Pipeline) {
+	controller.RegisterPipeline(name, pipeline)
+}
+
+// NewTaskCache returns the cache of task objects, it is filled by the informer of the task CRD
+func NewTaskCache(informer cache.SharedIndexInformer) (*TaskCache, error) {
+	return manager.NewTaskCache(informer)
+}
+
+// NewBaseController returns a BaseController of the task type with the clients of cloudcore
+func NewBaseController(name string, messageChan chan TaskMessage, taskCache *TaskCache) *BaseController {
+	return controller.NewBaseController(name, messageChan, taskCache,
+		informers.GetInformersManager().GetKubeInformerFactory(), client.GetKubeClient(), client.GetCRDClient())
+}
+
+// NewNodeFSM returns the state machine of a node in the task, current returns the state of the
+// node and update persists the next state with the event that caused the transition. The rule and
+// the stage sequence are usually derived from the StageGraph of the task type.
+func NewNodeFSM(taskName, nodeName string, rule map[string]api.State, stageSequence map[api.State]api.State,
+	current func(id, nodeName string) (api.State, error),
+	update func(id, nodeName string, state api.State, event fsm.Event) error) *fsm.FSM {
+	nodeFSM := &fsm.FSM{}
+	return nodeFSM.NodeName(nodeName).ID(taskName).Guard(rule).StageSequence(stageSequence).CurrentFunc(current).UpdateFunc(update)
+}
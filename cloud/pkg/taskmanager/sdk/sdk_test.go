@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+	"github.com/kubeedge/kubeedge/pkg/client/clientset/versioned/fake"
+)
+
+// firmwareTask is the fake task type registered out of tree, its tasks are stored as ConfigUpdateJobs
+const firmwareTask = "firmwareupdate"
+
+type firmwareController struct {
+	*BaseController
+}
+
+// firmwareObjects are the hooks of the fake task type on its task objects
+func firmwareObjects() TaskObjects {
+	return util.NewTypedTaskObjects(v1alpha1.Resource("configupdatejobs"),
+		func(crdClient crdClientset.Interface) util.TypedTaskClient[*v1alpha1.ConfigUpdateJob, *v1alpha1.ConfigUpdateJobList] {
+			return crdClient.OperationsV1alpha1().ConfigUpdateJobs()
+		},
+		func(meta metav1.ObjectMeta, spec v1alpha1.ConfigUpdateJobSpec) *v1alpha1.ConfigUpdateJob {
+			return &v1alpha1.ConfigUpdateJob{ObjectMeta: meta, Spec: spec}
+		},
+		func(job *v1alpha1.ConfigUpdateJob) TaskObjectStatus {
+			return util.JobObjectStatus(job.Status.CommonJobStatus, job.Status)
+		},
+		func(job *v1alpha1.ConfigUpdateJob) *int32 {
+			return job.Spec.TTLSecondsAfterFinished
+		})
+}
+
+func TestRegisterTaskType(t *testing.T) {
+	messageChan := make(chan TaskMessage, 1)
+	RegisterTaskType(firmwareTask, func(messageChan chan TaskMessage) (Controller, error) {
+		return &firmwareController{controller.NewBaseController(firmwareTask, messageChan, nil, nil, nil, nil)}, nil
+	}, firmwareObjects())
+
+	// the controller is created along with the in-tree ones and the reports of the nodes are routed to it
+	if err := controller.RegisterFactories(messageChan); err != nil {
+		t.Fatalf("failed to create the controllers: %v", err)
+	}
+	c, err := controller.GetController(firmwareTask)
+	if err != nil {
+		t.Fatalf("expected the controller of the task type to be registered: %v", err)
+	}
+	if c.Name() != firmwareTask {
+		t.Errorf("expected the controller of %s, got %s", firmwareTask, c.Name())
+	}
+	if !controller.IsNodeMessage(firmwareTask) {
+		t.Errorf("expected the reports of the nodes of %s to be routed to its controller", firmwareTask)
+	}
+
+	// the parts shared by all task types find the task objects through the registered hooks
+	registered := false
+	for _, taskType := range util.TaskObjectTypes() {
+		registered = registered || taskType == firmwareTask
+	}
+	objects, ok := util.GetTaskObjects(firmwareTask)
+	if !registered || !ok {
+		t.Fatalf("expected the hooks of %s to be registered", firmwareTask)
+	}
+
+	ttl := int32(60)
+	spec := &v1alpha1.ConfigUpdateJobSpec{CommonJobSpec: v1alpha1.CommonJobSpec{TTLSecondsAfterFinished: &ttl}}
+	task, err := objects.NewTask(metav1.ObjectMeta{Name: "firmware-1"}, spec)
+	if err != nil {
+		t.Fatalf("failed to build the task object: %v", err)
+	}
+	if _, err = objects.NewTask(metav1.ObjectMeta{Name: "firmware-2"}, v1alpha1.NodeUpgradeJobSpec{}); err == nil {
+		t.Errorf("expected a spec of another task type to be rejected")
+	}
+
+	taskClient := objects.Client(fake.NewSimpleClientset())
+	if _, err = taskClient.Create(context.TODO(), task); err != nil {
+		t.Fatalf("failed to create the task object: %v", err)
+	}
+	if err = taskClient.Patch(context.TODO(), "firmware-1", []byte(`{"status":{"state":"Successful","reason":"flashed"}}`)); err != nil {
+		t.Fatalf("failed to patch the task object: %v", err)
+	}
+	object, err := taskClient.Get(context.TODO(), "firmware-1")
+	if err != nil {
+		t.Fatalf("failed to get the task object: %v", err)
+	}
+	if status := objects.Status(object); status.State != api.TaskSuccessful || status.Reason != "flashed" {
+		t.Errorf("expected the status of the task object, got %+v", status)
+	}
+	if got := objects.TTLSecondsAfterFinished(object); got == nil || *got != ttl {
+		t.Errorf("expected ttlSecondsAfterFinished %d, got %v", ttl, got)
+	}
+
+	if err = taskClient.Delete(context.TODO(), "firmware-1", object.GetResourceVersion()); err != nil {
+		t.Fatalf("failed to delete the task object: %v", err)
+	}
+	if _, err = taskClient.Get(context.TODO(), "firmware-1"); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the task object to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,288 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package supportbundlecontroller
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/common/constants"
+	cloudcorev1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+	"github.com/kubeedge/kubeedge/pkg/util/supportbundle"
+	"github.com/kubeedge/kubeedge/pkg/version"
+)
+
+// cloudCoreSelector selects the pods of cloudcore whose logs are collected
+const cloudCoreSelector = "kubeedge=cloudcore"
+
+// ArchivePath returns the path of the archive of the SupportBundleJob
+func ArchivePath(name string) string {
+	return filepath.Join(config.Config.SupportBundleDir, name+".tar.gz")
+}
+
+// nodeBundleDir returns the directory the bundles of the nodes are stored in
+func nodeBundleDir(name string) string {
+	return filepath.Join(config.Config.SupportBundleDir, name)
+}
+
+// NodeBundlePath returns the path of the bundle collected from the node by the SupportBundleJob
+func NodeBundlePath(name, nodeName string) string {
+	return filepath.Join(nodeBundleDir(name), nodeName+".tar.gz")
+}
+
+// saveNodeBundle stores the base64 encoded bundle reported by the node
+func saveNodeBundle(name, nodeName, encoded string) error {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("failed to decode bundle: %v", err)
+	}
+	dir := nodeBundleDir(name)
+	if err = os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+	return os.WriteFile(NodeBundlePath(name, nodeName), data, 0600)
+}
+
+// localArtifact returns the artifact locating the stored bundle of the node on this cloudcore, the
+// artifact is pointed to the object store once the bundle is uploaded
+func localArtifact(name, nodeName string) (v1alpha1.NodeArtifact, error) {
+	artifact := v1alpha1.NodeArtifact{
+		NodeName: nodeName,
+		URL: strings.NewReplacer("{taskType}", util.TaskSupportBundle, "{taskID}", name, "{nodeID}", nodeName).
+			Replace(constants.DefaultTaskNodeBundleURL),
+	}
+	info, err := os.Stat(NodeBundlePath(name, nodeName))
+	if err != nil {
+		return artifact, err
+	}
+	artifact.Size = info.Size()
+	return artifact, nil
+}
+
+// uploadBundle streams the stored bundle of the node to <url>/<job>/<node>.tar.gz and returns the
+// URL of the object
+func uploadBundle(upload *cloudcorev1alpha1.TaskManagerSupportBundleUpload, name, nodeName string) (string, error) {
+	file, err := os.Open(NodeBundlePath(name, nodeName))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf("%s/%s/%s.tar.gz", strings.TrimSuffix(upload.URL, "/"), name, nodeName)
+	req, err := http.NewRequest(http.MethodPut, url, file)
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Content-Type", "application/gzip")
+	for key, value := range upload.Headers {
+		req.Header.Set(key, value)
+	}
+	timeout := time.Duration(upload.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = constants.DefaultSupportBundleUploadTimeout * time.Second
+	}
+	resp, err := (&http.Client{Timeout: timeout}).Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return "", fmt.Errorf("object store responded %s", resp.Status)
+	}
+	return url, nil
+}
+
+// removeBundle removes the archive and the node bundles of the job
+func removeBundle(name string) error {
+	if err := os.RemoveAll(nodeBundleDir(name)); err != nil {
+		return err
+	}
+	if err := os.Remove(ArchivePath(name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// packageBundle archives the bundle of cloudcore, the bundles of the nodes and the job itself
+// into a single archive and returns its path. The node bundles are kept so that they can be
+// downloaded on their own until the job is deleted.
+func packageBundle(job *v1alpha1.SupportBundleJob) (string, error) {
+	archive := ArchivePath(job.Name)
+	if err := os.MkdirAll(filepath.Dir(archive), 0750); err != nil {
+		return "", err
+	}
+	tmp := archive + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp)
+
+	w := supportbundle.NewWriter(file)
+	err = writeBundle(w, job, client.GetKubeClient(), client.GetCRDClient())
+	if err == nil {
+		err = w.Close()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+	if err = os.Rename(tmp, archive); err != nil {
+		return "", err
+	}
+	return archive, nil
+}
+
+func writeBundle(w *supportbundle.Writer, job *v1alpha1.SupportBundleJob, kubeClient kubernetes.Interface, crdClient crdClientset.Interface) error {
+	if err := w.AddJSON("job.json", job); err != nil {
+		return err
+	}
+	if err := w.AddJSON("cloudcore/version.json", version.Get()); err != nil {
+		return err
+	}
+	if err := w.Add("cloudcore/cloudcore.yaml", cloudCoreConfig()); err != nil {
+		return err
+	}
+	logLines := int64(job.Spec.LogLines)
+	if logLines == 0 {
+		logLines = defaultLogLines
+	}
+	for name, logs := range cloudCoreLogs(kubeClient, logLines) {
+		if err := w.Add(path.Join("cloudcore", "logs", name+".log"), logs); err != nil {
+			return err
+		}
+	}
+	if err := w.AddJSON("cloudcore/tasks.json", taskHistory(crdClient)); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(nodeBundleDir(job.Name))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(nodeBundleDir(job.Name), entry.Name()))
+		if err != nil {
+			return err
+		}
+		if err = w.Add(path.Join("nodes", entry.Name()), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cloudCoreConfig returns the redacted config of cloudcore, or why it is unavailable
+func cloudCoreConfig() []byte {
+	data, err := os.ReadFile(path.Join(constants.DefaultConfigDir, "cloudcore.yaml"))
+	if err == nil {
+		data, err = supportbundle.RedactConfig(data)
+	}
+	if err != nil {
+		return []byte(fmt.Sprintf("# failed to read cloudcore config: %v\n", err))
+	}
+	return data
+}
+
+// cloudCoreLogs returns the last log lines of each cloudcore pod, or why they are unavailable
+func cloudCoreLogs(kubeClient kubernetes.Interface, lines int64) map[string][]byte {
+	pods, err := kubeClient.CoreV1().Pods(constants.SystemNamespace).List(context.TODO(), metav1.ListOptions{LabelSelector: cloudCoreSelector})
+	if err != nil {
+		return map[string][]byte{"cloudcore": []byte(fmt.Sprintf("failed to list cloudcore pods: %v\n", err))}
+	}
+	logs := make(map[string][]byte, len(pods.Items))
+	for _, pod := range pods.Items {
+		data, err := kubeClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{TailLines: &lines}).DoRaw(context.TODO())
+		if err != nil {
+			data = []byte(fmt.Sprintf("failed to get logs of pod %s: %v\n", pod.Name, err))
+		}
+		logs[pod.Name] = supportbundle.RedactText(data)
+	}
+	return logs
+}
+
+// taskRecord summarizes a task for the task history of the bundle
+type taskRecord struct {
+	Kind   string                `json:"kind"`
+	Name   string                `json:"name"`
+	State  api.State             `json:"state,omitempty"`
+	Reason string                `json:"reason,omitempty"`
+	Time   string                `json:"time,omitempty"`
+	Nodes  []v1alpha1.TaskStatus `json:"nodes,omitempty"`
+}
+
+// taskHistory returns the NodeUpgradeJobs and ImagePrePullJobs known to the cluster
+func taskHistory(crdClient crdClientset.Interface) []taskRecord {
+	var history []taskRecord
+	operations := crdClient.OperationsV1alpha1()
+	upgrades, err := operations.NodeUpgradeJobs().List(context.TODO(), metav1.ListOptions{})
+	if err == nil {
+		for _, job := range upgrades.Items {
+			history = append(history, taskRecord{
+				Kind:   "NodeUpgradeJob",
+				Name:   job.Name,
+				State:  job.Status.State,
+				Reason: job.Status.Reason,
+				Time:   job.Status.Time,
+				Nodes:  job.Status.Status,
+			})
+		}
+	}
+	prePulls, err := operations.ImagePrePullJobs().List(context.TODO(), metav1.ListOptions{})
+	if err == nil {
+		for _, job := range prePulls.Items {
+			record := taskRecord{
+				Kind:   "ImagePrePullJob",
+				Name:   job.Name,
+				State:  job.Status.State,
+				Reason: job.Status.Reason,
+				Time:   job.Status.Time,
+			}
+			for _, status := range job.Status.Status {
+				if status.TaskStatus != nil {
+					record.Nodes = append(record.Nodes, *status.TaskStatus)
+				}
+			}
+			history = append(history, record)
+		}
+	}
+	return history
+}
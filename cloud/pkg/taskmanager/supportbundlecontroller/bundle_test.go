@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package supportbundlecontroller
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/config"
+	cloudcorev1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	crdfake "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned/fake"
+	"github.com/kubeedge/kubeedge/pkg/util/supportbundle"
+)
+
+func TestWriteBundle(t *testing.T) {
+	config.Config.SupportBundleDir = t.TempDir()
+
+	if err := saveNodeBundle("bundle", "edge-1", base64.StdEncoding.EncodeToString([]byte("node bundle"))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := saveNodeBundle("bundle", "edge-2", "not base64"); err == nil {
+		t.Errorf("expected an error for an invalid bundle")
+	}
+
+	job := &v1alpha1.SupportBundleJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "bundle"},
+		Spec:       v1alpha1.SupportBundleJobSpec{CommonJobSpec: v1alpha1.CommonJobSpec{NodeNames: []string{"edge-1", "edge-2"}}},
+		Status:     v1alpha1.SupportBundleJobStatus{CommonJobStatus: v1alpha1.CommonJobStatus{State: api.TaskSuccessful}},
+	}
+	upgrade := &v1alpha1.NodeUpgradeJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "upgrade"},
+		Status:     v1alpha1.NodeUpgradeJobStatus{State: api.TaskFailed},
+	}
+	buf := &bytes.Buffer{}
+	w := supportbundle.NewWriter(buf)
+	if err := writeBundle(w, job, fake.NewSimpleClientset(), crdfake.NewSimpleClientset(upgrade)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	gz, err := gzip.NewReader(buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reader := tar.NewReader(gz)
+	files := map[string]string{}
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		data, _ := io.ReadAll(reader)
+		files[header.Name] = string(data)
+	}
+	for _, name := range []string{"job.json", "cloudcore/version.json", "cloudcore/cloudcore.yaml", "cloudcore/tasks.json"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("%s is missing in the bundle", name)
+		}
+	}
+	if files["nodes/edge-1.tar.gz"] != "node bundle" {
+		t.Errorf("unexpected bundle of edge-1: %q", files["nodes/edge-1.tar.gz"])
+	}
+	if _, ok := files["nodes/edge-2.tar.gz"]; ok {
+		t.Errorf("invalid bundle of edge-2 is archived")
+	}
+	if !bytes.Contains([]byte(files["cloudcore/tasks.json"]), []byte(`"name": "upgrade"`)) {
+		t.Errorf("task history is missing the NodeUpgradeJob: %s", files["cloudcore/tasks.json"])
+	}
+
+	if err = removeBundle("bundle"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestUploadBundle(t *testing.T) {
+	config.Config.SupportBundleDir = t.TempDir()
+	if err := saveNodeBundle("bundle", "edge-1", base64.StdEncoding.EncodeToString([]byte("node bundle"))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	artifact, err := localArtifact("bundle", "edge-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := v1alpha1.NodeArtifact{NodeName: "edge-1", URL: "/task/supportbundle/name/bundle/node/edge-1/bundle", Size: 11}
+	if artifact != expected {
+		t.Errorf("expected artifact %+v, got %+v", expected, artifact)
+	}
+
+	uploaded := map[string]string{}
+	store := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		uploaded[r.URL.Path] = string(data)
+	}))
+	defer store.Close()
+
+	upload := &cloudcorev1alpha1.TaskManagerSupportBundleUpload{
+		URL:     store.URL + "/bundles/",
+		Headers: map[string]string{"Authorization": "Bearer token"},
+	}
+	url, err := uploadBundle(upload, "bundle", "edge-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if url != store.URL+"/bundles/bundle/edge-1.tar.gz" {
+		t.Errorf("unexpected object URL %s", url)
+	}
+	if uploaded["/bundles/bundle/edge-1.tar.gz"] != "node bundle" {
+		t.Errorf("unexpected uploads %v", uploaded)
+	}
+
+	upload.Headers = nil
+	if _, err = uploadBundle(upload, "bundle", "edge-1"); err == nil {
+		t.Errorf("expected an error for a rejected upload")
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package supportbundlecontroller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/manager"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+)
+
+// defaultLogLines is the number of log lines collected when the job does not set it
+const defaultLogLines = 1000
+
+// SupportBundleController is the controller of SupportBundleJobs
+type SupportBundleController = controller.JobController[*v1alpha1.SupportBundleJob, *v1alpha1.SupportBundleJobList]
+
+// supportBundleJobType is the type of SupportBundleJobs handled by the controller
+var supportBundleJobType = controller.JobType[*v1alpha1.SupportBundleJob, *v1alpha1.SupportBundleJobList]{
+	Name:          util.TaskSupportBundle,
+	Kind:          "SupportBundleJob",
+	Rule:          api.SupportBundleRule,
+	StageSequence: api.SupportBundleStageSequence,
+	Client: func(crdClient crdClientset.Interface) util.TypedTaskClient[*v1alpha1.SupportBundleJob, *v1alpha1.SupportBundleJobList] {
+		return crdClient.OperationsV1alpha1().SupportBundleJobs()
+	},
+	Request: request,
+}
+
+func init() {
+	controller.RegisterPipeline(util.TaskSupportBundle, controller.Pipeline{
+		Stages:      []api.State{api.CollectingState},
+		Concurrency: 1,
+	})
+	util.RegisterTaskObjects(util.TaskSupportBundle, util.NewTypedTaskObjects(v1alpha1.Resource("supportbundlejobs"),
+		supportBundleJobType.Client,
+		func(meta metav1.ObjectMeta, spec v1alpha1.SupportBundleJobSpec) *v1alpha1.SupportBundleJob {
+			return &v1alpha1.SupportBundleJob{ObjectMeta: meta, Spec: spec}
+		},
+		func(job *v1alpha1.SupportBundleJob) util.TaskObjectStatus {
+			return util.JobObjectStatus(job.Status.CommonJobStatus, job.Status)
+		},
+		func(job *v1alpha1.SupportBundleJob) *int32 {
+			return job.Spec.TTLSecondsAfterFinished
+		}))
+}
+
+func NewSupportBundleController(messageChan chan util.TaskMessage) (*SupportBundleController, error) {
+	cache, err := manager.NewTaskCache(
+		informers.GetInformersManager().GetKubeEdgeInformerFactory().Operations().V1alpha1().SupportBundleJobs().Informer())
+	if err != nil {
+		klog.Warningf("Create support bundle controller failed with error: %s", err)
+		return nil, err
+	}
+	c, b := newController(controller.NewBaseController(util.TaskSupportBundle, messageChan, cache,
+		informers.GetInformersManager().GetKubeInformerFactory(), client.GetKubeClient(), client.GetCRDClient()))
+	go b.runUploads()
+	return c, nil
+}
+
+// NewController returns the controller of SupportBundleJobs with the clients and the task cache of
+// the base, the bundles are served by cloudcore since they are not uploaded in the background
+func NewController(base *controller.BaseController) *SupportBundleController {
+	c, _ := newController(base)
+	return c
+}
+
+func newController(base *controller.BaseController) (*SupportBundleController, *bundles) {
+	b := &bundles{uploads: newUploader()}
+	jobType := supportBundleJobType
+	jobType.HandleNodeReport = b.handleNodeReport
+	jobType.HandleFinish = b.handleFinish
+	jobType.HandleReset = b.handleReset
+	b.jobs = controller.NewJobController(jobType, base)
+	return b.jobs, b
+}
+
+// bundles keeps the bundles reported by the nodes of the SupportBundleJobs and uploads them
+type bundles struct {
+	jobs    *SupportBundleController
+	uploads *uploader
+}
+
+// request returns the message requesting the edge nodes to collect their bundles
+func request(job *v1alpha1.SupportBundleJob) interface{} {
+	logLines := job.Spec.LogLines
+	if logLines == 0 {
+		logLines = defaultLogLines
+	}
+	return commontypes.SupportBundleJobRequest{LogLines: logLines}
+}
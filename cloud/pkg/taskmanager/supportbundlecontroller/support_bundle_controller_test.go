@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package supportbundlecontroller
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/manager"
+	cloudcorev1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/client/clientset/versioned/fake"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// TestUploadReportedBundle covers the upload of the reported bundles, the report itself is covered with
+// the other jobs by TestReportNodeStatus of package controller
+func TestUploadReportedBundle(t *testing.T) {
+	config.Config.SupportBundleDir = t.TempDir()
+	store := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer store.Close()
+	config.Config.SupportBundleUpload = &cloudcorev1alpha1.TaskManagerSupportBundleUpload{URL: store.URL}
+	defer func() { config.Config.SupportBundleUpload = nil }()
+	util.InitEventRecorder(record.NewFakeRecorder(100))
+	job := &v1alpha1.SupportBundleJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "bundle"},
+		Status: v1alpha1.SupportBundleJobStatus{
+			CommonJobStatus: v1alpha1.CommonJobStatus{
+				State:  api.CollectingState,
+				Status: []v1alpha1.TaskStatus{{NodeName: "edge-1", State: api.CollectingState}},
+			},
+		},
+	}
+	crdClient := fake.NewSimpleClientset(job)
+	cache := &manager.TaskCache{}
+	cache.CacheMap.Store(job.Name, job)
+	c, b := newController(controller.NewBaseController(util.TaskSupportBundle, nil, cache, nil, nil, crdClient))
+
+	event := fsm.Event{Type: "Collect", Action: api.ActionSuccess, ExternalMessage: base64.StdEncoding.EncodeToString([]byte("bundle"))}
+	if _, err := c.ReportNodeStatus(job.Name, "edge-1", event); err != nil {
+		t.Fatalf("failed to report the node status: %v", err)
+	}
+	got, err := crdClient.OperationsV1alpha1().SupportBundleJobs().Get(context.TODO(), job.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	local := v1alpha1.NodeArtifact{NodeName: "edge-1", URL: "/task/supportbundle/name/bundle/node/edge-1/bundle", Size: 6}
+	if len(got.Status.Artifacts) != 1 || got.Status.Artifacts[0] != local {
+		t.Errorf("unexpected artifacts %+v, want the bundle to be served by cloudcore until it is uploaded", got.Status.Artifacts)
+	}
+
+	// the bundle is uploaded out of the report and the artifact is patched afterwards
+	if err = b.upload(<-b.uploads.queue); err != nil {
+		t.Fatalf("failed to upload the bundle: %v", err)
+	}
+	got, err = crdClient.OperationsV1alpha1().SupportBundleJobs().Get(context.TODO(), job.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Status.Artifacts) != 1 || got.Status.Artifacts[0].URL != store.URL+"/bundle/edge-1.tar.gz" ||
+		got.Status.Status[0].State != api.TaskSuccessful {
+		t.Errorf("unexpected status %+v after the upload", got.Status)
+	}
+
+	// the uploaded bundle is kept when the artifacts are rewritten from a stale job
+	stale := got.DeepCopy()
+	stale.Status.Artifacts[0] = local
+	b.uploads.applyUploaded(stale)
+	if stale.Status.Artifacts[0].URL != store.URL+"/bundle/edge-1.tar.gz" {
+		t.Errorf("unexpected artifacts %+v of the stale job", stale.Status.Artifacts)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package supportbundlecontroller
+
+import (
+	"fmt"
+
+	"k8s.io/klog/v2"
+
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// handleNodeReport stores the bundle reported by the node and records it as an artifact of the
+// node instead of persisting it in the status, the bundle is uploaded to the object store in the
+// background
+func (b *bundles) handleNodeReport(job *v1alpha1.SupportBundleJob, nodeStatus *v1alpha1.TaskStatus, event fsm.Event) {
+	if nodeStatus.State != api.TaskSuccessful || event.ExternalMessage == "" {
+		return
+	}
+	nodeName := nodeStatus.NodeName
+	if err := saveNodeBundle(job.Name, nodeName, event.ExternalMessage); err != nil {
+		klog.Warningf("failed to store the bundle of node %s in SupportBundleJob %s: %v", nodeName, job.Name, err)
+		nodeStatus.Reason = fmt.Sprintf("failed to store the bundle: %v", err)
+		return
+	}
+	artifact, err := localArtifact(job.Name, nodeName)
+	if err != nil {
+		klog.Warningf("failed to publish the bundle of node %s in SupportBundleJob %s: %v", nodeName, job.Name, err)
+		nodeStatus.Reason = err.Error()
+	}
+	b.uploads.forgetNode(job.Name, nodeName)
+	job.Status.Artifacts = setArtifact(job.Status.Artifacts, artifact)
+	b.uploads.applyUploaded(job)
+	if err == nil && !b.uploads.enqueue(job.Name, nodeName) {
+		klog.Warningf("too many bundles to upload, the bundle of node %s in SupportBundleJob %s is served by cloudcore", nodeName, job.Name)
+	}
+}
+
+// setArtifact replaces the artifact of the same node, or appends it
+func setArtifact(artifacts []v1alpha1.NodeArtifact, artifact v1alpha1.NodeArtifact) []v1alpha1.NodeArtifact {
+	for i := range artifacts {
+		if artifacts[i].NodeName == artifact.NodeName {
+			artifacts[i] = artifact
+			return artifacts
+		}
+	}
+	return append(artifacts, artifact)
+}
+
+// handleFinish packages the bundles collected so far into the archive, whatever the outcome of the job
+func (b *bundles) handleFinish(job *v1alpha1.SupportBundleJob, status *v1alpha1.CommonJobStatus) {
+	archived := job.DeepCopy()
+	archived.Status.CommonJobStatus = *status
+	archive, err := packageBundle(archived)
+	if err != nil {
+		klog.Errorf("failed to package the bundle of SupportBundleJob %s: %v", job.Name, err)
+		status.Reason = fmt.Sprintf("failed to package the bundle: %v", err)
+		return
+	}
+	job.Status.Archive = archive
+}
+
+// handleReset removes the bundle of the job before it is rerun or once it is deleted
+func (b *bundles) handleReset(job *v1alpha1.SupportBundleJob) {
+	b.uploads.forget(job.Name)
+	if err := removeBundle(job.Name); err != nil {
+		klog.Warningf("failed to remove the bundle of SupportBundleJob %s: %v", job.Name, err)
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package supportbundlecontroller
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/klog/v2"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/config"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// uploadQueueSize is the number of node bundles which can wait to be uploaded
+const uploadQueueSize = 100
+
+// bundleUpload is a stored node bundle waiting to be uploaded to the object store
+type bundleUpload struct {
+	job  string
+	node string
+}
+
+func (u bundleUpload) key() string {
+	return u.job + "/" + u.node
+}
+
+// uploader uploads the node bundles to the object store out of the reports of the nodes, the
+// artifacts of the nodes locate the bundles on cloudcore until they are uploaded.
+type uploader struct {
+	queue chan bundleUpload
+	// uploaded are the URLs of the uploaded bundles by job and node
+	uploaded sync.Map
+}
+
+func newUploader() *uploader {
+	return &uploader{queue: make(chan bundleUpload, uploadQueueSize)}
+}
+
+// enqueue queues the bundle of the node to be uploaded if an object store is configured, it
+// returns false if the queue is full and the bundle is only served by cloudcore
+func (u *uploader) enqueue(job, node string) bool {
+	if upload := config.Config.SupportBundleUpload; upload == nil || upload.URL == "" {
+		return true
+	}
+	select {
+	case u.queue <- bundleUpload{job: job, node: node}:
+		return true
+	default:
+		return false
+	}
+}
+
+// applyUploaded points the artifacts of the job to the bundles which are uploaded
+func (u *uploader) applyUploaded(job *v1alpha1.SupportBundleJob) {
+	for i, artifact := range job.Status.Artifacts {
+		if url, ok := u.uploaded.Load(bundleUpload{job: job.Name, node: artifact.NodeName}.key()); ok {
+			job.Status.Artifacts[i].URL = url.(string)
+		}
+	}
+}
+
+// forgetNode drops the uploaded bundle of the node, e.g. once the node reports another bundle
+func (u *uploader) forgetNode(job, node string) {
+	u.uploaded.Delete(bundleUpload{job: job, node: node}.key())
+}
+
+// forget drops the uploaded bundles of the job, e.g. before it is rerun
+func (u *uploader) forget(job string) {
+	u.uploaded.Range(func(key, _ interface{}) bool {
+		if strings.HasPrefix(key.(string), job+"/") {
+			u.uploaded.Delete(key)
+		}
+		return true
+	})
+}
+
+// runUploads uploads the queued bundles one at a time until cloudcore stops
+func (b *bundles) runUploads() {
+	for {
+		select {
+		case <-beehiveContext.Done():
+			return
+		case upload := <-b.uploads.queue:
+			if err := b.upload(upload); err != nil {
+				klog.Warningf("failed to upload the bundle of node %s in SupportBundleJob %s, it is served by cloudcore: %v",
+					upload.node, upload.job, err)
+			}
+		}
+	}
+}
+
+// upload uploads the bundle of the node and patches the artifact of the node to locate the object
+func (b *bundles) upload(upload bundleUpload) error {
+	store := config.Config.SupportBundleUpload
+	if store == nil || store.URL == "" {
+		return nil
+	}
+	url, err := uploadBundle(store, upload.job, upload.node)
+	if err != nil {
+		return err
+	}
+
+	// the node reports are persisted under the lock of the controller, so that the artifacts
+	// patched here are not overwritten with stale ones
+	b.jobs.Lock()
+	defer b.jobs.Unlock()
+	b.uploads.uploaded.Store(upload.key(), url)
+	job, err := b.jobs.Get(upload.job)
+	if err != nil {
+		return fmt.Errorf("failed to get the job to record the uploaded bundle: %v", err)
+	}
+	newJob := job.DeepCopy()
+	b.uploads.applyUploaded(newJob)
+
+	oldData, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	newData, err := json.Marshal(newJob)
+	if err != nil {
+		return err
+	}
+	patch, err := jsonpatch.CreateMergePatch(oldData, newData)
+	if err != nil {
+		return err
+	}
+	if string(patch) == "{}" {
+		return nil
+	}
+	return b.jobs.PatchStatus(upload.job, patch)
+}
@@ -22,12 +22,21 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/beehive/pkg/core"
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
 	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/nodetask"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/backupcontroller"
 	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/configupdatecontroller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/diagnosecontroller"
 	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/imageprepullcontroller"
 	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/manager"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/noderestartcontroller"
 	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/nodeupgradecontroller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/osupgradecontroller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/restorecontroller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/supportbundlecontroller"
 	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
 	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
 	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
@@ -46,6 +55,11 @@ func newTaskManager(enable bool) *TaskManager {
 	if !enable {
 		return &TaskManager{enable: enable}
 	}
+	if err := util.InitRecordStore(config.Config.RecordStore); err != nil {
+		klog.Exitf("Init task record store failed with error: %s", err)
+	}
+	util.InitAlerts(config.Config.AlertWebhook)
+	util.InitTransitionExport(config.Config.TransitionExport)
 	taskMessage := make(chan util.TaskMessage, 10)
 	downStreamMessage := make(chan model.Message, 10)
 	downstream, err := manager.NewDownstreamController(downStreamMessage)
@@ -60,6 +74,7 @@ func newTaskManager(enable bool) *TaskManager {
 	if err != nil {
 		klog.Exitf("New executor machine failed with error: %s", err)
 	}
+	nodetask.SetTaskExecutor(executorMachine)
 
 	upgradeNodeController, err := nodeupgradecontroller.NewNodeUpgradeController(taskMessage)
 	if err != nil {
@@ -70,8 +85,53 @@ func newTaskManager(enable bool) *TaskManager {
 	if err != nil {
 		klog.Exitf("New upgrade node controller failed with error: %s", err)
 	}
+
+	supportBundleController, err := supportbundlecontroller.NewSupportBundleController(taskMessage)
+	if err != nil {
+		klog.Exitf("New support bundle controller failed with error: %s", err)
+	}
+
+	configUpdateController, err := configupdatecontroller.NewConfigUpdateController(taskMessage)
+	if err != nil {
+		klog.Exitf("New config update controller failed with error: %s", err)
+	}
+
+	nodeRestartController, err := noderestartcontroller.NewNodeRestartController(taskMessage)
+	if err != nil {
+		klog.Exitf("New node restart controller failed with error: %s", err)
+	}
+
+	diagnoseController, err := diagnosecontroller.NewDiagnoseController(taskMessage)
+	if err != nil {
+		klog.Exitf("New diagnose controller failed with error: %s", err)
+	}
+
+	backupController, err := backupcontroller.NewBackupController(taskMessage)
+	if err != nil {
+		klog.Exitf("New backup controller failed with error: %s", err)
+	}
+
+	restoreController, err := restorecontroller.NewRestoreController(taskMessage)
+	if err != nil {
+		klog.Exitf("New restore controller failed with error: %s", err)
+	}
+
+	osUpgradeController, err := osupgradecontroller.NewOSUpgradeController(taskMessage)
+	if err != nil {
+		klog.Exitf("New OS upgrade controller failed with error: %s", err)
+	}
 	controller.Register(util.TaskUpgrade, upgradeNodeController)
 	controller.Register(util.TaskPrePull, imagePrePullController)
+	controller.Register(util.TaskSupportBundle, supportBundleController)
+	controller.Register(util.TaskConfigUpdate, configUpdateController)
+	controller.Register(util.TaskRestart, nodeRestartController)
+	controller.Register(util.TaskDiagnose, diagnoseController)
+	controller.Register(util.TaskBackup, backupController)
+	controller.Register(util.TaskRestore, restoreController)
+	controller.Register(util.TaskOSUpgrade, osUpgradeController)
+	if err = controller.RegisterFactories(taskMessage); err != nil {
+		klog.Exitf("Register task controllers failed with error: %s", err)
+	}
 
 	return &TaskManager{
 		downstream:      downstream,
@@ -104,6 +164,9 @@ func (uc *TaskManager) Enable() bool {
 
 // Start controller
 func (uc *TaskManager) Start() {
+	if config.Config.ReadOnly {
+		klog.Warning("TaskManager is read-only, the tasks are planned but not dispatched to the edge nodes")
+	}
 	if err := uc.downstream.Start(); err != nil {
 		klog.Exitf("start task manager downstream failed with error: %s", err)
 	}
@@ -120,4 +183,6 @@ func (uc *TaskManager) Start() {
 	if err := controller.StartAllController(); err != nil {
 		klog.Exitf("start controller failed with error: %s", err)
 	}
+	util.StartAlerts(beehiveContext.Done())
+	util.StartTransitionExport(beehiveContext.Done())
 }
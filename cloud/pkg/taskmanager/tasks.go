@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// TaskSubmitterAnnotationKey records the cloudcore module which submitted the task object with Tasks
+const TaskSubmitterAnnotationKey = "operations.kubeedge.io/submitter"
+
+// Tasks runs node tasks programmatically for other cloudcore modules, e.g. a remediation controller.
+// The tasks are recorded as task objects, so they are validated, audited and executed the same as
+// the tasks created by users.
+type Tasks interface {
+	// Submit creates the task object of the spec and returns the ID of the task
+	Submit(ctx context.Context, spec TaskSpec) (TaskID, error)
+	// Watch sends the status of the task each time it changes, starting with the current one.
+	// The channel is closed once the task finishes, the task object is deleted or ctx is done.
+	Watch(ctx context.Context, id TaskID) (<-chan TaskUpdate, error)
+}
+
+// TaskSpec is a task to submit
+type TaskSpec struct {
+	// Name is the name of the task object, it is generated from GenerateName if it is empty
+	Name         string
+	GenerateName string
+	Labels       map[string]string
+	// Submitter is the module submitting the task, it is recorded in the annotation of the task object
+	Submitter string
+
+	// Type is the type of the task, e.g. upgrade
+	Type string
+	// Spec is a pointer to the spec of the task objects of the type, e.g. *v1alpha1.NodeUpgradeJobSpec
+	Spec interface{}
+}
+
+// TaskID identifies a task by its type, e.g. upgrade, and the name of its task object
+type TaskID struct {
+	Type string
+	Name string
+}
+
+// TaskUpdate is the status of a task
+type TaskUpdate struct {
+	TaskID
+	State  api.State
+	Reason string
+	// NodeStatus is the status of the nodes of the task
+	NodeStatus []v1alpha1.TaskStatus
+	// Deleted is set if the task object is deleted, no update follows
+	Deleted bool
+}
+
+// Finished returns whether the update is the last one of the task
+func (u TaskUpdate) Finished() bool {
+	return u.Deleted || fsm.TaskFinish(u.State)
+}
+
+type tasks struct {
+	crdClient crdClientset.Interface
+}
+
+// NewTasks returns the Tasks which records the tasks with the client
+func NewTasks(crdClient crdClientset.Interface) Tasks {
+	return &tasks{crdClient: crdClient}
+}
+
+func (t *tasks) Submit(ctx context.Context, spec TaskSpec) (TaskID, error) {
+	meta := metav1.ObjectMeta{
+		Name:         spec.Name,
+		GenerateName: spec.GenerateName,
+		Labels:       spec.Labels,
+	}
+	if spec.Name == "" && spec.GenerateName == "" {
+		return TaskID{}, errors.New("name or generateName of the task is required")
+	}
+	if spec.Submitter != "" {
+		meta.Annotations = map[string]string{TaskSubmitterAnnotationKey: spec.Submitter}
+	}
+
+	objects, ok := util.GetTaskObjects(spec.Type)
+	if !ok {
+		return TaskID{}, fmt.Errorf("task type %q is not supported", spec.Type)
+	}
+	object, err := objects.NewTask(meta, spec.Spec)
+	if err != nil {
+		return TaskID{}, err
+	}
+	job, err := objects.Client(t.crdClient).Create(ctx, object)
+	if err != nil {
+		return TaskID{}, err
+	}
+	klog.Infof("%s submitted %s task %s", spec.Submitter, spec.Type, job.GetName())
+	return TaskID{Type: spec.Type, Name: job.GetName()}, nil
+}
+
+func (t *tasks) Watch(ctx context.Context, id TaskID) (<-chan TaskUpdate, error) {
+	objects, ok := util.GetTaskObjects(id.Type)
+	if !ok {
+		return nil, fmt.Errorf("task type %q is not supported", id.Type)
+	}
+	selector := fields.OneTermEqualSelector("metadata.name", id.Name).String()
+	taskClient := objects.Client(t.crdClient)
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = selector
+			return taskClient.List(ctx, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = selector
+			return taskClient.Watch(ctx, options)
+		},
+	}
+
+	updates := make(chan TaskUpdate, 1)
+	go func() {
+		defer close(updates)
+		_, err := watchtools.UntilWithSync(ctx, lw, objects.NewObject(), nil, func(event watch.Event) (bool, error) {
+			update, ok := taskUpdate(id, objects, event)
+			if !ok {
+				return false, nil
+			}
+			select {
+			case updates <- update:
+			case <-ctx.Done():
+				return false, ctx.Err()
+			}
+			return update.Finished(), nil
+		})
+		if err != nil && ctx.Err() == nil {
+			klog.Errorf("failed to watch %s task %s: %v", id.Type, id.Name, err)
+		}
+	}()
+	return updates, nil
+}
+
+// taskUpdate returns the update of the task in the event, it is false if the event is not of the task
+func taskUpdate(id TaskID, objects util.TaskObjects, event watch.Event) (TaskUpdate, bool) {
+	update := TaskUpdate{TaskID: id, Deleted: event.Type == watch.Deleted}
+	job, ok := event.Object.(util.TaskObject)
+	if !ok || job.GetName() != id.Name {
+		return update, false
+	}
+	status := objects.Status(job)
+	update.State, update.Reason, update.NodeStatus = status.State, status.Reason, status.NodeStatus
+	return update, true
+}
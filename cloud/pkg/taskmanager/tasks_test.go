@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskmanager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/client/clientset/versioned/fake"
+)
+
+func TestSubmitAndWatch(t *testing.T) {
+	crdClient := fake.NewSimpleClientset()
+	tasks := NewTasks(crdClient)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := tasks.Submit(ctx, TaskSpec{Name: "none", Type: util.TaskUpgrade}); err == nil {
+		t.Errorf("expected a task without spec to be rejected")
+	}
+	if _, err := tasks.Submit(ctx, TaskSpec{
+		Name: "mismatched",
+		Type: util.TaskUpgrade,
+		Spec: &v1alpha1.ImagePrePullJobSpec{},
+	}); err == nil {
+		t.Errorf("expected a task with the spec of another type to be rejected")
+	}
+	if _, err := tasks.Submit(ctx, TaskSpec{Name: "unknown", Type: "unknown", Spec: &v1alpha1.NodeUpgradeJobSpec{}}); err == nil {
+		t.Errorf("expected a task of unknown type to be rejected")
+	}
+
+	id, err := tasks.Submit(ctx, TaskSpec{
+		Name:      "remediate",
+		Submitter: "remediation",
+		Type:      util.TaskUpgrade,
+		Spec:      &v1alpha1.NodeUpgradeJobSpec{Version: "v1.17.0", NodeNames: []string{"edge-1"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to submit task: %v", err)
+	}
+	if id != (TaskID{Type: util.TaskUpgrade, Name: "remediate"}) {
+		t.Errorf("unexpected task ID %v", id)
+	}
+	job, err := crdClient.OperationsV1alpha1().NodeUpgradeJobs().Get(ctx, "remediate", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected the task object to be created: %v", err)
+	}
+	if job.Annotations[TaskSubmitterAnnotationKey] != "remediation" || job.Spec.Version != "v1.17.0" {
+		t.Errorf("unexpected task object %+v", job.ObjectMeta)
+	}
+
+	updates, err := tasks.Watch(ctx, id)
+	if err != nil {
+		t.Fatalf("failed to watch task: %v", err)
+	}
+	if update := <-updates; update.State != "" || update.Finished() {
+		t.Errorf("expected the initial update of the task, got %+v", update)
+	}
+
+	job.Status = v1alpha1.NodeUpgradeJobStatus{
+		State:  api.TaskSuccessful,
+		Status: []v1alpha1.TaskStatus{{NodeName: "edge-1", State: api.TaskSuccessful}},
+	}
+	if _, err = crdClient.OperationsV1alpha1().NodeUpgradeJobs().UpdateStatus(ctx, job, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update task status: %v", err)
+	}
+	update := <-updates
+	if !update.Finished() || len(update.NodeStatus) != 1 || update.NodeStatus[0].NodeName != "edge-1" {
+		t.Errorf("expected the task to finish on edge-1, got %+v", update)
+	}
+	if _, ok := <-updates; ok {
+		t.Errorf("expected the updates to be closed once the task finished")
+	}
+
+	if _, err = tasks.Watch(ctx, TaskID{Type: "unknown", Name: "remediate"}); err == nil {
+		t.Errorf("expected an unknown task type to be rejected")
+	}
+}
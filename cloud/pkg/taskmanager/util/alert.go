@@ -0,0 +1,253 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	cloudcorev1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+const (
+	// AlertTaskFailed fires when a task fails or exceeds its deadline
+	AlertTaskFailed = "KubeEdgeTaskFailed"
+	// AlertTaskFailureBudget fires when the failed nodes of a task reach a failure budget warning threshold
+	AlertTaskFailureBudget = "KubeEdgeTaskFailureBudget"
+
+	AlertSeverityWarning  = "warning"
+	AlertSeverityCritical = "critical"
+
+	alertQueueSize = 100
+	// alertEndsAfter is how many repeat intervals a firing alert stays active without being posted again
+	alertEndsAfter = 4
+)
+
+// Alert is an alert in the format of the Alertmanager v2 API
+type Alert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+}
+
+type alerter struct {
+	webhook *cloudcorev1alpha1.TaskManagerAlertWebhook
+	client  *http.Client
+	// firing holds the firing alerts, keyed by the type and the name of their task and their name
+	firing map[string]Alert
+	queue  chan []Alert
+	sync.Mutex
+}
+
+var alerts *alerter
+
+// InitAlerts initializes the alerts of tasks, no alert is sent if the webhook has no URL
+func InitAlerts(webhook *cloudcorev1alpha1.TaskManagerAlertWebhook) {
+	if webhook == nil || webhook.URL == "" {
+		alerts = nil
+		return
+	}
+	alerts = &alerter{
+		webhook: webhook,
+		client:  &http.Client{Timeout: time.Duration(webhook.TimeoutSeconds) * time.Second},
+		firing:  map[string]Alert{},
+		queue:   make(chan []Alert, alertQueueSize),
+	}
+}
+
+// StartAlerts posts the alerts to the webhook until stop is closed, the firing alerts are
+// posted again every repeat interval.
+func StartAlerts(stop <-chan struct{}) {
+	if alerts == nil {
+		return
+	}
+	go alerts.run(stop)
+}
+
+// FireAlert fires the alert of the task, it does nothing if the alert is already firing
+func FireAlert(taskType, taskName, alertName, severity, summary string) {
+	a := alerts
+	if a == nil {
+		return
+	}
+	a.Lock()
+	defer a.Unlock()
+	key := alertKey(taskType, taskName, alertName)
+	if _, ok := a.firing[key]; ok {
+		return
+	}
+	labels := make(map[string]string, len(a.webhook.Labels)+4)
+	for k, v := range a.webhook.Labels {
+		labels[k] = v
+	}
+	labels["alertname"] = alertName
+	labels["task_type"] = taskType
+	labels["task"] = taskName
+	labels["severity"] = severity
+	now := time.Now()
+	alert := Alert{
+		Labels:      labels,
+		Annotations: map[string]string{"summary": summary},
+		StartsAt:    now,
+		EndsAt:      now.Add(alertEndsAfter * a.repeatInterval()),
+	}
+	a.firing[key] = alert
+	a.enqueue([]Alert{alert})
+}
+
+// ResolveAlerts resolves the firing alerts of the task with the names, or all of them if no name is given
+func ResolveAlerts(taskType, taskName string, alertNames ...string) {
+	a := alerts
+	if a == nil {
+		return
+	}
+	a.Lock()
+	defer a.Unlock()
+	var keys []string
+	if len(alertNames) == 0 {
+		prefix := alertKey(taskType, taskName, "")
+		for key := range a.firing {
+			if strings.HasPrefix(key, prefix) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	for _, name := range alertNames {
+		keys = append(keys, alertKey(taskType, taskName, name))
+	}
+
+	var resolved []Alert
+	now := time.Now()
+	for _, key := range keys {
+		alert, ok := a.firing[key]
+		if !ok {
+			continue
+		}
+		delete(a.firing, key)
+		alert.EndsAt = now
+		resolved = append(resolved, alert)
+	}
+	if len(resolved) != 0 {
+		a.enqueue(resolved)
+	}
+}
+
+// NotifyTaskState fires or resolves the alerts of the task according to its new state,
+// the failure alert fires when the task fails and all the alerts are resolved when it completes.
+func NotifyTaskState(taskType, taskName string, state api.State, reason string) {
+	switch {
+	case !fsm.TaskFinish(state):
+		// the task is running again, e.g. it is rerun
+		ResolveAlerts(taskType, taskName, AlertTaskFailed)
+	case state == api.TaskFailed || state == api.TaskDeadlineExceeded:
+		summary := fmt.Sprintf("%s task %s is %s", taskType, taskName, state)
+		if reason != "" {
+			summary += ": " + reason
+		}
+		ResolveAlerts(taskType, taskName, AlertTaskFailureBudget)
+		FireAlert(taskType, taskName, AlertTaskFailed, AlertSeverityCritical, summary)
+	default:
+		ResolveAlerts(taskType, taskName)
+	}
+}
+
+func (a *alerter) run(stop <-chan struct{}) {
+	ticker := time.NewTicker(a.repeatInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case batch := <-a.queue:
+			a.post(batch)
+		case <-ticker.C:
+			a.post(a.refresh())
+		}
+	}
+}
+
+// refresh extends the firing alerts so that they stay active until they are posted again
+func (a *alerter) refresh() []Alert {
+	a.Lock()
+	defer a.Unlock()
+	firing := make([]Alert, 0, len(a.firing))
+	endsAt := time.Now().Add(alertEndsAfter * a.repeatInterval())
+	for key, alert := range a.firing {
+		alert.EndsAt = endsAt
+		a.firing[key] = alert
+		firing = append(firing, alert)
+	}
+	return firing
+}
+
+// enqueue queues the alerts to be posted, the alerts are dropped if the queue is full,
+// the firing ones are posted again in the next repeat interval.
+func (a *alerter) enqueue(batch []Alert) {
+	select {
+	case a.queue <- batch:
+	default:
+		klog.Warningf("the alert queue is full, drop %d alerts", len(batch))
+	}
+}
+
+func (a *alerter) post(batch []Alert) {
+	if len(batch) == 0 {
+		return
+	}
+	if err := postAlerts(a.client, a.webhook.URL, batch); err != nil {
+		klog.Errorf("failed to post %d alerts: %v", len(batch), err)
+	}
+}
+
+func (a *alerter) repeatInterval() time.Duration {
+	if a.webhook.RepeatIntervalSeconds <= 0 {
+		return time.Minute
+	}
+	return time.Duration(a.webhook.RepeatIntervalSeconds) * time.Second
+}
+
+func postAlerts(client *http.Client, url string, batch []Alert) error {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alerts: %v", err)
+	}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("alert webhook returned status code %d: %s", resp.StatusCode, data)
+	}
+	return nil
+}
+
+func alertKey(taskType, taskName, alertName string) string {
+	return taskType + "/" + taskName + "/" + alertName
+}
@@ -0,0 +1,29 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// KeepCommandDigest keeps the digest of the commands the node reported in a former state of the task
+// if the node reports none, e.g. in the states verified from cloud which run no command on the node
+func KeepCommandDigest(status *v1alpha1.TaskStatus, former *v1alpha1.TaskStatus) {
+	if status.CommandDigest == "" && former != nil {
+		status.CommandDigest = former.CommandDigest
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// CanaryNodes returns the names of the canary nodes among the nodes of a task, either the nodes
+// selected by the label selector of the canary or the first nodes by name.
+func CanaryNodes(canary *v1alpha1.CanarySpec, nodes []corev1.Node) ([]string, error) {
+	var names []string
+	if canary.LabelSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(canary.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector of the canary: %v", err)
+		}
+		for _, node := range nodes {
+			if selector.Matches(labels.Set(node.Labels)) {
+				names = append(names, node.Name)
+			}
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+
+	for _, node := range nodes {
+		names = append(names, node.Name)
+	}
+	sort.Strings(names)
+	if len(names) > int(canary.Nodes) {
+		names = names[:canary.Nodes]
+	}
+	return names, nil
+}
+
+// CanaryFirst moves the canary nodes to the front of the nodes of a task, the order of the nodes is
+// kept otherwise.
+func CanaryFirst(nodes []v1alpha1.TaskStatus, canary map[string]bool) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return canary[nodes[i].NodeName] && !canary[nodes[j].NodeName]
+	})
+}
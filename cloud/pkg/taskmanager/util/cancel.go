@@ -0,0 +1,45 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TaskCancelAnnotationKey requests the cancellation of a task when it is set to "true" on the
+// task object. The task and its nodes go through the Cancelling state to Cancelled.
+const TaskCancelAnnotationKey = "operations.kubeedge.io/cancel"
+
+// TaskCancelGracefully is the value of TaskCancelAnnotationKey which cancels the task without
+// interrupting the nodes running it, they finish or time out before the task is Cancelled.
+const TaskCancelGracefully = "graceful"
+
+// IsCancelRequested returns whether the cancellation of the task object is requested
+func IsCancelRequested(task v1.Object) bool {
+	value := task.GetAnnotations()[TaskCancelAnnotationKey]
+	return value == "true" || value == TaskCancelGracefully
+}
+
+// IsGracefulCancel returns whether the task object is cancelled without interrupting the running nodes
+func IsGracefulCancel(task v1.Object) bool {
+	return task.GetAnnotations()[TaskCancelAnnotationKey] == TaskCancelGracefully
+}
+
+// CancelRequested returns whether the cancellation is requested by the update of the task object
+func CancelRequested(old, task v1.Object) bool {
+	return !IsCancelRequested(old) && IsCancelRequested(task)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"strings"
+
+	"github.com/blang/semver"
+	"k8s.io/klog/v2"
+
+	cloudcorev1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+)
+
+// VersionCheckItems returns the check items of an upgrade to the version, i.e. the check items of the job
+// followed by the check items of the catalog entries whose range includes the version, without duplicates.
+func VersionCheckItems(catalog []cloudcorev1alpha1.TaskManagerVersionCheckItems, version string, items []string) []string {
+	if len(catalog) == 0 {
+		return items
+	}
+	target, err := semver.Parse(strings.TrimPrefix(version, "v"))
+	if err != nil {
+		klog.Warningf("failed to select the check items of version %s: %v", version, err)
+		return items
+	}
+
+	selected := make(map[string]bool, len(items))
+	for _, item := range items {
+		selected[item] = true
+	}
+	checkItems := append([]string(nil), items...)
+	for _, entry := range catalog {
+		versions, err := semver.ParseRange(entry.Versions)
+		if err != nil {
+			klog.Warningf("invalid range of versions %q of the check items: %v", entry.Versions, err)
+			continue
+		}
+		if !versions(target) {
+			continue
+		}
+		for _, item := range entry.CheckItems {
+			if !selected[item] {
+				selected[item] = true
+				checkItems = append(checkItems, item)
+			}
+		}
+	}
+	return checkItems
+}
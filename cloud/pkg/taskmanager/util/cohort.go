@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"encoding/json"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// LabelNodeCohorts labels the nodes of the finished task with the cohorts of their outcome, the
+// references to the inputs of the task in the labels are replaced with their values. The nodes
+// which cannot be labeled are logged and skipped.
+func LabelNodeCohorts(taskName string, cohorts *v1alpha1.NodeCohortSpec, inputs map[string]string, nodes []v1alpha1.TaskStatus) {
+	if cohorts == nil {
+		return
+	}
+	if len(inputs) != 0 {
+		cohorts = cohorts.DeepCopy()
+		if err := SubstituteInputs(cohorts, inputs); err != nil {
+			klog.Warningf("failed to resolve the cohorts of task %s: %v", taskName, err)
+			return
+		}
+	}
+	var labeled int
+	for _, node := range nodes {
+		labels := NodeCohortLabels(cohorts, node.State)
+		if len(labels) == 0 {
+			continue
+		}
+		patch, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"labels": labels}})
+		if err != nil {
+			klog.Warningf("failed to label the cohort of node %s in task %s: %v", node.NodeName, taskName, err)
+			continue
+		}
+		_, err = client.GetKubeClient().CoreV1().Nodes().Patch(context.TODO(), node.NodeName, types.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				klog.Warningf("failed to label the cohort of node %s in task %s: %v", node.NodeName, taskName, err)
+			}
+			continue
+		}
+		labeled++
+	}
+	klog.Infof("labeled the cohorts of %d nodes of task %s", labeled, taskName)
+}
+
+// NodeCohortLabels returns the labels of the node which finished the task in the given state, a
+// label to remove is nil. A node which succeeded or failed loses the labels of the other outcome
+// which it is not given, the nodes which were not operated on get no label.
+func NodeCohortLabels(cohorts *v1alpha1.NodeCohortSpec, state api.State) map[string]*string {
+	var outcome, other map[string]string
+	switch state {
+	case api.TaskSkipped, api.TaskNodeRemoved:
+		return nil
+	case api.TaskSuccessful:
+		outcome, other = cohorts.SucceededLabels, cohorts.FailedLabels
+	case api.TaskFailed:
+		outcome, other = cohorts.FailedLabels, cohorts.SucceededLabels
+	}
+
+	labels := make(map[string]*string, len(cohorts.Labels)+len(outcome)+len(other))
+	for key := range other {
+		labels[key] = nil
+	}
+	for _, set := range []map[string]string{cohorts.Labels, outcome} {
+		for key, value := range set {
+			value := value
+			labels[key] = &value
+		}
+	}
+	return labels
+}
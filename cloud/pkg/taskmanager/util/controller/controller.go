@@ -17,16 +17,22 @@ limitations under the License.
 package controller
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	k8sinformer "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/config"
 	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
 	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/manager"
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
 	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
 	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
 	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
@@ -38,10 +44,17 @@ type Controller interface {
 	Start() error
 	ReportNodeStatus(string, string, fsm.Event) (api.State, error)
 	ReportTaskStatus(string, fsm.Event) (api.State, error)
-	ValidateNode(util.TaskMessage) []v1.Node
+	ValidateNode(util.TaskMessage) ([]v1.Node, []v1alpha1.ExcludedNode)
 	GetNodeStatus(string) ([]v1alpha1.TaskStatus, error)
 	UpdateNodeStatus(string, []v1alpha1.TaskStatus) error
 	StageCompleted(taskID string, state api.State) bool
+	KnownState(taskID string, state api.State) bool
+	AnalyzeImpact(taskID string, nodes []v1.Node) error
+	RecordExcludedNodes(taskID string, excluded []v1alpha1.ExcludedNode) error
+	RecordPlan(taskID string, plan v1alpha1.ExecutionPlan) error
+	RecordFailureThreshold(taskID string, threshold int32) error
+	RecordMaintenanceWindow(taskID string, window *v1alpha1.MaintenanceWindowStatus) error
+	Resync() error
 }
 
 type BaseController struct {
@@ -65,25 +78,65 @@ func (bc *BaseController) StageCompleted(string, api.State) bool {
 	return false
 }
 
-func (bc *BaseController) ValidateNode(taskMessage util.TaskMessage) []v1.Node {
-	var validateNodes []v1.Node
-	nodes, err := bc.getNodeList(taskMessage.NodeNames, taskMessage.LabelSelector)
+// KnownState returns true by default, controllers that can classify the node states override it
+func (bc *BaseController) KnownState(string, api.State) bool {
+	return true
+}
+
+// ValidateNode returns the nodes the task operates on, and the nodes selected by the task which
+// are excluded with the reason
+func (bc *BaseController) ValidateNode(taskMessage util.TaskMessage) ([]v1.Node, []v1alpha1.ExcludedNode) {
+	nodes, excluded, err := util.SelectNodes(&nodeLister{bc}, taskMessage.NodeNames, taskMessage.LabelSelector, taskMessage.NodeGroups)
 	if err != nil {
 		klog.Warningf("get node list error: %s", err.Error())
-		return nil
+		return nil, excluded
 	}
-	for _, node := range nodes {
-		if !util.IsEdgeNode(node) {
-			klog.Warningf("Node(%s) is not edge node", node.Name)
-			continue
-		}
-		ready := isNodeReady(node)
-		if !ready {
-			continue
-		}
-		validateNodes = append(validateNodes, *node)
+	candidates, notCandidates := util.CandidateNodes(nodes)
+	excluded = append(excluded, notCandidates...)
+
+	validateNodes, filtered := util.FilterNodesByWebhook(config.Config.NodeFilterWebhook, taskMessage, candidates)
+	return validateNodes, append(excluded, filtered...)
+}
+
+func (bc *BaseController) AnalyzeImpact(string, []v1.Node) error {
+	return nil
+}
+
+func (bc *BaseController) RecordExcludedNodes(string, []v1alpha1.ExcludedNode) error {
+	return nil
+}
+
+// RecordPlan records nothing by default, controllers of the tasks which support dry runs override it
+func (bc *BaseController) RecordPlan(string, v1alpha1.ExecutionPlan) error {
+	return nil
+}
+
+func (bc *BaseController) RecordFailureThreshold(string, int32) error {
+	return nil
+}
+
+// RecordMaintenanceWindow records nothing by default, controllers of the tasks which support
+// maintenance windows override it
+func (bc *BaseController) RecordMaintenanceWindow(string, *v1alpha1.MaintenanceWindowStatus) error {
+	return nil
+}
+
+// Resync does nothing by default, controllers override it to send their unfinished tasks to the
+// executors again, e.g. once the replica is elected to run the executors
+func (bc *BaseController) Resync() error {
+	return nil
+}
+
+// AffectedWorkloads returns the workloads that have running pods on the given nodes
+func (bc *BaseController) AffectedWorkloads(nodes []v1.Node) ([]v1alpha1.WorkloadReference, error) {
+	pods, err := bc.Informer.Core().V1().Pods().Lister().List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
 	}
-	return validateNodes
+	getReplicaSet := func(namespace, name string) (*appsv1.ReplicaSet, error) {
+		return bc.Informer.Apps().V1().ReplicaSets().Lister().ReplicaSets(namespace).Get(name)
+	}
+	return util.AffectedWorkloads(pods, getReplicaSet, nodes), nil
 }
 
 func (bc *BaseController) GetNodeStatus(string) ([]v1alpha1.TaskStatus, error) {
@@ -94,16 +147,6 @@ func (bc *BaseController) UpdateNodeStatus(string, []v1alpha1.TaskStatus) error
 	return fmt.Errorf("function UpdateNodeStatus need to be init")
 }
 
-func isNodeReady(node *v1.Node) bool {
-	for _, condition := range node.Status.Conditions {
-		if condition.Type == v1.NodeReady && condition.Status != v1.ConditionTrue {
-			klog.Warningf("Node(%s) is in NotReady state", node.Name)
-			return false
-		}
-	}
-	return true
-}
-
 func (bc *BaseController) ReportNodeStatus(string, string, fsm.Event) (api.State, error) {
 	return "", fmt.Errorf("function ReportNodeStatus need to be init")
 }
@@ -116,6 +159,61 @@ var (
 	controllers = map[string]Controller{}
 )
 
+// NewBaseController returns a BaseController of the task type, the controller sends
+// task messages to the executors through messageChan.
+func NewBaseController(name string, messageChan chan util.TaskMessage, taskCache *manager.TaskCache,
+	informer k8sinformer.SharedInformerFactory, kubeClient kubernetes.Interface, crdClient crdClientset.Interface) *BaseController {
+	return &BaseController{
+		name:        name,
+		Informer:    informer,
+		TaskManager: taskCache,
+		MessageChan: messageChan,
+		KubeClient:  kubeClient,
+		CrdClient:   crdClient,
+	}
+}
+
+// Factory creates the controller of a task type
+type Factory func(messageChan chan util.TaskMessage) (Controller, error)
+
+var factories = map[string]Factory{}
+
+// RegisterFactory registers the factory of a task type implemented out of tree,
+// it must be called before TaskManager starts, usually in an init function.
+func RegisterFactory(name string, factory Factory) {
+	if _, ok := factories[name]; ok {
+		klog.Warningf("controller factory %s exists ", name)
+	}
+	factories[name] = factory
+}
+
+// RegisterFactories creates and registers the controllers of all registered factories
+func RegisterFactories(messageChan chan util.TaskMessage) error {
+	for name, factory := range factories {
+		controller, err := factory(messageChan)
+		if err != nil {
+			return fmt.Errorf("create %s controller failed: %s", name, err.Error())
+		}
+		Register(name, controller)
+	}
+	return nil
+}
+
+// IsRegistered returns whether the controller of the task type is registered
+func IsRegistered(name string) bool {
+	_, ok := controllers[name]
+	return ok
+}
+
+// IsNodeMessage returns whether the operation is of the task messages which edge nodes send to TaskManager
+func IsNodeMessage(operation string) bool {
+	switch operation {
+	case util.TaskAccept, util.TaskInventory, util.TaskProbe:
+		return true
+	}
+	return IsRegistered(operation)
+}
+
 func Register(name string, controller Controller) {
 	if _, ok := controllers[name]; ok {
 		klog.Warningf("controller %s exists ", name)
@@ -133,6 +231,18 @@ func StartAllController() error {
 	return nil
 }
 
+// ResyncAllControllers sends the unfinished tasks of all controllers to the executors again,
+// a controller failing to resync does not stop the others
+func ResyncAllControllers() error {
+	var errs []error
+	for name, controller := range controllers {
+		if err := controller.Resync(); err != nil {
+			errs = append(errs, fmt.Errorf("resync %s controller failed: %s", name, err.Error()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func GetController(name string) (Controller, error) {
 	controller, ok := controllers[name]
 	if !ok {
@@ -141,29 +251,19 @@ func GetController(name string) (Controller, error) {
 	return controller, nil
 }
 
-func (bc *BaseController) getNodeList(nodeNames []string, labelSelector *metav1.LabelSelector) ([]*v1.Node, error) {
-	var nodesToUpgrade []*v1.Node
+// nodeLister looks up the nodes in the informer of the controller and the node groups in the apiserver
+type nodeLister struct {
+	bc *BaseController
+}
 
-	if len(nodeNames) != 0 {
-		for _, name := range nodeNames {
-			node, err := bc.Informer.Core().V1().Nodes().Lister().Get(name)
-			if err != nil {
-				return nil, fmt.Errorf("failed to get node with name %s: %v", name, err)
-			}
-			nodesToUpgrade = append(nodesToUpgrade, node)
-		}
-	} else if labelSelector != nil {
-		selector, err := metav1.LabelSelectorAsSelector(labelSelector)
-		if err != nil {
-			return nil, fmt.Errorf("labelSelector(%s) is not valid: %v", labelSelector, err)
-		}
+func (l *nodeLister) GetNode(name string) (*v1.Node, error) {
+	return l.bc.Informer.Core().V1().Nodes().Lister().Get(name)
+}
 
-		nodes, err := bc.Informer.Core().V1().Nodes().Lister().List(selector)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get nodes with label %s: %v", selector.String(), err)
-		}
-		nodesToUpgrade = nodes
-	}
+func (l *nodeLister) ListNodes(selector labels.Selector) ([]*v1.Node, error) {
+	return l.bc.Informer.Core().V1().Nodes().Lister().List(selector)
+}
 
-	return nodesToUpgrade, nil
+func (l *nodeLister) GetNodeGroup(name string) (*appsv1alpha1.NodeGroup, error) {
+	return l.bc.CrdClient.AppsV1alpha1().NodeGroups().Get(context.TODO(), name, metav1.GetOptions{})
 }
@@ -0,0 +1,220 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sinformer "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+func controllerRef(kind, name string) []metav1.OwnerReference {
+	isController := true
+	return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &isController}}
+}
+
+func TestAffectedWorkloads(t *testing.T) {
+	informer := k8sinformer.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	pods := []*v1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default", OwnerReferences: controllerRef("ReplicaSet", "web-rs")},
+			Spec:       v1.PodSpec{NodeName: "edge-1"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-2", Namespace: "default", OwnerReferences: controllerRef("ReplicaSet", "web-rs")},
+			Spec:       v1.PodSpec{NodeName: "edge-2"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent-1", Namespace: "kube-system", OwnerReferences: controllerRef("DaemonSet", "agent")},
+			Spec:       v1.PodSpec{NodeName: "edge-1"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", OwnerReferences: controllerRef("DaemonSet", "other")},
+			Spec:       v1.PodSpec{NodeName: "edge-3"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "done", Namespace: "default", OwnerReferences: controllerRef("Job", "done")},
+			Spec:       v1.PodSpec{NodeName: "edge-1"},
+			Status:     v1.PodStatus{Phase: v1.PodSucceeded},
+		},
+	}
+	for _, pod := range pods {
+		if err := informer.Core().V1().Pods().Informer().GetIndexer().Add(pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-rs", Namespace: "default", OwnerReferences: controllerRef("Deployment", "web")},
+	}
+	if err := informer.Apps().V1().ReplicaSets().Informer().GetIndexer().Add(rs); err != nil {
+		t.Fatal(err)
+	}
+
+	bc := &BaseController{Informer: informer}
+	nodes := []v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "edge-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "edge-2"}},
+	}
+	workloads, err := bc.AffectedWorkloads(nodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []v1alpha1.WorkloadReference{
+		{Kind: "DaemonSet", Namespace: "kube-system", Name: "agent", NodeNames: []string{"edge-1"}},
+		{Kind: "Deployment", Namespace: "default", Name: "web", NodeNames: []string{"edge-1", "edge-2"}},
+	}
+	if !reflect.DeepEqual(workloads, expected) {
+		t.Errorf("Got = %v, Want = %v", workloads, expected)
+	}
+}
+
+func TestPipelineOverride(t *testing.T) {
+	pipeline := Pipeline{
+		Stages:        []api.State{api.TaskChecking, api.UpgradingState},
+		VerifiesNodes: true,
+		StageTimeouts: map[api.State]uint32{api.TaskChecking: 60},
+		Concurrency:   1,
+	}
+	timeout, concurrency := uint32(600), int32(5)
+	tolerate := intstr.FromString("20%")
+	invalidTolerate := intstr.FromString("200%")
+	tests := []struct {
+		name     string
+		pipeline Pipeline
+		spec     v1alpha1.TaskTypeConfigSpec
+		expected Pipeline
+		invalid  bool
+	}{
+		{name: "nothing overridden", pipeline: pipeline, expected: pipeline},
+		{
+			name:     "overridden",
+			pipeline: pipeline,
+			spec: v1alpha1.TaskTypeConfigSpec{
+				TimeoutSeconds:  &timeout,
+				StageTimeouts:   map[string]uint32{"Upgrading": 1200},
+				Concurrency:     &concurrency,
+				FailureTolerate: &tolerate,
+				CheckItems:      []string{"disk"},
+				PostCheck:       &v1alpha1.PostCheckSpec{},
+			},
+			expected: Pipeline{
+				Stages:          pipeline.Stages,
+				VerifiesNodes:   true,
+				CheckItems:      []string{"disk"},
+				TimeoutSeconds:  600,
+				StageTimeouts:   map[api.State]uint32{api.TaskChecking: 60, api.UpgradingState: 1200},
+				Concurrency:     5,
+				FailureTolerate: &tolerate,
+				PostCheck:       &v1alpha1.PostCheckSpec{},
+			},
+		},
+		{name: "unknown stage", pipeline: pipeline, spec: v1alpha1.TaskTypeConfigSpec{StageTimeouts: map[string]uint32{"Pulling": 60}}, invalid: true},
+		{name: "zero stage timeout", pipeline: pipeline, spec: v1alpha1.TaskTypeConfigSpec{StageTimeouts: map[string]uint32{"Upgrading": 0}}, invalid: true},
+		{name: "invalid failure tolerance", pipeline: pipeline, spec: v1alpha1.TaskTypeConfigSpec{FailureTolerate: &invalidTolerate}, invalid: true},
+		{
+			name:     "no checking stage",
+			pipeline: Pipeline{Stages: []api.State{api.RestartingState}, VerifiesNodes: true},
+			spec:     v1alpha1.TaskTypeConfigSpec{CheckItems: []string{"disk"}},
+			invalid:  true,
+		},
+		{
+			name:     "nodes not verified",
+			pipeline: Pipeline{Stages: []api.State{api.CollectingState}},
+			spec:     v1alpha1.TaskTypeConfigSpec{PostCheck: &v1alpha1.PostCheckSpec{}},
+			invalid:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			overridden, err := test.pipeline.Override(test.spec)
+			if test.invalid {
+				if err == nil {
+					t.Errorf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(overridden, test.expected) {
+				t.Errorf("expected %+v, got %+v", test.expected, overridden)
+			}
+		})
+	}
+	if len(pipeline.StageTimeouts) != 1 {
+		t.Errorf("the default pipeline is modified by the override: %v", pipeline.StageTimeouts)
+	}
+}
+
+func TestPipelineApply(t *testing.T) {
+	tolerate := intstr.FromInt(2)
+	maxUnavailable := intstr.FromString("10%")
+	pipeline := Pipeline{
+		CheckItems:      []string{"disk"},
+		TimeoutSeconds:  600,
+		StageTimeouts:   map[api.State]uint32{api.TaskChecking: 60, api.UpgradingState: 1200},
+		Concurrency:     3,
+		FailureTolerate: &tolerate,
+		PostCheck:       &v1alpha1.PostCheckSpec{},
+	}
+	timeout := uint32(100)
+	jobTolerate := intstr.FromInt(0)
+
+	msg := pipeline.Apply(util.TaskMessage{StageTimeouts: map[api.State]uint32{api.UpgradingState: 300}})
+	if msg.TimeOutSeconds == nil || *msg.TimeOutSeconds != 600 {
+		t.Errorf("expected the default timeout 600, got %v", msg.TimeOutSeconds)
+	}
+	if expected := map[api.State]uint32{api.TaskChecking: 60, api.UpgradingState: 300}; !reflect.DeepEqual(msg.StageTimeouts, expected) {
+		t.Errorf("expected stage timeouts %v, got %v", expected, msg.StageTimeouts)
+	}
+	if msg.Concurrency != 3 || msg.FailureTolerate != &tolerate || !reflect.DeepEqual(msg.CheckItem, []string{"disk"}) || msg.PostCheck == nil {
+		t.Errorf("expected the defaults of the pipeline, got %+v", msg)
+	}
+
+	// the fields set by the task are kept, the stage timeouts of the pipeline do not apply to a task with a timeout
+	msg = pipeline.Apply(util.TaskMessage{
+		TimeOutSeconds:  &timeout,
+		Concurrency:     1,
+		FailureTolerate: &jobTolerate,
+		CheckItem:       []string{"cpu"},
+	})
+	if *msg.TimeOutSeconds != 100 || msg.StageTimeouts != nil || msg.Concurrency != 1 || msg.FailureTolerate != &jobTolerate ||
+		!reflect.DeepEqual(msg.CheckItem, []string{"cpu"}) {
+		t.Errorf("expected the fields of the task, got %+v", msg)
+	}
+
+	// the batches sized by maxUnavailable are not given a concurrency
+	msg = pipeline.Apply(util.TaskMessage{MaxUnavailable: &maxUnavailable})
+	if msg.Concurrency != 0 {
+		t.Errorf("expected no concurrency, got %d", msg.Concurrency)
+	}
+
+	// a task type without pipeline runs one node at a time
+	if msg = (Pipeline{}).Apply(util.TaskMessage{}); msg.Concurrency != 1 || msg.TimeOutSeconds != nil || msg.PostCheck != nil {
+		t.Errorf("expected the global defaults, got %+v", msg)
+	}
+}
@@ -0,0 +1,626 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	apimachineryType "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/klog/v2"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// Job is the object of a job type handled by JobController, e.g. *v1alpha1.ConfigUpdateJob, its spec
+// and status embed the CommonJobSpec and CommonJobStatus of the operation jobs
+type Job interface {
+	util.TaskObject
+	GetCommonJobSpec() *v1alpha1.CommonJobSpec
+	GetCommonJobStatus() *v1alpha1.CommonJobStatus
+}
+
+// JobType describes a job type handled by JobController, the jobs J are listed in L
+type JobType[J Job, L runtime.Object] struct {
+	// Name is the task type of the jobs, e.g. util.TaskConfigUpdate
+	Name string
+	// Kind is the kind of the jobs, e.g. ConfigUpdateJob
+	Kind string
+	// Rule and StageSequence drive the state machines of the jobs and of their nodes
+	Rule          map[string]api.State
+	StageSequence map[api.State]api.State
+	// Client returns the generated client of the jobs
+	Client func(crdClient crdClientset.Interface) util.TypedTaskClient[J, L]
+	// Request returns the message sent to the edge nodes to run the job
+	Request func(job J) interface{}
+	// PostCheck returns the post check of the job, it is only set for the job types whose nodes
+	// are verified from cloud
+	PostCheck func(job J) *v1alpha1.PostCheckSpec
+
+	// HandleNodeReport is set for the job types which keep what the nodes report in the status of
+	// the jobs. It is called with the copy of the job which is persisted, before the status of the
+	// node is persisted.
+	HandleNodeReport func(job J, nodeStatus *v1alpha1.TaskStatus, event fsm.Event)
+	// HandleFinish is set for the job types which complete the status of the jobs once they are
+	// finished, whatever their outcome.
+	HandleFinish func(job J, status *v1alpha1.CommonJobStatus)
+	// HandleReset is set for the job types which keep data of the jobs out of their status, the
+	// data is removed before the jobs are rerun and once they are deleted.
+	HandleReset func(job J)
+}
+
+// JobController is the controller of the job types which run the same request on each node, the
+// jobs are watched, sent to the executors and their status is persisted through the client of the
+// job type.
+type JobController[J Job, L runtime.Object] struct {
+	sync.Mutex
+	*BaseController
+	jobType JobType[J, L]
+	client  util.TypedTaskClient[J, L]
+}
+
+// NewJobController returns the controller of the job type
+func NewJobController[J Job, L runtime.Object](jobType JobType[J, L], base *BaseController) *JobController[J, L] {
+	return &JobController[J, L]{
+		BaseController: base,
+		jobType:        jobType,
+		client:         jobType.Client(base.CrdClient),
+	}
+}
+
+// Get returns the job from the apiserver
+func (jc *JobController[J, L]) Get(name string) (J, error) {
+	return jc.client.Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+// PatchStatus applies the merge patch to the status of the job
+func (jc *JobController[J, L]) PatchStatus(name string, patch []byte) error {
+	result, err := jc.client.Patch(context.TODO(), name, apimachineryType.MergePatchType, patch, metav1.PatchOptions{}, "status")
+	if err != nil {
+		return err
+	}
+	klog.V(4).Info("patch update task status result: ", result)
+	return nil
+}
+
+// list returns the jobs from the apiserver
+func (jc *JobController[J, L]) list() ([]J, error) {
+	list, err := jc.client.List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	items, err := apimeta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]J, 0, len(items))
+	for _, item := range items {
+		jobs = append(jobs, item.(J))
+	}
+	return jobs, nil
+}
+
+// postCheck returns the post check of the job, if the job type has one
+func (jc *JobController[J, L]) postCheck(job J) *v1alpha1.PostCheckSpec {
+	if jc.jobType.PostCheck == nil {
+		return nil
+	}
+	return jc.jobType.PostCheck(job)
+}
+
+func (jc *JobController[J, L]) newNodeFSM(taskName, nodeName string) *fsm.FSM {
+	fsm := &fsm.FSM{}
+	return fsm.NodeName(nodeName).ID(taskName).Guard(jc.jobType.Rule).StageSequence(jc.jobType.StageSequence).CurrentFunc(jc.currentNodeState).UpdateFunc(jc.updateNodeState)
+}
+
+func (jc *JobController[J, L]) newTaskFSM(taskName string) *fsm.FSM {
+	fsm := &fsm.FSM{}
+	return fsm.ID(taskName).Guard(jc.jobType.Rule).StageSequence(jc.jobType.StageSequence).CurrentFunc(jc.currentTaskState).UpdateFunc(jc.updateTaskState)
+}
+
+// cachedJob returns the job in the cache of the controller
+func (jc *JobController[J, L]) cachedJob(id string) (J, error) {
+	v, ok := jc.TaskManager.CacheMap.Load(id)
+	if !ok {
+		var job J
+		return job, fmt.Errorf("can not find task %s", id)
+	}
+	return v.(J), nil
+}
+
+func (jc *JobController[J, L]) currentNodeState(id, nodeName string) (api.State, error) {
+	job, err := jc.cachedJob(id)
+	if err != nil {
+		return "", err
+	}
+	var state api.State
+	for _, status := range job.GetCommonJobStatus().Status {
+		if status.NodeName == nodeName {
+			state = status.State
+			break
+		}
+	}
+	if state == "" {
+		state = api.TaskInit
+	}
+	return state, nil
+}
+
+// updateNodeState persists the state of the node
+func (jc *JobController[J, L]) updateNodeState(id, nodeName string, state api.State, event fsm.Event) error {
+	job, err := jc.cachedJob(id)
+	if err != nil {
+		return err
+	}
+	newJob := job.DeepCopyObject().(J)
+	status := *newJob.GetCommonJobStatus()
+	nodeStatus := v1alpha1.TaskStatus{
+		NodeName: nodeName,
+		State:    state,
+		Event:    event.Type,
+		Action:   event.Action,
+		Time:     time.Now().Format(util.ISO8601UTC),
+		Reason:   event.Msg,
+
+		CommandDigest: event.CommandDigest,
+	}
+	if fsm.TaskFinish(state) {
+		nodeStatus.Environment = event.Environment
+		nodeStatus.Metadata = event.Metadata
+	}
+	if jc.jobType.HandleNodeReport != nil {
+		jc.jobType.HandleNodeReport(newJob, &nodeStatus, event)
+	}
+	persisted := nodeStatus
+	util.PruneNodeStatus(&persisted, config.Config.PrunedNodeStatusFields)
+	for i := range status.Status {
+		if status.Status[i].NodeName == nodeName {
+			util.KeepCommandDigest(&persisted, &status.Status[i])
+			util.RecordNodeHistory(&persisted, &status.Status[i], config.Config.TransitionHistory)
+			status.Status[i] = persisted
+		}
+	}
+	if err := jc.patchStatus(job, newJob, status); err != nil {
+		return err
+	}
+	util.RecordNodeTransition(job, jc.jobType.Kind, jc.jobType.Name, nodeStatus)
+	return nil
+}
+
+func (jc *JobController[J, L]) currentTaskState(id, _ string) (api.State, error) {
+	job, err := jc.cachedJob(id)
+	if err != nil {
+		return "", err
+	}
+	state := job.GetCommonJobStatus().State
+	if state == "" {
+		state = api.TaskInit
+	}
+	return state, nil
+}
+
+// updateTaskState persists the state of the job
+func (jc *JobController[J, L]) updateTaskState(id, _ string, state api.State, event fsm.Event) error {
+	job, err := jc.cachedJob(id)
+	if err != nil {
+		return err
+	}
+	newJob := job.DeepCopyObject().(J)
+	status := *newJob.GetCommonJobStatus()
+
+	status.Event = event.Type
+	status.Action = event.Action
+	status.Reason = event.Msg
+	status.State = state
+	status.Time = time.Now().Format(util.ISO8601UTC)
+
+	if fsm.TaskFinish(state) {
+		status.Outputs = util.NodeOutputs(status.Status)
+		if jc.jobType.HandleFinish != nil {
+			jc.jobType.HandleFinish(newJob, &status)
+		}
+	}
+
+	if err := jc.patchStatus(job, newJob, status); err != nil {
+		return err
+	}
+	previous := job.GetCommonJobStatus().State
+	if fsm.TaskFinish(state) && !fsm.TaskFinish(previous) {
+		spec := newJob.GetCommonJobSpec()
+		go util.LabelNodeCohorts(job.GetName(), spec.Cohorts, nil, status.Status)
+		util.NotifyTaskFinished(newJob, jc.jobType.Kind, spec.Notifications, state, event.Msg, status.Status)
+	}
+	util.RecordTaskTransition(job, jc.jobType.Kind, previous, state, event)
+	util.NotifyTaskState(jc.jobType.Name, job.GetName(), state, event.Msg)
+	return nil
+}
+
+// patchStatus sets the status of newJob, a copy of job, and patches the difference with job
+func (jc *JobController[J, L]) patchStatus(job, newJob J, status v1alpha1.CommonJobStatus) error {
+	oldData, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the old %s(%s): %v", jc.jobType.Kind, job.GetName(), err)
+	}
+	*newJob.GetCommonJobStatus() = status
+	newData, err := json.Marshal(newJob)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the new %s(%s): %v", jc.jobType.Kind, job.GetName(), err)
+	}
+
+	patchBytes, err := jsonpatch.CreateMergePatch(oldData, newData)
+	if err != nil {
+		return fmt.Errorf("failed to create a merge patch: %v", err)
+	}
+
+	if err := jc.PatchStatus(job.GetName(), patchBytes); err != nil {
+		return fmt.Errorf("failed to patch update %s status: %v", jc.jobType.Kind, err)
+	}
+	return nil
+}
+
+func (jc *JobController[J, L]) ReportNodeStatus(taskID, nodeID string, event fsm.Event) (api.State, error) {
+	nodeFSM := jc.newNodeFSM(taskID, nodeID)
+	err := nodeFSM.AllowTransit(event)
+	if err != nil {
+		return "", err
+	}
+	state, err := nodeFSM.CurrentState()
+	if err != nil {
+		return "", err
+	}
+	jc.Lock()
+	defer jc.Unlock()
+	err = nodeFSM.Transit(event)
+	if err != nil {
+		return "", err
+	}
+	checkStatusChanged(nodeFSM, state)
+	return nodeFSM.CurrentState()
+}
+
+func checkStatusChanged(nodeFSM *fsm.FSM, state api.State) {
+	err := wait.Poll(100*time.Millisecond, time.Second, func() (bool, error) {
+		nowState, err := nodeFSM.CurrentState()
+		if err != nil {
+			return false, nil
+		}
+		if nowState == state {
+			return false, nil
+		}
+		return true, err
+	})
+	if err != nil {
+		klog.V(4).Infof("check status changed failed: %s", err.Error())
+	}
+}
+
+func (jc *JobController[J, L]) ReportTaskStatus(taskID string, event fsm.Event) (api.State, error) {
+	taskFSM := jc.newTaskFSM(taskID)
+	state, err := taskFSM.CurrentState()
+	if err != nil {
+		return "", err
+	}
+	err = taskFSM.AllowTransit(event)
+	if err != nil {
+		return "", err
+	}
+	err = taskFSM.Transit(event)
+	if err != nil {
+		return "", err
+	}
+	checkStatusChanged(taskFSM, state)
+	return taskFSM.CurrentState()
+}
+
+func (jc *JobController[J, L]) StageCompleted(taskID string, state api.State) bool {
+	return jc.newTaskFSM(taskID).TaskStagCompleted(state)
+}
+
+func (jc *JobController[J, L]) KnownState(taskID string, state api.State) bool {
+	return jc.newTaskFSM(taskID).KnownState(state)
+}
+
+func (jc *JobController[J, L]) GetNodeStatus(name string) ([]v1alpha1.TaskStatus, error) {
+	job, err := jc.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return job.GetCommonJobStatus().Status, nil
+}
+
+func (jc *JobController[J, L]) UpdateNodeStatus(name string, nodeStatus []v1alpha1.TaskStatus) error {
+	return jc.updateStatus(name, func(status *v1alpha1.CommonJobStatus) bool {
+		status.Status = nodeStatus
+		return true
+	})
+}
+
+// RecordExcludedNodes records the nodes selected by the job which are not operated on
+func (jc *JobController[J, L]) RecordExcludedNodes(name string, excluded []v1alpha1.ExcludedNode) error {
+	return jc.updateStatus(name, func(status *v1alpha1.CommonJobStatus) bool {
+		status.ExcludedNodes = excluded
+		return true
+	})
+}
+
+// RecordFailureThreshold records the number of failed nodes the job fails at
+func (jc *JobController[J, L]) RecordFailureThreshold(name string, threshold int32) error {
+	return jc.updateStatus(name, func(status *v1alpha1.CommonJobStatus) bool {
+		if status.FailureThreshold == threshold {
+			return false
+		}
+		status.FailureThreshold = threshold
+		return true
+	})
+}
+
+// updateStatus patches the status of the job if update changes it
+func (jc *JobController[J, L]) updateStatus(name string, update func(status *v1alpha1.CommonJobStatus) bool) error {
+	job, err := jc.Get(name)
+	if err != nil {
+		return err
+	}
+	status := *job.GetCommonJobStatus()
+	if !update(&status) {
+		return nil
+	}
+	return jc.patchStatus(job, job.DeepCopyObject().(J), status)
+}
+
+func (jc *JobController[J, L]) Start() error {
+	go jc.startSync()
+	return nil
+}
+
+// Resync sends the unfinished jobs to the executors again, they are handled as if they were added
+func (jc *JobController[J, L]) Resync() error {
+	jobs, err := jc.list()
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if fsm.TaskFinish(job.GetCommonJobStatus().State) {
+			continue
+		}
+		jc.TaskManager.Events() <- watch.Event{Type: watch.Added, Object: job}
+	}
+	return nil
+}
+
+func (jc *JobController[J, L]) startSync() {
+	jobs, err := jc.list()
+	if err != nil {
+		klog.Errorf(err.Error())
+		os.Exit(2)
+	}
+	for _, job := range jobs {
+		if fsm.TaskFinish(job.GetCommonJobStatus().State) {
+			continue
+		}
+		jc.jobAdded(job)
+	}
+	for {
+		select {
+		case <-beehiveContext.Done():
+			klog.Infof("stop sync %s", jc.jobType.Kind)
+			return
+		case e := <-jc.TaskManager.Events():
+			job, ok := e.Object.(J)
+			if !ok {
+				klog.Warningf("object type: %T unsupported", e.Object)
+				continue
+			}
+			switch e.Type {
+			case watch.Added:
+				jc.jobAdded(job)
+			case watch.Deleted:
+				jc.jobDeleted(job)
+			case watch.Modified:
+				jc.jobUpdated(job)
+			default:
+				klog.Warningf("%s event type: %s unsupported", jc.jobType.Kind, e.Type)
+			}
+		}
+	}
+}
+
+// jobAdded is used to process addition of new job in apiserver
+func (jc *JobController[J, L]) jobAdded(job J) {
+	klog.V(4).Infof("add %s: %v", jc.jobType.Kind, job)
+	jc.TaskManager.CacheMap.Store(job.GetName(), job)
+
+	status := *job.GetCommonJobStatus()
+	if fsm.TaskFinish(status.State) {
+		if util.RerunRequested(status.State, job.GetCommonJobSpec().Rerun, status.ObservedRerun) {
+			jc.rerunJob(job)
+			return
+		}
+		klog.Warningf("The %s is completed, don't send task message again", jc.jobType.Kind)
+		return
+	}
+
+	jc.processJob(job)
+	if util.IsCancelRequested(job) {
+		jc.cancelJob(job)
+	}
+}
+
+// cancelJob requests the executor of the job to cancel it
+func (jc *JobController[J, L]) cancelJob(job J) {
+	klog.Infof("cancel %s %s", jc.jobType.Kind, job.GetName())
+	jc.MessageChan <- util.TaskMessage{
+		Type:   jc.jobType.Name,
+		Name:   job.GetName(),
+		Cancel: true,
+		Drain:  util.IsGracefulCancel(job),
+	}
+}
+
+// processJob requests the edge nodes to run the request of the job
+func (jc *JobController[J, L]) processJob(job J) {
+	klog.V(4).Infof("deal task message: %v", job)
+	spec := job.GetCommonJobSpec()
+	jc.MessageChan <- util.TaskMessage{
+		Type:            jc.jobType.Name,
+		Name:            job.GetName(),
+		TimeOutSeconds:  spec.TimeoutSeconds,
+		Concurrency:     spec.Concurrency,
+		ActiveDeadline:  util.ActiveDeadline(spec.ActiveDeadlineSeconds),
+		FailureTolerate: spec.FailureTolerate,
+		NodeNames:       spec.NodeNames,
+		LabelSelector:   spec.LabelSelector,
+		Status:          v1alpha1.TaskStatus{},
+		PostCheck:       jc.postCheck(job),
+		Msg:             jc.jobType.Request(job),
+		Labels:          job.GetLabels(),
+		Metadata:        spec.Metadata,
+		RenderPayload:   spec.RenderPayload,
+		Notifications:   spec.Notifications,
+		Credential:      spec.Credential,
+		Owner:           util.NewTaskOwnerReference(job, jc.jobType.Kind),
+	}
+}
+
+// rerunJob resets the status of the finished job and runs it again with its current spec
+func (jc *JobController[J, L]) rerunJob(job J) {
+	spec := job.GetCommonJobSpec()
+	klog.Infof("rerun %s %s, rerun %d", jc.jobType.Kind, job.GetName(), spec.Rerun)
+	if jc.jobType.HandleReset != nil {
+		jc.jobType.HandleReset(job)
+	}
+	rerun := job.DeepCopyObject().(J)
+	status := v1alpha1.CommonJobStatus{
+		ObservedRerun: spec.Rerun,
+		StartTime:     time.Now().UTC().Format(util.ISO8601UTC),
+	}
+	if err := jc.patchStatus(job, rerun, status); err != nil {
+		klog.Errorf("failed to reset the status of %s %s to rerun it: %v", jc.jobType.Kind, job.GetName(), err)
+		return
+	}
+	jc.TaskManager.CacheMap.Store(job.GetName(), rerun)
+	jc.processJob(rerun)
+}
+
+// jobDeleted is used to process deleted job in apiserver
+func (jc *JobController[J, L]) jobDeleted(job J) {
+	jc.TaskManager.CacheMap.Delete(job.GetName())
+	klog.Infof("%s %s delete", jc.jobType.Kind, job.GetName())
+	if jc.jobType.HandleReset != nil {
+		jc.jobType.HandleReset(job)
+	}
+	jc.MessageChan <- util.TaskMessage{
+		Type:     jc.jobType.Name,
+		Name:     job.GetName(),
+		ShutDown: true,
+	}
+}
+
+// jobUpdated is used to process update of job in apiserver
+func (jc *JobController[J, L]) jobUpdated(job J) {
+	oldValue, ok := jc.TaskManager.CacheMap.Load(job.GetName())
+	if !ok {
+		klog.Infof("Update %s not exist, and store it first", job.GetName())
+		jc.jobAdded(job)
+		return
+	}
+	old := oldValue.(J)
+	jc.TaskManager.CacheMap.Store(job.GetName(), job)
+	oldSpec, spec := old.GetCommonJobSpec(), job.GetCommonJobSpec()
+	status := *job.GetCommonJobStatus()
+	if util.RerunRequested(status.State, spec.Rerun, status.ObservedRerun) {
+		jc.rerunJob(job)
+		return
+	}
+	finished := fsm.TaskFinish(status.State)
+	if finished && (!reflect.DeepEqual(oldSpec, spec) || !reflect.DeepEqual(jc.postCheck(old), jc.postCheck(job)) ||
+		!reflect.DeepEqual(jc.jobType.Request(old), jc.jobType.Request(job))) {
+		klog.Infof("%s %s is finished, the change of its spec is ignored until it is rerun", jc.jobType.Kind, job.GetName())
+	}
+
+	if util.CancelRequested(old, job) && !finished {
+		jc.cancelJob(job)
+		return
+	}
+	if (oldSpec.Concurrency != spec.Concurrency || !reflect.DeepEqual(oldSpec.FailureTolerate, spec.FailureTolerate)) &&
+		!finished {
+		klog.Infof("tune %s %s", jc.jobType.Kind, job.GetName())
+		jc.MessageChan <- util.TaskMessage{
+			Type:            jc.jobType.Name,
+			Name:            job.GetName(),
+			Concurrency:     spec.Concurrency,
+			FailureTolerate: spec.FailureTolerate,
+			SetTuning:       true,
+		}
+		return
+	}
+	if force, err := util.ForceCompletionRequested(old, job); err != nil {
+		klog.Errorf("%s %s: %v", jc.jobType.Kind, job.GetName(), err)
+	} else if force != nil && !finished {
+		klog.Infof("force node %s of %s %s to %s", force.NodeName, jc.jobType.Kind, job.GetName(), force.State)
+		jc.MessageChan <- util.TaskMessage{
+			Type:          jc.jobType.Name,
+			Name:          job.GetName(),
+			ForceComplete: force,
+		}
+		return
+	}
+
+	node := checkUpdateNode(old.GetCommonJobStatus().Status, status.Status)
+	if node == nil {
+		klog.V(4).Info("none node update")
+		return
+	}
+	jc.MessageChan <- util.TaskMessage{
+		Type:   jc.jobType.Name,
+		Name:   job.GetName(),
+		Status: *node,
+	}
+}
+
+// checkUpdateNode returns the first node whose status is updated
+func checkUpdateNode(old, new []v1alpha1.TaskStatus) *v1alpha1.TaskStatus {
+	if len(old) == 0 {
+		return nil
+	}
+	for i, updateNode := range new {
+		if i >= len(old) {
+			break
+		}
+		if !util.NodeUpdated(old[i], updateNode) {
+			continue
+		}
+		return &updateNode
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/backupcontroller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/configupdatecontroller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/diagnosecontroller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/noderestartcontroller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/osupgradecontroller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/restorecontroller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/supportbundlecontroller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/controller"
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util/manager"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/client/clientset/versioned/fake"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// running is the status of a job running on edge-1 in the state
+func running(state api.State) v1alpha1.CommonJobStatus {
+	return v1alpha1.CommonJobStatus{
+		State:  state,
+		Status: []v1alpha1.TaskStatus{{NodeName: "edge-1", State: state}},
+	}
+}
+
+func TestReportNodeStatus(t *testing.T) {
+	util.InitEventRecorder(record.NewFakeRecorder(100))
+	config.Config.SupportBundleDir = t.TempDir()
+	ctx := context.TODO()
+
+	cases := []struct {
+		name          string
+		taskType      string
+		job           runtime.Object
+		newController func(*controller.BaseController) controller.Controller
+		event         fsm.Event
+		expected      api.State
+		// check checks what the job type records besides the node status
+		check func(t *testing.T, crdClient *fake.Clientset)
+	}{
+		{
+			name:     "backup",
+			taskType: util.TaskBackup,
+			job: &v1alpha1.BackupJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "backup"},
+				Status:     v1alpha1.BackupJobStatus{CommonJobStatus: running(api.BackingUpState)},
+			},
+			newController: func(base *controller.BaseController) controller.Controller {
+				return backupcontroller.NewController(base)
+			},
+			event:    fsm.Event{Type: api.EventBackup, Action: api.ActionSuccess, ExternalMessage: `{"path":"/var/lib/kubeedge/backup/edge-1.tar.gz"}`},
+			expected: api.TaskSuccessful,
+			check: func(t *testing.T, crdClient *fake.Clientset) {
+				job, err := crdClient.OperationsV1alpha1().BackupJobs().Get(ctx, "backup", metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(job.Status.Backups) != 1 || job.Status.Backups[0].NodeName != "edge-1" ||
+					job.Status.Backups[0].Path != "/var/lib/kubeedge/backup/edge-1.tar.gz" {
+					t.Errorf("unexpected backups %+v", job.Status.Backups)
+				}
+			},
+		},
+		{
+			name:     "restore",
+			taskType: util.TaskRestore,
+			job: &v1alpha1.RestoreJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "restore"},
+				Status:     v1alpha1.RestoreJobStatus{CommonJobStatus: running(api.RestoringState)},
+			},
+			newController: func(base *controller.BaseController) controller.Controller {
+				return restorecontroller.NewController(base)
+			},
+			event:    fsm.Event{Type: api.EventRestore, Action: api.ActionSuccess},
+			expected: api.TaskSuccessful,
+		},
+		{
+			name:     "config update",
+			taskType: util.TaskConfigUpdate,
+			job: &v1alpha1.ConfigUpdateJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "update"},
+				Status:     v1alpha1.ConfigUpdateJobStatus{CommonJobStatus: running(api.ApplyingState)},
+			},
+			newController: func(base *controller.BaseController) controller.Controller {
+				return configupdatecontroller.NewController(base)
+			},
+			event:    fsm.Event{Type: api.EventApply, Action: api.ActionSuccess},
+			expected: api.VerifyingState,
+		},
+		{
+			name:     "node restart",
+			taskType: util.TaskRestart,
+			job: &v1alpha1.NodeRestartJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "restart"},
+				Status:     v1alpha1.NodeRestartJobStatus{CommonJobStatus: running(api.RestartingState)},
+			},
+			newController: func(base *controller.BaseController) controller.Controller {
+				return noderestartcontroller.NewController(base)
+			},
+			event:    fsm.Event{Type: api.EventRestart, Action: api.ActionSuccess},
+			expected: api.VerifyingState,
+		},
+		{
+			name:     "diagnose",
+			taskType: util.TaskDiagnose,
+			job: &v1alpha1.DiagnoseJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "diagnose"},
+				Status:     v1alpha1.DiagnoseJobStatus{CommonJobStatus: running(api.DiagnosingState)},
+			},
+			newController: func(base *controller.BaseController) controller.Controller {
+				return diagnosecontroller.NewController(base)
+			},
+			event:    fsm.Event{Type: api.EventDiagnose, Action: api.ActionSuccess, ExternalMessage: `[{"check":"disk","result":"Failed"}]`},
+			expected: api.TaskSuccessful,
+			check: func(t *testing.T, crdClient *fake.Clientset) {
+				job, err := crdClient.OperationsV1alpha1().DiagnoseJobs().Get(ctx, "diagnose", metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(job.Status.Diagnoses) != 1 || job.Status.Diagnoses[0].NodeName != "edge-1" || job.Status.Diagnoses[0].Healthy {
+					t.Errorf("unexpected diagnoses %+v, want edge-1 to be unhealthy", job.Status.Diagnoses)
+				}
+			},
+		},
+		{
+			name:     "os upgrade",
+			taskType: util.TaskOSUpgrade,
+			job: &v1alpha1.OSUpgradeJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "upgrade"},
+				Status:     v1alpha1.OSUpgradeJobStatus{CommonJobStatus: running(api.OSUpgradingState)},
+			},
+			newController: func(base *controller.BaseController) controller.Controller {
+				return osupgradecontroller.NewController(base)
+			},
+			event:    fsm.Event{Type: api.EventOSUpgrade, Action: api.ActionSuccess},
+			expected: api.VerifyingState,
+		},
+		{
+			name:     "support bundle",
+			taskType: util.TaskSupportBundle,
+			job: &v1alpha1.SupportBundleJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "bundle"},
+				Status:     v1alpha1.SupportBundleJobStatus{CommonJobStatus: running(api.CollectingState)},
+			},
+			newController: func(base *controller.BaseController) controller.Controller {
+				return supportbundlecontroller.NewController(base)
+			},
+			event:    fsm.Event{Type: "Collect", Action: api.ActionSuccess, ExternalMessage: base64.StdEncoding.EncodeToString([]byte("bundle"))},
+			expected: api.TaskSuccessful,
+			check: func(t *testing.T, crdClient *fake.Clientset) {
+				job, err := crdClient.OperationsV1alpha1().SupportBundleJobs().Get(ctx, "bundle", metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				local := v1alpha1.NodeArtifact{NodeName: "edge-1", URL: "/task/supportbundle/name/bundle/node/edge-1/bundle", Size: 6}
+				if len(job.Status.Artifacts) != 1 || job.Status.Artifacts[0] != local {
+					t.Errorf("unexpected artifacts %+v, want the bundle to be served by cloudcore", job.Status.Artifacts)
+				}
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			name := c.job.(metav1.Object).GetName()
+			crdClient := fake.NewSimpleClientset(c.job)
+			cache := &manager.TaskCache{}
+			cache.CacheMap.Store(name, c.job)
+			ctrl := c.newController(controller.NewBaseController(c.taskType, nil, cache, nil, nil, crdClient))
+
+			if _, err := ctrl.ReportNodeStatus(name, "edge-1", c.event); err != nil {
+				t.Fatalf("failed to report the node status: %v", err)
+			}
+			nodeStatus, err := ctrl.GetNodeStatus(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(nodeStatus) != 1 || nodeStatus[0].State != c.expected ||
+				nodeStatus[0].Event != c.event.Type || nodeStatus[0].Action != c.event.Action {
+				t.Errorf("unexpected node status %+v, want it to be %s", nodeStatus, c.expected)
+			}
+			if c.check != nil {
+				c.check(t, crdClient)
+			}
+		})
+	}
+}
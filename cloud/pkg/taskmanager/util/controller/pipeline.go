@@ -0,0 +1,165 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// Pipeline is the default pipeline of a task type: the stages its nodes go through, the checks run
+// before they are operated on and the timeouts. The tasks of the type run with its defaults for the
+// fields they do not set, a TaskTypeConfig named after the type overrides them.
+type Pipeline struct {
+	// Stages are the states of the nodes in the stages of the task type, in order
+	Stages []api.State
+	// VerifiesNodes is set if the nodes are verified from cloud with the post check once they are
+	// operated on
+	VerifiesNodes bool
+
+	// CheckItems are run in the Checking stage by the tasks which set no check item
+	CheckItems []string
+	// TimeoutSeconds limits each stage on each node for the tasks which set no timeout, the global
+	// default of the executors applies if it is 0
+	TimeoutSeconds uint32
+	// StageTimeouts limits the stages by the state of the nodes in the stage for the tasks which set
+	// no timeout
+	StageTimeouts map[api.State]uint32
+	// Concurrency is the number of nodes operated on at the same time by the tasks which set none
+	Concurrency int32
+	// FailureTolerate is the failure tolerance of the tasks which set none, util.DefaultFailureTolerate
+	// is used if it is nil
+	FailureTolerate *intstr.IntOrString
+	// PostCheck verifies the nodes from cloud for the tasks which set no post check
+	PostCheck *v1alpha1.PostCheckSpec
+}
+
+var pipelines = map[string]Pipeline{}
+
+// RegisterPipeline registers the default pipeline of a task type, it must be called before
+// TaskManager starts, usually in an init function.
+func RegisterPipeline(name string, pipeline Pipeline) {
+	if _, ok := pipelines[name]; ok {
+		klog.Warningf("pipeline %s exists ", name)
+	}
+	pipelines[name] = pipeline
+}
+
+// GetPipeline returns the default pipeline registered by the task type, the task types which
+// registered none run one node at a time with the global defaults
+func GetPipeline(name string) (Pipeline, bool) {
+	pipeline, ok := pipelines[name]
+	return pipeline, ok
+}
+
+// Override returns the pipeline with the defaults of the TaskTypeConfig, the fields it does not set
+// are kept. It fails if the defaults do not apply to the task type, e.g. a timeout of a stage it does
+// not have.
+func (p Pipeline) Override(spec v1alpha1.TaskTypeConfigSpec) (Pipeline, error) {
+	if len(spec.CheckItems) != 0 && !slices.Contains(p.Stages, api.TaskChecking) {
+		return p, fmt.Errorf("the task type has no %s stage to run the check items", api.TaskChecking)
+	}
+	if spec.PostCheck != nil && !p.VerifiesNodes {
+		return p, fmt.Errorf("the nodes of the task type are not verified from cloud, the post check is not run")
+	}
+	if spec.FailureTolerate != nil {
+		if _, err := util.MaxFailedNodes(spec.FailureTolerate, 0); err != nil {
+			return p, err
+		}
+	}
+	if len(spec.StageTimeouts) != 0 {
+		stages := make([]string, 0, len(spec.StageTimeouts))
+		for stage := range spec.StageTimeouts {
+			stages = append(stages, stage)
+		}
+		sort.Strings(stages)
+		stageTimeouts := make(map[api.State]uint32, len(p.StageTimeouts)+len(stages))
+		for state, timeout := range p.StageTimeouts {
+			stageTimeouts[state] = timeout
+		}
+		for _, stage := range stages {
+			if !slices.Contains(p.Stages, api.State(stage)) {
+				return p, fmt.Errorf("the task type has no %s stage, its stages are %v", stage, p.Stages)
+			}
+			if spec.StageTimeouts[stage] == 0 {
+				return p, fmt.Errorf("the timeout of the %s stage must be positive", stage)
+			}
+			stageTimeouts[api.State(stage)] = spec.StageTimeouts[stage]
+		}
+		p.StageTimeouts = stageTimeouts
+	}
+
+	if len(spec.CheckItems) != 0 {
+		p.CheckItems = spec.CheckItems
+	}
+	if spec.TimeoutSeconds != nil {
+		p.TimeoutSeconds = *spec.TimeoutSeconds
+	}
+	if spec.Concurrency != nil {
+		p.Concurrency = *spec.Concurrency
+	}
+	if spec.FailureTolerate != nil {
+		p.FailureTolerate = spec.FailureTolerate
+	}
+	if spec.PostCheck != nil {
+		p.PostCheck = spec.PostCheck
+	}
+	return p, nil
+}
+
+// Apply returns the task message with the defaults of the pipeline for the fields the task does not
+// set. The stage timeouts of the pipeline apply only if the task sets no timeout, its own stage
+// timeouts take precedence.
+func (p Pipeline) Apply(msg util.TaskMessage) util.TaskMessage {
+	if msg.TimeOutSeconds == nil || *msg.TimeOutSeconds == 0 {
+		if p.TimeoutSeconds != 0 {
+			timeout := p.TimeoutSeconds
+			msg.TimeOutSeconds = &timeout
+		}
+		if len(p.StageTimeouts) != 0 {
+			stageTimeouts := make(map[api.State]uint32, len(p.StageTimeouts)+len(msg.StageTimeouts))
+			for state, timeout := range p.StageTimeouts {
+				stageTimeouts[state] = timeout
+			}
+			for state, timeout := range msg.StageTimeouts {
+				stageTimeouts[state] = timeout
+			}
+			msg.StageTimeouts = stageTimeouts
+		}
+	}
+	if msg.Concurrency <= 0 && msg.MaxUnavailable == nil {
+		msg.Concurrency = max(p.Concurrency, 1)
+	}
+	if msg.FailureTolerate == nil {
+		msg.FailureTolerate = p.FailureTolerate
+	}
+	if len(msg.CheckItem) == 0 {
+		msg.CheckItem = p.CheckItems
+	}
+	if msg.PostCheck == nil && p.PostCheck != nil {
+		msg.PostCheck = p.PostCheck.DeepCopy()
+	}
+	return msg
+}
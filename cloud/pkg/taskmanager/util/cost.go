@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// NodeGroupLabelKey is the label the node group controller puts on the nodes of a node group
+const NodeGroupLabelKey = "apps.kubeedge.io/belonging-to"
+
+// nodeRetries counts the dispatch retries of the nodes of tasks which are not persisted yet,
+// the key is task name/node name
+var nodeRetries sync.Map
+
+// RecordNodeRetry counts a dispatch retry of the task on the node, it is added to the cost
+// of the node on its next transition.
+func RecordNodeRetry(taskName, nodeName string) {
+	key := taskName + "/" + nodeName
+	for {
+		v, loaded := nodeRetries.LoadOrStore(key, int32(1))
+		if !loaded {
+			return
+		}
+		if nodeRetries.CompareAndSwap(key, v, v.(int32)+1) {
+			return
+		}
+	}
+}
+
+func takeNodeRetries(taskName, nodeName string) int32 {
+	v, ok := nodeRetries.LoadAndDelete(taskName + "/" + nodeName)
+	if !ok {
+		return 0
+	}
+	return v.(int32)
+}
+
+// NodeGroup returns the node group the node belongs to, it is empty if the node belongs to no group
+func NodeGroup(nodeName string) string {
+	node, err := informers.GetInformersManager().GetKubeInformerFactory().Core().V1().Nodes().Lister().Get(nodeName)
+	if err != nil {
+		return ""
+	}
+	return node.Labels[NodeGroupLabelKey]
+}
+
+// AccountNodeCost returns the cost of the node after the transition of the event, previous is the cost
+// before the transition and group is the node group used if the node has no cost yet. The start time may
+// have been recorded by another cloudcore whose clock is skewed, so the duration never goes back and is
+// at least the sum of the time the edge node reported it ran each of its stages for.
+func AccountNodeCost(taskName, nodeName, group string, previous *v1alpha1.NodeCost, event fsm.Event) *v1alpha1.NodeCost {
+	now := time.Now()
+	cost := previous.DeepCopy()
+	if cost == nil {
+		cost = &v1alpha1.NodeCost{
+			StartTime: &metav1.Time{Time: now},
+			Group:     group,
+		}
+	}
+	cost.BytesDownloaded += event.BytesDownloaded
+	cost.Retries += takeNodeRetries(taskName, nodeName)
+	cost.StageSeconds += int64(event.Elapsed.Seconds())
+	if cost.StartTime != nil {
+		duration := int64(now.Sub(cost.StartTime.Time).Seconds())
+		if duration < cost.DurationSeconds {
+			duration = cost.DurationSeconds
+		}
+		if duration < cost.StageSeconds {
+			duration = cost.StageSeconds
+		}
+		cost.DurationSeconds = duration
+	}
+	return cost
+}
+
+// AggregateCost sums up the costs of the nodes of a task per task and per node group,
+// it returns nil if no node has a cost.
+func AggregateCost(costs []*v1alpha1.NodeCost) *v1alpha1.TaskCost {
+	var total *v1alpha1.TaskCost
+	groups := make(map[string]*v1alpha1.CostSummary)
+	for _, cost := range costs {
+		if cost == nil {
+			continue
+		}
+		if total == nil {
+			total = &v1alpha1.TaskCost{}
+		}
+		group, ok := groups[cost.Group]
+		if !ok {
+			group = &v1alpha1.CostSummary{Group: cost.Group}
+			groups[cost.Group] = group
+		}
+		for _, summary := range []*v1alpha1.CostSummary{&total.CostSummary, group} {
+			summary.Nodes++
+			summary.BytesDownloaded += cost.BytesDownloaded
+			summary.DurationSeconds += cost.DurationSeconds
+			summary.Retries += cost.Retries
+		}
+	}
+	if total == nil {
+		return nil
+	}
+	for _, group := range groups {
+		total.Groups = append(total.Groups, *group)
+	}
+	sort.Slice(total.Groups, func(i, j int) bool {
+		return total.Groups[i].Group < total.Groups[j].Group
+	})
+	return total
+}
+
+// RecordNodeCost exports the cost of a node which finished the task
+func RecordNodeCost(taskType string, cost *v1alpha1.NodeCost) {
+	if cost == nil {
+		return
+	}
+	monitor.TaskNodeDownloadedBytes.WithLabelValues(taskType, cost.Group).Add(float64(cost.BytesDownloaded))
+	monitor.TaskNodeDurationSeconds.WithLabelValues(taskType, cost.Group).Add(float64(cost.DurationSeconds))
+	monitor.TaskNodeRetries.WithLabelValues(taskType, cost.Group).Add(float64(cost.Retries))
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+)
+
+// RecordKindDeadLetters is the kind of the records which hold dead letters
+const RecordKindDeadLetters = "deadletters"
+
+// DeadLetter is a task message that could not be delivered to the edge node
+type DeadLetter struct {
+	NodeName string        `json:"nodeName"`
+	State    string        `json:"state"`
+	Reason   string        `json:"reason"`
+	Time     string        `json:"time"`
+	Message  model.Message `json:"message"`
+}
+
+// SaveDeadLetter persists the dead letter of the node, it replaces the former one of the same node
+func SaveDeadLetter(task TaskMessage, letter DeadLetter) error {
+	data, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %v", err)
+	}
+	return GetRecordStore().Save(task, RecordKindDeadLetters, letter.NodeName, data)
+}
+
+// ListDeadLetters returns the dead letters of the task sorted by node name
+func ListDeadLetters(taskType, taskName string) ([]DeadLetter, error) {
+	records, err := GetRecordStore().List(taskType, taskName, RecordKindDeadLetters)
+	if err != nil {
+		return nil, err
+	}
+	letters := make([]DeadLetter, 0, len(records))
+	for nodeName, data := range records {
+		var letter DeadLetter
+		if err = json.Unmarshal(data, &letter); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal dead letter of node %s: %v", nodeName, err)
+		}
+		letters = append(letters, letter)
+	}
+	sort.Slice(letters, func(i, j int) bool {
+		return letters[i].NodeName < letters[j].NodeName
+	})
+	return letters, nil
+}
+
+// DeleteDeadLetter removes the dead letter of the node from the store
+func DeleteDeadLetter(taskType, taskName, nodeName string) error {
+	return GetRecordStore().Delete(taskType, taskName, RecordKindDeadLetters, nodeName)
+}
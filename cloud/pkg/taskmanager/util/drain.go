@@ -0,0 +1,199 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// CordonedByAnnotationKey is set on the nodes cordoned by a task to the name of the task, only the
+// nodes cordoned by the task are uncordoned by it
+const CordonedByAnnotationKey = "operations.kubeedge.io/cordoned-by"
+
+// DefaultDrainTimeoutSeconds bounds the drain of a node if its timeout is not set
+const DefaultDrainTimeoutSeconds = 300
+
+// mirrorPodAnnotationKey is set on the mirror pods of the static pods of a node
+const mirrorPodAnnotationKey = "kubernetes.io/config.mirror"
+
+// drainPollInterval is how often the evicted pods are checked
+var drainPollInterval = 2 * time.Second
+
+// DrainNode cordons the node and evicts the pods selected by the drain spec, it returns once
+// the pods are gone or fails after the timeout of the spec.
+func DrainNode(kubeClient kubernetes.Interface, nodeName, taskName string, drain *v1alpha1.DrainSpec) error {
+	if err := CordonNode(kubeClient, nodeName, taskName); err != nil {
+		return fmt.Errorf("failed to cordon node %s: %v", nodeName, err)
+	}
+	podList, err := kubeClient.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list the pods of node %s: %v", nodeName, err)
+	}
+	pods, err := EvictablePods(podList.Items, drain)
+	if err != nil {
+		return err
+	}
+	timeout := time.Duration(DefaultDrainTimeoutSeconds) * time.Second
+	if drain.TimeoutSeconds != nil {
+		timeout = time.Duration(*drain.TimeoutSeconds) * time.Second
+	}
+
+	evicted := make(map[types.UID]bool, len(pods))
+	err = wait.PollImmediate(drainPollInterval, timeout, func() (bool, error) {
+		remaining := pods[:0]
+		for _, pod := range pods {
+			var gone bool
+			var err error
+			if evicted[pod.UID] {
+				gone, err = podGone(kubeClient, pod)
+			} else {
+				evicted[pod.UID], gone, err = evictPod(kubeClient, pod, drain.GracePeriodSeconds)
+			}
+			if err != nil {
+				return false, err
+			}
+			if !gone {
+				remaining = append(remaining, pod)
+			}
+		}
+		pods = remaining
+		return len(pods) == 0, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("%d pods of node %s are not evicted in %s", len(pods), nodeName, timeout)
+	}
+	return err
+}
+
+// podGone returns whether the evicted pod is deleted, a pod recreated with the same name is another pod
+func podGone(kubeClient kubernetes.Interface, pod corev1.Pod) (bool, error) {
+	current, err := kubeClient.CoreV1().Pods(pod.Namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) || (err == nil && current.UID != pod.UID) {
+		return true, nil
+	}
+	return false, err
+}
+
+// evictPod evicts the pod, it returns whether the eviction is accepted and whether the pod is gone.
+// An eviction rejected by a PodDisruptionBudget is retried.
+func evictPod(kubeClient kubernetes.Interface, pod corev1.Pod, gracePeriodSeconds *int64) (bool, bool, error) {
+	err := kubeClient.CoreV1().Pods(pod.Namespace).EvictV1(context.TODO(), &policyv1.Eviction{
+		ObjectMeta:    metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+		DeleteOptions: &metav1.DeleteOptions{GracePeriodSeconds: gracePeriodSeconds},
+	})
+	switch {
+	case err == nil:
+		return true, false, nil
+	case apierrors.IsNotFound(err):
+		return true, true, nil
+	case apierrors.IsTooManyRequests(err):
+		klog.V(4).Infof("eviction of pod %s/%s is blocked, retry it: %v", pod.Namespace, pod.Name, err)
+		return false, false, nil
+	default:
+		return false, false, fmt.Errorf("failed to evict pod %s/%s: %v", pod.Namespace, pod.Name, err)
+	}
+}
+
+// EvictablePods returns the pods evicted by the drain spec, mirror pods and finished pods are left
+// out, and so are the pods of DaemonSets unless they are evicted explicitly.
+func EvictablePods(pods []corev1.Pod, drain *v1alpha1.DrainSpec) ([]corev1.Pod, error) {
+	selector := labels.Everything()
+	if drain.PodSelector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(drain.PodSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pod selector: %v", err)
+		}
+	}
+	var evictable []corev1.Pod
+	for _, pod := range pods {
+		if _, ok := pod.Annotations[mirrorPodAnnotationKey]; ok {
+			continue
+		}
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if owner := metav1.GetControllerOf(&pod); owner != nil && owner.Kind == "DaemonSet" && !drain.EvictDaemonSetPods {
+			continue
+		}
+		if !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		evictable = append(evictable, pod)
+	}
+	return evictable, nil
+}
+
+// CordonNode marks the node unschedulable on behalf of the task, a node which is unschedulable
+// already is left unchanged so that the task does not uncordon it.
+func CordonNode(kubeClient kubernetes.Interface, nodeName, taskName string) error {
+	node, err := kubeClient.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if node.Spec.Unschedulable {
+		return nil
+	}
+	return patchNodeSchedulable(kubeClient, node, true, taskName)
+}
+
+// UncordonNode marks the node schedulable again if it was cordoned by the task
+func UncordonNode(kubeClient kubernetes.Interface, nodeName, taskName string) error {
+	node, err := kubeClient.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if node.Annotations[CordonedByAnnotationKey] != taskName {
+		return nil
+	}
+	return patchNodeSchedulable(kubeClient, node, false, nil)
+}
+
+// patchNodeSchedulable sets whether the node is unschedulable and who cordoned it, a nil cordonedBy
+// removes the annotation. The patch fails with a conflict if the node has been changed since.
+func patchNodeSchedulable(kubeClient kubernetes.Interface, node *corev1.Node, unschedulable bool, cordonedBy interface{}) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"resourceVersion": node.ResourceVersion,
+			"annotations":     map[string]interface{}{CordonedByAnnotationKey: cordonedBy},
+		},
+		"spec": map[string]interface{}{"unschedulable": unschedulable},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal patch: %v", err)
+	}
+	_, err = kubeClient.CoreV1().Nodes().Patch(context.TODO(), node.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+)
+
+const eventComponent = "taskmanager"
+
+var (
+	eventRecorder record.EventRecorder
+	recorderOnce  sync.Once
+)
+
+// GetEventRecorder returns the recorder that TaskManager emits events with
+func GetEventRecorder() record.EventRecorder {
+	recorderOnce.Do(func() {
+		broadcaster := record.NewBroadcaster()
+		broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: client.GetKubeClient().CoreV1().Events("")})
+		eventRecorder = broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: eventComponent})
+	})
+	return eventRecorder
+}
+
+// InitEventRecorder sets the recorder that TaskManager emits events with, the recorder sending the
+// events to the apiserver is used if it is not set
+func InitEventRecorder(recorder record.EventRecorder) {
+	recorderOnce.Do(func() {
+		eventRecorder = recorder
+	})
+}
+
+// TaskObjectReference returns the reference of the task object, it is nil if the task has no owner reference
+func TaskObjectReference(task TaskMessage) *corev1.ObjectReference {
+	if task.Owner == nil {
+		return nil
+	}
+	return ownerObjectReference(task.Owner)
+}
+
+func ownerObjectReference(owner *v1.OwnerReference) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		APIVersion: owner.APIVersion,
+		Kind:       owner.Kind,
+		Name:       owner.Name,
+		UID:        owner.UID,
+	}
+}
+
+// RecordTaskEvent emits an event on the task object, the event is annotated with the labels of the task
+func RecordTaskEvent(task TaskMessage, eventType, reason, messageFmt string, args ...interface{}) {
+	ref := TaskObjectReference(task)
+	if ref == nil {
+		return
+	}
+	GetEventRecorder().AnnotatedEventf(ref, TaskLabels(task), eventType, reason, messageFmt, args...)
+}
+
+// recordJobEvent emits an event on the job object of the given kind, the event is annotated with
+// the labels of the job and its name
+func recordJobEvent(task v1.Object, kind, eventType, reason, messageFmt string, args ...interface{}) {
+	annotations := make(map[string]string, len(task.GetLabels())+1)
+	for k, v := range task.GetLabels() {
+		annotations[k] = v
+	}
+	annotations[TaskNameLabelKey] = task.GetName()
+	GetEventRecorder().AnnotatedEventf(ownerObjectReference(NewTaskOwnerReference(task, kind)), annotations,
+		eventType, reason, messageFmt, args...)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// MaxExcludedNodes is the max number of excluded nodes recorded in the status of a task
+const MaxExcludedNodes = 100
+
+// ExclusionSummary counts the excluded nodes of a task by reason, e.g. "2 NotReady, 1 NotEdgeNode",
+// the most frequent reason first.
+func ExclusionSummary(excluded []v1alpha1.ExcludedNode) string {
+	counts := map[v1alpha1.ExclusionReason]int{}
+	var reasons []v1alpha1.ExclusionReason
+	for _, node := range excluded {
+		if counts[node.Reason] == 0 {
+			reasons = append(reasons, node.Reason)
+		}
+		counts[node.Reason]++
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if counts[reasons[i]] != counts[reasons[j]] {
+			return counts[reasons[i]] > counts[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+	parts := make([]string, len(reasons))
+	for i, reason := range reasons {
+		parts[i] = fmt.Sprintf("%d %s", counts[reason], reason)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	cloudcorev1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+const (
+	// transitionQueueSize is how many transitions wait to be exported at most, the new transitions
+	// are dropped once the queue is full. As many transitions which failed to be exported are kept
+	// to be posted again, the oldest of them are dropped while the store is unavailable.
+	transitionQueueSize = 10000
+	// transitionExportMaxBackoff bounds the backoff of posting the transitions again while the store fails
+	transitionExportMaxBackoff = 5 * time.Minute
+	// transitionExportErrorLimit is how much of the body of a failed response is logged
+	transitionExportErrorLimit = 4096
+)
+
+// NodeTransition is a state transition of a node of a task, as it is exported
+type NodeTransition struct {
+	Time     time.Time `json:"time"`
+	TaskType string    `json:"taskType"`
+	TaskName string    `json:"taskName"`
+	NodeName string    `json:"nodeName"`
+	State    string    `json:"state"`
+	Event    string    `json:"event,omitempty"`
+	Action   string    `json:"action,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+type transitionExporter struct {
+	config *cloudcorev1alpha1.TaskManagerTransitionExport
+	client *http.Client
+	queue  chan NodeTransition
+}
+
+var exporter *transitionExporter
+
+// InitTransitionExport initializes the export of the node transitions, nothing is exported if the
+// endpoint has no URL
+func InitTransitionExport(config *cloudcorev1alpha1.TaskManagerTransitionExport) {
+	if config == nil || config.URL == "" {
+		exporter = nil
+		return
+	}
+	if config.Format != cloudcorev1alpha1.TransitionExportFormatNDJSON && config.Format != cloudcorev1alpha1.TransitionExportFormatJSON {
+		klog.Warningf("unknown transition export format %q, export in %s", config.Format, cloudcorev1alpha1.TransitionExportFormatNDJSON)
+	}
+	exporter = &transitionExporter{
+		config: config,
+		client: &http.Client{Timeout: time.Duration(config.TimeoutSeconds) * time.Second},
+		queue:  make(chan NodeTransition, transitionQueueSize),
+	}
+}
+
+// StartTransitionExport posts the node transitions to the store in batches until stop is closed,
+// the pending transitions are posted before it returns.
+func StartTransitionExport(stop <-chan struct{}) {
+	if exporter == nil {
+		return
+	}
+	go exporter.run(stop)
+}
+
+// ExportNodeTransition queues the transition of the node of the task to be exported
+func ExportNodeTransition(taskType, taskName string, status v1alpha1.TaskStatus) {
+	e := exporter
+	if e == nil {
+		return
+	}
+	transition := NodeTransition{
+		Time:     time.Now().UTC(),
+		TaskType: taskType,
+		TaskName: taskName,
+		NodeName: status.NodeName,
+		State:    string(status.State),
+		Event:    status.Event,
+		Action:   string(status.Action),
+		Reason:   status.Reason,
+	}
+	select {
+	case e.queue <- transition:
+	default:
+		klog.Warningf("the transition export queue is full, drop the transition of node %s of task %s", status.NodeName, taskName)
+		monitor.TaskTransitionExportDropped.Inc()
+	}
+}
+
+func (e *transitionExporter) run(stop <-chan struct{}) {
+	ticker := time.NewTicker(e.flushInterval())
+	defer ticker.Stop()
+	batchSize := int(e.config.BatchSize)
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	// pending are the transitions not exported yet, the batch which failed to be posted is kept in
+	// front of them and posted again once the backoff elapsed
+	pending := make([]NodeTransition, 0, batchSize)
+	var backoff time.Duration
+	var retryAt time.Time
+	add := func(transition NodeTransition) {
+		pending = append(pending, transition)
+		if dropped := len(pending) - transitionQueueSize; dropped > 0 {
+			klog.Warningf("too many node transitions failed to be exported, drop the %d oldest of them", dropped)
+			monitor.TaskTransitionExportDropped.Add(float64(dropped))
+			pending = pending[dropped:]
+		}
+	}
+	// flush posts the pending transitions in batches, the last batch which is not full is posted
+	// only if all is set. It stops at the first batch which fails to be posted.
+	flush := func(all bool) {
+		for len(pending) != 0 && (all || len(pending) >= batchSize) && !time.Now().Before(retryAt) {
+			size := min(batchSize, len(pending))
+			if err := e.post(pending[:size]); err != nil {
+				backoff = min(max(2*backoff, e.flushInterval()), transitionExportMaxBackoff)
+				retryAt = time.Now().Add(backoff)
+				klog.Errorf("failed to export %d node transitions, retry in %s: %v", size, backoff, err)
+				return
+			}
+			backoff, retryAt = 0, time.Time{}
+			pending = pending[size:]
+		}
+	}
+	for {
+		select {
+		case <-stop:
+			for {
+				select {
+				case transition := <-e.queue:
+					add(transition)
+				default:
+					// the pending transitions are posted once more regardless of the backoff
+					retryAt = time.Time{}
+					flush(true)
+					if len(pending) != 0 {
+						klog.Warningf("drop %d node transitions which failed to be exported", len(pending))
+						monitor.TaskTransitionExportDropped.Add(float64(len(pending)))
+					}
+					return
+				}
+			}
+		case transition := <-e.queue:
+			add(transition)
+			flush(false)
+		case <-ticker.C:
+			flush(true)
+		}
+	}
+}
+
+func (e *transitionExporter) flushInterval() time.Duration {
+	if e.config.FlushIntervalSeconds <= 0 {
+		return 10 * time.Second
+	}
+	return time.Duration(e.config.FlushIntervalSeconds) * time.Second
+}
+
+func (e *transitionExporter) post(batch []NodeTransition) error {
+	var body bytes.Buffer
+	contentType := "application/x-ndjson"
+	if e.config.Format == cloudcorev1alpha1.TransitionExportFormatJSON {
+		contentType = "application/json"
+		if err := json.NewEncoder(&body).Encode(batch); err != nil {
+			return fmt.Errorf("failed to marshal transitions: %v", err)
+		}
+	} else {
+		encoder := json.NewEncoder(&body)
+		for _, transition := range batch {
+			if err := encoder.Encode(transition); err != nil {
+				return fmt.Errorf("failed to marshal transitions: %v", err)
+			}
+		}
+	}
+	req, err := http.NewRequest(http.MethodPost, e.config.URL, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	for key, value := range e.config.Headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, transitionExportErrorLimit))
+		return fmt.Errorf("transition store returned status code %d: %s", resp.StatusCode, data)
+	}
+	return nil
+}
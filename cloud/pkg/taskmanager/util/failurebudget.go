@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// DefaultFailureTolerate is the failure tolerance of the tasks which do not set one
+var DefaultFailureTolerate = intstr.FromString("10%")
+
+// MaxFailedNodes returns the failure budget of a task with the failure tolerance and the number of nodes,
+// the task fails once its failed nodes exceed it. The tolerance is a number of nodes, a percentage of
+// the nodes, e.g. 10%, or a deprecated ratio, e.g. 0.1. The default tolerance is used if it is nil.
+func MaxFailedNodes(tolerate *intstr.IntOrString, nodes int) (float64, error) {
+	if tolerate == nil {
+		tolerate = &DefaultFailureTolerate
+	}
+	if tolerate.Type == intstr.Int {
+		if tolerate.IntVal < 0 {
+			return 0, fmt.Errorf("failureTolerate %d must not be negative", tolerate.IntVal)
+		}
+		return float64(tolerate.IntVal), nil
+	}
+	if percent, ok := strings.CutSuffix(tolerate.StrVal, "%"); ok {
+		value, err := strconv.Atoi(percent)
+		if err != nil || value < 0 || value > 100 {
+			return 0, fmt.Errorf("failureTolerate %q must be a percentage between 0%% and 100%%", tolerate.StrVal)
+		}
+		return float64(nodes) * float64(value) / 100, nil
+	}
+	ratio, err := strconv.ParseFloat(tolerate.StrVal, 64)
+	if err != nil || ratio < 0 || ratio > 1 {
+		return 0, fmt.Errorf("failureTolerate %q must be a number of nodes or a percentage, e.g. 10%%", tolerate.StrVal)
+	}
+	return float64(nodes) * ratio, nil
+}
+
+// FailureThreshold returns the number of failed nodes a task with the failure budget fails at,
+// i.e. the first number of failed nodes which exceeds the budget
+func FailureThreshold(maxFailedNodes float64) int32 {
+	if maxFailedNodes < 0 {
+		return 1
+	}
+	return int32(math.Floor(maxFailedNodes)) + 1
+}
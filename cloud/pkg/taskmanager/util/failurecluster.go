@@ -0,0 +1,157 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+const (
+	// MaxFailureClusters is the max number of failure clusters recorded in the status of a task
+	MaxFailureClusters = 10
+	// MaxFailureClusterNodes is the max number of nodes listed in a failure cluster
+	MaxFailureClusterNodes = 10
+)
+
+// FailureClusters groups the failed nodes by the event they failed on, their EdgeCore version, their
+// architecture and their values of the labels, the largest cluster first. The attributes of the
+// nodes deleted from the cluster are taken from the environment they reported, if any.
+func FailureClusters(nodeStatus []v1alpha1.TaskStatus, lister corelisters.NodeLister, labels []string) []v1alpha1.FailureCluster {
+	clusters := map[string]*v1alpha1.FailureCluster{}
+	messages := map[string]map[string]int{}
+	var keys []string
+	for _, status := range nodeStatus {
+		if status.State != api.TaskFailed {
+			continue
+		}
+		cluster := v1alpha1.FailureCluster{Reason: status.Event}
+		if status.Environment != nil {
+			cluster.Architecture = status.Environment.Architecture
+		}
+		if node, err := lister.Get(status.NodeName); err == nil {
+			cluster.Version = EdgeCoreVersion(node)
+			if node.Status.NodeInfo.Architecture != "" {
+				cluster.Architecture = node.Status.NodeInfo.Architecture
+			}
+			for _, label := range labels {
+				if value, ok := node.Labels[label]; ok {
+					if cluster.Labels == nil {
+						cluster.Labels = map[string]string{}
+					}
+					cluster.Labels[label] = value
+				}
+			}
+		}
+
+		key := clusterKey(cluster, labels)
+		if _, ok := clusters[key]; !ok {
+			clusters[key] = &cluster
+			messages[key] = map[string]int{}
+			keys = append(keys, key)
+		}
+		clusters[key].Nodes++
+		clusters[key].NodeNames = append(clusters[key].NodeNames, status.NodeName)
+		messages[key][status.Reason]++
+	}
+
+	result := make([]v1alpha1.FailureCluster, 0, len(keys))
+	for _, key := range keys {
+		cluster := clusters[key]
+		cluster.Message = mostFrequent(messages[key])
+		sort.Strings(cluster.NodeNames)
+		if len(cluster.NodeNames) > MaxFailureClusterNodes {
+			cluster.NodeNames = cluster.NodeNames[:MaxFailureClusterNodes]
+		}
+		result = append(result, *cluster)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Nodes > result[j].Nodes
+	})
+	if len(result) > MaxFailureClusters {
+		result = result[:MaxFailureClusters]
+	}
+	return result
+}
+
+// FailureClusterSummary describes the failure clusters, e.g. "3 failed on Upgrade (v1.14.2, arm)"
+func FailureClusterSummary(clusters []v1alpha1.FailureCluster) string {
+	parts := make([]string, len(clusters))
+	for i, cluster := range clusters {
+		var attributes []string
+		for _, attribute := range []string{cluster.Version, cluster.Architecture} {
+			if attribute != "" {
+				attributes = append(attributes, attribute)
+			}
+		}
+		labels := make([]string, 0, len(cluster.Labels))
+		for label, value := range cluster.Labels {
+			labels = append(labels, label+"="+value)
+		}
+		sort.Strings(labels)
+		attributes = append(attributes, labels...)
+
+		parts[i] = fmt.Sprintf("%d failed on %s", cluster.Nodes, cluster.Reason)
+		if len(attributes) != 0 {
+			parts[i] += fmt.Sprintf(" (%s)", strings.Join(attributes, ", "))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// RecordFailureClusters emits a warning event on the finished task object describing its failure clusters
+func RecordFailureClusters(task metav1.Object, kind string, clusters []v1alpha1.FailureCluster) {
+	recordJobEvent(task, kind, corev1.EventTypeWarning, "FailureClusters",
+		"The failed nodes are clustered: %s", FailureClusterSummary(clusters))
+}
+
+// EdgeCoreVersion returns the EdgeCore version of the node, e.g. v1.14.2 of v1.22.6-kubeedge-v1.14.2,
+// or the kubelet version if it is not in this format
+func EdgeCoreVersion(node *corev1.Node) string {
+	version := node.Status.NodeInfo.KubeletVersion
+	if strs := strings.SplitN(version, "-", 3); len(strs) == 3 {
+		return strs[2]
+	}
+	return version
+}
+
+func clusterKey(cluster v1alpha1.FailureCluster, labels []string) string {
+	key := []string{cluster.Reason, cluster.Version, cluster.Architecture}
+	for _, label := range labels {
+		key = append(key, cluster.Labels[label])
+	}
+	return strings.Join(key, "\x00")
+}
+
+// mostFrequent returns the most frequent message, the first one by name if several are as frequent
+func mostFrequent(counts map[string]int) string {
+	var message string
+	for m, count := range counts {
+		if count > counts[message] || (count == counts[message] && m < message) {
+			message = m
+		}
+	}
+	return message
+}
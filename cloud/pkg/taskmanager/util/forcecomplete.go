@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+)
+
+// TaskForceCompleteAnnotationKey requests an unfinished node of a task to be completed manually,
+// e.g. when the node is unreachable and wedges the task. Its value is a JSON encoded ForceCompletion,
+// each change of the value is applied once and the last one stays on the task object as a record.
+const TaskForceCompleteAnnotationKey = "operations.kubeedge.io/force-complete"
+
+// ForceCompletion is the request of an admin to complete a node of a task
+type ForceCompletion struct {
+	// NodeName is the node to complete
+	NodeName string `json:"nodeName"`
+	// State is the final state of the node, Successful or Failed
+	State api.State `json:"state"`
+	// Reason is the justification of the admin, it is recorded in the node status
+	Reason string `json:"reason"`
+}
+
+// Validate returns an error if the force completion is incomplete
+func (f *ForceCompletion) Validate() error {
+	if f.NodeName == "" {
+		return fmt.Errorf("nodeName is required")
+	}
+	if f.State != api.TaskSuccessful && f.State != api.TaskFailed {
+		return fmt.Errorf("state must be %s or %s, got %q", api.TaskSuccessful, api.TaskFailed, f.State)
+	}
+	if f.Reason == "" {
+		return fmt.Errorf("a reason is required to force the node to complete")
+	}
+	return nil
+}
+
+// ParseForceCompletion decodes and validates the value of the force-complete annotation
+func ParseForceCompletion(value string) (*ForceCompletion, error) {
+	force := &ForceCompletion{}
+	if err := json.Unmarshal([]byte(value), force); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %v", TaskForceCompleteAnnotationKey, err)
+	}
+	if err := force.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %v", TaskForceCompleteAnnotationKey, err)
+	}
+	return force, nil
+}
+
+// ForceCompletionRequested returns the force completion requested by the update of the task object,
+// it is nil if the annotation is unchanged or removed.
+func ForceCompletionRequested(old, task v1.Object) (*ForceCompletion, error) {
+	value := task.GetAnnotations()[TaskForceCompleteAnnotationKey]
+	if value == "" || value == old.GetAnnotations()[TaskForceCompleteAnnotationKey] {
+		return nil, nil
+	}
+	return ParseForceCompletion(value)
+}
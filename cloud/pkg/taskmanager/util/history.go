@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	cloudcorev1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// minHistoryRecords is the least number of records the history of a node is compacted to, it holds
+// the first transition, the summary of the dropped transitions, a failure and the latest transition
+const minHistoryRecords = 4
+
+// RecordNodeHistory appends the transition of the node status to the history of the node kept in
+// its former status, and compacts the history once it exceeds the max records of the config.
+// Nothing is recorded if the transition history is disabled.
+func RecordNodeHistory(status *v1alpha1.TaskStatus, former *v1alpha1.TaskStatus, config *cloudcorev1alpha1.TaskManagerTransitionHistory) {
+	if config == nil || !config.Enable {
+		return
+	}
+	var history []v1alpha1.NodeTransitionRecord
+	if former != nil {
+		history = append(history, former.History...)
+	}
+	history = append(history, v1alpha1.NodeTransitionRecord{
+		State:  status.State,
+		Event:  status.Event,
+		Action: status.Action,
+		Time:   status.Time,
+		Reason: status.Reason,
+	})
+	status.History = CompactHistory(history, int(max(config.MaxRecords, minHistoryRecords)))
+}
+
+// CompactHistory returns the history with at most maxRecords records. The first record and the latest
+// maxRecords/2 records are kept, and the failures in between as long as they fit, the latest first.
+// The other records are summarized in a single record following the first one, it holds the latest
+// of them and counts them all.
+func CompactHistory(history []v1alpha1.NodeTransitionRecord, maxRecords int) []v1alpha1.NodeTransitionRecord {
+	if len(history) <= maxRecords {
+		return history
+	}
+	latest := maxRecords / 2
+	middle := history[1 : len(history)-latest]
+	// the first record, the summary and the latest records leave room for the failures
+	room := maxRecords - latest - 2
+	kept := make([]bool, len(middle))
+	for i := len(middle) - 1; i >= 0 && room > 0; i-- {
+		if middle[i].Compacted == 0 && middle[i].Action == api.ActionFailure {
+			kept[i] = true
+			room--
+		}
+	}
+
+	var summary v1alpha1.NodeTransitionRecord
+	var compacted int32
+	failures := make([]v1alpha1.NodeTransitionRecord, 0, len(middle))
+	for i, record := range middle {
+		if kept[i] {
+			failures = append(failures, record)
+			continue
+		}
+		compacted += max(record.Compacted, 1)
+		summary = record
+	}
+	summary.Compacted = compacted
+
+	result := make([]v1alpha1.NodeTransitionRecord, 0, maxRecords)
+	result = append(result, history[0], summary)
+	result = append(result, failures...)
+	return append(result, history[len(history)-latest:]...)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// ReplicaSetGetter gets the ReplicaSet owning a pod so that the pod is attributed to its Deployment
+type ReplicaSetGetter func(namespace, name string) (*appsv1.ReplicaSet, error)
+
+// AffectedWorkloads returns the workloads that have running pods on the given nodes
+func AffectedWorkloads(pods []*corev1.Pod, getReplicaSet ReplicaSetGetter, nodes []corev1.Node) []v1alpha1.WorkloadReference {
+	nodeSet := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		nodeSet[node.Name] = true
+	}
+
+	workloads := map[string]*v1alpha1.WorkloadReference{}
+	var keys []string
+	for _, pod := range pods {
+		if !nodeSet[pod.Spec.NodeName] || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		owner := metav1.GetControllerOf(pod)
+		if owner == nil {
+			continue
+		}
+		kind, name := owner.Kind, owner.Name
+		if kind == "ReplicaSet" {
+			rs, err := getReplicaSet(pod.Namespace, name)
+			if err == nil {
+				if rsOwner := metav1.GetControllerOf(rs); rsOwner != nil {
+					kind, name = rsOwner.Kind, rsOwner.Name
+				}
+			}
+		}
+		key := strings.Join([]string{kind, pod.Namespace, name}, "/")
+		workload, ok := workloads[key]
+		if !ok {
+			workload = &v1alpha1.WorkloadReference{
+				Kind:      kind,
+				Namespace: pod.Namespace,
+				Name:      name,
+			}
+			workloads[key] = workload
+			keys = append(keys, key)
+		}
+		workload.NodeNames = append(workload.NodeNames, pod.Spec.NodeName)
+	}
+
+	sort.Strings(keys)
+	result := make([]v1alpha1.WorkloadReference, 0, len(keys))
+	for _, key := range keys {
+		workload := workloads[key]
+		workload.NodeNames = RemoveDuplicateElement(workload.NodeNames)
+		sort.Strings(workload.NodeNames)
+		result = append(result, *workload)
+	}
+	return result
+}
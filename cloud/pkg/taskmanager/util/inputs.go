@@ -0,0 +1,268 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// The outputs every job exports once it finished
+const (
+	OutputSucceededNodes = "succeededNodes"
+	OutputFailedNodes    = "failedNodes"
+	// OutputVersion is the version a NodeUpgradeJob upgraded its nodes to
+	OutputVersion = "version"
+	// OutputImages are the images an ImagePrePullJob pulled
+	OutputImages = "images"
+)
+
+// InputRetryInterval is how often a job waiting for the jobs its inputs reference checks them again
+const InputRetryInterval = 30 * time.Second
+
+// inputWaits are the timers of the jobs waiting for the jobs their inputs reference by kind and name,
+// a timer is kept after it fired until the job stops waiting.
+var inputWaits sync.Map
+
+// inputReference matches a reference to an input in the spec of a job, e.g. $(inputs.nodes)
+var inputReference = regexp.MustCompile(`\$\(inputs\.([A-Za-z0-9_-]+)\)`)
+
+// NodeOutputs returns the outputs every job exports from the status of its nodes, the names of the
+// succeeded and the failed nodes in alphabetical order and separated by commas
+func NodeOutputs(nodes []v1alpha1.TaskStatus) map[string]string {
+	var succeeded, failed []string
+	for _, node := range nodes {
+		switch node.State {
+		case api.TaskSuccessful:
+			succeeded = append(succeeded, node.NodeName)
+		case api.TaskFailed:
+			failed = append(failed, node.NodeName)
+		}
+	}
+	sort.Strings(succeeded)
+	sort.Strings(failed)
+	return map[string]string{
+		OutputSucceededNodes: strings.Join(succeeded, ","),
+		OutputFailedNodes:    strings.Join(failed, ","),
+	}
+}
+
+// ResolveInputs resolves the inputs of a job to the outputs of the jobs they reference. The reason why
+// the job has to wait is returned instead while a referenced job does not exist or is not finished,
+// an error is returned if a finished job does not export the referenced output.
+func ResolveInputs(inputs []v1alpha1.JobInput) (map[string]string, string, error) {
+	values := make(map[string]string, len(inputs))
+	for _, input := range inputs {
+		state, outputs, err := jobOutputs(input.Kind, input.JobName)
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Sprintf("%s %s of input %s does not exist", input.Kind, input.JobName, input.Name), nil
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get %s %s of input %s: %v", input.Kind, input.JobName, input.Name, err)
+		}
+		if !fsm.TaskFinish(state) {
+			return nil, fmt.Sprintf("%s %s of input %s is not finished", input.Kind, input.JobName, input.Name), nil
+		}
+		value, ok := outputs[input.Output]
+		if !ok {
+			return nil, "", fmt.Errorf("%s %s of input %s has no output %s", input.Kind, input.JobName, input.Name, input.Output)
+		}
+		values[input.Name] = value
+	}
+	return values, "", nil
+}
+
+// WaitForInputs calls retry once InputRetryInterval passed, it returns false if the job was already waiting
+func WaitForInputs(kind, name string, retry func()) bool {
+	timer, waiting := inputWaits.Swap(kind+"/"+name, time.AfterFunc(InputRetryInterval, retry))
+	if waiting {
+		timer.(*time.Timer).Stop()
+	}
+	return !waiting
+}
+
+// StopWaitingForInputs stops the timer of the job which no longer waits for its inputs
+func StopWaitingForInputs(kind, name string) {
+	if value, ok := inputWaits.LoadAndDelete(kind + "/" + name); ok {
+		value.(*time.Timer).Stop()
+	}
+}
+
+// RecordWaitingForInputs emits an event on the job which started waiting for its inputs
+func RecordWaitingForInputs(task metav1.Object, kind, reason string) {
+	recordJobEvent(task, kind, corev1.EventTypeNormal, "WaitingForInputs",
+		"The job waits for its inputs: %s", reason)
+}
+
+// jobOutputs returns the state and the outputs of the job
+func jobOutputs(kind, name string) (api.State, map[string]string, error) {
+	operations := client.GetCRDClient().OperationsV1alpha1()
+	switch kind {
+	case "NodeUpgradeJob":
+		job, err := operations.NodeUpgradeJobs().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return "", nil, err
+		}
+		return job.Status.State, job.Status.Outputs, nil
+	case "ImagePrePullJob":
+		job, err := operations.ImagePrePullJobs().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return "", nil, err
+		}
+		return job.Status.State, job.Status.Outputs, nil
+	case "SupportBundleJob":
+		job, err := operations.SupportBundleJobs().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return "", nil, err
+		}
+		return job.Status.State, job.Status.Outputs, nil
+	case "ConfigUpdateJob":
+		job, err := operations.ConfigUpdateJobs().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return "", nil, err
+		}
+		return job.Status.State, job.Status.Outputs, nil
+	case "NodeRestartJob":
+		job, err := operations.NodeRestartJobs().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return "", nil, err
+		}
+		return job.Status.State, job.Status.Outputs, nil
+	case "DiagnoseJob":
+		job, err := operations.DiagnoseJobs().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return "", nil, err
+		}
+		return job.Status.State, job.Status.Outputs, nil
+	case "BackupJob":
+		job, err := operations.BackupJobs().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return "", nil, err
+		}
+		return job.Status.State, job.Status.Outputs, nil
+	case "RestoreJob":
+		job, err := operations.RestoreJobs().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return "", nil, err
+		}
+		return job.Status.State, job.Status.Outputs, nil
+	case "OSUpgradeJob":
+		job, err := operations.OSUpgradeJobs().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return "", nil, err
+		}
+		return job.Status.State, job.Status.Outputs, nil
+	}
+	return "", nil, fmt.Errorf("unknown job kind %s", kind)
+}
+
+// SubstituteInputs replaces the references to the inputs in the string fields of the spec, which must
+// be a pointer, with the values of the inputs. An item of a list which is exactly a reference is
+// replaced by the comma separated items of the value, e.g. ["$(inputs.nodes)"] by ["node1", "node2"].
+func SubstituteInputs(spec interface{}, values map[string]string) error {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal spec: %v", err)
+	}
+	if !inputReference.Match(data) {
+		return nil
+	}
+
+	var value interface{}
+	if err = json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("failed to unmarshal spec: %v", err)
+	}
+	if value, err = substituteValue(value, values); err != nil {
+		return err
+	}
+	if data, err = json.Marshal(value); err != nil {
+		return fmt.Errorf("failed to marshal substituted spec: %v", err)
+	}
+	return json.Unmarshal(data, spec)
+}
+
+// ExpandInputs replaces the references to the inputs in the string with the values of the inputs,
+// the unknown references are kept
+func ExpandInputs(s string, values map[string]string) string {
+	return inputReference.ReplaceAllStringFunc(s, func(reference string) string {
+		if value, ok := values[inputReference.FindStringSubmatch(reference)[1]]; ok {
+			return value
+		}
+		return reference
+	})
+}
+
+func substituteValue(value interface{}, values map[string]string) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		for _, match := range inputReference.FindAllStringSubmatch(v, -1) {
+			if _, ok := values[match[1]]; !ok {
+				return nil, fmt.Errorf("input %s is not defined", match[1])
+			}
+		}
+		return ExpandInputs(v, values), nil
+	case []interface{}:
+		items := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				if match := inputReference.FindStringSubmatch(s); match != nil && match[0] == s {
+					value, ok := values[match[1]]
+					if !ok {
+						return nil, fmt.Errorf("input %s is not defined", match[1])
+					}
+					for _, expanded := range strings.Split(value, ",") {
+						if expanded != "" {
+							items = append(items, expanded)
+						}
+					}
+					continue
+				}
+			}
+			substituted, err := substituteValue(item, values)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, substituted)
+		}
+		return items, nil
+	case map[string]interface{}:
+		for key := range v {
+			substituted, err := substituteValue(v[key], values)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = substituted
+		}
+		return v, nil
+	}
+	return value, nil
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"time"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// InMaintenanceWindow returns whether the time is within one of the daily maintenance windows,
+// and when the next of them opens otherwise. The time is always within a window if there is none.
+func InMaintenanceWindow(windows []v1alpha1.MaintenanceWindow, now time.Time) (bool, time.Time) {
+	now = now.UTC()
+	var next time.Time
+	for _, window := range windows {
+		start, err := time.Parse(v1alpha1.MaintenanceWindowStartLayout, window.Start)
+		if err != nil {
+			continue
+		}
+		opens := time.Date(now.Year(), now.Month(), now.Day(), start.Hour(), start.Minute(), 0, 0, time.UTC)
+		if opens.After(now) {
+			// the window opened yesterday may still be open
+			opens = opens.AddDate(0, 0, -1)
+		}
+		if now.Before(opens.Add(time.Duration(window.DurationMinutes) * time.Minute)) {
+			return true, time.Time{}
+		}
+		opens = opens.AddDate(0, 0, 1)
+		if next.IsZero() || opens.Before(next) {
+			next = opens
+		}
+	}
+	return next.IsZero(), next
+}
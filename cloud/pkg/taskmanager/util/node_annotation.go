@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+)
+
+// getKubeClient and getCRDClient return the clients the node locks are read and written with,
+// they are replaced in tests
+var (
+	getKubeClient = client.GetKubeClient
+	getCRDClient  = client.GetCRDClient
+)
+
+// TaskInProgressAnnotationKey is set on edge nodes while a task is operating on them,
+// its value is the name of the task. It locks the node, no other task is dispatched to
+// the node until it is removed. Schedulers and other controllers can use it to avoid
+// placing new critical work on the nodes.
+const TaskInProgressAnnotationKey = "operations.kubeedge.io/in-progress"
+
+// TaskTypeInProgressAnnotationKey is set along with TaskInProgressAnnotationKey, its value
+// is the type of the task operating on the node.
+const TaskTypeInProgressAnnotationKey = "operations.kubeedge.io/in-progress-type"
+
+// NodeBatteryLevelAnnotationKey is set on battery powered edge nodes, its value is the remaining
+// battery in percent, e.g. 80. The nodes low on battery are operated on last in the Readiness ordering.
+const NodeBatteryLevelAnnotationKey = "operations.kubeedge.io/battery-level"
+
+// LockNode annotates the node with the task operating on it unless another task holds the node,
+// in which case it returns the holder. The node held by a task whose object is deleted is taken over.
+func LockNode(nodeName, taskType, taskName string) (string, error) {
+	var holder string
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		holder = ""
+		node, err := getKubeClient().CoreV1().Nodes().Get(context.TODO(), nodeName, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		name, ok := node.Annotations[TaskInProgressAnnotationKey]
+		kind := node.Annotations[TaskTypeInProgressAnnotationKey]
+		if ok && !LockedBy(node.Annotations, taskType, taskName) && taskExists(kind, name) {
+			holder = name
+			if kind != "" {
+				holder = kind + "/" + name
+			}
+			return nil
+		}
+		return patchNodeInProgress(nodeName, node.ResourceVersion, taskName, taskType)
+	})
+	return holder, err
+}
+
+// UnmarkNodeInProgress removes the in-progress annotation of the node,
+// it is left unchanged if another task has taken the node over.
+func UnmarkNodeInProgress(nodeName, taskType, taskName string) error {
+	node, err := getKubeClient().CoreV1().Nodes().Get(context.TODO(), nodeName, v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if !LockedBy(node.Annotations, taskType, taskName) {
+		return nil
+	}
+	return patchNodeInProgress(nodeName, node.ResourceVersion, nil, nil)
+}
+
+// LockedBy returns whether the in-progress annotations of a node are set to the task. Tasks of
+// different types may have the same name, the type is compared unless the lock predates it.
+func LockedBy(annotations map[string]string, taskType, taskName string) bool {
+	name, ok := annotations[TaskInProgressAnnotationKey]
+	if !ok || name != taskName {
+		return false
+	}
+	kind := annotations[TaskTypeInProgressAnnotationKey]
+	return kind == "" || kind == taskType
+}
+
+// taskExists reports whether the object of the task holding a node still exists, the tasks of
+// types no longer registered are taken as deleted. The locks predating the type annotation are
+// looked up in every task type, the tasks which cannot be checked are taken as existing.
+func taskExists(taskType, taskName string) bool {
+	taskTypes := []string{taskType}
+	if taskType == "" {
+		taskTypes = TaskObjectTypes()
+	}
+	for _, kind := range taskTypes {
+		objects, ok := GetTaskObjects(kind)
+		if !ok {
+			continue
+		}
+		_, err := objects.Client(getCRDClient()).Get(context.TODO(), taskName)
+		if err == nil {
+			return true
+		}
+		if !apierrors.IsNotFound(err) {
+			klog.Warningf("failed to check task %s/%s: %v", kind, taskName, err)
+			return true
+		}
+	}
+	return false
+}
+
+// patchNodeInProgress sets the in-progress annotations of the node to the task, nil values remove them.
+// The patch fails with a conflict if resourceVersion is set and the node has been changed since.
+func patchNodeInProgress(nodeName, resourceVersion string, taskName, taskType interface{}) error {
+	metadata := map[string]interface{}{
+		"annotations": map[string]interface{}{
+			TaskInProgressAnnotationKey:     taskName,
+			TaskTypeInProgressAnnotationKey: taskType,
+		},
+	}
+	if resourceVersion != "" {
+		metadata["resourceVersion"] = resourceVersion
+	}
+	patch, err := json.Marshal(map[string]interface{}{"metadata": metadata})
+	if err != nil {
+		return fmt.Errorf("failed to marshal patch: %v", err)
+	}
+	_, err = getKubeClient().CoreV1().Nodes().Patch(context.TODO(), nodeName, types.MergePatchType, patch, v1.PatchOptions{})
+	return err
+}
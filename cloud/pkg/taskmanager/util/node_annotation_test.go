@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+	crdfake "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned/fake"
+)
+
+// useNodeLockClients replaces the clients of the node locks with the nodes and the NodeUpgradeJobs
+// until the test ends, the NodeUpgradeJobs are the task objects of TaskUpgrade
+func useNodeLockClients(t *testing.T, nodes []*corev1.Node, jobs ...string) *fake.Clientset {
+	kube, crd := getKubeClient, getCRDClient
+	t.Cleanup(func() {
+		getKubeClient, getCRDClient = kube, crd
+	})
+	kubeClient := fake.NewSimpleClientset()
+	for _, node := range nodes {
+		if _, err := kubeClient.CoreV1().Nodes().Create(context.TODO(), node, v1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create node %s: %v", node.Name, err)
+		}
+	}
+	crdClient := crdfake.NewSimpleClientset()
+	for _, job := range jobs {
+		if _, err := crdClient.OperationsV1alpha1().NodeUpgradeJobs().Create(context.TODO(),
+			&v1alpha1.NodeUpgradeJob{ObjectMeta: v1.ObjectMeta{Name: job}}, v1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create job %s: %v", job, err)
+		}
+	}
+	getKubeClient = func() kubernetes.Interface { return kubeClient }
+	getCRDClient = func() crdClientset.Interface { return crdClient }
+
+	RegisterTaskObjects(TaskUpgrade, NewTypedTaskObjects(v1alpha1.Resource("nodeupgradejobs"),
+		func(crdClient crdClientset.Interface) TypedTaskClient[*v1alpha1.NodeUpgradeJob, *v1alpha1.NodeUpgradeJobList] {
+			return crdClient.OperationsV1alpha1().NodeUpgradeJobs()
+		},
+		func(meta v1.ObjectMeta, spec v1alpha1.NodeUpgradeJobSpec) *v1alpha1.NodeUpgradeJob {
+			return &v1alpha1.NodeUpgradeJob{ObjectMeta: meta, Spec: spec}
+		},
+		func(job *v1alpha1.NodeUpgradeJob) TaskObjectStatus {
+			return TaskObjectStatus{State: job.Status.State, Reason: job.Status.Reason, Time: job.Status.Time, Status: job.Status}
+		},
+		func(job *v1alpha1.NodeUpgradeJob) *int32 {
+			return job.Spec.TTLSecondsAfterFinished
+		}))
+	return kubeClient
+}
+
+func lockedNode(name string, annotations map[string]string) *corev1.Node {
+	return &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: name, Annotations: annotations}}
+}
+
+func TestLockNode(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		jobs        []string
+		holder      string
+	}{
+		{
+			name: "free node",
+		},
+		{
+			name:        "node locked by the task",
+			annotations: map[string]string{TaskInProgressAnnotationKey: "job-1", TaskTypeInProgressAnnotationKey: TaskUpgrade},
+			jobs:        []string{"job-1"},
+		},
+		{
+			name:        "node locked by another task",
+			annotations: map[string]string{TaskInProgressAnnotationKey: "job-2", TaskTypeInProgressAnnotationKey: TaskUpgrade},
+			jobs:        []string{"job-1", "job-2"},
+			holder:      TaskUpgrade + "/job-2",
+		},
+		{
+			name:        "node locked before the type was recorded",
+			annotations: map[string]string{TaskInProgressAnnotationKey: "job-2"},
+			jobs:        []string{"job-1", "job-2"},
+			holder:      "job-2",
+		},
+		{
+			name:        "node locked by a deleted task",
+			annotations: map[string]string{TaskInProgressAnnotationKey: "job-2", TaskTypeInProgressAnnotationKey: TaskUpgrade},
+			jobs:        []string{"job-1"},
+		},
+		{
+			name:        "node locked by a task type no longer registered",
+			annotations: map[string]string{TaskInProgressAnnotationKey: "job-2", TaskTypeInProgressAnnotationKey: "firmwareupdate"},
+			jobs:        []string{"job-1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kubeClient := useNodeLockClients(t, []*corev1.Node{lockedNode("edge-1", test.annotations)}, test.jobs...)
+			holder, err := LockNode("edge-1", TaskUpgrade, "job-1")
+			if err != nil {
+				t.Fatalf("failed to lock node: %v", err)
+			}
+			if holder != test.holder {
+				t.Errorf("Got = %q, Want = %q", holder, test.holder)
+			}
+			node, _ := kubeClient.CoreV1().Nodes().Get(context.TODO(), "edge-1", v1.GetOptions{})
+			if locked := LockedBy(node.Annotations, TaskUpgrade, "job-1"); locked != (test.holder == "") {
+				t.Errorf("expected the node to be locked by the task: %t, got %v", test.holder == "", node.Annotations)
+			}
+		})
+	}
+
+	useNodeLockClients(t, nil)
+	if _, err := LockNode("edge-1", TaskUpgrade, "job-1"); err == nil {
+		t.Errorf("expected locking a missing node to fail")
+	}
+}
+
+func TestUnmarkNodeInProgress(t *testing.T) {
+	kubeClient := useNodeLockClients(t, []*corev1.Node{
+		lockedNode("edge-1", map[string]string{TaskInProgressAnnotationKey: "job-1", TaskTypeInProgressAnnotationKey: TaskUpgrade}),
+		lockedNode("edge-2", map[string]string{TaskInProgressAnnotationKey: "job-2", TaskTypeInProgressAnnotationKey: TaskUpgrade}),
+	}, "job-1", "job-2")
+
+	for _, name := range []string{"edge-1", "edge-2"} {
+		if err := UnmarkNodeInProgress(name, TaskUpgrade, "job-1"); err != nil {
+			t.Fatalf("failed to unmark node %s: %v", name, err)
+		}
+	}
+	node, _ := kubeClient.CoreV1().Nodes().Get(context.TODO(), "edge-1", v1.GetOptions{})
+	if _, ok := node.Annotations[TaskInProgressAnnotationKey]; ok {
+		t.Errorf("expected the lock of the task to be removed, got %v", node.Annotations)
+	}
+	if _, ok := node.Annotations[TaskTypeInProgressAnnotationKey]; ok {
+		t.Errorf("expected the type of the lock to be removed, got %v", node.Annotations)
+	}
+	node, _ = kubeClient.CoreV1().Nodes().Get(context.TODO(), "edge-2", v1.GetOptions{})
+	if !LockedBy(node.Annotations, TaskUpgrade, "job-2") {
+		t.Errorf("expected the node taken over by another task to stay locked, got %v", node.Annotations)
+	}
+}
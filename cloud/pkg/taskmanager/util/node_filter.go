@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	cloudcorev1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// nodeFilterRejected is the message of a node the webhook rejects without a reason
+const (
+	nodeFilterRejected = "the node is rejected by the node filter webhook"
+	// nodeFilterResponseLimit is the size of the largest response of the node filter webhook, a larger
+	// response is a failure of the webhook
+	nodeFilterResponseLimit = 1 << 20
+)
+
+// NodeFilterRequest is sent to the node filter webhook with the candidate nodes of a task
+type NodeFilterRequest struct {
+	TaskType  string            `json:"taskType"`
+	TaskName  string            `json:"taskName"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	NodeNames []string          `json:"nodeNames"`
+}
+
+// NodeFilterResponse is returned by the node filter webhook
+type NodeFilterResponse struct {
+	// NodeNames are the nodes allowed to run the task
+	NodeNames []string `json:"nodeNames"`
+	// Reasons explain why the other candidate nodes are excluded, keyed by node name
+	Reasons map[string]string `json:"reasons,omitempty"`
+}
+
+// FilterNodesByWebhook asks the external node filter webhook which candidate nodes the task can run on,
+// the other candidate nodes are excluded with the reasons the webhook gives. If the webhook fails, the
+// candidate nodes are kept or excluded as FilterFailed by the failure policy of the webhook.
+func FilterNodesByWebhook(webhook *cloudcorev1alpha1.TaskManagerNodeFilterWebhook, taskMessage TaskMessage,
+	nodes []v1.Node) ([]v1.Node, []v1alpha1.ExcludedNode) {
+	if webhook == nil || webhook.URL == "" || len(nodes) == 0 {
+		return nodes, nil
+	}
+
+	var filtered []v1.Node
+	var excluded []v1alpha1.ExcludedNode
+	resp, err := callNodeFilterWebhook(webhook, taskMessage, nodes)
+	if err != nil {
+		if webhook.FailurePolicy == cloudcorev1alpha1.NodeFilterFailurePolicyIgnore {
+			klog.Warningf("node filter webhook failed for task %s, keep all candidate nodes: %v", taskMessage.Name, err)
+			return nodes, nil
+		}
+		klog.Errorf("node filter webhook failed for task %s, exclude all candidate nodes: %v", taskMessage.Name, err)
+		for _, node := range nodes {
+			excluded = append(excluded, v1alpha1.ExcludedNode{
+				NodeName: node.Name,
+				Reason:   v1alpha1.ExclusionFilterFailed,
+				Message:  fmt.Sprintf("the node filter webhook failed: %v", err),
+			})
+		}
+		return nil, excluded
+	}
+
+	allowed := make(map[string]bool, len(resp.NodeNames))
+	for _, name := range resp.NodeNames {
+		allowed[name] = true
+	}
+	for _, node := range nodes {
+		if allowed[node.Name] {
+			filtered = append(filtered, node)
+			continue
+		}
+		reason := resp.Reasons[node.Name]
+		if reason == "" {
+			reason = nodeFilterRejected
+		}
+		klog.Infof("node %s is excluded from task %s by node filter webhook: %s", node.Name, taskMessage.Name, reason)
+		excluded = append(excluded, v1alpha1.ExcludedNode{
+			NodeName: node.Name,
+			Reason:   v1alpha1.ExclusionFiltered,
+			Message:  reason,
+		})
+	}
+	return filtered, excluded
+}
+
+// nodeFilterTimeout returns the timeout of calling the webhook, it is the default one if TimeoutSeconds is not set
+func nodeFilterTimeout(webhook *cloudcorev1alpha1.TaskManagerNodeFilterWebhook) time.Duration {
+	timeout := time.Duration(webhook.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = constants.DefaultNodeFilterWebhookTimeout * time.Second
+	}
+	return timeout
+}
+
+func callNodeFilterWebhook(webhook *cloudcorev1alpha1.TaskManagerNodeFilterWebhook, taskMessage TaskMessage, nodes []v1.Node) (*NodeFilterResponse, error) {
+	req := NodeFilterRequest{
+		TaskType:  taskMessage.Type,
+		TaskName:  taskMessage.Name,
+		Labels:    taskMessage.Labels,
+		NodeNames: make([]string, len(nodes)),
+	}
+	for i, node := range nodes {
+		req.NodeNames[i] = node.Name
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal node filter request: %v", err)
+	}
+
+	client := &http.Client{Timeout: nodeFilterTimeout(webhook)}
+	resp, err := client.Post(webhook.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, nodeFilterResponseLimit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read node filter response: %v", err)
+	}
+	if len(data) > nodeFilterResponseLimit {
+		return nil, fmt.Errorf("node filter response exceeds %d bytes", nodeFilterResponseLimit)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("node filter webhook returned status code %d: %s", resp.StatusCode, data)
+	}
+
+	var filterResp NodeFilterResponse
+	if err = json.Unmarshal(data, &filterResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal node filter response: %v", err)
+	}
+	return &filterResp, nil
+}
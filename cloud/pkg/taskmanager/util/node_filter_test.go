@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cloudcorev1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+func TestFilterNodesByWebhook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req NodeFilterRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if req.TaskName == "broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if req.TaskName == "oversized" {
+			_ = json.NewEncoder(w).Encode(NodeFilterResponse{
+				NodeNames: req.NodeNames,
+				Reasons:   map[string]string{"edge-2": strings.Repeat("x", nodeFilterResponseLimit)},
+			})
+			return
+		}
+		resp := NodeFilterResponse{Reasons: map[string]string{}}
+		for _, name := range req.NodeNames {
+			if name == "edge-2" {
+				resp.Reasons[name] = "site under construction"
+				continue
+			}
+			resp.NodeNames = append(resp.NodeNames, name)
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	nodes := []v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "edge-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "edge-2"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "edge-3"}},
+	}
+
+	failed := func(err string, names ...string) []v1alpha1.ExcludedNode {
+		var excluded []v1alpha1.ExcludedNode
+		for _, name := range names {
+			excluded = append(excluded, v1alpha1.ExcludedNode{
+				NodeName: name,
+				Reason:   v1alpha1.ExclusionFilterFailed,
+				Message:  "the node filter webhook failed: " + err,
+			})
+		}
+		return excluded
+	}
+	cases := []struct {
+		name     string
+		webhook  *cloudcorev1alpha1.TaskManagerNodeFilterWebhook
+		task     string
+		expected []string
+		excluded []v1alpha1.ExcludedNode
+	}{
+		{
+			name:     "webhook not configured",
+			webhook:  &cloudcorev1alpha1.TaskManagerNodeFilterWebhook{},
+			task:     "task",
+			expected: []string{"edge-1", "edge-2", "edge-3"},
+		},
+		{
+			name:     "nodes excluded by webhook",
+			webhook:  &cloudcorev1alpha1.TaskManagerNodeFilterWebhook{URL: server.URL, TimeoutSeconds: 1},
+			task:     "task",
+			expected: []string{"edge-1", "edge-3"},
+			excluded: []v1alpha1.ExcludedNode{{NodeName: "edge-2", Reason: v1alpha1.ExclusionFiltered, Message: "site under construction"}},
+		},
+		{
+			name:     "webhook failure",
+			webhook:  &cloudcorev1alpha1.TaskManagerNodeFilterWebhook{URL: server.URL, TimeoutSeconds: 1},
+			task:     "broken",
+			expected: nil,
+			excluded: failed("node filter webhook returned status code 500: ", "edge-1", "edge-2", "edge-3"),
+		},
+		{
+			name:     "oversized response",
+			webhook:  &cloudcorev1alpha1.TaskManagerNodeFilterWebhook{URL: server.URL, TimeoutSeconds: 1},
+			task:     "oversized",
+			expected: nil,
+			excluded: failed("node filter response exceeds 1048576 bytes", "edge-1", "edge-2", "edge-3"),
+		},
+		{
+			name:     "webhook failure ignored",
+			webhook:  &cloudcorev1alpha1.TaskManagerNodeFilterWebhook{URL: server.URL, TimeoutSeconds: 1, FailurePolicy: cloudcorev1alpha1.NodeFilterFailurePolicyIgnore},
+			task:     "broken",
+			expected: []string{"edge-1", "edge-2", "edge-3"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			filtered, excluded := FilterNodesByWebhook(c.webhook, TaskMessage{Type: TaskUpgrade, Name: c.task}, nodes)
+			if !reflect.DeepEqual(excluded, c.excluded) {
+				t.Errorf("expected excluded nodes %v, got %v", c.excluded, excluded)
+			}
+			if len(filtered) != len(c.expected) {
+				t.Fatalf("expected nodes %v, got %v", c.expected, filtered)
+			}
+			for i, node := range filtered {
+				if node.Name != c.expected[i] {
+					t.Errorf("expected nodes %v, got %v", c.expected, filtered)
+				}
+			}
+		})
+	}
+}
+
+func TestNodeFilterTimeout(t *testing.T) {
+	if timeout := nodeFilterTimeout(&cloudcorev1alpha1.TaskManagerNodeFilterWebhook{}); timeout != 10*time.Second {
+		t.Errorf("expected the default timeout 10s when it is not set, got %s", timeout)
+	}
+	if timeout := nodeFilterTimeout(&cloudcorev1alpha1.TaskManagerNodeFilterWebhook{TimeoutSeconds: 3}); timeout != 3*time.Second {
+		t.Errorf("expected timeout 3s, got %s", timeout)
+	}
+}
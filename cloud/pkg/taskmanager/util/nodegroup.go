@@ -0,0 +1,44 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+)
+
+// NodeGroupMembers returns the nodes which are members of the NodeGroup, the nodes named by the group
+// and the nodes matching its labels, in the order of the nodes.
+func NodeGroupMembers(group *appsv1alpha1.NodeGroup, nodes []*corev1.Node) []*corev1.Node {
+	named := make(map[string]bool, len(group.Spec.Nodes))
+	for _, name := range group.Spec.Nodes {
+		named[name] = true
+	}
+	var selector labels.Selector
+	if len(group.Spec.MatchLabels) != 0 {
+		selector = labels.SelectorFromSet(group.Spec.MatchLabels)
+	}
+	var members []*corev1.Node
+	for _, node := range nodes {
+		if named[node.Name] || (selector != nil && selector.Matches(labels.Set(node.Labels))) {
+			members = append(members, node)
+		}
+	}
+	return members
+}
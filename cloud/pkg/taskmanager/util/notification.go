@@ -0,0 +1,179 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+const (
+	// NotificationFinished is sent once a task reaches a terminal state
+	NotificationFinished = "Finished"
+	// NotificationFailureToleranceExceeded is sent once the failed nodes of a task exceed its failure tolerance
+	NotificationFailureToleranceExceeded = "FailureToleranceExceeded"
+
+	defaultNotificationTimeoutSeconds = 10
+	// notificationAttempts is how many times a notification is posted to a webhook before it is dropped
+	notificationAttempts = 3
+)
+
+// notificationRetryInterval is the interval between the attempts to post a notification
+var notificationRetryInterval = 2 * time.Second
+
+// TaskNotification is the summary of a task posted to the webhooks of its notification sinks
+type TaskNotification struct {
+	// Type is Finished or FailureToleranceExceeded
+	Type string `json:"type"`
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// State is the terminal state of the task, it is empty in the FailureToleranceExceeded notification
+	State api.State `json:"state,omitempty"`
+	// Reason is the reason of the last transition of the task
+	Reason string                `json:"reason,omitempty"`
+	Nodes  TaskNotificationNodes `json:"nodes"`
+	Time   time.Time             `json:"time"`
+}
+
+// TaskNotificationNodes counts the nodes of the task by their outcome
+type TaskNotificationNodes struct {
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped"`
+}
+
+// CountNotificationNodes counts the nodes by their outcome, the nodes which are removed or skipped
+// count as skipped
+func CountNotificationNodes(nodes []v1alpha1.TaskStatus) TaskNotificationNodes {
+	counts := TaskNotificationNodes{Total: len(nodes)}
+	for _, node := range nodes {
+		switch node.State {
+		case api.TaskSuccessful:
+			counts.Succeeded++
+		case api.TaskFailed:
+			counts.Failed++
+		case api.TaskSkipped, api.TaskNodeRemoved:
+			counts.Skipped++
+		}
+	}
+	return counts
+}
+
+// NotifyTaskFinished sends the Finished notification of the task to its sinks, it is called once
+// the task reaches a terminal state.
+func NotifyTaskFinished(task metav1.Object, kind string, sinks []v1alpha1.NotificationSink, state api.State, reason string, nodes []v1alpha1.TaskStatus) {
+	if len(sinks) == 0 {
+		return
+	}
+	Notify(ownerObjectReference(NewTaskOwnerReference(task, kind)), sinks, TaskNotification{
+		Type:   NotificationFinished,
+		Kind:   kind,
+		Name:   task.GetName(),
+		State:  state,
+		Reason: reason,
+		Nodes:  CountNotificationNodes(nodes),
+		Time:   time.Now().UTC(),
+	})
+}
+
+// Notify records the notification as an event of the task and posts it to the webhooks of the sinks,
+// the webhooks are posted to in the background.
+func Notify(ref *corev1.ObjectReference, sinks []v1alpha1.NotificationSink, notification TaskNotification) {
+	if len(sinks) == 0 {
+		return
+	}
+	summary := notificationSummary(notification)
+	if ref != nil {
+		GetEventRecorder().Eventf(ref, corev1.EventTypeNormal, "Task"+notification.Type, "%s", summary)
+	}
+	for _, sink := range sinks {
+		if sink.Webhook == nil {
+			continue
+		}
+		go func(sink v1alpha1.NotificationSink) {
+			err := postNotification(*sink.Webhook, notification)
+			if err == nil {
+				return
+			}
+			klog.Warningf("failed to notify sink %s of %s %s: %v", sink.Name, notification.Kind, notification.Name, err)
+			if ref != nil {
+				GetEventRecorder().Eventf(ref, corev1.EventTypeWarning, "NotificationFailed",
+					"failed to post the %s notification to sink %s: %v", notification.Type, sink.Name, err)
+			}
+		}(sink)
+	}
+}
+
+// notificationSummary returns the message of the event of the notification
+func notificationSummary(notification TaskNotification) string {
+	nodes := notification.Nodes
+	summary := fmt.Sprintf("%s %s is %s, %d/%d nodes succeeded, %d failed, %d skipped", notification.Kind, notification.Name,
+		notification.State, nodes.Succeeded, nodes.Total, nodes.Failed, nodes.Skipped)
+	if notification.Type == NotificationFailureToleranceExceeded {
+		summary = fmt.Sprintf("%s %s exceeded its failure tolerance, %d/%d nodes failed", notification.Kind, notification.Name,
+			nodes.Failed, nodes.Total)
+	}
+	if notification.Reason != "" {
+		summary += ": " + notification.Reason
+	}
+	return summary
+}
+
+// postNotification posts the notification to the webhook, it is retried a few times if it fails
+func postNotification(webhook v1alpha1.NotificationWebhook, notification TaskNotification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the notification: %v", err)
+	}
+	timeout := time.Duration(defaultNotificationTimeoutSeconds) * time.Second
+	if webhook.TimeoutSeconds != nil {
+		timeout = time.Duration(*webhook.TimeoutSeconds) * time.Second
+	}
+	client := &http.Client{Timeout: timeout}
+	for attempt := 1; ; attempt++ {
+		err = postNotificationOnce(client, webhook.URL, body)
+		if err == nil || attempt == notificationAttempts {
+			return err
+		}
+		time.Sleep(notificationRetryInterval)
+	}
+}
+
+func postNotificationOnce(client *http.Client, url string, body []byte) error {
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("notification webhook returned status code %d: %s", resp.StatusCode, data)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// OrderingSeed returns the seed the nodes of a task are shuffled with, nil if they are not shuffled.
+// The seed recorded by the task is kept, the seed of the spec is used otherwise and a random seed
+// is picked if there is none.
+func OrderingSeed(ordering v1alpha1.NodeOrdering, specSeed, recordedSeed *int64) *int64 {
+	if ordering != v1alpha1.NodeOrderingRandom {
+		return nil
+	}
+	if recordedSeed != nil {
+		return recordedSeed
+	}
+	if specSeed != nil {
+		return specSeed
+	}
+	seed := rand.New(rand.NewSource(time.Now().UnixNano())).Int63()
+	return &seed
+}
+
+// ShuffleNodes shuffles the nodes with the seed, the nodes are sorted by name first so that the same
+// nodes are always shuffled in the same order with the same seed.
+func ShuffleNodes(nodes []v1alpha1.TaskStatus, seed int64) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].NodeName < nodes[j].NodeName
+	})
+	rand.New(rand.NewSource(seed)).Shuffle(len(nodes), func(i, j int) {
+		nodes[i], nodes[j] = nodes[j], nodes[i]
+	})
+}
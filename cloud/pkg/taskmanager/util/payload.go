@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	metav1 "k8s.io/api/core/v1"
+)
+
+const templateDelim = "{{"
+
+// PayloadTemplateData is the data that task payload templates are rendered with,
+// e.g. {{ .NodeName }} or {{ index .Labels "site" }}
+type PayloadTemplateData struct {
+	NodeName    string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// RenderPayload renders the Go templates in the string fields of the task payload with the
+// variables of the node, it is only called for the tasks which set renderPayload. The payload
+// is returned as is if it contains no template.
+func RenderPayload(payload interface{}, node *metav1.Node) (interface{}, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %v", err)
+	}
+	if !bytes.Contains(data, []byte(templateDelim)) {
+		return payload, nil
+	}
+
+	var value interface{}
+	if err = json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal payload: %v", err)
+	}
+	templateData := PayloadTemplateData{
+		NodeName:    node.Name,
+		Labels:      node.Labels,
+		Annotations: node.Annotations,
+	}
+	return renderValue(value, templateData)
+}
+
+func renderValue(value interface{}, data PayloadTemplateData) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		if !strings.Contains(v, templateDelim) {
+			return v, nil
+		}
+		tmpl, err := template.New("payload").Option("missingkey=zero").Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template %q: %v", v, err)
+		}
+		var buf bytes.Buffer
+		if err = tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("failed to render template %q: %v", v, err)
+		}
+		return buf.String(), nil
+	case []interface{}:
+		for i := range v {
+			rendered, err := renderValue(v[i], data)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = rendered
+		}
+		return v, nil
+	case map[string]interface{}:
+		for key := range v {
+			rendered, err := renderValue(v[key], data)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = rendered
+		}
+		return v, nil
+	}
+	return value, nil
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// PendingRemovalTaints are the taints the cluster autoscaling tools put on a node they are about to remove
+var PendingRemovalTaints = []string{
+	// cluster-autoscaler taints the node before it drains and deletes it
+	"ToBeDeletedByClusterAutoscaler",
+	// karpenter taints the node it disrupts, the key changed in karpenter v1
+	"karpenter.sh/disruption",
+	"karpenter.sh/disrupted",
+}
+
+// PendingRemovalAnnotationKey marks a node which is about to be removed when it is set to "true",
+// it lets other tooling keep tasks away from the nodes it terminates.
+const PendingRemovalAnnotationKey = "operations.kubeedge.io/pending-removal"
+
+// PendingRemoval returns the marker showing that the node is about to be removed from the cluster,
+// it is false if the node is not slated for removal.
+func PendingRemoval(node *v1.Node) (string, bool) {
+	if node.DeletionTimestamp != nil {
+		return "the node is being deleted", true
+	}
+	if node.Annotations[PendingRemovalAnnotationKey] == "true" {
+		return "annotation " + PendingRemovalAnnotationKey, true
+	}
+	for _, taint := range node.Spec.Taints {
+		for _, key := range PendingRemovalTaints {
+			if taint.Key == key {
+				return "taint " + key, true
+			}
+		}
+	}
+	return "", false
+}
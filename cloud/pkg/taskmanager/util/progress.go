@@ -0,0 +1,47 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// NodeProgress sums up the status of the nodes of a task, it is nil if the task has no node
+func NodeProgress(nodes []v1alpha1.TaskStatus) *v1alpha1.TaskProgress {
+	if len(nodes) == 0 {
+		return nil
+	}
+	progress := &v1alpha1.TaskProgress{Total: int32(len(nodes))}
+	var finished int32
+	for _, node := range nodes {
+		switch {
+		case node.State == api.TaskSuccessful:
+			progress.Succeeded++
+		case node.State == api.TaskFailed:
+			progress.Failed++
+		case node.State != "" && !fsm.TaskFinish(node.State):
+			progress.InProgress++
+		}
+		if fsm.TaskFinish(node.State) {
+			finished++
+		}
+	}
+	progress.PercentComplete = finished * 100 / progress.Total
+	return progress
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+const (
+	// PromotionAbortAnnotationKey aborts the pending promotion of a task when it is set to "true"
+	// on the task object.
+	PromotionAbortAnnotationKey = "operations.kubeedge.io/abort-promotion"
+	// PromotedFromLabelKey is set on the task created by a promotion to the name of the promoted task
+	PromotedFromLabelKey = "operations.kubeedge.io/promoted-from"
+)
+
+// IsPromotionAborted returns whether the promotion of the task object is aborted
+func IsPromotionAborted(task metav1.Object) bool {
+	return task.GetAnnotations()[PromotionAbortAnnotationKey] == "true"
+}
+
+// ParseSuccessThreshold returns the success threshold of the promotion, it is 1 if unset
+func ParseSuccessThreshold(promotion *v1alpha1.PromotionSpec) (float64, error) {
+	if promotion.SuccessThreshold == "" {
+		return 1, nil
+	}
+	threshold, err := strconv.ParseFloat(promotion.SuccessThreshold, 64)
+	if err != nil || threshold < 0 || threshold > 1 {
+		return 0, fmt.Errorf("successThreshold %q must be a ratio between 0 and 1", promotion.SuccessThreshold)
+	}
+	return threshold, nil
+}
+
+// CheckPromotion returns an error explaining why the task with the node status may not be promoted
+func CheckPromotion(promotion *v1alpha1.PromotionSpec, status []v1alpha1.TaskStatus) error {
+	threshold, err := ParseSuccessThreshold(promotion)
+	if err != nil {
+		return err
+	}
+	// the nodes removed from the cluster or skipped are not part of the outcome
+	var successful, total int
+	for _, node := range status {
+		switch node.State {
+		case api.TaskNodeRemoved, api.TaskSkipped:
+			continue
+		case api.TaskSuccessful:
+			successful++
+		}
+		total++
+	}
+	if total == 0 {
+		return fmt.Errorf("no node was upgraded")
+	}
+	ratio := float64(successful) / float64(total)
+	if ratio < threshold {
+		return fmt.Errorf("success ratio %.2f (%d/%d) is below the threshold %v", ratio, successful, total, threshold)
+	}
+	return nil
+}
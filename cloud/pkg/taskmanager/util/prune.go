@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	cloudcorev1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// IsNodeStatusFieldPruned returns whether the field of the node status in the state is not persisted
+func IsNodeStatusFieldPruned(state api.State, field string, prunedFields []string) bool {
+	if fsm.TaskFinish(state) {
+		return false
+	}
+	for _, f := range prunedFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// PruneNodeStatus drops the common fields of the node status which are not persisted,
+// it returns whether any field is dropped.
+func PruneNodeStatus(status *v1alpha1.TaskStatus, prunedFields []string) bool {
+	var pruned bool
+	if status.Reason != "" && IsNodeStatusFieldPruned(status.State, cloudcorev1alpha1.NodeStatusFieldReason, prunedFields) {
+		status.Reason = ""
+		pruned = true
+	}
+	if status.Time != "" && IsNodeStatusFieldPruned(status.State, cloudcorev1alpha1.NodeStatusFieldTime, prunedFields) {
+		status.Time = ""
+		pruned = true
+	}
+	return pruned
+}
+
+// RecordNodeTransition exports the transition of the node status of the task, the full detail
+// including the reason, which may be pruned from the persisted status, is emitted as an event
+// on the task object.
+func RecordNodeTransition(task v1.Object, kind, taskType string, status v1alpha1.TaskStatus) {
+	monitor.TaskNodeTransitions.WithLabelValues(taskType, string(status.State), string(status.Action)).Inc()
+	ExportNodeTransition(taskType, task.GetName(), status)
+	eventType, reason := nodeTransitionEvent(status)
+	recordJobEvent(task, kind, eventType, reason,
+		"node %s turns to %s on %s %s at %s: %s", status.NodeName, status.State, status.Event, status.Action, status.Time, status.Reason)
+}
+
+// RecordTaskTransition emits an event on the task object when the task turns to another state
+func RecordTaskTransition(task v1.Object, kind string, previous, state api.State, event fsm.Event) {
+	if previous == state {
+		return
+	}
+	eventType := corev1.EventTypeNormal
+	if state == api.TaskFailed || state == api.TaskDeadlineExceeded {
+		eventType = corev1.EventTypeWarning
+	}
+	recordJobEvent(task, kind, eventType, "Task"+string(state),
+		"task turns from %s to %s on %s %s: %s", previous, state, event.Type, event.Action, event.Msg)
+}
+
+// nodeTransitionEvent returns the type and the reason of the event of the node status, e.g.
+// NodeUpgrading, the failures and the timeouts of the node are warnings.
+func nodeTransitionEvent(status v1alpha1.TaskStatus) (string, string) {
+	switch {
+	case status.Event == api.EventTimeOut:
+		return corev1.EventTypeWarning, "NodeTimeout"
+	case status.State == api.TaskFailed:
+		return corev1.EventTypeWarning, "NodeFailed"
+	default:
+		return corev1.EventTypeNormal, "Node" + string(status.State)
+	}
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/common/constants"
+	cloudcorev1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+)
+
+// RecordStore persists the records TaskManager keeps for the nodes of tasks, such as dead letters.
+// The records of a task are grouped by kind, a node has at most one record of each kind.
+type RecordStore interface {
+	// Save stores the record of the node, it replaces the former one of the same kind
+	Save(task TaskMessage, kind, nodeName string, data []byte) error
+	// List returns the records of the kind of the task by node name
+	List(taskType, taskName, kind string) (map[string][]byte, error)
+	// Delete removes the record of the kind of the node
+	Delete(taskType, taskName, kind, nodeName string) error
+	// Purge removes all records of the deleted task
+	Purge(taskType, taskName string) error
+}
+
+var recordStore RecordStore = configMapRecordStore{}
+
+// InitRecordStore sets up the record store configured for TaskManager
+func InitRecordStore(config *cloudcorev1alpha1.TaskManagerRecordStore) error {
+	if config == nil {
+		return nil
+	}
+	switch config.Backend {
+	case "", cloudcorev1alpha1.RecordStoreBackendConfigMap:
+		recordStore = configMapRecordStore{}
+	case cloudcorev1alpha1.RecordStoreBackendAnnotation:
+		recordStore = annotationRecordStore{}
+	case cloudcorev1alpha1.RecordStoreBackendS3:
+		store, err := newS3RecordStore(config.S3)
+		if err != nil {
+			return err
+		}
+		recordStore = store
+	default:
+		return fmt.Errorf("unsupported record store backend %q", config.Backend)
+	}
+	return nil
+}
+
+// GetRecordStore returns the record store of TaskManager
+func GetRecordStore() RecordStore {
+	return recordStore
+}
+
+// RecordConfigMapName returns the name of the ConfigMap which stores the records of the kind of the task
+func RecordConfigMapName(taskType, taskName, kind string) string {
+	return fmt.Sprintf("%s-%s-%s", taskType, taskName, kind)
+}
+
+// configMapRecordStore stores the records of each kind of a task in a ConfigMap of the kubeedge
+// namespace, the ConfigMap is owned by the task and garbage-collected with it.
+type configMapRecordStore struct{}
+
+func (configMapRecordStore) Save(task TaskMessage, kind, nodeName string, data []byte) error {
+	name := RecordConfigMapName(task.Type, task.Name, kind)
+	configMaps := client.GetKubeClient().CoreV1().ConfigMaps(constants.SystemNamespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := configMaps.Get(context.TODO(), name, v1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			cm = &corev1.ConfigMap{
+				ObjectMeta: TaskObjectMeta(name, constants.SystemNamespace, task),
+				Data:       map[string]string{nodeName: string(data)},
+			}
+			_, err = configMaps.Create(context.TODO(), cm, v1.CreateOptions{})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[nodeName] = string(data)
+		_, err = configMaps.Update(context.TODO(), cm, v1.UpdateOptions{})
+		return err
+	})
+}
+
+func (configMapRecordStore) List(taskType, taskName, kind string) (map[string][]byte, error) {
+	cm, err := client.GetKubeClient().CoreV1().ConfigMaps(constants.SystemNamespace).
+		Get(context.TODO(), RecordConfigMapName(taskType, taskName, kind), v1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	records := make(map[string][]byte, len(cm.Data))
+	for nodeName, data := range cm.Data {
+		records[nodeName] = []byte(data)
+	}
+	return records, nil
+}
+
+func (configMapRecordStore) Delete(taskType, taskName, kind, nodeName string) error {
+	configMaps := client.GetKubeClient().CoreV1().ConfigMaps(constants.SystemNamespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := configMaps.Get(context.TODO(), RecordConfigMapName(taskType, taskName, kind), v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if _, ok := cm.Data[nodeName]; !ok {
+			return nil
+		}
+		delete(cm.Data, nodeName)
+		_, err = configMaps.Update(context.TODO(), cm, v1.UpdateOptions{})
+		return err
+	})
+}
+
+func (configMapRecordStore) Purge(_, _ string) error {
+	return nil
+}
+
+// RecordAnnotationKey returns the annotation of the task object which stores the records of the kind
+func RecordAnnotationKey(kind string) string {
+	return "operations.kubeedge.io/" + kind
+}
+
+// annotationRecordStore stores the records of each kind of a task as a JSON object keyed by
+// node name in an annotation of the task object.
+type annotationRecordStore struct{}
+
+func (s annotationRecordStore) Save(task TaskMessage, kind, nodeName string, data []byte) error {
+	return s.update(task.Type, task.Name, kind, func(records map[string]string) bool {
+		records[nodeName] = string(data)
+		return true
+	})
+}
+
+func (annotationRecordStore) List(taskType, taskName, kind string) (map[string][]byte, error) {
+	taskClient, err := taskObjectClient(taskType)
+	if err != nil {
+		return nil, err
+	}
+	object, err := taskClient.Get(context.TODO(), taskName)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := object.GetAnnotations()[RecordAnnotationKey(kind)]
+	if !ok {
+		return nil, nil
+	}
+	records := map[string]string{}
+	if err = json.Unmarshal([]byte(value), &records); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s of task %s: %v", kind, taskName, err)
+	}
+	result := make(map[string][]byte, len(records))
+	for nodeName, data := range records {
+		result[nodeName] = []byte(data)
+	}
+	return result, nil
+}
+
+func (s annotationRecordStore) Delete(taskType, taskName, kind, nodeName string) error {
+	return s.update(taskType, taskName, kind, func(records map[string]string) bool {
+		if _, ok := records[nodeName]; !ok {
+			return false
+		}
+		delete(records, nodeName)
+		return true
+	})
+}
+
+func (annotationRecordStore) Purge(_, _ string) error {
+	return nil
+}
+
+// update modifies the records of the kind of the task with modify, the task object is
+// patched only if modify returns true.
+func (annotationRecordStore) update(taskType, taskName, kind string, modify func(map[string]string) bool) error {
+	key := RecordAnnotationKey(kind)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		taskClient, err := taskObjectClient(taskType)
+		if err != nil {
+			return err
+		}
+		object, err := taskClient.Get(context.TODO(), taskName)
+		if err != nil {
+			return err
+		}
+		records := map[string]string{}
+		if value, ok := object.GetAnnotations()[key]; ok {
+			if err = json.Unmarshal([]byte(value), &records); err != nil {
+				return fmt.Errorf("failed to unmarshal %s of task %s: %v", kind, taskName, err)
+			}
+		}
+		if !modify(records) {
+			return nil
+		}
+		var value interface{}
+		if len(records) != 0 {
+			data, err := json.Marshal(records)
+			if err != nil {
+				return err
+			}
+			value = string(data)
+		}
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"resourceVersion": object.GetResourceVersion(),
+				"annotations":     map[string]interface{}{key: value},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal patch: %v", err)
+		}
+		return taskClient.Patch(context.TODO(), taskName, patch)
+	})
+}
+
+// taskObjectClient returns the client of the task objects of the task type
+func taskObjectClient(taskType string) (TaskObjectClient, error) {
+	objects, ok := GetTaskObjects(taskType)
+	if !ok {
+		return nil, fmt.Errorf("task type %s does not support the annotation record store", taskType)
+	}
+	return objects.Client(client.GetCRDClient()), nil
+}
@@ -0,0 +1,287 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/common/constants"
+	cloudcorev1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+)
+
+const (
+	defaultS3Region = "us-east-1"
+	s3Timeout       = 30 * time.Second
+)
+
+// s3RecordStore stores each record as an object of an S3-compatible bucket,
+// the requests are signed with AWS Signature Version 4.
+type s3RecordStore struct {
+	endpoint        *url.URL
+	bucket          string
+	region          string
+	prefix          string
+	accessKeyID     string
+	secretAccessKey string
+	client          *http.Client
+	// now returns the signing time, it is replaced in tests
+	now func() time.Time
+}
+
+func newS3RecordStore(config *cloudcorev1alpha1.TaskManagerS3RecordStore) (*s3RecordStore, error) {
+	if config == nil || config.Endpoint == "" || config.Bucket == "" {
+		return nil, fmt.Errorf("endpoint and bucket of the S3 record store are required")
+	}
+	if config.CredentialsSecret == "" {
+		return nil, fmt.Errorf("credentialsSecret of the S3 record store is required")
+	}
+	secret, err := client.GetKubeClient().CoreV1().Secrets(constants.SystemNamespace).
+		Get(context.TODO(), config.CredentialsSecret, v1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credentials of the S3 record store: %v", err)
+	}
+	return newS3RecordStoreWithCredentials(config, string(secret.Data["accessKeyID"]), string(secret.Data["secretAccessKey"]))
+}
+
+func newS3RecordStoreWithCredentials(config *cloudcorev1alpha1.TaskManagerS3RecordStore, accessKeyID, secretAccessKey string) (*s3RecordStore, error) {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint %q of the S3 record store", config.Endpoint)
+	}
+	if accessKeyID == "" || secretAccessKey == "" {
+		return nil, fmt.Errorf("accessKeyID and secretAccessKey of the S3 record store are required")
+	}
+	region := config.Region
+	if region == "" {
+		region = defaultS3Region
+	}
+	return &s3RecordStore{
+		endpoint:        endpoint,
+		bucket:          config.Bucket,
+		region:          region,
+		prefix:          config.Prefix,
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		client:          &http.Client{Timeout: s3Timeout},
+		now:             time.Now,
+	}, nil
+}
+
+func (s *s3RecordStore) taskPrefix(taskType, taskName string) string {
+	return fmt.Sprintf("%s%s/%s/", s.prefix, taskType, taskName)
+}
+
+func (s *s3RecordStore) kindPrefix(taskType, taskName, kind string) string {
+	return s.taskPrefix(taskType, taskName) + kind + "/"
+}
+
+func (s *s3RecordStore) Save(task TaskMessage, kind, nodeName string, data []byte) error {
+	_, err := s.do(http.MethodPut, s.kindPrefix(task.Type, task.Name, kind)+nodeName, nil, data)
+	return err
+}
+
+func (s *s3RecordStore) List(taskType, taskName, kind string) (map[string][]byte, error) {
+	prefix := s.kindPrefix(taskType, taskName, kind)
+	keys, err := s.listKeys(prefix)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	records := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		data, err := s.do(http.MethodGet, key, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		records[strings.TrimPrefix(key, prefix)] = data
+	}
+	return records, nil
+}
+
+func (s *s3RecordStore) Delete(taskType, taskName, kind, nodeName string) error {
+	_, err := s.do(http.MethodDelete, s.kindPrefix(taskType, taskName, kind)+nodeName, nil, nil)
+	return err
+}
+
+func (s *s3RecordStore) Purge(taskType, taskName string) error {
+	keys, err := s.listKeys(s.taskPrefix(taskType, taskName))
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if _, err = s.do(http.MethodDelete, key, nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// listKeys returns the keys of all objects with the prefix
+func (s *s3RecordStore) listKeys(prefix string) ([]string, error) {
+	var keys []string
+	query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+	for {
+		data, err := s.do(http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		var result listBucketResult
+		if err = xml.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("failed to decode object list: %v", err)
+		}
+		for _, content := range result.Contents {
+			keys = append(keys, content.Key)
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return keys, nil
+		}
+		query.Set("continuation-token", result.NextContinuationToken)
+	}
+}
+
+// do sends a signed request for the object key, an empty key addresses the bucket
+func (s *s3RecordStore) do(method, key string, query url.Values, body []byte) ([]byte, error) {
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawQuery = canonicalQuery(query)
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, body)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound && method == http.MethodDelete {
+		return nil, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s %s failed with status %d: %s", method, u.Path, resp.StatusCode, data)
+	}
+	return data, nil
+}
+
+// sign adds the AWS Signature Version 4 authorization of the request,
+// the host and all headers of the request are signed.
+func (s *s3RecordStore) sign(req *http.Request, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretAccessKey), date)
+	for _, part := range []string{s.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery encodes the query sorted by key with the escaping required by Signature Version 4
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, key := range keys {
+		for _, value := range query[key] {
+			parts = append(parts, uriEncode(key)+"="+uriEncode(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func uriEncode(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
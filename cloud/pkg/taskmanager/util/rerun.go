@@ -0,0 +1,40 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"time"
+
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// RerunRequested returns whether the finished task is to run again, the Rerun of its spec
+// differs from the one it last ran with. A task which is not finished is never rerun.
+func RerunRequested(state api.State, rerun, observedRerun int64) bool {
+	return fsm.TaskFinish(state) && rerun != observedRerun
+}
+
+// ActiveDeadline returns how long the task runs before it is finished DeadlineExceeded, it is zero if the
+// task has no deadline. The executor of the task counts it on the monotonic clock of its cloudcore rather
+// than from a start time recorded by another cloudcore, whose clock may be skewed.
+func ActiveDeadline(activeDeadlineSeconds *int64) time.Duration {
+	if activeDeadlineSeconds == nil || *activeDeadlineSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(*activeDeadlineSeconds) * time.Second
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// UpgradeConcurrency returns the number of nodes the NodeUpgradeJob upgrades at the same time, or
+// the maxUnavailable its batches are sized by from its nodes if it has a rolling strategy or its
+// concurrency is a percentage. The concurrency is 0 if it is not set, the default of the task type
+// then applies.
+func UpgradeConcurrency(spec v1alpha1.NodeUpgradeJobSpec) (int32, *intstr.IntOrString) {
+	if spec.RollingStrategy != nil {
+		return 1, spec.RollingStrategy.MaxUnavailable
+	}
+	if spec.Concurrency == nil {
+		return 0, nil
+	}
+	if spec.Concurrency.Type == intstr.String {
+		return 1, spec.Concurrency
+	}
+	if spec.Concurrency.IntVal <= 0 {
+		return 0, nil
+	}
+	return spec.Concurrency.IntVal, nil
+}
+
+// RollingBatchSize returns how many of the nodes are run at the same time with maxUnavailable,
+// a percentage is rounded down and one node is run at least.
+func RollingBatchSize(maxUnavailable *intstr.IntOrString, nodes int) (int, error) {
+	if maxUnavailable == nil {
+		return 0, fmt.Errorf("maxUnavailable is not set")
+	}
+	size, err := intstr.GetScaledValueFromIntOrPercent(maxUnavailable, nodes, false)
+	if err != nil {
+		return 0, fmt.Errorf("invalid maxUnavailable %s: %v", maxUnavailable.String(), err)
+	}
+	if size < 1 {
+		size = 1
+	}
+	return size, nil
+}
+
+// BatchSize returns how many of the nodes of a task are run at the same time, the batches are sized
+// by maxUnavailable if it is set and by the concurrency otherwise, one node is run at least.
+func BatchSize(concurrency int32, maxUnavailable *intstr.IntOrString, nodes int) (int, error) {
+	if maxUnavailable != nil {
+		return RollingBatchSize(maxUnavailable, nodes)
+	}
+	return int(max(concurrency, 1)), nil
+}
+
+// Batches splits the nodes into the batches of the given size run one after another
+func Batches(nodes []string, size int) [][]string {
+	var batches [][]string
+	for start := 0; start < len(nodes); start += size {
+		end := min(start+size, len(nodes))
+		batches = append(batches, nodes[start:end])
+	}
+	return batches
+}
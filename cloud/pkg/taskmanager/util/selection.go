@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// NodeLister looks up the nodes and the node groups the tasks select their nodes with, TaskManager
+// looks them up in its informers and "keadm ctl task plan" in the apiserver.
+type NodeLister interface {
+	GetNode(name string) (*corev1.Node, error)
+	ListNodes(selector labels.Selector) ([]*corev1.Node, error)
+	GetNodeGroup(name string) (*appsv1alpha1.NodeGroup, error)
+}
+
+// SelectNodes returns the nodes named by a task, or else the nodes matching its label selector, and the
+// members of its node groups. No node is selected while any named node is missing, the missing nodes
+// are returned excluded with the error.
+func SelectNodes(lister NodeLister, nodeNames []string, labelSelector *metav1.LabelSelector,
+	nodeGroups []string) ([]*corev1.Node, []v1alpha1.ExcludedNode, error) {
+	var selected []*corev1.Node
+	if len(nodeNames) != 0 {
+		var missing []v1alpha1.ExcludedNode
+		for _, name := range nodeNames {
+			node, err := lister.GetNode(name)
+			if err != nil {
+				missing = append(missing, v1alpha1.ExcludedNode{NodeName: name, Reason: v1alpha1.ExclusionNotFound, Message: err.Error()})
+				continue
+			}
+			selected = append(selected, node)
+		}
+		if len(missing) != 0 {
+			return nil, missing, fmt.Errorf("failed to get %d of the nodes with names %v", len(missing), nodeNames)
+		}
+	} else if labelSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+		if err != nil {
+			return nil, nil, fmt.Errorf("labelSelector(%s) is not valid: %v", labelSelector, err)
+		}
+		nodes, err := lister.ListNodes(selector)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get nodes with label %s: %v", selector.String(), err)
+		}
+		selected = nodes
+	}
+
+	if len(nodeGroups) != 0 {
+		members, err := nodeGroupMembers(lister, nodeGroups)
+		if err != nil {
+			return nil, nil, err
+		}
+		names := make(map[string]bool, len(selected))
+		for _, node := range selected {
+			names[node.Name] = true
+		}
+		for _, node := range members {
+			if !names[node.Name] {
+				names[node.Name] = true
+				selected = append(selected, node)
+			}
+		}
+	}
+	return selected, nil, nil
+}
+
+// nodeGroupMembers returns the current members of the node groups, the membership is resolved
+// every time so that the nodes joining or leaving the groups are taken into account.
+func nodeGroupMembers(lister NodeLister, nodeGroups []string) ([]*corev1.Node, error) {
+	nodes, err := lister.ListNodes(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %v", err)
+	}
+	var members []*corev1.Node
+	for _, name := range nodeGroups {
+		group, err := lister.GetNodeGroup(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get node group %s: %v", name, err)
+		}
+		members = append(members, NodeGroupMembers(group, nodes)...)
+	}
+	return members, nil
+}
+
+// CandidateNodes returns the selected nodes the tasks can run on, the nodes which are not edge
+// nodes or are not ready are excluded.
+func CandidateNodes(nodes []*corev1.Node) ([]corev1.Node, []v1alpha1.ExcludedNode) {
+	var candidates []corev1.Node
+	var excluded []v1alpha1.ExcludedNode
+	for _, node := range nodes {
+		if !IsEdgeNode(node) {
+			klog.Warningf("Node(%s) is not edge node", node.Name)
+			excluded = append(excluded, v1alpha1.ExcludedNode{NodeName: node.Name, Reason: v1alpha1.ExclusionNotEdgeNode})
+			continue
+		}
+		if !isNodeReady(node) {
+			klog.Warningf("Node(%s) is in NotReady state", node.Name)
+			excluded = append(excluded, v1alpha1.ExcludedNode{NodeName: node.Name, Reason: v1alpha1.ExclusionNotReady})
+			continue
+		}
+		candidates = append(candidates, *node)
+	}
+	return candidates, excluded
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady && condition.Status != corev1.ConditionTrue {
+			return false
+		}
+	}
+	return true
+}
+
+// UpgradeExclusion returns the reason why the node is not upgraded, or an empty reason if it needs upgrade
+func UpgradeExclusion(node corev1.Node, upgradeVersion string) (v1alpha1.ExclusionReason, string) {
+	if FilterVersion(node.Status.NodeInfo.KubeletVersion, upgradeVersion) {
+		klog.Warningf("Node(%s) version(%s) already on the expected version %s.", node.Name, node.Status.NodeInfo.KubeletVersion, upgradeVersion)
+		return v1alpha1.ExclusionUpToDate, fmt.Sprintf("the node is already on version %s", node.Status.NodeInfo.KubeletVersion)
+	}
+
+	// if node is in Upgrading state, don't need upgrade
+	if _, ok := node.Labels[NodeUpgradeJobStatusKey]; ok {
+		klog.Warningf("Node(%s) is in upgrade state", node.Name)
+		return v1alpha1.ExclusionUpgrading, "the node is being upgraded by another NodeUpgradeJob"
+	}
+
+	return "", ""
+}
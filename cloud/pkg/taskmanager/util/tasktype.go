@@ -0,0 +1,202 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+)
+
+// TaskObject is the object recording a task, e.g. a NodeUpgradeJob
+type TaskObject interface {
+	runtime.Object
+	metav1.Object
+}
+
+// TaskObjectClient reads and writes the task objects of a task type
+type TaskObjectClient interface {
+	Get(ctx context.Context, name string) (TaskObject, error)
+	Create(ctx context.Context, object TaskObject) (TaskObject, error)
+	// Patch applies the merge patch to the task object
+	Patch(ctx context.Context, name string, patch []byte) error
+	List(ctx context.Context, options metav1.ListOptions) (runtime.Object, error)
+	Watch(ctx context.Context, options metav1.ListOptions) (watch.Interface, error)
+	// Delete deletes the task object if it is still of the resource version
+	Delete(ctx context.Context, name, resourceVersion string) error
+}
+
+// TaskObjectStatus is the status of a task object
+type TaskObjectStatus struct {
+	State  api.State
+	Reason string
+	// Time is the time of the last state transition of the task, i.e. when it finished once it is finished
+	Time string
+	// NodeStatus is the status of the nodes of the task
+	NodeStatus []v1alpha1.TaskStatus
+	// Status is the status of the task object as is, it is served by the task status API
+	Status interface{}
+}
+
+// TaskObjects are the hooks of a task type on its task objects. The parts of TaskManager shared by
+// all task types, e.g. the record store, the node locks and the task status API, work on the task
+// objects through them, so they support the task types registered out of tree as well.
+type TaskObjects struct {
+	// Resource is the resource of the task objects, e.g. nodeupgradejobs.operations.kubeedge.io
+	Resource schema.GroupResource
+	// Client returns the client of the task objects with the clientset of KubeEdge, the task types
+	// whose CRDs are out of the clientset return a client of their own
+	Client func(crdClient crdClientset.Interface) TaskObjectClient
+	// NewObject returns an empty task object, the objects watched with the client are of its type
+	NewObject func() TaskObject
+	// NewTask returns the task object of the spec, spec is a pointer to the spec of the task objects
+	NewTask func(meta metav1.ObjectMeta, spec interface{}) (TaskObject, error)
+	// Status returns the status of the task object
+	Status func(object TaskObject) TaskObjectStatus
+	// TTLSecondsAfterFinished returns how long the task object is kept once it is finished, it is nil
+	// if the task object is not deleted, or not yet, e.g. while it is still needed after it finished
+	TTLSecondsAfterFinished func(object TaskObject) *int32
+}
+
+var (
+	taskObjects     = map[string]TaskObjects{}
+	taskObjectsLock sync.RWMutex
+)
+
+// RegisterTaskObjects registers the hooks of the task type on its task objects
+func RegisterTaskObjects(taskType string, objects TaskObjects) {
+	taskObjectsLock.Lock()
+	defer taskObjectsLock.Unlock()
+	taskObjects[taskType] = objects
+}
+
+// GetTaskObjects returns the hooks of the task type on its task objects, it is false if the
+// task type is not registered
+func GetTaskObjects(taskType string) (TaskObjects, bool) {
+	taskObjectsLock.RLock()
+	defer taskObjectsLock.RUnlock()
+	objects, ok := taskObjects[taskType]
+	return objects, ok
+}
+
+// TaskObjectTypes returns the registered task types in order
+func TaskObjectTypes() []string {
+	taskObjectsLock.RLock()
+	defer taskObjectsLock.RUnlock()
+	taskTypes := make([]string, 0, len(taskObjects))
+	for taskType := range taskObjects {
+		taskTypes = append(taskTypes, taskType)
+	}
+	sort.Strings(taskTypes)
+	return taskTypes
+}
+
+// TypedTaskClient is the generated client of the task objects T listed in L, e.g. the client
+// of NodeUpgradeJobs
+type TypedTaskClient[T TaskObject, L runtime.Object] interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (T, error)
+	Create(ctx context.Context, object T, opts metav1.CreateOptions) (T, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (T, error)
+	List(ctx context.Context, opts metav1.ListOptions) (L, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+}
+
+// NewTypedTaskObjects returns the TaskObjects of the task objects T with the spec S. client returns
+// the generated client of the task objects, newTask builds a task object of the spec and ttl returns
+// the ttlSecondsAfterFinished of a task object.
+func NewTypedTaskObjects[T TaskObject, L runtime.Object, S any](resource schema.GroupResource,
+	client func(crdClient crdClientset.Interface) TypedTaskClient[T, L],
+	newTask func(meta metav1.ObjectMeta, spec S) T,
+	status func(object T) TaskObjectStatus,
+	ttl func(object T) *int32) TaskObjects {
+	return TaskObjects{
+		Resource: resource,
+		Client: func(crdClient crdClientset.Interface) TaskObjectClient {
+			return typedTaskClient[T, L]{client: client(crdClient)}
+		},
+		NewObject: func() TaskObject {
+			var spec S
+			return newTask(metav1.ObjectMeta{}, spec)
+		},
+		NewTask: func(meta metav1.ObjectMeta, spec interface{}) (TaskObject, error) {
+			s, ok := spec.(*S)
+			if !ok || s == nil {
+				return nil, fmt.Errorf("the spec of %s must be a non-nil %T, got %T", resource, s, spec)
+			}
+			return newTask(meta, *s), nil
+		},
+		Status: func(object TaskObject) TaskObjectStatus {
+			return status(object.(T))
+		},
+		TTLSecondsAfterFinished: func(object TaskObject) *int32 {
+			return ttl(object.(T))
+		},
+	}
+}
+
+// JobObjectStatus returns the TaskObjectStatus of the jobs sharing CommonJobStatus, status is the
+// whole status of the job
+func JobObjectStatus(common v1alpha1.CommonJobStatus, status interface{}) TaskObjectStatus {
+	return TaskObjectStatus{State: common.State, Reason: common.Reason, Time: common.Time, NodeStatus: common.Status, Status: status}
+}
+
+type typedTaskClient[T TaskObject, L runtime.Object] struct {
+	client TypedTaskClient[T, L]
+}
+
+func (c typedTaskClient[T, L]) Get(ctx context.Context, name string) (TaskObject, error) {
+	return c.client.Get(ctx, name, metav1.GetOptions{})
+}
+
+func (c typedTaskClient[T, L]) Create(ctx context.Context, object TaskObject) (TaskObject, error) {
+	typed, ok := object.(T)
+	if !ok {
+		return nil, fmt.Errorf("unexpected task object %T", object)
+	}
+	return c.client.Create(ctx, typed, metav1.CreateOptions{})
+}
+
+func (c typedTaskClient[T, L]) Patch(ctx context.Context, name string, patch []byte) error {
+	_, err := c.client.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+func (c typedTaskClient[T, L]) List(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+	return c.client.List(ctx, options)
+}
+
+func (c typedTaskClient[T, L]) Watch(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+	return c.client.Watch(ctx, options)
+}
+
+func (c typedTaskClient[T, L]) Delete(ctx context.Context, name, resourceVersion string) error {
+	return c.client.Delete(ctx, name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: &resourceVersion},
+	})
+}
@@ -17,16 +17,20 @@ limitations under the License.
 package util
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/distribution/distribution/v3/reference"
 	metav1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	versionutil "k8s.io/apimachinery/pkg/util/version"
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/kubeedge/common/constants"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
 	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
 )
 
@@ -36,27 +40,155 @@ const (
 	NodeUpgradeHistoryKey     = "nodeupgradejob.operations.kubeedge.io/history"
 )
 
+const (
+	// TaskNameLabelKey and TaskTypeLabelKey are set on resources created on behalf of a task
+	TaskNameLabelKey = "operations.kubeedge.io/task-name"
+	TaskTypeLabelKey = "operations.kubeedge.io/task-type"
+)
+
 const (
 	TaskUpgrade  = "upgrade"
 	TaskRollback = "rollback"
 	TaskBackup   = "backup"
 	TaskPrePull  = "prepull"
+	// TaskSupportBundle collects a support bundle from edge nodes and cloudcore
+	TaskSupportBundle = "supportbundle"
+	// TaskConfigUpdate applies a patch of the edgecore config on edge nodes
+	TaskConfigUpdate = "configupdate"
+	// TaskRestart restarts edgecore or reboots the host of edge nodes
+	TaskRestart = "restart"
+	// TaskDiagnose runs the checks of keadm debug diagnose on edge nodes
+	TaskDiagnose = "diagnose"
+	// TaskRestore restores the backup of a BackupJob on edge nodes
+	TaskRestore = "restore"
+	// TaskOSUpgrade applies an OS or firmware update with a hook script on edge nodes
+	TaskOSUpgrade = "osupgrade"
+	// TaskAccept is the operation of receipts sent by edge nodes when they accept a task
+	TaskAccept = "accept"
+	// TaskInventory is the operation of messages sent by edge nodes to report the tasks they persist
+	TaskInventory = "inventory"
+	// TaskPurge is the operation of messages sent to edge nodes to purge the tasks that no longer exist
+	TaskPurge = "purge"
+	// TaskProbe is the operation of the preflight probes sent to edge nodes and of their answers
+	TaskProbe = "probe"
 
 	ISO8601UTC = "2006-01-02T15:04:05Z"
 )
 
+// ErrNotLeader is returned on the replicas of cloudcore which do not run the executors of the tasks
+var ErrNotLeader = errors.New("this cloudcore does not run the tasks, send the request to the leader")
+
 type TaskMessage struct {
-	Type            string
-	Name            string
-	TimeOutSeconds  *uint32
-	ShutDown        bool
-	CheckItem       []string
-	Concurrency     int32
-	FailureTolerate float64
-	NodeNames       []string
-	LabelSelector   *v1.LabelSelector
-	Status          v1alpha1.TaskStatus
-	Msg             interface{}
+	Type           string
+	Name           string
+	TimeOutSeconds *uint32
+	// StageTimeouts limits the stages of the task on each node by the state of the node in the
+	// stage, the stages not in it are limited by TimeOutSeconds
+	StageTimeouts map[api.State]uint32
+	ShutDown      bool
+	// Cancel requests the executor of the task to cancel it
+	Cancel bool
+	// Drain lets the nodes running the task finish it when the task is cancelled
+	Drain bool
+	// ForceComplete requests the executor of the task to complete a node manually
+	ForceComplete *ForceCompletion
+	// Paused stops the executor of the task from dispatching new nodes
+	Paused bool
+	// SetPaused requests the running executor of the task to apply Paused
+	SetPaused bool
+	// PauseOn pauses the task once one of its conditions matches
+	PauseOn *v1alpha1.PauseOnSpec
+	// Apply gates the disruptive stages of the task, the nodes are dispatched to them only when it is applied
+	Apply *v1alpha1.ApplySpec
+	// SetApply requests the running executor of the task to apply Apply
+	SetApply bool
+	// Canary selects the nodes upgraded before the task waits for approval
+	Canary *v1alpha1.CanarySpec
+	// SetApprove requests the running executor of the task to apply the approval of Canary
+	SetApprove bool
+	// SetTuning requests the running executor of the task to apply Concurrency, MaxUnavailable and FailureTolerate
+	SetTuning bool
+	// DrainNodeBeforeUpgrade drains each node before it is upgraded
+	DrainNodeBeforeUpgrade *v1alpha1.DrainSpec
+	// OrderingSeed shuffles the nodes of the task with the seed when they are selected, they are
+	// operated on in the order they are selected if it is nil
+	OrderingSeed *int64
+	// OrderByReadiness sorts the nodes of the task from the most ready to the least ready when they are selected
+	OrderByReadiness bool
+	// DryRun runs the checks on the nodes of the task and finishes it without operating on them
+	DryRun bool
+	// RotateCertificate requests edgehub to rotate the certificate of the nodes which expires soon in the cert check item
+	RotateCertificate bool
+	// ActiveDeadline finishes the task DeadlineExceeded once its executor ran it for so long, it is measured
+	// by the monotonic clock of the cloudcore running the task, the task has no deadline if it is zero
+	ActiveDeadline time.Duration
+	CheckItem      []string
+	Concurrency    int32
+	// MaxUnavailable sizes the batch of nodes run at the same time from the number of nodes of the task,
+	// it takes precedence over Concurrency
+	MaxUnavailable *intstr.IntOrString
+	// DispatchJitterSeconds bounds the random delay before the job is dispatched to each node
+	DispatchJitterSeconds int32
+	// Preflight probes the nodes of the task before it is dispatched to them, the nodes are not probed if it is nil
+	Preflight *v1alpha1.PreflightSpec
+	// PostCheck verifies the nodes from cloud once they report they are upgraded to Version,
+	// the nodes succeed once they report it if it is nil
+	PostCheck *v1alpha1.PostCheckSpec
+	Version   string
+	// FailureTolerate is the number or the percentage of the nodes of the task which can fail,
+	// the task fails once its failed nodes reach it
+	FailureTolerate *intstr.IntOrString
+	// Notifications are sent once the failed nodes of the task exceed its failure tolerance
+	Notifications []v1alpha1.NotificationSink
+	// RollbackPolicy decides whether the nodes upgraded by the task are rolled back once it exceeds its failure tolerance
+	RollbackPolicy v1alpha1.RollbackPolicy
+	NodeNames      []string
+	LabelSelector  *v1.LabelSelector
+	// NodeGroups adds the members of the NodeGroups to the nodes of the task
+	NodeGroups []string
+	Status     v1alpha1.TaskStatus
+	Msg        interface{}
+	// Labels are the labels of the task object, they are propagated to auxiliary resources
+	Labels map[string]string
+	// Metadata is the custom metadata of the task, it is sent to the edge nodes with the task
+	Metadata map[string]string
+	// RenderPayload renders the templates in Msg with the variables of each node before it is sent to the node
+	RenderPayload bool
+	// Credential mints a short-lived token for each node of the task, it is sent to the node with the task
+	Credential *v1alpha1.TaskCredentialSpec
+	// Owner references the task object, auxiliary resources are garbage-collected with it
+	Owner *v1.OwnerReference
+}
+
+// NewTaskOwnerReference returns the OwnerReference of a task object of the given kind
+func NewTaskOwnerReference(task v1.Object, kind string) *v1.OwnerReference {
+	return v1.NewControllerRef(task, v1alpha1.SchemeGroupVersion.WithKind(kind))
+}
+
+// TaskLabels returns the labels of the resources created on behalf of a task, they are the
+// task's labels along with its name and type
+func TaskLabels(task TaskMessage) map[string]string {
+	labels := make(map[string]string, len(task.Labels)+2)
+	for k, v := range task.Labels {
+		labels[k] = v
+	}
+	labels[TaskNameLabelKey] = task.Name
+	labels[TaskTypeLabelKey] = task.Type
+	return labels
+}
+
+// TaskObjectMeta returns the metadata of a resource created on behalf of a task.
+// The resource carries the task's labels and is owned by the task.
+func TaskObjectMeta(name, namespace string, task TaskMessage) v1.ObjectMeta {
+	meta := v1.ObjectMeta{
+		Name:      name,
+		Namespace: namespace,
+		Labels:    TaskLabels(task),
+	}
+	if task.Owner != nil {
+		meta.OwnerReferences = []v1.OwnerReference{*task.Owner}
+	}
+	return meta
 }
 
 // FilterVersion returns true only if the edge node version already on the upgrade req
@@ -137,15 +269,27 @@ func GetImageRepo(image string) (string, error) {
 	return named.Name(), nil
 }
 
+// ParseTaskResource returns the task ID and the node name of the resource of a task message,
+// task/${TaskID}/node/${NodeID} sent by edge nodes or ${TaskType}/${TaskID}/node/${NodeID} sent to them.
+// The type of the task is not part of the resource sent by edge nodes, it is the operation of the message.
+func ParseTaskResource(resource string) (taskID, nodeName string, err error) {
+	s := strings.Split(resource, constants.ResourceSep)
+	if len(s) != 4 || s[2] != "node" || s[1] == "" || s[3] == "" {
+		return "", "", fmt.Errorf("resource %q is not a task resource", resource)
+	}
+	return s[1], s[3], nil
+}
+
+// GetNodeName returns the node name of the resource of a task message, it is empty if the resource is malformed
 func GetNodeName(resource string) string {
-	// task/${TaskID}/node/${NodeID}
-	s := strings.Split(resource, "/")
-	return s[3]
+	_, nodeName, _ := ParseTaskResource(resource)
+	return nodeName
 }
+
+// GetTaskID returns the task ID of the resource of a task message, it is empty if the resource is malformed
 func GetTaskID(resource string) string {
-	// task/${TaskID}/node/${NodeID}
-	s := strings.Split(resource, "/")
-	return s[1]
+	taskID, _, _ := ParseTaskResource(resource)
+	return taskID
 }
 
 func VersionLess(version1, version2 string) (bool, error) {
@@ -179,3 +323,29 @@ func NodeUpdated(old, new v1alpha1.TaskStatus) bool {
 	}
 	return true
 }
+
+// UpgradeStageTimeouts returns the timeouts of the stages of a NodeUpgradeJob by the state of the
+// nodes in the stage, the stages whose timeout is not set or is 0 are left out
+func UpgradeStageTimeouts(timeouts *v1alpha1.StageTimeouts) map[api.State]uint32 {
+	if timeouts == nil {
+		return nil
+	}
+	stages := map[api.State]uint32{}
+	for _, stage := range []struct {
+		timeout *uint32
+		states  []api.State
+	}{
+		{timeouts.Check, []api.State{api.TaskChecking}},
+		{timeouts.Backup, []api.State{api.BackingUpState}},
+		{timeouts.Upgrade, []api.State{api.UpgradingState}},
+		{timeouts.Rollback, []api.State{api.RollingBackState, api.RevertingState}},
+	} {
+		if stage.timeout == nil || *stage.timeout == 0 {
+			continue
+		}
+		for _, state := range stage.states {
+			stages[state] = *stage.timeout
+		}
+	}
+	return stages
+}
@@ -17,8 +17,28 @@ limitations under the License.
 package util
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	metav1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+	cloudcorev1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
 )
 
 func TestFilterVersion(t *testing.T) {
@@ -169,3 +189,1246 @@ func TestGetImageRepo(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskObjectMeta(t *testing.T) {
+	owner := &v1.OwnerReference{Kind: "NodeUpgradeJob", Name: "upgrade"}
+	task := TaskMessage{
+		Type:   TaskUpgrade,
+		Name:   "upgrade",
+		Labels: map[string]string{"team": "edge"},
+		Owner:  owner,
+	}
+	meta := TaskObjectMeta("report", "default", task)
+	expectedLabels := map[string]string{
+		"team":           "edge",
+		TaskNameLabelKey: "upgrade",
+		TaskTypeLabelKey: TaskUpgrade,
+	}
+	if !reflect.DeepEqual(meta.Labels, expectedLabels) {
+		t.Errorf("Got labels = %v, Want = %v", meta.Labels, expectedLabels)
+	}
+	if len(meta.OwnerReferences) != 1 || meta.OwnerReferences[0] != *owner {
+		t.Errorf("Got owner references = %v, Want = %v", meta.OwnerReferences, owner)
+	}
+	if _, ok := task.Labels[TaskNameLabelKey]; ok {
+		t.Errorf("task labels should not be modified")
+	}
+}
+
+func TestRecordTaskEvent(t *testing.T) {
+	recorder := record.NewFakeRecorder(2)
+	recorderOnce.Do(func() {})
+	eventRecorder = recorder
+
+	RecordTaskEvent(TaskMessage{Type: TaskUpgrade, Name: "upgrade"}, metav1.EventTypeNormal, "Paused", "the job is paused")
+	task := TaskMessage{
+		Type:   TaskUpgrade,
+		Name:   "upgrade",
+		Labels: map[string]string{"team": "edge"},
+		Owner:  &v1.OwnerReference{Kind: "NodeUpgradeJob", Name: "upgrade"},
+	}
+	RecordTaskEvent(task, metav1.EventTypeNormal, "Paused", "the job is paused")
+	job := &v1alpha1.NodeUpgradeJob{ObjectMeta: v1.ObjectMeta{Name: "upgrade", Labels: map[string]string{"team": "edge"}}}
+	RecordWaitingForInputs(job, "NodeUpgradeJob", "job other is running")
+
+	expected := []string{
+		"Normal Paused the job is paused map[operations.kubeedge.io/task-name:upgrade operations.kubeedge.io/task-type:upgrade team:edge]",
+		"Normal WaitingForInputs The job waits for its inputs: job other is running map[operations.kubeedge.io/task-name:upgrade team:edge]",
+	}
+	for _, want := range expected {
+		if got := <-recorder.Events; got != want {
+			t.Errorf("Got event = %q, Want = %q", got, want)
+		}
+	}
+	if len(recorder.Events) != 0 {
+		t.Errorf("expected no event for the task without an owner, got %q", <-recorder.Events)
+	}
+}
+
+func TestRenderPayload(t *testing.T) {
+	node := &metav1.Node{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "edge-1",
+			Labels:      map[string]string{"site": "sh-01"},
+			Annotations: map[string]string{"broker": "tcp://10.0.0.1:1883"},
+		},
+	}
+	type payload struct {
+		Images []string
+		Script string
+		Secret string
+	}
+
+	rendered, err := RenderPayload(payload{
+		Images: []string{`registry-{{ index .Labels "site" }}.local/nginx`},
+		Script: `echo {{ .NodeName }} {{ index .Annotations "broker" }} {{ index .Labels "absent" }}`,
+		Secret: "secret",
+	}, node)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"Images": []interface{}{"registry-sh-01.local/nginx"},
+		"Script": "echo edge-1 tcp://10.0.0.1:1883 ",
+		"Secret": "secret",
+	}
+	if !reflect.DeepEqual(rendered, expected) {
+		t.Errorf("expected %v, got %v", expected, rendered)
+	}
+
+	plain := payload{Script: "echo hello"}
+	rendered, err = RenderPayload(plain, node)
+	if err != nil || !reflect.DeepEqual(rendered, plain) {
+		t.Errorf("expected payload without template unchanged, got %v, %v", rendered, err)
+	}
+
+	if _, err = RenderPayload(payload{Script: "{{ .NodeName "}, node); err == nil {
+		t.Errorf("expected error for invalid template")
+	}
+}
+
+func TestSubstituteInputs(t *testing.T) {
+	values := map[string]string{"nodes": "edge-1,edge-2", "version": "v1.15.0", "none": ""}
+	spec := v1alpha1.NodeUpgradeJobSpec{
+		Version:   "$(inputs.version)",
+		Image:     "kubeedge/installation-package:$(inputs.version)",
+		NodeNames: []string{"$(inputs.nodes)", "edge-3", "$(inputs.none)"},
+		Metadata:  map[string]string{"ticket": "CHG-1"},
+	}
+	if err := SubstituteInputs(&spec, values); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := v1alpha1.NodeUpgradeJobSpec{
+		Version:   "v1.15.0",
+		Image:     "kubeedge/installation-package:v1.15.0",
+		NodeNames: []string{"edge-1", "edge-2", "edge-3"},
+		Metadata:  map[string]string{"ticket": "CHG-1"},
+	}
+	if !reflect.DeepEqual(spec, expected) {
+		t.Errorf("expected %v, got %v", expected, spec)
+	}
+
+	undefined := v1alpha1.NodeUpgradeJobSpec{NodeNames: []string{"$(inputs.absent)"}}
+	if err := SubstituteInputs(&undefined, values); err == nil {
+		t.Errorf("expected error for undefined input")
+	}
+	if got := ExpandInputs("$(inputs.version)-$(inputs.absent)", values); got != "v1.15.0-$(inputs.absent)" {
+		t.Errorf("expected unknown reference kept, got %s", got)
+	}
+}
+
+func TestNodeOutputs(t *testing.T) {
+	outputs := NodeOutputs([]v1alpha1.TaskStatus{
+		{NodeName: "edge-2", State: api.TaskSuccessful},
+		{NodeName: "edge-3", State: api.TaskFailed},
+		{NodeName: "edge-1", State: api.TaskSuccessful},
+		{NodeName: "edge-4", State: api.TaskCancelled},
+	})
+	expected := map[string]string{OutputSucceededNodes: "edge-1,edge-2", OutputFailedNodes: "edge-3"}
+	if !reflect.DeepEqual(outputs, expected) {
+		t.Errorf("expected %v, got %v", expected, outputs)
+	}
+}
+
+func TestPruneNodeStatus(t *testing.T) {
+	fields := []string{"reason", "time"}
+	tests := []struct {
+		name     string
+		status   v1alpha1.TaskStatus
+		fields   []string
+		expected v1alpha1.TaskStatus
+		pruned   bool
+	}{
+		{
+			name:     "intermediate state is pruned",
+			status:   v1alpha1.TaskStatus{NodeName: "edge-1", State: api.NodeUpgrading, Event: "Upgrade", Action: api.ActionSuccess, Reason: "upgrading", Time: "now"},
+			fields:   fields,
+			expected: v1alpha1.TaskStatus{NodeName: "edge-1", State: api.NodeUpgrading, Event: "Upgrade", Action: api.ActionSuccess},
+			pruned:   true,
+		},
+		{
+			name:     "terminal state is kept",
+			status:   v1alpha1.TaskStatus{NodeName: "edge-1", State: api.TaskFailed, Reason: "timeout", Time: "now"},
+			fields:   fields,
+			expected: v1alpha1.TaskStatus{NodeName: "edge-1", State: api.TaskFailed, Reason: "timeout", Time: "now"},
+		},
+		{
+			name:     "only configured fields are pruned",
+			status:   v1alpha1.TaskStatus{NodeName: "edge-1", State: api.TaskChecking, Reason: "checking", Time: "now"},
+			fields:   []string{"time"},
+			expected: v1alpha1.TaskStatus{NodeName: "edge-1", State: api.TaskChecking, Reason: "checking"},
+			pruned:   true,
+		},
+		{
+			name:     "no fields configured",
+			status:   v1alpha1.TaskStatus{NodeName: "edge-1", State: api.TaskChecking, Reason: "checking"},
+			expected: v1alpha1.TaskStatus{NodeName: "edge-1", State: api.TaskChecking, Reason: "checking"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status := test.status
+			pruned := PruneNodeStatus(&status, test.fields)
+			if pruned != test.pruned {
+				t.Errorf("expected pruned %v, got %v", test.pruned, pruned)
+			}
+			if !reflect.DeepEqual(status, test.expected) {
+				t.Errorf("expected status %v, got %v", test.expected, status)
+			}
+		})
+	}
+}
+
+func TestNodeTransitionEvent(t *testing.T) {
+	tests := []struct {
+		status    v1alpha1.TaskStatus
+		eventType string
+		reason    string
+	}{
+		{v1alpha1.TaskStatus{State: api.TaskChecking, Event: "Check"}, metav1.EventTypeNormal, "NodeChecking"},
+		{v1alpha1.TaskStatus{State: api.NodeUpgrading, Event: "Upgrade"}, metav1.EventTypeNormal, "NodeUpgrading"},
+		{v1alpha1.TaskStatus{State: api.TaskFailed, Event: "Upgrade"}, metav1.EventTypeWarning, "NodeFailed"},
+		{v1alpha1.TaskStatus{State: api.TaskFailed, Event: api.EventTimeOut}, metav1.EventTypeWarning, "NodeTimeout"},
+	}
+	for _, test := range tests {
+		eventType, reason := nodeTransitionEvent(test.status)
+		if eventType != test.eventType || reason != test.reason {
+			t.Errorf("expected %s %s for %v, got %s %s", test.eventType, test.reason, test.status, eventType, reason)
+		}
+	}
+}
+
+func TestNodeProgress(t *testing.T) {
+	if progress := NodeProgress(nil); progress != nil {
+		t.Errorf("expected no progress without nodes, got %v", progress)
+	}
+	progress := NodeProgress([]v1alpha1.TaskStatus{
+		{NodeName: "edge-1", State: api.TaskSuccessful},
+		{NodeName: "edge-2", State: api.TaskFailed},
+		{NodeName: "edge-3", State: api.NodeUpgrading},
+		{NodeName: "edge-4", State: api.TaskSkipped},
+		{NodeName: "edge-5"},
+	})
+	expected := &v1alpha1.TaskProgress{Total: 5, Succeeded: 1, Failed: 1, InProgress: 1, PercentComplete: 60}
+	if !reflect.DeepEqual(progress, expected) {
+		t.Errorf("expected progress %v, got %v", expected, progress)
+	}
+}
+
+func TestCancelRequested(t *testing.T) {
+	running := &v1alpha1.ImagePrePullJob{}
+	cancelled := &v1alpha1.ImagePrePullJob{ObjectMeta: v1.ObjectMeta{
+		Annotations: map[string]string{TaskCancelAnnotationKey: "true"},
+	}}
+
+	if !CancelRequested(running, cancelled) {
+		t.Errorf("expected cancellation to be requested")
+	}
+	if CancelRequested(cancelled, cancelled) {
+		t.Errorf("expected no new cancellation request")
+	}
+	if CancelRequested(cancelled, running) {
+		t.Errorf("expected removing the annotation not to request cancellation")
+	}
+
+	graceful := &v1alpha1.ImagePrePullJob{ObjectMeta: v1.ObjectMeta{
+		Annotations: map[string]string{TaskCancelAnnotationKey: TaskCancelGracefully},
+	}}
+	if !CancelRequested(running, graceful) || !IsGracefulCancel(graceful) || IsGracefulCancel(cancelled) {
+		t.Errorf("expected graceful cancellation to be requested")
+	}
+}
+
+func TestLockedBy(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		expected    bool
+	}{
+		{name: "unlocked", annotations: nil, expected: false},
+		{name: "same task", annotations: map[string]string{
+			TaskInProgressAnnotationKey:     "task",
+			TaskTypeInProgressAnnotationKey: "imagePrePull",
+		}, expected: true},
+		{name: "same name of another type", annotations: map[string]string{
+			TaskInProgressAnnotationKey:     "task",
+			TaskTypeInProgressAnnotationKey: "nodeUpgrade",
+		}, expected: false},
+		{name: "another task", annotations: map[string]string{
+			TaskInProgressAnnotationKey:     "other",
+			TaskTypeInProgressAnnotationKey: "imagePrePull",
+		}, expected: false},
+		{name: "lock without type", annotations: map[string]string{
+			TaskInProgressAnnotationKey: "task",
+		}, expected: true},
+	}
+	for _, c := range cases {
+		if got := LockedBy(c.annotations, "imagePrePull", "task"); got != c.expected {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, got)
+		}
+	}
+}
+
+func TestForceCompletionRequested(t *testing.T) {
+	withForce := func(value string) *v1alpha1.NodeUpgradeJob {
+		return &v1alpha1.NodeUpgradeJob{ObjectMeta: v1.ObjectMeta{
+			Annotations: map[string]string{TaskForceCompleteAnnotationKey: value},
+		}}
+	}
+	running := &v1alpha1.NodeUpgradeJob{}
+	forced := withForce(`{"nodeName":"edge-1","state":"Failed","reason":"unreachable since the flood"}`)
+
+	force, err := ForceCompletionRequested(running, forced)
+	if err != nil || force == nil || force.NodeName != "edge-1" || force.State != api.TaskFailed {
+		t.Fatalf("expected edge-1 to be forced to Failed, got %+v, %v", force, err)
+	}
+	if force, err = ForceCompletionRequested(forced, forced); force != nil || err != nil {
+		t.Errorf("expected no new force completion, got %+v, %v", force, err)
+	}
+	for _, value := range []string{
+		`{"nodeName":"edge-1","state":"Failed"}`,
+		`{"nodeName":"edge-1","state":"Upgrading","reason":"stuck"}`,
+		`{"state":"Successful","reason":"stuck"}`,
+		`edge-1`,
+	} {
+		if _, err = ForceCompletionRequested(running, withForce(value)); err == nil {
+			t.Errorf("expected force completion %s to be rejected", value)
+		}
+	}
+}
+
+func TestAccountNodeCost(t *testing.T) {
+	cost := AccountNodeCost("task", "node", "group-a", nil, fsm.Event{BytesDownloaded: 100})
+	if cost.StartTime == nil || cost.Group != "group-a" || cost.BytesDownloaded != 100 || cost.Retries != 0 {
+		t.Fatalf("unexpected initial cost %+v", cost)
+	}
+
+	RecordNodeRetry("task", "node")
+	RecordNodeRetry("task", "node")
+	cost.StartTime = &v1.Time{Time: cost.StartTime.Add(-time.Minute)}
+	next := AccountNodeCost("task", "node", "group-b", cost, fsm.Event{BytesDownloaded: 50})
+	if next.Group != "group-a" || next.BytesDownloaded != 150 || next.Retries != 2 || next.DurationSeconds < 60 {
+		t.Errorf("unexpected accumulated cost %+v", next)
+	}
+	if cost.BytesDownloaded != 100 {
+		t.Errorf("previous cost must not be modified")
+	}
+	if next = AccountNodeCost("task", "node", "", next, fsm.Event{}); next.Retries != 2 {
+		t.Errorf("retries are counted twice: %d", next.Retries)
+	}
+
+	// the start time is recorded by a clock ahead of this one
+	skewed := &v1alpha1.NodeCost{StartTime: &v1.Time{Time: time.Now().Add(time.Hour)}, DurationSeconds: 30, StageSeconds: 25}
+	if next = AccountNodeCost("task", "node", "", skewed, fsm.Event{Elapsed: 20 * time.Second}); next.DurationSeconds != 45 || next.StageSeconds != 45 {
+		t.Errorf("expected the duration to be the time the node reported it ran its stages for, got %+v", next)
+	}
+	if next = AccountNodeCost("task", "node", "", next, fsm.Event{}); next.DurationSeconds != 45 {
+		t.Errorf("expected the duration never to go back, got %d", next.DurationSeconds)
+	}
+
+	// the stage ran while the task was accounted for, the time it ran for is not added to the duration
+	started := &v1alpha1.NodeCost{StartTime: &v1.Time{Time: time.Now().Add(-time.Minute)}, DurationSeconds: 40}
+	if next = AccountNodeCost("task", "node", "", started, fsm.Event{Elapsed: 30 * time.Second}); next.DurationSeconds < 60 || next.DurationSeconds > 61 {
+		t.Errorf("expected the duration to be the time since the start, got %d", next.DurationSeconds)
+	}
+}
+
+func TestAggregateCost(t *testing.T) {
+	if cost := AggregateCost([]*v1alpha1.NodeCost{nil}); cost != nil {
+		t.Errorf("expected nil cost, got %+v", cost)
+	}
+
+	cost := AggregateCost([]*v1alpha1.NodeCost{
+		{BytesDownloaded: 100, DurationSeconds: 10, Retries: 1, Group: "b"},
+		nil,
+		{BytesDownloaded: 200, DurationSeconds: 20},
+		{BytesDownloaded: 300, DurationSeconds: 30, Retries: 2, Group: "b"},
+	})
+	expected := &v1alpha1.TaskCost{
+		CostSummary: v1alpha1.CostSummary{Nodes: 3, BytesDownloaded: 600, DurationSeconds: 60, Retries: 3},
+		Groups: []v1alpha1.CostSummary{
+			{Nodes: 1, BytesDownloaded: 200, DurationSeconds: 20},
+			{Group: "b", Nodes: 2, BytesDownloaded: 400, DurationSeconds: 40, Retries: 3},
+		},
+	}
+	if !reflect.DeepEqual(cost, expected) {
+		t.Errorf("expected %+v, got %+v", expected, cost)
+	}
+}
+
+func TestCheckPromotion(t *testing.T) {
+	status := []v1alpha1.TaskStatus{
+		{NodeName: "a", State: api.TaskSuccessful},
+		{NodeName: "b", State: api.TaskSuccessful},
+		{NodeName: "c", State: api.TaskSuccessful},
+		{NodeName: "d", State: api.TaskFailed},
+	}
+	tests := []struct {
+		threshold string
+		status    []v1alpha1.TaskStatus
+		expectErr bool
+	}{
+		{threshold: "", status: status, expectErr: true},
+		{threshold: "0.75", status: status},
+		{threshold: "0.98", status: status, expectErr: true},
+		{threshold: "1.5", status: status, expectErr: true},
+		{threshold: "0", status: nil, expectErr: true},
+		{threshold: "", status: status[:3]},
+		{threshold: "", status: append(status[:3:3], v1alpha1.TaskStatus{NodeName: "e", State: api.TaskNodeRemoved})},
+	}
+	for _, test := range tests {
+		err := CheckPromotion(&v1alpha1.PromotionSpec{NodeGroup: "production", SuccessThreshold: test.threshold}, test.status)
+		if (err != nil) != test.expectErr {
+			t.Errorf("threshold %q with %d nodes: expected error %v, got %v", test.threshold, len(test.status), test.expectErr, err)
+		}
+	}
+}
+
+func TestPendingRemoval(t *testing.T) {
+	now := v1.Now()
+	tests := []struct {
+		name    string
+		node    metav1.Node
+		pending bool
+	}{
+		{
+			name: "regular node",
+			node: metav1.Node{Spec: metav1.NodeSpec{Taints: []metav1.Taint{{Key: "node-role.kubernetes.io/edge"}}}},
+		},
+		{
+			name:    "deleted node",
+			node:    metav1.Node{ObjectMeta: v1.ObjectMeta{DeletionTimestamp: &now}},
+			pending: true,
+		},
+		{
+			name:    "annotated node",
+			node:    metav1.Node{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{PendingRemovalAnnotationKey: "true"}}},
+			pending: true,
+		},
+		{
+			name:    "scaled down by cluster-autoscaler",
+			node:    metav1.Node{Spec: metav1.NodeSpec{Taints: []metav1.Taint{{Key: "ToBeDeletedByClusterAutoscaler"}}}},
+			pending: true,
+		},
+		{
+			name:    "disrupted by karpenter",
+			node:    metav1.Node{Spec: metav1.NodeSpec{Taints: []metav1.Taint{{Key: "karpenter.sh/disrupted"}}}},
+			pending: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if marker, pending := PendingRemoval(&test.node); pending != test.pending {
+				t.Errorf("expected pending removal %t, got %t (%s)", test.pending, pending, marker)
+			}
+		})
+	}
+}
+
+func TestRerunAndDeadline(t *testing.T) {
+	if RerunRequested(api.TaskChecking, 1, 0) {
+		t.Errorf("a running task must not be rerun")
+	}
+	if RerunRequested(api.TaskSuccessful, 1, 1) {
+		t.Errorf("a finished task with an unchanged rerun must not be rerun")
+	}
+	if !RerunRequested(api.TaskPartiallySucceeded, 2, 1) {
+		t.Errorf("expected the finished task to be rerun")
+	}
+
+	seconds, invalid := int64(60), int64(0)
+	if deadline := ActiveDeadline(nil); deadline != 0 {
+		t.Errorf("expected no deadline, got %s", deadline)
+	}
+	if deadline := ActiveDeadline(&invalid); deadline != 0 {
+		t.Errorf("expected no deadline for a non-positive activeDeadlineSeconds, got %s", deadline)
+	}
+	if deadline := ActiveDeadline(&seconds); deadline != time.Minute {
+		t.Errorf("expected the deadline to be a minute, got %s", deadline)
+	}
+}
+
+func TestVersionMappings(t *testing.T) {
+	node := func(name, arch string) metav1.Node {
+		return metav1.Node{ObjectMeta: v1.ObjectMeta{Name: name, Labels: map[string]string{"kubernetes.io/arch": arch, "site": "edge"}}}
+	}
+	mappings := []v1alpha1.VersionMapping{
+		{Name: "arm", Version: "v1.16.3", LabelSelector: &v1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/arch": "arm64"}}},
+		{Name: "edge", Version: "v1.17.0", LabelSelector: &v1.LabelSelector{MatchLabels: map[string]string{"site": "edge"}}},
+		{Name: "none", Version: "v1.17.0", LabelSelector: &v1.LabelSelector{MatchLabels: map[string]string{"site": "cloud"}}},
+	}
+	assigned, err := AssignVersionMappings(mappings, []metav1.Node{node("gw-2", "arm64"), node("srv-1", "amd64"), node("gw-1", "arm64")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(assigned, [][]string{{"gw-1", "gw-2"}, {"srv-1"}, nil}) {
+		t.Errorf("expected the nodes to be assigned to the first mapping selecting them, got %v", assigned)
+	}
+
+	mappings[0].LabelSelector.MatchExpressions = []v1.LabelSelectorRequirement{{Key: "site", Operator: "Unknown"}}
+	if _, err = AssignVersionMappings(mappings, nil); err == nil {
+		t.Errorf("expected an error for an invalid label selector")
+	}
+
+	tests := []struct {
+		name     string
+		mappings []v1alpha1.VersionMappingStatus
+		expected api.State
+	}{
+		{
+			name: "running",
+			mappings: []v1alpha1.VersionMappingStatus{
+				{Name: "arm", Job: "upgrade-arm", State: api.TaskSuccessful},
+				{Name: "edge", Job: "upgrade-edge", State: api.UpgradingState},
+			},
+		},
+		{
+			name: "succeeded",
+			mappings: []v1alpha1.VersionMappingStatus{
+				{Name: "arm", Job: "upgrade-arm", State: api.TaskSuccessful},
+				{Name: "none"},
+			},
+			expected: api.TaskSuccessful,
+		},
+		{
+			name: "partially succeeded",
+			mappings: []v1alpha1.VersionMappingStatus{
+				{Name: "arm", Job: "upgrade-arm", State: api.TaskSuccessful},
+				{Name: "edge", Job: "upgrade-edge", State: api.TaskFailed},
+			},
+			expected: api.TaskPartiallySucceeded,
+		},
+		{
+			name:     "no node",
+			mappings: []v1alpha1.VersionMappingStatus{{Name: "none"}},
+			expected: api.TaskFailed,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if state, reason := VersionMappingsState(test.mappings); state != test.expected {
+				t.Errorf("expected state %q, got %q: %s", test.expected, state, reason)
+			}
+		})
+	}
+}
+
+func TestInMaintenanceWindow(t *testing.T) {
+	windows := []v1alpha1.MaintenanceWindow{
+		{Start: "23:00", DurationMinutes: 120},
+		{Start: "12:30", DurationMinutes: 30},
+	}
+	tests := []struct {
+		name     string
+		now      time.Time
+		open     bool
+		nextOpen time.Time
+	}{
+		{name: "in the window opened yesterday", now: time.Date(2024, 5, 2, 0, 30, 0, 0, time.UTC), open: true},
+		{name: "in the window", now: time.Date(2024, 5, 2, 12, 45, 0, 0, time.UTC), open: true},
+		{name: "window closed", now: time.Date(2024, 5, 2, 13, 0, 0, 0, time.UTC), nextOpen: time.Date(2024, 5, 2, 23, 0, 0, 0, time.UTC)},
+		{name: "before the windows", now: time.Date(2024, 5, 2, 1, 0, 0, 0, time.UTC), nextOpen: time.Date(2024, 5, 2, 12, 30, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			open, next := InMaintenanceWindow(windows, test.now)
+			if open != test.open || !next.Equal(test.nextOpen) {
+				t.Errorf("expected open %t until %s, got %t until %s", test.open, test.nextOpen, open, next)
+			}
+		})
+	}
+	if open, _ := InMaintenanceWindow(nil, time.Now()); !open {
+		t.Errorf("expected a task without maintenance windows to be applied at any time")
+	}
+}
+
+func TestRollingBatchSize(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxUnavailable intstr.IntOrString
+		nodes          int
+		size           int
+	}{
+		{name: "percentage", maxUnavailable: intstr.FromString("10%"), nodes: 200, size: 20},
+		{name: "percentage rounded down", maxUnavailable: intstr.FromString("10%"), nodes: 25, size: 2},
+		{name: "at least one node", maxUnavailable: intstr.FromString("10%"), nodes: 5, size: 1},
+		{name: "count", maxUnavailable: intstr.FromInt(3), nodes: 200, size: 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			size, err := RollingBatchSize(&test.maxUnavailable, test.nodes)
+			if err != nil || size != test.size {
+				t.Errorf("expected %d nodes, got %d: %v", test.size, size, err)
+			}
+		})
+	}
+	invalid := intstr.FromString("ten")
+	if _, err := RollingBatchSize(&invalid, 10); err == nil {
+		t.Errorf("expected an error for an invalid maxUnavailable")
+	}
+}
+
+func TestBatches(t *testing.T) {
+	maxUnavailable := intstr.FromString("50%")
+	if size, err := BatchSize(3, &maxUnavailable, 4); err != nil || size != 2 {
+		t.Errorf("expected maxUnavailable to size the batches, got %d: %v", size, err)
+	}
+	if size, err := BatchSize(0, nil, 4); err != nil || size != 1 {
+		t.Errorf("expected one node at least, got %d: %v", size, err)
+	}
+	batches := Batches([]string{"a", "b", "c"}, 2)
+	if !reflect.DeepEqual(batches, [][]string{{"a", "b"}, {"c"}}) {
+		t.Errorf("unexpected batches %v", batches)
+	}
+}
+
+func TestMaxFailedNodes(t *testing.T) {
+	tests := []struct {
+		name      string
+		tolerate  *intstr.IntOrString
+		max       float64
+		threshold int32
+		wantErr   bool
+	}{
+		{name: "default", max: 2, threshold: 3},
+		{name: "count", tolerate: &intstr.IntOrString{Type: intstr.Int, IntVal: 3}, max: 3, threshold: 4},
+		{name: "percentage", tolerate: &intstr.IntOrString{Type: intstr.String, StrVal: "25%"}, max: 5, threshold: 6},
+		{name: "deprecated ratio", tolerate: &intstr.IntOrString{Type: intstr.String, StrVal: "0.125"}, max: 2.5, threshold: 3},
+		{name: "no failure tolerated", tolerate: &intstr.IntOrString{Type: intstr.String, StrVal: "0%"}, max: 0, threshold: 1},
+		{name: "percentage above 100", tolerate: &intstr.IntOrString{Type: intstr.String, StrVal: "120%"}, wantErr: true},
+		{name: "invalid", tolerate: &intstr.IntOrString{Type: intstr.String, StrVal: "ten"}, wantErr: true},
+		{name: "negative count", tolerate: &intstr.IntOrString{Type: intstr.Int, IntVal: -1}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			max, err := MaxFailedNodes(test.tolerate, 20)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("MaxFailedNodes() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+			if max != test.max || FailureThreshold(max) != test.threshold {
+				t.Errorf("expected %v nodes and threshold %d, got %v and %d", test.max, test.threshold, max, FailureThreshold(max))
+			}
+		})
+	}
+}
+
+func TestUpgradeConcurrency(t *testing.T) {
+	count := intstr.FromInt(5)
+	percent := intstr.FromString("5%")
+	maxUnavailable := intstr.FromString("10%")
+	tests := []struct {
+		name           string
+		spec           v1alpha1.NodeUpgradeJobSpec
+		concurrency    int32
+		maxUnavailable *intstr.IntOrString
+	}{
+		{name: "not set", spec: v1alpha1.NodeUpgradeJobSpec{}, concurrency: 0},
+		{name: "count", spec: v1alpha1.NodeUpgradeJobSpec{Concurrency: &count}, concurrency: 5},
+		{name: "percentage", spec: v1alpha1.NodeUpgradeJobSpec{Concurrency: &percent}, concurrency: 1, maxUnavailable: &percent},
+		{
+			name: "rolling strategy takes precedence",
+			spec: v1alpha1.NodeUpgradeJobSpec{
+				Concurrency:     &percent,
+				RollingStrategy: &v1alpha1.RollingStrategy{MaxUnavailable: &maxUnavailable},
+			},
+			concurrency:    1,
+			maxUnavailable: &maxUnavailable,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			concurrency, maxUnavailable := UpgradeConcurrency(test.spec)
+			if concurrency != test.concurrency || maxUnavailable != test.maxUnavailable {
+				t.Errorf("expected %d and %v, got %d and %v", test.concurrency, test.maxUnavailable, concurrency, maxUnavailable)
+			}
+		})
+	}
+}
+
+func TestCanaryNodes(t *testing.T) {
+	nodes := []metav1.Node{
+		{ObjectMeta: v1.ObjectMeta{Name: "edge-3", Labels: map[string]string{"canary": "true"}}},
+		{ObjectMeta: v1.ObjectMeta{Name: "edge-1"}},
+		{ObjectMeta: v1.ObjectMeta{Name: "edge-2", Labels: map[string]string{"canary": "true"}}},
+	}
+	tests := []struct {
+		name   string
+		canary v1alpha1.CanarySpec
+		names  []string
+	}{
+		{name: "first nodes by name", canary: v1alpha1.CanarySpec{Nodes: 2}, names: []string{"edge-1", "edge-2"}},
+		{name: "more canary nodes than nodes", canary: v1alpha1.CanarySpec{Nodes: 5}, names: []string{"edge-1", "edge-2", "edge-3"}},
+		{
+			name:   "label selector",
+			canary: v1alpha1.CanarySpec{LabelSelector: &v1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}}},
+			names:  []string{"edge-2", "edge-3"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			names, err := CanaryNodes(&test.canary, nodes)
+			if err != nil || !reflect.DeepEqual(names, test.names) {
+				t.Errorf("expected canary nodes %v, got %v: %v", test.names, names, err)
+			}
+		})
+	}
+}
+
+func TestVersionCheckItems(t *testing.T) {
+	catalog := []cloudcorev1alpha1.TaskManagerVersionCheckItems{
+		{Versions: ">=1.17.0 <1.18.0", CheckItems: []string{"disk", "config-deprecation"}},
+		{Versions: ">=1.18.0", CheckItems: []string{"cgroup"}},
+		{Versions: "not a range", CheckItems: []string{"invalid"}},
+	}
+	tests := []struct {
+		name    string
+		version string
+		items   []string
+		want    []string
+	}{
+		{name: "matching version", version: "v1.17.2", items: []string{"cpu", "disk"}, want: []string{"cpu", "disk", "config-deprecation"}},
+		{name: "other version", version: "v1.18.0", items: []string{"cpu"}, want: []string{"cpu", "cgroup"}},
+		{name: "no matching version", version: "v1.16.0", items: []string{"cpu"}, want: []string{"cpu"}},
+		{name: "invalid version", version: "latest", items: []string{"cpu"}, want: []string{"cpu"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if items := VersionCheckItems(catalog, test.version, test.items); !reflect.DeepEqual(items, test.want) {
+				t.Errorf("expected check items %v, got %v", test.want, items)
+			}
+		})
+	}
+}
+
+func TestExclusionSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		excluded []v1alpha1.ExcludedNode
+		want     string
+	}{
+		{name: "no excluded node", want: ""},
+		{
+			name: "most frequent reason first",
+			excluded: []v1alpha1.ExcludedNode{
+				{NodeName: "node1", Reason: v1alpha1.ExclusionNotEdgeNode},
+				{NodeName: "node2", Reason: v1alpha1.ExclusionNotReady},
+				{NodeName: "node3", Reason: v1alpha1.ExclusionNotReady},
+			},
+			want: "2 NotReady, 1 NotEdgeNode",
+		},
+		{
+			name: "same count sorted by reason",
+			excluded: []v1alpha1.ExcludedNode{
+				{NodeName: "node1", Reason: v1alpha1.ExclusionUpToDate},
+				{NodeName: "node2", Reason: v1alpha1.ExclusionFiltered},
+			},
+			want: "1 Filtered, 1 UpToDate",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if summary := ExclusionSummary(test.excluded); summary != test.want {
+				t.Errorf("expected summary %q, got %q", test.want, summary)
+			}
+		})
+	}
+}
+
+func TestNodeGroupMembers(t *testing.T) {
+	nodes := []*metav1.Node{
+		{ObjectMeta: v1.ObjectMeta{Name: "edge-1", Labels: map[string]string{"region": "hangzhou"}}},
+		{ObjectMeta: v1.ObjectMeta{Name: "edge-2", Labels: map[string]string{"region": "beijing"}}},
+		{ObjectMeta: v1.ObjectMeta{Name: "edge-3"}},
+	}
+	group := &appsv1alpha1.NodeGroup{Spec: appsv1alpha1.NodeGroupSpec{
+		Nodes:       []string{"edge-3", "edge-4"},
+		MatchLabels: map[string]string{"region": "hangzhou"},
+	}}
+	var names []string
+	for _, node := range NodeGroupMembers(group, nodes) {
+		names = append(names, node.Name)
+	}
+	if want := []string{"edge-1", "edge-3"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("expected the members %v, got %v", want, names)
+	}
+	if members := NodeGroupMembers(&appsv1alpha1.NodeGroup{}, nodes); len(members) != 0 {
+		t.Errorf("expected an empty group to have no member, got %d", len(members))
+	}
+}
+
+func TestEvictablePods(t *testing.T) {
+	daemonSet := true
+	pods := []metav1.Pod{
+		{ObjectMeta: v1.ObjectMeta{Name: "app", Labels: map[string]string{"app": "web"}}},
+		{ObjectMeta: v1.ObjectMeta{Name: "mirror", Annotations: map[string]string{mirrorPodAnnotationKey: "hash"}}},
+		{ObjectMeta: v1.ObjectMeta{Name: "finished"}, Status: metav1.PodStatus{Phase: metav1.PodSucceeded}},
+		{ObjectMeta: v1.ObjectMeta{Name: "daemon", OwnerReferences: []v1.OwnerReference{{Kind: "DaemonSet", Name: "agent", Controller: &daemonSet}}}},
+		{ObjectMeta: v1.ObjectMeta{Name: "db", Labels: map[string]string{"app": "db"}}},
+	}
+	tests := []struct {
+		name  string
+		drain *v1alpha1.DrainSpec
+		want  []string
+	}{
+		{name: "default", drain: &v1alpha1.DrainSpec{}, want: []string{"app", "db"}},
+		{name: "daemonset pods", drain: &v1alpha1.DrainSpec{EvictDaemonSetPods: true}, want: []string{"app", "daemon", "db"}},
+		{name: "pod selector", drain: &v1alpha1.DrainSpec{PodSelector: &v1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}}, want: []string{"app"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			evictable, err := EvictablePods(pods, test.drain)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var names []string
+			for _, pod := range evictable {
+				names = append(names, pod.Name)
+			}
+			if !reflect.DeepEqual(names, test.want) {
+				t.Errorf("expected the pods %v to be evicted, got %v", test.want, names)
+			}
+		})
+	}
+}
+
+func TestCordonNode(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(
+		&metav1.Node{ObjectMeta: v1.ObjectMeta{Name: "edge-1"}},
+		&metav1.Node{ObjectMeta: v1.ObjectMeta{Name: "edge-2"}, Spec: metav1.NodeSpec{Unschedulable: true}},
+	)
+	for _, name := range []string{"edge-1", "edge-2"} {
+		if err := CordonNode(kubeClient, name, "upgrade"); err != nil {
+			t.Fatalf("failed to cordon node %s: %v", name, err)
+		}
+		if err := UncordonNode(kubeClient, name, "other"); err != nil {
+			t.Fatalf("failed to uncordon node %s: %v", name, err)
+		}
+	}
+	node, _ := kubeClient.CoreV1().Nodes().Get(context.TODO(), "edge-1", v1.GetOptions{})
+	if !node.Spec.Unschedulable || node.Annotations[CordonedByAnnotationKey] != "upgrade" {
+		t.Fatalf("expected the node to stay cordoned by the task, got %+v", node)
+	}
+
+	for _, name := range []string{"edge-1", "edge-2"} {
+		if err := UncordonNode(kubeClient, name, "upgrade"); err != nil {
+			t.Fatalf("failed to uncordon node %s: %v", name, err)
+		}
+	}
+	node, _ = kubeClient.CoreV1().Nodes().Get(context.TODO(), "edge-1", v1.GetOptions{})
+	if node.Spec.Unschedulable || node.Annotations[CordonedByAnnotationKey] != "" {
+		t.Errorf("expected the node cordoned by the task to be uncordoned, got %+v", node)
+	}
+	node, _ = kubeClient.CoreV1().Nodes().Get(context.TODO(), "edge-2", v1.GetOptions{})
+	if !node.Spec.Unschedulable {
+		t.Errorf("expected the node cordoned before the task to stay cordoned")
+	}
+}
+
+func TestShuffleNodes(t *testing.T) {
+	names := func(nodes []v1alpha1.TaskStatus) []string {
+		var names []string
+		for _, node := range nodes {
+			names = append(names, node.NodeName)
+		}
+		return names
+	}
+	nodes := []v1alpha1.TaskStatus{{NodeName: "edge-1"}, {NodeName: "edge-2"}, {NodeName: "edge-3"}, {NodeName: "edge-4"}, {NodeName: "edge-5"}}
+	reversed := []v1alpha1.TaskStatus{{NodeName: "edge-5"}, {NodeName: "edge-4"}, {NodeName: "edge-3"}, {NodeName: "edge-2"}, {NodeName: "edge-1"}}
+	ShuffleNodes(nodes, 42)
+	ShuffleNodes(reversed, 42)
+	if !reflect.DeepEqual(names(nodes), names(reversed)) {
+		t.Errorf("expected the same nodes to be shuffled in the same order with the same seed, got %v and %v", names(nodes), names(reversed))
+	}
+
+	seed := int64(7)
+	recorded := int64(9)
+	if OrderingSeed(v1alpha1.NodeOrderingDefault, &seed, nil) != nil {
+		t.Errorf("expected the nodes not to be shuffled with the default ordering")
+	}
+	if got := OrderingSeed(v1alpha1.NodeOrderingRandom, &seed, &recorded); *got != recorded {
+		t.Errorf("expected the recorded seed to be kept, got %d", *got)
+	}
+	if got := OrderingSeed(v1alpha1.NodeOrderingRandom, &seed, nil); *got != seed {
+		t.Errorf("expected the seed of the spec, got %d", *got)
+	}
+	if OrderingSeed(v1alpha1.NodeOrderingRandom, nil, nil) == nil {
+		t.Errorf("expected a random seed to be picked")
+	}
+}
+
+func TestFailureClusters(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, node := range []struct{ name, arch, version, site string }{
+		{"edge-1", "arm", "v1.22.6-kubeedge-v1.14.2", "hangzhou"},
+		{"edge-2", "arm", "v1.22.6-kubeedge-v1.14.2", "hangzhou"},
+		{"edge-3", "amd64", "v1.22.6-kubeedge-v1.15.0", "shanghai"},
+		{"edge-4", "arm", "v1.22.6-kubeedge-v1.14.2", "hangzhou"},
+	} {
+		if err := indexer.Add(&metav1.Node{
+			ObjectMeta: v1.ObjectMeta{Name: node.name, Labels: map[string]string{"site": node.site}},
+			Status:     metav1.NodeStatus{NodeInfo: metav1.NodeSystemInfo{Architecture: node.arch, KubeletVersion: node.version}},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	nodeStatus := []v1alpha1.TaskStatus{
+		{NodeName: "edge-3", State: api.TaskFailed, Event: "Check", Reason: "disk is full"},
+		{NodeName: "edge-2", State: api.TaskFailed, Event: "Upgrade", Reason: "edgecore exited"},
+		{NodeName: "edge-1", State: api.TaskFailed, Event: "Upgrade", Reason: "edgecore exited"},
+		{NodeName: "edge-4", State: api.TaskSuccessful, Event: "Upgrade"},
+		{NodeName: "edge-5", State: api.TaskFailed, Event: "TimeOut", Environment: &v1alpha1.NodeEnvironment{Architecture: "arm"}},
+	}
+
+	clusters := FailureClusters(nodeStatus, corelisters.NewNodeLister(indexer), []string{"site"})
+	expected := []v1alpha1.FailureCluster{
+		{Reason: "Upgrade", Message: "edgecore exited", Version: "v1.14.2", Architecture: "arm",
+			Labels: map[string]string{"site": "hangzhou"}, Nodes: 2, NodeNames: []string{"edge-1", "edge-2"}},
+		{Reason: "Check", Message: "disk is full", Version: "v1.15.0", Architecture: "amd64",
+			Labels: map[string]string{"site": "shanghai"}, Nodes: 1, NodeNames: []string{"edge-3"}},
+		{Reason: "TimeOut", Architecture: "arm", Nodes: 1, NodeNames: []string{"edge-5"}},
+	}
+	if !reflect.DeepEqual(clusters, expected) {
+		t.Errorf("expected the clusters %v, got %v", expected, clusters)
+	}
+	summary := "2 failed on Upgrade (v1.14.2, arm, site=hangzhou); 1 failed on Check (v1.15.0, amd64, site=shanghai); 1 failed on TimeOut (arm)"
+	if got := FailureClusterSummary(clusters); got != summary {
+		t.Errorf("expected the summary %q, got %q", summary, got)
+	}
+}
+
+func TestTransitionExport(t *testing.T) {
+	posted := make(chan []NodeTransition, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Basic token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var batch []NodeTransition
+		decoder := json.NewDecoder(r.Body)
+		for decoder.More() {
+			var transition NodeTransition
+			if err := decoder.Decode(&transition); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			batch = append(batch, transition)
+		}
+		posted <- batch
+	}))
+	defer server.Close()
+
+	InitTransitionExport(&cloudcorev1alpha1.TaskManagerTransitionExport{
+		URL:                  server.URL,
+		Format:               cloudcorev1alpha1.TransitionExportFormatNDJSON,
+		Headers:              map[string]string{"Authorization": "Basic token"},
+		BatchSize:            2,
+		FlushIntervalSeconds: 60,
+		TimeoutSeconds:       1,
+	})
+	defer InitTransitionExport(nil)
+	stop := make(chan struct{})
+	StartTransitionExport(stop)
+
+	ExportNodeTransition(TaskUpgrade, "upgrade", v1alpha1.TaskStatus{NodeName: "edge-1", State: api.NodeUpgrading, Event: "Upgrade", Action: api.ActionSuccess})
+	ExportNodeTransition(TaskUpgrade, "upgrade", v1alpha1.TaskStatus{NodeName: "edge-2", State: api.TaskFailed, Event: "Check", Action: api.ActionFailure, Reason: "disk is full"})
+	ExportNodeTransition(TaskUpgrade, "upgrade", v1alpha1.TaskStatus{NodeName: "edge-1", State: api.TaskSuccessful, Event: "Upgrade", Action: api.ActionSuccess})
+
+	var batch []NodeTransition
+	select {
+	case batch = <-posted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no transition is exported")
+	}
+	if len(batch) != 2 || batch[1].NodeName != "edge-2" || batch[1].State != string(api.TaskFailed) || batch[1].Reason != "disk is full" {
+		t.Fatalf("expected the first full batch of transitions, got %+v", batch)
+	}
+	// the pending transitions are exported when cloudcore stops
+	close(stop)
+	select {
+	case batch = <-posted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the pending transition is not exported")
+	}
+	if len(batch) != 1 || batch[0].State != string(api.TaskSuccessful) {
+		t.Errorf("expected the pending transition, got %+v", batch)
+	}
+}
+
+func TestTransitionExportRetry(t *testing.T) {
+	posted := make(chan []NodeTransition, 10)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the store is unavailable for the first post
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var batch []NodeTransition
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		posted <- batch
+	}))
+	defer server.Close()
+
+	InitTransitionExport(&cloudcorev1alpha1.TaskManagerTransitionExport{
+		URL:                  server.URL,
+		Format:               cloudcorev1alpha1.TransitionExportFormatJSON,
+		BatchSize:            1,
+		FlushIntervalSeconds: 1,
+		TimeoutSeconds:       1,
+	})
+	defer InitTransitionExport(nil)
+	stop := make(chan struct{})
+	defer close(stop)
+	StartTransitionExport(stop)
+
+	ExportNodeTransition(TaskUpgrade, "upgrade", v1alpha1.TaskStatus{NodeName: "edge-1", State: api.TaskSuccessful})
+	ExportNodeTransition(TaskUpgrade, "upgrade", v1alpha1.TaskStatus{NodeName: "edge-2", State: api.TaskFailed})
+
+	// the failed batch is posted again before the later transitions
+	var nodes []string
+	for len(nodes) < 2 {
+		select {
+		case batch := <-posted:
+			for _, transition := range batch {
+				nodes = append(nodes, transition.NodeName)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("expected the transitions to be exported again, got %v", nodes)
+		}
+	}
+	if !reflect.DeepEqual(nodes, []string{"edge-1", "edge-2"}) {
+		t.Errorf("expected the transitions to be exported in order, got %v", nodes)
+	}
+}
+
+func TestTaskAlerts(t *testing.T) {
+	posted := make(chan []Alert, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []Alert
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		posted <- batch
+	}))
+	defer server.Close()
+
+	InitAlerts(&cloudcorev1alpha1.TaskManagerAlertWebhook{
+		URL:                   server.URL,
+		TimeoutSeconds:        1,
+		RepeatIntervalSeconds: 60,
+		Labels:                map[string]string{"cluster": "edge"},
+	})
+	defer InitAlerts(nil)
+	stop := make(chan struct{})
+	defer close(stop)
+	StartAlerts(stop)
+
+	receive := func() []Alert {
+		select {
+		case batch := <-posted:
+			return batch
+		case <-time.After(5 * time.Second):
+			t.Fatal("no alert is posted")
+			return nil
+		}
+	}
+
+	NotifyTaskState(TaskUpgrade, "upgrade", api.TaskChecking, "")
+	NotifyTaskState(TaskUpgrade, "upgrade", api.TaskFailed, "2 nodes failed")
+	// the alert is already firing
+	NotifyTaskState(TaskUpgrade, "upgrade", api.TaskFailed, "2 nodes failed")
+	firing := receive()
+	if len(firing) != 1 || !firing[0].EndsAt.After(time.Now()) {
+		t.Fatalf("expected 1 firing alert, got %+v", firing)
+	}
+	expectedLabels := map[string]string{
+		"cluster":   "edge",
+		"alertname": AlertTaskFailed,
+		"task_type": TaskUpgrade,
+		"task":      "upgrade",
+		"severity":  AlertSeverityCritical,
+	}
+	if !reflect.DeepEqual(firing[0].Labels, expectedLabels) {
+		t.Errorf("expected labels %v, got %v", expectedLabels, firing[0].Labels)
+	}
+	if summary := firing[0].Annotations["summary"]; summary != "upgrade task upgrade is Failed: 2 nodes failed" {
+		t.Errorf("unexpected summary %q", summary)
+	}
+
+	NotifyTaskState(TaskUpgrade, "upgrade", api.TaskSuccessful, "")
+	resolved := receive()
+	if len(resolved) != 1 || resolved[0].Labels["alertname"] != AlertTaskFailed || resolved[0].EndsAt.After(time.Now()) {
+		t.Fatalf("expected the alert to be resolved, got %+v", resolved)
+	}
+}
+
+func TestKeepCommandDigest(t *testing.T) {
+	former := &v1alpha1.TaskStatus{State: api.TaskChecking, CommandDigest: "sha256:upgrading"}
+
+	// the node verified from cloud reports no digest
+	status := v1alpha1.TaskStatus{State: api.TaskSuccessful}
+	KeepCommandDigest(&status, former)
+	if status.CommandDigest != "sha256:upgrading" {
+		t.Errorf("expected the former digest to be kept, got %q", status.CommandDigest)
+	}
+
+	status = v1alpha1.TaskStatus{State: api.TaskFailed, CommandDigest: "sha256:rollingback"}
+	KeepCommandDigest(&status, former)
+	if status.CommandDigest != "sha256:rollingback" {
+		t.Errorf("expected the reported digest, got %q", status.CommandDigest)
+	}
+
+	status = v1alpha1.TaskStatus{State: api.TaskSuccessful}
+	KeepCommandDigest(&status, nil)
+	if status.CommandDigest != "" {
+		t.Errorf("expected no digest, got %q", status.CommandDigest)
+	}
+}
+
+func TestRecordNodeHistory(t *testing.T) {
+	config := &cloudcorev1alpha1.TaskManagerTransitionHistory{Enable: true, MaxRecords: 6}
+	record := func(state api.State, action api.Action) v1alpha1.TaskStatus {
+		return v1alpha1.TaskStatus{NodeName: "node1", State: state, Event: "Upgrade", Action: action}
+	}
+
+	var former *v1alpha1.TaskStatus
+	transitions := []v1alpha1.TaskStatus{
+		record(api.TaskInit, api.ActionSuccess),
+		record(api.UpgradingState, api.ActionFailure),
+		record(api.UpgradingState, api.ActionSuccess),
+		record(api.UpgradingState, api.ActionSuccess),
+		record(api.UpgradingState, api.ActionFailure),
+		record(api.UpgradingState, api.ActionSuccess),
+		record(api.UpgradingState, api.ActionSuccess),
+		record(api.UpgradingState, api.ActionSuccess),
+		record(api.TaskSuccessful, api.ActionSuccess),
+	}
+	for i := range transitions {
+		RecordNodeHistory(&transitions[i], former, config)
+		former = &transitions[i]
+	}
+
+	history := former.History
+	if len(history) != 6 {
+		t.Fatalf("expected the history to be compacted to 6 records, got %+v", history)
+	}
+	// the first record, the summary of 4 transitions, the latest failure and the latest 3 records
+	if history[0].State != api.TaskInit || history[1].Compacted != 4 || history[2].Action != api.ActionFailure {
+		t.Errorf("unexpected compacted history %+v", history)
+	}
+	if history[5].State != api.TaskSuccessful {
+		t.Errorf("expected the latest transition to be kept, got %+v", history[5])
+	}
+	var count int32
+	for _, r := range history {
+		count += max(r.Compacted, 1)
+	}
+	if count != int32(len(transitions)) {
+		t.Errorf("expected the history to count %d transitions, got %d", len(transitions), count)
+	}
+
+	status := record(api.TaskInit, api.ActionSuccess)
+	RecordNodeHistory(&status, nil, &cloudcorev1alpha1.TaskManagerTransitionHistory{MaxRecords: 6})
+	if status.History != nil {
+		t.Errorf("expected no history when disabled, got %+v", status.History)
+	}
+}
+
+func TestNodeCohortLabels(t *testing.T) {
+	cohorts := &v1alpha1.NodeCohortSpec{
+		Labels:          map[string]string{"job": "upgrade-1"},
+		SucceededLabels: map[string]string{"version": "v1.19.0", "outcome": "succeeded"},
+		FailedLabels:    map[string]string{"failed": "true", "outcome": "failed"},
+	}
+	value := func(s string) *string { return &s }
+	tests := []struct {
+		state api.State
+		want  map[string]*string
+	}{
+		{
+			state: api.TaskSuccessful,
+			want:  map[string]*string{"job": value("upgrade-1"), "version": value("v1.19.0"), "outcome": value("succeeded"), "failed": nil},
+		},
+		{
+			state: api.TaskFailed,
+			want:  map[string]*string{"job": value("upgrade-1"), "version": nil, "outcome": value("failed"), "failed": value("true")},
+		},
+		{state: api.TaskSkipped},
+		{state: api.TaskNodeRemoved},
+	}
+	for _, test := range tests {
+		t.Run(string(test.state), func(t *testing.T) {
+			if got := NodeCohortLabels(cohorts, test.state); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("NodeCohortLabels() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTaskNotification(t *testing.T) {
+	nodes := CountNotificationNodes([]v1alpha1.TaskStatus{
+		{NodeName: "edge-1", State: api.TaskSuccessful},
+		{NodeName: "edge-2", State: api.TaskFailed},
+		{NodeName: "edge-3", State: api.TaskSkipped},
+		{NodeName: "edge-4", State: api.TaskNodeRemoved},
+		{NodeName: "edge-5", State: api.TaskSuccessful},
+	})
+	if expected := (TaskNotificationNodes{Total: 5, Succeeded: 2, Failed: 1, Skipped: 2}); nodes != expected {
+		t.Errorf("expected nodes %+v, got %+v", expected, nodes)
+	}
+	notification := TaskNotification{
+		Type:   NotificationFinished,
+		Kind:   "NodeUpgradeJob",
+		Name:   "upgrade",
+		State:  api.TaskSuccessful,
+		Reason: "done",
+		Nodes:  nodes,
+	}
+	if got := notificationSummary(notification); got != "NodeUpgradeJob upgrade is Successful, 2/5 nodes succeeded, 1 failed, 2 skipped: done" {
+		t.Errorf("unexpected summary: %s", got)
+	}
+	notification.Type, notification.State, notification.Reason = NotificationFailureToleranceExceeded, "", ""
+	if got := notificationSummary(notification); got != "NodeUpgradeJob upgrade exceeded its failure tolerance, 1/5 nodes failed" {
+		t.Errorf("unexpected summary: %s", got)
+	}
+
+	interval := notificationRetryInterval
+	notificationRetryInterval = time.Millisecond
+	defer func() { notificationRetryInterval = interval }()
+	var attempts int
+	var received TaskNotification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode the notification: %v", err)
+		}
+	}))
+	defer server.Close()
+	if err := postNotification(v1alpha1.NotificationWebhook{URL: server.URL}, notification); err != nil {
+		t.Fatalf("failed to post the notification: %v", err)
+	}
+	if attempts != 2 || received.Type != NotificationFailureToleranceExceeded || received.Nodes != nodes {
+		t.Errorf("expected the notification to be posted once retried, got %d attempts and %+v", attempts, received)
+	}
+
+	attempts = 0
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	if err := postNotification(v1alpha1.NotificationWebhook{URL: failing.URL}, notification); err == nil || attempts != notificationAttempts {
+		t.Errorf("expected the notification to be dropped after %d attempts, got %d attempts: %v", notificationAttempts, attempts, err)
+	}
+}
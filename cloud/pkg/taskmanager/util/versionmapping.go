@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+// VersionMappingOfLabelKey is set on the task created for a version mapping to the name of the task
+// declaring the mapping
+const VersionMappingOfLabelKey = "operations.kubeedge.io/version-mapping-of"
+
+// AssignVersionMappings returns the names of the nodes selected by each mapping in order,
+// a node selected by several mappings is assigned to the first of them.
+func AssignVersionMappings(mappings []v1alpha1.VersionMapping, nodes []corev1.Node) ([][]string, error) {
+	assigned := make([][]string, len(mappings))
+	selectors := make([]labels.Selector, len(mappings))
+	for i, mapping := range mappings {
+		selector, err := metav1.LabelSelectorAsSelector(mapping.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector of version mapping %s: %v", mapping.Name, err)
+		}
+		selectors[i] = selector
+	}
+	for _, node := range nodes {
+		for i, selector := range selectors {
+			if selector.Matches(labels.Set(node.Labels)) {
+				assigned[i] = append(assigned[i], node.Name)
+				break
+			}
+		}
+	}
+	for i := range assigned {
+		sort.Strings(assigned[i])
+	}
+	return assigned, nil
+}
+
+// VersionMappingsState returns the state of a task from the states of its version mappings and why,
+// the state is empty as long as a mapping is not finished. The mappings selecting no node are left out.
+func VersionMappingsState(mappings []v1alpha1.VersionMappingStatus) (api.State, string) {
+	var successful, total int
+	for _, mapping := range mappings {
+		if mapping.Job == "" {
+			continue
+		}
+		if !fsm.TaskFinish(mapping.State) {
+			return "", ""
+		}
+		if mapping.State == api.TaskSuccessful {
+			successful++
+		}
+		total++
+	}
+	reason := fmt.Sprintf("%d/%d version mappings succeeded", successful, total)
+	switch {
+	case total == 0:
+		return api.TaskFailed, "no node is selected by the version mappings"
+	case successful == total:
+		return api.TaskSuccessful, reason
+	case successful > 0:
+		return api.TaskPartiallySucceeded, reason
+	default:
+		return api.TaskFailed, reason
+	}
+}
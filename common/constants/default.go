@@ -30,6 +30,14 @@ const (
 	DefaultCertURL              = "/edge.crt"
 	DefaultNodeUpgradeURL       = "/nodeupgrade"
 	DefaultTaskStateReportURL   = "/task/{taskType}/name/{taskID}/node/{nodeID}/status"
+	DefaultTaskStatusURL        = "/task/{taskType}/name/{taskID}/status"
+	DefaultTaskDeadLettersURL   = "/task/{taskType}/name/{taskID}/deadletters"
+	DefaultTaskRedriveURL       = "/task/{taskType}/name/{taskID}/deadletters/redrive"
+	DefaultTaskBundleURL        = "/task/{taskType}/name/{taskID}/bundle"
+	DefaultTaskAlertsURL        = "/task/alerts"
+	DefaultTaskNodeBundleURL    = "/task/{taskType}/name/{taskID}/node/{nodeID}/bundle"
+	DefaultTaskPullURL          = "/task/node/{nodeID}/pull"
+	DefaultTaskMessageURL       = "/task/node/{nodeID}/messages"
 	DefaultServiceAccountIssuer = "https://kubernetes.default.svc.cluster.local"
 
 	// Edged
@@ -122,6 +130,25 @@ const (
 	DefaultNodeUpgradeJobStatusBuffer = 1024
 	DefaultNodeUpgradeJobEventBuffer  = 1
 	DefaultNodeUpgradeJobWorkers      = 1
+	DefaultNodeFilterWebhookTimeout   = 10
+	DefaultFlappingDisconnects        = 3
+	DefaultSlowRTTMilliseconds        = 1000
+	DefaultSupportBundleDir           = "/var/lib/kubeedge/support-bundles"
+	DefaultSupportBundleUploadTimeout = 30
+	DefaultAlertWebhookTimeout        = 10
+	DefaultAlertRepeatInterval        = 60
+	DefaultStalledExecutorSeconds     = 1800
+	DefaultTaskCredentialNamespace    = "kubeedge"
+
+	DefaultTransitionExportBatchSize     = 100
+	DefaultTransitionExportFlushInterval = 10
+	DefaultTransitionExportTimeout       = 10
+	DefaultTransitionHistoryMaxRecords   = 20
+
+	DefaultTaskManagerLeaseName     = "taskmanager"
+	DefaultTaskManagerLeaseDuration = 15
+	DefaultTaskManagerRenewDeadline = 10
+	DefaultTaskManagerRetryPeriod   = 2
 
 	// ImagePrePullController
 	DefaultImagePrePullJobStatusBuffer = 1024
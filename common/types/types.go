@@ -8,6 +8,7 @@ import (
 
 	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
 	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/envelope"
 )
 
 // PodStatusRequest is Message.Content which comes from edge
@@ -38,6 +39,8 @@ type NodeUpgradeJobRequest struct {
 	Version     string
 	UpgradeTool string
 	Image       string
+	// VerificationProbes are run by the edge node after EdgeCore is upgraded
+	VerificationProbes []v1alpha1.VerificationProbe `json:",omitempty"`
 }
 
 // NodeUpgradeJobResponse is used to report status msg to cloudhub https service
@@ -54,6 +57,24 @@ type NodeUpgradeJobResponse struct {
 // NodePreCheckRequest is pre-check msg coming from cloud to edge
 type NodePreCheckRequest struct {
 	CheckItem []string
+	// DryRun asks the node to also check it could run the task, e.g. the upgrade tools can be
+	// pulled and run, and to report what it found even if the checks pass
+	DryRun bool
+	// RotateCertificate asks the node to request the rotation of its certificate from edgehub if it
+	// expires soon instead of failing the cert check item
+	RotateCertificate bool
+	// RegistryProbe is what the registry check item measures, the task pulls no image if it is nil
+	RegistryProbe *RegistryProbe
+}
+
+// RegistryProbe describes the images the task pulls on the node, the node checks it can download
+// them from their registry in time
+type RegistryProbe struct {
+	Images []string
+	// Secret is the pull secret of the images, namespace/name
+	Secret string
+	// TimeoutSeconds is how long the node has to pull the images
+	TimeoutSeconds uint32
 }
 
 type NodeTaskRequest struct {
@@ -61,6 +82,24 @@ type NodeTaskRequest struct {
 	Type   string
 	State  string
 	Item   interface{}
+	// Reconcile is set when cloud re-dispatches a task after a restart, the edge
+	// replays its buffered result for the same state instead of executing it again.
+	Reconcile bool
+	// SealedItem is the Item encrypted for the edge node, Item is empty if it is set.
+	SealedItem *envelope.Envelope `json:",omitempty"`
+	// Metadata is the custom metadata of the task, it is passed to the commands run for the task
+	// and echoed back in the response.
+	Metadata map[string]string `json:",omitempty"`
+	// SealedCredential is the TaskCredential minted for the edge node, encrypted for it
+	SealedCredential *envelope.Envelope `json:",omitempty"`
+	// Credential is the opened SealedCredential, it is never sent
+	Credential *TaskCredential `json:"-"`
+}
+
+// TaskCredential is the short-lived token minted for the edge node to run a task
+type TaskCredential struct {
+	Token               string
+	ExpirationTimestamp metaV1.Time
 }
 
 type NodeTaskResponse struct {
@@ -77,10 +116,64 @@ type NodeTaskResponse struct {
 	Action api.Action
 	// Reason represents for the reason of the ImagePrePullJob.
 	Reason string
-	// Time represents for the running time of the ImagePrePullJob.
+	// Time is the time of the edge node when it reported the result. It is informational only, the
+	// clock of the edge node may be skewed from the one of cloud.
 	Time string
+	// ElapsedSeconds is how long the edge node ran the stage for, measured by its monotonic clock so
+	// that cloud can rely on it whatever the skew of the clock of the edge node.
+	ElapsedSeconds int64 `json:",omitempty"`
 
 	ExternalMessage string
+	// Environment is a snapshot of the execution environment of the edge node
+	Environment *v1alpha1.NodeEnvironment `json:",omitempty"`
+	// BytesDownloaded is the size of the images pulled by the edge node in the stage
+	BytesDownloaded int64 `json:",omitempty"`
+	// Metadata is the custom metadata of the task the response belongs to
+	Metadata map[string]string `json:",omitempty"`
+	// CommandDigest is the digest of the record of the commands the edge node ran for the task
+	CommandDigest string `json:",omitempty"`
+}
+
+// NodeTaskReceipt is sent by the edge node once it has validated and queued a task,
+// before the task is executed.
+type NodeTaskReceipt struct {
+	NodeName string
+	// Type is the type of the task
+	Type string
+	// State is the task state the receipt belongs to
+	State string
+}
+
+// NodeTaskProbe is the preflight probe cloud sends to the edge node before the task is dispatched
+// to it, the edge node answers it right away with the same probe.
+type NodeTaskProbe struct {
+	NodeName string
+	// Type and TaskID identify the task the probe belongs to
+	Type   string
+	TaskID string
+}
+
+// NodeTaskReport is the last task result buffered on the edge node
+type NodeTaskReport struct {
+	// Type and TaskID identify the task the report belongs to.
+	Type   string `json:",omitempty"`
+	TaskID string `json:",omitempty"`
+	// State is the task state the response belongs to.
+	State    string
+	Response NodeTaskResponse
+}
+
+// NodeTaskKey identifies a task
+type NodeTaskKey struct {
+	Type   string
+	TaskID string
+}
+
+// NodeTaskInventory lists the tasks whose state is persisted on the edge node. The edge node
+// reports its inventory to cloud, and cloud replies with the tasks that no longer exist.
+type NodeTaskInventory struct {
+	NodeName string
+	Tasks    []NodeTaskKey
 }
 
 // ObjectResp is the object that api-server response
@@ -98,6 +191,58 @@ type ImagePrePullJobRequest struct {
 	CheckItems []string
 }
 
+// SupportBundleJobRequest is support bundle msg from cloud to edge
+type SupportBundleJobRequest struct {
+	// LogLines is the number of the most recent edgecore log lines in the bundle
+	LogLines int32
+}
+
+// ConfigUpdateJobRequest is config update msg from cloud to edge
+type ConfigUpdateJobRequest struct {
+	// Patch is the JSON merge patch applied to the edgecore config
+	Patch []byte
+}
+
+// NodeRestartJobRequest is node restart msg from cloud to edge
+type NodeRestartJobRequest struct {
+	// Reboot reboots the host instead of restarting edgecore only
+	Reboot bool
+}
+
+// DiagnoseJobRequest is diagnose msg from cloud to edge
+type DiagnoseJobRequest struct {
+	// Checks are the checks run on the node, all of them are run if it is empty
+	Checks []string
+}
+
+// BackupJobRequest is backup msg from cloud to edge
+type BackupJobRequest struct {
+	// Location is the directory the backup is written to, in a subdirectory named after the job
+	Location string
+}
+
+// RestoreJobRequest is restore msg from cloud to edge
+type RestoreJobRequest struct {
+	// BackupJob is the name of the BackupJob whose backup is restored
+	BackupJob string
+	// Location is the directory the BackupJob wrote its backup to
+	Location string
+}
+
+// OSUpgradeJobRequest is OS upgrade msg from cloud to edge
+type OSUpgradeJobRequest struct {
+	// ArtifactURL is where the node downloads the artifact from
+	ArtifactURL string
+	// ArtifactSHA256 is the hex encoded SHA-256 digest of the artifact
+	ArtifactSHA256 string
+	// Version is the version of the OS or firmware the artifact updates the node to
+	Version string
+	// Hook is the file name of the signed hook script which applies the artifact
+	Hook string
+	// HookSHA256 pins the hex encoded SHA-256 digest of the hook script if it is not empty
+	HookSHA256 string
+}
+
 // ImagePrePullJobResponse is used to report status msg to cloudhub https service from each node
 type ImagePrePullJobResponse struct {
 	NodeName    string
@@ -18,6 +18,7 @@ package util
 
 import (
 	"fmt"
+	"sync/atomic"
 
 	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
 	"github.com/kubeedge/beehive/pkg/core/model"
@@ -25,8 +26,47 @@ import (
 	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
 )
 
+const (
+	// TaskAccept is the operation of the receipts of tasks accepted by the edge node
+	TaskAccept = "accept"
+	// TaskProbe is the operation of the preflight probes of tasks and of their answers
+	TaskProbe = "probe"
+)
+
+// taskSender sends the task messages of the edge node to cloud, they are sent through edgehub
+// unless the node polls for its tasks over HTTPS
+var taskSender atomic.Value
+
+// SetTaskSender replaces how the task messages of the edge node are sent to cloud
+func SetTaskSender(send func(model.Message)) {
+	taskSender.Store(send)
+}
+
+// SendTaskMessage sends the task message of the edge node to cloud
+func SendTaskMessage(msg model.Message) {
+	if send, ok := taskSender.Load().(func(model.Message)); ok {
+		send(msg)
+		return
+	}
+	beehiveContext.Send(modules.EdgeHubModuleName, msg)
+}
+
 func ReportTaskResult(taskType, taskID string, resp types.NodeTaskResponse) {
 	msg := model.NewMessage("").SetRoute(modules.EdgeHubModuleName, modules.HubGroup).
 		SetResourceOperation(fmt.Sprintf("task/%s/node/%s", taskID, resp.NodeName), taskType).FillBody(resp)
-	beehiveContext.Send(modules.EdgeHubModuleName, *msg)
+	SendTaskMessage(*msg)
+}
+
+// ReportTaskReceipt tells cloud that the task has been accepted by the edge node
+func ReportTaskReceipt(taskID string, receipt types.NodeTaskReceipt) {
+	msg := model.NewMessage("").SetRoute(modules.EdgeHubModuleName, modules.HubGroup).
+		SetResourceOperation(fmt.Sprintf("task/%s/node/%s", taskID, receipt.NodeName), TaskAccept).FillBody(receipt)
+	SendTaskMessage(*msg)
+}
+
+// AnswerTaskProbe tells cloud that the edge node is reachable over the task channel
+func AnswerTaskProbe(taskID string, probe types.NodeTaskProbe) {
+	msg := model.NewMessage("").SetRoute(modules.EdgeHubModuleName, modules.HubGroup).
+		SetResourceOperation(fmt.Sprintf("task/%s/node/%s", taskID, probe.NodeName), TaskProbe).FillBody(probe)
+	SendTaskMessage(*msg)
 }
@@ -40,6 +40,19 @@ var jitteryDuration = func(totalDuration float64) time.Duration {
 
 var CleanupTokenChan = make(chan struct{}, 1)
 
+// rotationRequests asks the rotation process of the CertManager to rotate the certificate before its deadline
+var rotationRequests = make(chan struct{}, 1)
+
+// RequestRotation asks the CertManager of EdgeHub to rotate the edge certificate now rather than at
+// its rotation deadline. It does not block, the requests made before the rotation starts are merged.
+// It takes effect only if certificate rotation is enabled.
+func RequestRotation() {
+	select {
+	case rotationRequests <- struct{}{}:
+	default:
+	}
+}
+
 type CertManager struct {
 	RotateCertificates bool
 	NodeName           string
@@ -150,7 +163,11 @@ func (cm *CertManager) rotate() {
 			timer := time.NewTimer(sleepInterval)
 			defer timer.Stop()
 
-			<-timer.C // unblock when deadline expires
+			select {
+			case <-timer.C: // unblock when deadline expires
+			case <-rotationRequests:
+				klog.Infof("Certificate rotation is requested before the deadline")
+			}
 		}
 
 		backoff := wait.Backoff{
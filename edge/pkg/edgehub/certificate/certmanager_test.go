@@ -101,3 +101,19 @@ P1Iiy/r5PerqODcCIQCH+qeEuxIgZzUAD/Wm6xameEmyn/mO4su/4UE6APNZFQ==
 		require.NoError(t, err)
 	})
 }
+
+func TestRequestRotation(t *testing.T) {
+	// the requests made before the rotation starts are merged into one
+	RequestRotation()
+	RequestRotation()
+	select {
+	case <-rotationRequests:
+	default:
+		t.Fatalf("expected the rotation to be requested")
+	}
+	select {
+	case <-rotationRequests:
+		t.Errorf("expected the requests to be merged")
+	default:
+	}
+}
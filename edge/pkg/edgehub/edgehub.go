@@ -14,7 +14,8 @@ import (
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/clients"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/config"
 	// register Task handler
-	_ "github.com/kubeedge/kubeedge/edge/pkg/edgehub/task"
+	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/task"
+	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/task/taskexecutor"
 	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
 )
 
@@ -85,6 +86,16 @@ func (eh *EdgeHub) Start() {
 
 	go eh.ifRotationDone()
 
+	// the tasks are polled before the results are reported, they are reported over HTTPS then
+	if config.Config.TaskPollInterval > 0 {
+		task.PollTasks(&config.Config.EdgeHub, config.Config.NodeName)
+	}
+	go task.ReportTaskInventory(config.Config.NodeName)
+	go taskexecutor.ReportConfigUpdate(config.Config.NodeName)
+	go taskexecutor.ReportNodeRestart(config.Config.NodeName)
+	go taskexecutor.ReportRestore(config.Config.NodeName)
+	go taskexecutor.ReportOSUpgrade(config.Config.NodeName)
+
 	for {
 		select {
 		case <-beehiveContext.Done():
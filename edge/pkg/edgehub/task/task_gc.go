@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/cloudconnection"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/util"
+	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/task/taskexecutor"
+	keadmutil "github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+)
+
+const (
+	// TaskInventory is the operation to report the tasks persisted on the node to cloud
+	TaskInventory = "inventory"
+	// TaskPurge is the operation of cloud asking the node to purge the tasks that no longer exist
+	TaskPurge = "purge"
+
+	inventoryInterval = time.Hour
+)
+
+// ReportTaskInventory reports the tasks persisted on the node to cloud once connected, or at once if
+// the node polls for its tasks over HTTPS, and every hour after, cloud replies with the stale ones
+// whose objects have been deleted or finished.
+func ReportTaskInventory(nodeName string) {
+	ticker := time.NewTicker(inventoryInterval)
+	defer ticker.Stop()
+	reported := false
+	for {
+		if (polling.Load() || cloudconnection.IsConnected()) && !reported {
+			reported = reportTaskInventory(nodeName)
+		}
+		select {
+		case <-beehiveContext.Done():
+			klog.Warning("stop reporting task inventory")
+			return
+		case <-ticker.C:
+			reported = false
+		case <-time.After(10 * time.Second):
+		}
+	}
+}
+
+func reportTaskInventory(nodeName string) bool {
+	tasks, err := keadmutil.ListTaskReports()
+	if err != nil {
+		klog.Warningf("failed to list persisted tasks: %v", err)
+		return false
+	}
+	if len(tasks) == 0 {
+		return true
+	}
+	msg := model.NewMessage("").SetRoute(modules.EdgeHubModuleName, modules.HubGroup).
+		SetResourceOperation(fmt.Sprintf("task/%s/node/%s", TaskInventory, nodeName), TaskInventory).
+		FillBody(commontypes.NodeTaskInventory{NodeName: nodeName, Tasks: tasks})
+	util.SendTaskMessage(*msg)
+	return true
+}
+
+// purgeTasks removes the persisted state of the tasks cloud no longer needs, their objects were
+// deleted or the cloud has recorded their final results
+func purgeTasks(message *model.Message) error {
+	data, err := message.GetContentData()
+	if err != nil {
+		return fmt.Errorf("failed to get content data: %v", err)
+	}
+	inventory := commontypes.NodeTaskInventory{}
+	if err = json.Unmarshal(data, &inventory); err != nil {
+		return fmt.Errorf("unmarshal failed: %v", err)
+	}
+	for _, task := range inventory.Tasks {
+		klog.Infof("purge stale task %s/%s", task.Type, task.TaskID)
+		taskexecutor.ForgetTask(task.Type, task.TaskID)
+	}
+	return nil
+}
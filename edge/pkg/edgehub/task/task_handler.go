@@ -19,6 +19,10 @@ package task
 import (
 	"encoding/json"
 	"fmt"
+	"time"
+
+	"k8s.io/client-go/util/keyutil"
+	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/beehive/pkg/core/model"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
@@ -28,6 +32,9 @@ import (
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/clients"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/common/msghandler"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/task/taskexecutor"
+	keadmutil "github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/envelope"
 )
 
 func init() {
@@ -43,6 +50,12 @@ func (th *taskHandler) Filter(message *model.Message) bool {
 }
 
 func (th *taskHandler) Process(message *model.Message, _ clients.Adapter) error {
+	if message.GetOperation() == TaskPurge {
+		return purgeTasks(message)
+	}
+	if message.GetOperation() == util.TaskProbe {
+		return answerProbe(message)
+	}
 	taskReq := &commontypes.NodeTaskRequest{}
 	data, err := message.GetContentData()
 	if err != nil {
@@ -52,10 +65,36 @@ func (th *taskHandler) Process(message *model.Message, _ clients.Adapter) error
 	if err != nil {
 		return fmt.Errorf("unmarshal failed: %v", err)
 	}
+	// cloud is reconciling after a restart, replay the buffered result if this state was already executed
+	if resp, ok := keadmutil.ReplayTaskReport(*taskReq); ok {
+		klog.Infof("replay buffered result of task %s in state %s", taskReq.TaskID, taskReq.State)
+		util.ReportTaskResult(taskReq.Type, taskReq.TaskID, *resp)
+		return nil
+	}
+	// a task starts from its initial state again when it is rerun, it is no longer cancelled
+	if !taskReq.Reconcile && (taskReq.State == "" || taskReq.State == string(api.TaskInit)) {
+		taskexecutor.ForgetTask(taskReq.Type, taskReq.TaskID)
+	}
 	executor, err := taskexecutor.GetExecutor(taskReq.Type)
 	if err != nil {
 		return err
 	}
+	if taskReq.SealedItem != nil {
+		if err = openSealedItem(taskReq); err != nil {
+			return err
+		}
+	}
+	if taskReq.SealedCredential != nil {
+		if err = openSealedCredential(taskReq); err != nil {
+			return err
+		}
+	}
+	util.ReportTaskReceipt(taskReq.TaskID, commontypes.NodeTaskReceipt{
+		NodeName: options.GetEdgeCoreConfig().Modules.Edged.HostnameOverride,
+		Type:     taskReq.Type,
+		State:    taskReq.State,
+	})
+	start := time.Now()
 	event, err := executor.Do(*taskReq)
 	if err != nil {
 		return err
@@ -68,11 +107,70 @@ func (th *taskHandler) Process(message *model.Message, _ clients.Adapter) error
 	}
 
 	resp := commontypes.NodeTaskResponse{
-		NodeName: options.GetEdgeCoreConfig().Modules.Edged.HostnameOverride,
-		Event:    event.Type,
-		Action:   event.Action,
-		Reason:   event.Msg,
+		NodeName:        options.GetEdgeCoreConfig().Modules.Edged.HostnameOverride,
+		Event:           event.Type,
+		Action:          event.Action,
+		Reason:          event.Msg,
+		Environment:     keadmutil.CollectEnvironment(options.GetEdgeCoreConfig()),
+		BytesDownloaded: event.BytesDownloaded,
+		Metadata:        taskReq.Metadata,
+		CommandDigest:   keadmutil.TaskAuditDigest(taskReq.Type, taskReq.TaskID),
+		ElapsedSeconds:  int64(time.Since(start).Seconds()),
+	}
+	if err = keadmutil.SaveTaskReport(taskReq.Type, taskReq.TaskID, taskReq.State, resp); err != nil {
+		klog.Warningf("failed to save task report: %v", err)
 	}
 	util.ReportTaskResult(taskReq.Type, taskReq.TaskID, resp)
 	return nil
 }
+
+// answerProbe answers the preflight probe of a task, nothing is executed on the node
+func answerProbe(message *model.Message) error {
+	data, err := message.GetContentData()
+	if err != nil {
+		return fmt.Errorf("failed to get content data: %v", err)
+	}
+	probe := commontypes.NodeTaskProbe{}
+	if err = json.Unmarshal(data, &probe); err != nil {
+		return fmt.Errorf("unmarshal failed: %v", err)
+	}
+	probe.NodeName = options.GetEdgeCoreConfig().Modules.Edged.HostnameOverride
+	util.AnswerTaskProbe(probe.TaskID, probe)
+	return nil
+}
+
+// openSealedItem decrypts the payload sealed for this node with the private key of its certificate
+func openSealedItem(taskReq *commontypes.NodeTaskRequest) error {
+	privateKey, err := keyutil.PrivateKeyFromFile(options.GetEdgeCoreConfig().Modules.EdgeHub.TLSPrivateKeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load private key: %v", err)
+	}
+	data, err := envelope.Open(privateKey, taskReq.SealedItem)
+	if err != nil {
+		return fmt.Errorf("failed to open sealed payload of task %s: %v", taskReq.TaskID, err)
+	}
+	if err = json.Unmarshal(data, &taskReq.Item); err != nil {
+		return fmt.Errorf("unmarshal sealed payload failed: %v", err)
+	}
+	taskReq.SealedItem = nil
+	return nil
+}
+
+// openSealedCredential decrypts the credential minted for this node with the private key of its certificate
+func openSealedCredential(taskReq *commontypes.NodeTaskRequest) error {
+	privateKey, err := keyutil.PrivateKeyFromFile(options.GetEdgeCoreConfig().Modules.EdgeHub.TLSPrivateKeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load private key: %v", err)
+	}
+	data, err := envelope.Open(privateKey, taskReq.SealedCredential)
+	if err != nil {
+		return fmt.Errorf("failed to open sealed credential of task %s: %v", taskReq.TaskID, err)
+	}
+	credential := &commontypes.TaskCredential{}
+	if err = json.Unmarshal(data, credential); err != nil {
+		return fmt.Errorf("unmarshal sealed credential failed: %v", err)
+	}
+	taskReq.Credential = credential
+	taskReq.SealedCredential = nil
+	return nil
+}
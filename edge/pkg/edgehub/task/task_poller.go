@@ -0,0 +1,215 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/util"
+	commhttp "github.com/kubeedge/kubeedge/edge/pkg/edgehub/common/http"
+	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/common/msghandler"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
+)
+
+const (
+	// pullResponseLimit is the max length of the task messages pulled at once
+	pullResponseLimit = 8 << 20
+	// taskMessageQueueSize is how many task messages of the node wait to be sent to cloud
+	taskMessageQueueSize = 100
+	// taskMessageRetries is how many times a task message is sent again when cloud is unreachable,
+	// the message is dropped then and the buffered result is replayed once cloud reconciles the task
+	taskMessageRetries = 3
+)
+
+// polling is set once the node polls for its tasks over HTTPS
+var polling atomic.Bool
+
+// taskPoller polls the tasks of the node from the HTTPS server of cloudhub and sends the task
+// messages of the node to it, so that the tasks of the node do not depend on its edgehub connection
+type taskPoller struct {
+	nodeName string
+	server   string
+	interval time.Duration
+	// client returns the client with the current certificate of the node, which may be rotated
+	client   func() (*http.Client, error)
+	messages chan model.Message
+}
+
+// PollTasks polls the pending tasks of the node from cloud every taskPollInterval over the HTTPS
+// server of cloudhub with the certificate of the node, the tasks are then handled the same as
+// pushed ones. The receipts and results of the tasks are sent to the same server, neither the polls
+// nor the task messages go through the edgehub connection, which may be down or blocked.
+func PollTasks(eh *v1alpha2.EdgeHub, nodeName string) {
+	p := &taskPoller{
+		nodeName: nodeName,
+		server:   eh.HTTPServer,
+		interval: time.Duration(eh.TaskPollInterval) * time.Second,
+		client: func() (*http.Client, error) {
+			return newNodeClient(eh.TLSCAFile, eh.TLSCertFile, eh.TLSPrivateKeyFile)
+		},
+		messages: make(chan model.Message, taskMessageQueueSize),
+	}
+	polling.Store(true)
+	util.SetTaskSender(p.queue)
+	go p.sendMessages()
+	go p.run()
+}
+
+func newNodeClient(caFile, certFile, keyFile string) (*http.Client, error) {
+	caPem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file %s: %v", caFile, err)
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the certificate of the node: %v", err)
+	}
+	return commhttp.NewHTTPClientWithCA(caPem, cert)
+}
+
+func (p *taskPoller) run() {
+	klog.Infof("poll tasks from cloud every %s", p.interval)
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-beehiveContext.Done():
+			klog.Warning("stop polling tasks")
+			return
+		case <-ticker.C:
+		}
+		msgs, err := p.poll()
+		if err != nil {
+			klog.Warningf("failed to poll tasks from cloud: %v", err)
+			continue
+		}
+		for _, msg := range msgs {
+			if err = msghandler.ProcessHandler(msg, nil); err != nil {
+				klog.Errorf("failed to handle task message %s: %v", msg.GetResource(), err)
+			}
+		}
+	}
+}
+
+// poll takes the pending task messages of the node from cloud
+func (p *taskPoller) poll() ([]model.Message, error) {
+	client, err := p.client()
+	if err != nil {
+		return nil, err
+	}
+	url := p.server + strings.ReplaceAll(constants.DefaultTaskPullURL, "{nodeID}", p.nodeName)
+	req, err := commhttp.BuildRequest(http.MethodGet, url, nil, "", p.nodeName)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := commhttp.SendRequest(req, client)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, pullResponseLimit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the pulled tasks: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to pull tasks, code: %d, message: %s", resp.StatusCode, string(body))
+	}
+	var msgs []model.Message
+	if err = json.Unmarshal(body, &msgs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the pulled tasks: %v", err)
+	}
+	return msgs, nil
+}
+
+// queue queues the task message to be sent to cloud, the messages are sent in order
+func (p *taskPoller) queue(msg model.Message) {
+	select {
+	case p.messages <- msg:
+	case <-beehiveContext.Done():
+	}
+}
+
+func (p *taskPoller) sendMessages() {
+	for {
+		select {
+		case <-beehiveContext.Done():
+			return
+		case msg := <-p.messages:
+			p.sendMessage(msg)
+		}
+	}
+}
+
+// sendMessage sends the task message to cloud, it is retried every poll interval while cloud is unreachable
+func (p *taskPoller) sendMessage(msg model.Message) {
+	for i := 0; ; i++ {
+		err := p.send(msg)
+		if err == nil {
+			return
+		}
+		if i >= taskMessageRetries {
+			klog.Errorf("drop task message %s after %d retries: %v", msg.GetResource(), i, err)
+			return
+		}
+		klog.Warningf("failed to send task message %s, retry in %s: %v", msg.GetResource(), p.interval, err)
+		select {
+		case <-beehiveContext.Done():
+			return
+		case <-time.After(p.interval):
+		}
+	}
+}
+
+func (p *taskPoller) send(msg model.Message) error {
+	client, err := p.client()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task message: %v", err)
+	}
+	url := p.server + strings.ReplaceAll(constants.DefaultTaskMessageURL, "{nodeID}", p.nodeName)
+	req, err := commhttp.BuildRequest(http.MethodPost, url, bytes.NewReader(data), "", p.nodeName)
+	if err != nil {
+		return err
+	}
+	resp, err := commhttp.SendRequest(req, client)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, constants.MaxRespBodyLength))
+		return fmt.Errorf("failed to send task message, code: %d, message: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
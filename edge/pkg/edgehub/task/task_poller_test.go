@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+)
+
+func newTestPoller(server *httptest.Server) *taskPoller {
+	return &taskPoller{
+		nodeName: "edge-1",
+		server:   server.URL,
+		interval: time.Millisecond,
+		client: func() (*http.Client, error) {
+			return server.Client(), nil
+		},
+		messages: make(chan model.Message, taskMessageQueueSize),
+	}
+}
+
+func TestPollTasks(t *testing.T) {
+	task := model.NewMessage("").SetResourceOperation("task/upgrade/node/edge-1", "upgrade")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/task/node/edge-1/pull" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode([]model.Message{*task})
+	}))
+	defer server.Close()
+
+	msgs, err := newTestPoller(server).poll()
+	if err != nil {
+		t.Fatalf("failed to poll tasks: %v", err)
+	}
+	if len(msgs) != 1 || msgs[0].GetID() != task.GetID() || msgs[0].GetResource() != task.GetResource() {
+		t.Errorf("expected the pending task to be polled, got %v", msgs)
+	}
+
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unavailable.Close()
+	if _, err = newTestPoller(unavailable).poll(); err == nil {
+		t.Errorf("expected polling a replica which does not hold the tasks to fail")
+	}
+}
+
+func TestSendTaskMessage(t *testing.T) {
+	received := make(chan model.Message, 1)
+	failures := 2
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/task/node/edge-1/messages" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		msg := model.Message{}
+		if err := json.Unmarshal(body, &msg); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- msg
+	}))
+	defer server.Close()
+
+	// the message is sent again while cloud fails to receive it
+	msg := model.NewMessage("").SetResourceOperation("task/upgrade/node/edge-1", "accept").FillBody("accepted")
+	newTestPoller(server).sendMessage(*msg)
+	select {
+	case got := <-received:
+		if got.GetID() != msg.GetID() || got.GetOperation() != "accept" {
+			t.Errorf("expected the task message to be received, got %v", got)
+		}
+	default:
+		t.Fatalf("expected the task message to be received after retries")
+	}
+
+	failures = taskMessageRetries + 1
+	newTestPoller(server).sendMessage(*msg)
+	select {
+	case got := <-received:
+		t.Errorf("expected the task message to be dropped after %d retries, got %v", taskMessageRetries, got)
+	default:
+	}
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskexecutor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/common/types"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/cmd/edgecore/app/options"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/dbm"
+	edgeutil "github.com/kubeedge/kubeedge/edge/pkg/common/util"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+const (
+	TaskBackup = "backup"
+
+	// backupManifestFile maps the files of a backup to the paths they are restored to
+	backupManifestFile = "manifest.json"
+	// backupDatabaseFile is the name of the local database of edgecore in a backup
+	backupDatabaseFile = "edgecore.db"
+)
+
+// defaultBackupLocation is the directory the backups are written to if the job sets no location
+var defaultBackupLocation = filepath.Join(util.KubeEdgeBackupPath, "jobs")
+
+type Backup struct {
+	*BaseExecutor
+}
+
+func (b *Backup) Name() string {
+	return b.name
+}
+
+func NewBackupExecutor() Executor {
+	methods := map[string]func(types.NodeTaskRequest) fsm.Event{
+		string(api.TaskInit):       emptyInit,
+		"":                         emptyInit,
+		string(api.BackingUpState): backupEdgeCore,
+		string(api.TaskCancelling): cancelTask,
+	}
+	return &Backup{
+		BaseExecutor: NewBaseExecutor(TaskBackup, methods),
+	}
+}
+
+// backupEdgeCore backs up the local database of edgecore, its config and its certificates in
+// background, into a directory named after the job. The backup is reported as JSON in the external
+// message of the result.
+func backupEdgeCore(taskReq types.NodeTaskRequest) fsm.Event {
+	event := fsm.Event{
+		Type:   api.EventBackup,
+		Action: api.ActionFailure,
+	}
+	var backupReq commontypes.BackupJobRequest
+	data, err := json.Marshal(taskReq.Item)
+	if err == nil {
+		err = json.Unmarshal(data, &backupReq)
+	}
+	if err != nil {
+		event.Msg = err.Error()
+		return event
+	}
+
+	edgeCoreConfig := options.GetEdgeCoreConfig()
+	dir := filepath.Join(backupLocation(backupReq.Location), taskReq.TaskID)
+	ctx, done := startStep(taskReq)
+	go func() {
+		defer done()
+		resp := commontypes.NodeTaskResponse{
+			NodeName:    edgeCoreConfig.Modules.Edged.HostnameOverride,
+			Event:       event.Type,
+			Action:      api.ActionSuccess,
+			Environment: util.CollectEnvironment(edgeCoreConfig),
+			Metadata:    taskReq.Metadata,
+
+			CommandDigest: util.TaskAuditDigest(taskReq.Type, taskReq.TaskID),
+		}
+		backup, err := backupFiles(edgeCoreConfig, dir)
+		if err == nil {
+			data, err = json.Marshal(backup)
+		}
+		if ctx.Err() != nil || IsCancelled(taskReq.Type, taskReq.TaskID) {
+			klog.Infof("task %s is cancelled, remove the backup %s", taskReq.TaskID, dir)
+			_ = os.RemoveAll(dir)
+			return
+		}
+		if err != nil {
+			_ = os.RemoveAll(dir)
+			resp.Action = api.ActionFailure
+			resp.Reason = err.Error()
+		} else {
+			resp.Reason = fmt.Sprintf("%d files backed up to %s, %d bytes", len(backup.Files), dir, backup.Size)
+			resp.ExternalMessage = string(data)
+		}
+		edgeutil.ReportTaskResult(taskReq.Type, taskReq.TaskID, resp)
+		endRun(taskReq.Type, taskReq.TaskID)
+	}()
+	return fsm.Event{}
+}
+
+// backupFiles writes the backup to dir along with its manifest, the local database is copied with
+// VACUUM INTO so that the copy is consistent while edgecore writes to it
+func backupFiles(config *v1alpha2.EdgeCoreConfig, dir string) (*v1alpha1.NodeBackup, error) {
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, fmt.Errorf("failed to clean backup directory %s: %v", dir, err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory %s: %v", dir, err)
+	}
+	hub := config.Modules.EdgeHub
+	manifest := map[string]string{
+		backupDatabaseFile: config.DataBase.DataSource,
+		"edgecore.yaml":    options.GetEdgeCoreOptions().ConfigFile,
+		"rootCA.crt":       hub.TLSCAFile,
+		"server.crt":       hub.TLSCertFile,
+		"server.key":       hub.TLSPrivateKeyFile,
+	}
+	names := make([]string, 0, len(manifest))
+	for name := range manifest {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	backup := &v1alpha1.NodeBackup{NodeName: config.Modules.Edged.HostnameOverride, Path: dir}
+	for _, name := range names {
+		src, dst := manifest[name], filepath.Join(dir, name)
+		var err error
+		if name == backupDatabaseFile && dbm.DBAccess != nil {
+			_, err = dbm.DBAccess.Raw("VACUUM INTO ?", dst).Exec()
+		} else {
+			err = filecopy(src, dst)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to back up %s: %v", src, err)
+		}
+		info, err := os.Stat(dst)
+		if err != nil {
+			return nil, err
+		}
+		backup.Files = append(backup.Files, src)
+		backup.Size += info.Size()
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.WriteFile(filepath.Join(dir, backupManifestFile), data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write the backup manifest: %v", err)
+	}
+	return backup, nil
+}
+
+// backupLocation returns the directory the backups of the job are written to
+func backupLocation(location string) string {
+	if location == "" {
+		return defaultBackupLocation
+	}
+	return location
+}
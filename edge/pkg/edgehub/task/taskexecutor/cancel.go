@@ -0,0 +1,225 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskexecutor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/cmd/edgecore/app/options"
+	edgeutil "github.com/kubeedge/kubeedge/edge/pkg/common/util"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+var (
+	// cancelGracePeriod is how long the steps of a cancelled task have to stop before they are killed
+	cancelGracePeriod = 30 * time.Second
+	// killWait is how long the killed steps have to return before they are abandoned
+	killWait = 5 * time.Second
+	// saveTaskReport buffers the confirmed cancellation of the task, it is replaced in tests
+	saveTaskReport = util.SaveTaskReport
+)
+
+// Canceller is implemented by the executors which clean up after their cancelled tasks.
+//
+// When cloud cancels a task, the steps of the task running in background are asked to stop through
+// the context returned by startStep, the commands they run are interrupted and killed once
+// cancelGracePeriod passes. Cleanup is called after the steps returned, or were abandoned, to remove
+// what the task left on the node, e.g. its partial downloads. The node then reports the task
+// cancelled, a failed cleanup is reported in the reason. The steps handed over to keadm are not
+// cancelled, they run to completion.
+type Canceller interface {
+	// Cleanup removes the leftovers of the cancelled task, they are what its steps recorded with
+	// leaveBehind, e.g. the images they pulled
+	Cleanup(taskReq types.NodeTaskRequest, leftovers []string) error
+}
+
+// taskRun tracks the steps of a task running on the node and what they left on it
+type taskRun struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	steps  sync.WaitGroup
+
+	sync.Mutex
+	leftovers  []string
+	cancelling bool
+}
+
+// runs are the runs of the tasks on the node, keyed by taskKey
+var runs sync.Map
+
+// getRun returns the run of the task, it is created on first use
+func getRun(taskType, taskID string) *taskRun {
+	key := taskKey(taskType, taskID)
+	if run, ok := runs.Load(key); ok {
+		return run.(*taskRun)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	run, loaded := runs.LoadOrStore(key, &taskRun{ctx: ctx, cancel: cancel})
+	if loaded {
+		cancel()
+	}
+	return run.(*taskRun)
+}
+
+// startStep registers a step of the task running in background. It returns the context which is
+// done once the task is cancelled, and the function the step calls when it returns.
+func startStep(taskReq types.NodeTaskRequest) (context.Context, func()) {
+	run := getRun(taskReq.Type, taskReq.TaskID)
+	run.steps.Add(1)
+	return run.ctx, run.steps.Done
+}
+
+// leaveBehind records what the step of the task left on the node, it is cleaned up by the executor
+// if the task is cancelled
+func leaveBehind(taskReq types.NodeTaskRequest, leftover string) {
+	run := getRun(taskReq.Type, taskReq.TaskID)
+	run.Lock()
+	defer run.Unlock()
+	run.leftovers = append(run.leftovers, leftover)
+}
+
+// endRun drops the run of the task once its steps reported their result, nothing is left to clean up
+func endRun(taskType, taskID string) {
+	runs.Delete(taskKey(taskType, taskID))
+}
+
+// stepCommand returns the command run by a step of a task, it is interrupted once the task is
+// cancelled and killed if it does not exit within cancelGracePeriod. The command is recorded in
+// the audit record of the task.
+func stepCommand(ctx context.Context, taskReq types.NodeTaskRequest, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	auditCommand(taskReq, cmd.String())
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = cancelGracePeriod
+	return cmd
+}
+
+// cancelTask records the cancellation of the task. The steps of the task running in background are
+// stopped and cleaned up before the cancellation is confirmed, the task is confirmed cancelled right
+// away if it runs no step.
+func cancelTask(taskReq types.NodeTaskRequest) fsm.Event {
+	klog.Infof("task %s/%s is cancelled", taskReq.Type, taskReq.TaskID)
+	cancelledTasks.Store(taskKey(taskReq.Type, taskReq.TaskID), true)
+	event := fsm.Event{
+		Type:   api.EventCancel,
+		Action: api.ActionSuccess,
+	}
+	value, ok := runs.Load(taskKey(taskReq.Type, taskReq.TaskID))
+	if !ok {
+		return event
+	}
+	run := value.(*taskRun)
+	run.Lock()
+	cancelling := run.cancelling
+	run.cancelling = true
+	run.Unlock()
+	if !cancelling {
+		go stopRun(taskReq, run, event)
+	}
+	// the cancellation is confirmed once the steps stopped
+	return fsm.Event{}
+}
+
+// stopRun stops the steps of the cancelled task, cleans up what they left on the node and confirms
+// the cancellation to cloud
+func stopRun(taskReq types.NodeTaskRequest, run *taskRun, event fsm.Event) {
+	defer runs.CompareAndDelete(taskKey(taskReq.Type, taskReq.TaskID), run)
+	run.cancel()
+	var reasons []string
+	if !waitSteps(run, cancelGracePeriod+killWait) {
+		klog.Warningf("steps of task %s/%s did not stop within %s, abandon them", taskReq.Type, taskReq.TaskID, cancelGracePeriod+killWait)
+		reasons = append(reasons, fmt.Sprintf("the steps did not stop within %s and are abandoned", cancelGracePeriod+killWait))
+	}
+
+	run.Lock()
+	leftovers := run.leftovers
+	run.Unlock()
+	if executor, err := GetExecutor(taskReq.Type); err == nil && len(leftovers) != 0 {
+		if canceller, ok := executor.(Canceller); ok {
+			if err = canceller.Cleanup(taskReq, leftovers); err != nil {
+				klog.Warningf("failed to clean up cancelled task %s/%s: %v", taskReq.Type, taskReq.TaskID, err)
+				reasons = append(reasons, fmt.Sprintf("cleanup failed: %v", err))
+			}
+		}
+	}
+	if len(reasons) != 0 {
+		event.Msg = strings.Join(reasons, ", ")
+	}
+
+	edgeCoreConfig := options.GetEdgeCoreConfig()
+	resp := types.NodeTaskResponse{
+		NodeName:    edgeCoreConfig.Modules.Edged.HostnameOverride,
+		Event:       event.Type,
+		Action:      event.Action,
+		Reason:      event.Msg,
+		Environment: util.CollectEnvironment(edgeCoreConfig),
+		Metadata:    taskReq.Metadata,
+
+		CommandDigest: util.TaskAuditDigest(taskReq.Type, taskReq.TaskID),
+	}
+	if err := saveTaskReport(taskReq.Type, taskReq.TaskID, taskReq.State, resp); err != nil {
+		klog.Warningf("failed to save task report: %v", err)
+	}
+	edgeutil.ReportTaskResult(taskReq.Type, taskReq.TaskID, resp)
+}
+
+// waitSteps waits for the steps of the run to return, it returns false if they did not return in time
+func waitSteps(run *taskRun, timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		run.steps.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// removeImages removes the images a cancelled task pulled
+func removeImages(images []string) error {
+	config := options.GetEdgeCoreConfig()
+	container, err := util.NewContainerRuntime(config.Modules.Edged.TailoredKubeletConfig.ContainerRuntimeEndpoint, config.Modules.Edged.TailoredKubeletConfig.CgroupDriver)
+	if err != nil {
+		return fmt.Errorf("failed to new container runtime: %v", err)
+	}
+	var errs []error
+	for _, image := range images {
+		if err = container.RemoveImage(image); err != nil {
+			errs = append(errs, fmt.Errorf("remove image %s: %v", image, err))
+			continue
+		}
+		klog.Infof("removed image %s pulled by the cancelled task", image)
+	}
+	return errors.Join(errs...)
+}
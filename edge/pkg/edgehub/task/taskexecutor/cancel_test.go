@@ -0,0 +1,224 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskexecutor
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/cmd/edgecore/app/options"
+	edgeutil "github.com/kubeedge/kubeedge/edge/pkg/common/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+const cancelTestTask = "canceltest"
+
+// fakeCanceller records the leftovers it is asked to clean up
+type fakeCanceller struct {
+	cleaned    chan []string
+	cleanupErr error
+}
+
+func (f *fakeCanceller) Name() string {
+	return cancelTestTask
+}
+
+func (f *fakeCanceller) Do(types.NodeTaskRequest) (fsm.Event, error) {
+	return fsm.Event{}, nil
+}
+
+func (f *fakeCanceller) Cleanup(_ types.NodeTaskRequest, leftovers []string) error {
+	f.cleaned <- leftovers
+	return f.cleanupErr
+}
+
+// useCanceller registers the fake executor, loads the config of edgecore and captures the results
+// the node reports and buffers until the test ends
+func useCanceller(t *testing.T, cleanupErr error) (*fakeCanceller, chan types.NodeTaskResponse, chan string) {
+	if options.GetEdgeCoreConfig() == nil {
+		configFile := filepath.Join(t.TempDir(), "edgecore.yaml")
+		if err := os.WriteFile(configFile, []byte("modules:\n  edged:\n    hostnameOverride: edge-1\n"), 0600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		opts := options.NewEdgeCoreOptions()
+		opts.ConfigFile = configFile
+		if _, err := opts.Config(); err != nil {
+			t.Fatalf("failed to load config: %v", err)
+		}
+	}
+
+	canceller := &fakeCanceller{cleaned: make(chan []string, 1), cleanupErr: cleanupErr}
+	executors[cancelTestTask] = canceller
+	save, grace, wait := saveTaskReport, cancelGracePeriod, killWait
+	t.Cleanup(func() {
+		delete(executors, cancelTestTask)
+		saveTaskReport, cancelGracePeriod, killWait = save, grace, wait
+	})
+
+	reports := make(chan types.NodeTaskResponse, 2)
+	edgeutil.SetTaskSender(func(msg model.Message) {
+		data, err := msg.GetContentData()
+		if err != nil {
+			t.Errorf("failed to get content data: %v", err)
+			return
+		}
+		resp := types.NodeTaskResponse{}
+		if err = json.Unmarshal(data, &resp); err != nil {
+			t.Errorf("failed to unmarshal task result: %v", err)
+			return
+		}
+		reports <- resp
+	})
+	saved := make(chan string, 2)
+	saveTaskReport = func(_, _, state string, _ types.NodeTaskResponse) error {
+		saved <- state
+		return nil
+	}
+	return canceller, reports, saved
+}
+
+func cancelRequest(t *testing.T, taskID string) types.NodeTaskRequest {
+	t.Cleanup(func() {
+		cancelledTasks.Delete(taskKey(cancelTestTask, taskID))
+		endRun(cancelTestTask, taskID)
+	})
+	return types.NodeTaskRequest{Type: cancelTestTask, TaskID: taskID, State: string(api.TaskCancelling)}
+}
+
+func TestCancelBeforeDispatch(t *testing.T) {
+	_, reports, _ := useCanceller(t, nil)
+	taskReq := cancelRequest(t, "before-dispatch")
+
+	// nothing runs on the node, the cancellation is confirmed right away
+	event := cancelTask(taskReq)
+	if event.Type != api.EventCancel || event.Action != api.ActionSuccess {
+		t.Fatalf("expected the cancellation to be confirmed, got %+v", event)
+	}
+	// the steps dispatched after the cancellation see it and do not run
+	if !IsCancelled(cancelTestTask, "before-dispatch") {
+		t.Errorf("expected the task to be recorded cancelled")
+	}
+	select {
+	case resp := <-reports:
+		t.Errorf("expected the confirmation to be left to the task handler, got %+v", resp)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestCancelDuringExecution(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		taskID     string
+		cleanupErr error
+		// ignoreCancel makes the step keep running once the task is cancelled
+		ignoreCancel bool
+		reason       string
+	}{
+		{name: "steps stop", taskID: "during-execution"},
+		{name: "cleanup fails", taskID: "cleanup-fails", cleanupErr: errors.New("image is in use"), reason: "cleanup failed: image is in use"},
+		{name: "steps abandoned", taskID: "steps-abandoned", ignoreCancel: true, reason: "the steps did not stop within 20ms and are abandoned"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			canceller, reports, saved := useCanceller(t, tc.cleanupErr)
+			cancelGracePeriod, killWait = 10*time.Millisecond, 10*time.Millisecond
+			taskReq := cancelRequest(t, tc.taskID)
+
+			release := make(chan struct{})
+			defer close(release)
+			ctx, done := startStep(taskReq)
+			leaveBehind(taskReq, "image-a")
+			go func() {
+				defer done()
+				if tc.ignoreCancel {
+					<-release
+					return
+				}
+				<-ctx.Done()
+			}()
+
+			// the cancellation is confirmed once the steps stopped, a repeated cancellation is confirmed once
+			for i := 0; i < 2; i++ {
+				if event := cancelTask(taskReq); event.Action != "" {
+					t.Fatalf("expected the confirmation to wait for the steps, got %+v", event)
+				}
+			}
+
+			select {
+			case leftovers := <-canceller.cleaned:
+				if !reflect.DeepEqual(leftovers, []string{"image-a"}) {
+					t.Errorf("expected the leftovers of the step to be cleaned up, got %v", leftovers)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("expected the leftovers of the task to be cleaned up")
+			}
+			select {
+			case resp := <-reports:
+				if resp.NodeName != "edge-1" || resp.Event != api.EventCancel || resp.Action != api.ActionSuccess {
+					t.Errorf("expected the cancellation to be confirmed, got %+v", resp)
+				}
+				if !strings.Contains(resp.Reason, tc.reason) || (tc.reason == "") != (resp.Reason == "") {
+					t.Errorf("expected reason %q, got %q", tc.reason, resp.Reason)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("expected the cancellation to be reported")
+			}
+			if state := <-saved; state != string(api.TaskCancelling) {
+				t.Errorf("expected the confirmation to be buffered in state %s, got %s", api.TaskCancelling, state)
+			}
+			select {
+			case resp := <-reports:
+				t.Errorf("expected the cancellation to be confirmed once, got %+v", resp)
+			case <-time.After(50 * time.Millisecond):
+			}
+			if _, ok := runs.Load(taskKey(cancelTestTask, tc.taskID)); ok {
+				t.Errorf("expected the run of the cancelled task to be dropped")
+			}
+		})
+	}
+}
+
+func TestCancelAfterCompletion(t *testing.T) {
+	canceller, reports, _ := useCanceller(t, nil)
+	taskReq := cancelRequest(t, "after-completion")
+
+	// the step reported its result and ended the run
+	_, done := startStep(taskReq)
+	leaveBehind(taskReq, "image-a")
+	done()
+	endRun(cancelTestTask, "after-completion")
+
+	event := cancelTask(taskReq)
+	if event.Type != api.EventCancel || event.Action != api.ActionSuccess {
+		t.Fatalf("expected the cancellation to be confirmed, got %+v", event)
+	}
+	select {
+	case leftovers := <-canceller.cleaned:
+		t.Errorf("expected the result of the finished task to be kept, got %v cleaned up", leftovers)
+	case resp := <-reports:
+		t.Errorf("expected the confirmation to be left to the task handler, got %+v", resp)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskexecutor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/kubeedge/common/types"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/cmd/edgecore/app/options"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/cloudconnection"
+	edgeutil "github.com/kubeedge/kubeedge/edge/pkg/common/util"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2/validation"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+const (
+	TaskConfigUpdate = "configupdate"
+
+	// configBackupSuffix is appended to the config file to name the config edgecore ran with
+	// before the patch was applied
+	configBackupSuffix = ".bak"
+	// configUpdateSettleSeconds is how long edgecore has to be active again after it restarted with
+	// the patched config, the config it ran with is restored otherwise
+	configUpdateSettleSeconds = 30
+)
+
+var (
+	// configUpdateFile records the config update applied on the node until edgecore restarted and
+	// reported its result
+	configUpdateFile = filepath.Join(util.KubeEdgePath, "config-update.json")
+	// configRestoredFile is created if edgecore did not come back with the patched config and the
+	// config it ran with is restored
+	configRestoredFile = filepath.Join(util.KubeEdgePath, "config-update.restored")
+)
+
+type ConfigUpdate struct {
+	*BaseExecutor
+}
+
+func (c *ConfigUpdate) Name() string {
+	return c.name
+}
+
+func NewConfigUpdateExecutor() Executor {
+	methods := map[string]func(types.NodeTaskRequest) fsm.Event{
+		string(api.TaskInit):       initConfigUpdate,
+		"":                         initConfigUpdate,
+		string(api.ApplyingState):  applyConfig,
+		string(api.TaskCancelling): cancelTask,
+	}
+	return &ConfigUpdate{
+		BaseExecutor: NewBaseExecutor(TaskConfigUpdate, methods),
+	}
+}
+
+// pendingConfigUpdate is the config update waiting for edgecore to restart
+type pendingConfigUpdate struct {
+	TaskID   string            `json:"taskID"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// initConfigUpdate checks that the patch applies to the config of the node and the patched config is valid
+func initConfigUpdate(taskReq types.NodeTaskRequest) fsm.Event {
+	event := fsm.Event{
+		Type:   "Init",
+		Action: api.ActionSuccess,
+	}
+	if _, err := patchedConfig(taskReq); err != nil {
+		event.Action = api.ActionFailure
+		event.Msg = err.Error()
+	}
+	return event
+}
+
+// applyConfig backs up the config of the node, writes the patched config and restarts edgecore in
+// background. The result is reported by edgecore once it restarted, with ReportConfigUpdate.
+func applyConfig(taskReq types.NodeTaskRequest) fsm.Event {
+	event := fsm.Event{
+		Type:   api.EventApply,
+		Action: api.ActionFailure,
+	}
+	if pending, err := loadPendingConfigUpdate(); err == nil && pending.TaskID == taskReq.TaskID {
+		klog.Infof("config update of task %s is already applied, wait for edgecore to restart", taskReq.TaskID)
+		return fsm.Event{}
+	}
+	if _, err := exec.LookPath("systemctl"); err != nil {
+		event.Msg = "edgecore is not managed by systemd, it cannot be restarted with the patched config"
+		return event
+	}
+	data, err := patchedConfig(taskReq)
+	if err != nil {
+		event.Msg = err.Error()
+		return event
+	}
+
+	configFile := options.GetEdgeCoreOptions().ConfigFile
+	backupFile := configFile + configBackupSuffix
+	origin, err := os.ReadFile(configFile)
+	if err != nil {
+		event.Msg = fmt.Sprintf("failed to read config file %s: %v", configFile, err)
+		return event
+	}
+	if err = os.WriteFile(backupFile, origin, 0600); err != nil {
+		event.Msg = fmt.Sprintf("failed to back up config file to %s: %v", backupFile, err)
+		return event
+	}
+	record, err := json.Marshal(pendingConfigUpdate{TaskID: taskReq.TaskID, Metadata: taskReq.Metadata})
+	if err != nil {
+		event.Msg = err.Error()
+		return event
+	}
+	_ = os.Remove(configRestoredFile)
+	if err = os.WriteFile(configUpdateFile, record, 0600); err != nil {
+		event.Msg = fmt.Sprintf("failed to record the config update: %v", err)
+		return event
+	}
+	if err = os.WriteFile(configFile, data, 0600); err != nil {
+		_ = os.Remove(configUpdateFile)
+		event.Msg = fmt.Sprintf("failed to write config file %s: %v", configFile, err)
+		return event
+	}
+
+	// restart edgecore in a child process which outlives it, the backup is restored if edgecore
+	// is not active again in time
+	restartCmd := fmt.Sprintf("sleep 2; systemctl restart edgecore; sleep %d; "+
+		"if ! systemctl is-active --quiet edgecore; then cp -f %s %s && touch %s; systemctl restart edgecore; fi",
+		configUpdateSettleSeconds, backupFile, configFile, configRestoredFile)
+	command := fmt.Sprintf("nohup bash -c '%s' > /tmp/edgecore-config-update.log 2>&1 &", restartCmd)
+	if s, err := taskCommand(taskReq, command).CombinedOutput(); err != nil {
+		_ = os.WriteFile(configFile, origin, 0600)
+		_ = os.Remove(configUpdateFile)
+		event.Msg = fmt.Sprintf("failed to restart edgecore: %v, %s", err, s)
+		return event
+	}
+	klog.Infof("config patch of task %s is applied, restart edgecore", taskReq.TaskID)
+	return fsm.Event{}
+}
+
+// patchedConfig returns the config of the node with the patch of the task applied, it fails if the
+// patched config is invalid
+func patchedConfig(taskReq types.NodeTaskRequest) ([]byte, error) {
+	var updateReq commontypes.ConfigUpdateJobRequest
+	data, err := json.Marshal(taskReq.Item)
+	if err == nil {
+		err = json.Unmarshal(data, &updateReq)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(updateReq.Patch) == 0 {
+		return nil, fmt.Errorf("the config patch is empty")
+	}
+
+	configFile := options.GetEdgeCoreOptions().ConfigFile
+	origin, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %v", configFile, err)
+	}
+	originJSON, err := yaml.YAMLToJSON(origin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert config file %s: %v", configFile, err)
+	}
+	patchedJSON, err := jsonpatch.MergePatch(originJSON, updateReq.Patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply the config patch: %v", err)
+	}
+
+	config := v1alpha2.NewDefaultEdgeCoreConfig()
+	if err = yaml.UnmarshalStrict(patchedJSON, config); err != nil {
+		return nil, fmt.Errorf("the patched config is invalid: %v", err)
+	}
+	if errs := validation.ValidateEdgeCoreConfiguration(config); len(errs) > 0 {
+		return nil, fmt.Errorf("the patched config is invalid: %v", errs.ToAggregate())
+	}
+	return yaml.JSONToYAML(patchedJSON)
+}
+
+func loadPendingConfigUpdate() (*pendingConfigUpdate, error) {
+	data, err := os.ReadFile(configUpdateFile)
+	if err != nil {
+		return nil, err
+	}
+	pending := &pendingConfigUpdate{}
+	if err = json.Unmarshal(data, pending); err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// ReportConfigUpdate reports the config update applied before edgecore restarted once connected to
+// cloud. The update fails if edgecore came back with the config it ran with before.
+func ReportConfigUpdate(nodeName string) {
+	pending, err := loadPendingConfigUpdate()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			klog.Warningf("failed to load the pending config update: %v", err)
+		}
+		return
+	}
+	if !waitCloudConnected() {
+		return
+	}
+
+	resp := commontypes.NodeTaskResponse{
+		NodeName:    nodeName,
+		Event:       api.EventApply,
+		Action:      api.ActionSuccess,
+		Environment: util.CollectEnvironment(options.GetEdgeCoreConfig()),
+		Metadata:    pending.Metadata,
+
+		CommandDigest: util.TaskAuditDigest(TaskConfigUpdate, pending.TaskID),
+	}
+	if _, err = os.Stat(configRestoredFile); err == nil {
+		resp.Action = api.ActionFailure
+		resp.Reason = "edgecore did not restart with the patched config, the config it ran with is restored"
+	}
+	klog.Infof("report config update of task %s: %s", pending.TaskID, resp.Action)
+	if err = util.SaveTaskReport(TaskConfigUpdate, pending.TaskID, string(api.ApplyingState), resp); err != nil {
+		klog.Warningf("failed to save task report: %v", err)
+	}
+	edgeutil.ReportTaskResult(TaskConfigUpdate, pending.TaskID, resp)
+	if err = os.Remove(configUpdateFile); err != nil {
+		klog.Warningf("failed to remove %s: %v", configUpdateFile, err)
+	}
+	_ = os.Remove(configRestoredFile)
+}
+
+// waitCloudConnected waits for edgecore to connect to cloud, it returns false if edgecore stops meanwhile
+func waitCloudConnected() bool {
+	for !cloudconnection.IsConnected() {
+		select {
+		case <-beehiveContext.Done():
+			return false
+		case <-time.After(time.Second):
+		}
+	}
+	return true
+}
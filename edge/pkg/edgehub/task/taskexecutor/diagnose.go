@@ -0,0 +1,253 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/common/types"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/cmd/edgecore/app/options"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/cloudconnection"
+	edgeutil "github.com/kubeedge/kubeedge/edge/pkg/common/util"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+const (
+	TaskDiagnose = "diagnose"
+
+	// certExpiryWarning is how long before it expires the certificate of the node is reported
+	certExpiryWarning = 30 * 24 * time.Hour
+	// diagnoseTimeout bounds each check which reaches out of edgecore
+	diagnoseTimeout = 5 * time.Second
+)
+
+// diagnoseChecks are the checks of keadm debug diagnose run by a DiagnoseJob, in the order they run
+var diagnoseChecks = []v1alpha1.DiagnoseCheck{
+	v1alpha1.DiagnoseConnectivity,
+	v1alpha1.DiagnoseCertificate,
+	v1alpha1.DiagnoseRuntime,
+	v1alpha1.DiagnoseDatabase,
+}
+
+var diagnoseFuncs = map[v1alpha1.DiagnoseCheck]func(*v1alpha2.EdgeCoreConfig) (v1alpha1.DiagnoseResult, string){
+	v1alpha1.DiagnoseConnectivity: diagnoseConnectivity,
+	v1alpha1.DiagnoseCertificate:  diagnoseCertificate,
+	v1alpha1.DiagnoseRuntime:      diagnoseRuntime,
+	v1alpha1.DiagnoseDatabase:     diagnoseDatabase,
+}
+
+type Diagnose struct {
+	*BaseExecutor
+}
+
+func (d *Diagnose) Name() string {
+	return d.name
+}
+
+func NewDiagnoseExecutor() Executor {
+	methods := map[string]func(types.NodeTaskRequest) fsm.Event{
+		string(api.TaskInit):        emptyInit,
+		"":                          emptyInit,
+		string(api.DiagnosingState): diagnoseNode,
+		string(api.TaskCancelling):  cancelTask,
+	}
+	return &Diagnose{
+		BaseExecutor: NewBaseExecutor(TaskDiagnose, methods),
+	}
+}
+
+// diagnoseNode runs the checks of the task in background, the findings are reported as JSON in the
+// external message of the result. The node succeeds once the checks ran, whatever they found.
+func diagnoseNode(taskReq types.NodeTaskRequest) fsm.Event {
+	event := fsm.Event{
+		Type:   api.EventDiagnose,
+		Action: api.ActionSuccess,
+	}
+
+	var diagnoseReq commontypes.DiagnoseJobRequest
+	data, err := json.Marshal(taskReq.Item)
+	if err == nil {
+		err = json.Unmarshal(data, &diagnoseReq)
+	}
+	if err != nil {
+		event.Msg = err.Error()
+		event.Action = api.ActionFailure
+		return event
+	}
+
+	edgeCoreConfig := options.GetEdgeCoreConfig()
+	ctx, done := startStep(taskReq)
+	go func() {
+		defer done()
+		findings := runDiagnoseChecks(edgeCoreConfig, diagnoseReq.Checks)
+		if ctx.Err() != nil || IsCancelled(taskReq.Type, taskReq.TaskID) {
+			klog.Infof("task %s is cancelled, drop the findings", taskReq.TaskID)
+			return
+		}
+		resp := commontypes.NodeTaskResponse{
+			NodeName:    edgeCoreConfig.Modules.Edged.HostnameOverride,
+			Event:       event.Type,
+			Action:      event.Action,
+			Reason:      summarizeFindings(findings),
+			Environment: util.CollectEnvironment(edgeCoreConfig),
+			Metadata:    taskReq.Metadata,
+
+			CommandDigest: util.TaskAuditDigest(taskReq.Type, taskReq.TaskID),
+		}
+		data, err := json.Marshal(findings)
+		if err != nil {
+			resp.Action = api.ActionFailure
+			resp.Reason = fmt.Sprintf("failed to marshal the findings: %v", err)
+		} else {
+			resp.ExternalMessage = string(data)
+		}
+		edgeutil.ReportTaskResult(taskReq.Type, taskReq.TaskID, resp)
+		endRun(taskReq.Type, taskReq.TaskID)
+	}()
+	return fsm.Event{}
+}
+
+// runDiagnoseChecks runs the given checks, all of them if none is given
+func runDiagnoseChecks(config *v1alpha2.EdgeCoreConfig, checks []string) []v1alpha1.DiagnoseFinding {
+	selected := diagnoseChecks
+	if len(checks) != 0 {
+		selected = make([]v1alpha1.DiagnoseCheck, 0, len(checks))
+		for _, check := range checks {
+			selected = append(selected, v1alpha1.DiagnoseCheck(check))
+		}
+	}
+	findings := make([]v1alpha1.DiagnoseFinding, 0, len(selected))
+	for _, check := range selected {
+		finding := v1alpha1.DiagnoseFinding{Check: check}
+		if diagnose, ok := diagnoseFuncs[check]; ok {
+			finding.Result, finding.Message = diagnose(config)
+		} else {
+			finding.Result, finding.Message = v1alpha1.DiagnoseWarning, "the check is not supported by this edgecore"
+		}
+		klog.V(4).Infof("diagnose %s: %s, %s", check, finding.Result, finding.Message)
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// summarizeFindings counts the checks which did not pass
+func summarizeFindings(findings []v1alpha1.DiagnoseFinding) string {
+	var failed, warned int
+	for _, finding := range findings {
+		switch finding.Result {
+		case v1alpha1.DiagnoseFailed:
+			failed++
+		case v1alpha1.DiagnoseWarning:
+			warned++
+		}
+	}
+	return fmt.Sprintf("%d checks run, %d failed, %d warned", len(findings), failed, warned)
+}
+
+// diagnoseConnectivity checks edgecore is connected to cloud and the websocket server of cloudhub
+// can be reached
+func diagnoseConnectivity(config *v1alpha2.EdgeCoreConfig) (v1alpha1.DiagnoseResult, string) {
+	if !cloudconnection.IsConnected() {
+		return v1alpha1.DiagnoseFailed, "edgecore is not connected to cloud"
+	}
+	hub := config.Modules.EdgeHub
+	if hub.WebSocket == nil || !hub.WebSocket.Enable {
+		return v1alpha1.DiagnosePassed, "edgecore is connected to cloud over quic"
+	}
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", hub.WebSocket.Server, diagnoseTimeout)
+	if err != nil {
+		return v1alpha1.DiagnoseFailed, fmt.Sprintf("failed to connect to cloudhub server %s: %v", hub.WebSocket.Server, err)
+	}
+	conn.Close()
+	return v1alpha1.DiagnosePassed, fmt.Sprintf("cloudhub server %s is reached in %s", hub.WebSocket.Server,
+		time.Since(start).Round(time.Millisecond))
+}
+
+// diagnoseCertificate checks the certificate of the node is valid, it warns if the certificate
+// expires within certExpiryWarning
+func diagnoseCertificate(config *v1alpha2.EdgeCoreConfig) (v1alpha1.DiagnoseResult, string) {
+	cert, err := edgeCertificate(config.Modules.EdgeHub.TLSCertFile)
+	if err != nil {
+		return v1alpha1.DiagnoseFailed, err.Error()
+	}
+	now := time.Now()
+	switch {
+	case now.Before(cert.NotBefore):
+		return v1alpha1.DiagnoseFailed, fmt.Sprintf("certificate is not valid before %s", cert.NotBefore.UTC().Format(time.RFC3339))
+	case now.After(cert.NotAfter):
+		return v1alpha1.DiagnoseFailed, fmt.Sprintf("certificate expired at %s", cert.NotAfter.UTC().Format(time.RFC3339))
+	case cert.NotAfter.Sub(now) < certExpiryWarning:
+		return v1alpha1.DiagnoseWarning, fmt.Sprintf("certificate expires at %s", cert.NotAfter.UTC().Format(time.RFC3339))
+	}
+	return v1alpha1.DiagnosePassed, fmt.Sprintf("certificate is valid until %s", cert.NotAfter.UTC().Format(time.RFC3339))
+}
+
+// diagnoseRuntime checks the container runtime answers and reports its conditions ready
+func diagnoseRuntime(config *v1alpha2.EdgeCoreConfig) (v1alpha1.DiagnoseResult, string) {
+	kubelet := config.Modules.Edged.TailoredKubeletConfig
+	runtime, err := util.NewContainerRuntime(kubelet.ContainerRuntimeEndpoint, kubelet.CgroupDriver)
+	if err != nil {
+		return v1alpha1.DiagnoseFailed, fmt.Sprintf("failed to connect to the container runtime: %v", err)
+	}
+	cri, ok := runtime.(*util.CRIRuntime)
+	if !ok {
+		return v1alpha1.DiagnosePassed, fmt.Sprintf("container runtime %s is connected", kubelet.ContainerRuntimeEndpoint)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), diagnoseTimeout)
+	defer cancel()
+	version, err := cri.RuntimeService.Version(ctx, "")
+	if err != nil {
+		return v1alpha1.DiagnoseFailed, fmt.Sprintf("failed to get the version of the container runtime: %v", err)
+	}
+	status, err := cri.RuntimeService.Status(ctx, false)
+	if err != nil {
+		return v1alpha1.DiagnoseFailed, fmt.Sprintf("failed to get the status of the container runtime: %v", err)
+	}
+	for _, condition := range status.GetStatus().GetConditions() {
+		if !condition.Status {
+			return v1alpha1.DiagnoseFailed, fmt.Sprintf("%s %s is not %s: %s", version.RuntimeName, version.RuntimeVersion,
+				condition.Type, condition.Message)
+		}
+	}
+	return v1alpha1.DiagnosePassed, fmt.Sprintf("%s %s is ready", version.RuntimeName, version.RuntimeVersion)
+}
+
+// diagnoseDatabase checks the database of edgecore exists
+func diagnoseDatabase(config *v1alpha2.EdgeCoreConfig) (v1alpha1.DiagnoseResult, string) {
+	dataSource := v1alpha2.DataBaseDataSource
+	if config.DataBase != nil && config.DataBase.DataSource != "" {
+		dataSource = config.DataBase.DataSource
+	}
+	info, err := os.Stat(dataSource)
+	if err != nil {
+		return v1alpha1.DiagnoseFailed, fmt.Sprintf("failed to stat database %s: %v", dataSource, err)
+	}
+	return v1alpha1.DiagnosePassed, fmt.Sprintf("database %s is %d KiB", dataSource, info.Size()>>10)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskexecutor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/shirou/gopsutil/disk"
+
+	"github.com/kubeedge/kubeedge/edge/cmd/edgecore/app/options"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+)
+
+// MinUpgradeFreeDisk is the free disk space the upgrade needs for the installation package and the backup
+const MinUpgradeFreeDisk uint64 = 512 << 20
+
+// feasibilityChecks validate that the node could run a task of their type without operating on it,
+// they run along with the check items of a dry run. A check returns what it found if it passes.
+var feasibilityChecks = map[string]map[string]func() (string, error){
+	TaskUpgrade: {
+		"runtime":        checkUpgradeRuntime,
+		"permission":     checkUpgradePermission,
+		"upgradeDisk":    checkUpgradeDisk,
+		"serviceManager": checkServiceManager,
+	},
+}
+
+// checkUpgradeRuntime checks the container runtime keadm is pulled with can be reached
+func checkUpgradeRuntime() (string, error) {
+	config := options.GetEdgeCoreConfig().Modules.Edged.TailoredKubeletConfig
+	runtime, err := util.NewContainerRuntime(config.ContainerRuntimeEndpoint, config.CgroupDriver)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to the container runtime: %v", err)
+	}
+	cri, ok := runtime.(*util.CRIRuntime)
+	if !ok {
+		return config.ContainerRuntimeEndpoint, nil
+	}
+	version, err := cri.RuntimeService.Version(context.TODO(), "")
+	if err != nil {
+		return "", fmt.Errorf("failed to get the version of the container runtime: %v", err)
+	}
+	return fmt.Sprintf("%s %s", version.RuntimeName, version.RuntimeVersion), nil
+}
+
+// checkUpgradePermission checks edgecore runs as root, which keadm needs to replace edgecore
+func checkUpgradePermission() (string, error) {
+	uid := os.Geteuid()
+	if uid > 0 {
+		return "", fmt.Errorf("edgecore runs as uid %d, the upgrade needs root", uid)
+	}
+	return "root", nil
+}
+
+// checkUpgradeDisk checks the disk the upgrade is downloaded to has MinUpgradeFreeDisk free
+func checkUpgradeDisk() (string, error) {
+	dir := filepath.Clean(util.KubeEdgeUpgradePath)
+	// the upgrade directory is created by the first upgrade
+	for {
+		if _, err := os.Stat(dir); err == nil || !errors.Is(err, os.ErrNotExist) || filepath.Dir(dir) == dir {
+			break
+		}
+		dir = filepath.Dir(dir)
+	}
+	usage, err := disk.Usage(dir)
+	if err != nil {
+		return "", err
+	}
+	if usage.Free < MinUpgradeFreeDisk {
+		return "", fmt.Errorf("%d MiB free on %s, the upgrade needs %d MiB", usage.Free>>20, dir, MinUpgradeFreeDisk>>20)
+	}
+	return fmt.Sprintf("%d MiB free on %s", usage.Free>>20, dir), nil
+}
+
+// checkServiceManager reports how edgecore is restarted by the upgrade, it never fails as
+// keadm starts edgecore as a process if systemd is not found
+func checkServiceManager() (string, error) {
+	if util.HasSystemd() {
+		return "systemd", nil
+	}
+	return "none, edgecore is restarted as a process", nil
+}
@@ -51,10 +51,11 @@ func (p *PrePull) Name() string {
 
 func NewPrePullExecutor() Executor {
 	methods := map[string]func(types.NodeTaskRequest) fsm.Event{
-		string(api.TaskChecking): preCheck,
-		string(api.TaskInit):     emptyInit,
-		"":                       emptyInit,
-		string(api.PullingState): pullImages,
+		string(api.TaskChecking):   preCheck,
+		string(api.TaskInit):       emptyInit,
+		"":                         emptyInit,
+		string(api.PullingState):   pullImages,
+		string(api.TaskCancelling): cancelTask,
 	}
 	return &PrePull{
 		BaseExecutor: NewBaseExecutor(TaskPrePull, methods),
@@ -86,8 +87,19 @@ func pullImages(taskReq types.NodeTaskRequest) fsm.Event {
 		return event
 	}
 
+	ctx, done := startStep(taskReq)
 	go func() {
-		errorStr, imageStatus := prePullImages(*prePullReq, container)
+		defer done()
+		cancelled := func() bool {
+			return ctx.Err() != nil || IsCancelled(taskReq.Type, taskReq.TaskID)
+		}
+		errorStr, imageStatus, bytesDownloaded := prePullImages(*prePullReq, container, cancelled, func(image string) {
+			leaveBehind(taskReq, image)
+		})
+		if cancelled() {
+			klog.Infof("task %s is cancelled, pulled images %d/%d", taskReq.TaskID, len(imageStatus), len(prePullReq.Images))
+			return
+		}
 		if errorStr != "" {
 			event.Action = api.ActionFailure
 			event.Msg = errorStr
@@ -103,12 +115,22 @@ func pullImages(taskReq types.NodeTaskRequest) fsm.Event {
 			Action:          event.Action,
 			Reason:          event.Msg,
 			ExternalMessage: string(data),
+			Environment:     util.CollectEnvironment(edgeCoreConfig),
+			BytesDownloaded: bytesDownloaded,
+			Metadata:        taskReq.Metadata,
+			CommandDigest:   util.TaskAuditDigest(taskReq.Type, taskReq.TaskID),
 		}
 		edgeutil.ReportTaskResult(taskReq.Type, taskReq.TaskID, resp)
+		endRun(taskReq.Type, taskReq.TaskID)
 	}()
 	return fsm.Event{}
 }
 
+// Cleanup removes the images the cancelled task pulled which were not on the node before
+func (p *PrePull) Cleanup(_ types.NodeTaskRequest, leftovers []string) error {
+	return removeImages(leftovers)
+}
+
 func getImagePrePullJobRequest(taskReq commontypes.NodeTaskRequest) (*commontypes.ImagePrePullJobRequest, error) {
 	var prePullReq commontypes.ImagePrePullJobRequest
 	data, err := json.Marshal(taskReq.Item)
@@ -122,21 +144,34 @@ func getImagePrePullJobRequest(taskReq commontypes.NodeTaskRequest) (*commontype
 	return &prePullReq, err
 }
 
-func prePullImages(prePullReq commontypes.ImagePrePullJobRequest, container util.ContainerRuntime) (string, []v1alpha1.ImageStatus) {
+// prePullImages pulls the images of the request, it returns the error message, the status of
+// each image and the size of the images which were pulled. pulled is called with each image
+// which was not on the node before.
+func prePullImages(prePullReq commontypes.ImagePrePullJobRequest, container util.ContainerRuntime, cancelled func() bool, pulled func(string)) (string, []v1alpha1.ImageStatus, int64) {
 	errorStr := ""
 	authConfig, err := makeAuthConfig(prePullReq.Secret)
 	if err != nil {
-		return errorStr, []v1alpha1.ImageStatus{}
+		return errorStr, []v1alpha1.ImageStatus{}, 0
 	}
 
+	var bytesDownloaded int64
+
 	var imageStatus []v1alpha1.ImageStatus
 	for _, image := range prePullReq.Images {
+		if cancelled() {
+			break
+		}
 		prePullStatus := v1alpha1.ImageStatus{
 			Image: image,
 		}
 		for i := 0; i <= int(prePullReq.RetryTimes); i++ {
-			err = container.PullImage(image, authConfig, nil)
+			var size uint64
+			size, err = container.PullImageWithSize(image, authConfig, nil)
 			if err == nil {
+				bytesDownloaded += int64(size)
+				if size != 0 {
+					pulled(image)
+				}
 				break
 			}
 		}
@@ -152,7 +187,7 @@ func prePullImages(prePullReq commontypes.ImagePrePullJobRequest, container util
 		imageStatus = append(imageStatus, prePullStatus)
 	}
 
-	return errorStr, imageStatus
+	return errorStr, imageStatus, bytesDownloaded
 }
 
 func makeAuthConfig(pullsecret string) (*runtimeapi.AuthConfig, error) {
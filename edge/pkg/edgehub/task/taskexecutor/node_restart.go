@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskexecutor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/common/types"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/cmd/edgecore/app/options"
+	edgeutil "github.com/kubeedge/kubeedge/edge/pkg/common/util"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+const (
+	TaskRestart = "restart"
+
+	// bootIDFile changes on every boot of the host
+	bootIDFile = "/proc/sys/kernel/random/boot_id"
+)
+
+// nodeRestartFile records the restart requested on the node until edgecore is back and reported its result
+var nodeRestartFile = filepath.Join(util.KubeEdgePath, "node-restart.json")
+
+type NodeRestart struct {
+	*BaseExecutor
+}
+
+func (r *NodeRestart) Name() string {
+	return r.name
+}
+
+func NewRestartExecutor() Executor {
+	methods := map[string]func(types.NodeTaskRequest) fsm.Event{
+		string(api.TaskInit):        normalInit,
+		"":                          normalInit,
+		string(api.RestartingState): restartNode,
+		string(api.TaskCancelling):  cancelTask,
+	}
+	return &NodeRestart{
+		BaseExecutor: NewBaseExecutor(TaskRestart, methods),
+	}
+}
+
+// pendingNodeRestart is the restart waiting for edgecore to be back
+type pendingNodeRestart struct {
+	TaskID   string            `json:"taskID"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Reboot   bool              `json:"reboot,omitempty"`
+	// BootID is the boot ID of the host before it rebooted
+	BootID string `json:"bootID,omitempty"`
+}
+
+// restartNode records the restart and restarts edgecore, or reboots the host, in background. The result
+// is reported by edgecore once it is back, with ReportNodeRestart.
+func restartNode(taskReq types.NodeTaskRequest) fsm.Event {
+	event := fsm.Event{
+		Type:   api.EventRestart,
+		Action: api.ActionFailure,
+	}
+	if pending, err := loadPendingNodeRestart(); err == nil && pending.TaskID == taskReq.TaskID {
+		klog.Infof("restart of task %s is already requested, wait for edgecore to be back", taskReq.TaskID)
+		return fsm.Event{}
+	}
+	if _, err := exec.LookPath("systemctl"); err != nil {
+		event.Msg = "edgecore is not managed by systemd, it cannot be restarted"
+		return event
+	}
+	var restartReq commontypes.NodeRestartJobRequest
+	data, err := json.Marshal(taskReq.Item)
+	if err == nil {
+		err = json.Unmarshal(data, &restartReq)
+	}
+	if err != nil {
+		event.Msg = err.Error()
+		return event
+	}
+
+	pending := pendingNodeRestart{TaskID: taskReq.TaskID, Metadata: taskReq.Metadata, Reboot: restartReq.Reboot}
+	restartCmd := "systemctl restart edgecore"
+	if restartReq.Reboot {
+		if pending.BootID, err = bootID(); err != nil {
+			event.Msg = err.Error()
+			return event
+		}
+		restartCmd = "systemctl reboot"
+	}
+	record, err := json.Marshal(pending)
+	if err != nil {
+		event.Msg = err.Error()
+		return event
+	}
+	if err = os.WriteFile(nodeRestartFile, record, 0600); err != nil {
+		event.Msg = fmt.Sprintf("failed to record the restart: %v", err)
+		return event
+	}
+
+	// restart in a child process which outlives edgecore, it gives edgecore the time to save the task
+	command := fmt.Sprintf("nohup bash -c 'sleep 2; %s' > /tmp/edgecore-restart.log 2>&1 &", restartCmd)
+	if s, err := taskCommand(taskReq, command).CombinedOutput(); err != nil {
+		_ = os.Remove(nodeRestartFile)
+		event.Msg = fmt.Sprintf("failed to restart: %v, %s", err, s)
+		return event
+	}
+	klog.Infof("restart node for task %s, reboot: %t", taskReq.TaskID, restartReq.Reboot)
+	return fsm.Event{}
+}
+
+func bootID() (string, error) {
+	data, err := os.ReadFile(bootIDFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the boot ID of the host: %v", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func loadPendingNodeRestart() (*pendingNodeRestart, error) {
+	data, err := os.ReadFile(nodeRestartFile)
+	if err != nil {
+		return nil, err
+	}
+	pending := &pendingNodeRestart{}
+	if err = json.Unmarshal(data, pending); err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// ReportNodeRestart reports the restart requested before edgecore stopped once connected to cloud.
+// The restart fails if the host was to reboot but its boot ID did not change.
+func ReportNodeRestart(nodeName string) {
+	pending, err := loadPendingNodeRestart()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			klog.Warningf("failed to load the pending restart: %v", err)
+		}
+		return
+	}
+	if !waitCloudConnected() {
+		return
+	}
+
+	resp := commontypes.NodeTaskResponse{
+		NodeName:    nodeName,
+		Event:       api.EventRestart,
+		Action:      api.ActionSuccess,
+		Environment: util.CollectEnvironment(options.GetEdgeCoreConfig()),
+		Metadata:    pending.Metadata,
+
+		CommandDigest: util.TaskAuditDigest(TaskRestart, pending.TaskID),
+	}
+	if pending.Reboot {
+		if id, err := bootID(); err != nil || id == pending.BootID {
+			resp.Action = api.ActionFailure
+			resp.Reason = "edgecore restarted but the host did not reboot"
+		}
+	}
+	klog.Infof("report restart of task %s: %s", pending.TaskID, resp.Action)
+	if err = util.SaveTaskReport(TaskRestart, pending.TaskID, string(api.RestartingState), resp); err != nil {
+		klog.Warningf("failed to save task report: %v", err)
+	}
+	edgeutil.ReportTaskResult(TaskRestart, pending.TaskID, resp)
+	if err = os.Remove(nodeRestartFile); err != nil {
+		klog.Warningf("failed to remove %s: %v", nodeRestartFile, err)
+	}
+}
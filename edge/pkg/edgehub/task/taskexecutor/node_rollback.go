@@ -18,7 +18,6 @@ package taskexecutor
 
 import (
 	"fmt"
-	"os/exec"
 
 	"k8s.io/klog/v2"
 
@@ -47,14 +46,14 @@ func rollbackNode(taskReq commontypes.NodeTaskRequest) (event fsm.Event) {
 		return
 	}
 
-	err = rollback(upgradeReq)
+	err = rollback(upgradeReq, taskReq)
 	if err != nil {
 		return
 	}
 	return event
 }
 
-func rollback(upgradeReq *commontypes.NodeUpgradeJobRequest) error {
+func rollback(upgradeReq *commontypes.NodeUpgradeJobRequest, taskReq commontypes.NodeTaskRequest) error {
 	klog.Infof("Begin to run rollback command")
 	rollBackCmd := fmt.Sprintf("keadm rollback edge --name %s --history %s >> /tmp/keadm.log 2>&1",
 		upgradeReq.UpgradeID, version.Get())
@@ -62,7 +61,7 @@ func rollback(upgradeReq *commontypes.NodeUpgradeJobRequest) error {
 	// run upgrade cmd to upgrade edge node
 	// use nohup command to start a child progress
 	command := fmt.Sprintf("nohup %s &", rollBackCmd)
-	cmd := exec.Command("bash", "-c", command)
+	cmd := taskCommand(taskReq, command)
 	s, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("run rollback command %s failed: %v, %s", command, err, s)
@@ -19,7 +19,7 @@ package taskexecutor
 import (
 	"encoding/json"
 	"fmt"
-	"os/exec"
+	"os"
 	"path/filepath"
 
 	"k8s.io/klog/v2"
@@ -52,7 +52,9 @@ func NewUpgradeExecutor() Executor {
 		"":                           initUpgrade,
 		string(api.BackingUpState):   backupNode,
 		string(api.RollingBackState): rollbackNode,
+		string(api.RevertingState):   rollbackNode,
 		string(api.UpgradingState):   upgrade,
+		string(api.TaskCancelling):   cancelTask,
 	}
 	return &Upgrade{
 		BaseExecutor: NewBaseExecutor(TaskUpgrade, methods),
@@ -88,13 +90,21 @@ func initUpgrade(taskReq types.NodeTaskRequest) (event fsm.Event) {
 			Action: api.ActionSuccess,
 		}
 	}
-	err = prepareKeadm(upgradeReq)
+	event.BytesDownloaded, err = prepareKeadm(upgradeReq)
+	if event.BytesDownloaded != 0 {
+		leaveBehind(taskReq, upgradeReq.Image)
+	}
 	if err != nil {
 		return
 	}
 	return event
 }
 
+// Cleanup removes the image of keadm pulled by the cancelled task, keadm copied from it is kept
+func (u *Upgrade) Cleanup(_ types.NodeTaskRequest, leftovers []string) error {
+	return removeImages(leftovers)
+}
+
 func getTaskRequest(taskReq commontypes.NodeTaskRequest) (*commontypes.NodeUpgradeJobRequest, error) {
 	data, err := json.Marshal(taskReq.Item)
 	if err != nil {
@@ -119,7 +129,7 @@ func upgrade(taskReq types.NodeTaskRequest) (event fsm.Event) {
 		event.Msg = err.Error()
 		return
 	}
-	err = keadmUpgrade(*upgradeReq, opts)
+	err = keadmUpgrade(*upgradeReq, taskReq, opts)
 	if err != nil {
 		event.Action = api.ActionFailure
 		event.Msg = err.Error()
@@ -127,15 +137,23 @@ func upgrade(taskReq types.NodeTaskRequest) (event fsm.Event) {
 	return
 }
 
-func keadmUpgrade(upgradeReq commontypes.NodeUpgradeJobRequest, opts *options.EdgeCoreOptions) error {
+func keadmUpgrade(upgradeReq commontypes.NodeUpgradeJobRequest, taskReq types.NodeTaskRequest, opts *options.EdgeCoreOptions) error {
 	klog.Infof("Begin to run upgrade command")
-	upgradeCmd := fmt.Sprintf("keadm upgrade edge --upgradeID %s --historyID %s --fromVersion %s --toVersion %s --config %s --image %s > /tmp/keadm.log 2>&1",
+	upgradeCmd := fmt.Sprintf("keadm upgrade edge --upgradeID %s --historyID %s --fromVersion %s --toVersion %s --config %s --image %s",
 		upgradeReq.UpgradeID, upgradeReq.HistoryID, version.Get(), upgradeReq.Version, opts.ConfigFile, upgradeReq.Image)
+	if len(upgradeReq.VerificationProbes) != 0 {
+		verificationFile, err := saveVerificationProbes(upgradeReq)
+		if err != nil {
+			return err
+		}
+		upgradeCmd = fmt.Sprintf("%s --verificationFile %s", upgradeCmd, verificationFile)
+	}
+	upgradeCmd += " > /tmp/keadm.log 2>&1"
 
 	// run upgrade cmd to upgrade edge node
 	// use nohup command to start a child progress
 	command := fmt.Sprintf("nohup %s &", upgradeCmd)
-	cmd := exec.Command("bash", "-c", command)
+	cmd := taskCommand(taskReq, command)
 	s, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("run upgrade command %s failed: %v, %s", command, err, s)
@@ -144,29 +162,47 @@ func keadmUpgrade(upgradeReq commontypes.NodeUpgradeJobRequest, opts *options.Ed
 	return nil
 }
 
-func prepareKeadm(upgradeReq *commontypes.NodeUpgradeJobRequest) error {
+// saveVerificationProbes writes the verification probes to a file that is read by keadm after upgrade
+func saveVerificationProbes(upgradeReq commontypes.NodeUpgradeJobRequest) (string, error) {
+	data, err := json.Marshal(upgradeReq.VerificationProbes)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal verification probes: %v", err)
+	}
+	dir := filepath.Join(util.KubeEdgeUpgradePath, upgradeReq.Version)
+	if err = os.MkdirAll(dir, 0750); err != nil {
+		return "", fmt.Errorf("failed to create dir %s: %v", dir, err)
+	}
+	verificationFile := filepath.Join(dir, "verification.json")
+	if err = os.WriteFile(verificationFile, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write verification probes: %v", err)
+	}
+	return verificationFile, nil
+}
+
+// prepareKeadm installs the requested keadm, it returns the size of the image pulled for it
+func prepareKeadm(upgradeReq *commontypes.NodeUpgradeJobRequest) (int64, error) {
 	config := options.GetEdgeCoreConfig()
 
 	// install the requested installer keadm from docker image
 	klog.Infof("Begin to download version %s keadm", upgradeReq.Version)
 	container, err := util.NewContainerRuntime(config.Modules.Edged.TailoredKubeletConfig.ContainerRuntimeEndpoint, config.Modules.Edged.TailoredKubeletConfig.CgroupDriver)
 	if err != nil {
-		return fmt.Errorf("failed to new container runtime: %v", err)
+		return 0, fmt.Errorf("failed to new container runtime: %v", err)
 	}
 	image := upgradeReq.Image
 
 	// TODO: do some verification 1.sha256(pass in using CRD) 2.image signature verification
 	// TODO: release verification mechanism
-	err = container.PullImages([]string{image})
+	size, err := container.PullImageWithSize(image, nil, nil)
 	if err != nil {
-		return fmt.Errorf("pull image failed: %v", err)
+		return 0, fmt.Errorf("pull image failed: %v", err)
 	}
 	files := map[string]string{
 		filepath.Join(util.KubeEdgeUsrBinPath, util.KeadmBinaryName): filepath.Join(util.KubeEdgeUsrBinPath, util.KeadmBinaryName),
 	}
 	err = container.CopyResources(image, files)
 	if err != nil {
-		return fmt.Errorf("failed to cp file from image to host: %v", err)
+		return int64(size), fmt.Errorf("failed to cp file from image to host: %v", err)
 	}
-	return nil
+	return int64(size), nil
 }
@@ -0,0 +1,432 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskexecutor
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/common/types"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/cmd/edgecore/app/options"
+	edgeutil "github.com/kubeedge/kubeedge/edge/pkg/common/util"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+const (
+	TaskOSUpgrade = "osupgrade"
+
+	// osUpgradeRebootExitCode is the exit code of the hooks which need the host to be rebooted
+	// to complete the update
+	osUpgradeRebootExitCode = 100
+	// osUpgradeVersionEnv passes the version of the artifact to the hook
+	osUpgradeVersionEnv = "KUBEEDGE_OS_UPGRADE_VERSION"
+)
+
+var (
+	// osUpgradeHookPath is the directory of the hooks installed on the node and their signatures
+	osUpgradeHookPath = filepath.Join(util.KubeEdgePath, "hooks")
+	// osUpgradeTrustedKeysFile holds the PEM encoded ed25519 public keys the hooks are signed with
+	osUpgradeTrustedKeysFile = filepath.Join(osUpgradeHookPath, "trusted-keys.pem")
+	// osUpgradeArtifactPath is the directory the artifacts are downloaded to, one directory per task
+	osUpgradeArtifactPath = filepath.Join(util.KubeEdgeUpgradePath, "os")
+	// osUpgradeFile records the upgrade running on the node until its result is reported, so that
+	// edgecore reports it once it is back if the host rebooted
+	osUpgradeFile = filepath.Join(util.KubeEdgePath, "os-upgrade.json")
+)
+
+type OSUpgrade struct {
+	*BaseExecutor
+}
+
+func (u *OSUpgrade) Name() string {
+	return u.name
+}
+
+func NewOSUpgradeExecutor() Executor {
+	methods := map[string]func(types.NodeTaskRequest) fsm.Event{
+		string(api.TaskInit):         initOSUpgrade,
+		"":                           initOSUpgrade,
+		string(api.OSUpgradingState): upgradeOS,
+		string(api.TaskCancelling):   cancelTask,
+	}
+	return &OSUpgrade{
+		BaseExecutor: NewBaseExecutor(TaskOSUpgrade, methods),
+	}
+}
+
+// Cleanup removes the artifacts the cancelled task downloaded
+func (u *OSUpgrade) Cleanup(_ types.NodeTaskRequest, leftovers []string) error {
+	var errs []string
+	for _, dir := range leftovers {
+		if err := os.RemoveAll(dir); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) != 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// pendingOSUpgrade is the upgrade whose result is not reported yet
+type pendingOSUpgrade struct {
+	TaskID   string            `json:"taskID"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// BootID is the boot ID of the host before the hook ran
+	BootID string `json:"bootID"`
+	// Reboot is set once the hook asked for the host to be rebooted
+	Reboot bool `json:"reboot,omitempty"`
+}
+
+// initOSUpgrade checks the node has the hook of the task and its signature is verified
+func initOSUpgrade(taskReq types.NodeTaskRequest) fsm.Event {
+	event := fsm.Event{
+		Type:   "Init",
+		Action: api.ActionSuccess,
+	}
+	upgradeReq, err := getOSUpgradeJobRequest(taskReq)
+	if err == nil {
+		_, err = verifyOSUpgradeHook(upgradeReq.Hook, upgradeReq.HookSHA256)
+	}
+	if err != nil {
+		event.Action = api.ActionFailure
+		event.Msg = err.Error()
+	}
+	return event
+}
+
+// upgradeOS downloads the artifact and runs the hook on it in background. The hook applies the update,
+// the host is rebooted if the hook asks for it and the result is then reported by edgecore once it is
+// back, with ReportOSUpgrade.
+func upgradeOS(taskReq types.NodeTaskRequest) fsm.Event {
+	event := fsm.Event{
+		Type:   api.EventOSUpgrade,
+		Action: api.ActionFailure,
+	}
+	if pending, err := loadPendingOSUpgrade(); err == nil && pending.TaskID == taskReq.TaskID {
+		klog.Infof("upgrade of task %s is already running, wait for its result", taskReq.TaskID)
+		return fsm.Event{}
+	}
+	upgradeReq, err := getOSUpgradeJobRequest(taskReq)
+	if err != nil {
+		event.Msg = err.Error()
+		return event
+	}
+	hook, err := verifyOSUpgradeHook(upgradeReq.Hook, upgradeReq.HookSHA256)
+	if err != nil {
+		event.Msg = err.Error()
+		return event
+	}
+	pending := pendingOSUpgrade{TaskID: taskReq.TaskID, Metadata: taskReq.Metadata}
+	if pending.BootID, err = bootID(); err != nil {
+		event.Msg = err.Error()
+		return event
+	}
+	if err = savePendingOSUpgrade(pending); err != nil {
+		event.Msg = err.Error()
+		return event
+	}
+
+	edgeCoreConfig := options.GetEdgeCoreConfig()
+	dir := filepath.Join(osUpgradeArtifactPath, taskReq.TaskID)
+	ctx, done := startStep(taskReq)
+	leaveBehind(taskReq, dir)
+	go func() {
+		defer done()
+		resp := commontypes.NodeTaskResponse{
+			NodeName:    edgeCoreConfig.Modules.Edged.HostnameOverride,
+			Event:       event.Type,
+			Action:      api.ActionSuccess,
+			Environment: util.CollectEnvironment(edgeCoreConfig),
+			Metadata:    taskReq.Metadata,
+
+			CommandDigest: util.TaskAuditDigest(taskReq.Type, taskReq.TaskID),
+		}
+		reboot, err := applyOSUpgrade(ctx, taskReq, *upgradeReq, hook, dir)
+		if ctx.Err() != nil || IsCancelled(taskReq.Type, taskReq.TaskID) {
+			klog.Infof("task %s is cancelled, the hook %s is interrupted", taskReq.TaskID, upgradeReq.Hook)
+			_ = os.Remove(osUpgradeFile)
+			return
+		}
+		_ = os.RemoveAll(dir)
+		switch {
+		case err != nil:
+			resp.Action = api.ActionFailure
+			resp.Reason = err.Error()
+		case reboot:
+			rebootHost(taskReq, pending, resp)
+			endRun(taskReq.Type, taskReq.TaskID)
+			return
+		default:
+			resp.Reason = fmt.Sprintf("the hook %s applied the artifact", upgradeReq.Hook)
+		}
+		_ = os.Remove(osUpgradeFile)
+		edgeutil.ReportTaskResult(taskReq.Type, taskReq.TaskID, resp)
+		endRun(taskReq.Type, taskReq.TaskID)
+	}()
+	return fsm.Event{}
+}
+
+// applyOSUpgrade downloads the artifact to dir and runs the hook on it, it returns whether the hook
+// asked for the host to be rebooted
+func applyOSUpgrade(ctx context.Context, taskReq types.NodeTaskRequest, upgradeReq commontypes.OSUpgradeJobRequest,
+	hook, dir string) (bool, error) {
+	artifact, err := downloadOSUpgradeArtifact(ctx, upgradeReq, dir)
+	if err != nil {
+		return false, err
+	}
+	cmd := stepCommand(ctx, taskReq, hook, artifact)
+	cmd.Env = append(taskEnv(taskReq), osUpgradeVersionEnv+"="+upgradeReq.Version)
+	out, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == osUpgradeRebootExitCode {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("the hook %s failed: %v, %s", upgradeReq.Hook, err, strings.TrimSpace(string(out)))
+	}
+	return false, nil
+}
+
+// rebootHost reports that the host reboots and reboots it in background, the upgrade is reported by
+// edgecore once the host is back
+func rebootHost(taskReq types.NodeTaskRequest, pending pendingOSUpgrade, resp commontypes.NodeTaskResponse) {
+	fail := func(reason string) {
+		_ = os.Remove(osUpgradeFile)
+		resp.Event = api.EventReconnect
+		resp.Action = api.ActionFailure
+		resp.Reason = reason
+		edgeutil.ReportTaskResult(taskReq.Type, taskReq.TaskID, resp)
+	}
+	if _, err := exec.LookPath("systemctl"); err != nil {
+		fail("the hook asked for a reboot but the host is not managed by systemd, it cannot be rebooted")
+		return
+	}
+	pending.Reboot = true
+	if err := savePendingOSUpgrade(pending); err != nil {
+		fail(err.Error())
+		return
+	}
+	resp.Event = api.EventReboot
+	resp.Reason = "the hook asked for a reboot"
+	edgeutil.ReportTaskResult(taskReq.Type, taskReq.TaskID, resp)
+
+	// reboot in a child process which outlives edgecore, it gives edgecore the time to send the report
+	command := "nohup bash -c 'sleep 2; systemctl reboot' > /tmp/edgecore-os-upgrade.log 2>&1 &"
+	if s, err := taskCommand(taskReq, command).CombinedOutput(); err != nil {
+		fail(fmt.Sprintf("failed to reboot: %v, %s", err, s))
+		return
+	}
+	klog.Infof("reboot the host for task %s", taskReq.TaskID)
+}
+
+// downloadOSUpgradeArtifact downloads the artifact to dir, it fails if its digest does not match
+func downloadOSUpgradeArtifact(ctx context.Context, upgradeReq commontypes.OSUpgradeJobRequest, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "artifact")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, upgradeReq.ArtifactURL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download the artifact: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download the artifact: %s", resp.Status)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err = io.Copy(io.MultiWriter(file, hash), resp.Body); err != nil {
+		return "", fmt.Errorf("failed to download the artifact: %v", err)
+	}
+	if digest := hex.EncodeToString(hash.Sum(nil)); digest != upgradeReq.ArtifactSHA256 {
+		return "", fmt.Errorf("the digest of the artifact is %s, expected %s", digest, upgradeReq.ArtifactSHA256)
+	}
+	return path, nil
+}
+
+// verifyOSUpgradeHook returns the path of the hook once its signature is verified by one of the trusted
+// keys of the node, and its digest matches the pinned one if any
+func verifyOSUpgradeHook(name, pinned string) (string, error) {
+	if name == "" || filepath.Base(name) != name || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid hook name %q", name)
+	}
+	path := filepath.Join(osUpgradeHookPath, name)
+	script, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the hook: %v", err)
+	}
+	if pinned != "" {
+		if digest := sha256.Sum256(script); hex.EncodeToString(digest[:]) != pinned {
+			return "", fmt.Errorf("the digest of the hook %s does not match %s", name, pinned)
+		}
+	}
+	data, err := os.ReadFile(path + ".sig")
+	if err != nil {
+		return "", fmt.Errorf("failed to read the signature of the hook: %v", err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return "", fmt.Errorf("invalid signature of the hook %s: %v", name, err)
+	}
+	keys, err := loadTrustedKeys()
+	if err != nil {
+		return "", err
+	}
+	for _, key := range keys {
+		if ed25519.Verify(key, script, signature) {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("the signature of the hook %s is not verified by the keys in %s", name, osUpgradeTrustedKeysFile)
+}
+
+// loadTrustedKeys returns the ed25519 public keys of osUpgradeTrustedKeysFile
+func loadTrustedKeys() ([]ed25519.PublicKey, error) {
+	data, err := os.ReadFile(osUpgradeTrustedKeysFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the trusted keys: %v", err)
+	}
+	var keys []ed25519.PublicKey
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "PUBLIC KEY" {
+			continue
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			klog.Warningf("invalid key in %s: %v", osUpgradeTrustedKeysFile, err)
+			continue
+		}
+		if key, ok := key.(ed25519.PublicKey); ok {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no ed25519 public key in %s", osUpgradeTrustedKeysFile)
+	}
+	return keys, nil
+}
+
+func getOSUpgradeJobRequest(taskReq types.NodeTaskRequest) (*commontypes.OSUpgradeJobRequest, error) {
+	var upgradeReq commontypes.OSUpgradeJobRequest
+	data, err := json.Marshal(taskReq.Item)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &upgradeReq); err != nil {
+		return nil, err
+	}
+	return &upgradeReq, nil
+}
+
+func savePendingOSUpgrade(pending pendingOSUpgrade) error {
+	record, err := json.Marshal(pending)
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(osUpgradeFile, record, 0600); err != nil {
+		return fmt.Errorf("failed to record the upgrade: %v", err)
+	}
+	return nil
+}
+
+func loadPendingOSUpgrade() (*pendingOSUpgrade, error) {
+	data, err := os.ReadFile(osUpgradeFile)
+	if err != nil {
+		return nil, err
+	}
+	pending := &pendingOSUpgrade{}
+	if err = json.Unmarshal(data, pending); err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// ReportOSUpgrade reports the upgrade which was running before edgecore stopped once connected to cloud.
+// The node reconnected if the host rebooted, whether the hook asked for it or rebooted the host itself,
+// the upgrade fails otherwise since the result of the hook is lost.
+func ReportOSUpgrade(nodeName string) {
+	pending, err := loadPendingOSUpgrade()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			klog.Warningf("failed to load the pending upgrade: %v", err)
+		}
+		return
+	}
+	if !waitCloudConnected() {
+		return
+	}
+
+	resp := commontypes.NodeTaskResponse{
+		NodeName:    nodeName,
+		Event:       api.EventReconnect,
+		Action:      api.ActionSuccess,
+		Environment: util.CollectEnvironment(options.GetEdgeCoreConfig()),
+		Metadata:    pending.Metadata,
+
+		CommandDigest: util.TaskAuditDigest(TaskOSUpgrade, pending.TaskID),
+	}
+	if id, err := bootID(); err != nil || id == pending.BootID {
+		resp.Action = api.ActionFailure
+		resp.Reason = "edgecore restarted while the hook was running, the result of the hook is lost"
+		if pending.Reboot {
+			resp.Reason = "edgecore restarted but the host did not reboot"
+		}
+	}
+	state := api.OSUpgradingState
+	if pending.Reboot {
+		state = api.RebootingState
+	}
+	klog.Infof("report upgrade of task %s: %s", pending.TaskID, resp.Action)
+	if err = util.SaveTaskReport(TaskOSUpgrade, pending.TaskID, string(state), resp); err != nil {
+		klog.Warningf("failed to save task report: %v", err)
+	}
+	edgeutil.ReportTaskResult(TaskOSUpgrade, pending.TaskID, resp)
+	if err = os.RemoveAll(filepath.Join(osUpgradeArtifactPath, pending.TaskID)); err != nil {
+		klog.Warningf("failed to remove the artifact of task %s: %v", pending.TaskID, err)
+	}
+	if err = os.Remove(osUpgradeFile); err != nil {
+		klog.Warningf("failed to remove %s: %v", osUpgradeFile, err)
+	}
+}
@@ -17,8 +17,11 @@ limitations under the License.
 package taskexecutor
 
 import (
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/shirou/gopsutil/cpu"
@@ -26,6 +29,8 @@ import (
 	"github.com/shirou/gopsutil/v3/mem"
 
 	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/cmd/edgecore/app/options"
+	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/certificate"
 	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
 	"github.com/kubeedge/kubeedge/pkg/util/fsm"
 )
@@ -34,6 +39,10 @@ const (
 	MaxCPUUsage  float64 = 80
 	MaxMemUsage  float64 = 80
 	MaxDiskUsage float64 = 80
+
+	// CertExpiryImminent is how long before it expires the certificate of the node fails the check,
+	// the node may drop offline while it is operated on otherwise
+	CertExpiryImminent = 7 * 24 * time.Hour
 )
 
 func preCheck(taskReq types.NodeTaskRequest) fsm.Event {
@@ -57,12 +66,29 @@ func preCheck(taskReq types.NodeTaskRequest) fsm.Event {
 		return event
 	}
 
-	var failed bool
+	var failed, warned bool
 	var checkResult = map[string]string{}
+	var warnings = map[string]string{}
 	var checkFunc = map[string]func() error{
 		"cpu":  checkCPU,
 		"mem":  checkMem,
 		"disk": checkDisk,
+		"cert": func() error {
+			config := options.GetEdgeCoreConfig().Modules.EdgeHub
+			// the expiring certificate is rotated by edgehub, which reconnects with the new one
+			rotate := checkItems.RotateCertificate && config.RotateCertificates
+			warning, err := checkCert(config.TLSCertFile, time.Now(), rotate)
+			if warning != "" {
+				warnings["cert"] = warning
+				if rotate {
+					certificate.RequestRotation()
+				}
+			}
+			return err
+		},
+		"registry": func() error {
+			return checkRegistry(checkItems.RegistryProbe)
+		},
 	}
 	for _, item := range checkItems.CheckItem {
 		f, ok := checkFunc[item]
@@ -76,12 +102,31 @@ func preCheck(taskReq types.NodeTaskRequest) fsm.Event {
 			checkResult[item] = err.Error()
 			continue
 		}
+		if warning, ok := warnings[item]; ok {
+			// the node passes the check, the warning is reported with its result
+			warned = true
+			checkResult[item] = warning
+			continue
+		}
 		checkResult[item] = "ok"
 	}
-	if !failed {
+	if checkItems.DryRun {
+		// the verdict of the node is reported whether it passed or not
+		for item, f := range feasibilityChecks[taskReq.Type] {
+			found, err := f()
+			if err != nil {
+				failed = true
+				checkResult[item] = err.Error()
+				continue
+			}
+			checkResult[item] = found
+		}
+	} else if !failed && !warned {
 		return event
 	}
-	event.Action = api.ActionFailure
+	if failed {
+		event.Action = api.ActionFailure
+	}
 	result, err := json.Marshal(checkResult)
 	if err != nil {
 		event.Msg = err.Error()
@@ -149,6 +194,52 @@ func checkDisk() error {
 	return fmt.Errorf(string(result))
 }
 
+// checkCert checks the certificate in certFile does not expire soon, it only reads the certificate.
+// The certificate fails the check if it expires within CertExpiryImminent, unless it is going to be
+// rotated, and is reported as a warning if it expires within certExpiryWarning.
+func checkCert(certFile string, now time.Time, rotate bool) (string, error) {
+	cert, err := edgeCertificate(certFile)
+	if err != nil {
+		return "", err
+	}
+	if now.Before(cert.NotBefore) {
+		return "", fmt.Errorf("certificate is not valid before %s", cert.NotBefore.UTC().Format(time.RFC3339))
+	}
+	if now.After(cert.NotAfter) {
+		return "", fmt.Errorf("certificate expired at %s", cert.NotAfter.UTC().Format(time.RFC3339))
+	}
+	remaining := cert.NotAfter.Sub(now)
+	if remaining >= certExpiryWarning {
+		return "", nil
+	}
+
+	expiry := cert.NotAfter.UTC().Format(time.RFC3339)
+	if rotate {
+		return fmt.Sprintf("certificate expires at %s, rotation is requested", expiry), nil
+	}
+	if remaining < CertExpiryImminent {
+		return "", fmt.Errorf("certificate expires at %s, within %s", expiry, CertExpiryImminent)
+	}
+	return fmt.Sprintf("certificate expires at %s", expiry), nil
+}
+
+// edgeCertificate reads the edgehub certificate of the node
+func edgeCertificate(certFile string) (*x509.Certificate, error) {
+	data, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate %s: %v", certFile, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("certificate %s is not PEM encoded", certFile)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate %s: %v", certFile, err)
+	}
+	return cert, nil
+}
+
 func normalInit(types.NodeTaskRequest) fsm.Event {
 	return fsm.Event{
 		Type:   "Init",
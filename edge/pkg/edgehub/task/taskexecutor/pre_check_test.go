@@ -0,0 +1,179 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskexecutor
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubeedge/kubeedge/common/types"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+)
+
+// writeCert writes a self-signed certificate valid from notBefore to notAfter to a file in dir
+func writeCert(t *testing.T, dir string, notBefore, notAfter time.Time) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "system:node:edge-1"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	certFile := filepath.Join(dir, "server.crt")
+	if err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatalf("failed to write certificate: %v", err)
+	}
+	return certFile
+}
+
+func TestCheckCert(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+
+	for _, tc := range []struct {
+		name     string
+		notAfter time.Time
+		// notBefore defaults to a day before now
+		notBefore time.Time
+		rotate    bool
+		warned    bool
+		failed    bool
+	}{
+		{name: "valid", notAfter: now.Add(90 * day)},
+		{name: "expiring", notAfter: now.Add(20 * day), warned: true},
+		{name: "expiring with rotation", notAfter: now.Add(20 * day), rotate: true, warned: true},
+		{name: "expiry imminent", notAfter: now.Add(3 * day), failed: true},
+		{name: "expiry imminent with rotation", notAfter: now.Add(3 * day), rotate: true, warned: true},
+		{name: "expired", notAfter: now.Add(-time.Hour), rotate: true, failed: true},
+		{name: "not yet valid", notBefore: now.Add(time.Hour), notAfter: now.Add(90 * day), rotate: true, failed: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			notBefore := tc.notBefore
+			if notBefore.IsZero() {
+				notBefore = now.Add(-day)
+			}
+			certFile := writeCert(t, t.TempDir(), notBefore, tc.notAfter)
+			before, err := os.ReadFile(certFile)
+			if err != nil {
+				t.Fatalf("failed to read certificate: %v", err)
+			}
+
+			warning, err := checkCert(certFile, now, tc.rotate)
+			if (err != nil) != tc.failed {
+				t.Errorf("expected the check to fail: %t, got %v", tc.failed, err)
+			}
+			if (warning != "") != tc.warned {
+				t.Errorf("expected a warning: %t, got %q", tc.warned, warning)
+			}
+			// the check never renews the certificate
+			after, err := os.ReadFile(certFile)
+			if err != nil {
+				t.Fatalf("failed to read certificate: %v", err)
+			}
+			if !bytes.Equal(before, after) {
+				t.Errorf("expected the certificate to be left as it is")
+			}
+		})
+	}
+
+	if _, err := checkCert(filepath.Join(t.TempDir(), "missing.crt"), now, true); err == nil {
+		t.Errorf("expected the check to fail without the certificate")
+	}
+}
+
+func TestPreCheckDryRun(t *testing.T) {
+	checks := feasibilityChecks
+	defer func() { feasibilityChecks = checks }()
+
+	for _, tc := range []struct {
+		name   string
+		dryRun bool
+		checks map[string]func() (string, error)
+		action api.Action
+		result map[string]string
+	}{
+		{
+			name:   "feasible",
+			dryRun: true,
+			checks: map[string]func() (string, error){
+				"permission":     func() (string, error) { return "root", nil },
+				"serviceManager": func() (string, error) { return "systemd", nil },
+			},
+			action: api.ActionSuccess,
+			result: map[string]string{"permission": "root", "serviceManager": "systemd"},
+		},
+		{
+			name:   "not feasible",
+			dryRun: true,
+			checks: map[string]func() (string, error){
+				"permission":  func() (string, error) { return "", errors.New("edgecore runs as uid 1000, the upgrade needs root") },
+				"upgradeDisk": func() (string, error) { return "2048 MiB free on /etc/kubeedge", nil },
+			},
+			action: api.ActionFailure,
+			result: map[string]string{"permission": "edgecore runs as uid 1000, the upgrade needs root", "upgradeDisk": "2048 MiB free on /etc/kubeedge"},
+		},
+		{
+			name: "not a dry run",
+			checks: map[string]func() (string, error){
+				"permission": func() (string, error) { return "", errors.New("the check is not run") },
+			},
+			action: api.ActionSuccess,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			feasibilityChecks = map[string]map[string]func() (string, error){TaskUpgrade: tc.checks}
+			event := preCheck(types.NodeTaskRequest{Type: TaskUpgrade, Item: types.NodePreCheckRequest{DryRun: tc.dryRun}})
+			if event.Action != tc.action {
+				t.Errorf("expected action %s, got %s: %s", tc.action, event.Action, event.Msg)
+			}
+			if tc.result == nil {
+				if event.Msg != "" {
+					t.Errorf("expected no verdict without the dry run, got %s", event.Msg)
+				}
+				return
+			}
+			// the verdict is reported whether the node passed or not
+			var result map[string]string
+			if err := json.Unmarshal([]byte(event.Msg), &result); err != nil {
+				t.Fatalf("failed to unmarshal the verdict %q: %v", event.Msg, err)
+			}
+			if !reflect.DeepEqual(result, tc.result) {
+				t.Errorf("expected verdict %v, got %v", tc.result, result)
+			}
+		})
+	}
+}
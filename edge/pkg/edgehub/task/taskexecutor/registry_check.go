@@ -0,0 +1,331 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/distribution/distribution/v3/reference"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/cmd/edgecore/app/options"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+)
+
+const (
+	// registryProbeDuration bounds how long the registry check item downloads from the registry
+	registryProbeDuration = 5 * time.Second
+	// registryProbeBytes bounds how much the registry check item downloads from the registry
+	registryProbeBytes = 32 << 20
+	// registryRequestTimeout bounds each request to the registry but the download
+	registryRequestTimeout = 10 * time.Second
+)
+
+var (
+	// manifestMediaTypes are the manifests the registry check item reads
+	manifestMediaTypes = []string{
+		"application/vnd.oci.image.index.v1+json",
+		"application/vnd.docker.distribution.manifest.list.v2+json",
+		"application/vnd.oci.image.manifest.v1+json",
+		"application/vnd.docker.distribution.manifest.v2+json",
+	}
+	// challengeParam matches a parameter of the authentication challenge of a registry, e.g. realm="https://auth.docker.io/token"
+	challengeParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+)
+
+// registryManifest is an image index or an image manifest of a registry
+type registryManifest struct {
+	Manifests []struct {
+		Digest   string `json:"digest"`
+		Platform struct {
+			Architecture string `json:"architecture"`
+			OS           string `json:"os"`
+		} `json:"platform"`
+	} `json:"manifests"`
+	Layers []struct {
+		Digest string `json:"digest"`
+		Size   int64  `json:"size"`
+	} `json:"layers"`
+}
+
+// registryRepository reads a repository of a registry with the distribution API, it authenticates
+// with the token of the registry once the registry challenges it
+type registryRepository struct {
+	client *http.Client
+	base   string
+	auth   *runtimeapi.AuthConfig
+	token  string
+}
+
+func newRegistryRepository(named reference.Named, auth *runtimeapi.AuthConfig) *registryRepository {
+	domain := reference.Domain(named)
+	if domain == "docker.io" {
+		domain = "registry-1.docker.io"
+	}
+	return &registryRepository{
+		client: &http.Client{},
+		base:   fmt.Sprintf("https://%s/v2/%s", domain, reference.Path(named)),
+		auth:   auth,
+	}
+}
+
+// get sends a GET request to the path of the repository, it authenticates and sends it again if the
+// registry challenges it. The response is returned only if it is successful.
+func (r *registryRepository) get(ctx context.Context, path string, accept []string) (*http.Response, error) {
+	for authenticated := false; ; authenticated = true {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.base+path, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(accept) != 0 {
+			req.Header.Set("Accept", strings.Join(accept, ", "))
+		}
+		if r.token != "" {
+			req.Header.Set("Authorization", r.token)
+		}
+		resp, err := r.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusOK {
+			return resp, nil
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized || authenticated {
+			return nil, fmt.Errorf("GET %s: %s", req.URL.Path, resp.Status)
+		}
+		if err = r.authenticate(ctx, resp.Header.Get("WWW-Authenticate")); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// authenticate answers the authentication challenge of the registry with the credentials of the pull
+// secret, anonymously if there is no pull secret
+func (r *registryRepository) authenticate(ctx context.Context, challenge string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if r.auth == nil || r.auth.Username == "" {
+			return fmt.Errorf("the registry requires credentials")
+		}
+		req := http.Request{Header: http.Header{}}
+		req.SetBasicAuth(r.auth.Username, r.auth.Password)
+		r.token = req.Header.Get("Authorization")
+		return nil
+	case "bearer":
+	default:
+		return fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+
+	values := url.Values{}
+	var realm string
+	for _, param := range challengeParam.FindAllStringSubmatch(params, -1) {
+		if param[1] == "realm" {
+			realm = param[2]
+			continue
+		}
+		values.Set(param[1], param[2])
+	}
+	if realm == "" {
+		return fmt.Errorf("the authentication challenge %q has no realm", challenge)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm+"?"+values.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	if r.auth != nil && r.auth.Username != "" {
+		req.SetBasicAuth(r.auth.Username, r.auth.Password)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to get a token of the registry: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to get a token of the registry: %s", resp.Status)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return fmt.Errorf("failed to decode the token of the registry: %v", err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	r.token = "Bearer " + token.Token
+	return nil
+}
+
+// manifest returns the image manifest of the reference for the platform of the node
+func (r *registryRepository) manifest(ctx context.Context, ref string) (*registryManifest, error) {
+	for {
+		resp, err := r.get(ctx, "/manifests/"+ref, manifestMediaTypes)
+		if err != nil {
+			return nil, err
+		}
+		manifest := &registryManifest{}
+		err = json.NewDecoder(resp.Body).Decode(manifest)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the manifest %s: %v", ref, err)
+		}
+		if len(manifest.Manifests) == 0 {
+			return manifest, nil
+		}
+
+		ref = ""
+		for _, m := range manifest.Manifests {
+			if m.Platform.OS == runtime.GOOS && m.Platform.Architecture == runtime.GOARCH {
+				ref = m.Digest
+				break
+			}
+		}
+		if ref == "" {
+			return nil, fmt.Errorf("the image has no manifest for %s/%s", runtime.GOOS, runtime.GOARCH)
+		}
+	}
+}
+
+// measure downloads the blob for up to registryProbeDuration or registryProbeBytes, it returns the
+// latency of the registry and the throughput of the download in bytes per second
+func (r *registryRepository) measure(digest string) (time.Duration, float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), registryProbeDuration+registryRequestTimeout)
+	defer cancel()
+	start := time.Now()
+	resp, err := r.get(ctx, "/blobs/"+digest, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer resp.Body.Close()
+	latency := time.Since(start)
+
+	body := io.LimitReader(resp.Body, registryProbeBytes)
+	downloadStart := time.Now()
+	var downloaded int64
+	buf := make([]byte, 32<<10)
+	for time.Since(downloadStart) < registryProbeDuration {
+		n, err := body.Read(buf)
+		downloaded += int64(n)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to download blob %s: %v", digest, err)
+		}
+	}
+	elapsed := time.Since(downloadStart).Seconds()
+	if downloaded == 0 || elapsed == 0 {
+		return latency, 0, fmt.Errorf("nothing is downloaded from the registry in %s", registryProbeDuration)
+	}
+	return latency, float64(downloaded) / elapsed, nil
+}
+
+// checkRegistry checks the node can download the images of the task which are not on the node yet
+// from their registry within the timeout of the pull. It measures the throughput of the download of
+// the largest layer and estimates how long the pull takes from the size of the layers.
+func checkRegistry(probe *types.RegistryProbe) error {
+	if probe == nil || len(probe.Images) == 0 {
+		return nil
+	}
+	auth, err := makeAuthConfig(probe.Secret)
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	var largest struct {
+		repository *registryRepository
+		digest     string
+		size       int64
+	}
+	for _, image := range probe.Images {
+		if imagePresent(image) {
+			continue
+		}
+		named, err := reference.ParseNormalizedNamed(image)
+		if err != nil {
+			return fmt.Errorf("invalid image %s: %v", image, err)
+		}
+		ref := "latest"
+		if digested, ok := named.(reference.Digested); ok {
+			ref = digested.Digest().String()
+		} else if tagged, ok := named.(reference.Tagged); ok {
+			ref = tagged.Tag()
+		}
+
+		repository := newRegistryRepository(named, auth)
+		ctx, cancel := context.WithTimeout(context.Background(), registryRequestTimeout)
+		manifest, err := repository.manifest(ctx, ref)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to read the manifest of image %s: %v", image, err)
+		}
+		for _, layer := range manifest.Layers {
+			total += layer.Size
+			if layer.Size > largest.size {
+				largest.repository, largest.digest, largest.size = repository, layer.Digest, layer.Size
+			}
+		}
+	}
+	if largest.repository == nil {
+		return nil
+	}
+
+	latency, throughput, err := largest.repository.measure(largest.digest)
+	if err != nil {
+		return err
+	}
+	estimate := latency + time.Duration(float64(total)/throughput*float64(time.Second))
+	timeout := time.Duration(probe.TimeoutSeconds) * time.Second
+	klog.Infof("registry latency is %s, throughput is %.2f MiB/s, pulling %.2f MiB takes about %s",
+		latency.Round(time.Millisecond), throughput/(1<<20), float64(total)/(1<<20), estimate.Round(time.Second))
+	if timeout != 0 && estimate > timeout {
+		return fmt.Errorf("pulling %.2f MiB at %.2f MiB/s takes about %s, which exceeds the timeout %s",
+			float64(total)/(1<<20), throughput/(1<<20), estimate.Round(time.Second), timeout)
+	}
+	return nil
+}
+
+// imagePresent returns true if the image is on the node already, it is assumed not to be if the
+// container runtime cannot tell
+func imagePresent(image string) bool {
+	kubelet := options.GetEdgeCoreConfig().Modules.Edged.TailoredKubeletConfig
+	container, err := util.NewContainerRuntime(kubelet.ContainerRuntimeEndpoint, kubelet.CgroupDriver)
+	if err != nil {
+		return false
+	}
+	cri, ok := container.(*util.CRIRuntime)
+	if !ok {
+		return false
+	}
+	status, err := cri.ImageManagerService.ImageStatus(context.Background(), &runtimeapi.ImageSpec{Image: image}, false)
+	return err == nil && status.GetImage() != nil
+}
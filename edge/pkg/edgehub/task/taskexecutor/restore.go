@@ -0,0 +1,216 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskexecutor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/common/types"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/cmd/edgecore/app/options"
+	edgeutil "github.com/kubeedge/kubeedge/edge/pkg/common/util"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+const TaskRestore = "restore"
+
+// restoreFile records the restore applied on the node until edgecore started again and reported its result
+var restoreFile = filepath.Join(util.KubeEdgePath, "restore.json")
+
+type Restore struct {
+	*BaseExecutor
+}
+
+func (r *Restore) Name() string {
+	return r.name
+}
+
+func NewRestoreExecutor() Executor {
+	methods := map[string]func(types.NodeTaskRequest) fsm.Event{
+		string(api.TaskInit):       initRestore,
+		"":                         initRestore,
+		string(api.RestoringState): restoreEdgeCore,
+		string(api.TaskCancelling): cancelTask,
+	}
+	return &Restore{
+		BaseExecutor: NewBaseExecutor(TaskRestore, methods),
+	}
+}
+
+// pendingRestore is the restore waiting for edgecore to start again
+type pendingRestore struct {
+	TaskID   string            `json:"taskID"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// initRestore checks the node has the backup of the BackupJob and edgecore can be restarted
+func initRestore(taskReq types.NodeTaskRequest) fsm.Event {
+	event := fsm.Event{
+		Type:   "Init",
+		Action: api.ActionSuccess,
+	}
+	if _, _, err := loadBackupManifest(taskReq); err != nil {
+		event.Action = api.ActionFailure
+		event.Msg = err.Error()
+	} else if _, err = exec.LookPath("systemctl"); err != nil {
+		event.Action = api.ActionFailure
+		event.Msg = "edgecore is not managed by systemd, it cannot be stopped to restore the backup"
+	}
+	return event
+}
+
+// restoreEdgeCore stops edgecore, copies the files of the backup back to their paths and starts edgecore
+// again in background. The result is reported by edgecore once it started, with ReportRestore.
+func restoreEdgeCore(taskReq types.NodeTaskRequest) fsm.Event {
+	event := fsm.Event{
+		Type:   api.EventRestore,
+		Action: api.ActionFailure,
+	}
+	if pending, err := loadPendingRestore(); err == nil && pending.TaskID == taskReq.TaskID {
+		klog.Infof("backup of task %s is already being restored, wait for edgecore to start", taskReq.TaskID)
+		return fsm.Event{}
+	}
+	dir, manifest, err := loadBackupManifest(taskReq)
+	if err != nil {
+		event.Msg = err.Error()
+		return event
+	}
+	names := make([]string, 0, len(manifest))
+	for name := range manifest {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	copies := make([]string, 0, len(names))
+	for _, name := range names {
+		dst := manifest[name]
+		if name == backupDatabaseFile {
+			// the journal of the local database belongs to the database it replaces
+			copies = append(copies, fmt.Sprintf("rm -f %s-wal %s-shm", dst, dst))
+		}
+		copies = append(copies, fmt.Sprintf("cp -f %s %s", filepath.Join(dir, name), dst))
+	}
+
+	record, err := json.Marshal(pendingRestore{TaskID: taskReq.TaskID, Metadata: taskReq.Metadata})
+	if err != nil {
+		event.Msg = err.Error()
+		return event
+	}
+	if err = os.WriteFile(restoreFile, record, 0600); err != nil {
+		event.Msg = fmt.Sprintf("failed to record the restore: %v", err)
+		return event
+	}
+
+	// restore in a child process which outlives edgecore, edgecore is started again whether the
+	// files are restored or not
+	restoreCmd := fmt.Sprintf("sleep 2; systemctl stop edgecore; %s; systemctl start edgecore", strings.Join(copies, "; "))
+	command := fmt.Sprintf("nohup bash -c '%s' > /tmp/edgecore-restore.log 2>&1 &", restoreCmd)
+	if s, err := taskCommand(taskReq, command).CombinedOutput(); err != nil {
+		_ = os.Remove(restoreFile)
+		event.Msg = fmt.Sprintf("failed to restore the backup: %v, %s", err, s)
+		return event
+	}
+	klog.Infof("restore the backup %s for task %s, stop edgecore", dir, taskReq.TaskID)
+	return fsm.Event{}
+}
+
+// loadBackupManifest returns the directory of the backup the task restores and the paths its files
+// are restored to
+func loadBackupManifest(taskReq types.NodeTaskRequest) (string, map[string]string, error) {
+	var restoreReq commontypes.RestoreJobRequest
+	data, err := json.Marshal(taskReq.Item)
+	if err == nil {
+		err = json.Unmarshal(data, &restoreReq)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if restoreReq.BackupJob == "" || filepath.Base(restoreReq.BackupJob) != restoreReq.BackupJob {
+		return "", nil, fmt.Errorf("invalid BackupJob name %q", restoreReq.BackupJob)
+	}
+	dir := filepath.Join(backupLocation(restoreReq.Location), restoreReq.BackupJob)
+	data, err = os.ReadFile(filepath.Join(dir, backupManifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil, fmt.Errorf("the node has no backup of BackupJob %s in %s", restoreReq.BackupJob, dir)
+		}
+		return "", nil, fmt.Errorf("failed to read the backup manifest: %v", err)
+	}
+	manifest := map[string]string{}
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return "", nil, fmt.Errorf("invalid backup manifest: %v", err)
+	}
+	for name := range manifest {
+		if _, err = os.Stat(filepath.Join(dir, name)); err != nil {
+			return "", nil, fmt.Errorf("the backup of BackupJob %s is incomplete: %v", restoreReq.BackupJob, err)
+		}
+	}
+	return dir, manifest, nil
+}
+
+func loadPendingRestore() (*pendingRestore, error) {
+	data, err := os.ReadFile(restoreFile)
+	if err != nil {
+		return nil, err
+	}
+	pending := &pendingRestore{}
+	if err = json.Unmarshal(data, pending); err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// ReportRestore reports the restore applied while edgecore was stopped once connected to cloud
+func ReportRestore(nodeName string) {
+	pending, err := loadPendingRestore()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			klog.Warningf("failed to load the pending restore: %v", err)
+		}
+		return
+	}
+	if !waitCloudConnected() {
+		return
+	}
+
+	resp := commontypes.NodeTaskResponse{
+		NodeName:    nodeName,
+		Event:       api.EventRestore,
+		Action:      api.ActionSuccess,
+		Environment: util.CollectEnvironment(options.GetEdgeCoreConfig()),
+		Metadata:    pending.Metadata,
+
+		CommandDigest: util.TaskAuditDigest(TaskRestore, pending.TaskID),
+	}
+	klog.Infof("report restore of task %s: %s", pending.TaskID, resp.Action)
+	if err = util.SaveTaskReport(TaskRestore, pending.TaskID, string(api.RestoringState), resp); err != nil {
+		klog.Warningf("failed to save task report: %v", err)
+	}
+	edgeutil.ReportTaskResult(TaskRestore, pending.TaskID, resp)
+	if err = os.Remove(restoreFile); err != nil {
+		klog.Warningf("failed to remove %s: %v", restoreFile, err)
+	}
+}
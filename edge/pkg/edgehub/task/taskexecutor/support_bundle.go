@@ -0,0 +1,205 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskexecutor
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeedge/kubeedge/common/types"
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/edge/cmd/edgecore/app/options"
+	edgeutil "github.com/kubeedge/kubeedge/edge/pkg/common/util"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+	"github.com/kubeedge/kubeedge/pkg/util/supportbundle"
+	"github.com/kubeedge/kubeedge/pkg/version"
+)
+
+const (
+	TaskSupportBundle = "supportbundle"
+
+	// maxBundleSize bounds the compressed bundle of the node, it is reported to cloud in a single message
+	maxBundleSize = 4 << 20
+)
+
+type SupportBundle struct {
+	*BaseExecutor
+}
+
+func (s *SupportBundle) Name() string {
+	return s.name
+}
+
+func NewSupportBundleExecutor() Executor {
+	methods := map[string]func(types.NodeTaskRequest) fsm.Event{
+		string(api.TaskInit):        emptyInit,
+		"":                          emptyInit,
+		string(api.CollectingState): collectBundle,
+		string(api.TaskCancelling):  cancelTask,
+	}
+	return &SupportBundle{
+		BaseExecutor: NewBaseExecutor(TaskSupportBundle, methods),
+	}
+}
+
+// collectBundle collects the bundle of the node in background, the bundle is reported
+// base64 encoded in the external message of the result.
+func collectBundle(taskReq types.NodeTaskRequest) fsm.Event {
+	event := fsm.Event{
+		Type:   "Collect",
+		Action: api.ActionSuccess,
+	}
+
+	var bundleReq commontypes.SupportBundleJobRequest
+	data, err := json.Marshal(taskReq.Item)
+	if err == nil {
+		err = json.Unmarshal(data, &bundleReq)
+	}
+	if err != nil {
+		event.Msg = err.Error()
+		event.Action = api.ActionFailure
+		return event
+	}
+
+	edgeCoreConfig := options.GetEdgeCoreConfig()
+	ctx, done := startStep(taskReq)
+	go func() {
+		defer done()
+		bundle, err := buildBundle(ctx, taskReq, edgeCoreConfig, int(bundleReq.LogLines))
+		if ctx.Err() != nil || IsCancelled(taskReq.Type, taskReq.TaskID) {
+			klog.Infof("task %s is cancelled, drop the support bundle", taskReq.TaskID)
+			return
+		}
+		resp := commontypes.NodeTaskResponse{
+			NodeName:    edgeCoreConfig.Modules.Edged.HostnameOverride,
+			Event:       event.Type,
+			Action:      event.Action,
+			Environment: util.CollectEnvironment(edgeCoreConfig),
+			Metadata:    taskReq.Metadata,
+
+			CommandDigest: util.TaskAuditDigest(taskReq.Type, taskReq.TaskID),
+		}
+		if err != nil {
+			resp.Action = api.ActionFailure
+			resp.Reason = err.Error()
+		} else {
+			resp.ExternalMessage = base64.StdEncoding.EncodeToString(bundle)
+		}
+		edgeutil.ReportTaskResult(taskReq.Type, taskReq.TaskID, resp)
+		endRun(taskReq.Type, taskReq.TaskID)
+	}()
+	return fsm.Event{}
+}
+
+// buildBundle archives the versions, the redacted config, the recent logs and the task history
+// of the node. The logs are left out if the bundle would exceed maxBundleSize.
+func buildBundle(ctx context.Context, taskReq types.NodeTaskRequest, config *v1alpha2.EdgeCoreConfig, logLines int) ([]byte, error) {
+	bundle, err := writeBundle(ctx, taskReq, config, logLines)
+	if err != nil {
+		return nil, err
+	}
+	if len(bundle) <= maxBundleSize {
+		return bundle, nil
+	}
+	klog.Warningf("support bundle of %d bytes exceeds %d bytes, leave out the logs", len(bundle), maxBundleSize)
+	bundle, err = writeBundle(ctx, taskReq, config, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(bundle) > maxBundleSize {
+		return nil, fmt.Errorf("support bundle of %d bytes exceeds %d bytes", len(bundle), maxBundleSize)
+	}
+	return bundle, nil
+}
+
+func writeBundle(ctx context.Context, taskReq types.NodeTaskRequest, config *v1alpha2.EdgeCoreConfig, logLines int) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	w := supportbundle.NewWriter(buf)
+
+	err := w.AddJSON("version.json", map[string]interface{}{
+		"edgecore":    version.Get(),
+		"environment": util.CollectEnvironment(config),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := yaml.Marshal(config)
+	if err == nil {
+		data, err = supportbundle.RedactConfig(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to redact edgecore config: %v", err)
+	}
+	if err = w.Add("edgecore.yaml", data); err != nil {
+		return nil, err
+	}
+
+	if logLines > 0 {
+		if err = w.Add("edgecore.log", edgeCoreLogs(ctx, taskReq, logLines)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = w.AddJSON("tasks.json", taskHistory()); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to archive support bundle: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// edgeCoreLogs returns the last lines of the edgecore journal, or why they are unavailable
+func edgeCoreLogs(ctx context.Context, taskReq types.NodeTaskRequest, lines int) []byte {
+	out, err := stepCommand(ctx, taskReq, "journalctl", "-u", "edgecore.service", "--no-pager", "-n", strconv.Itoa(lines)).Output()
+	if err != nil {
+		return []byte(fmt.Sprintf("failed to read edgecore logs from journal: %v\n", err))
+	}
+	return supportbundle.RedactText(out)
+}
+
+// taskHistory returns the last results of the tasks buffered on the node, the external
+// messages are left out as they may hold former bundles.
+func taskHistory() []commontypes.NodeTaskReport {
+	tasks, err := util.ListTaskReports()
+	if err != nil {
+		klog.Warningf("failed to list task reports: %v", err)
+		return nil
+	}
+	history := make([]commontypes.NodeTaskReport, 0, len(tasks))
+	for _, task := range tasks {
+		report, err := util.LoadTaskReport(task.Type, task.TaskID)
+		if err != nil {
+			klog.Warningf("failed to load task report %s/%s: %v", task.Type, task.TaskID, err)
+			continue
+		}
+		report.Response.ExternalMessage = ""
+		history = append(history, *report)
+	}
+	return history
+}
@@ -18,10 +18,14 @@ package taskexecutor
 
 import (
 	"fmt"
+	"os"
+	"os/exec"
+	"sync"
 
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
 	"github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
 	"github.com/kubeedge/kubeedge/pkg/util/fsm"
 )
@@ -29,6 +33,13 @@ import (
 func init() {
 	Register(TaskUpgrade, NewUpgradeExecutor())
 	Register(TaskPrePull, NewPrePullExecutor())
+	Register(TaskSupportBundle, NewSupportBundleExecutor())
+	Register(TaskConfigUpdate, NewConfigUpdateExecutor())
+	Register(TaskRestart, NewRestartExecutor())
+	Register(TaskDiagnose, NewDiagnoseExecutor())
+	Register(TaskBackup, NewBackupExecutor())
+	Register(TaskRestore, NewRestoreExecutor())
+	Register(TaskOSUpgrade, NewOSUpgradeExecutor())
 }
 
 type Executor interface {
@@ -65,9 +76,13 @@ func (be *BaseExecutor) Do(taskReq types.NodeTaskRequest) (fsm.Event, error) {
 var (
 	executors     = make(map[string]Executor)
 	CommonMethods = map[string]func(types.NodeTaskRequest) fsm.Event{
-		string(v1alpha1.TaskChecking): preCheck,
-		string(v1alpha1.TaskInit):     normalInit,
+		string(v1alpha1.TaskChecking):   preCheck,
+		string(v1alpha1.TaskInit):       normalInit,
+		string(v1alpha1.TaskCancelling): cancelTask,
 	}
+
+	// cancelledTasks are the tasks cancelled by cloud on this node
+	cancelledTasks sync.Map
 )
 
 func Register(name string, executor Executor) {
@@ -85,6 +100,51 @@ func GetExecutor(name string) (Executor, error) {
 	return executor, nil
 }
 
+func taskKey(taskType, taskID string) string {
+	return taskType + "/" + taskID
+}
+
+// IsCancelled returns whether the task is cancelled by cloud
+func IsCancelled(taskType, taskID string) bool {
+	_, ok := cancelledTasks.Load(taskKey(taskType, taskID))
+	return ok
+}
+
+// ForgetTask drops the cancellation record, the run, the buffered report and the audit record of the task
+func ForgetTask(taskType, taskID string) {
+	cancelledTasks.Delete(taskKey(taskType, taskID))
+	endRun(taskType, taskID)
+	if err := util.DeleteTaskReport(taskType, taskID); err != nil {
+		klog.Warningf("failed to remove report of task %s/%s: %v", taskType, taskID, err)
+	}
+	if err := util.DeleteTaskAudit(taskType, taskID); err != nil {
+		klog.Warningf("failed to remove audit record of task %s/%s: %v", taskType, taskID, err)
+	}
+}
+
+// taskCommand returns the bash command run for a task, it is run in the environment of the task.
+// The command is recorded in the audit record of the task.
+func taskCommand(taskReq types.NodeTaskRequest, command string) *exec.Cmd {
+	cmd := exec.Command("bash", "-c", command)
+	cmd.Env = taskEnv(taskReq)
+	auditCommand(taskReq, command)
+	return cmd
+}
+
+// taskEnv returns the environment of the commands run for a task, the custom metadata of the task
+// and the credential minted for it are passed in it
+func taskEnv(taskReq types.NodeTaskRequest) []string {
+	env := append(os.Environ(), util.TaskMetadataEnv(taskReq.Metadata)...)
+	return append(env, util.TaskCredentialEnv(taskReq.Credential)...)
+}
+
+// auditCommand records the command run for the task in its current state
+func auditCommand(taskReq types.NodeTaskRequest, command string) {
+	if err := util.RecordTaskCommand(taskReq.Type, taskReq.TaskID, taskReq.State, command); err != nil {
+		klog.Warningf("failed to record command of task %s/%s: %v", taskReq.Type, taskReq.TaskID, err)
+	}
+}
+
 func emptyInit(_ types.NodeTaskRequest) (event fsm.Event) {
 	return fsm.Event{
 		Type:   "Init",
@@ -109,7 +109,7 @@ function :copy:to:destination {
       elif [ "$CRD_NAME" == "objectsyncs" ]; then
           cp -v ${entry} ${CRD_OUTPUTS}/reliablesyncs/objectsync_${RELIABLESYNCS_VERSION}.yaml
           cp -v ${entry} ${HELM_CRDS_DIR}/objectsync_${RELIABLESYNCS_VERSION}.yaml
-      elif [ "$CRD_NAME" == "nodeupgradejobs" ] || [ "$CRD_NAME" == "imageprepulljobs" ]; then
+      elif [ "$CRD_NAME" == "nodeupgradejobs" ] || [ "$CRD_NAME" == "imageprepulljobs" ] || [ "$CRD_NAME" == "supportbundlejobs" ] || [ "$CRD_NAME" == "configupdatejobs" ] || [ "$CRD_NAME" == "noderestartjobs" ] || [ "$CRD_NAME" == "diagnosejobs" ] || [ "$CRD_NAME" == "backupjobs" ] || [ "$CRD_NAME" == "restorejobs" ] || [ "$CRD_NAME" == "osupgradejobs" ] || [ "$CRD_NAME" == "tasktypeconfigs" ]; then
           CRD_NAME=$(remove_suffix_s "$CRD_NAME")
           cp -v ${entry} ${CRD_OUTPUTS}/operations/operations_${OPERATIONS_VERSION}_${CRD_NAME}.yaml
           cp -v ${entry} ${HELM_CRDS_DIR}/operations_${OPERATIONS_VERSION}_${CRD_NAME}.yaml
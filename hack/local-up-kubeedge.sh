@@ -172,6 +172,14 @@ function create_operation_crd {
   echo "creating the operation crd..."
   kubectl apply -f ${KUBEEDGE_ROOT}/build/crds/operations/operations_v1alpha1_nodeupgradejob.yaml
   kubectl apply -f ${KUBEEDGE_ROOT}/build/crds/operations/operations_v1alpha1_imageprepulljob.yaml
+  kubectl apply -f ${KUBEEDGE_ROOT}/build/crds/operations/operations_v1alpha1_supportbundlejob.yaml
+  kubectl apply -f ${KUBEEDGE_ROOT}/build/crds/operations/operations_v1alpha1_configupdatejob.yaml
+  kubectl apply -f ${KUBEEDGE_ROOT}/build/crds/operations/operations_v1alpha1_noderestartjob.yaml
+  kubectl apply -f ${KUBEEDGE_ROOT}/build/crds/operations/operations_v1alpha1_diagnosejob.yaml
+  kubectl apply -f ${KUBEEDGE_ROOT}/build/crds/operations/operations_v1alpha1_backupjob.yaml
+  kubectl apply -f ${KUBEEDGE_ROOT}/build/crds/operations/operations_v1alpha1_restorejob.yaml
+  kubectl apply -f ${KUBEEDGE_ROOT}/build/crds/operations/operations_v1alpha1_osupgradejob.yaml
+  kubectl apply -f ${KUBEEDGE_ROOT}/build/crds/operations/operations_v1alpha1_tasktypeconfig.yaml
 }
 
 function create_serviceaccountaccess_crd {
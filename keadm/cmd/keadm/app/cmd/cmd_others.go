@@ -27,6 +27,7 @@ import (
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/debug"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/deprecated"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/edge"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/fleet"
 )
 
 var (
@@ -93,6 +94,7 @@ func NewKubeedgeCommand() *cobra.Command {
 	cmds.AddCommand(edge.NewEdgeRollback())
 
 	cmds.AddCommand(ctl.NewCtl())
+	cmds.AddCommand(fleet.NewFleet())
 
 	return cmds
 }
@@ -21,6 +21,7 @@ import (
 
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/ctl/get"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/ctl/restart"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/ctl/task"
 )
 
 var (
@@ -37,5 +38,6 @@ func NewCtl() *cobra.Command {
 
 	cmd.AddCommand(get.NewEdgeGet())
 	cmd.AddCommand(restart.NewEdgeRestart())
+	cmd.AddCommand(task.NewTask())
 	return cmd
 }
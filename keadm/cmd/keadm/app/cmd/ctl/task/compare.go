@@ -0,0 +1,303 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+var (
+	compareLongDescription = `
+"keadm ctl task compare" compares the node outcomes of two runs of a job, typically the same
+selection of nodes upgraded to different versions. For each node it reports whether the node
+was fixed, regressed or is still failing, whether its duration regressed, and the failure
+reasons which are new in the target run.
+`
+	compareExample = `
+keadm ctl task compare upgrade-v1.17.1 upgrade-v1.17.2 --kube-config /root/.kube/config
+`
+)
+
+// Changes of the outcome of a node between the base and the target run
+const (
+	ChangeFixed        = "Fixed"
+	ChangeRegressed    = "Regressed"
+	ChangeStillFailing = "StillFailing"
+	ChangeUnchanged    = "Unchanged"
+	ChangeIncomplete   = "Incomplete"
+	ChangeOnlyInBase   = "OnlyInBase"
+	ChangeOnlyInTarget = "OnlyInTarget"
+)
+
+// CompareOptions are the options of the task compare command
+type CompareOptions struct {
+	Kubeconfig string
+	Kind       string
+	Output     string
+	// RegressionRatio is the ratio of the target to the base duration from which the duration
+	// of a node is considered regressed
+	RegressionRatio float64
+}
+
+// NodeComparison is the change of the outcome of a node between two runs
+type NodeComparison struct {
+	NodeName    string    `json:"nodeName"`
+	Change      string    `json:"change"`
+	BaseState   api.State `json:"baseState,omitempty"`
+	TargetState api.State `json:"targetState,omitempty"`
+	// BaseDurationSeconds and TargetDurationSeconds are only known if the runs account costs
+	BaseDurationSeconds   int64 `json:"baseDurationSeconds,omitempty"`
+	TargetDurationSeconds int64 `json:"targetDurationSeconds,omitempty"`
+	DurationRegressed     bool  `json:"durationRegressed,omitempty"`
+	// NewFailureReason is the failure reason of the target run if the base run did not fail the same way
+	NewFailureReason string `json:"newFailureReason,omitempty"`
+}
+
+// Comparison compares the node outcomes of two runs
+type Comparison struct {
+	Kind          string           `json:"kind"`
+	Base          string           `json:"base"`
+	Target        string           `json:"target"`
+	BaseVersion   string           `json:"baseVersion,omitempty"`
+	TargetVersion string           `json:"targetVersion,omitempty"`
+	Summary       map[string]int   `json:"summary"`
+	Nodes         []NodeComparison `json:"nodes"`
+	Warnings      []string         `json:"warnings,omitempty"`
+}
+
+// jobRun is the part of a job run relevant to the comparison
+type jobRun struct {
+	name     string
+	version  string
+	state    api.State
+	selector interface{}
+	nodes    map[string]v1alpha1.TaskStatus
+}
+
+// NewTaskCompare returns the command comparing two runs of a job
+func NewTaskCompare() *cobra.Command {
+	opts := &CompareOptions{
+		Kubeconfig:      common.DefaultKubeConfig,
+		Kind:            "NodeUpgradeJob",
+		Output:          "table",
+		RegressionRatio: 1.5,
+	}
+	cmd := &cobra.Command{
+		Use:     "compare BASE TARGET",
+		Short:   "Compare the node outcomes of two NodeUpgradeJobs or ImagePrePullJobs",
+		Long:    compareLongDescription,
+		Example: compareExample,
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run(os.Stdout, args[0], args[1])
+		},
+	}
+	cmd.Flags().StringVar(&opts.Kubeconfig, common.FlagNameKubeConfig, opts.Kubeconfig,
+		"Use this key to set kube-config path, eg: $HOME/.kube/config")
+	cmd.Flags().StringVar(&opts.Kind, "kind", opts.Kind, "Kind of the jobs, NodeUpgradeJob or ImagePrePullJob")
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", opts.Output, "Output format, table or json")
+	cmd.Flags().Float64Var(&opts.RegressionRatio, "regression-ratio", opts.RegressionRatio,
+		"Ratio of the target to the base duration of a node from which its duration is reported as regressed")
+	return cmd
+}
+
+func (o *CompareOptions) run(out io.Writer, base, target string) error {
+	client, err := util.KubeEdgeClient(o.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	baseRun, err := getJobRun(ctx, client, o.Kind, base)
+	if err != nil {
+		return err
+	}
+	targetRun, err := getJobRun(ctx, client, o.Kind, target)
+	if err != nil {
+		return err
+	}
+	comparison := compareRuns(o.Kind, baseRun, targetRun, o.RegressionRatio)
+	if o.Output == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(comparison)
+	}
+	return printComparison(out, comparison)
+}
+
+func getJobRun(ctx context.Context, client versioned.Interface, kind, name string) (*jobRun, error) {
+	run := &jobRun{name: name, nodes: map[string]v1alpha1.TaskStatus{}}
+	switch kind {
+	case "NodeUpgradeJob":
+		upgrade, err := client.OperationsV1alpha1().NodeUpgradeJobs().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		run.version = upgrade.Spec.Version
+		run.state = upgrade.Status.State
+		run.selector = []interface{}{upgrade.Spec.NodeNames, upgrade.Spec.LabelSelector}
+		for _, status := range upgrade.Status.Status {
+			run.nodes[status.NodeName] = status
+		}
+	case "ImagePrePullJob":
+		prePull, err := client.OperationsV1alpha1().ImagePrePullJobs().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		template := prePull.Spec.ImagePrePullTemplate
+		run.state = prePull.Status.State
+		run.selector = []interface{}{template.NodeNames, template.LabelSelector}
+		for _, status := range prePull.Status.Status {
+			if status.TaskStatus != nil {
+				run.nodes[status.NodeName] = *status.TaskStatus
+			}
+		}
+	default:
+		return nil, fmt.Errorf("kind %q is not supported, NodeUpgradeJob or ImagePrePullJob is expected", kind)
+	}
+	return run, nil
+}
+
+// compareRuns compares the outcome of each node of the base run with the target run
+func compareRuns(kind string, base, target *jobRun, regressionRatio float64) *Comparison {
+	comparison := &Comparison{
+		Kind:          kind,
+		Base:          base.name,
+		Target:        target.name,
+		BaseVersion:   base.version,
+		TargetVersion: target.version,
+		Summary:       map[string]int{},
+	}
+	if !reflect.DeepEqual(base.selector, target.selector) {
+		comparison.Warnings = append(comparison.Warnings, "the jobs select nodes differently")
+	}
+	for _, run := range []*jobRun{base, target} {
+		if !fsm.TaskFinish(run.state) {
+			comparison.Warnings = append(comparison.Warnings, fmt.Sprintf("job %s is not completed", run.name))
+		}
+	}
+
+	names := make([]string, 0, len(base.nodes)+len(target.nodes))
+	for name := range base.nodes {
+		names = append(names, name)
+	}
+	for name := range target.nodes {
+		if _, ok := base.nodes[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		baseStatus, inBase := base.nodes[name]
+		targetStatus, inTarget := target.nodes[name]
+		node := NodeComparison{
+			NodeName:    name,
+			BaseState:   baseStatus.State,
+			TargetState: targetStatus.State,
+		}
+		if baseStatus.Cost != nil {
+			node.BaseDurationSeconds = baseStatus.Cost.DurationSeconds
+		}
+		if targetStatus.Cost != nil {
+			node.TargetDurationSeconds = targetStatus.Cost.DurationSeconds
+		}
+		switch {
+		case !inTarget:
+			node.Change = ChangeOnlyInBase
+		case !inBase:
+			node.Change = ChangeOnlyInTarget
+		case baseStatus.State == api.TaskFailed && targetStatus.State == api.TaskSuccessful:
+			node.Change = ChangeFixed
+		case baseStatus.State == api.TaskSuccessful && targetStatus.State == api.TaskFailed:
+			node.Change = ChangeRegressed
+		case baseStatus.State == api.TaskFailed && targetStatus.State == api.TaskFailed:
+			node.Change = ChangeStillFailing
+		case baseStatus.State == api.TaskSuccessful && targetStatus.State == api.TaskSuccessful:
+			node.Change = ChangeUnchanged
+		default:
+			node.Change = ChangeIncomplete
+		}
+		if inTarget && targetStatus.State == api.TaskFailed &&
+			(baseStatus.State != api.TaskFailed || baseStatus.Reason != targetStatus.Reason) {
+			node.NewFailureReason = targetStatus.Reason
+		}
+		if inBase && inTarget && node.BaseDurationSeconds > 0 &&
+			float64(node.TargetDurationSeconds) >= float64(node.BaseDurationSeconds)*regressionRatio {
+			node.DurationRegressed = true
+		}
+		comparison.Summary[node.Change]++
+		comparison.Nodes = append(comparison.Nodes, node)
+	}
+	return comparison
+}
+
+func printComparison(out io.Writer, comparison *Comparison) error {
+	fmt.Fprintf(out, "%s %s (%s) -> %s (%s)\n", comparison.Kind,
+		comparison.Base, comparison.BaseVersion, comparison.Target, comparison.TargetVersion)
+	for _, change := range []string{ChangeFixed, ChangeRegressed, ChangeStillFailing, ChangeUnchanged,
+		ChangeIncomplete, ChangeOnlyInBase, ChangeOnlyInTarget} {
+		if count := comparison.Summary[change]; count != 0 {
+			fmt.Fprintf(out, "%-14s %d\n", change+":", count)
+		}
+	}
+	fmt.Fprintln(out)
+
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NODE\tCHANGE\tBASE\tTARGET\tDURATION\tNEW FAILURE REASON")
+	for _, node := range comparison.Nodes {
+		duration := "-"
+		if node.BaseDurationSeconds != 0 || node.TargetDurationSeconds != 0 {
+			duration = fmt.Sprintf("%ds -> %ds", node.BaseDurationSeconds, node.TargetDurationSeconds)
+			if node.DurationRegressed {
+				duration += " (regressed)"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", node.NodeName, node.Change,
+			stateOrDash(node.BaseState), stateOrDash(node.TargetState), duration, node.NewFailureReason)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for _, warning := range comparison.Warnings {
+		fmt.Fprintf(out, "Warning: %s\n", warning)
+	}
+	return nil
+}
+
+func stateOrDash(state api.State) string {
+	if state == "" {
+		return "-"
+	}
+	return string(state)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/client/clientset/versioned/fake"
+)
+
+func nodeStatus(name string, state api.State, reason string, duration int64) v1alpha1.TaskStatus {
+	return v1alpha1.TaskStatus{
+		NodeName: name,
+		State:    state,
+		Reason:   reason,
+		Cost:     &v1alpha1.NodeCost{DurationSeconds: duration},
+	}
+}
+
+func TestCompareRuns(t *testing.T) {
+	base := &v1alpha1.NodeUpgradeJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "upgrade-v1.17.1"},
+		Spec:       v1alpha1.NodeUpgradeJobSpec{Version: "v1.17.1", NodeNames: []string{"a", "b", "c", "d", "e"}},
+		Status: v1alpha1.NodeUpgradeJobStatus{
+			State: api.TaskFailed,
+			Status: []v1alpha1.TaskStatus{
+				nodeStatus("a", api.TaskFailed, "disk full", 10),
+				nodeStatus("b", api.TaskSuccessful, "", 10),
+				nodeStatus("c", api.TaskFailed, "timeout", 10),
+				nodeStatus("d", api.TaskSuccessful, "", 10),
+				nodeStatus("e", api.TaskFailed, "timeout", 10),
+			},
+		},
+	}
+	target := &v1alpha1.NodeUpgradeJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "upgrade-v1.17.2"},
+		Spec:       v1alpha1.NodeUpgradeJobSpec{Version: "v1.17.2", NodeNames: []string{"a", "b", "c", "d", "e"}},
+		Status: v1alpha1.NodeUpgradeJobStatus{
+			State: api.TaskFailed,
+			Status: []v1alpha1.TaskStatus{
+				nodeStatus("a", api.TaskSuccessful, "", 12),
+				nodeStatus("b", api.TaskFailed, "checksum mismatch", 10),
+				nodeStatus("c", api.TaskFailed, "timeout", 10),
+				nodeStatus("d", api.TaskSuccessful, "", 30),
+				nodeStatus("e", api.TaskFailed, "disk full", 10),
+			},
+		},
+	}
+	client := fake.NewSimpleClientset(base, target)
+	baseRun, err := getJobRun(context.TODO(), client, "NodeUpgradeJob", base.Name)
+	assert.NoError(t, err)
+	targetRun, err := getJobRun(context.TODO(), client, "NodeUpgradeJob", target.Name)
+	assert.NoError(t, err)
+
+	comparison := compareRuns("NodeUpgradeJob", baseRun, targetRun, 1.5)
+	assert.Equal(t, "v1.17.1", comparison.BaseVersion)
+	assert.Equal(t, "v1.17.2", comparison.TargetVersion)
+	assert.Empty(t, comparison.Warnings)
+	assert.Equal(t, map[string]int{
+		ChangeFixed:        1,
+		ChangeRegressed:    1,
+		ChangeStillFailing: 2,
+		ChangeUnchanged:    1,
+	}, comparison.Summary)
+
+	nodes := map[string]NodeComparison{}
+	for _, node := range comparison.Nodes {
+		nodes[node.NodeName] = node
+	}
+	assert.Equal(t, ChangeFixed, nodes["a"].Change)
+	assert.Equal(t, "checksum mismatch", nodes["b"].NewFailureReason)
+	assert.Empty(t, nodes["c"].NewFailureReason)
+	assert.True(t, nodes["d"].DurationRegressed)
+	assert.False(t, nodes["a"].DurationRegressed)
+	assert.Equal(t, "disk full", nodes["e"].NewFailureReason)
+
+	_, err = getJobRun(context.TODO(), client, "NodeUpgradeJob", "missing")
+	assert.Error(t, err)
+}
+
+func TestCompareRunsWarnings(t *testing.T) {
+	base := &jobRun{name: "base", state: api.TaskSuccessful, selector: []string{"a"},
+		nodes: map[string]v1alpha1.TaskStatus{"a": {NodeName: "a", State: api.TaskSuccessful}}}
+	target := &jobRun{name: "target", state: api.TaskChecking, selector: []string{"b"},
+		nodes: map[string]v1alpha1.TaskStatus{"b": {NodeName: "b", State: api.TaskChecking}}}
+
+	comparison := compareRuns("NodeUpgradeJob", base, target, 1.5)
+	assert.Equal(t, []string{"the jobs select nodes differently", "job target is not completed"}, comparison.Warnings)
+	assert.Equal(t, map[string]int{ChangeOnlyInBase: 1, ChangeOnlyInTarget: 1}, comparison.Summary)
+}
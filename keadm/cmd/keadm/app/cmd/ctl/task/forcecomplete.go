@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	taskutil "github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+	"github.com/kubeedge/kubeedge/pkg/util/fsm"
+)
+
+var (
+	forceCompleteLongDescription = `
+"keadm ctl task force-complete" marks an unfinished node of a running job Successful or Failed,
+e.g. when the node is unreachable and wedges the job. The worker of the node is released and the
+job continues with the other nodes. A reason is required, it is recorded in the node status and
+in an event of the job.
+`
+	forceCompleteExample = `
+keadm ctl task force-complete upgrade-v1.17.2 --node edge-1 --state Failed --reason "node destroyed in the flood"
+`
+)
+
+// ForceCompleteOptions are the options of the task force-complete command
+type ForceCompleteOptions struct {
+	Kubeconfig string
+	Kind       string
+	NodeName   string
+	State      string
+	Reason     string
+}
+
+// NewTaskForceComplete returns the command forcing a node of a job to complete
+func NewTaskForceComplete() *cobra.Command {
+	opts := &ForceCompleteOptions{
+		Kubeconfig: common.DefaultKubeConfig,
+		Kind:       "NodeUpgradeJob",
+	}
+	cmd := &cobra.Command{
+		Use:     "force-complete JOB",
+		Short:   "Mark an unfinished node of a NodeUpgradeJob or ImagePrePullJob Successful or Failed",
+		Long:    forceCompleteLongDescription,
+		Example: forceCompleteExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := util.KubeEdgeClient(opts.Kubeconfig)
+			if err != nil {
+				return err
+			}
+			return opts.run(context.Background(), os.Stdout, client, args[0])
+		},
+	}
+	cmd.Flags().StringVar(&opts.Kubeconfig, common.FlagNameKubeConfig, opts.Kubeconfig,
+		"Use this key to set kube-config path, eg: $HOME/.kube/config")
+	cmd.Flags().StringVar(&opts.Kind, "kind", opts.Kind, "Kind of the job, NodeUpgradeJob or ImagePrePullJob")
+	cmd.Flags().StringVar(&opts.NodeName, "node", opts.NodeName, "Name of the node to complete")
+	cmd.Flags().StringVar(&opts.State, "state", opts.State, "Final state of the node, Successful or Failed")
+	cmd.Flags().StringVar(&opts.Reason, "reason", opts.Reason, "Justification of the completion, it is recorded in the node status")
+	return cmd
+}
+
+func (o *ForceCompleteOptions) run(ctx context.Context, out io.Writer, client versioned.Interface, name string) error {
+	force := taskutil.ForceCompletion{
+		NodeName: o.NodeName,
+		State:    api.State(o.State),
+		Reason:   o.Reason,
+	}
+	if err := force.Validate(); err != nil {
+		return err
+	}
+	run, err := getJobRun(ctx, client, o.Kind, name)
+	if err != nil {
+		return err
+	}
+	if fsm.TaskFinish(run.state) {
+		return fmt.Errorf("%s %s is already %s", o.Kind, name, run.state)
+	}
+	node, ok := run.nodes[force.NodeName]
+	if !ok {
+		return fmt.Errorf("node %s is not part of %s %s", force.NodeName, o.Kind, name)
+	}
+	if fsm.TaskFinish(node.State) {
+		return fmt.Errorf("node %s already finished %s %s as %s", force.NodeName, o.Kind, name, node.State)
+	}
+
+	value, err := json.Marshal(force)
+	if err != nil {
+		return err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{taskutil.TaskForceCompleteAnnotationKey: string(value)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	jobs := client.OperationsV1alpha1()
+	switch o.Kind {
+	case "NodeUpgradeJob":
+		_, err = jobs.NodeUpgradeJobs().Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	case "ImagePrePullJob":
+		_, err = jobs.ImagePrePullJobs().Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to request the completion of node %s: %v", force.NodeName, err)
+	}
+	fmt.Fprintf(out, "node %s of %s %s is forced to %s\n", force.NodeName, o.Kind, name, force.State)
+	return nil
+}
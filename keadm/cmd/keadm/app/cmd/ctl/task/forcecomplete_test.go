@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	taskutil "github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/client/clientset/versioned/fake"
+)
+
+func TestForceComplete(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(&v1alpha1.NodeUpgradeJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "upgrade"},
+		Status: v1alpha1.NodeUpgradeJobStatus{
+			State: api.NodeUpgrading,
+			Status: []v1alpha1.TaskStatus{
+				{NodeName: "edge-1", State: api.NodeUpgrading},
+				{NodeName: "edge-2", State: api.TaskSuccessful},
+			},
+		},
+	})
+	opts := &ForceCompleteOptions{Kind: "NodeUpgradeJob", NodeName: "edge-1", State: "Failed"}
+	out := &bytes.Buffer{}
+
+	assert.Error(t, opts.run(ctx, out, client, "upgrade"), "a reason is required")
+	opts.Reason = "unreachable"
+	opts.NodeName = "edge-2"
+	assert.Error(t, opts.run(ctx, out, client, "upgrade"), "a finished node can not be forced")
+	opts.NodeName = "edge-3"
+	assert.Error(t, opts.run(ctx, out, client, "upgrade"), "the node is not part of the job")
+
+	opts.NodeName = "edge-1"
+	assert.NoError(t, opts.run(ctx, out, client, "upgrade"))
+	upgrade, err := client.OperationsV1alpha1().NodeUpgradeJobs().Get(ctx, "upgrade", metav1.GetOptions{})
+	assert.NoError(t, err)
+	force, err := taskutil.ParseForceCompletion(upgrade.Annotations[taskutil.TaskForceCompleteAnnotationKey])
+	assert.NoError(t, err)
+	assert.Equal(t, taskutil.ForceCompletion{NodeName: "edge-1", State: api.TaskFailed, Reason: "unreachable"}, *force)
+}
@@ -0,0 +1,569 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	taskutil "github.com/kubeedge/kubeedge/cloud/pkg/taskmanager/util"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+	cloudcorev1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/componentconfig/cloudcore/v1alpha1"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+)
+
+var (
+	planLongDescription = `
+"keadm ctl task plan" selects the nodes of a NodeUpgradeJob or ImagePrePullJob against the live
+cluster the way TaskManager does, and prints the execution plan without creating the job:
+the stages, the canary nodes, the batches of nodes run concurrently, the failure budget, the
+maintenance windows, the workloads running on the nodes and the nodes excluded with the reasons.
+The nodes are filtered by the node filter webhook of the cloudcore configuration if it has one.
+TaskManager orders the nodes by their connection quality at dispatch time, so the nodes of the
+batches may differ, their sizes do not.
+`
+	planExample = `
+keadm ctl task plan -f upgrade.yaml --kube-config /root/.kube/config
+`
+)
+
+const (
+	// defaultTimeoutSeconds is the timeout of a node in a stage when the job does not set one
+	defaultTimeoutSeconds = 300
+	// cloudcoreConfigMap is the ConfigMap the configuration of cloudcore is read from
+	cloudcoreConfigMap = "cloudcore"
+)
+
+// PlanOptions are the options of the task plan command
+type PlanOptions struct {
+	Kubeconfig string
+	File       string
+	Output     string
+}
+
+// Plan is the execution plan of a job
+type Plan struct {
+	Kind           string   `json:"kind"`
+	Name           string   `json:"name"`
+	Stages         []string `json:"stages"`
+	Concurrency    int32    `json:"concurrency"`
+	TimeoutSeconds uint32   `json:"timeoutSeconds"`
+	// StageTimeouts are the timeouts of the stages which do not use TimeoutSeconds
+	StageTimeouts   map[string]uint32 `json:"stageTimeouts,omitempty"`
+	FailureTolerate string            `json:"failureTolerate"`
+	// MaxFailedNodes is how many nodes may fail before the job is failed
+	MaxFailedNodes float64 `json:"maxFailedNodes"`
+	// CanaryNodes are the nodes of a NodeUpgradeJob upgraded first, they are the first batches
+	CanaryNodes []string `json:"canaryNodes,omitempty"`
+	// Batches are the nodes run concurrently in each stage
+	Batches  [][]string              `json:"batches"`
+	Excluded []v1alpha1.ExcludedNode `json:"excluded,omitempty"`
+	// MaintenanceWindows are the daily windows a NodeUpgradeJob is applied in
+	MaintenanceWindows []v1alpha1.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+	// NextMaintenanceWindow is when the next maintenance window opens, it is empty while one is open
+	NextMaintenanceWindow string `json:"nextMaintenanceWindow,omitempty"`
+	// AffectedWorkloads are the workloads that have running pods on the nodes of a NodeUpgradeJob
+	AffectedWorkloads []v1alpha1.WorkloadReference `json:"affectedWorkloads,omitempty"`
+	Warnings          []string                     `json:"warnings,omitempty"`
+	// WorstCaseDuration is the duration of the job if every node of every batch times out
+	WorstCaseDuration string `json:"worstCaseDuration"`
+}
+
+// job is the part of NodeUpgradeJob and ImagePrePullJob relevant to planning
+type job struct {
+	kind   string
+	name   string
+	labels map[string]string
+	// taskType is the type of the tasks of the job, it is sent to the node filter webhook
+	taskType       string
+	nodeNames      []string
+	labelSelector  *metav1.LabelSelector
+	nodeGroups     []string
+	concurrency    int32
+	timeoutSeconds *uint32
+	// stageTimeouts are the timeouts of the stages of a NodeUpgradeJob by the state of the nodes in the stage
+	stageTimeouts   map[api.State]uint32
+	failureTolerate *intstr.IntOrString
+	stages          []api.State
+	// version is the version a NodeUpgradeJob upgrades to
+	version string
+	// preset is the strategy preset of a NodeUpgradeJob, it is expanded by the admission webhook
+	preset string
+	// maxUnavailable sizes the batches of a NodeUpgradeJob from the selected nodes instead of concurrency
+	maxUnavailable *intstr.IntOrString
+	canary         *v1alpha1.CanarySpec
+	ordering       v1alpha1.NodeOrdering
+	orderingSeed   *int64
+	apply          *v1alpha1.ApplySpec
+}
+
+// NewTaskPlan returns the command printing the execution plan of a job
+func NewTaskPlan() *cobra.Command {
+	opts := &PlanOptions{
+		Kubeconfig: common.DefaultKubeConfig,
+		Output:     "table",
+	}
+	cmd := &cobra.Command{
+		Use:     "plan",
+		Short:   "Print the execution plan of a NodeUpgradeJob or ImagePrePullJob without creating it",
+		Long:    planLongDescription,
+		Example: planExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run(os.Stdout)
+		},
+	}
+	cmd.Flags().StringVar(&opts.Kubeconfig, common.FlagNameKubeConfig, opts.Kubeconfig,
+		"Use this key to set kube-config path, eg: $HOME/.kube/config")
+	cmd.Flags().StringVarP(&opts.File, "filename", "f", opts.File, "Path of the job manifest")
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", opts.Output, "Output format, table or json")
+	return cmd
+}
+
+func (o *PlanOptions) run(out io.Writer) error {
+	if o.File == "" {
+		return fmt.Errorf("job manifest is required")
+	}
+	data, err := os.ReadFile(o.File)
+	if err != nil {
+		return err
+	}
+	j, err := parseJob(data)
+	if err != nil {
+		return err
+	}
+	client, err := util.KubeClient(o.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	crdClient, err := util.KubeEdgeClient(o.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	plan, err := buildPlan(context.Background(), client, crdClient, j)
+	if err != nil {
+		return err
+	}
+	if o.Output == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(plan)
+	}
+	return printPlan(out, plan)
+}
+
+func parseJob(data []byte) (*job, error) {
+	typeMeta := metav1.TypeMeta{}
+	if err := yaml.Unmarshal(data, &typeMeta); err != nil {
+		return nil, err
+	}
+	switch typeMeta.Kind {
+	case "NodeUpgradeJob":
+		upgrade := v1alpha1.NodeUpgradeJob{}
+		if err := yaml.UnmarshalStrict(data, &upgrade); err != nil {
+			return nil, err
+		}
+		if len(upgrade.Spec.VersionMappings) != 0 {
+			return nil, fmt.Errorf("NodeUpgradeJob %s with versionMappings is not supported, plan a job per mapping instead", upgrade.Name)
+		}
+		if upgrade.Spec.Version == "" {
+			return nil, fmt.Errorf("version of NodeUpgradeJob %s is required", upgrade.Name)
+		}
+		concurrency, maxUnavailable := taskutil.UpgradeConcurrency(upgrade.Spec)
+		var stages []api.State
+		for _, state := range api.UpgradeStages.States() {
+			// the nodes are verified from cloud only if the job has a post check
+			if state == api.VerifyingState && upgrade.Spec.PostCheck == nil {
+				continue
+			}
+			stages = append(stages, state)
+		}
+		return &job{
+			kind:            typeMeta.Kind,
+			name:            upgrade.Name,
+			labels:          upgrade.Labels,
+			taskType:        taskutil.TaskUpgrade,
+			nodeNames:       upgrade.Spec.NodeNames,
+			labelSelector:   upgrade.Spec.LabelSelector,
+			nodeGroups:      upgrade.Spec.NodeGroups,
+			concurrency:     concurrency,
+			timeoutSeconds:  upgrade.Spec.TimeoutSeconds,
+			stageTimeouts:   taskutil.UpgradeStageTimeouts(upgrade.Spec.Timeouts),
+			failureTolerate: upgrade.Spec.FailureTolerate,
+			stages:          stages,
+			version:         upgrade.Spec.Version,
+			preset:          string(upgrade.Spec.Preset),
+			maxUnavailable:  maxUnavailable,
+			canary:          upgrade.Spec.Canary,
+			ordering:        upgrade.Spec.Ordering,
+			orderingSeed:    upgrade.Spec.OrderingSeed,
+			apply:           upgrade.Spec.Apply,
+		}, nil
+	case "ImagePrePullJob":
+		prePull := v1alpha1.ImagePrePullJob{}
+		if err := yaml.UnmarshalStrict(data, &prePull); err != nil {
+			return nil, err
+		}
+		template := prePull.Spec.ImagePrePullTemplate
+		if len(template.Images) == 0 {
+			return nil, fmt.Errorf("images of ImagePrePullJob %s are required", prePull.Name)
+		}
+		return &job{
+			kind:            typeMeta.Kind,
+			name:            prePull.Name,
+			labels:          prePull.Labels,
+			taskType:        taskutil.TaskPrePull,
+			nodeNames:       template.NodeNames,
+			labelSelector:   template.LabelSelector,
+			concurrency:     template.Concurrency,
+			timeoutSeconds:  template.TimeoutSeconds,
+			failureTolerate: template.FailureTolerate,
+			stages:          api.PrePullStages.States(),
+		}, nil
+	default:
+		return nil, fmt.Errorf("kind %q is not supported, NodeUpgradeJob or ImagePrePullJob is expected", typeMeta.Kind)
+	}
+}
+
+// buildPlan selects the nodes of the job with the node selection of TaskManager and splits them into batches
+func buildPlan(ctx context.Context, client kubernetes.Interface, crdClient crdClientset.Interface, j *job) (*Plan, error) {
+	if len(j.nodeNames) == 0 && j.labelSelector == nil && len(j.nodeGroups) == 0 {
+		return nil, fmt.Errorf("nodeNames, labelSelector or nodeGroups of %s %s is required", j.kind, j.name)
+	}
+	plan := &Plan{
+		Kind:            j.kind,
+		Name:            j.name,
+		TimeoutSeconds:  defaultTimeoutSeconds,
+		FailureTolerate: taskutil.DefaultFailureTolerate.String(),
+	}
+	for _, stage := range j.stages {
+		plan.Stages = append(plan.Stages, string(stage))
+	}
+	if j.timeoutSeconds != nil && *j.timeoutSeconds != 0 {
+		plan.TimeoutSeconds = *j.timeoutSeconds
+	}
+	tolerate := j.failureTolerate
+	if tolerate != nil {
+		if _, err := taskutil.MaxFailedNodes(tolerate, 0); err != nil {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("%v, %s is used", err, plan.FailureTolerate))
+			tolerate = nil
+		} else {
+			plan.FailureTolerate = tolerate.String()
+		}
+	}
+	if j.preset != "" {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("preset %q is expanded when the job is created, the defaults are used for the fields it would set", j.preset))
+	}
+	if len(j.nodeNames) != 0 && j.labelSelector != nil {
+		plan.Warnings = append(plan.Warnings, "both nodeNames and labelSelector are set, labelSelector is ignored")
+	}
+
+	nodes, err := selectNodes(ctx, client, crdClient, j, plan)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]v1alpha1.TaskStatus, len(nodes))
+	for i, node := range nodes {
+		if task, ok := node.Annotations[taskutil.TaskInProgressAnnotationKey]; ok && task != j.name {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("node %s is being operated on by task %s", node.Name, task))
+		}
+		statuses[i] = v1alpha1.TaskStatus{NodeName: node.Name}
+	}
+	orderNodes(statuses, j, plan)
+
+	canary, err := canaryNodes(statuses, nodes, j, plan)
+	if err != nil {
+		return nil, err
+	}
+	var canaryNames, otherNames []string
+	for _, status := range statuses {
+		if canary[status.NodeName] {
+			canaryNames = append(canaryNames, status.NodeName)
+		} else {
+			otherNames = append(otherNames, status.NodeName)
+		}
+	}
+
+	size, err := taskutil.BatchSize(j.concurrency, j.maxUnavailable, len(statuses))
+	if err != nil {
+		return nil, err
+	}
+	plan.Concurrency = int32(size)
+	// the failure tolerance is validated above
+	plan.MaxFailedNodes, _ = taskutil.MaxFailedNodes(tolerate, len(statuses))
+	// the other nodes are dispatched once the canary nodes are upgraded, so they are batched apart
+	plan.Batches = append(taskutil.Batches(canaryNames, size), taskutil.Batches(otherNames, size)...)
+
+	var stagesTimeout time.Duration
+	for _, stage := range plan.Stages {
+		timeout := plan.TimeoutSeconds
+		if stageTimeout, ok := j.stageTimeouts[api.State(stage)]; ok {
+			if plan.StageTimeouts == nil {
+				plan.StageTimeouts = map[string]uint32{}
+			}
+			plan.StageTimeouts[stage] = stageTimeout
+			timeout = stageTimeout
+		}
+		stagesTimeout += time.Duration(timeout) * time.Second
+	}
+	worstCase := time.Duration(len(plan.Batches)) * stagesTimeout
+	plan.WorstCaseDuration = worstCase.String()
+
+	planApply(j, plan, time.Now())
+	if j.kind == "NodeUpgradeJob" && len(nodes) != 0 {
+		workloads, err := affectedWorkloads(ctx, client, nodes)
+		if err != nil {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("failed to list the workloads running on the nodes: %v", err))
+		} else {
+			plan.AffectedWorkloads = workloads
+		}
+	}
+	return plan, nil
+}
+
+// selectNodes selects the nodes of the job and excludes the nodes TaskManager does not operate on,
+// the nodes are sorted by name
+func selectNodes(ctx context.Context, client kubernetes.Interface, crdClient crdClientset.Interface, j *job, plan *Plan) ([]corev1.Node, error) {
+	lister := &nodeLister{ctx: ctx, client: client, crdClient: crdClient}
+	selected, excluded, err := taskutil.SelectNodes(lister, j.nodeNames, j.labelSelector, j.nodeGroups)
+	if err != nil {
+		if len(excluded) == 0 {
+			return nil, err
+		}
+		// TaskManager does not select any node while a named node is missing
+		plan.Excluded = excluded
+		plan.Warnings = append(plan.Warnings, "named nodes are missing, TaskManager will not run the job on any node")
+		return nil, nil
+	}
+	sort.Slice(selected, func(i, k int) bool { return selected[i].Name < selected[k].Name })
+
+	candidates, excluded := taskutil.CandidateNodes(selected)
+	plan.Excluded = append(plan.Excluded, excluded...)
+
+	webhook, err := nodeFilterWebhook(ctx, client)
+	if err != nil {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("the nodes are not filtered by the node filter webhook: %v", err))
+	}
+	message := taskutil.TaskMessage{Type: j.taskType, Name: j.name, Labels: j.labels}
+	candidates, excluded = taskutil.FilterNodesByWebhook(webhook, message, candidates)
+	plan.Excluded = append(plan.Excluded, excluded...)
+	if j.kind != "NodeUpgradeJob" {
+		return candidates, nil
+	}
+
+	var nodes []corev1.Node
+	for _, node := range candidates {
+		if reason, msg := taskutil.UpgradeExclusion(node, j.version); reason != "" {
+			plan.Excluded = append(plan.Excluded, v1alpha1.ExcludedNode{NodeName: node.Name, Reason: reason, Message: msg})
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// nodeFilterWebhook returns the node filter webhook of the cloudcore configuration, nil if it has none
+func nodeFilterWebhook(ctx context.Context, client kubernetes.Interface) (*cloudcorev1alpha1.TaskManagerNodeFilterWebhook, error) {
+	cm, err := client.CoreV1().ConfigMaps(constants.SystemNamespace).Get(ctx, cloudcoreConfigMap, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configmap %s: %v", cloudcoreConfigMap, err)
+	}
+	config := &cloudcorev1alpha1.CloudCoreConfig{}
+	if err := yaml.Unmarshal([]byte(cm.Data["cloudcore.yaml"]), config); err != nil {
+		return nil, fmt.Errorf("failed to parse the configuration of cloudcore: %v", err)
+	}
+	if config.Modules == nil || config.Modules.TaskManager == nil {
+		return nil, nil
+	}
+	return config.Modules.TaskManager.NodeFilterWebhook, nil
+}
+
+// orderNodes orders the nodes the way TaskManager does when it starts the job
+func orderNodes(statuses []v1alpha1.TaskStatus, j *job, plan *Plan) {
+	switch j.ordering {
+	case v1alpha1.NodeOrderingRandom:
+		if j.orderingSeed == nil {
+			plan.Warnings = append(plan.Warnings, "the nodes are shuffled with a seed picked when the job is created, set orderingSeed to plan their order")
+			return
+		}
+		taskutil.ShuffleNodes(statuses, *j.orderingSeed)
+	case v1alpha1.NodeOrderingReadiness:
+		plan.Warnings = append(plan.Warnings, "the nodes are ordered by their readiness when the job starts, the nodes of the batches may differ")
+	}
+}
+
+// canaryNodes selects the canary nodes of the job and moves them to the front
+func canaryNodes(statuses []v1alpha1.TaskStatus, nodes []corev1.Node, j *job, plan *Plan) (map[string]bool, error) {
+	if j.canary == nil {
+		return nil, nil
+	}
+	names, err := taskutil.CanaryNodes(j.canary, nodes)
+	if err != nil {
+		return nil, err
+	}
+	canary := make(map[string]bool, len(names))
+	for _, name := range names {
+		canary[name] = true
+	}
+	taskutil.CanaryFirst(statuses, canary)
+	plan.CanaryNodes = names
+	if len(names) == 0 {
+		plan.Warnings = append(plan.Warnings, "no canary node is selected, the job waits for approval before upgrading any node")
+	} else if !j.canary.Approve {
+		plan.Warnings = append(plan.Warnings, "the other nodes wait for the approval of the job once the canary nodes are upgraded")
+	}
+	return canary, nil
+}
+
+// planApply records when a staged NodeUpgradeJob is applied
+func planApply(j *job, plan *Plan, now time.Time) {
+	if j.apply == nil {
+		return
+	}
+	if j.apply.Hold {
+		plan.Warnings = append(plan.Warnings, "the upgrade is held, it is applied once apply.hold is set to false")
+	}
+	if len(j.apply.MaintenanceWindows) == 0 {
+		return
+	}
+	plan.MaintenanceWindows = j.apply.MaintenanceWindows
+	if in, next := taskutil.InMaintenanceWindow(j.apply.MaintenanceWindows, now); !in {
+		plan.NextMaintenanceWindow = next.Format(time.RFC3339)
+	}
+}
+
+// affectedWorkloads returns the workloads that have running pods on the nodes
+func affectedWorkloads(ctx context.Context, client kubernetes.Interface, nodes []corev1.Node) ([]v1alpha1.WorkloadReference, error) {
+	list, err := client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pods := make([]*corev1.Pod, len(list.Items))
+	for i := range list.Items {
+		pods[i] = &list.Items[i]
+	}
+	getReplicaSet := func(namespace, name string) (*appsv1.ReplicaSet, error) {
+		return client.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	}
+	return taskutil.AffectedWorkloads(pods, getReplicaSet, nodes), nil
+}
+
+// nodeLister looks up the nodes and the node groups of the job in the apiserver
+type nodeLister struct {
+	ctx       context.Context
+	client    kubernetes.Interface
+	crdClient crdClientset.Interface
+}
+
+func (l *nodeLister) GetNode(name string) (*corev1.Node, error) {
+	return l.client.CoreV1().Nodes().Get(l.ctx, name, metav1.GetOptions{})
+}
+
+func (l *nodeLister) ListNodes(selector labels.Selector) ([]*corev1.Node, error) {
+	list, err := l.client.CoreV1().Nodes().List(l.ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*corev1.Node, len(list.Items))
+	for i := range list.Items {
+		nodes[i] = &list.Items[i]
+	}
+	return nodes, nil
+}
+
+func (l *nodeLister) GetNodeGroup(name string) (*appsv1alpha1.NodeGroup, error) {
+	return l.crdClient.AppsV1alpha1().NodeGroups().Get(l.ctx, name, metav1.GetOptions{})
+}
+
+func printPlan(out io.Writer, plan *Plan) error {
+	fmt.Fprintf(out, "%s %s\n", plan.Kind, plan.Name)
+	fmt.Fprintf(out, "Stages:           %s\n", strings.Join(plan.Stages, " -> "))
+	fmt.Fprintf(out, "Concurrency:      %d\n", plan.Concurrency)
+	fmt.Fprintf(out, "Node timeout:     %ds\n", plan.TimeoutSeconds)
+	if len(plan.StageTimeouts) != 0 {
+		timeouts := make([]string, 0, len(plan.StageTimeouts))
+		for _, stage := range plan.Stages {
+			if timeout, ok := plan.StageTimeouts[stage]; ok {
+				timeouts = append(timeouts, fmt.Sprintf("%s %ds", stage, timeout))
+			}
+		}
+		fmt.Fprintf(out, "Stage timeouts:   %s\n", strings.Join(timeouts, ", "))
+	}
+	fmt.Fprintf(out, "Failure budget:   %.1f nodes (%s of the selected nodes)\n", plan.MaxFailedNodes, plan.FailureTolerate)
+	if len(plan.CanaryNodes) != 0 {
+		fmt.Fprintf(out, "Canary nodes:     %s\n", strings.Join(plan.CanaryNodes, ","))
+	}
+	if len(plan.MaintenanceWindows) != 0 {
+		windows := make([]string, 0, len(plan.MaintenanceWindows))
+		for _, window := range plan.MaintenanceWindows {
+			windows = append(windows, fmt.Sprintf("%s UTC %dm", window.Start, window.DurationMinutes))
+		}
+		fmt.Fprintf(out, "Maintenance:      %s\n", strings.Join(windows, ", "))
+		if plan.NextMaintenanceWindow != "" {
+			fmt.Fprintf(out, "Next window:      %s\n", plan.NextMaintenanceWindow)
+		}
+	}
+	fmt.Fprintf(out, "Worst case:       %s\n", plan.WorstCaseDuration)
+	fmt.Fprintln(out)
+
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "BATCH\tNODES")
+	for i, batch := range plan.Batches {
+		fmt.Fprintf(w, "%d\t%s\n", i+1, strings.Join(batch, ","))
+	}
+	if len(plan.Excluded) != 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "EXCLUDED\tREASON\tMESSAGE")
+		for _, node := range plan.Excluded {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", node.NodeName, node.Reason, node.Message)
+		}
+	}
+	if len(plan.AffectedWorkloads) != 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "WORKLOAD\tNODES")
+		for _, workload := range plan.AffectedWorkloads {
+			fmt.Fprintf(w, "%s/%s/%s\t%s\n", workload.Kind, workload.Namespace, workload.Name, strings.Join(workload.NodeNames, ","))
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for _, warning := range plan.Warnings {
+		fmt.Fprintf(out, "Warning: %s\n", warning)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	appsv1alpha1 "github.com/kubeedge/kubeedge/pkg/apis/apps/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	crdfake "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned/fake"
+)
+
+func edgeNode(name, version string, ready corev1.ConditionStatus) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{constants.EdgeNodeRoleKey: constants.EdgeNodeRoleValue, "region": "a"},
+		},
+		Status: corev1.NodeStatus{
+			NodeInfo:   corev1.NodeSystemInfo{KubeletVersion: version},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+		},
+	}
+}
+
+func TestBuildPlan(t *testing.T) {
+	assert := assert.New(t)
+	cloudNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "cloud-1", Labels: map[string]string{"region": "a"}}}
+	otherRegionNode := edgeNode("edge-6", "v1.26.10-kubeedge-v1.16.0", corev1.ConditionTrue)
+	otherRegionNode.Labels["region"] = "b"
+	client := fake.NewSimpleClientset(
+		edgeNode("edge-1", "v1.26.10-kubeedge-v1.16.0", corev1.ConditionTrue),
+		edgeNode("edge-2", "v1.26.10-kubeedge-v1.16.0", corev1.ConditionTrue),
+		edgeNode("edge-3", "v1.26.10-kubeedge-v1.16.0", corev1.ConditionTrue),
+		edgeNode("edge-4", "v1.26.10-kubeedge-v1.17.0", corev1.ConditionTrue),
+		edgeNode("edge-5", "v1.26.10-kubeedge-v1.16.0", corev1.ConditionFalse),
+		cloudNode,
+		otherRegionNode,
+	)
+	crdClient := crdfake.NewSimpleClientset(&appsv1alpha1.NodeGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "group-b"},
+		Spec:       appsv1alpha1.NodeGroupSpec{Nodes: []string{"edge-6"}},
+	})
+
+	j, err := parseJob([]byte(`
+apiVersion: operations.kubeedge.io/v1alpha1
+kind: NodeUpgradeJob
+metadata:
+  name: upgrade-v1.17
+spec:
+  version: v1.17.0
+  concurrency: 2
+  failureTolerate: "0.5"
+  labelSelector:
+    matchLabels:
+      region: a
+`))
+	assert.NoError(err)
+
+	plan, err := buildPlan(context.Background(), client, crdClient, j)
+	assert.NoError(err)
+	assert.Equal([]string{"Checking", "BackingUp", "Upgrading"}, plan.Stages)
+	assert.Equal([][]string{{"edge-1", "edge-2"}, {"edge-3"}}, plan.Batches)
+	assert.Equal([]v1alpha1.ExcludedNode{
+		{NodeName: "cloud-1", Reason: v1alpha1.ExclusionNotEdgeNode},
+		{NodeName: "edge-5", Reason: v1alpha1.ExclusionNotReady},
+		{NodeName: "edge-4", Reason: v1alpha1.ExclusionUpToDate, Message: "the node is already on version v1.26.10-kubeedge-v1.17.0"},
+	}, plan.Excluded)
+	assert.Equal(1.5, plan.MaxFailedNodes)
+	assert.Equal("30m0s", plan.WorstCaseDuration)
+
+	// the canary nodes and the members of the node groups are planned the way TaskManager runs them
+	j.nodeGroups = []string{"group-b"}
+	j.canary = &v1alpha1.CanarySpec{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}}}
+	node, err := client.CoreV1().Nodes().Get(context.Background(), "edge-3", metav1.GetOptions{})
+	assert.NoError(err)
+	node.Labels["canary"] = "true"
+	_, err = client.CoreV1().Nodes().Update(context.Background(), node, metav1.UpdateOptions{})
+	assert.NoError(err)
+	plan, err = buildPlan(context.Background(), client, crdClient, j)
+	assert.NoError(err)
+	assert.Equal([]string{"edge-3"}, plan.CanaryNodes)
+	assert.Equal([][]string{{"edge-3"}, {"edge-1", "edge-2"}, {"edge-6"}}, plan.Batches)
+
+	j.nodeGroups, j.canary = nil, nil
+	j.nodeNames = []string{"edge-1", "edge-9"}
+	plan, err = buildPlan(context.Background(), client, crdClient, j)
+	assert.NoError(err)
+	assert.Empty(plan.Batches)
+	assert.Len(plan.Excluded, 1)
+	assert.Equal("edge-9", plan.Excluded[0].NodeName)
+	assert.Equal(v1alpha1.ExclusionNotFound, plan.Excluded[0].Reason)
+}
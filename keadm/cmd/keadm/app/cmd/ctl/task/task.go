@@ -0,0 +1,37 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package task
+
+import "github.com/spf13/cobra"
+
+var (
+	taskShortDescription = `Commands operating on NodeUpgradeJobs and ImagePrePullJobs`
+)
+
+// NewTask returns KubeEdge task command.
+func NewTask() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "task",
+		Short: taskShortDescription,
+		Long:  taskShortDescription,
+	}
+
+	cmd.AddCommand(NewTaskPlan())
+	cmd.AddCommand(NewTaskCompare())
+	cmd.AddCommand(NewTaskForceComplete())
+	return cmd
+}
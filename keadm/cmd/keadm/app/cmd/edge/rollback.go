@@ -19,6 +19,7 @@ package edge
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
@@ -75,9 +76,12 @@ func rollbackEdgeCore(ro *RollbackOptions) error {
 		Type:   "RollBack",
 		Action: api.ActionSuccess,
 	}
+	util.AuditTaskCommands(ro.TaskType, ro.TaskName, string(api.RollingBackState))
+	start := time.Now()
 	defer func() {
+		event.Elapsed = time.Since(start)
 		// report upgrade result to cloudhub
-		if err = util.ReportTaskResult(configure, ro.TaskType, ro.TaskName, *event); err != nil {
+		if err = util.ReportTaskResult(configure, ro.TaskType, ro.TaskName, string(api.RollingBackState), *event); err != nil {
 			klog.Warningf("failed to report upgrade result to cloud: %v", err)
 		}
 	}()
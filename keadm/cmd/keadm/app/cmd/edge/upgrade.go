@@ -17,10 +17,13 @@ limitations under the License.
 package edge
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
@@ -31,6 +34,7 @@ import (
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
 	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
 	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
 	"github.com/kubeedge/kubeedge/pkg/util/fsm"
 )
 
@@ -83,24 +87,28 @@ func (up *UpgradeOptions) Upgrade() error {
 	}
 
 	upgrade := Upgrade{
-		UpgradeID:      up.UpgradeID,
-		HistoryID:      up.HistoryID,
-		FromVersion:    up.FromVersion,
-		ToVersion:      up.ToVersion,
-		TaskType:       up.TaskType,
-		Image:          up.Image,
-		DisableBackup:  up.DisableBackup,
-		ConfigFilePath: up.Config,
-		EdgeCoreConfig: configure,
+		UpgradeID:        up.UpgradeID,
+		HistoryID:        up.HistoryID,
+		FromVersion:      up.FromVersion,
+		ToVersion:        up.ToVersion,
+		TaskType:         up.TaskType,
+		Image:            up.Image,
+		DisableBackup:    up.DisableBackup,
+		ConfigFilePath:   up.Config,
+		VerificationFile: up.VerificationFile,
+		EdgeCoreConfig:   configure,
 	}
 
 	event := &fsm.Event{
 		Type:   "Upgrade",
 		Action: api.ActionSuccess,
 	}
+	util.AuditTaskCommands(upgrade.TaskType, upgrade.UpgradeID, string(api.UpgradingState))
+	start := time.Now()
 	defer func() {
+		event.Elapsed = time.Since(start)
 		// report upgrade result to cloudhub
-		if err = util.ReportTaskResult(configure, upgrade.TaskType, upgrade.UpgradeID, *event); err != nil {
+		if err = util.ReportTaskResult(configure, upgrade.TaskType, upgrade.UpgradeID, string(api.UpgradingState), *event); err != nil {
 			klog.Errorf("failed to report upgrade result to cloud: %v", err)
 		}
 		// cleanup idempotency record
@@ -151,6 +159,17 @@ func (up *UpgradeOptions) Upgrade() error {
 		return fmt.Errorf("upgrade process failed: %v", err)
 	}
 
+	err = upgrade.Verify()
+	if err != nil {
+		event.Type = "Verify"
+		event.Action = api.ActionFailure
+		event.Msg = err.Error()
+		if rbErr := upgrade.Rollback(); rbErr != nil {
+			event.Msg = fmt.Sprintf("%s, rollback error: %v", err.Error(), rbErr)
+		}
+		return fmt.Errorf("upgrade verification failed: %v", err)
+	}
+
 	return nil
 }
 
@@ -261,6 +280,34 @@ func (up *Upgrade) Process() error {
 	return nil
 }
 
+// Verify runs the verification probes against the upgraded edge node
+func (up *Upgrade) Verify() error {
+	if up.VerificationFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(up.VerificationFile)
+	if err != nil {
+		return fmt.Errorf("failed to read verification file %s: %v", up.VerificationFile, err)
+	}
+	var probes []v1alpha1.VerificationProbe
+	if err = json.Unmarshal(data, &probes); err != nil {
+		return fmt.Errorf("failed to unmarshal verification probes: %v", err)
+	}
+
+	var failures []string
+	for _, probe := range probes {
+		klog.Infof("run verification probe %s", probe.Name)
+		output, err := util.RunVerificationProbe(probe)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("probe %s failed: %v, output: %s", probe.Name, err, output))
+		}
+	}
+	if len(failures) != 0 {
+		return fmt.Errorf("verification failed: %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
 func (up *Upgrade) Rollback() error {
 	return rollback(up.FromVersion, up.EdgeCoreConfig.DataBase.DataSource, up.ConfigFilePath)
 }
@@ -323,18 +370,21 @@ type UpgradeOptions struct {
 	Image         string
 	DisableBackup bool
 	TaskType      string
+	// VerificationFile is the file of probes to run after upgrade
+	VerificationFile string
 }
 
 type Upgrade struct {
-	UpgradeID      string
-	HistoryID      string
-	FromVersion    string
-	ToVersion      string
-	Image          string
-	DisableBackup  bool
-	ConfigFilePath string
-	TaskType       string
-	EdgeCoreConfig *v1alpha2.EdgeCoreConfig
+	UpgradeID        string
+	HistoryID        string
+	FromVersion      string
+	ToVersion        string
+	Image            string
+	DisableBackup    bool
+	ConfigFilePath   string
+	TaskType         string
+	VerificationFile string
+	EdgeCoreConfig   *v1alpha2.EdgeCoreConfig
 
 	Status string
 	Reason string
@@ -365,4 +415,7 @@ func AddUpgradeFlags(cmd *cobra.Command, upgradeOptions *UpgradeOptions) {
 
 	cmd.Flags().BoolVar(&upgradeOptions.DisableBackup, "disable-backup", upgradeOptions.DisableBackup,
 		"Use this key to specify the backup enable for upgrade.")
+
+	cmd.Flags().StringVar(&upgradeOptions.VerificationFile, "verificationFile", upgradeOptions.VerificationFile,
+		"Use this key to specify the file of probes to verify the edge node after upgrade.")
 }
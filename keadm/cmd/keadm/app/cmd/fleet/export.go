@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleet
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/federation"
+)
+
+var (
+	exportLongDescription = `
+"keadm fleet export" prints a task of a cluster as a template which can be propagated to
+member clusters with standard multicluster tooling such as Karmada or a GitOps pipeline.
+The template carries no status and no metadata specific to the source cluster. The tasks
+created from it in member clusters keep its name, so "keadm fleet report" collects their status.
+`
+	exportExample = `
+keadm fleet export --type upgrade --name upgrade-v1.17 --kube-config /root/.kube/config --karmada-clusters region-a,region-b
+- karmada-clusters adds a Karmada ClusterPropagationPolicy which propagates the template to the member clusters
+`
+)
+
+// ExportOptions are the options of the fleet export command
+type ExportOptions struct {
+	Kubeconfig      string
+	TaskType        string
+	TaskName        string
+	KarmadaClusters []string
+}
+
+// NewFleetExport returns the command exporting a task as a template for member clusters
+func NewFleetExport() *cobra.Command {
+	opts := &ExportOptions{
+		Kubeconfig: common.DefaultKubeConfig,
+		TaskType:   federation.TaskTypeUpgrade,
+	}
+	cmd := &cobra.Command{
+		Use:     "export",
+		Short:   "Export a task as a template to be propagated to member clusters",
+		Long:    exportLongDescription,
+		Example: exportExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run(os.Stdout, os.Stderr)
+		},
+	}
+	cmd.Flags().StringVar(&opts.Kubeconfig, common.FlagNameKubeConfig, opts.Kubeconfig,
+		"Use this key to set kube-config path, eg: $HOME/.kube/config")
+	cmd.Flags().StringVar(&opts.TaskType, "type", opts.TaskType, "Type of the task, upgrade or prepull")
+	cmd.Flags().StringVar(&opts.TaskName, "name", opts.TaskName, "Name of the task")
+	cmd.Flags().StringSliceVar(&opts.KarmadaClusters, "karmada-clusters", opts.KarmadaClusters,
+		"Member clusters the template is propagated to by a Karmada ClusterPropagationPolicy")
+	return cmd
+}
+
+func (o *ExportOptions) run(out, errOut io.Writer) error {
+	if o.TaskName == "" {
+		return fmt.Errorf("task name is required")
+	}
+	client, err := util.KubeEdgeClient(o.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	template, warnings, err := federation.ExportTemplate(context.Background(), client, o.TaskType, o.TaskName)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		fmt.Fprintf(errOut, "Warning: %s\n", warning)
+	}
+
+	docs := []interface{}{template}
+	if len(o.KarmadaClusters) != 0 {
+		policy, err := federation.KarmadaPropagationPolicy(template, o.KarmadaClusters)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, policy)
+	}
+	for i, doc := range docs {
+		data, err := yaml.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprintln(out, "---")
+		}
+		if _, err = out.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
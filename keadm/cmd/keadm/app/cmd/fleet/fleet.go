@@ -0,0 +1,38 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleet
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var (
+	fleetShortDescription = `Commands operating on tasks across multiple KubeEdge clusters`
+)
+
+// NewFleet returns the fleet command which aggregates tasks of multiple cloudcores
+func NewFleet() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fleet",
+		Short: fleetShortDescription,
+		Long:  fleetShortDescription,
+	}
+
+	cmd.AddCommand(NewFleetReport())
+	cmd.AddCommand(NewFleetExport())
+	return cmd
+}
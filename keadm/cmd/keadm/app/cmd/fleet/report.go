@@ -0,0 +1,180 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/federation"
+)
+
+var (
+	reportLongDescription = `
+"keadm fleet report" reads the status of a task from the cloudcores of multiple clusters,
+e.g. one per region, and prints a consolidated fleet report of the rollout.
+The report can also be stored as the ConfigMap fleet-<type>-<name> in the kubeedge namespace
+of a central cluster, so that it is available to central operations.
+`
+	reportExample = `
+keadm fleet report --type upgrade --name upgrade-v1.17 --cluster region-a=/root/.kube/region-a --cluster region-b=/root/.kube/region-b
+- cluster is the name and the kubeconfig path of a member cluster, it can be repeated
+- save-to is the kubeconfig path of the central cluster where the report is stored
+`
+)
+
+// ReportOptions are the options of the fleet report command
+type ReportOptions struct {
+	Clusters []string
+	TaskType string
+	TaskName string
+	Output   string
+	SaveTo   string
+}
+
+// NewFleetReport returns the command printing the fleet report of a task
+func NewFleetReport() *cobra.Command {
+	opts := &ReportOptions{
+		TaskType: federation.TaskTypeUpgrade,
+		Output:   "table",
+	}
+	cmd := &cobra.Command{
+		Use:     "report",
+		Short:   "Print the consolidated status of a task across multiple clusters",
+		Long:    reportLongDescription,
+		Example: reportExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run(os.Stdout)
+		},
+	}
+	cmd.Flags().StringArrayVar(&opts.Clusters, "cluster", opts.Clusters,
+		"Member cluster in the form <name>=<kubeconfig path>, it can be repeated")
+	cmd.Flags().StringVar(&opts.TaskType, "type", opts.TaskType, "Type of the task, upgrade or prepull")
+	cmd.Flags().StringVar(&opts.TaskName, "name", opts.TaskName, "Name of the task")
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", opts.Output, "Output format, table or json")
+	cmd.Flags().StringVar(&opts.SaveTo, "save-to", opts.SaveTo,
+		"Kubeconfig path of the central cluster where the report is stored as a ConfigMap")
+	return cmd
+}
+
+func (o *ReportOptions) run(out io.Writer) error {
+	if o.TaskName == "" {
+		return fmt.Errorf("task name is required")
+	}
+	clusters, err := parseClusters(o.Clusters)
+	if err != nil {
+		return err
+	}
+	report, err := federation.Collect(context.Background(), clusters, o.TaskType, o.TaskName)
+	if err != nil {
+		return err
+	}
+	if o.SaveTo != "" {
+		if err = saveReport(o.SaveTo, report); err != nil {
+			return fmt.Errorf("failed to save fleet report: %v", err)
+		}
+	}
+	if o.Output == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	return printReport(out, report)
+}
+
+func parseClusters(values []string) ([]federation.Cluster, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("at least one cluster is required")
+	}
+	clusters := make([]federation.Cluster, 0, len(values))
+	for _, value := range values {
+		name, kubeConfig, ok := strings.Cut(value, "=")
+		if !ok || name == "" || kubeConfig == "" {
+			return nil, fmt.Errorf("invalid cluster %q, expected <name>=<kubeconfig path>", value)
+		}
+		client, err := util.KubeEdgeClient(kubeConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client of cluster %s: %v", name, err)
+		}
+		clusters = append(clusters, federation.Cluster{Name: name, Client: client})
+	}
+	return clusters, nil
+}
+
+func printReport(out io.Writer, report *federation.FleetReport) error {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "CLUSTER\tSTATE\tNODES\tFAILED NODES\tERROR")
+	for _, cluster := range report.Clusters {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", cluster.Cluster, cluster.State, formatNodes(cluster.Nodes),
+			strings.Join(cluster.FailedNodes, ","), cluster.Error)
+	}
+	fmt.Fprintf(w, "TOTAL\t\t%s\t\t\n", formatNodes(report.Nodes))
+	return w.Flush()
+}
+
+// formatNodes formats the node counts by state as State=count pairs sorted by state
+func formatNodes(nodes map[api.State]int) string {
+	states := make([]string, 0, len(nodes))
+	for state := range nodes {
+		states = append(states, string(state))
+	}
+	sort.Strings(states)
+	pairs := make([]string, 0, len(states))
+	for _, state := range states {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", state, nodes[api.State(state)]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// saveReport stores the report as a ConfigMap in the kubeedge namespace of the central cluster
+func saveReport(kubeConfig string, report *federation.FleetReport) error {
+	client, err := util.KubeClient(kubeConfig)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("fleet-%s-%s", report.TaskType, report.TaskName),
+			Namespace: constants.SystemNamespace,
+		},
+		Data: map[string]string{"report.json": string(data)},
+	}
+	configMaps := client.CoreV1().ConfigMaps(constants.SystemNamespace)
+	_, err = configMaps.Create(context.Background(), cm, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		_, err = configMaps.Update(context.Background(), cm, metav1.UpdateOptions{})
+	}
+	return err
+}
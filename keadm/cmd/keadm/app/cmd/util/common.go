@@ -649,13 +649,23 @@ func downloadServiceFile(componentType types.ComponentType, version semver.Versi
 	return nil
 }
 
-func ReportTaskResult(config *v1alpha2.EdgeCoreConfig, taskType, taskID string, event fsm.Event) error {
+func ReportTaskResult(config *v1alpha2.EdgeCoreConfig, taskType, taskID, state string, event fsm.Event) error {
 	resp := &commontypes.NodeTaskResponse{
 		NodeName: config.Modules.Edged.HostnameOverride,
 		Event:    event.Type,
 		Action:   event.Action,
 		Time:     time.Now().Format(apis.ISO8601UTC),
 		Reason:   event.Msg,
+		// cloud keeps the environment of the terminal results for failure analysis
+		Environment: CollectEnvironment(config),
+		// the custom metadata of the task is passed to keadm by edgecore
+		Metadata:       TaskMetadataFromEnv(),
+		CommandDigest:  TaskAuditDigest(taskType, taskID),
+		ElapsedSeconds: int64(event.Elapsed.Seconds()),
+	}
+	// buffer the result first, cloud will ask for it again if it does not receive the report
+	if err := SaveTaskReport(taskType, taskID, state, *resp); err != nil {
+		klog.Warningf("failed to save task report: %v", err)
 	}
 	edgeHub := config.Modules.EdgeHub
 	var caCrt []byte
@@ -34,10 +34,12 @@ import (
 
 // Constants used by installers
 const (
-	KubeEdgePath        = "/etc/kubeedge/"
-	KubeEdgeBackupPath  = "/etc/kubeedge/backup/"
-	KubeEdgeUpgradePath = "/etc/kubeedge/upgrade/"
-	KubeEdgeUsrBinPath  = "/usr/local/bin"
+	KubeEdgePath           = "/etc/kubeedge/"
+	KubeEdgeBackupPath     = "/etc/kubeedge/backup/"
+	KubeEdgeUpgradePath    = "/etc/kubeedge/upgrade/"
+	KubeEdgeTaskReportPath = "/etc/kubeedge/task-report/"
+	KubeEdgeTaskAuditPath  = "/etc/kubeedge/task-audit/"
+	KubeEdgeUsrBinPath     = "/usr/local/bin"
 
 	KubeEdgeLogPath = "/var/log/kubeedge/"
 
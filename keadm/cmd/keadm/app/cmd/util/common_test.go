@@ -21,11 +21,17 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
+	"time"
+
 	"github.com/blang/semver"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/version"
 
+	commontypes "github.com/kubeedge/kubeedge/common/types"
 	types "github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
 )
 
@@ -410,3 +416,61 @@ func TestGetHelmVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskMetadataEnv(t *testing.T) {
+	env := TaskMetadataEnv(map[string]string{"ticket": "OPS-1234", "change_id": "42", "not-a-name": "skipped"})
+	expected := []string{TaskMetadataEnvPrefix + "change_id=42", TaskMetadataEnvPrefix + "ticket=OPS-1234"}
+	if !reflect.DeepEqual(env, expected) {
+		t.Fatalf("expected environment %v, got %v", expected, env)
+	}
+
+	for _, kv := range env {
+		key, value, _ := strings.Cut(kv, "=")
+		t.Setenv(key, value)
+	}
+	metadata := TaskMetadataFromEnv()
+	if metadata["ticket"] != "OPS-1234" || metadata["change_id"] != "42" || len(metadata) != 2 {
+		t.Errorf("unexpected metadata %v read from the environment", metadata)
+	}
+}
+
+func TestTaskCredentialEnv(t *testing.T) {
+	if env := TaskCredentialEnv(nil); len(env) != 0 {
+		t.Errorf("expected no environment without a credential, got %v", env)
+	}
+	expiration := metav1.NewTime(time.Date(2024, 5, 1, 8, 10, 0, 0, time.UTC))
+	env := TaskCredentialEnv(&commontypes.TaskCredential{Token: "token", ExpirationTimestamp: expiration})
+	expected := []string{TaskTokenEnv + "=token", TaskTokenExpirationEnv + "=2024-05-01T08:10:00Z"}
+	if !reflect.DeepEqual(env, expected) {
+		t.Errorf("expected environment %v, got %v", expected, env)
+	}
+}
+
+func TestDeleteTaskReport(t *testing.T) {
+	taskReportDir = t.TempDir()
+	defer func() { taskReportDir = KubeEdgeTaskReportPath }()
+
+	resp := commontypes.NodeTaskResponse{NodeName: "edge-1", Action: "Success"}
+	for _, id := range []string{"job-1", "job-2"} {
+		if err := SaveTaskReport("restart", id, "Restarting", resp); err != nil {
+			t.Fatalf("failed to save task report: %v", err)
+		}
+	}
+	if err := DeleteTaskReport("restart", "job-1"); err != nil {
+		t.Fatalf("failed to delete task report: %v", err)
+	}
+	if err := DeleteTaskReport("restart", "job-1"); err != nil {
+		t.Errorf("expected deleting a missing task report to succeed, got %v", err)
+	}
+	tasks, err := ListTaskReports()
+	if err != nil {
+		t.Fatalf("failed to list task reports: %v", err)
+	}
+	expected := []commontypes.NodeTaskKey{{Type: "restart", TaskID: "job-2"}}
+	if !reflect.DeepEqual(tasks, expected) {
+		t.Errorf("expected task reports %v, got %v", expected, tasks)
+	}
+	if _, err = LoadTaskReport("restart", "job-1"); !os.IsNotExist(err) {
+		t.Errorf("expected the deleted task report to be gone, got %v", err)
+	}
+}
@@ -33,10 +33,12 @@ import (
 
 // Constants used by installers
 const (
-	KubeEdgePath        = "C:\\etc\\kubeedge\\"
-	KubeEdgeBackupPath  = "C:\\etc\\kubeedge\\backup\\"
-	KubeEdgeUpgradePath = "C:\\etc\\kubeedge\\upgrade\\"
-	KubeEdgeUsrBinPath  = "C:\\usr\\local\\bin"
+	KubeEdgePath           = "C:\\etc\\kubeedge\\"
+	KubeEdgeBackupPath     = "C:\\etc\\kubeedge\\backup\\"
+	KubeEdgeUpgradePath    = "C:\\etc\\kubeedge\\upgrade\\"
+	KubeEdgeTaskReportPath = "C:\\etc\\kubeedge\\task-report\\"
+	KubeEdgeTaskAuditPath  = "C:\\etc\\kubeedge\\task-audit\\"
+	KubeEdgeUsrBinPath     = "C:\\usr\\local\\bin"
 
 	KubeEdgeLogPath = "C:\\var\\log\\kubeedge\\"
 
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/kubelet/cri/remote"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// osReleaseFile identifies the OS of the edge node, it is replaced in tests
+var osReleaseFile = "/etc/os-release"
+
+// CollectEnvironment takes a snapshot of the execution environment of the edge node,
+// the parts which cannot be collected are left empty.
+func CollectEnvironment(config *v1alpha2.EdgeCoreConfig) *v1alpha1.NodeEnvironment {
+	env := &v1alpha1.NodeEnvironment{
+		OS:            osName(),
+		KernelVersion: kernelVersion(),
+		Architecture:  runtime.GOARCH,
+	}
+	if config == nil || config.Modules == nil || config.Modules.Edged == nil {
+		return env
+	}
+	edged := config.Modules.Edged
+	if edged.TailoredKubeletConfig != nil {
+		env.ContainerRuntimeVersion = containerRuntimeVersion(edged.TailoredKubeletConfig.ContainerRuntimeEndpoint)
+	}
+	if edged.RootDirectory != "" {
+		free, err := diskFree(edged.RootDirectory)
+		if err != nil {
+			klog.Warningf("failed to get free disk space of %s: %v", edged.RootDirectory, err)
+		} else {
+			env.DiskFree = free
+		}
+	}
+	return env
+}
+
+// osName returns the pretty name in os-release, or the name of the OS family if it is unknown
+func osName() string {
+	file, err := os.Open(osReleaseFile)
+	if err != nil {
+		return runtime.GOOS
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "PRETTY_NAME="); ok {
+			return strings.Trim(value, `"`)
+		}
+	}
+	return runtime.GOOS
+}
+
+func containerRuntimeVersion(endpoint string) string {
+	if endpoint == "" {
+		return ""
+	}
+	runtimeService, err := remote.NewRemoteRuntimeService(endpoint, 3*time.Second, oteltrace.NewNoopTracerProvider())
+	if err != nil {
+		klog.Warningf("failed to connect container runtime: %v", err)
+		return ""
+	}
+	version, err := runtimeService.Version(context.Background(), "")
+	if err != nil {
+		klog.Warningf("failed to get container runtime version: %v", err)
+		return ""
+	}
+	return version.RuntimeName + " " + version.RuntimeVersion
+}
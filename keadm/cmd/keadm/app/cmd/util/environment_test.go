@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/componentconfig/edgecore/v1alpha2"
+)
+
+func TestOSName(t *testing.T) {
+	file := osReleaseFile
+	defer func() { osReleaseFile = file }()
+	dir := t.TempDir()
+
+	for _, tc := range []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{name: "pretty name", content: "NAME=\"Ubuntu\"\nPRETTY_NAME=\"Ubuntu 22.04.3 LTS\"\nVERSION_ID=\"22.04\"\n", expected: "Ubuntu 22.04.3 LTS"},
+		{name: "unquoted pretty name", content: "PRETTY_NAME=Alpine\n", expected: "Alpine"},
+		{name: "no pretty name", content: "NAME=\"Ubuntu\"\n", expected: runtime.GOOS},
+		{name: "no os-release", expected: runtime.GOOS},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			osReleaseFile = filepath.Join(dir, "missing")
+			if tc.content != "" {
+				osReleaseFile = filepath.Join(dir, "os-release")
+				if err := os.WriteFile(osReleaseFile, []byte(tc.content), 0600); err != nil {
+					t.Fatalf("failed to write os-release: %v", err)
+				}
+			}
+			if name := osName(); name != tc.expected {
+				t.Errorf("expected OS %q, got %q", tc.expected, name)
+			}
+		})
+	}
+}
+
+func TestCollectEnvironment(t *testing.T) {
+	env := CollectEnvironment(nil)
+	if env.OS == "" || env.Architecture != runtime.GOARCH {
+		t.Errorf("expected the OS and the architecture without the config of edgecore, got %+v", env)
+	}
+	if env.DiskFree != nil || env.ContainerRuntimeVersion != "" {
+		t.Errorf("expected the parts read from the config of edgecore to be left empty, got %+v", env)
+	}
+
+	config := v1alpha2.NewDefaultEdgeCoreConfig()
+	config.Modules.Edged.TailoredKubeletConfig.ContainerRuntimeEndpoint = ""
+	config.Modules.Edged.RootDirectory = t.TempDir()
+	env = CollectEnvironment(config)
+	if env.DiskFree == nil || env.DiskFree.Sign() <= 0 {
+		t.Errorf("expected the free disk space of the root directory, got %v", env.DiskFree)
+	}
+	if env.ContainerRuntimeVersion != "" {
+		t.Errorf("expected no container runtime version without its endpoint, got %q", env.ContainerRuntimeVersion)
+	}
+
+	// the disk space which cannot be read is left empty
+	config.Modules.Edged.RootDirectory = filepath.Join(t.TempDir(), "missing")
+	if env = CollectEnvironment(config); env.DiskFree != nil {
+		t.Errorf("expected no free disk space of a missing root directory, got %v", env.DiskFree)
+	}
+}
@@ -0,0 +1,43 @@
+//go:build windows
+
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func kernelVersion() string {
+	major, minor, build := windows.RtlGetNtVersionNumbers()
+	return fmt.Sprintf("%d.%d.%d", major, minor, build)
+}
+
+func diskFree(path string) (*resource.Quantity, error) {
+	pathPtr, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	var free uint64
+	if err = windows.GetDiskFreeSpaceEx(pathPtr, &free, nil, nil); err != nil {
+		return nil, err
+	}
+	return resource.NewQuantity(int64(free), resource.BinarySI), nil
+}
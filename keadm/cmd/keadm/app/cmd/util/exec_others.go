@@ -20,6 +20,7 @@ type Command struct {
 }
 
 func NewCommand(command string) *Command {
+	auditCommand(command)
 	return &Command{
 		Cmd: exec.Command("bash", "-c", command),
 	}
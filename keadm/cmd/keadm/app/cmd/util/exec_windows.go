@@ -22,6 +22,7 @@ type Command struct {
 }
 
 func NewCommand(command string) *Command {
+	auditCommand(command)
 	return &Command{
 		Cmd: exec.Command("powershell", "-c", command),
 	}
@@ -41,6 +41,11 @@ var mqttLabel = map[string]string{"io.kubeedge.edgecore/mqtt": image.EdgeMQTT}
 type ContainerRuntime interface {
 	PullImages(images []string) error
 	PullImage(image string, authConfig *runtimeapi.AuthConfig, sandboxConfig *runtimeapi.PodSandboxConfig) error
+	// PullImageWithSize pulls the image like PullImage, it returns the size of the pulled image
+	// or 0 if the image is already present.
+	PullImageWithSize(image string, authConfig *runtimeapi.AuthConfig, sandboxConfig *runtimeapi.PodSandboxConfig) (uint64, error)
+	// RemoveImage removes the image, it is not an error if the image is not present
+	RemoveImage(image string) error
 	CopyResources(edgeImage string, files map[string]string) error
 	RunMQTT(mqttImage string) error
 	RemoveMQTT() error
@@ -98,18 +103,33 @@ func (runtime *CRIRuntime) PullImages(images []string) error {
 }
 
 func (runtime *CRIRuntime) PullImage(image string, authConfig *runtimeapi.AuthConfig, sandboxConfig *runtimeapi.PodSandboxConfig) error {
+	_, err := runtime.PullImageWithSize(image, authConfig, sandboxConfig)
+	return err
+}
+
+func (runtime *CRIRuntime) PullImageWithSize(image string, authConfig *runtimeapi.AuthConfig, sandboxConfig *runtimeapi.PodSandboxConfig) (uint64, error) {
 	image = convertCRIImage(image)
 	imageSpec := &runtimeapi.ImageSpec{Image: image}
 	status, err := runtime.ImageManagerService.ImageStatus(runtime.ctx, imageSpec, true)
 	if err != nil {
-		return err
+		return 0, err
 	}
-	if status == nil || status.Image == nil {
-		if _, err := runtime.ImageManagerService.PullImage(runtime.ctx, imageSpec, authConfig, sandboxConfig); err != nil {
-			return err
-		}
+	if status != nil && status.Image != nil {
+		return 0, nil
 	}
-	return nil
+	if _, err := runtime.ImageManagerService.PullImage(runtime.ctx, imageSpec, authConfig, sandboxConfig); err != nil {
+		return 0, err
+	}
+	status, err = runtime.ImageManagerService.ImageStatus(runtime.ctx, imageSpec, false)
+	if err != nil || status == nil || status.Image == nil {
+		// the image is pulled, its size is only used for accounting
+		return 0, nil
+	}
+	return status.Image.Size_, nil
+}
+
+func (runtime *CRIRuntime) RemoveImage(image string) error {
+	return runtime.ImageManagerService.RemoveImage(runtime.ctx, &runtimeapi.ImageSpec{Image: convertCRIImage(image)})
 }
 
 // CopyResources copies binary and configuration file from the image to the host.
@@ -10,6 +10,7 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 
 	"github.com/kubeedge/kubeedge/common/constants"
+	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
 )
 
 func kubeConfig(kubeconfigPath string) (conf *rest.Config, err error) {
@@ -33,6 +34,15 @@ func KubeClient(kubeConfigPath string) (*kubernetes.Clientset, error) {
 	return kubernetes.NewForConfig(kubeConfig)
 }
 
+// KubeEdgeClient returns the client of KubeEdge resources from config
+func KubeEdgeClient(kubeConfigPath string) (*crdClientset.Clientset, error) {
+	kubeConfig, err := kubeConfig(kubeConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("get kube config failed with error: %s", err)
+	}
+	return crdClientset.NewForConfig(kubeConfig)
+}
+
 func (co *Common) CleanNameSpace(ns, kubeConfigPath string) error {
 	cli, err := KubeClient(kubeConfigPath)
 	if err != nil {
@@ -0,0 +1,180 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+const (
+	defaultProbeTimeoutSeconds   = 1
+	defaultProbePeriodSeconds    = 10
+	defaultProbeFailureThreshold = 3
+	maxProbeOutputBytes          = 1024
+)
+
+// probeSleep waits for the initial delay and the period of the probes, it is replaced in tests
+var probeSleep = time.Sleep
+
+// RunVerificationProbe runs the probe until it succeeds or reaches its failure threshold,
+// the output of the last attempt is returned.
+func RunVerificationProbe(probe v1alpha1.VerificationProbe) (string, error) {
+	timeout := time.Duration(defaultInt32(probe.TimeoutSeconds, defaultProbeTimeoutSeconds)) * time.Second
+	period := time.Duration(defaultInt32(probe.PeriodSeconds, defaultProbePeriodSeconds)) * time.Second
+	threshold := defaultInt32(probe.FailureThreshold, defaultProbeFailureThreshold)
+
+	probeSleep(time.Duration(probe.InitialDelaySeconds) * time.Second)
+	var output string
+	var err error
+	for i := int32(0); i < threshold; i++ {
+		if i > 0 {
+			probeSleep(period)
+		}
+		output, err = runProbeOnce(probe, timeout)
+		if err == nil {
+			return output, nil
+		}
+	}
+	return output, err
+}
+
+func defaultInt32(value, defaultValue int32) int32 {
+	if value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+func runProbeOnce(probe v1alpha1.VerificationProbe, timeout time.Duration) (string, error) {
+	switch {
+	case probe.Exec != nil:
+		return runExecProbe(probe.Exec, timeout)
+	case probe.HTTPGet != nil:
+		return runHTTPProbe(probe.HTTPGet, timeout)
+	case probe.TCPSocket != nil:
+		return runTCPProbe(probe.TCPSocket, timeout)
+	}
+	return "", fmt.Errorf("probe %s has no action specified", probe.Name)
+}
+
+func runExecProbe(action *v1.ExecAction, timeout time.Duration) (string, error) {
+	if len(action.Command) == 0 {
+		return "", fmt.Errorf("exec probe command is empty")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	// #nosec G204
+	out, err := exec.CommandContext(ctx, action.Command[0], action.Command[1:]...).CombinedOutput()
+	return truncateProbeOutput(string(out)), err
+}
+
+func runHTTPProbe(action *v1.HTTPGetAction, timeout time.Duration) (string, error) {
+	port, err := probePort(action.Port)
+	if err != nil {
+		return "", err
+	}
+	host := action.Host
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	scheme := "http"
+	if action.Scheme == v1.URISchemeHTTPS {
+		scheme = "https"
+	}
+	u := url.URL{Scheme: scheme, Host: net.JoinHostPort(host, port), Path: action.Path}
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	for _, header := range action.HTTPHeaders {
+		if header.Name == "Host" {
+			req.Host = header.Value
+			continue
+		}
+		req.Header.Add(header.Name, header.Value)
+	}
+	client := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// the same as kubelet http probes, certificates of local services are not verified
+			// #nosec G402
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxProbeOutputBytes))
+	if err != nil {
+		return "", err
+	}
+	output := fmt.Sprintf("%s: %s", resp.Status, body)
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return output, fmt.Errorf("http probe %s returned status code %d", u.String(), resp.StatusCode)
+	}
+	return output, nil
+}
+
+func runTCPProbe(action *v1.TCPSocketAction, timeout time.Duration) (string, error) {
+	port, err := probePort(action.Port)
+	if err != nil {
+		return "", err
+	}
+	host := action.Host
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, port), timeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	return fmt.Sprintf("connected to %s", conn.RemoteAddr()), nil
+}
+
+func probePort(port intstr.IntOrString) (string, error) {
+	if port.Type != intstr.Int {
+		return "", fmt.Errorf("named port %s is not supported by edge verification probes", port.StrVal)
+	}
+	if port.IntVal <= 0 || port.IntVal > 65535 {
+		return "", fmt.Errorf("invalid port %d", port.IntVal)
+	}
+	return strconv.Itoa(int(port.IntVal)), nil
+}
+
+func truncateProbeOutput(output string) string {
+	if len(output) > maxProbeOutputBytes {
+		return output[:maxProbeOutputBytes]
+	}
+	return output
+}
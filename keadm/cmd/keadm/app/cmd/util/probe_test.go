@@ -0,0 +1,197 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+)
+
+// serverAddress returns the host and the port of the test server
+func serverAddress(t *testing.T, server *httptest.Server) (string, intstr.IntOrString) {
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("failed to parse server url: %v", err)
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		t.Fatalf("failed to parse server port: %v", err)
+	}
+	return u.Hostname(), intstr.FromInt(port)
+}
+
+func TestExecProbe(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		command []string
+		output  string
+		failed  bool
+	}{
+		{name: "success", command: []string{"echo", "ready"}, output: "ready\n"},
+		{name: "failure", command: []string{"sh", "-c", "echo not ready; exit 1"}, output: "not ready\n", failed: true},
+		{name: "timeout", command: []string{"sleep", "5"}, failed: true},
+		{name: "empty command", failed: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := runExecProbe(&v1.ExecAction{Command: tc.command}, 200*time.Millisecond)
+			if (err != nil) != tc.failed {
+				t.Fatalf("expected the probe to fail: %t, got %v", tc.failed, err)
+			}
+			if output != tc.output {
+				t.Errorf("expected output %q, got %q", tc.output, output)
+			}
+		})
+	}
+
+	output, _ := runExecProbe(&v1.ExecAction{Command: []string{"sh", "-c", "head -c 2048 /dev/zero"}}, time.Second)
+	if len(output) != maxProbeOutputBytes {
+		t.Errorf("expected the output to be truncated to %d bytes, got %d", maxProbeOutputBytes, len(output))
+	}
+}
+
+func TestHTTPProbe(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/healthz":
+			if r.Host != "edgecore.local" || r.Header.Get("X-Probe") != "verification" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte("ok"))
+		case "/slow":
+			time.Sleep(time.Second)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	tlsServer := httptest.NewTLSServer(handler)
+	defer tlsServer.Close()
+	headers := []v1.HTTPHeader{{Name: "Host", Value: "edgecore.local"}, {Name: "X-Probe", Value: "verification"}}
+
+	for _, tc := range []struct {
+		name    string
+		server  *httptest.Server
+		path    string
+		scheme  v1.URIScheme
+		headers []v1.HTTPHeader
+		failed  bool
+	}{
+		{name: "success", server: server, path: "/healthz", headers: headers},
+		{name: "https without verifying the certificate", server: tlsServer, path: "/healthz", scheme: v1.URISchemeHTTPS, headers: headers},
+		{name: "missing headers", server: server, path: "/healthz", failed: true},
+		{name: "unavailable", server: server, path: "/unavailable", failed: true},
+		{name: "timeout", server: server, path: "/slow", failed: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			host, port := serverAddress(t, tc.server)
+			action := &v1.HTTPGetAction{Host: host, Port: port, Path: tc.path, Scheme: tc.scheme, HTTPHeaders: tc.headers}
+			output, err := runHTTPProbe(action, 200*time.Millisecond)
+			if (err != nil) != tc.failed {
+				t.Fatalf("expected the probe to fail: %t, got %v: %s", tc.failed, err, output)
+			}
+			if !tc.failed && output != "200 OK: ok" {
+				t.Errorf("expected the status and the body in the output, got %q", output)
+			}
+		})
+	}
+
+	if _, err := runHTTPProbe(&v1.HTTPGetAction{Port: intstr.FromString("http")}, time.Second); err == nil {
+		t.Errorf("expected a named port to be rejected")
+	}
+}
+
+func TestTCPProbe(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	output, err := runTCPProbe(&v1.TCPSocketAction{Port: intstr.FromInt(port)}, time.Second)
+	if err != nil || !strings.Contains(output, strconv.Itoa(port)) {
+		t.Errorf("expected the probe to connect to the local port, got %q: %v", output, err)
+	}
+
+	listener.Close()
+	if _, err = runTCPProbe(&v1.TCPSocketAction{Port: intstr.FromInt(port)}, time.Second); err == nil {
+		t.Errorf("expected the probe of a closed port to fail")
+	}
+	if _, err = runTCPProbe(&v1.TCPSocketAction{Port: intstr.FromInt(70000)}, time.Second); err == nil {
+		t.Errorf("expected an invalid port to be rejected")
+	}
+}
+
+func TestRunVerificationProbe(t *testing.T) {
+	var slept []time.Duration
+	probeSleep = func(d time.Duration) { slept = append(slept, d) }
+	defer func() { probeSleep = time.Sleep }()
+
+	failures := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	host, port := serverAddress(t, server)
+	probe := v1alpha1.VerificationProbe{
+		Name:                "edgecore",
+		InitialDelaySeconds: 5,
+		PeriodSeconds:       2,
+		FailureThreshold:    3,
+	}
+	probe.HTTPGet = &v1.HTTPGetAction{Host: host, Port: port}
+
+	// the probe succeeds once the service is ready within the failure threshold
+	failures = 2
+	if _, err := RunVerificationProbe(probe); err != nil {
+		t.Fatalf("expected the probe to succeed on its third attempt, got %v", err)
+	}
+	expected := []time.Duration{5 * time.Second, 2 * time.Second, 2 * time.Second}
+	if len(slept) != len(expected) || slept[0] != expected[0] || slept[1] != expected[1] || slept[2] != expected[2] {
+		t.Errorf("expected the initial delay and the period between attempts %v, got %v", expected, slept)
+	}
+
+	// the output of the last attempt is returned once the failure threshold is reached
+	failures = 3
+	output, err := RunVerificationProbe(probe)
+	if err == nil || !strings.HasPrefix(output, "503") {
+		t.Errorf("expected the probe to fail after 3 attempts with the last output, got %q: %v", output, err)
+	}
+	if failures != 0 {
+		t.Errorf("expected the probe to be attempted 3 times, %d attempts left", failures)
+	}
+
+	if _, err = RunVerificationProbe(v1alpha1.VerificationProbe{Name: "empty", FailureThreshold: 1}); err == nil {
+		t.Errorf("expected a probe without an action to fail")
+	}
+}
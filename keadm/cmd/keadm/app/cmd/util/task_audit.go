@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/pkg/apis"
+	"github.com/kubeedge/kubeedge/pkg/util/supportbundle"
+)
+
+// TaskCommandRecord is a command run on the edge node for a step of a task, it is kept in the audit
+// record of the task on the node
+type TaskCommandRecord struct {
+	Time  string `json:"time"`
+	State string `json:"state"`
+	// Command is the command line run, the secrets passed to it are redacted
+	Command string `json:"command"`
+}
+
+// auditedTask is the task the commands run by this process are recorded for
+var auditedTask struct {
+	sync.Mutex
+	taskType, taskID, state string
+}
+
+func taskAuditFile(taskType, taskID string) string {
+	return filepath.Join(KubeEdgeTaskAuditPath, fmt.Sprintf("%s-%s.log", taskType, taskID))
+}
+
+// AuditTaskCommands records the commands run by this process from now on for the task in the state,
+// e.g. the commands keadm runs to upgrade the node
+func AuditTaskCommands(taskType, taskID, state string) {
+	auditedTask.Lock()
+	defer auditedTask.Unlock()
+	auditedTask.taskType, auditedTask.taskID, auditedTask.state = taskType, taskID, state
+}
+
+// auditCommand records the command for the audited task of this process, if any
+func auditCommand(command string) {
+	auditedTask.Lock()
+	taskType, taskID, state := auditedTask.taskType, auditedTask.taskID, auditedTask.state
+	auditedTask.Unlock()
+	if taskID == "" {
+		return
+	}
+	if err := RecordTaskCommand(taskType, taskID, state, command); err != nil {
+		klog.Warningf("failed to record command of task %s/%s: %v", taskType, taskID, err)
+	}
+}
+
+// RecordTaskCommand appends the command run for the task in the state to the audit record of the
+// task, one JSON record per line. The secrets passed to the command are redacted.
+func RecordTaskCommand(taskType, taskID, state, command string) error {
+	data, err := json.Marshal(TaskCommandRecord{
+		Time:    time.Now().UTC().Format(apis.ISO8601UTC),
+		State:   state,
+		Command: supportbundle.RedactCommand(command),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal command record: %v", err)
+	}
+	if err = os.MkdirAll(KubeEdgeTaskAuditPath, 0750); err != nil {
+		return fmt.Errorf("failed to create task audit dir: %v", err)
+	}
+	f, err := os.OpenFile(taskAuditFile(taskType, taskID), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// TaskAuditDigest returns the SHA-256 digest of the audit record of the task, sha256:<hex>, an
+// auditor verifies the record kept on the node against it. It is empty if the task ran no command.
+func TaskAuditDigest(taskType, taskID string) string {
+	data, err := os.ReadFile(taskAuditFile(taskType, taskID))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			klog.Warningf("failed to read audit record of task %s/%s: %v", taskType, taskID, err)
+		}
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// DeleteTaskAudit removes the audit record of the task
+func DeleteTaskAudit(taskType, taskID string) error {
+	err := os.Remove(taskAuditFile(taskType, taskID))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
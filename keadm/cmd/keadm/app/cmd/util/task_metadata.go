@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+)
+
+// TaskMetadataEnvPrefix prefixes the environment variables the custom metadata of a task is passed in
+// to the commands run for the task, e.g. KUBEEDGE_TASK_METADATA_ticket for the key ticket
+const TaskMetadataEnvPrefix = "KUBEEDGE_TASK_METADATA_"
+
+// TaskMetadataEnv returns the environment variables of the custom metadata of a task,
+// the keys which are not valid environment variable names are skipped
+func TaskMetadataEnv(metadata map[string]string) []string {
+	env := make([]string, 0, len(metadata))
+	for key, value := range metadata {
+		if len(validation.IsCIdentifier(key)) != 0 {
+			continue
+		}
+		env = append(env, TaskMetadataEnvPrefix+key+"="+value)
+	}
+	sort.Strings(env)
+	return env
+}
+
+const (
+	// TaskTokenEnv is the environment variable the token minted for the task is passed in to the
+	// commands run for the task
+	TaskTokenEnv = "KUBEEDGE_TASK_TOKEN"
+	// TaskTokenExpirationEnv is the environment variable the expiration of the token is passed in, in RFC 3339
+	TaskTokenExpirationEnv = "KUBEEDGE_TASK_TOKEN_EXPIRATION"
+)
+
+// TaskCredentialEnv returns the environment variables of the credential minted for a task, it is
+// empty if the task has no credential
+func TaskCredentialEnv(credential *commontypes.TaskCredential) []string {
+	if credential == nil {
+		return nil
+	}
+	return []string{
+		TaskTokenEnv + "=" + credential.Token,
+		TaskTokenExpirationEnv + "=" + credential.ExpirationTimestamp.UTC().Format(time.RFC3339),
+	}
+}
+
+// TaskMetadataFromEnv returns the custom metadata of the task passed to this process in its environment
+func TaskMetadataFromEnv() map[string]string {
+	var metadata map[string]string
+	for _, kv := range os.Environ() {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(key, TaskMetadataEnvPrefix) || key == TaskMetadataEnvPrefix {
+			continue
+		}
+		if metadata == nil {
+			metadata = map[string]string{}
+		}
+		metadata[strings.TrimPrefix(key, TaskMetadataEnvPrefix)] = value
+	}
+	return metadata
+}
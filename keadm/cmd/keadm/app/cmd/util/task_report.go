@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+)
+
+// taskReportDir is where the task reports are buffered, it is replaced in tests
+var taskReportDir = KubeEdgeTaskReportPath
+
+func taskReportFile(taskType, taskID string) string {
+	return filepath.Join(taskReportDir, fmt.Sprintf("%s-%s.json", taskType, taskID))
+}
+
+// SaveTaskReport buffers the latest result of a node task, so that it can be
+// replayed to cloud if cloudcore was unreachable when the result was reported.
+// The report is written to a temporary file first and renamed, so that a crash
+// while it is written never leaves a partial report.
+func SaveTaskReport(taskType, taskID, state string, resp commontypes.NodeTaskResponse) error {
+	data, err := json.Marshal(commontypes.NodeTaskReport{
+		Type:     taskType,
+		TaskID:   taskID,
+		State:    state,
+		Response: resp,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal task report: %v", err)
+	}
+	if err = os.MkdirAll(taskReportDir, 0750); err != nil {
+		return fmt.Errorf("failed to create task report dir: %v", err)
+	}
+	file := taskReportFile(taskType, taskID)
+	tmp := file + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create task report: %v", err)
+	}
+	defer os.Remove(tmp)
+
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write task report: %v", err)
+	}
+	return os.Rename(tmp, file)
+}
+
+// LoadTaskReport returns the buffered result of a node task
+func LoadTaskReport(taskType, taskID string) (*commontypes.NodeTaskReport, error) {
+	data, err := os.ReadFile(taskReportFile(taskType, taskID))
+	if err != nil {
+		return nil, err
+	}
+	report := &commontypes.NodeTaskReport{}
+	if err = json.Unmarshal(data, report); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task report: %v", err)
+	}
+	return report, nil
+}
+
+// ReplayTaskReport returns the buffered result of the node task to report again if cloud is reconciling
+// the task in the state the result was saved in, it returns false if the state is to be executed.
+// A report which cannot be read is not replayed, the state is executed again.
+func ReplayTaskReport(taskReq commontypes.NodeTaskRequest) (*commontypes.NodeTaskResponse, bool) {
+	if !taskReq.Reconcile {
+		return nil, false
+	}
+	report, err := LoadTaskReport(taskReq.Type, taskReq.TaskID)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			klog.Warningf("failed to load task report of task %s, execute state %s again: %v", taskReq.TaskID, taskReq.State, err)
+		}
+		return nil, false
+	}
+	if report.State != taskReq.State {
+		return nil, false
+	}
+	return &report.Response, true
+}
+
+// ListTaskReports returns the tasks whose results are buffered on the edge node
+func ListTaskReports() ([]commontypes.NodeTaskKey, error) {
+	entries, err := os.ReadDir(taskReportDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task report dir: %v", err)
+	}
+	var tasks []commontypes.NodeTaskKey
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(taskReportDir, name))
+		if err != nil {
+			return nil, err
+		}
+		report := commontypes.NodeTaskReport{}
+		if err = json.Unmarshal(data, &report); err != nil {
+			klog.Warningf("skip invalid task report %s: %v", name, err)
+			continue
+		}
+		if report.Type == "" || report.TaskID == "" {
+			// reports saved by former versions only carry the task in the file name <type>-<id>.json,
+			// the in-tree task types contain no dash
+			var ok bool
+			report.Type, report.TaskID, ok = strings.Cut(strings.TrimSuffix(name, ".json"), "-")
+			if !ok {
+				continue
+			}
+		}
+		tasks = append(tasks, commontypes.NodeTaskKey{Type: report.Type, TaskID: report.TaskID})
+	}
+	return tasks, nil
+}
+
+// DeleteTaskReport removes the buffered result of a node task
+func DeleteTaskReport(taskType, taskID string) error {
+	err := os.Remove(taskReportFile(taskType, taskID))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	commontypes "github.com/kubeedge/kubeedge/common/types"
+)
+
+func useTaskReportDir(t *testing.T) string {
+	taskReportDir = t.TempDir()
+	t.Cleanup(func() { taskReportDir = KubeEdgeTaskReportPath })
+	return taskReportDir
+}
+
+func TestSaveTaskReport(t *testing.T) {
+	dir := useTaskReportDir(t)
+
+	resp := commontypes.NodeTaskResponse{NodeName: "edge-1", Action: "Success"}
+	if err := SaveTaskReport("upgrade", "job-1", "Upgrading", resp); err != nil {
+		t.Fatalf("failed to save task report: %v", err)
+	}
+	// the latest result replaces the former one
+	resp.Action = "Failure"
+	if err := SaveTaskReport("upgrade", "job-1", "Upgrading", resp); err != nil {
+		t.Fatalf("failed to save task report: %v", err)
+	}
+	report, err := LoadTaskReport("upgrade", "job-1")
+	if err != nil {
+		t.Fatalf("failed to load task report: %v", err)
+	}
+	expected := &commontypes.NodeTaskReport{Type: "upgrade", TaskID: "job-1", State: "Upgrading", Response: resp}
+	if !reflect.DeepEqual(report, expected) {
+		t.Errorf("expected task report %+v, got %+v", expected, report)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read task report dir: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "upgrade-job-1.json" {
+		t.Errorf("expected only the task report to be left, got %v", entries)
+	}
+}
+
+func TestReplayTaskReport(t *testing.T) {
+	dir := useTaskReportDir(t)
+
+	resp := commontypes.NodeTaskResponse{NodeName: "edge-1", Action: "Success"}
+	if err := SaveTaskReport("upgrade", "job-1", "Upgrading", resp); err != nil {
+		t.Fatalf("failed to save task report: %v", err)
+	}
+	// a report which was partially written before the file was renamed
+	if err := os.WriteFile(filepath.Join(dir, "restart-job-2.json"), []byte(`{"Type":"restart","TaskID":"job-2","Sta`), 0600); err != nil {
+		t.Fatalf("failed to write corrupt task report: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		req     commontypes.NodeTaskRequest
+		replay  bool
+		message string
+	}{
+		{
+			name:    "reconciled in the saved state",
+			req:     commontypes.NodeTaskRequest{Type: "upgrade", TaskID: "job-1", State: "Upgrading", Reconcile: true},
+			replay:  true,
+			message: "expected the buffered result to be replayed",
+		},
+		{
+			name:    "not reconciling",
+			req:     commontypes.NodeTaskRequest{Type: "upgrade", TaskID: "job-1", State: "Upgrading"},
+			message: "expected a dispatched task to be executed",
+		},
+		{
+			name:    "reconciled in another state",
+			req:     commontypes.NodeTaskRequest{Type: "upgrade", TaskID: "job-1", State: "Checking", Reconcile: true},
+			message: "expected a state which was not executed to be executed",
+		},
+		{
+			name:    "no report",
+			req:     commontypes.NodeTaskRequest{Type: "upgrade", TaskID: "job-3", State: "Upgrading", Reconcile: true},
+			message: "expected a task without a report to be executed",
+		},
+		{
+			name:    "corrupt report",
+			req:     commontypes.NodeTaskRequest{Type: "restart", TaskID: "job-2", State: "Restarting", Reconcile: true},
+			message: "expected a task whose report cannot be read to be executed again",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			replayed, ok := ReplayTaskReport(tc.req)
+			if ok != tc.replay {
+				t.Fatal(tc.message)
+			}
+			if ok && !reflect.DeepEqual(*replayed, resp) {
+				t.Errorf("expected the buffered result %+v to be replayed, got %+v", resp, *replayed)
+			}
+		})
+	}
+}
+
+func TestListTaskReportsSkipsPartialFiles(t *testing.T) {
+	dir := useTaskReportDir(t)
+
+	resp := commontypes.NodeTaskResponse{NodeName: "edge-1", Action: "Success"}
+	if err := SaveTaskReport("upgrade", "job-1", "Upgrading", resp); err != nil {
+		t.Fatalf("failed to save task report: %v", err)
+	}
+	// the temporary file of a write which crashed and a corrupt report are skipped
+	if err := os.WriteFile(filepath.Join(dir, "restart-job-2.json.tmp"), []byte(`{"Type":"restart"`), 0600); err != nil {
+		t.Fatalf("failed to write temporary task report: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "restart-job-3.json"), []byte(`not json`), 0600); err != nil {
+		t.Fatalf("failed to write corrupt task report: %v", err)
+	}
+
+	tasks, err := ListTaskReports()
+	if err != nil {
+		t.Fatalf("failed to list task reports: %v", err)
+	}
+	expected := []commontypes.NodeTaskKey{{Type: "upgrade", TaskID: "job-1"}}
+	if !reflect.DeepEqual(tasks, expected) {
+		t.Errorf("expected task reports %v, got %v", expected, tasks)
+	}
+
+	if err = DeleteTaskReport("upgrade", "job-1"); err != nil {
+		t.Fatalf("failed to delete task report: %v", err)
+	}
+	if _, ok := ReplayTaskReport(commontypes.NodeTaskRequest{Type: "upgrade", TaskID: "job-1", State: "Upgrading", Reconcile: true}); ok {
+		t.Errorf("expected the removed task report not to be replayed")
+	}
+}
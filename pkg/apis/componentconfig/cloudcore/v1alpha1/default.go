@@ -119,6 +119,44 @@ func NewDefaultCloudCoreConfig() *CloudCoreConfig {
 				Load: &TaskManagerLoad{
 					TaskWorkers: constants.DefaultNodeUpgradeJobWorkers,
 				},
+				NodeFilterWebhook: &TaskManagerNodeFilterWebhook{
+					TimeoutSeconds: constants.DefaultNodeFilterWebhookTimeout,
+				},
+				AlertWebhook: &TaskManagerAlertWebhook{
+					TimeoutSeconds:        constants.DefaultAlertWebhookTimeout,
+					RepeatIntervalSeconds: constants.DefaultAlertRepeatInterval,
+				},
+				TransitionExport: &TaskManagerTransitionExport{
+					Format:               TransitionExportFormatNDJSON,
+					BatchSize:            constants.DefaultTransitionExportBatchSize,
+					FlushIntervalSeconds: constants.DefaultTransitionExportFlushInterval,
+					TimeoutSeconds:       constants.DefaultTransitionExportTimeout,
+				},
+				TransitionHistory: &TaskManagerTransitionHistory{
+					MaxRecords: constants.DefaultTransitionHistoryMaxRecords,
+				},
+				DispatchMode:                   TaskDispatchModePush,
+				FailureBudgetWarningThresholds: []int32{50, 80},
+				UnknownStatePolicy:             UnknownStatePolicyIgnore,
+				StalledExecutorSeconds:         constants.DefaultStalledExecutorSeconds,
+				RecordStore: &TaskManagerRecordStore{
+					Backend: RecordStoreBackendConfigMap,
+				},
+				SupportBundleDir: constants.DefaultSupportBundleDir,
+				SupportBundleUpload: &TaskManagerSupportBundleUpload{
+					TimeoutSeconds: constants.DefaultSupportBundleUploadTimeout,
+				},
+				DispatchPacing: &TaskManagerDispatchPacing{
+					Enable:              true,
+					FlappingDisconnects: constants.DefaultFlappingDisconnects,
+					SlowRTTMilliseconds: constants.DefaultSlowRTTMilliseconds,
+				},
+				LeaderElection: &TaskManagerLeaderElection{
+					LeaseName:            constants.DefaultTaskManagerLeaseName,
+					LeaseDurationSeconds: constants.DefaultTaskManagerLeaseDuration,
+					RenewDeadlineSeconds: constants.DefaultTaskManagerRenewDeadline,
+					RetryPeriodSeconds:   constants.DefaultTaskManagerRetryPeriod,
+				},
 			},
 			SyncController: &SyncController{
 				Enable: true,
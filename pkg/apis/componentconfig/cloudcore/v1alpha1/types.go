@@ -427,8 +427,245 @@ type TaskManager struct {
 	Buffer *TaskManagerBuffer `json:"buffer,omitempty"`
 	// Load indicates Operation Controller Load
 	Load *TaskManagerLoad `json:"load,omitempty"`
+	// NodeFilterWebhook indicates the external webhook used to filter the candidate nodes of tasks
+	NodeFilterWebhook *TaskManagerNodeFilterWebhook `json:"nodeFilterWebhook,omitempty"`
+	// DispatchMode indicates how tasks are delivered to edge nodes, Push or Pull.
+	// In Pull mode the tasks are held by cloud until the edge nodes poll for them,
+	// edge nodes must set edgeHub.taskPollInterval. The edge nodes poll the HTTPS server
+	// of cloudhub and send the results of the tasks to it, the tasks do not go through
+	// the websocket or quic connection of edgehub.
+	// default Push
+	DispatchMode string `json:"dispatchMode,omitempty"`
+	// FailureBudgetWarningThresholds indicates the percentages of the failure tolerance of a task,
+	// a warning event is emitted when the failed nodes of the task reach each of them
+	// default [50, 80]
+	FailureBudgetWarningThresholds []int32 `json:"failureBudgetWarningThresholds,omitempty"`
+	// DispatchPacing indicates how edge nodes with poor connections are deprioritized in tasks
+	DispatchPacing *TaskManagerDispatchPacing `json:"dispatchPacing,omitempty"`
+	// DispatchRetries indicates how many times a task message is dispatched again when the
+	// edge node does not respond in time, the unanswered message is then saved as a dead letter
+	// in the record store.
+	// default 0
+	DispatchRetries int32 `json:"dispatchRetries,omitempty"`
+	// PrunedNodeStatusFields indicates the per-node status fields which are not persisted to the task
+	// while the node is in an intermediate state, the results of nodes in terminal states are persisted
+	// in full. The full detail of every transition is still exported as metrics and events.
+	// Supported fields are reason, time and imageStatus.
+	// default empty
+	PrunedNodeStatusFields []string `json:"prunedNodeStatusFields,omitempty"`
+	// AcceptTimeoutSeconds indicates how long TaskManager waits for the edge node to accept a task,
+	// a task which is not accepted in time is considered undelivered and is dispatched again
+	// without waiting for the task timeout. 0 means edge nodes are not required to accept tasks.
+	// default 0
+	AcceptTimeoutSeconds int32 `json:"acceptTimeoutSeconds,omitempty"`
+	// SealedPayloadTypes indicates the task types whose payloads are encrypted for the target edge node,
+	// using the public key of the certificate the node connects to CloudHub with. Intermediate relays
+	// and message dumps only see the sealed payload.
+	// default empty
+	SealedPayloadTypes []string `json:"sealedPayloadTypes,omitempty"`
+	// CredentialServiceAccounts indicates the service accounts, as namespace/name, whose tokens TaskManager
+	// mints for the credentials of the tasks. The nodes of a task requesting the credential of any other
+	// service account fail, so that the tasks cannot borrow the identities TaskManager can mint tokens of.
+	// default empty, no credential is minted
+	CredentialServiceAccounts []string `json:"credentialServiceAccounts,omitempty"`
+	// UnknownStatePolicy indicates how a node reporting a state the task does not recognize is handled,
+	// Ignore or Fail. The node is marked UnknownState with the raw state in its reason in both cases,
+	// with Ignore it times out unless it reports a known state, with Fail it is failed immediately.
+	// default Ignore
+	// +kubebuilder:validation:Enum=Ignore;Fail
+	UnknownStatePolicy string `json:"unknownStatePolicy,omitempty"`
+	// RecordStore indicates where the records TaskManager keeps for the nodes of tasks, such as
+	// dead letters, are persisted
+	RecordStore *TaskManagerRecordStore `json:"recordStore,omitempty"`
+	// SupportBundleDir indicates the directory the bundles collected by SupportBundleJobs are stored in,
+	// the bundles are removed with their jobs
+	// default "/var/lib/kubeedge/support-bundles"
+	SupportBundleDir string `json:"supportBundleDir,omitempty"`
+	// SupportBundleUpload indicates the object store the bundles of the edge nodes collected by
+	// SupportBundleJobs are uploaded to, the bundles are only kept on cloudcore if it is not set
+	SupportBundleUpload *TaskManagerSupportBundleUpload `json:"supportBundleUpload,omitempty"`
+	// VersionCheckItems indicates the check items of the NodeUpgradeJobs upgrading to some versions,
+	// e.g. the checks of the configs deprecated by a version. They are run in addition to the check
+	// items of the jobs, so that users don't need to know which checks matter for which upgrade.
+	// default empty
+	VersionCheckItems []TaskManagerVersionCheckItems `json:"versionCheckItems,omitempty"`
+	// MaxInFlightNodes indicates the max number of nodes operated on at the same time across all
+	// the tasks, in addition to the concurrency of each task. It protects CloudHub and the image
+	// registries when many tasks run at once, the tasks waiting for a free slot take it in turn.
+	// 0 means no limit.
+	// default 0
+	MaxInFlightNodes int32 `json:"maxInFlightNodes,omitempty"`
+	// DownstreamRateLimit indicates how many task messages are sent to the edge nodes per second,
+	// for each task and across all the tasks, so that a big task does not flood CloudHub
+	DownstreamRateLimit *TaskManagerDownstreamRateLimit `json:"downstreamRateLimit,omitempty"`
+	// FailureClusterLabels indicates the node labels the failed nodes of a finished NodeUpgradeJob are
+	// grouped by, e.g. a site label, in addition to the event they failed on, their version and their
+	// architecture. The clusters are recorded in the status of the job.
+	// default empty
+	FailureClusterLabels []string `json:"failureClusterLabels,omitempty"`
+	// AlertWebhook indicates the Alertmanager compatible webhook the alerts of tasks are sent to
+	AlertWebhook *TaskManagerAlertWebhook `json:"alertWebhook,omitempty"`
+	// TransitionExport indicates the external store the state transitions of the nodes of tasks are
+	// streamed to, for the analytics of the rollouts beyond the retention of the task objects
+	TransitionExport *TaskManagerTransitionExport `json:"transitionExport,omitempty"`
+	// TransitionHistory indicates whether the state transitions of each node are recorded in the status
+	// of the task, and how the history of a node is compacted so that retried nodes don't grow the
+	// status unboundedly
+	TransitionHistory *TaskManagerTransitionHistory `json:"transitionHistory,omitempty"`
+	// StalledExecutorSeconds indicates how long the executor of a task with nodes in flight may make
+	// no progress, i.e. no node changes its state and no node is dispatched, before it is considered
+	// stalled and restarted from the recorded status of the task. It is extended to cover the timeouts
+	// of the task. A panicked executor is restarted without waiting. 0 disables the watchdog.
+	// default 1800
+	StalledExecutorSeconds int32 `json:"stalledExecutorSeconds,omitempty"`
+	// LeaderElection indicates the election of the replica running the executors of the tasks when
+	// cloudcore runs in HA. The other replicas record the status reported by the nodes connected to
+	// them, and a new leader rebuilds the executors of the unfinished tasks from their recorded status.
+	// The task messages of the nodes connected to the other replicas, and the pulls and receipts of
+	// these nodes, are relayed through ConfigMaps in the kubeedge namespace.
+	LeaderElection *TaskManagerLeaderElection `json:"leaderElection,omitempty"`
+	// ReadOnly puts TaskManager in observe-only mode, e.g. in a staging environment or to validate an
+	// upgrade of cloudcore before it operates on the fleet. The tasks are accepted, their nodes are
+	// selected and their plans recorded, but nothing is dispatched to the edge nodes. The tasks wait
+	// for dispatch until TaskManager is switched back to active mode.
+	// default false
+	ReadOnly bool `json:"readOnly,omitempty"`
+}
+
+// TaskManagerDownstreamRateLimit indicates the token buckets the task messages sent to the edge nodes
+// are limited by. A message waits for a token of the bucket of its task and of the global bucket, the
+// messages of a task are sent in order.
+type TaskManagerDownstreamRateLimit struct {
+	// QPS indicates the messages sent per second across all the tasks, 0 means no limit
+	// default 0
+	QPS int32 `json:"qps,omitempty"`
+	// Burst indicates the messages sent at once across all the tasks, QPS is used if it is 0
+	// default 0
+	Burst int32 `json:"burst,omitempty"`
+	// TaskQPS indicates the messages of each task sent per second, 0 means no limit
+	// default 0
+	TaskQPS int32 `json:"taskQPS,omitempty"`
+	// TaskBurst indicates the messages of each task sent at once, TaskQPS is used if it is 0
+	// default 0
+	TaskBurst int32 `json:"taskBurst,omitempty"`
+}
+
+// TaskManagerLeaderElection indicates how the replicas of cloudcore elect the one running the executors
+// of the tasks, the election is held with a Lease in the kubeedge namespace
+type TaskManagerLeaderElection struct {
+	// Enable indicates whether the executors run on the elected replica only, it is required to run
+	// the tasks when cloudcore runs in HA
+	// default false
+	Enable bool `json:"enable"`
+	// LeaseName indicates the name of the Lease the replicas are elected with
+	// default taskmanager
+	LeaseName string `json:"leaseName,omitempty"`
+	// LeaseDurationSeconds indicates how long the other replicas wait before they take over the
+	// lease of a leader which stopped renewing it
+	// default 15
+	LeaseDurationSeconds int32 `json:"leaseDurationSeconds,omitempty"`
+	// RenewDeadlineSeconds indicates how long the leader tries to renew its lease before it gives up
+	// the executors, it must be less than LeaseDurationSeconds
+	// default 10
+	RenewDeadlineSeconds int32 `json:"renewDeadlineSeconds,omitempty"`
+	// RetryPeriodSeconds indicates how often the replicas try to acquire or renew the lease
+	// default 2
+	RetryPeriodSeconds int32 `json:"retryPeriodSeconds,omitempty"`
+}
+
+// TaskManagerVersionCheckItems indicates the check items of the upgrades to a range of versions
+type TaskManagerVersionCheckItems struct {
+	// Versions indicates the range of the versions upgraded to, e.g. ">=1.17.0 <1.18.0",
+	// see github.com/blang/semver for the syntax of the range
+	Versions string `json:"versions"`
+	// CheckItems indicates the check items run before upgrading to the versions
+	CheckItems []string `json:"checkItems"`
+}
+
+// TaskManagerRecordStore indicates the storage of the records of tasks
+type TaskManagerRecordStore struct {
+	// Backend indicates the storage of the records, ConfigMap, Annotation or S3.
+	// ConfigMap stores the records in the ConfigMap <taskType>-<taskName>-<kind> of the kubeedge namespace,
+	// Annotation stores them in the annotation operations.kubeedge.io/<kind> of the task object,
+	// S3 stores them in an S3-compatible bucket outside of the cluster.
+	// default ConfigMap
+	Backend string `json:"backend,omitempty"`
+	// S3 indicates the bucket used by the S3 backend
+	S3 *TaskManagerS3RecordStore `json:"s3,omitempty"`
+}
+
+// TaskManagerS3RecordStore indicates the S3-compatible bucket the records of tasks are stored in,
+// the records are stored as objects <prefix><taskType>/<taskName>/<kind>/<nodeName> and are
+// removed when the task is deleted.
+type TaskManagerS3RecordStore struct {
+	// Endpoint indicates the URL of the S3-compatible service, the bucket is addressed in path style
+	Endpoint string `json:"endpoint,omitempty"`
+	// Bucket indicates the name of the bucket
+	Bucket string `json:"bucket,omitempty"`
+	// Region indicates the region the requests are signed for
+	// default us-east-1
+	Region string `json:"region,omitempty"`
+	// Prefix indicates the prefix of the object keys of the records
+	Prefix string `json:"prefix,omitempty"`
+	// CredentialsSecret indicates the Secret in the kubeedge namespace which holds the access key
+	// of the bucket in the keys accessKeyID and secretAccessKey
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
 }
 
+// TaskManagerDispatchPacing indicates how TaskManager orders the nodes of a task by their connection quality.
+// The tasks which order their nodes by a seed or by readiness are not paced, they keep the recorded order.
+type TaskManagerDispatchPacing struct {
+	// Enable indicates whether edge nodes with poor connections are dispatched at the end of tasks
+	// default true
+	Enable bool `json:"enable"`
+	// FlappingDisconnects indicates the number of disconnects within the last 10 minutes
+	// from which an edge node is considered flapping
+	// default 3
+	FlappingDisconnects int32 `json:"flappingDisconnects,omitempty"`
+	// SlowRTTMilliseconds indicates the message round trip time above which an edge node is considered slow
+	// default 1000
+	SlowRTTMilliseconds int32 `json:"slowRTTMilliseconds,omitempty"`
+}
+
+const (
+	// TaskDispatchModePush means tasks are pushed to edge nodes as soon as they are scheduled
+	TaskDispatchModePush = "Push"
+	// TaskDispatchModePull means tasks are held until edge nodes poll for them
+	TaskDispatchModePull = "Pull"
+)
+
+const (
+	// RecordStoreBackendConfigMap means the records of a task are stored in a ConfigMap
+	RecordStoreBackendConfigMap = "ConfigMap"
+	// RecordStoreBackendAnnotation means the records of a task are stored in an annotation of the task object
+	RecordStoreBackendAnnotation = "Annotation"
+	// RecordStoreBackendS3 means the records of a task are stored in an S3-compatible bucket
+	RecordStoreBackendS3 = "S3"
+)
+
+const (
+	// NodeFilterFailurePolicyIgnore means all the candidate nodes are kept when the node filter webhook fails
+	NodeFilterFailurePolicyIgnore = "Ignore"
+	// NodeFilterFailurePolicyFail means all the candidate nodes are excluded when the node filter webhook fails
+	NodeFilterFailurePolicyFail = "Fail"
+)
+
+const (
+	// UnknownStatePolicyIgnore means nodes in unknown states are left to time out
+	UnknownStatePolicyIgnore = "Ignore"
+	// UnknownStatePolicyFail means nodes in unknown states are failed
+	UnknownStatePolicyFail = "Fail"
+)
+
+const (
+	// NodeStatusFieldReason is the reason of the node status
+	NodeStatusFieldReason = "reason"
+	// NodeStatusFieldTime is the transition time of the node status
+	NodeStatusFieldTime = "time"
+	// NodeStatusFieldImageStatus is the image pulling results of the node status of ImagePrePullJob
+	NodeStatusFieldImageStatus = "imageStatus"
+)
+
 // TaskManagerBuffer indicates TaskManager buffer
 type TaskManagerBuffer struct {
 	// TaskStatus indicates the buffer of update NodeUpgradeJob status
@@ -446,6 +683,103 @@ type TaskManagerLoad struct {
 	TaskWorkers int32 `json:"taskWorkers,omitempty"`
 }
 
+// TaskManagerNodeFilterWebhook indicates the external node filter webhook of TaskManager
+type TaskManagerNodeFilterWebhook struct {
+	// URL indicates the address of the webhook, the webhook is not called if it is empty
+	URL string `json:"url,omitempty"`
+	// TimeoutSeconds indicates the timeout of calling the webhook
+	// default 10
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+	// FailurePolicy indicates how the candidate nodes are handled when the webhook fails, Ignore or Fail.
+	// A response larger than 1 MiB is a failure of the webhook.
+	// With Ignore all the candidate nodes are kept, with Fail they are all excluded as FilterFailed
+	// with the error of the webhook, so that an outage of the webhook is not taken for its decision.
+	// default Fail
+	FailurePolicy string `json:"failurePolicy,omitempty"`
+}
+
+// TaskManagerAlertWebhook indicates the Alertmanager compatible webhook of TaskManager.
+// An alert fires when a task fails or reaches a failure budget warning threshold, and is
+// resolved when the task completes or is deleted.
+type TaskManagerAlertWebhook struct {
+	// URL indicates the address the alerts are posted to, e.g. http://alertmanager:9093/api/v2/alerts,
+	// no alert is sent if it is empty
+	URL string `json:"url,omitempty"`
+	// TimeoutSeconds indicates the timeout of posting the alerts
+	// default 10
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+	// RepeatIntervalSeconds indicates how often the firing alerts are posted again,
+	// so that Alertmanager does not resolve them on its own
+	// default 60
+	RepeatIntervalSeconds int32 `json:"repeatIntervalSeconds,omitempty"`
+	// Labels indicates the labels added to all the alerts, e.g. the cluster name
+	// default empty
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+const (
+	// TransitionExportFormatNDJSON posts the transitions as newline-delimited JSON objects,
+	// e.g. for the JSONEachRow format of ClickHouse
+	TransitionExportFormatNDJSON = "NDJSON"
+	// TransitionExportFormatJSON posts the transitions as a JSON array, e.g. for PostgREST
+	TransitionExportFormatJSON = "JSON"
+)
+
+// TaskManagerTransitionExport indicates the HTTP endpoint of the external time-series store the state
+// transitions of the nodes of tasks are streamed to. Only the transitions are exported, the full status
+// of the tasks stays in the task objects. The transitions which fail to be posted are posted again with
+// backoff, up to 10000 of them are kept while the store is unavailable.
+type TaskManagerTransitionExport struct {
+	// URL indicates the address the transitions are posted to, e.g.
+	// http://clickhouse:8123/?query=INSERT%20INTO%20node_transitions%20FORMAT%20JSONEachRow,
+	// no transition is exported if it is empty
+	URL string `json:"url,omitempty"`
+	// Format indicates how the transitions are encoded, NDJSON or JSON
+	// default NDJSON
+	Format string `json:"format,omitempty"`
+	// Headers indicates the headers added to the requests, e.g. the credentials of the store
+	// default empty
+	Headers map[string]string `json:"headers,omitempty"`
+	// BatchSize indicates the max number of transitions posted at once
+	// default 100
+	BatchSize int32 `json:"batchSize,omitempty"`
+	// FlushIntervalSeconds indicates how long the transitions are batched before they are posted
+	// default 10
+	FlushIntervalSeconds int32 `json:"flushIntervalSeconds,omitempty"`
+	// TimeoutSeconds indicates the timeout of posting the transitions
+	// default 10
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// TaskManagerSupportBundleUpload indicates the object store the bundles of the edge nodes are uploaded
+// to. Each bundle is uploaded with an HTTP PUT to <url>/<job>/<node>.tar.gz, e.g. to a bucket of an S3
+// compatible store which accepts the credentials in the headers.
+type TaskManagerSupportBundleUpload struct {
+	// URL indicates the address the bundles are uploaded under, e.g. https://minio:9000/support-bundles,
+	// no bundle is uploaded if it is empty
+	URL string `json:"url,omitempty"`
+	// Headers indicates the headers added to the requests, e.g. the credentials of the store
+	// default empty
+	Headers map[string]string `json:"headers,omitempty"`
+	// TimeoutSeconds indicates the timeout of uploading a bundle
+	// default 30
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// TaskManagerTransitionHistory indicates the recording of the state transitions of each node in the
+// status of the task. The history of a node is compacted once it exceeds MaxRecords: the first and the
+// latest transitions and the failures are kept, the transitions in between are summarized in a single
+// record which counts them.
+type TaskManagerTransitionHistory struct {
+	// Enable indicates whether the transitions of the nodes are recorded in the status of the tasks
+	// default false
+	Enable bool `json:"enable"`
+	// MaxRecords indicates the max number of transition records kept for each node, values below 4
+	// are raised to 4
+	// default 20
+	MaxRecords int32 `json:"maxRecords,omitempty"`
+}
+
 // ImagePrePullController indicates the operations controller
 type ImagePrePullController struct {
 	// Enable indicates whether ImagePrePullController is enabled,
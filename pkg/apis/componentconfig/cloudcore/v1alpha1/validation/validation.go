@@ -44,6 +44,9 @@ func ValidateCloudCoreConfiguration(c *v1alpha1.CloudCoreConfig) field.ErrorList
 	allErrs = append(allErrs, ValidateModuleSyncController(*c.Modules.SyncController)...)
 	allErrs = append(allErrs, ValidateModuleDynamicController(*c.Modules.DynamicController)...)
 	allErrs = append(allErrs, ValidateModuleCloudStream(*c.Modules.CloudStream)...)
+	if c.Modules.TaskManager != nil {
+		allErrs = append(allErrs, ValidateModuleTaskManager(*c.Modules.TaskManager)...)
+	}
 	return allErrs
 }
 
@@ -203,6 +206,38 @@ func ValidateModuleCloudStream(d v1alpha1.CloudStream) field.ErrorList {
 	return allErrs
 }
 
+// ValidateModuleTaskManager validates `t` and returns an errorList if it is invalid
+func ValidateModuleTaskManager(t v1alpha1.TaskManager) field.ErrorList {
+	if !t.Enable {
+		return field.ErrorList{}
+	}
+
+	allErrs := field.ErrorList{}
+	switch t.UnknownStatePolicy {
+	case "", v1alpha1.UnknownStatePolicyIgnore, v1alpha1.UnknownStatePolicyFail:
+	default:
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("UnknownStatePolicy"), t.UnknownStatePolicy,
+			[]string{v1alpha1.UnknownStatePolicyIgnore, v1alpha1.UnknownStatePolicyFail}))
+	}
+	supportedFields := []string{v1alpha1.NodeStatusFieldReason, v1alpha1.NodeStatusFieldTime, v1alpha1.NodeStatusFieldImageStatus}
+	for i, f := range t.PrunedNodeStatusFields {
+		switch f {
+		case v1alpha1.NodeStatusFieldReason, v1alpha1.NodeStatusFieldTime, v1alpha1.NodeStatusFieldImageStatus:
+		default:
+			allErrs = append(allErrs, field.NotSupported(field.NewPath("PrunedNodeStatusFields").Index(i), f, supportedFields))
+		}
+	}
+	if t.NodeFilterWebhook != nil {
+		switch t.NodeFilterWebhook.FailurePolicy {
+		case "", v1alpha1.NodeFilterFailurePolicyIgnore, v1alpha1.NodeFilterFailurePolicyFail:
+		default:
+			allErrs = append(allErrs, field.NotSupported(field.NewPath("NodeFilterWebhook", "FailurePolicy"), t.NodeFilterWebhook.FailurePolicy,
+				[]string{v1alpha1.NodeFilterFailurePolicyIgnore, v1alpha1.NodeFilterFailurePolicyFail}))
+		}
+	}
+	return allErrs
+}
+
 // ValidateKubeAPIConfig validates `k` and returns an errorList if it is invalid
 func ValidateKubeAPIConfig(k v1alpha1.KubeAPIConfig) field.ErrorList {
 	allErrs := field.ErrorList{}
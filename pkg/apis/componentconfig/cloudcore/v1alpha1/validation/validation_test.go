@@ -437,6 +437,66 @@ func TestValidateModuleCloudStream(t *testing.T) {
 	}
 }
 
+func TestValidateModuleTaskManager(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    v1alpha1.TaskManager
+		expected field.ErrorList
+	}{
+		{
+			name: "case1 not enable",
+			input: v1alpha1.TaskManager{
+				Enable:            false,
+				NodeFilterWebhook: &v1alpha1.TaskManagerNodeFilterWebhook{FailurePolicy: "Retry"},
+			},
+			expected: field.ErrorList{},
+		},
+		{
+			name: "case2 unsupported node filter failure policy",
+			input: v1alpha1.TaskManager{
+				Enable:            true,
+				NodeFilterWebhook: &v1alpha1.TaskManagerNodeFilterWebhook{FailurePolicy: "Retry"},
+			},
+			expected: field.ErrorList{field.NotSupported(field.NewPath("NodeFilterWebhook", "FailurePolicy"), "Retry",
+				[]string{v1alpha1.NodeFilterFailurePolicyIgnore, v1alpha1.NodeFilterFailurePolicyFail})},
+		},
+		{
+			name: "case3 unsupported unknown state policy",
+			input: v1alpha1.TaskManager{
+				Enable:             true,
+				UnknownStatePolicy: "fail",
+			},
+			expected: field.ErrorList{field.NotSupported(field.NewPath("UnknownStatePolicy"), "fail",
+				[]string{v1alpha1.UnknownStatePolicyIgnore, v1alpha1.UnknownStatePolicyFail})},
+		},
+		{
+			name: "case4 unsupported pruned node status field",
+			input: v1alpha1.TaskManager{
+				Enable:                 true,
+				PrunedNodeStatusFields: []string{v1alpha1.NodeStatusFieldTime, "reasons"},
+			},
+			expected: field.ErrorList{field.NotSupported(field.NewPath("PrunedNodeStatusFields").Index(1), "reasons",
+				[]string{v1alpha1.NodeStatusFieldReason, v1alpha1.NodeStatusFieldTime, v1alpha1.NodeStatusFieldImageStatus})},
+		},
+		{
+			name: "case5 all ok",
+			input: v1alpha1.TaskManager{
+				Enable:                 true,
+				PrunedNodeStatusFields: []string{v1alpha1.NodeStatusFieldReason, v1alpha1.NodeStatusFieldImageStatus},
+				UnknownStatePolicy:     v1alpha1.UnknownStatePolicyFail,
+				NodeFilterWebhook:      &v1alpha1.TaskManagerNodeFilterWebhook{FailurePolicy: v1alpha1.NodeFilterFailurePolicyIgnore},
+			},
+			expected: field.ErrorList{},
+		},
+	}
+
+	for _, c := range cases {
+		if result := ValidateModuleTaskManager(c.input); !reflect.DeepEqual(result, c.expected) {
+			t.Errorf("%v: expected %v, but got %v", c.name, c.expected, result)
+		}
+	}
+}
+
 func TestValidateKubeAPIConfig(t *testing.T) {
 	dir := t.TempDir()
 
@@ -854,6 +854,12 @@ type EdgeHub struct {
 	// RotateCertificates indicates whether edge certificate can be rotated
 	// default true
 	RotateCertificates bool `json:"rotateCertificates,omitempty"`
+	// TaskPollInterval indicates the interval (second) to poll the pending tasks from cloud,
+	// it is required when cloud TaskManager works in Pull mode. The tasks are polled from HTTPServer
+	// with the certificate of the node and their receipts and results are sent to it, independent
+	// of the websocket or quic connection of edgehub.
+	// default 0, tasks are pushed by cloud
+	TaskPollInterval int32 `json:"taskPollInterval,omitempty"`
 }
 
 // EdgeHubQUIC indicates the quic client config
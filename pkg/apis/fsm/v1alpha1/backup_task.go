@@ -20,12 +20,19 @@ const (
 	BackingUpState State = "BackingUp"
 )
 
-var BackupRule = map[string]State{
-	"/Init/Success": TaskChecking,
-
-	"Checking/Check/Success": BackingUpState,
-	"Checking/Check/Failure": TaskFailed,
+const (
+	// EventBackup finishes the backup of the node, the backup taken is reported with it
+	EventBackup = "Backup"
+)
 
-	"BackingUp/Backup/Success": TaskSuccessful,
-	"BackingUp/Backup/Failure": TaskFailed,
+// BackupStages are the stages of BackupJob
+var BackupStages = StageGraph{
+	Stages: []Stage{
+		{State: BackingUpState, Event: EventBackup},
+	},
 }
+
+// CurrentState/Event/Action: NextState
+var BackupRule = BackupStages.Rule()
+
+var BackupStageSequence = BackupStages.StageSequence()
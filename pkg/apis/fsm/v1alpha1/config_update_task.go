@@ -0,0 +1,42 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+const (
+	// ApplyingState is the state of a node applying the config patch and restarting EdgeCore
+	ApplyingState State = "Applying"
+)
+
+const (
+	// EventApply finishes applying the config patch on the node, the node is verified from cloud
+	// once EdgeCore restarted with the patched config
+	EventApply = "Apply"
+)
+
+// ConfigUpdateStages are the stages of ConfigUpdateJob
+var ConfigUpdateStages = StageGraph{
+	Stages: []Stage{
+		{State: ApplyingState, Event: EventApply},
+		// the node is verified from cloud once EdgeCore restarted with the patched config
+		{State: VerifyingState, Event: EventPostCheck, Internal: true},
+	},
+}
+
+// CurrentState/Event/Action: NextState
+var ConfigUpdateRule = ConfigUpdateStages.Rule()
+
+var ConfigUpdateStageSequence = ConfigUpdateStages.StageSequence()
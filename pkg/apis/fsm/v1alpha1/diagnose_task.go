@@ -0,0 +1,39 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+const (
+	// DiagnosingState is the state of a node running the checks of the diagnosis
+	DiagnosingState State = "Diagnosing"
+)
+
+const (
+	// EventDiagnose finishes the diagnosis of the node, the findings are reported with it
+	EventDiagnose = "Diagnose"
+)
+
+// DiagnoseStages are the stages of DiagnoseJob
+var DiagnoseStages = StageGraph{
+	Stages: []Stage{
+		{State: DiagnosingState, Event: EventDiagnose},
+	},
+}
+
+// CurrentState/Event/Action: NextState
+var DiagnoseRule = DiagnoseStages.Rule()
+
+var DiagnoseStageSequence = DiagnoseStages.StageSequence()
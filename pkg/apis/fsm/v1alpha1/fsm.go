@@ -33,13 +33,59 @@ const (
 	TaskSuccessful State = "Successful"
 	TaskFailed     State = "Failed"
 	TaskPause      State = "Pause"
+	// TaskUnknown is the state of a node that reported a state the task does not recognize
+	TaskUnknown State = "UnknownState"
+	// TaskCancelling is the state of a task or node whose cancellation is requested but not yet confirmed
+	TaskCancelling State = "Cancelling"
+	// TaskCancelled is the state of a task or node that was cancelled before it finished
+	TaskCancelled State = "Cancelled"
+	// TaskNodeRemoved is the state of a node whose Node object was deleted before it finished the task
+	TaskNodeRemoved State = "NodeRemoved"
+	// TaskSkipped is the state of a node which was not dispatched because it is pending removal
+	TaskSkipped State = "Skipped"
+	// TaskPartiallySucceeded is the state of a task which finished while some of its nodes failed,
+	// within the failure tolerance of the task
+	TaskPartiallySucceeded State = "PartiallySucceeded"
+	// TaskDeadlineExceeded is the state of a task which did not finish before its deadline
+	TaskDeadlineExceeded State = "DeadlineExceeded"
 )
 
+// A task finishes in exactly one of the following terminal states, it never leaves it:
+//   - Successful: the last stage completed on all the nodes which were not removed or skipped.
+//   - PartiallySucceeded: the last stage completed while some nodes failed within the failure tolerance.
+//     The task transits as with the Success action, with the PartialSuccess action instead.
+//   - Failed: the failed nodes exceeded the failure tolerance, or a stage of the task failed.
+//   - Cancelled: the task was cancelled before it finished.
+//   - DeadlineExceeded: the task did not finish before its deadline. It applies to any state
+//     which is not terminal, see TaskTerminalRule.
+//
+// A node leaves a terminal state only through an explicit rule of the task type, such as a Successful
+// node of a NodeUpgradeJob which is reverted once the job exceeded its failure tolerance.
+
 const (
 	ActionSuccess Action = "Success"
 	ActionFailure Action = "Failure"
+	// ActionPartialSuccess completes a stage of a task some nodes failed, the task transits as with
+	// ActionSuccess except that it finishes PartiallySucceeded instead of Successful.
+	ActionPartialSuccess Action = "PartialSuccess"
 )
 
 const (
 	EventTimeOut = "TimeOut"
+	// EventCancel requests the cancellation of a running state, it is sent again
+	// in the Cancelling state to confirm the cancellation.
+	EventCancel = "Cancel"
+	// EventCancelIdle cancels a node which is not running the task at once, there is no running
+	// state on the node whose cancellation must be confirmed.
+	EventCancelIdle = "CancelIdle"
+	// EventDeadlineExceeded finishes a task which did not finish before its deadline
+	EventDeadlineExceeded = "DeadlineExceeded"
 )
+
+// TaskTerminalRule holds the transitions shared by all the task types, they apply to any state which
+// is not terminal and which the rule of the task type does not handle.
+// Event/Action: NextState
+var TaskTerminalRule = map[string]State{
+	EventDeadlineExceeded + "/" + string(ActionFailure): TaskDeadlineExceeded,
+	EventCancelIdle + "/" + string(ActionSuccess):       TaskCancelled,
+}
@@ -20,23 +20,15 @@ const (
 	PullingState State = "Pulling"
 )
 
-// CurrentState/Event/Action: NextState
-var PrePullRule = map[string]State{
-	"Init/Init/Success":    TaskChecking,
-	"Init/Init/Failure":    TaskFailed,
-	"Init/TimeOut/Failure": TaskFailed,
-
-	"Checking/Check/Success":   PullingState,
-	"Checking/Check/Failure":   TaskFailed,
-	"Checking/TimeOut/Failure": TaskFailed,
-
-	"Pulling/Pull/Success":    TaskSuccessful,
-	"Pulling/Pull/Failure":    TaskFailed,
-	"Pulling/TimeOut/Failure": TaskFailed,
+// PrePullStages are the stages of ImagePrePullJob
+var PrePullStages = StageGraph{
+	Stages: []Stage{
+		{State: TaskChecking, Event: "Check"},
+		{State: PullingState, Event: "Pull"},
+	},
 }
 
-var PrePullStageSequence = map[State]State{
-	"":           TaskChecking,
-	TaskInit:     TaskChecking,
-	TaskChecking: PullingState,
-}
+// CurrentState/Event/Action: NextState
+var PrePullRule = PrePullStages.Rule()
+
+var PrePullStageSequence = PrePullStages.StageSequence()
@@ -0,0 +1,57 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+const (
+	// OSUpgradingState is the state of a node applying an OS or firmware update with its hook
+	OSUpgradingState State = "OSUpgrading"
+	// RebootingState is the state of a node whose host reboots to complete the update, the node is
+	// disconnected meanwhile and it is not failed until the stage times out
+	RebootingState State = "Rebooting"
+)
+
+const (
+	// EventOSUpgrade finishes applying the update on the node without rebooting its host
+	EventOSUpgrade = "OSUpgrade"
+	// EventReboot is reported by the node before its host reboots to complete the update
+	EventReboot = "Reboot"
+	// EventReconnect is reported by the node once it is back after its host rebooted
+	EventReconnect = "Reconnect"
+)
+
+// OSUpgradeStages are the stages of OSUpgradeJob
+var OSUpgradeStages = StageGraph{
+	Stages: []Stage{
+		{State: OSUpgradingState, Event: EventOSUpgrade},
+		// the node reboots within the OSUpgrading stage so that nothing is dispatched to it while it
+		// is disconnected
+		{State: RebootingState, Event: EventReconnect, Optional: true, EnterEvent: EventReboot, Internal: true},
+		{State: VerifyingState, Event: EventPostCheck, Internal: true},
+	},
+	Rules: map[string]State{
+		// the hook may reboot the host itself before the node reports it
+		"OSUpgrading/Reconnect/Success": VerifyingState,
+		"OSUpgrading/Reconnect/Failure": TaskFailed,
+	},
+}
+
+// CurrentState/Event/Action: NextState
+var OSUpgradeRule = OSUpgradeStages.Rule()
+
+// OSUpgradeStageSequence has no stage for Rebooting, the node reboots within the OSUpgrading stage
+// so that nothing is dispatched to it while it is disconnected
+var OSUpgradeStageSequence = OSUpgradeStages.StageSequence()
@@ -0,0 +1,41 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+const (
+	// RestartingState is the state of a node restarting EdgeCore or rebooting its host
+	RestartingState State = "Restarting"
+)
+
+const (
+	// EventRestart finishes restarting the node, the node is verified from cloud once EdgeCore restarted
+	EventRestart = "Restart"
+)
+
+// RestartStages are the stages of NodeRestartJob
+var RestartStages = StageGraph{
+	Stages: []Stage{
+		{State: RestartingState, Event: EventRestart},
+		// the node is verified from cloud once EdgeCore restarted
+		{State: VerifyingState, Event: EventPostCheck, Internal: true},
+	},
+}
+
+// CurrentState/Event/Action: NextState
+var RestartRule = RestartStages.Rule()
+
+var RestartStageSequence = RestartStages.StageSequence()
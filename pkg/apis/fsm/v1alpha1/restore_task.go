@@ -0,0 +1,39 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+const (
+	// RestoringState is the state of a node restoring a backup, EdgeCore is stopped meanwhile
+	RestoringState State = "Restoring"
+)
+
+const (
+	// EventRestore finishes restoring the node, it is reported once EdgeCore started again
+	EventRestore = "Restore"
+)
+
+// RestoreStages are the stages of RestoreJob
+var RestoreStages = StageGraph{
+	Stages: []Stage{
+		{State: RestoringState, Event: EventRestore},
+	},
+}
+
+// CurrentState/Event/Action: NextState
+var RestoreRule = RestoreStages.Rule()
+
+var RestoreStageSequence = RestoreStages.StageSequence()
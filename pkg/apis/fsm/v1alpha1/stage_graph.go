@@ -0,0 +1,181 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import "fmt"
+
+const (
+	// EventInit starts a node or a task, it moves it to the first stage
+	EventInit = "Init"
+)
+
+// Stage is a stage of a task type, the nodes are in State while they run it and report Event once
+// they finish it.
+type Stage struct {
+	// State is the state of the nodes in the stage, e.g. Upgrading
+	State State
+	// Event is reported by the nodes once they finish the stage, e.g. Upgrade
+	Event string
+	// Optional stages are entered only by the nodes which report EnterEvent in the stage before,
+	// the nodes which finish the stage before skip them
+	Optional bool
+	// EnterEvent is reported in the stage before to enter the optional stage, it is Event if empty
+	EnterEvent string
+	// Internal stages are not dispatched to the nodes, the nodes enter them once they finish the
+	// stage before, e.g. Verifying which is run from cloud. They are not stages of the task.
+	Internal bool
+	// OnFailure is the state of the nodes which fail the stage or time out in it, e.g. a stage rolling
+	// them back. The nodes fail if it is empty.
+	OnFailure State
+}
+
+// StageGraph declares the stages of a task type in order, the rule and the stage sequence of its
+// FSM are derived from it. Each stage can be cancelled, and times out on the TimeOut event.
+type StageGraph struct {
+	Stages []Stage
+	// Rules are the transitions which are not derived from the stages, e.g. to roll back the nodes
+	// once the task failed. They take precedence over the derived ones.
+	// CurrentState/Event/Action: NextState
+	Rules map[string]State
+	// Sequence is the stage sequence which is not derived from the stages, it takes precedence over
+	// the derived one
+	Sequence map[State]State
+}
+
+// Validate returns an error if the stages cannot be derived into a rule
+func (g StageGraph) Validate() error {
+	if len(g.Stages) == 0 {
+		return fmt.Errorf("no stage")
+	}
+	states := map[State]bool{}
+	for i, stage := range g.Stages {
+		switch {
+		case stage.State == "" || stage.Event == "":
+			return fmt.Errorf("stage %d has no state or event", i)
+		case reservedState(stage.State):
+			return fmt.Errorf("stage %d has the reserved state %s", i, stage.State)
+		case states[stage.State]:
+			return fmt.Errorf("state %s is used by several stages", stage.State)
+		case i == 0 && (stage.Optional || stage.Internal):
+			return fmt.Errorf("the first stage %s is optional or internal", stage.State)
+		}
+		states[stage.State] = true
+	}
+	for _, stage := range g.Stages {
+		if stage.OnFailure != "" && !states[stage.OnFailure] && !terminalState(stage.OnFailure) {
+			return fmt.Errorf("stage %s fails to %s which is neither a stage nor terminal", stage.State, stage.OnFailure)
+		}
+	}
+	return nil
+}
+
+// Rule returns the rule of the FSM, keyed by CurrentState/Event/Action with the next state as value.
+// The nodes move to the next stage which is not optional once they finish a stage, and to Successful
+// once they finish the last one.
+func (g StageGraph) Rule() map[string]State {
+	rule := map[string]State{}
+	set := func(state State, event string, action Action, next State) {
+		rule[string(state)+"/"+event+"/"+string(action)] = next
+	}
+	// advance moves the nodes which finished stage i to the next stage, or to the optional stages
+	// right after it with their enter events
+	advance := func(i int, from State, event string) {
+		next := TaskSuccessful
+		for j := i + 1; j < len(g.Stages); j++ {
+			if !g.Stages[j].Optional {
+				next = g.Stages[j].State
+				break
+			}
+			enter := g.Stages[j].EnterEvent
+			if enter == "" {
+				enter = g.Stages[j].Event
+			}
+			set(from, enter, ActionSuccess, g.Stages[j].State)
+		}
+		set(from, event, ActionSuccess, next)
+	}
+
+	advance(-1, TaskInit, EventInit)
+	set(TaskInit, EventInit, ActionFailure, TaskFailed)
+	set(TaskInit, EventTimeOut, ActionFailure, TaskFailed)
+	set(TaskInit, EventCancel, ActionSuccess, TaskCancelling)
+	for i, stage := range g.Stages {
+		failed := stage.OnFailure
+		if failed == "" {
+			failed = TaskFailed
+		}
+		advance(i, stage.State, stage.Event)
+		set(stage.State, stage.Event, ActionFailure, failed)
+		set(stage.State, EventTimeOut, ActionFailure, failed)
+		set(stage.State, EventCancel, ActionSuccess, TaskCancelling)
+	}
+	set(TaskUnknown, EventTimeOut, ActionFailure, TaskFailed)
+	set(TaskUnknown, EventCancel, ActionSuccess, TaskCancelling)
+	set(TaskCancelling, EventCancel, ActionSuccess, TaskCancelled)
+	set(TaskCancelling, EventCancel, ActionFailure, TaskFailed)
+	set(TaskCancelling, EventTimeOut, ActionFailure, TaskFailed)
+
+	for key, next := range g.Rules {
+		rule[key] = next
+	}
+	return rule
+}
+
+// StageSequence returns the stage sequence of the FSM, which maps each stage of the task to the stage
+// after it. The internal stages are not stages of the task.
+func (g StageGraph) StageSequence() map[State]State {
+	sequence := map[State]State{}
+	var last State
+	for _, stage := range g.Stages {
+		if stage.Internal {
+			continue
+		}
+		if last == "" {
+			sequence[""] = stage.State
+			sequence[TaskInit] = stage.State
+		} else {
+			sequence[last] = stage.State
+		}
+		last = stage.State
+	}
+	for from, to := range g.Sequence {
+		sequence[from] = to
+	}
+	return sequence
+}
+
+// States returns the states of the nodes in the stages, in order
+func (g StageGraph) States() []State {
+	states := make([]State, 0, len(g.Stages))
+	for _, stage := range g.Stages {
+		states = append(states, stage.State)
+	}
+	return states
+}
+
+func reservedState(state State) bool {
+	return state == TaskInit || state == TaskUnknown || state == TaskCancelling || state == TaskPause || terminalState(state)
+}
+
+// terminalState returns whether the state is terminal, see TaskFinish of pkg/util/fsm
+func terminalState(state State) bool {
+	switch state {
+	case TaskFailed, TaskSuccessful, TaskCancelled, TaskNodeRemoved, TaskSkipped, TaskPartiallySucceeded, TaskDeadlineExceeded, TaskReverted:
+		return true
+	}
+	return false
+}
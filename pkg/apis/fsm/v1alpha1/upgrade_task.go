@@ -18,44 +18,92 @@ package v1alpha1
 
 const (
 	UpgradingState State = "Upgrading"
+	// RevertingState is the state of a task rolling back the nodes it upgraded as it exceeded its failure
+	// tolerance, and the state of each of these nodes until it is rolled back
+	RevertingState State = "Reverting"
+	// TaskReverted is the state of a node upgraded by the task which was rolled back afterwards
+	TaskReverted State = "Reverted"
+	// WaitingApprovalState is the state of a task which upgraded its canary nodes, the other nodes
+	// are upgraded once the task is approved
+	WaitingApprovalState State = "WaitingApproval"
+	// VerifyingState is the state of a node which reported it is upgraded until it is verified from cloud
+	VerifyingState State = "Verifying"
 )
 
-// CurrentState/Event/Action: NextState
-var UpgradeRule = map[string]State{
-	"Init/Init/Success":    TaskChecking,
-	"Init/Init/Failure":    TaskFailed,
-	"Init/TimeOut/Failure": TaskFailed,
-	"Init/Upgrade/Success": TaskSuccessful,
-
-	"Checking/Check/Success":   BackingUpState,
-	"Checking/Check/Failure":   TaskFailed,
-	"Checking/TimeOut/Failure": TaskFailed,
-
-	"BackingUp/Backup/Success":  UpgradingState,
-	"BackingUp/Backup/Failure":  TaskFailed,
-	"BackingUp/TimeOut/Failure": TaskFailed,
-
-	"Upgrading/Upgrade/Success": TaskSuccessful,
-	"Upgrading/Upgrade/Failure": TaskFailed,
-	"Upgrading/TimeOut/Failure": TaskFailed,
-
-	// TODO provide options for task failure, such as successful node upgrade rollback.
-	"RollingBack/Rollback/Failure": TaskFailed,
-	"RollingBack/TimeOut/Failure":  TaskFailed,
-	"RollingBack/Rollback/Success": TaskFailed,
-
-	"Upgrading/Rollback/Failure": TaskFailed,
-	"Upgrading/Rollback/Success": TaskFailed,
-
-	//TODO delete in version 1.18
-	"Init/Rollback/Failure": TaskFailed,
-	"Init/Rollback/Success": TaskFailed,
-}
+const (
+	// EventRevert starts rolling back the nodes upgraded by a task, it finishes the task once they are rolled back
+	EventRevert = "Revert"
+	// EventCanaryUpgraded stops a task once its canary nodes are upgraded until it is approved
+	EventCanaryUpgraded = "CanaryUpgraded"
+	// EventApprove resumes upgrading the nodes of a task waiting for approval
+	EventApprove = "Approve"
+	// EventDryRun finishes a dry run once its nodes are checked, the nodes which passed the
+	// checks and the task succeed without being upgraded
+	EventDryRun = "DryRun"
+	// EventPostCheck starts verifying a node from cloud once it reports it is upgraded, and finishes
+	// the node with the verdict
+	EventPostCheck = "PostCheck"
+)
+
+// UpgradeStages are the stages of NodeUpgradeJob
+var UpgradeStages = StageGraph{
+	Stages: []Stage{
+		{State: TaskChecking, Event: "Check"},
+		{State: BackingUpState, Event: EventBackup},
+		{State: UpgradingState, Event: "Upgrade"},
+		// the node which reported it is upgraded is verified from cloud if the job has a post check
+		{State: VerifyingState, Event: EventPostCheck, Optional: true, Internal: true},
+	},
+	Rules: map[string]State{
+		"Init/Upgrade/Success":     TaskSuccessful,
+		"Init/PostCheck/Success":   VerifyingState,
+		"Upgrading/Verify/Failure": TaskFailed,
+
+		// a dry run finishes once the nodes are checked, the nodes which passed the checks are BackingUp
+		"Checking/DryRun/Success":  TaskSuccessful,
+		"BackingUp/DryRun/Success": TaskSuccessful,
+
+		// TODO provide options for task failure, such as successful node upgrade rollback.
+		"RollingBack/Rollback/Failure": TaskFailed,
+		"RollingBack/TimeOut/Failure":  TaskFailed,
+		"RollingBack/Rollback/Success": TaskFailed,
+		"RollingBack/Cancel/Success":   TaskCancelling,
 
-var UpdateStageSequence = map[State]State{
-	"":             TaskChecking,
-	TaskInit:       TaskChecking,
-	TaskChecking:   BackingUpState,
-	BackingUpState: UpgradingState,
-	UpgradingState: RollingBackState,
+		"Upgrading/Rollback/Failure": TaskFailed,
+		"Upgrading/Rollback/Success": TaskFailed,
+
+		// the task exceeded its failure tolerance, the upgraded nodes are rolled back before the task fails
+		"Checking/Revert/Success":   RevertingState,
+		"BackingUp/Revert/Success":  RevertingState,
+		"Upgrading/Revert/Success":  RevertingState,
+		"Reverting/Revert/Success":  RevertingState,
+		"Reverting/Revert/Failure":  TaskFailed,
+		"Successful/Revert/Success": RevertingState,
+
+		"Reverting/Rollback/Success": TaskReverted,
+		"Reverting/Rollback/Failure": TaskFailed,
+		"Reverting/TimeOut/Failure":  TaskFailed,
+		"Reverting/Cancel/Success":   TaskCancelling,
+
+		// the canary nodes are upgraded, the other nodes wait for the approval of the task
+		"Upgrading/CanaryUpgraded/Success":       WaitingApprovalState,
+		"WaitingApproval/CanaryUpgraded/Success": WaitingApprovalState,
+		"WaitingApproval/Approve/Success":        UpgradingState,
+		"WaitingApproval/Cancel/Success":         TaskCancelling,
+		"WaitingApproval/Revert/Success":         RevertingState,
+
+		//TODO delete in version 1.18
+		"Init/Rollback/Failure": TaskFailed,
+		"Init/Rollback/Success": TaskFailed,
+	},
+	Sequence: map[State]State{
+		UpgradingState: RollingBackState,
+		// the canary nodes completed the Upgrading stage, the other nodes have not started it yet
+		WaitingApprovalState: RollingBackState,
+	},
 }
+
+// CurrentState/Event/Action: NextState
+var UpgradeRule = UpgradeStages.Rule()
+
+var UpdateStageSequence = UpgradeStages.StageSequence()
@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BackupJob snapshots the local database of edgecore, its config and its certificates on edge nodes,
+// e.g. before they are upgraded, so that a RestoreJob can restore them on demand.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+type BackupJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec represents the specification of the desired behavior of BackupJob.
+	// +required
+	Spec BackupJobSpec `json:"spec"`
+
+	// Status represents the status of BackupJob.
+	// +optional
+	Status BackupJobStatus `json:"status,omitempty"`
+}
+
+// GetCommonJobSpec returns the part of the spec of the BackupJob shared by the operation jobs
+func (j *BackupJob) GetCommonJobSpec() *CommonJobSpec {
+	return &j.Spec.CommonJobSpec
+}
+
+// GetCommonJobStatus returns the part of the status of the BackupJob shared by the operation jobs
+func (j *BackupJob) GetCommonJobStatus() *CommonJobStatus {
+	return &j.Status.CommonJobStatus
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BackupJobList is a list of BackupJob.
+type BackupJobList struct {
+	// Standard type metadata.
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard list metadata.
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of BackupJob.
+	Items []BackupJob `json:"items"`
+}
+
+// BackupJobSpec represents the specification of the desired behavior of BackupJob.
+type BackupJobSpec struct {
+	// CommonJobSpec selects the nodes of the job and bounds how it runs on them.
+	CommonJobSpec `json:",inline"`
+
+	// Location is the directory on each node the backup is written to, in a subdirectory named
+	// after the job. The default Location value is /etc/kubeedge/backup/jobs.
+	// +optional
+	Location string `json:"location,omitempty"`
+}
+
+// BackupJobStatus stores the status of BackupJob.
+// The running state of BackupJob is BackingUp, its events are Backup besides Init and TimeOut.
+// +kubebuilder:validation:Type=object
+type BackupJobStatus struct {
+	// CommonJobStatus is the state of the job and of each of its nodes.
+	CommonJobStatus `json:",inline"`
+
+	// Backups are the backups taken on each backed up edge node.
+	// +optional
+	Backups []NodeBackup `json:"backups,omitempty"`
+}
+
+// NodeBackup is the backup taken on an edge node
+type NodeBackup struct {
+	// NodeName is the name of the backed up edge node.
+	NodeName string `json:"nodeName"`
+	// Path is the directory on the node the backup is written to.
+	Path string `json:"path"`
+	// Files are the files backed up, by their path on the node.
+	// +optional
+	Files []string `json:"files,omitempty"`
+	// Size is the size of the backup in bytes.
+	// +optional
+	Size int64 `json:"size,omitempty"`
+}
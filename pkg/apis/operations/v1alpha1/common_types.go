@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+)
+
+// CommonJobSpec is the part of the specification shared by the operation jobs run on edge nodes,
+// BackupJob, RestoreJob, ConfigUpdateJob, NodeRestartJob, DiagnoseJob, OSUpgradeJob and SupportBundleJob.
+type CommonJobSpec struct {
+	// NodeNames is a request to select some specific nodes. If it is non-empty,
+	// the job simply operates on these edge nodes.
+	// Please note that sets of NodeNames and LabelSelector are ORed.
+	// Users must set one and can only set one.
+	// +optional
+	NodeNames []string `json:"nodeNames,omitempty"`
+	// LabelSelector is a filter to select member clusters by labels.
+	// It must match a node's labels for the job to be operated on that node.
+	// Please note that sets of NodeNames and LabelSelector are ORed.
+	// Users must set one and can only set one.
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+
+	// FailureTolerate specifies how many of the nodes of the job can fail, a number of nodes or a percentage
+	// of the nodes, e.g. 10%. The job fails once more nodes than it fail, e.g. it fails on the 4th failed node
+	// if it is 3 and on the first failed node if it is 0. A ratio, e.g. 0.1, is still accepted but deprecated.
+	// It can be updated while the job is running, a job which already failed is not resumed by raising it.
+	// The default FailureTolerate value is 10%.
+	// +optional
+	// +kubebuilder:validation:XIntOrString
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Pattern=`^([0-9]|[1-9][0-9]|100)%$|^0(\.[0-9]+)?$|^1(\.0+)?$`
+	FailureTolerate *intstr.IntOrString `json:"failureTolerate,omitempty"`
+
+	// Concurrency specifies the maximum number of edge nodes that are operated on at the same time.
+	// It can be updated while the job is running, the nodes being operated on finish if it is lowered.
+	// The default Concurrency value is 1.
+	// +optional
+	Concurrency int32 `json:"concurrency,omitempty"`
+
+	// TimeoutSeconds limits the duration of the job on each edgenode, until the node is verified if the
+	// job verifies it.
+	// Default to 300, OSUpgradeJob defaults to 3600.
+	// If set to 0, we'll use the default value.
+	// +optional
+	TimeoutSeconds *uint32 `json:"timeoutSeconds,omitempty"`
+
+	// ActiveDeadlineSeconds bounds the duration of the job from the time cloudcore starts running it,
+	// it is finished DeadlineExceeded once the deadline is exceeded and the nodes still running it are
+	// cancelled. It is measured by the monotonic clock of the cloudcore running the job, it counts again
+	// from the time another cloudcore takes over the job or cloudcore restarts.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// TTLSecondsAfterFinished limits the lifetime of the job once it finished, the job is deleted the
+	// given seconds after it reaches a terminal state. The job is kept if it is not set.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+
+	// Metadata is the custom metadata of the job, e.g. the ID of an external ticket. It is passed to the
+	// commands run for the job on the edge nodes in the environment variables KUBEEDGE_TASK_METADATA_<key>,
+	// and echoed back with the result of each node in its status. The keys must be valid environment
+	// variable names.
+	// +optional
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// RenderPayload renders the Go templates in the string fields of the request sent to each node
+	// with the variables of the node, e.g. {{ .NodeName }} or {{ index .Labels "site" }}. The request
+	// is sent as is unless it is set.
+	// +optional
+	RenderPayload bool `json:"renderPayload,omitempty"`
+
+	// Rerun runs the finished job again when it is changed. Any other change of the spec of a
+	// finished job is ignored, re-applying it is a no-op unless Rerun is changed as well.
+	// +optional
+	Rerun int64 `json:"rerun,omitempty"`
+
+	// Cohorts label the nodes of the job with the cohort of their outcome once the job is finished, e.g.
+	// upgrade-result=failed-v1.17.1, so that later jobs select the cohorts with their labelSelector.
+	// +optional
+	Cohorts *NodeCohortSpec `json:"cohorts,omitempty"`
+
+	// Notifications are sent once the job finishes and once its failed nodes exceed its failure
+	// tolerance, so that CI/CD pipelines react to the job without polling it.
+	// +optional
+	Notifications []NotificationSink `json:"notifications,omitempty"`
+
+	// Credential mints a short-lived token for each node of the job, it is passed to the commands run
+	// for the job on the node.
+	// +optional
+	Credential *TaskCredentialSpec `json:"credential,omitempty"`
+}
+
+// CommonJobStatus is the part of the status shared by the operation jobs run on edge nodes.
+// +kubebuilder:validation:Type=object
+type CommonJobStatus struct {
+	// State represents for the state phase of the job.
+	// The possible state values are: "", the running state of the type of the job, Successful,
+	// PartiallySucceeded, Failed, Cancelled and DeadlineExceeded.
+	State api.State `json:"state,omitempty"`
+
+	// Event represents for the event of the job.
+	// The possible event values are Init, TimeOut and the events of the type of the job.
+	Event string `json:"event,omitempty"`
+
+	// Action represents for the action of the job.
+	// There are two possible action values: Success, Failure.
+	Action api.Action `json:"action,omitempty"`
+
+	// Reason represents for the reason of the job.
+	Reason string `json:"reason,omitempty"`
+
+	// Time represents for the running time of the job.
+	Time string `json:"time,omitempty"`
+
+	// Status contains the status of the job for each edge node.
+	Status []TaskStatus `json:"nodeStatus,omitempty"`
+
+	// ExcludedNodes are the nodes selected by the job which are not operated on and why, the first
+	// 100 of them are listed.
+	// +optional
+	ExcludedNodes []ExcludedNode `json:"excludedNodes,omitempty"`
+
+	// FailureThreshold is the number of failed nodes the job fails at, it is computed from failureTolerate
+	// and the number of nodes of the job.
+	// +optional
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+
+	// ObservedRerun is the Rerun of the spec the job last ran with.
+	// +optional
+	ObservedRerun int64 `json:"observedRerun,omitempty"`
+
+	// StartTime is the time the job was last rerun, the job starts at its creation otherwise.
+	// +optional
+	StartTime string `json:"startTime,omitempty"`
+
+	// Outputs are the values the job exports once it is finished, other jobs reference them as inputs.
+	// Every job exports succeededNodes and failedNodes, the comma separated names of its succeeded and
+	// failed nodes.
+	// +optional
+	Outputs map[string]string `json:"outputs,omitempty"`
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ConfigUpdateJob pushes a change of the edgecore configuration to edge nodes, e.g. enables a module
+// or raises the logging level. Each node applies the change, restarts edgecore and is verified from
+// cloud once edgecore is back.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+type ConfigUpdateJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec represents the specification of the desired behavior of ConfigUpdateJob.
+	// +required
+	Spec ConfigUpdateJobSpec `json:"spec"`
+
+	// Status represents the status of ConfigUpdateJob.
+	// +optional
+	Status ConfigUpdateJobStatus `json:"status,omitempty"`
+}
+
+// GetCommonJobSpec returns the part of the spec of the ConfigUpdateJob shared by the operation jobs
+func (j *ConfigUpdateJob) GetCommonJobSpec() *CommonJobSpec {
+	return &j.Spec.CommonJobSpec
+}
+
+// GetCommonJobStatus returns the part of the status of the ConfigUpdateJob shared by the operation jobs
+func (j *ConfigUpdateJob) GetCommonJobStatus() *CommonJobStatus {
+	return &j.Status.CommonJobStatus
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ConfigUpdateJobList is a list of ConfigUpdateJob.
+type ConfigUpdateJobList struct {
+	// Standard type metadata.
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard list metadata.
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of ConfigUpdateJob.
+	Items []ConfigUpdateJob `json:"items"`
+}
+
+// ConfigUpdateJobSpec represents the specification of the desired behavior of ConfigUpdateJob.
+type ConfigUpdateJobSpec struct {
+	// CommonJobSpec selects the nodes of the job and bounds how it runs on them.
+	CommonJobSpec `json:",inline"`
+
+	// Patch is the JSON merge patch applied to the edgecore config of each node, in the layout of the
+	// edgecore config file, e.g. {"modules": {"edgeStream": {"enable": true}}}. A null value removes the
+	// field. The node keeps the config it ran with next to the config file, edgecore.yaml.bak.
+	// +required
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Patch runtime.RawExtension `json:"patch"`
+
+	// PostCheck verifies each node from cloud once edgecore restarted with the patched config, the node
+	// succeeds once it is Ready and runs the critical pods.
+	// The default PostCheck value is nil, which only waits for the node to be Ready for 300 seconds.
+	// +optional
+	PostCheck *PostCheckSpec `json:"postCheck,omitempty"`
+}
+
+// ConfigUpdateJobStatus stores the status of ConfigUpdateJob.
+// The running state of ConfigUpdateJob is Applying, its events are Apply and PostCheck besides Init and TimeOut.
+// +kubebuilder:validation:Type=object
+type ConfigUpdateJobStatus struct {
+	// CommonJobStatus is the state of the job and of each of its nodes.
+	CommonJobStatus `json:",inline"`
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DiagnoseJob runs the checks of `keadm debug diagnose` on edge nodes, e.g. the connection to
+// cloud, the validity of the certificate and the health of the container runtime, and records
+// the findings of each node in its status.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+type DiagnoseJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec represents the specification of the desired behavior of DiagnoseJob.
+	// +required
+	Spec DiagnoseJobSpec `json:"spec"`
+
+	// Status represents the status of DiagnoseJob.
+	// +optional
+	Status DiagnoseJobStatus `json:"status,omitempty"`
+}
+
+// GetCommonJobSpec returns the part of the spec of the DiagnoseJob shared by the operation jobs
+func (j *DiagnoseJob) GetCommonJobSpec() *CommonJobSpec {
+	return &j.Spec.CommonJobSpec
+}
+
+// GetCommonJobStatus returns the part of the status of the DiagnoseJob shared by the operation jobs
+func (j *DiagnoseJob) GetCommonJobStatus() *CommonJobStatus {
+	return &j.Status.CommonJobStatus
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DiagnoseJobList is a list of DiagnoseJob.
+type DiagnoseJobList struct {
+	// Standard type metadata.
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard list metadata.
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of DiagnoseJob.
+	Items []DiagnoseJob `json:"items"`
+}
+
+// DiagnoseJobSpec represents the specification of the desired behavior of DiagnoseJob.
+type DiagnoseJobSpec struct {
+	// CommonJobSpec selects the nodes of the job and bounds how it runs on them.
+	CommonJobSpec `json:",inline"`
+
+	// Checks are the checks run on each node, all of them are run if it is empty.
+	// +optional
+	Checks []DiagnoseCheck `json:"checks,omitempty"`
+}
+
+// DiagnoseJobStatus stores the status of DiagnoseJob.
+// The running state of DiagnoseJob is Diagnosing, its events are Diagnose besides Init and TimeOut.
+// +kubebuilder:validation:Type=object
+type DiagnoseJobStatus struct {
+	// CommonJobStatus is the state of the job and of each of its nodes.
+	CommonJobStatus `json:",inline"`
+
+	// Diagnoses are the findings of the checks run on each diagnosed edge node.
+	// +optional
+	Diagnoses []NodeDiagnosis `json:"diagnoses,omitempty"`
+}
+
+// DiagnoseCheck is a check run on the edge nodes
+// +kubebuilder:validation:Enum=connectivity;certificate;runtime;database
+type DiagnoseCheck string
+
+const (
+	// DiagnoseConnectivity checks that edgecore is connected to cloud and reaches the cloudhub server
+	DiagnoseConnectivity DiagnoseCheck = "connectivity"
+	// DiagnoseCertificate checks that the certificate of the node is valid and does not expire soon
+	DiagnoseCertificate DiagnoseCheck = "certificate"
+	// DiagnoseRuntime checks that the container runtime answers on its endpoint
+	DiagnoseRuntime DiagnoseCheck = "runtime"
+	// DiagnoseDatabase checks that the database of edgecore exists
+	DiagnoseDatabase DiagnoseCheck = "database"
+)
+
+// DiagnoseResult is the result of a check
+type DiagnoseResult string
+
+const (
+	DiagnosePassed  DiagnoseResult = "Passed"
+	DiagnoseWarning DiagnoseResult = "Warning"
+	DiagnoseFailed  DiagnoseResult = "Failed"
+)
+
+// NodeDiagnosis is the diagnosis of an edge node
+type NodeDiagnosis struct {
+	// NodeName is the name of the diagnosed edge node.
+	NodeName string `json:"nodeName"`
+	// Healthy is true if none of the checks failed on the node.
+	Healthy bool `json:"healthy"`
+	// Findings are the results of the checks run on the node.
+	// +optional
+	Findings []DiagnoseFinding `json:"findings,omitempty"`
+}
+
+// DiagnoseFinding is the result of a check run on an edge node
+type DiagnoseFinding struct {
+	// Check is the check run.
+	Check DiagnoseCheck `json:"check"`
+	// Result is the result of the check: Passed, Warning or Failed.
+	Result DiagnoseResult `json:"result"`
+	// Message describes the result.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
 )
@@ -59,7 +60,9 @@ type ImagePrePullJobList struct {
 
 // ImagePrePullSpec represents the specification of the desired behavior of ImagePrePullJob.
 type ImagePrePullJobSpec struct {
-	// ImagePrepullTemplate represents original templates of imagePrePull
+	// ImagePrepullTemplate represents original templates of imagePrePull.
+	// Concurrency, FailureTolerate and Rerun are the only fields of the template which can be
+	// updated once the job is created.
 	ImagePrePullTemplate ImagePrePullTemplate `json:"imagePrePullTemplate,omitempty"`
 }
 
@@ -82,20 +85,42 @@ type ImagePrePullTemplate struct {
 	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
 
 	// CheckItems specifies the items need to be checked before the task is executed.
-	// The default CheckItems value is disk.
+	// The default CheckItems value is disk. The check item registry fails the nodes which cannot pull
+	// the images they do not have yet from their registry within TimeoutSeconds, estimated from the
+	// latency of the registry and the throughput of the download of the largest layer.
 	// +optional
 	CheckItems []string `json:"checkItems,omitempty"`
 
-	// FailureTolerate specifies the task tolerance failure ratio.
-	// The default FailureTolerate value is 0.1.
+	// FailureTolerate specifies how many of the nodes of the job can fail, a number of nodes or a percentage
+	// of the nodes, e.g. 10%. The job fails once more nodes than it fail, e.g. it fails on the 4th failed node
+	// if it is 3 and on the first failed node if it is 0. A ratio, e.g. 0.1, is still accepted but deprecated.
+	// It can be updated while the job is running, a job which already failed is not resumed by raising it.
+	// The default FailureTolerate value is 10%.
 	// +optional
-	FailureTolerate string `json:"failureTolerate,omitempty"`
+	// +kubebuilder:validation:XIntOrString
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Pattern=`^([0-9]|[1-9][0-9]|100)%$|^0(\.[0-9]+)?$|^1(\.0+)?$`
+	FailureTolerate *intstr.IntOrString `json:"failureTolerate,omitempty"`
 
 	// Concurrency specifies the maximum number of edge nodes that can pull images at the same time.
+	// It can be updated while the job is running, the nodes pulling images finish if it is lowered.
 	// The default Concurrency value is 1.
 	// +optional
 	Concurrency int32 `json:"concurrency,omitempty"`
 
+	// DispatchJitterSeconds is the upper bound of the random delay before the job is dispatched to each
+	// edge node, it keeps the nodes sharing a registry from pulling the images at once.
+	// The default DispatchJitterSeconds value is 0, which dispatches without delay.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	DispatchJitterSeconds int32 `json:"dispatchJitterSeconds,omitempty"`
+
+	// Preflight probes the edge nodes over the task channel before the job is dispatched to them,
+	// the nodes which do not answer are failed right away instead of taking a worker until they time out.
+	// The default Preflight value is nil, which dispatches the job without probing the nodes.
+	// +optional
+	Preflight *PreflightSpec `json:"preflight,omitempty"`
+
 	// TimeoutSeconds limits the duration of the node prepull job on each edgenode.
 	// Default to 300.
 	// If set to 0, we'll use the default value 300.
@@ -111,6 +136,60 @@ type ImagePrePullTemplate struct {
 	// Default to 0
 	// +optional
 	RetryTimes int32 `json:"retryTimes,omitempty"`
+
+	// ActiveDeadlineSeconds bounds the duration of the job from the time cloudcore starts running it,
+	// it is finished DeadlineExceeded once the deadline is exceeded and the nodes still running it are
+	// cancelled. It is measured by the monotonic clock of the cloudcore running the job, it counts again
+	// from the time another cloudcore takes over the job or cloudcore restarts.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// TTLSecondsAfterFinished limits the lifetime of the job once it finished, the job is deleted the
+	// given seconds after it reaches a terminal state. The job is kept if it is not set.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+
+	// Metadata is the custom metadata of the job, e.g. the ID of an external ticket. It is passed to the
+	// commands run for the job on the edge nodes in the environment variables KUBEEDGE_TASK_METADATA_<key>,
+	// and echoed back with the result of each node in its status. The keys must be valid environment
+	// variable names.
+	// +optional
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// RenderPayload renders the Go templates in the string fields of the request sent to each node
+	// with the variables of the node, e.g. {{ .NodeName }} or {{ index .Labels "site" }}. The request
+	// is sent as is unless it is set.
+	// +optional
+	RenderPayload bool `json:"renderPayload,omitempty"`
+
+	// Cohorts label the nodes of the job with the cohort of their outcome once the job is finished, e.g.
+	// upgrade-result=failed-v1.17.1, so that later jobs select the cohorts with their labelSelector.
+	// +optional
+	Cohorts *NodeCohortSpec `json:"cohorts,omitempty"`
+
+	// Notifications are sent once the job finishes and once its failed nodes exceed its failure
+	// tolerance, so that CI/CD pipelines react to the job without polling it.
+	// +optional
+	Notifications []NotificationSink `json:"notifications,omitempty"`
+
+	// Credential mints a short-lived token for each node of the job, it is passed to the commands run
+	// for the job on the node.
+	// +optional
+	Credential *TaskCredentialSpec `json:"credential,omitempty"`
+
+	// Rerun runs the finished job again when it is changed. Any other change of the spec of a
+	// finished job is ignored, re-applying it is a no-op unless Rerun is changed as well.
+	// +optional
+	Rerun int64 `json:"rerun,omitempty"`
+
+	// Inputs bind names to the outputs of other jobs, e.g. to the nodes a NodeUpgradeJob upgraded.
+	// A string of the template references an input as $(inputs.<name>), an item of a list which is
+	// exactly a reference is replaced by the comma separated items of the input, e.g. images: ["$(inputs.images)"].
+	// The job waits until the jobs it references are finished, the inputs are resolved once when it starts.
+	// +optional
+	Inputs []JobInput `json:"inputs,omitempty"`
 }
 
 // ImagePrePullJobStatus stores the status of ImagePrePullJob.
@@ -137,6 +216,38 @@ type ImagePrePullJobStatus struct {
 
 	// Status contains image prepull status for each edge node.
 	Status []ImagePrePullStatus `json:"status,omitempty"`
+
+	// ExcludedNodes are the nodes selected by the job which are not operated on and why, the first
+	// 100 of them are listed.
+	// +optional
+	ExcludedNodes []ExcludedNode `json:"excludedNodes,omitempty"`
+
+	// FailureThreshold is the number of failed nodes the job fails at, it is computed from failureTolerate
+	// and the number of nodes of the job.
+	// +optional
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+
+	// Cost aggregates the cost of pulling the images on the edge nodes.
+	// +optional
+	Cost *TaskCost `json:"cost,omitempty"`
+
+	// ObservedRerun is the Rerun of the spec the job last ran with.
+	// +optional
+	ObservedRerun int64 `json:"observedRerun,omitempty"`
+
+	// StartTime is the time the job was last rerun, the job starts at its creation otherwise.
+	// +optional
+	StartTime string `json:"startTime,omitempty"`
+
+	// Inputs are the values the inputs of the job resolved to when it started.
+	// +optional
+	Inputs map[string]string `json:"inputs,omitempty"`
+
+	// Outputs are the values the job exports once it is finished, other jobs reference them as inputs.
+	// Every job exports succeededNodes and failedNodes, the comma separated names of its succeeded and
+	// failed nodes, an ImagePrePullJob exports the comma separated images it pulled as well.
+	// +optional
+	Outputs map[string]string `json:"outputs,omitempty"`
 }
 
 // ImagePrePullStatus stores image prepull status for each edge node.
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeRestartJob restarts edgecore, or reboots the host, on edge nodes. Each node is verified from
+// cloud once edgecore is back.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+type NodeRestartJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec represents the specification of the desired behavior of NodeRestartJob.
+	// +required
+	Spec NodeRestartJobSpec `json:"spec"`
+
+	// Status represents the status of NodeRestartJob.
+	// +optional
+	Status NodeRestartJobStatus `json:"status,omitempty"`
+}
+
+// GetCommonJobSpec returns the part of the spec of the NodeRestartJob shared by the operation jobs
+func (j *NodeRestartJob) GetCommonJobSpec() *CommonJobSpec {
+	return &j.Spec.CommonJobSpec
+}
+
+// GetCommonJobStatus returns the part of the status of the NodeRestartJob shared by the operation jobs
+func (j *NodeRestartJob) GetCommonJobStatus() *CommonJobStatus {
+	return &j.Status.CommonJobStatus
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeRestartJobList is a list of NodeRestartJob.
+type NodeRestartJobList struct {
+	// Standard type metadata.
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard list metadata.
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of NodeRestartJob.
+	Items []NodeRestartJob `json:"items"`
+}
+
+// NodeRestartJobSpec represents the specification of the desired behavior of NodeRestartJob.
+type NodeRestartJobSpec struct {
+	// CommonJobSpec selects the nodes of the job and bounds how it runs on them.
+	CommonJobSpec `json:",inline"`
+
+	// Reboot reboots the host of each node instead of restarting edgecore only, the node fails if its
+	// host did not reboot.
+	// +optional
+	Reboot bool `json:"reboot,omitempty"`
+
+	// PostCheck verifies each node from cloud once edgecore restarted, the node succeeds once it is
+	// Ready and runs the critical pods.
+	// The default PostCheck value is nil, which only waits for the node to be Ready for 300 seconds.
+	// +optional
+	PostCheck *PostCheckSpec `json:"postCheck,omitempty"`
+}
+
+// NodeRestartJobStatus stores the status of NodeRestartJob.
+// The running state of NodeRestartJob is Restarting, its events are Restart and PostCheck besides Init and TimeOut.
+// +kubebuilder:validation:Type=object
+type NodeRestartJobStatus struct {
+	// CommonJobStatus is the state of the job and of each of its nodes.
+	CommonJobStatus `json:",inline"`
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// OSUpgradeJob updates the OS or the firmware of edge nodes. Each node downloads the artifact of the
+// job and runs the signed hook script installed on it to apply the artifact, the hook may ask for the
+// host to be rebooted. Each node is verified from cloud once it is back.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+type OSUpgradeJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec represents the specification of the desired behavior of OSUpgradeJob.
+	// +required
+	Spec OSUpgradeJobSpec `json:"spec"`
+
+	// Status represents the status of OSUpgradeJob.
+	// +optional
+	Status OSUpgradeJobStatus `json:"status,omitempty"`
+}
+
+// GetCommonJobSpec returns the part of the spec of the OSUpgradeJob shared by the operation jobs
+func (j *OSUpgradeJob) GetCommonJobSpec() *CommonJobSpec {
+	return &j.Spec.CommonJobSpec
+}
+
+// GetCommonJobStatus returns the part of the status of the OSUpgradeJob shared by the operation jobs
+func (j *OSUpgradeJob) GetCommonJobStatus() *CommonJobStatus {
+	return &j.Status.CommonJobStatus
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// OSUpgradeJobList is a list of OSUpgradeJob.
+type OSUpgradeJobList struct {
+	// Standard type metadata.
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard list metadata.
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of OSUpgradeJob.
+	Items []OSUpgradeJob `json:"items"`
+}
+
+// OSUpgradeJobSpec represents the specification of the desired behavior of OSUpgradeJob.
+type OSUpgradeJobSpec struct {
+	// CommonJobSpec selects the nodes of the job and bounds how it runs on them.
+	CommonJobSpec `json:",inline"`
+
+	// Artifact is the OS or firmware update the hook applies on each node.
+	// +required
+	Artifact OSUpgradeArtifact `json:"artifact"`
+
+	// Hook references the hook script installed on each node which applies the artifact.
+	// +required
+	Hook OSUpgradeHook `json:"hook"`
+
+	// PostCheck verifies each node from cloud once the artifact is applied and the node is back, the
+	// node succeeds once it is Ready and runs the critical pods.
+	// The default PostCheck value is nil, which only waits for the node to be Ready for 300 seconds.
+	// +optional
+	PostCheck *PostCheckSpec `json:"postCheck,omitempty"`
+}
+
+// OSUpgradeArtifact is the OS or firmware update applied on the edge nodes
+type OSUpgradeArtifact struct {
+	// URL is where each node downloads the artifact from, over http or https.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// SHA256 is the hex encoded SHA-256 digest of the artifact, the node fails if the artifact it
+	// downloaded does not match it.
+	// +kubebuilder:validation:Pattern=`^[a-f0-9]{64}$`
+	SHA256 string `json:"sha256"`
+
+	// Version is the version of the OS or firmware the artifact updates the node to, it is passed
+	// to the hook.
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// OSUpgradeHook references the hook script which applies the artifact on the edge nodes. The hook is
+// installed in /etc/kubeedge/hooks on each node along with its signature, <name>.sig, the base64
+// encoded ed25519 signature of the script by one of the keys trusted by the node in
+// /etc/kubeedge/hooks/trusted-keys.pem. The node fails if the signature of the hook is not verified.
+// The hook is run with the path of the artifact as argument, it exits 0 once the artifact is applied,
+// or 100 to have the host rebooted to complete the update.
+type OSUpgradeHook struct {
+	// Name is the file name of the hook script in /etc/kubeedge/hooks.
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9][A-Za-z0-9._-]*$`
+	Name string `json:"name"`
+
+	// SHA256 pins the hex encoded SHA-256 digest of the hook script, the node fails if its hook does
+	// not match it.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-f0-9]{64}$`
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// OSUpgradeJobStatus stores the status of OSUpgradeJob.
+// The running state of OSUpgradeJob is OSUpgrading, its events are OSUpgrade, Reboot, Reconnect and PostCheck besides Init and TimeOut.
+// +kubebuilder:validation:Type=object
+type OSUpgradeJobStatus struct {
+	// CommonJobStatus is the state of the job and of each of its nodes.
+	CommonJobStatus `json:",inline"`
+}
@@ -67,6 +67,22 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&NodeUpgradeJobList{},
 		&ImagePrePullJob{},
 		&ImagePrePullJobList{},
+		&SupportBundleJob{},
+		&SupportBundleJobList{},
+		&ConfigUpdateJob{},
+		&ConfigUpdateJobList{},
+		&NodeRestartJob{},
+		&NodeRestartJobList{},
+		&DiagnoseJob{},
+		&DiagnoseJobList{},
+		&BackupJob{},
+		&BackupJobList{},
+		&RestoreJob{},
+		&RestoreJobList{},
+		&OSUpgradeJob{},
+		&OSUpgradeJobList{},
+		&TaskTypeConfig{},
+		&TaskTypeConfigList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RestoreJob restores the backup taken by a BackupJob on edge nodes, the local database of edgecore,
+// its config and its certificates. Edgecore is stopped while they are restored and started again,
+// the edge nodes must run edgecore with systemd.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+type RestoreJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec represents the specification of the desired behavior of RestoreJob.
+	// +required
+	Spec RestoreJobSpec `json:"spec"`
+
+	// Status represents the status of RestoreJob.
+	// +optional
+	Status RestoreJobStatus `json:"status,omitempty"`
+}
+
+// GetCommonJobSpec returns the part of the spec of the RestoreJob shared by the operation jobs
+func (j *RestoreJob) GetCommonJobSpec() *CommonJobSpec {
+	return &j.Spec.CommonJobSpec
+}
+
+// GetCommonJobStatus returns the part of the status of the RestoreJob shared by the operation jobs
+func (j *RestoreJob) GetCommonJobStatus() *CommonJobStatus {
+	return &j.Status.CommonJobStatus
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RestoreJobList is a list of RestoreJob.
+type RestoreJobList struct {
+	// Standard type metadata.
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard list metadata.
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of RestoreJob.
+	Items []RestoreJob `json:"items"`
+}
+
+// RestoreJobSpec represents the specification of the desired behavior of RestoreJob.
+type RestoreJobSpec struct {
+	// CommonJobSpec selects the nodes of the job and bounds how it runs on them.
+	CommonJobSpec `json:",inline"`
+
+	// BackupJobName is the BackupJob whose backup is restored on each node, the nodes it did not back
+	// up fail.
+	// +required
+	BackupJobName string `json:"backupJobName"`
+
+	// Location is the directory on each node the BackupJob wrote its backup to, it must be the
+	// Location of the BackupJob. The default Location value is /etc/kubeedge/backup/jobs.
+	// +optional
+	Location string `json:"location,omitempty"`
+}
+
+// RestoreJobStatus stores the status of RestoreJob.
+// The running state of RestoreJob is Restoring, its events are Restore besides Init and TimeOut.
+// +kubebuilder:validation:Type=object
+type RestoreJobStatus struct {
+	// CommonJobStatus is the state of the job and of each of its nodes.
+	CommonJobStatus `json:",inline"`
+}
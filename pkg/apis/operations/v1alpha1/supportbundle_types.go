@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SupportBundleJob collects a support bundle from edge nodes and cloudcore, to be attached to bug reports.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+type SupportBundleJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec represents the specification of the desired behavior of SupportBundleJob.
+	// +required
+	Spec SupportBundleJobSpec `json:"spec"`
+
+	// Status represents the status of SupportBundleJob.
+	// +optional
+	Status SupportBundleJobStatus `json:"status,omitempty"`
+}
+
+// GetCommonJobSpec returns the part of the spec of the SupportBundleJob shared by the operation jobs
+func (j *SupportBundleJob) GetCommonJobSpec() *CommonJobSpec {
+	return &j.Spec.CommonJobSpec
+}
+
+// GetCommonJobStatus returns the part of the status of the SupportBundleJob shared by the operation jobs
+func (j *SupportBundleJob) GetCommonJobStatus() *CommonJobStatus {
+	return &j.Status.CommonJobStatus
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SupportBundleJobList is a list of SupportBundleJob.
+type SupportBundleJobList struct {
+	// Standard type metadata.
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard list metadata.
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of SupportBundleJob.
+	Items []SupportBundleJob `json:"items"`
+}
+
+// SupportBundleJobSpec represents the specification of the desired behavior of SupportBundleJob.
+type SupportBundleJobSpec struct {
+	// CommonJobSpec selects the nodes of the job and bounds how it runs on them.
+	CommonJobSpec `json:",inline"`
+
+	// LogLines is the number of the most recent log lines of edgecore and cloudcore in the bundle.
+	// The default LogLines value is 1000.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	LogLines int32 `json:"logLines,omitempty"`
+}
+
+// SupportBundleJobStatus stores the status of SupportBundleJob.
+// The running state of SupportBundleJob is Collecting, its events are Collect besides Init and TimeOut.
+// +kubebuilder:validation:Type=object
+type SupportBundleJobStatus struct {
+	// CommonJobStatus is the state of the job and of each of its nodes.
+	CommonJobStatus `json:",inline"`
+
+	// Archive is the path of the bundle archive on the host of the cloudcore that packaged it,
+	// it is set once the job is finished. The archive can be downloaded from the
+	// /task/supportbundle/name/{name}/bundle endpoint of that cloudcore.
+	// +optional
+	Archive string `json:"archive,omitempty"`
+
+	// Artifacts are the bundles collected from the edge nodes, each of them can be downloaded on its own.
+	// +optional
+	Artifacts []NodeArtifact `json:"artifacts,omitempty"`
+}
+
+// NodeArtifact is an artifact collected from an edge node
+type NodeArtifact struct {
+	// NodeName is the name of the edge node the artifact is collected from.
+	NodeName string `json:"nodeName"`
+	// URL locates the artifact. It is the URL of the object in the object store if TaskManager uploads
+	// the bundles, the path of the /task/supportbundle/name/{name}/node/{node}/bundle endpoint of the
+	// cloudcore which stores the artifact otherwise.
+	URL string `json:"url"`
+	// Size is the size of the artifact in bytes.
+	// +optional
+	Size int64 `json:"size,omitempty"`
+}
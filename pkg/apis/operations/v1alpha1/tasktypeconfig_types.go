@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TaskTypeConfig overrides the default pipeline of a task type, i.e. the defaults the jobs of the type
+// run with when they do not set them. Its name is the task type it overrides, e.g. upgrade for
+// NodeUpgradeJob or prepull for ImagePrePullJob. The defaults are applied when the executor of a job
+// starts, a change applies to the jobs started afterwards and to the jobs resumed once cloudcore restarted.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Accepted",type=boolean,JSONPath=`.status.accepted`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type TaskTypeConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the defaults of the task type which override the defaults it registered.
+	// +required
+	Spec TaskTypeConfigSpec `json:"spec"`
+
+	// Status is whether the override is accepted.
+	// +optional
+	Status TaskTypeConfigStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TaskTypeConfigList is a list of TaskTypeConfig.
+type TaskTypeConfigList struct {
+	// Standard type metadata.
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard list metadata.
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of TaskTypeConfig.
+	Items []TaskTypeConfig `json:"items"`
+}
+
+// TaskTypeConfigSpec is the defaults of a task type, the fields which are not set keep the default
+// registered by the task type.
+type TaskTypeConfigSpec struct {
+	// TimeoutSeconds limits the duration of each stage on each node for the jobs which set no timeout.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *uint32 `json:"timeoutSeconds,omitempty"`
+
+	// StageTimeouts limits the duration of the stages on each node for the jobs which set no timeout,
+	// keyed by the state of the nodes in the stage, e.g. Upgrading. The stages of the task type are
+	// listed in the status.
+	// +optional
+	StageTimeouts map[string]uint32 `json:"stageTimeouts,omitempty"`
+
+	// Concurrency is the maximum number of nodes operated on at the same time for the jobs which set
+	// no concurrency.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Concurrency *int32 `json:"concurrency,omitempty"`
+
+	// FailureTolerate is the failure tolerance of the jobs which set none, a number of nodes or a
+	// percentage of the nodes, e.g. 10%.
+	// +optional
+	// +kubebuilder:validation:XIntOrString
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Pattern=`^([0-9]|[1-9][0-9]|100)%$`
+	FailureTolerate *intstr.IntOrString `json:"failureTolerate,omitempty"`
+
+	// CheckItems are the checks run on each node before it is operated on for the jobs which set no
+	// check item, e.g. cpu, mem, disk. Only the task types with a Checking stage run them.
+	// +optional
+	CheckItems []string `json:"checkItems,omitempty"`
+
+	// PostCheck verifies each node from cloud once it is operated on for the jobs which set no post
+	// check. Only the task types which verify their nodes from cloud run it.
+	// +optional
+	PostCheck *PostCheckSpec `json:"postCheck,omitempty"`
+}
+
+// TaskTypeConfigStatus is whether the override of the task type is accepted
+type TaskTypeConfigStatus struct {
+	// Accepted is set once the override is validated against the task type, the jobs keep the
+	// defaults registered by the task type if it is not accepted.
+	Accepted bool `json:"accepted"`
+
+	// Reason is why the override is not accepted.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Stages are the states of the nodes in the stages of the task type, in order.
+	// +optional
+	Stages []string `json:"stages,omitempty"`
+
+	// ObservedGeneration is the generation of the spec the status is computed from.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
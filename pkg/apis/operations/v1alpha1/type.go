@@ -17,7 +17,10 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
 )
@@ -30,6 +33,14 @@ import (
 // +k8s:openapi-gen=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.spec.version`
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+// +kubebuilder:printcolumn:name="Progress",type=integer,JSONPath=`.status.progress.percentComplete`,description="The percentage of the nodes which finished"
+// +kubebuilder:printcolumn:name="Succeeded",type=integer,JSONPath=`.status.progress.succeeded`
+// +kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.progress.failed`
+// +kubebuilder:printcolumn:name="Total",type=integer,JSONPath=`.status.progress.total`,priority=1
+// +kubebuilder:printcolumn:name="Paused Until",type=string,JSONPath=`.status.maintenanceWindow.pausedUntil`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 type NodeUpgradeJob struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -65,6 +76,10 @@ type NodeUpgradeJobSpec struct {
 	// If set to 0, we'll use the default value 300.
 	// +optional
 	TimeoutSeconds *uint32 `json:"timeoutSeconds,omitempty"`
+	// Timeouts limits the duration of each stage of the node upgrade job separately,
+	// the stages without a timeout are limited by TimeoutSeconds.
+	// +optional
+	Timeouts *StageTimeouts `json:"timeouts,omitempty"`
 	// NodeNames is a request to select some specific nodes. If it is non-empty,
 	// the upgrade job simply select these edge nodes to do upgrade operation.
 	// Please note that sets of NodeNames and LabelSelector are ORed.
@@ -77,6 +92,11 @@ type NodeUpgradeJobSpec struct {
 	// Users must set one and can only set one.
 	// +optional
 	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+	// NodeGroups selects the members of the KubeEdge NodeGroups with these names, in addition to
+	// the nodes selected by NodeNames or LabelSelector. The members are resolved when the job starts,
+	// and again when it is resumed if it is paused before any node is dispatched.
+	// +optional
+	NodeGroups []string `json:"nodeGroups,omitempty"`
 	// Image specifies a container image name, the image contains: keadm and edgecore.
 	// keadm is used as upgradetool, to install the new version of edgecore.
 	// The image name consists of registry hostname and repository name,
@@ -85,26 +105,631 @@ type NodeUpgradeJobSpec struct {
 	// The default image name is: kubeedge/installation-package.
 	// +optional
 	Image string `json:"image,omitempty"`
-	// Concurrency specifies the max number of edge nodes that can be upgraded at the same time.
+	// Concurrency specifies the max number of edge nodes that can be upgraded at the same time,
+	// or the max percentage of the nodes of the job, e.g. 5%. A percentage is rounded down, one node
+	// is upgraded at least. It is computed when the job starts and again when nodes are skipped or removed.
+	// It can be updated while the job is running, the nodes being upgraded finish their upgrade if it is lowered.
 	// The default Concurrency value is 1.
 	// +optional
-	Concurrency int32 `json:"concurrency,omitempty"`
+	// +kubebuilder:validation:XIntOrString
+	Concurrency *intstr.IntOrString `json:"concurrency,omitempty"`
+
+	// RollingStrategy sizes the batches of edge nodes upgraded at the same time from the number of nodes
+	// of the job, it takes precedence over Concurrency. It can be updated while the job is running.
+	// +optional
+	RollingStrategy *RollingStrategy `json:"rollingStrategy,omitempty"`
+
+	// DispatchJitterSeconds is the upper bound of the random delay before the job is dispatched to each
+	// edge node, it keeps the nodes sharing upstream infrastructure from starting the download at once.
+	// The default DispatchJitterSeconds value is 0, which dispatches without delay.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	DispatchJitterSeconds int32 `json:"dispatchJitterSeconds,omitempty"`
+
+	// Preflight probes the edge nodes over the task channel before the job is dispatched to them,
+	// the nodes which do not answer are failed right away instead of taking a worker until they time out.
+	// The default Preflight value is nil, which dispatches the job without probing the nodes.
+	// +optional
+	Preflight *PreflightSpec `json:"preflight,omitempty"`
 
 	// CheckItems specifies the items need to be checked before the task is executed.
-	// The default CheckItems value is nil.
+	// The default CheckItems value is nil. The check items CloudCore selects for the version
+	// are checked as well, see versionCheckItems of the TaskManager config.
+	// The check item cert fails the nodes whose edgehub certificate expires within 7 days, and warns
+	// about the certificates which expire within 30 days. The check item registry fails the nodes
+	// which cannot pull the image from its registry within the timeout of the upgrade, estimated from
+	// the latency of the registry and the throughput of the download of the largest layer.
 	// +optional
 	CheckItems []string `json:"checkItems,omitempty"`
 
-	// FailureTolerate specifies the task tolerance failure ratio.
-	// The default FailureTolerate value is 0.1.
+	// RotateExpiringCertificate requests the rotation of the edgehub certificate of the nodes which expires
+	// within 30 days in the check item cert, instead of failing them, so that the nodes do not drop offline
+	// during the rollout. The check does not renew the certificate, edgehub rotates it and reconnects with
+	// the new one. It takes effect only if cert is checked and certificate rotation of edgehub is enabled.
+	// The default RotateExpiringCertificate value is false.
+	// +optional
+	RotateExpiringCertificate bool `json:"rotateExpiringCertificate,omitempty"`
+
+	// FailureTolerate specifies how many of the nodes of the job can fail, a number of nodes or a percentage
+	// of the nodes, e.g. 10%. The job fails once more nodes than it fail, e.g. it fails on the 4th failed node
+	// if it is 3 and on the first failed node if it is 0. A ratio, e.g. 0.1, is still accepted but deprecated.
+	// It can be updated while the job is running, a job which already failed is not resumed by raising it.
+	// The default FailureTolerate value is 10%.
+	// +optional
+	// +kubebuilder:validation:XIntOrString
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Pattern=`^([0-9]|[1-9][0-9]|100)%$|^0(\.[0-9]+)?$|^1(\.0+)?$`
+	FailureTolerate *intstr.IntOrString `json:"failureTolerate,omitempty"`
+
+	// Preset is the name of a bundled upgrade strategy, one of conservative, balanced and fast.
+	// The preset fills Concurrency, FailureTolerate and TimeoutSeconds when they are not specified,
+	// values specified in the job always take precedence.
+	// +optional
+	// +kubebuilder:validation:Enum=conservative;balanced;fast
+	Preset UpgradePreset `json:"preset,omitempty"`
+
+	// Verification specifies the probes run on the edge node after EdgeCore is upgraded.
+	// The node is failed and rolled back if any of the probes fails.
+	// +optional
+	Verification *VerificationSpec `json:"verification,omitempty"`
+
+	// PostCheck verifies each edge node from cloud once it reports it is upgraded, the node is Verifying
+	// until it is Ready, reports Version and runs the critical pods, and it succeeds then. The node is
+	// failed if it is not verified in time.
+	// +optional
+	PostCheck *PostCheckSpec `json:"postCheck,omitempty"`
+
+	// RollbackPolicy decides what happens to the nodes already upgraded once the job exceeds its failure
+	// tolerance. None keeps them upgraded, Automatic rolls them back before the job fails.
+	// The default RollbackPolicy value is None.
+	// +optional
+	// +kubebuilder:validation:Enum=None;Automatic
+	RollbackPolicy RollbackPolicy `json:"rollbackPolicy,omitempty"`
+
+	// Promotion chains the upgrade to the next node group once this job succeeded and soaked.
+	// Setting the annotation operations.kubeedge.io/abort-promotion to "true" aborts the promotion.
+	// +optional
+	Promotion *PromotionSpec `json:"promotion,omitempty"`
+
+	// Paused stops dispatching the job to new edge nodes, the nodes being upgraded finish their upgrade.
+	// Setting it back to false resumes the job from the next node not upgraded yet.
+	// Paused, Apply, Concurrency, RollingStrategy, FailureTolerate and the approval of the canary are the only fields
+	// of the spec which can be updated once the job is created.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// PauseOn pauses the job by itself once one of its conditions matches, so that the rollout stops
+	// before it exceeds its failure tolerance. The job is paused by setting Paused, setting it back to
+	// false resumes the job and the conditions are evaluated again from then on.
+	// +optional
+	PauseOn *PauseOnSpec `json:"pauseOn,omitempty"`
+
+	// Apply splits the upgrade into staging and applying. The nodes are staged at any time, i.e. the
+	// installation package is downloaded and the nodes are checked, while EdgeCore is backed up, upgraded
+	// and restarted only when the upgrade is applied. The upgrade is applied at once if it is not set.
+	// +optional
+	Apply *ApplySpec `json:"apply,omitempty"`
+
+	// Canary upgrades a canary set of the edge nodes first. The job then waits in the WaitingApproval
+	// state until it is approved, the other nodes are upgraded once Canary.Approve is set to true.
+	// +optional
+	Canary *CanarySpec `json:"canary,omitempty"`
+
+	// DrainNodeBeforeUpgrade cordons each node and evicts its pods before it is upgraded, so that the
+	// workloads are moved away before EdgeCore restarts. The node is uncordoned once it is upgraded
+	// successfully, it stays cordoned if the upgrade fails.
+	// +optional
+	DrainNodeBeforeUpgrade *DrainSpec `json:"drainNodeBeforeUpgrade,omitempty"`
+
+	// Ordering decides the order the nodes are upgraded in, Default, Random or Readiness. Random shuffles
+	// the nodes, so that the first nodes and their failures sample the fleet instead of being the same sites
+	// every time. Readiness upgrades the most ready nodes first, so that the riskiest nodes do not abort the
+	// job early. The canary nodes are still upgraded first.
+	// +optional
+	// +kubebuilder:validation:Enum=Default;Random;Readiness
+	Ordering NodeOrdering `json:"ordering,omitempty"`
+
+	// OrderingSeed seeds the Random ordering, a random seed is picked if it is not set. The seed is
+	// recorded in status.orderingSeed, a job selecting the same nodes with the same seed upgrades them
+	// in the same order.
 	// +optional
-	FailureTolerate string `json:"failureTolerate,omitempty"`
+	OrderingSeed *int64 `json:"orderingSeed,omitempty"`
+
+	// ActiveDeadlineSeconds bounds the duration of the job from the time cloudcore starts running it,
+	// it is finished DeadlineExceeded once the deadline is exceeded and the nodes still running it are
+	// cancelled. It is measured by the monotonic clock of the cloudcore running the job, it counts again
+	// from the time another cloudcore takes over the job or cloudcore restarts.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// TTLSecondsAfterFinished limits the lifetime of the job once it finished, the job is deleted the
+	// given seconds after it reaches a terminal state. The job is kept if it is not set.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+
+	// DryRun resolves the nodes of the job and runs the check items on them without upgrading any node.
+	// The nodes also check they could be upgraded, i.e. the container runtime keadm is pulled with is
+	// reachable, edgecore runs as root, enough disk is free and the service manager is detected, and
+	// report what they found in the reason of their status.
+	// The nodes which pass the checks succeed, the plan of the upgrade is recorded in status.plan.
+	// DryRun can be changed together with Rerun only, e.g. to run the upgrade once the dry run succeeded.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Metadata is the custom metadata of the job, e.g. the ID of an external ticket. It is passed to the
+	// commands run for the job on the edge nodes in the environment variables KUBEEDGE_TASK_METADATA_<key>,
+	// and echoed back with the result of each node in its status. The keys must be valid environment
+	// variable names.
+	// +optional
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// RenderPayload renders the Go templates in the string fields of the request sent to each node
+	// with the variables of the node, e.g. {{ .NodeName }} or {{ index .Labels "site" }}. The request
+	// is sent as is unless it is set.
+	// +optional
+	RenderPayload bool `json:"renderPayload,omitempty"`
+
+	// Cohorts label the nodes of the job with the cohort of their outcome once the job is finished, e.g.
+	// upgrade-result=failed-v1.17.1, so that later jobs select the cohorts with their labelSelector.
+	// The nodes of a dry run are not labeled.
+	// +optional
+	Cohorts *NodeCohortSpec `json:"cohorts,omitempty"`
+
+	// Notifications are sent once the job finishes and once its failed nodes exceed its failure
+	// tolerance, so that CI/CD pipelines react to the job without polling it.
+	// +optional
+	Notifications []NotificationSink `json:"notifications,omitempty"`
+
+	// Credential mints a short-lived token for each node of the job, it is passed to the commands run
+	// for the job on the node.
+	// +optional
+	Credential *TaskCredentialSpec `json:"credential,omitempty"`
+
+	// Rerun runs the finished job again when it is changed. Any other change of the spec of a
+	// finished job is ignored, re-applying it is a no-op unless Rerun is changed as well.
+	// +optional
+	Rerun int64 `json:"rerun,omitempty"`
+
+	// VersionMappings upgrade the nodes selected by each mapping to the version of the mapping,
+	// e.g. ARM gateways to one version and x86 servers to another. The job then runs a NodeUpgradeJob
+	// per mapping with the rest of its spec and tracks their outcome instead of upgrading nodes itself,
+	// Version, NodeNames, LabelSelector and NodeGroups must not be set. A node selected by several
+	// mappings is upgraded by the first of them.
+	// +optional
+	VersionMappings []VersionMapping `json:"versionMappings,omitempty"`
+
+	// Inputs bind names to the outputs of other jobs, e.g. to the nodes a dry run found upgradable.
+	// A string of the spec references an input as $(inputs.<name>), an item of a list which is exactly
+	// a reference is replaced by the comma separated items of the input, e.g. nodeNames: ["$(inputs.nodes)"].
+	// The job waits until the jobs it references are finished, the inputs are resolved once when it starts.
+	// +optional
+	Inputs []JobInput `json:"inputs,omitempty"`
+}
+
+// VersionMapping maps the nodes selected by a label selector to the version they are upgraded to.
+type VersionMapping struct {
+	// Name identifies the mapping, the NodeUpgradeJob of the mapping is named <job>-<name>.
+	Name string `json:"name"`
+	// LabelSelector selects the nodes upgraded to the version of the mapping.
+	LabelSelector *metav1.LabelSelector `json:"labelSelector"`
+	// Version is the EdgeCore version the selected nodes are upgraded to.
+	Version string `json:"version"`
+	// Image overrides the Image of the job for the selected nodes.
+	// +optional
+	Image string `json:"image,omitempty"`
 }
 
+// RollingStrategy describes how many edge nodes of a job are upgraded at the same time.
+type RollingStrategy struct {
+	// MaxUnavailable is the max number of edge nodes upgraded at the same time, or the max percentage
+	// of the nodes of the job, e.g. 10%. A percentage is rounded down, one node is upgraded at least.
+	// The batch size follows the number of nodes still part of the job, e.g. when nodes are removed.
+	// +kubebuilder:validation:XIntOrString
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable"`
+}
+
+// PauseOnSpec declares the conditions which pause a job by themselves. The failed nodes are counted
+// from the time the job starts or is resumed.
+type PauseOnSpec struct {
+	// FailedNodes pauses the job once the given number of nodes failed.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	FailedNodes *int32 `json:"failedNodes,omitempty"`
+	// FailureRate pauses the job once the failed nodes reach a percentage of the nodes finished within a window.
+	// +optional
+	FailureRate *FailureRateRule `json:"failureRate,omitempty"`
+	// ExternalAlerts pause the job once one of the alerts with the given names fires, e.g. an SLO alert
+	// of the workloads. The alerts are posted to cloudcore by an Alertmanager webhook receiver at /task/alerts.
+	// +optional
+	ExternalAlerts []string `json:"externalAlerts,omitempty"`
+}
+
+// FailureRateRule matches once the failed nodes reach Rate of the nodes which finished within the
+// last WindowSeconds.
+type FailureRateRule struct {
+	// Rate is the percentage of the finished nodes which failed, e.g. 10%.
+	// +kubebuilder:validation:Pattern=`^[0-9]+%$`
+	Rate string `json:"rate"`
+	// WindowSeconds is how far back the finished nodes are counted.
+	// The default WindowSeconds value is 600.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	WindowSeconds *int32 `json:"windowSeconds,omitempty"`
+	// MinNodes is how many nodes must finish within the window before the rate is evaluated, so that
+	// the first failures of the job do not pause it at once.
+	// The default MinNodes value is 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MinNodes *int32 `json:"minNodes,omitempty"`
+}
+
+// StageTimeouts limits the duration of the stages of the job on each edge node, in seconds.
+// A stage is limited by TimeoutSeconds of the job if its timeout is not set or is 0.
+type StageTimeouts struct {
+	// Check limits the pre-checks of the node.
+	// +optional
+	Check *uint32 `json:"check,omitempty"`
+	// Backup limits the backup of the node before it is upgraded.
+	// +optional
+	Backup *uint32 `json:"backup,omitempty"`
+	// Upgrade limits the upgrade of the node, including the download of the installation package.
+	// +optional
+	Upgrade *uint32 `json:"upgrade,omitempty"`
+	// Rollback limits the rollback of the node once its upgrade fails or the job is reverted.
+	// +optional
+	Rollback *uint32 `json:"rollback,omitempty"`
+}
+
+// NodeOrdering decides the order the nodes of a job are operated on in.
+type NodeOrdering string
+
+const (
+	// NodeOrderingDefault operates on the nodes in the order they are selected
+	NodeOrderingDefault NodeOrdering = "Default"
+	// NodeOrderingRandom operates on the nodes in a random order
+	NodeOrderingRandom NodeOrdering = "Random"
+	// NodeOrderingReadiness operates on the nodes from the most ready to the least ready, the readiness
+	// of a node is scored from the stability of its connection, its disk headroom, its recent failures
+	// and its battery level if it is reported
+	NodeOrderingReadiness NodeOrdering = "Readiness"
+)
+
+// DrainSpec decides how the pods of a node are evicted before the node is upgraded.
+type DrainSpec struct {
+	// PodSelector selects the pods evicted from the node, all the pods are evicted by default.
+	// Mirror pods and the pods which are finished are never evicted.
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+	// EvictDaemonSetPods evicts the pods managed by DaemonSets too, they are left on the node by
+	// default as DaemonSets tolerate the node being unschedulable.
+	// +optional
+	EvictDaemonSetPods bool `json:"evictDaemonSetPods,omitempty"`
+	// GracePeriodSeconds overrides the termination grace period of the evicted pods.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty"`
+	// TimeoutSeconds limits how long the pods are evicted and waited for to terminate, the upgrade
+	// of the node fails if they are not gone in time, e.g. when a PodDisruptionBudget blocks the eviction.
+	// Default to 300.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// PreflightSpec describes the probe of the edge nodes of a job before the job is dispatched to them.
+type PreflightSpec struct {
+	// TimeoutSeconds is how long the nodes have to answer the probe, the nodes which do not answer
+	// in time are failed as unreachable.
+	// Default to 10.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// CanarySpec selects the canary nodes of a job, either by number or by label selector.
+type CanarySpec struct {
+	// Nodes is the number of canary nodes, they are the first nodes of the job by name.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Nodes int32 `json:"nodes,omitempty"`
+	// LabelSelector selects the canary nodes among the nodes of the job. It is exclusive with Nodes.
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+	// Approve upgrades the other nodes of the job once the canary nodes are upgraded.
+	// +optional
+	Approve bool `json:"approve,omitempty"`
+}
+
+// ApplySpec describes when a staged upgrade is applied to the edge nodes.
+type ApplySpec struct {
+	// Hold keeps the upgrade staged, it is applied once Hold is set back to false.
+	// +optional
+	Hold bool `json:"hold,omitempty"`
+	// MaintenanceWindows are the daily windows the upgrade is applied in, the nodes not applied
+	// when a window closes wait for the next one. The upgrade is applied at any time if it is empty.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// MaintenanceWindowStartLayout is the layout of the start of a maintenance window
+const MaintenanceWindowStartLayout = "15:04"
+
+// MaintenanceWindow is a daily window in which disruptive operations are allowed on the edge nodes.
+type MaintenanceWindow struct {
+	// Start is the time of the day the window opens, in UTC in the format HH:MM.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+	// DurationMinutes is how long the window stays open.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1440
+	DurationMinutes int32 `json:"durationMinutes"`
+}
+
+// PromotionSpec describes when and where a completed NodeUpgradeJob is promoted.
+type PromotionSpec struct {
+	// NodeGroup is the node group the upgrade is promoted to. A NodeUpgradeJob with the same spec
+	// selecting the nodes of the group is created on promotion.
+	NodeGroup string `json:"nodeGroup"`
+	// SuccessThreshold is the minimum ratio of successful nodes required for the promotion, e.g. "0.98".
+	// The default SuccessThreshold value is 1.
+	// +optional
+	SuccessThreshold string `json:"successThreshold,omitempty"`
+	// SoakSeconds is how long the job stays completed before it is promoted.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	SoakSeconds int64 `json:"soakSeconds,omitempty"`
+}
+
+// PromotionPhase is the outcome of the promotion of a NodeUpgradeJob.
+type PromotionPhase string
+
+const (
+	// PromotionPromoted means the NodeUpgradeJob of the next node group is created.
+	PromotionPromoted PromotionPhase = "Promoted"
+	// PromotionHalted means the job did not meet the success threshold of the promotion.
+	PromotionHalted PromotionPhase = "Halted"
+	// PromotionAborted means the promotion was aborted by the user.
+	PromotionAborted PromotionPhase = "Aborted"
+)
+
+// MaintenanceWindowStatus describes a NodeUpgradeJob waiting for its next maintenance window, the
+// progress of the job is kept in the node status and the job resumes once the window opens.
+type MaintenanceWindowStatus struct {
+	// PausedUntil is the time the next maintenance window opens.
+	PausedUntil string `json:"pausedUntil"`
+	// RemainingNodes is the number of nodes which have not finished the upgrade.
+	RemainingNodes int32 `json:"remainingNodes"`
+}
+
+// PromotionStatus is the outcome of the promotion of a NodeUpgradeJob.
+type PromotionStatus struct {
+	// Phase is one of Promoted, Halted and Aborted.
+	Phase PromotionPhase `json:"phase"`
+	// Job is the name of the NodeUpgradeJob created for the next node group.
+	// +optional
+	Job string `json:"job,omitempty"`
+	// Reason explains why the promotion was halted or aborted.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Time is when the promotion was decided.
+	// +optional
+	Time string `json:"time,omitempty"`
+}
+
+// UpgradePreset is the name of a bundled upgrade strategy.
+type UpgradePreset string
+
+const (
+	// UpgradePresetConservative upgrades one node at a time and tolerates no failure.
+	UpgradePresetConservative UpgradePreset = "conservative"
+	// UpgradePresetBalanced upgrades a few nodes at a time and tolerates some failures.
+	UpgradePresetBalanced UpgradePreset = "balanced"
+	// UpgradePresetFast upgrades many nodes at a time and tolerates more failures.
+	UpgradePresetFast UpgradePreset = "fast"
+)
+
+// RollbackPolicy decides whether the nodes upgraded by a failed NodeUpgradeJob are rolled back.
+type RollbackPolicy string
+
+const (
+	// RollbackPolicyNone keeps the upgraded nodes upgraded when the job fails.
+	RollbackPolicyNone RollbackPolicy = "None"
+	// RollbackPolicyAutomatic rolls the upgraded nodes back when the job exceeds its failure tolerance.
+	RollbackPolicyAutomatic RollbackPolicy = "Automatic"
+)
+
+// NodeCohortSpec describes the labels set on the nodes of a job once it is finished. The labels of
+// the other outcome are removed from a node, e.g. a node which failed a former run of the job is no
+// longer labeled with FailedLabels once it succeeds.
+type NodeCohortSpec struct {
+	// Labels are set on all the nodes the job operated on, e.g. upgrade-wave=1.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// SucceededLabels are set on the nodes which succeeded, e.g. upgrade-result=succeeded-v1.17.1.
+	// +optional
+	SucceededLabels map[string]string `json:"succeededLabels,omitempty"`
+	// FailedLabels are set on the nodes which failed, e.g. upgrade-result=failed-v1.17.1.
+	// +optional
+	FailedLabels map[string]string `json:"failedLabels,omitempty"`
+}
+
+// NotificationSink is where the notifications of a job are sent. Every notification is recorded as an
+// event of the job, a sink without webhook only records it.
+type NotificationSink struct {
+	// Name identifies the sink in the events of the job.
+	Name string `json:"name"`
+	// Webhook posts the notifications to an HTTP endpoint.
+	// +optional
+	Webhook *NotificationWebhook `json:"webhook,omitempty"`
+}
+
+// NotificationWebhook posts the notifications of a job as JSON to an HTTP endpoint, a notification
+// which cannot be posted is retried a few times and dropped then.
+type NotificationWebhook struct {
+	// URL is the endpoint the notifications are posted to.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+	// TimeoutSeconds limits each request to the endpoint.
+	// The default TimeoutSeconds value is 10.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// TaskCredentialSpec asks cloud to mint a short-lived token of a service account for each node of a
+// job, so that the commands run for the job on the node, e.g. to cordon the node or query the
+// metaserver, do not rely on the long-lived credentials of the node. The token is minted each time a
+// stage of the job is dispatched to the node and delivered encrypted for the node in the task. It is
+// passed to the commands in the environment variables KUBEEDGE_TASK_TOKEN and
+// KUBEEDGE_TASK_TOKEN_EXPIRATION.
+type TaskCredentialSpec struct {
+	// ServiceAccountName is the service account the tokens are minted for, its role bindings scope
+	// what the tokens are allowed to do. It must be listed in credentialServiceAccounts of cloudcore,
+	// and the creator of the job must be allowed to create the tokens of the service account.
+	ServiceAccountName string `json:"serviceAccountName"`
+	// Namespace is the namespace of the service account.
+	// The default Namespace value is kubeedge.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Audiences are the intended audiences of the tokens, they are the audiences of the apiserver if empty.
+	// +optional
+	Audiences []string `json:"audiences,omitempty"`
+	// ExpirationSeconds is how long the tokens are valid for.
+	// The default ExpirationSeconds value is 600.
+	// +optional
+	// +kubebuilder:validation:Minimum=600
+	ExpirationSeconds *int64 `json:"expirationSeconds,omitempty"`
+}
+
+// PostCheckSpec describes how cloud verifies an edge node after it is upgraded.
+type PostCheckSpec struct {
+	// CriticalPods select the pods which must be Running on the node, e.g. the pods of a DaemonSet.
+	// +optional
+	CriticalPods []CriticalPodSelector `json:"criticalPods,omitempty"`
+	// TimeoutSeconds limits how long the node is verified.
+	// The default TimeoutSeconds value is 300.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+	// SmokeTest runs a canary pod on the node once it is Ready and runs the critical pods, the node
+	// is verified once the canary is Running and passes its probe, proving the node still runs
+	// workloads. The canary pod is deleted once the node is verified or the post check times out.
+	// +optional
+	SmokeTest *SmokeTestSpec `json:"smokeTest,omitempty"`
+}
+
+// SmokeTestSpec describes the canary pod run on the node by the post check.
+type SmokeTestSpec struct {
+	// Image is the image of the canary container, a small image already present on the nodes
+	// avoids the time to pull it.
+	Image string `json:"image"`
+	// Namespace is the namespace the canary pod is created in.
+	// The default Namespace value is default.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Command is the entrypoint of the canary container, the entrypoint of the image is run if it
+	// is not set.
+	// +optional
+	Command []string `json:"command,omitempty"`
+	// Probe is the readiness probe of the canary container, the canary passes once it is Running
+	// if it is not set.
+	// +optional
+	Probe *VerificationProbe `json:"probe,omitempty"`
+}
+
+// CriticalPodSelector selects the pods of a namespace which must be Running on the upgraded node,
+// at least one pod must be selected.
+type CriticalPodSelector struct {
+	// Namespace is the namespace of the pods.
+	Namespace string `json:"namespace"`
+	// LabelSelector selects the pods by their labels.
+	LabelSelector *metav1.LabelSelector `json:"labelSelector"`
+}
+
+// JobInput binds a name to an output of another job.
+type JobInput struct {
+	// Name is the name the input is referenced by in the spec of the job.
+	Name string `json:"name"`
+	// Kind is the kind of the referenced job.
+	// +kubebuilder:validation:Enum=NodeUpgradeJob;ImagePrePullJob;SupportBundleJob;ConfigUpdateJob;NodeRestartJob;DiagnoseJob;BackupJob;RestoreJob;OSUpgradeJob
+	Kind string `json:"kind"`
+	// JobName is the name of the referenced job.
+	JobName string `json:"jobName"`
+	// Output is the name of the output of the referenced job, e.g. succeededNodes.
+	Output string `json:"output"`
+}
+
+// VerificationSpec describes how an edge node is verified after it is upgraded.
+type VerificationSpec struct {
+	// Probes are run by the edge node against local services.
+	// +optional
+	Probes []VerificationProbe `json:"probes,omitempty"`
+}
+
+// VerificationProbe describes a check that is run on the edge node, similar to a pod probe.
+// Exactly one of Exec, HTTPGet and TCPSocket must be specified.
+type VerificationProbe struct {
+	// Name is the name of the probe, it is used in the verification result.
+	Name string `json:"name"`
+	// Exec specifies a command to run, exit status 0 is treated as success.
+	// +optional
+	Exec *corev1.ExecAction `json:"exec,omitempty"`
+	// HTTPGet specifies an http request to perform, status code in [200, 400) is treated as success.
+	// +optional
+	HTTPGet *corev1.HTTPGetAction `json:"httpGet,omitempty"`
+	// TCPSocket specifies a port to connect to.
+	// +optional
+	TCPSocket *corev1.TCPSocketAction `json:"tcpSocket,omitempty"`
+	// InitialDelaySeconds is the number of seconds to wait after EdgeCore is restarted before the probe is run.
+	// +optional
+	InitialDelaySeconds int32 `json:"initialDelaySeconds,omitempty"`
+	// TimeoutSeconds is the number of seconds after which a single probe attempt times out.
+	// Defaults to 1 second.
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+	// PeriodSeconds is how often (in seconds) the probe is retried.
+	// Defaults to 10 seconds.
+	// +optional
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+	// FailureThreshold is the number of consecutive failed attempts after which the probe fails.
+	// Defaults to 3.
+	// +optional
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+}
+
+// NodeUpgradeJobConditionType is the type of a condition of a NodeUpgradeJob.
+type NodeUpgradeJobConditionType string
+
+const (
+	// NodeUpgradeJobProgressing is true while the job dispatches and upgrades its nodes, it is false
+	// once the job finishes, is paused or waits for the approval of its canary.
+	NodeUpgradeJobProgressing NodeUpgradeJobConditionType = "Progressing"
+	// NodeUpgradeJobFailed is true once the job failed or exceeded its deadline.
+	NodeUpgradeJobFailed NodeUpgradeJobConditionType = "Failed"
+	// NodeUpgradeJobComplete is true once the job upgraded its nodes, some of them may have failed
+	// within the failure tolerance of the job.
+	NodeUpgradeJobComplete NodeUpgradeJobConditionType = "Complete"
+	// NodeUpgradeJobApproved is true once the canary of the job is approved, it is only set for
+	// the jobs with a canary.
+	NodeUpgradeJobApproved NodeUpgradeJobConditionType = "Approved"
+	// NodeUpgradeJobPaused is true while the job is paused.
+	NodeUpgradeJobPaused NodeUpgradeJobConditionType = "Paused"
+)
+
 // NodeUpgradeJobStatus stores the status of NodeUpgradeJob.
 // contains multiple edge nodes upgrade status.
 // +kubebuilder:validation:Type=object
 type NodeUpgradeJobStatus struct {
+	// Conditions are the latest observations of the state of the NodeUpgradeJob, see
+	// NodeUpgradeJobConditionType for their types. They are derived from State, Event, Action
+	// and Reason, which are kept for the compatibility with the existing clients.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
 	// State represents for the state phase of the NodeUpgradeJob.
 	// There are several possible state values: "", Upgrading, BackingUp, RollingBack and Checking.
 	State api.State `json:"state,omitempty"`
@@ -125,6 +750,139 @@ type NodeUpgradeJobStatus struct {
 	Time string `json:"time,omitempty"`
 	// Status contains upgrade Status for each edge node.
 	Status []TaskStatus `json:"nodeStatus,omitempty"`
+
+	// ExcludedNodes are the nodes selected by the job which are not operated on and why, the first
+	// 100 of them are listed.
+	// +optional
+	ExcludedNodes []ExcludedNode `json:"excludedNodes,omitempty"`
+
+	// FailureThreshold is the number of failed nodes the job fails at, it is computed from failureTolerate
+	// and the number of nodes of the job.
+	// +optional
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+	// AffectedWorkloads lists the workloads that have pods running on the nodes to upgrade,
+	// it is computed before the upgrade is executed.
+	// +optional
+	AffectedWorkloads []WorkloadReference `json:"affectedWorkloads,omitempty"`
+	// Cost aggregates the cost of executing the task on the edge nodes.
+	// +optional
+	Cost *TaskCost `json:"cost,omitempty"`
+	// Progress sums up the status of the edge nodes.
+	// +optional
+	Progress *TaskProgress `json:"progress,omitempty"`
+	// MaintenanceWindow is set while the job is paused until its next maintenance window opens.
+	// +optional
+	MaintenanceWindow *MaintenanceWindowStatus `json:"maintenanceWindow,omitempty"`
+	// Promotion is the outcome of the promotion of the job, it is set once the promotion is decided.
+	// +optional
+	Promotion *PromotionStatus `json:"promotion,omitempty"`
+
+	// ObservedRerun is the Rerun of the spec the job last ran with.
+	// +optional
+	ObservedRerun int64 `json:"observedRerun,omitempty"`
+
+	// StartTime is the time the job was last rerun, the job starts at its creation otherwise.
+	// +optional
+	StartTime string `json:"startTime,omitempty"`
+
+	// OrderingSeed is the seed the nodes of the job are shuffled with if its ordering is Random.
+	// +optional
+	OrderingSeed *int64 `json:"orderingSeed,omitempty"`
+
+	// Plan is how the job would upgrade its nodes, it is recorded if the job is a dry run.
+	// +optional
+	Plan *ExecutionPlan `json:"plan,omitempty"`
+
+	// FailureClusters group the failed nodes by the event they failed on, their version, their
+	// architecture and the failureClusterLabels of the TaskManager config, the largest cluster first.
+	// They are computed once the job finishes, the first 10 clusters are listed.
+	// +optional
+	FailureClusters []FailureCluster `json:"failureClusters,omitempty"`
+
+	// VersionMappings are the NodeUpgradeJobs run for the version mappings of the job.
+	// +optional
+	VersionMappings []VersionMappingStatus `json:"versionMappings,omitempty"`
+
+	// Inputs are the values the inputs of the job resolved to when it started.
+	// +optional
+	Inputs map[string]string `json:"inputs,omitempty"`
+
+	// Outputs are the values the job exports once it is finished, other jobs reference them as inputs.
+	// Every job exports succeededNodes and failedNodes, the comma separated names of its succeeded and
+	// failed nodes, a NodeUpgradeJob exports the version it upgraded to as well.
+	// +optional
+	Outputs map[string]string `json:"outputs,omitempty"`
+}
+
+// VersionMappingStatus is the status of the NodeUpgradeJob run for a version mapping.
+type VersionMappingStatus struct {
+	// Name is the name of the version mapping.
+	Name string `json:"name"`
+	// Version is the version the nodes of the mapping are upgraded to.
+	Version string `json:"version,omitempty"`
+	// Job is the name of the NodeUpgradeJob of the mapping, it is empty if the mapping selected no node.
+	// +optional
+	Job string `json:"job,omitempty"`
+	// Nodes is the number of nodes selected by the mapping.
+	Nodes int32 `json:"nodes"`
+	// State is the state of the NodeUpgradeJob of the mapping.
+	// +optional
+	State api.State `json:"state,omitempty"`
+	// Reason is the reason of the state of the NodeUpgradeJob of the mapping.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// FailureCluster is a group of the failed nodes of a task which failed the same way and share attributes.
+type FailureCluster struct {
+	// Reason is the event the nodes failed on, e.g. Check, Upgrade or TimeOut.
+	Reason string `json:"reason"`
+	// Message is the most frequent failure reason of the nodes.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Version is the EdgeCore version the nodes run, i.e. the version they failed to upgrade from.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// Architecture is the CPU architecture of the nodes.
+	// +optional
+	Architecture string `json:"architecture,omitempty"`
+	// Labels are the values the nodes have for the failureClusterLabels of the TaskManager config.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Nodes is the number of nodes in the cluster.
+	Nodes int32 `json:"nodes"`
+	// NodeNames are the first 10 nodes of the cluster by name.
+	// +optional
+	NodeNames []string `json:"nodeNames,omitempty"`
+}
+
+// ExecutionPlan is how a task would operate on its edge nodes.
+type ExecutionPlan struct {
+	// Nodes are the nodes the task would operate on, in the order they would be dispatched in.
+	// +optional
+	Nodes []string `json:"nodes,omitempty"`
+	// CanaryNodes are the nodes the task would operate on before it waits for approval.
+	// +optional
+	CanaryNodes []string `json:"canaryNodes,omitempty"`
+	// BatchSize is the number of nodes the task would operate on at the same time.
+	BatchSize int32 `json:"batchSize"`
+	// CheckItems are the items checked on each node, including the check items CloudCore
+	// selects for the version.
+	// +optional
+	CheckItems []string `json:"checkItems,omitempty"`
+}
+
+// WorkloadReference identifies a workload that has pods running on the nodes targeted by a task.
+type WorkloadReference struct {
+	// Kind is the kind of the workload, such as Deployment, DaemonSet or StatefulSet.
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the workload.
+	Namespace string `json:"namespace"`
+	// Name is the name of the workload.
+	Name string `json:"name"`
+	// NodeNames are the targeted nodes running pods of the workload.
+	// +optional
+	NodeNames []string `json:"nodeNames,omitempty"`
 }
 
 // TaskStatus stores the status of Upgrade for each edge node.
@@ -145,4 +903,166 @@ type TaskStatus struct {
 	Reason string `json:"reason,omitempty"`
 	// Time represents for the running time of the ImagePrePullJob.
 	Time string `json:"time,omitempty"`
+	// Environment is the execution environment reported by the edge node with its terminal result.
+	// +optional
+	Environment *NodeEnvironment `json:"environment,omitempty"`
+	// Cost is the cost of executing the task on the edge node.
+	// +optional
+	Cost *NodeCost `json:"cost,omitempty"`
+	// QueuePosition is the position of the edge node among the nodes waiting for dispatch,
+	// it is 1 for the next node to dispatch and unset once the node is dispatched.
+	// +optional
+	QueuePosition int32 `json:"queuePosition,omitempty"`
+	// EstimatedStartTime is the estimated time the task starts on the waiting edge node, it is
+	// derived from the average time the nodes took so far and unset until a node finished.
+	// +optional
+	EstimatedStartTime string `json:"estimatedStartTime,omitempty"`
+	// Metadata is the custom metadata of the job echoed back by the edge node with its terminal result.
+	// +optional
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// CommandDigest is the SHA-256 digest, sha256:<hex>, of the record of the commands the edge node ran
+	// for the job, reported with its terminal result. The record is kept on the node in
+	// /etc/kubeedge/task-audit/<type>-<job>.log, with the secrets passed to the commands redacted.
+	// +optional
+	CommandDigest string `json:"commandDigest,omitempty"`
+	// History is the state transitions of the edge node, oldest first, recorded if the transition
+	// history of TaskManager is enabled. A long history is compacted, the first and the latest
+	// transitions and the failures are kept and the others are summarized in a single record.
+	// +optional
+	History []NodeTransitionRecord `json:"history,omitempty"`
+}
+
+// NodeTransitionRecord is a state transition of an edge node recorded in the status of the task
+type NodeTransitionRecord struct {
+	// State is the state the node turned to.
+	State api.State `json:"state,omitempty"`
+	// Event is the event the node turned to the state on.
+	Event string `json:"event,omitempty"`
+	// Action is the action of the event.
+	Action api.Action `json:"action,omitempty"`
+	// Time is the time of the transition.
+	// +optional
+	Time string `json:"time,omitempty"`
+	// Reason is the reason of the transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Compacted is the number of transitions the record summarizes, it is set on the record which
+	// replaces the transitions dropped by compaction, the record then holds the latest of them.
+	// +optional
+	Compacted int32 `json:"compacted,omitempty"`
+}
+
+// ExclusionReason is why a node selected by a task is not operated on
+type ExclusionReason string
+
+const (
+	// ExclusionNotFound means the node named by the task does not exist, no node is operated on then.
+	ExclusionNotFound ExclusionReason = "NotFound"
+	// ExclusionNotEdgeNode means the node is not an edge node.
+	ExclusionNotEdgeNode ExclusionReason = "NotEdgeNode"
+	// ExclusionNotReady means the node is not ready.
+	ExclusionNotReady ExclusionReason = "NotReady"
+	// ExclusionFiltered means the node filter webhook of TaskManager rejected the node.
+	ExclusionFiltered ExclusionReason = "Filtered"
+	// ExclusionFilterFailed means the node filter webhook of TaskManager failed, the node is excluded by
+	// the failure policy of the webhook.
+	ExclusionFilterFailed ExclusionReason = "FilterFailed"
+	// ExclusionUpToDate means the node already runs the version the task upgrades to.
+	ExclusionUpToDate ExclusionReason = "UpToDate"
+	// ExclusionUpgrading means the node is being upgraded by another task.
+	ExclusionUpgrading ExclusionReason = "Upgrading"
+)
+
+// ExcludedNode is a node selected by a task which is not operated on
+type ExcludedNode struct {
+	// NodeName is the name of the node.
+	NodeName string `json:"nodeName"`
+	// Reason is why the node is not operated on.
+	Reason ExclusionReason `json:"reason"`
+	// Message details the reason.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// NodeEnvironment is a snapshot of the execution environment of an edge node
+type NodeEnvironment struct {
+	// OS is the operating system of the edge node, e.g. Ubuntu 22.04.3 LTS.
+	OS string `json:"os,omitempty"`
+	// KernelVersion is the kernel release of the edge node.
+	KernelVersion string `json:"kernelVersion,omitempty"`
+	// Architecture is the CPU architecture of the edge node.
+	Architecture string `json:"architecture,omitempty"`
+	// ContainerRuntimeVersion is the name and version of the container runtime, e.g. containerd 1.7.2.
+	ContainerRuntimeVersion string `json:"containerRuntimeVersion,omitempty"`
+	// DiskFree is the free disk space of the root directory of edged.
+	// +optional
+	DiskFree *resource.Quantity `json:"diskFree,omitempty"`
+}
+
+// NodeCost is the cost of executing a task on an edge node.
+type NodeCost struct {
+	// BytesDownloaded is the size of the images the edge node pulled for the task as reported
+	// by the container runtime, images which are already present are not counted.
+	// +optional
+	BytesDownloaded int64 `json:"bytesDownloaded,omitempty"`
+	// DurationSeconds is the time from the start of the task on the edge node to its latest transition,
+	// it is never less than StageSeconds.
+	// +optional
+	DurationSeconds int64 `json:"durationSeconds,omitempty"`
+	// Retries is the number of times the task was dispatched again because the edge node did not respond.
+	// +optional
+	Retries int32 `json:"retries,omitempty"`
+	// StageSeconds is the sum of the time the edge node reported it ran the stages of the task for,
+	// measured by its monotonic clock.
+	// +optional
+	StageSeconds int64 `json:"stageSeconds,omitempty"`
+	// StartTime is the time the task started on the edge node.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// Group is the node group the edge node belongs to.
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// TaskProgress sums up the status of the edge nodes of a task.
+type TaskProgress struct {
+	// Total is the number of edge nodes of the task.
+	Total int32 `json:"total"`
+	// Succeeded is the number of edge nodes the task succeeded on.
+	Succeeded int32 `json:"succeeded"`
+	// Failed is the number of edge nodes the task failed on.
+	Failed int32 `json:"failed"`
+	// InProgress is the number of edge nodes the task is operating on.
+	InProgress int32 `json:"inProgress"`
+	// PercentComplete is the percentage of the edge nodes which finished, including the nodes
+	// which failed, were skipped or were removed.
+	PercentComplete int32 `json:"percentComplete"`
+}
+
+// TaskCost aggregates the cost of executing a task on its edge nodes.
+type TaskCost struct {
+	// CostSummary is the total cost of the task.
+	CostSummary `json:",inline"`
+	// Groups breaks the cost down by node group, nodes which belong to no group are summed up
+	// in the group with an empty name.
+	// +optional
+	Groups []CostSummary `json:"groups,omitempty"`
+}
+
+// CostSummary is the summed up cost of a set of edge nodes.
+type CostSummary struct {
+	// Group is the name of the node group, it is empty for the total of the task.
+	// +optional
+	Group string `json:"group,omitempty"`
+	// Nodes is the number of edge nodes the cost is summed up from.
+	Nodes int32 `json:"nodes"`
+	// BytesDownloaded is the total size of the images pulled by the edge nodes.
+	// +optional
+	BytesDownloaded int64 `json:"bytesDownloaded,omitempty"`
+	// DurationSeconds is the total execution time of the edge nodes.
+	// +optional
+	DurationSeconds int64 `json:"durationSeconds,omitempty"`
+	// Retries is the total number of dispatch retries of the edge nodes.
+	// +optional
+	Retries int32 `json:"retries,omitempty"`
 }
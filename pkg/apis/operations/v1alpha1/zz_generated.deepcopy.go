@@ -22,12 +22,35 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImagePrePullJob) DeepCopyInto(out *ImagePrePullJob) {
+func (in *ApplySpec) DeepCopyInto(out *ApplySpec) {
+	*out = *in
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplySpec.
+func (in *ApplySpec) DeepCopy() *ApplySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupJob) DeepCopyInto(out *BackupJob) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -36,18 +59,18 @@ func (in *ImagePrePullJob) DeepCopyInto(out *ImagePrePullJob) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePrePullJob.
-func (in *ImagePrePullJob) DeepCopy() *ImagePrePullJob {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupJob.
+func (in *BackupJob) DeepCopy() *BackupJob {
 	if in == nil {
 		return nil
 	}
-	out := new(ImagePrePullJob)
+	out := new(BackupJob)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ImagePrePullJob) DeepCopyObject() runtime.Object {
+func (in *BackupJob) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -55,13 +78,13 @@ func (in *ImagePrePullJob) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImagePrePullJobList) DeepCopyInto(out *ImagePrePullJobList) {
+func (in *BackupJobList) DeepCopyInto(out *BackupJobList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ImagePrePullJob, len(*in))
+		*out = make([]BackupJob, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -69,18 +92,18 @@ func (in *ImagePrePullJobList) DeepCopyInto(out *ImagePrePullJobList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePrePullJobList.
-func (in *ImagePrePullJobList) DeepCopy() *ImagePrePullJobList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupJobList.
+func (in *BackupJobList) DeepCopy() *BackupJobList {
 	if in == nil {
 		return nil
 	}
-	out := new(ImagePrePullJobList)
+	out := new(BackupJobList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ImagePrePullJobList) DeepCopyObject() runtime.Object {
+func (in *BackupJobList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -88,28 +111,29 @@ func (in *ImagePrePullJobList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImagePrePullJobSpec) DeepCopyInto(out *ImagePrePullJobSpec) {
+func (in *BackupJobSpec) DeepCopyInto(out *BackupJobSpec) {
 	*out = *in
-	in.ImagePrePullTemplate.DeepCopyInto(&out.ImagePrePullTemplate)
+	in.CommonJobSpec.DeepCopyInto(&out.CommonJobSpec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePrePullJobSpec.
-func (in *ImagePrePullJobSpec) DeepCopy() *ImagePrePullJobSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupJobSpec.
+func (in *BackupJobSpec) DeepCopy() *BackupJobSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ImagePrePullJobSpec)
+	out := new(BackupJobSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImagePrePullJobStatus) DeepCopyInto(out *ImagePrePullJobStatus) {
+func (in *BackupJobStatus) DeepCopyInto(out *BackupJobStatus) {
 	*out = *in
-	if in.Status != nil {
-		in, out := &in.Status, &out.Status
-		*out = make([]ImagePrePullStatus, len(*in))
+	in.CommonJobStatus.DeepCopyInto(&out.CommonJobStatus)
+	if in.Backups != nil {
+		in, out := &in.Backups, &out.Backups
+		*out = make([]NodeBackup, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -117,50 +141,40 @@ func (in *ImagePrePullJobStatus) DeepCopyInto(out *ImagePrePullJobStatus) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePrePullJobStatus.
-func (in *ImagePrePullJobStatus) DeepCopy() *ImagePrePullJobStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupJobStatus.
+func (in *BackupJobStatus) DeepCopy() *BackupJobStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ImagePrePullJobStatus)
+	out := new(BackupJobStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImagePrePullStatus) DeepCopyInto(out *ImagePrePullStatus) {
+func (in *CanarySpec) DeepCopyInto(out *CanarySpec) {
 	*out = *in
-	if in.TaskStatus != nil {
-		in, out := &in.TaskStatus, &out.TaskStatus
-		*out = new(TaskStatus)
-		**out = **in
-	}
-	if in.ImageStatus != nil {
-		in, out := &in.ImageStatus, &out.ImageStatus
-		*out = make([]ImageStatus, len(*in))
-		copy(*out, *in)
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePrePullStatus.
-func (in *ImagePrePullStatus) DeepCopy() *ImagePrePullStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanarySpec.
+func (in *CanarySpec) DeepCopy() *CanarySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ImagePrePullStatus)
+	out := new(CanarySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImagePrePullTemplate) DeepCopyInto(out *ImagePrePullTemplate) {
+func (in *CommonJobSpec) DeepCopyInto(out *CommonJobSpec) {
 	*out = *in
-	if in.Images != nil {
-		in, out := &in.Images, &out.Images
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 	if in.NodeNames != nil {
 		in, out := &in.NodeNames, &out.NodeNames
 		*out = make([]string, len(*in))
@@ -171,47 +185,100 @@ func (in *ImagePrePullTemplate) DeepCopyInto(out *ImagePrePullTemplate) {
 		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.CheckItems != nil {
-		in, out := &in.CheckItems, &out.CheckItems
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.FailureTolerate != nil {
+		in, out := &in.FailureTolerate, &out.FailureTolerate
+		*out = new(intstr.IntOrString)
+		**out = **in
 	}
 	if in.TimeoutSeconds != nil {
 		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
 		*out = new(uint32)
 		**out = **in
 	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Cohorts != nil {
+		in, out := &in.Cohorts, &out.Cohorts
+		*out = new(NodeCohortSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]NotificationSink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Credential != nil {
+		in, out := &in.Credential, &out.Credential
+		*out = new(TaskCredentialSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePrePullTemplate.
-func (in *ImagePrePullTemplate) DeepCopy() *ImagePrePullTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommonJobSpec.
+func (in *CommonJobSpec) DeepCopy() *CommonJobSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ImagePrePullTemplate)
+	out := new(CommonJobSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageStatus) DeepCopyInto(out *ImageStatus) {
+func (in *CommonJobStatus) DeepCopyInto(out *CommonJobStatus) {
 	*out = *in
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = make([]TaskStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExcludedNodes != nil {
+		in, out := &in.ExcludedNodes, &out.ExcludedNodes
+		*out = make([]ExcludedNode, len(*in))
+		copy(*out, *in)
+	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageStatus.
-func (in *ImageStatus) DeepCopy() *ImageStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommonJobStatus.
+func (in *CommonJobStatus) DeepCopy() *CommonJobStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageStatus)
+	out := new(CommonJobStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeUpgradeJob) DeepCopyInto(out *NodeUpgradeJob) {
+func (in *ConfigUpdateJob) DeepCopyInto(out *ConfigUpdateJob) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -220,18 +287,18 @@ func (in *NodeUpgradeJob) DeepCopyInto(out *NodeUpgradeJob) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeUpgradeJob.
-func (in *NodeUpgradeJob) DeepCopy() *NodeUpgradeJob {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigUpdateJob.
+func (in *ConfigUpdateJob) DeepCopy() *ConfigUpdateJob {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeUpgradeJob)
+	out := new(ConfigUpdateJob)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NodeUpgradeJob) DeepCopyObject() runtime.Object {
+func (in *ConfigUpdateJob) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -239,13 +306,13 @@ func (in *NodeUpgradeJob) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeUpgradeJobList) DeepCopyInto(out *NodeUpgradeJobList) {
+func (in *ConfigUpdateJobList) DeepCopyInto(out *ConfigUpdateJobList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]NodeUpgradeJob, len(*in))
+		*out = make([]ConfigUpdateJob, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -253,18 +320,18 @@ func (in *NodeUpgradeJobList) DeepCopyInto(out *NodeUpgradeJobList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeUpgradeJobList.
-func (in *NodeUpgradeJobList) DeepCopy() *NodeUpgradeJobList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigUpdateJobList.
+func (in *ConfigUpdateJobList) DeepCopy() *ConfigUpdateJobList {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeUpgradeJobList)
+	out := new(ConfigUpdateJobList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NodeUpgradeJobList) DeepCopyObject() runtime.Object {
+func (in *ConfigUpdateJobList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -272,74 +339,2116 @@ func (in *NodeUpgradeJobList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeUpgradeJobSpec) DeepCopyInto(out *NodeUpgradeJobSpec) {
+func (in *ConfigUpdateJobSpec) DeepCopyInto(out *ConfigUpdateJobSpec) {
 	*out = *in
-	if in.TimeoutSeconds != nil {
-		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
-		*out = new(uint32)
-		**out = **in
+	in.CommonJobSpec.DeepCopyInto(&out.CommonJobSpec)
+	in.Patch.DeepCopyInto(&out.Patch)
+	if in.PostCheck != nil {
+		in, out := &in.PostCheck, &out.PostCheck
+		*out = new(PostCheckSpec)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.NodeNames != nil {
-		in, out := &in.NodeNames, &out.NodeNames
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigUpdateJobSpec.
+func (in *ConfigUpdateJobSpec) DeepCopy() *ConfigUpdateJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigUpdateJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigUpdateJobStatus) DeepCopyInto(out *ConfigUpdateJobStatus) {
+	*out = *in
+	in.CommonJobStatus.DeepCopyInto(&out.CommonJobStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigUpdateJobStatus.
+func (in *ConfigUpdateJobStatus) DeepCopy() *ConfigUpdateJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigUpdateJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostSummary) DeepCopyInto(out *CostSummary) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostSummary.
+func (in *CostSummary) DeepCopy() *CostSummary {
+	if in == nil {
+		return nil
 	}
+	out := new(CostSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CriticalPodSelector) DeepCopyInto(out *CriticalPodSelector) {
+	*out = *in
 	if in.LabelSelector != nil {
 		in, out := &in.LabelSelector, &out.LabelSelector
 		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.CheckItems != nil {
-		in, out := &in.CheckItems, &out.CheckItems
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CriticalPodSelector.
+func (in *CriticalPodSelector) DeepCopy() *CriticalPodSelector {
+	if in == nil {
+		return nil
 	}
+	out := new(CriticalPodSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiagnoseFinding) DeepCopyInto(out *DiagnoseFinding) {
+	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeUpgradeJobSpec.
-func (in *NodeUpgradeJobSpec) DeepCopy() *NodeUpgradeJobSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiagnoseFinding.
+func (in *DiagnoseFinding) DeepCopy() *DiagnoseFinding {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeUpgradeJobSpec)
+	out := new(DiagnoseFinding)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeUpgradeJobStatus) DeepCopyInto(out *NodeUpgradeJobStatus) {
+func (in *DiagnoseJob) DeepCopyInto(out *DiagnoseJob) {
 	*out = *in
-	if in.Status != nil {
-		in, out := &in.Status, &out.Status
-		*out = make([]TaskStatus, len(*in))
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiagnoseJob.
+func (in *DiagnoseJob) DeepCopy() *DiagnoseJob {
+	if in == nil {
+		return nil
+	}
+	out := new(DiagnoseJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DiagnoseJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiagnoseJobList) DeepCopyInto(out *DiagnoseJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DiagnoseJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiagnoseJobList.
+func (in *DiagnoseJobList) DeepCopy() *DiagnoseJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(DiagnoseJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DiagnoseJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiagnoseJobSpec) DeepCopyInto(out *DiagnoseJobSpec) {
+	*out = *in
+	in.CommonJobSpec.DeepCopyInto(&out.CommonJobSpec)
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]DiagnoseCheck, len(*in))
 		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeUpgradeJobStatus.
-func (in *NodeUpgradeJobStatus) DeepCopy() *NodeUpgradeJobStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiagnoseJobSpec.
+func (in *DiagnoseJobSpec) DeepCopy() *DiagnoseJobSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeUpgradeJobStatus)
+	out := new(DiagnoseJobSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TaskStatus) DeepCopyInto(out *TaskStatus) {
+func (in *DiagnoseJobStatus) DeepCopyInto(out *DiagnoseJobStatus) {
 	*out = *in
+	in.CommonJobStatus.DeepCopyInto(&out.CommonJobStatus)
+	if in.Diagnoses != nil {
+		in, out := &in.Diagnoses, &out.Diagnoses
+		*out = make([]NodeDiagnosis, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskStatus.
-func (in *TaskStatus) DeepCopy() *TaskStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiagnoseJobStatus.
+func (in *DiagnoseJobStatus) DeepCopy() *DiagnoseJobStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(TaskStatus)
+	out := new(DiagnoseJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainSpec) DeepCopyInto(out *DrainSpec) {
+	*out = *in
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GracePeriodSeconds != nil {
+		in, out := &in.GracePeriodSeconds, &out.GracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainSpec.
+func (in *DrainSpec) DeepCopy() *DrainSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DrainSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExcludedNode) DeepCopyInto(out *ExcludedNode) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExcludedNode.
+func (in *ExcludedNode) DeepCopy() *ExcludedNode {
+	if in == nil {
+		return nil
+	}
+	out := new(ExcludedNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecutionPlan) DeepCopyInto(out *ExecutionPlan) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CanaryNodes != nil {
+		in, out := &in.CanaryNodes, &out.CanaryNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CheckItems != nil {
+		in, out := &in.CheckItems, &out.CheckItems
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutionPlan.
+func (in *ExecutionPlan) DeepCopy() *ExecutionPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecutionPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailureCluster) DeepCopyInto(out *FailureCluster) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeNames != nil {
+		in, out := &in.NodeNames, &out.NodeNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailureCluster.
+func (in *FailureCluster) DeepCopy() *FailureCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(FailureCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailureRateRule) DeepCopyInto(out *FailureRateRule) {
+	*out = *in
+	if in.WindowSeconds != nil {
+		in, out := &in.WindowSeconds, &out.WindowSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinNodes != nil {
+		in, out := &in.MinNodes, &out.MinNodes
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailureRateRule.
+func (in *FailureRateRule) DeepCopy() *FailureRateRule {
+	if in == nil {
+		return nil
+	}
+	out := new(FailureRateRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePrePullJob) DeepCopyInto(out *ImagePrePullJob) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePrePullJob.
+func (in *ImagePrePullJob) DeepCopy() *ImagePrePullJob {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePrePullJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImagePrePullJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePrePullJobList) DeepCopyInto(out *ImagePrePullJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ImagePrePullJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePrePullJobList.
+func (in *ImagePrePullJobList) DeepCopy() *ImagePrePullJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePrePullJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImagePrePullJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePrePullJobSpec) DeepCopyInto(out *ImagePrePullJobSpec) {
+	*out = *in
+	in.ImagePrePullTemplate.DeepCopyInto(&out.ImagePrePullTemplate)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePrePullJobSpec.
+func (in *ImagePrePullJobSpec) DeepCopy() *ImagePrePullJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePrePullJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePrePullJobStatus) DeepCopyInto(out *ImagePrePullJobStatus) {
+	*out = *in
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = make([]ImagePrePullStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExcludedNodes != nil {
+		in, out := &in.ExcludedNodes, &out.ExcludedNodes
+		*out = make([]ExcludedNode, len(*in))
+		copy(*out, *in)
+	}
+	if in.Cost != nil {
+		in, out := &in.Cost, &out.Cost
+		*out = new(TaskCost)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePrePullJobStatus.
+func (in *ImagePrePullJobStatus) DeepCopy() *ImagePrePullJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePrePullJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePrePullStatus) DeepCopyInto(out *ImagePrePullStatus) {
+	*out = *in
+	if in.TaskStatus != nil {
+		in, out := &in.TaskStatus, &out.TaskStatus
+		*out = new(TaskStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageStatus != nil {
+		in, out := &in.ImageStatus, &out.ImageStatus
+		*out = make([]ImageStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePrePullStatus.
+func (in *ImagePrePullStatus) DeepCopy() *ImagePrePullStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePrePullStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePrePullTemplate) DeepCopyInto(out *ImagePrePullTemplate) {
+	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeNames != nil {
+		in, out := &in.NodeNames, &out.NodeNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CheckItems != nil {
+		in, out := &in.CheckItems, &out.CheckItems
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailureTolerate != nil {
+		in, out := &in.FailureTolerate, &out.FailureTolerate
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Preflight != nil {
+		in, out := &in.Preflight, &out.Preflight
+		*out = new(PreflightSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Cohorts != nil {
+		in, out := &in.Cohorts, &out.Cohorts
+		*out = new(NodeCohortSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]NotificationSink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Credential != nil {
+		in, out := &in.Credential, &out.Credential
+		*out = new(TaskCredentialSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = make([]JobInput, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePrePullTemplate.
+func (in *ImagePrePullTemplate) DeepCopy() *ImagePrePullTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePrePullTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageStatus) DeepCopyInto(out *ImageStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageStatus.
+func (in *ImageStatus) DeepCopy() *ImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobInput) DeepCopyInto(out *JobInput) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobInput.
+func (in *JobInput) DeepCopy() *JobInput {
+	if in == nil {
+		return nil
+	}
+	out := new(JobInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindowStatus) DeepCopyInto(out *MaintenanceWindowStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindowStatus.
+func (in *MaintenanceWindowStatus) DeepCopy() *MaintenanceWindowStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindowStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeArtifact) DeepCopyInto(out *NodeArtifact) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeArtifact.
+func (in *NodeArtifact) DeepCopy() *NodeArtifact {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeArtifact)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeBackup) DeepCopyInto(out *NodeBackup) {
+	*out = *in
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeBackup.
+func (in *NodeBackup) DeepCopy() *NodeBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeCohortSpec) DeepCopyInto(out *NodeCohortSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SucceededLabels != nil {
+		in, out := &in.SucceededLabels, &out.SucceededLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.FailedLabels != nil {
+		in, out := &in.FailedLabels, &out.FailedLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeCohortSpec.
+func (in *NodeCohortSpec) DeepCopy() *NodeCohortSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeCohortSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeCost) DeepCopyInto(out *NodeCost) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeCost.
+func (in *NodeCost) DeepCopy() *NodeCost {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeCost)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeDiagnosis) DeepCopyInto(out *NodeDiagnosis) {
+	*out = *in
+	if in.Findings != nil {
+		in, out := &in.Findings, &out.Findings
+		*out = make([]DiagnoseFinding, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDiagnosis.
+func (in *NodeDiagnosis) DeepCopy() *NodeDiagnosis {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeDiagnosis)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeEnvironment) DeepCopyInto(out *NodeEnvironment) {
+	*out = *in
+	if in.DiskFree != nil {
+		in, out := &in.DiskFree, &out.DiskFree
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeEnvironment.
+func (in *NodeEnvironment) DeepCopy() *NodeEnvironment {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeEnvironment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeRestartJob) DeepCopyInto(out *NodeRestartJob) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeRestartJob.
+func (in *NodeRestartJob) DeepCopy() *NodeRestartJob {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeRestartJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeRestartJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeRestartJobList) DeepCopyInto(out *NodeRestartJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NodeRestartJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeRestartJobList.
+func (in *NodeRestartJobList) DeepCopy() *NodeRestartJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeRestartJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeRestartJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeRestartJobSpec) DeepCopyInto(out *NodeRestartJobSpec) {
+	*out = *in
+	in.CommonJobSpec.DeepCopyInto(&out.CommonJobSpec)
+	if in.PostCheck != nil {
+		in, out := &in.PostCheck, &out.PostCheck
+		*out = new(PostCheckSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeRestartJobSpec.
+func (in *NodeRestartJobSpec) DeepCopy() *NodeRestartJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeRestartJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeRestartJobStatus) DeepCopyInto(out *NodeRestartJobStatus) {
+	*out = *in
+	in.CommonJobStatus.DeepCopyInto(&out.CommonJobStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeRestartJobStatus.
+func (in *NodeRestartJobStatus) DeepCopy() *NodeRestartJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeRestartJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeTransitionRecord) DeepCopyInto(out *NodeTransitionRecord) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeTransitionRecord.
+func (in *NodeTransitionRecord) DeepCopy() *NodeTransitionRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeTransitionRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeUpgradeJob) DeepCopyInto(out *NodeUpgradeJob) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeUpgradeJob.
+func (in *NodeUpgradeJob) DeepCopy() *NodeUpgradeJob {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeUpgradeJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeUpgradeJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeUpgradeJobList) DeepCopyInto(out *NodeUpgradeJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NodeUpgradeJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeUpgradeJobList.
+func (in *NodeUpgradeJobList) DeepCopy() *NodeUpgradeJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeUpgradeJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeUpgradeJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeUpgradeJobSpec) DeepCopyInto(out *NodeUpgradeJobSpec) {
+	*out = *in
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = new(StageTimeouts)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeNames != nil {
+		in, out := &in.NodeNames, &out.NodeNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeGroups != nil {
+		in, out := &in.NodeGroups, &out.NodeGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Concurrency != nil {
+		in, out := &in.Concurrency, &out.Concurrency
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.RollingStrategy != nil {
+		in, out := &in.RollingStrategy, &out.RollingStrategy
+		*out = new(RollingStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Preflight != nil {
+		in, out := &in.Preflight, &out.Preflight
+		*out = new(PreflightSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CheckItems != nil {
+		in, out := &in.CheckItems, &out.CheckItems
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailureTolerate != nil {
+		in, out := &in.FailureTolerate, &out.FailureTolerate
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(VerificationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostCheck != nil {
+		in, out := &in.PostCheck, &out.PostCheck
+		*out = new(PostCheckSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Promotion != nil {
+		in, out := &in.Promotion, &out.Promotion
+		*out = new(PromotionSpec)
+		**out = **in
+	}
+	if in.PauseOn != nil {
+		in, out := &in.PauseOn, &out.PauseOn
+		*out = new(PauseOnSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Apply != nil {
+		in, out := &in.Apply, &out.Apply
+		*out = new(ApplySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanarySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DrainNodeBeforeUpgrade != nil {
+		in, out := &in.DrainNodeBeforeUpgrade, &out.DrainNodeBeforeUpgrade
+		*out = new(DrainSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OrderingSeed != nil {
+		in, out := &in.OrderingSeed, &out.OrderingSeed
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Cohorts != nil {
+		in, out := &in.Cohorts, &out.Cohorts
+		*out = new(NodeCohortSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]NotificationSink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Credential != nil {
+		in, out := &in.Credential, &out.Credential
+		*out = new(TaskCredentialSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VersionMappings != nil {
+		in, out := &in.VersionMappings, &out.VersionMappings
+		*out = make([]VersionMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = make([]JobInput, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeUpgradeJobSpec.
+func (in *NodeUpgradeJobSpec) DeepCopy() *NodeUpgradeJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeUpgradeJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeUpgradeJobStatus) DeepCopyInto(out *NodeUpgradeJobStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = make([]TaskStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExcludedNodes != nil {
+		in, out := &in.ExcludedNodes, &out.ExcludedNodes
+		*out = make([]ExcludedNode, len(*in))
+		copy(*out, *in)
+	}
+	if in.AffectedWorkloads != nil {
+		in, out := &in.AffectedWorkloads, &out.AffectedWorkloads
+		*out = make([]WorkloadReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Cost != nil {
+		in, out := &in.Cost, &out.Cost
+		*out = new(TaskCost)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Progress != nil {
+		in, out := &in.Progress, &out.Progress
+		*out = new(TaskProgress)
+		**out = **in
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindowStatus)
+		**out = **in
+	}
+	if in.Promotion != nil {
+		in, out := &in.Promotion, &out.Promotion
+		*out = new(PromotionStatus)
+		**out = **in
+	}
+	if in.OrderingSeed != nil {
+		in, out := &in.OrderingSeed, &out.OrderingSeed
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(ExecutionPlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailureClusters != nil {
+		in, out := &in.FailureClusters, &out.FailureClusters
+		*out = make([]FailureCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VersionMappings != nil {
+		in, out := &in.VersionMappings, &out.VersionMappings
+		*out = make([]VersionMappingStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeUpgradeJobStatus.
+func (in *NodeUpgradeJobStatus) DeepCopy() *NodeUpgradeJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeUpgradeJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationSink) DeepCopyInto(out *NotificationSink) {
+	*out = *in
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(NotificationWebhook)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationSink.
+func (in *NotificationSink) DeepCopy() *NotificationSink {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationWebhook) DeepCopyInto(out *NotificationWebhook) {
+	*out = *in
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationWebhook.
+func (in *NotificationWebhook) DeepCopy() *NotificationWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSUpgradeArtifact) DeepCopyInto(out *OSUpgradeArtifact) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSUpgradeArtifact.
+func (in *OSUpgradeArtifact) DeepCopy() *OSUpgradeArtifact {
+	if in == nil {
+		return nil
+	}
+	out := new(OSUpgradeArtifact)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSUpgradeHook) DeepCopyInto(out *OSUpgradeHook) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSUpgradeHook.
+func (in *OSUpgradeHook) DeepCopy() *OSUpgradeHook {
+	if in == nil {
+		return nil
+	}
+	out := new(OSUpgradeHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSUpgradeJob) DeepCopyInto(out *OSUpgradeJob) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSUpgradeJob.
+func (in *OSUpgradeJob) DeepCopy() *OSUpgradeJob {
+	if in == nil {
+		return nil
+	}
+	out := new(OSUpgradeJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OSUpgradeJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSUpgradeJobList) DeepCopyInto(out *OSUpgradeJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OSUpgradeJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSUpgradeJobList.
+func (in *OSUpgradeJobList) DeepCopy() *OSUpgradeJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(OSUpgradeJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OSUpgradeJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSUpgradeJobSpec) DeepCopyInto(out *OSUpgradeJobSpec) {
+	*out = *in
+	in.CommonJobSpec.DeepCopyInto(&out.CommonJobSpec)
+	out.Artifact = in.Artifact
+	out.Hook = in.Hook
+	if in.PostCheck != nil {
+		in, out := &in.PostCheck, &out.PostCheck
+		*out = new(PostCheckSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSUpgradeJobSpec.
+func (in *OSUpgradeJobSpec) DeepCopy() *OSUpgradeJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OSUpgradeJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSUpgradeJobStatus) DeepCopyInto(out *OSUpgradeJobStatus) {
+	*out = *in
+	in.CommonJobStatus.DeepCopyInto(&out.CommonJobStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSUpgradeJobStatus.
+func (in *OSUpgradeJobStatus) DeepCopy() *OSUpgradeJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OSUpgradeJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PauseOnSpec) DeepCopyInto(out *PauseOnSpec) {
+	*out = *in
+	if in.FailedNodes != nil {
+		in, out := &in.FailedNodes, &out.FailedNodes
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureRate != nil {
+		in, out := &in.FailureRate, &out.FailureRate
+		*out = new(FailureRateRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalAlerts != nil {
+		in, out := &in.ExternalAlerts, &out.ExternalAlerts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PauseOnSpec.
+func (in *PauseOnSpec) DeepCopy() *PauseOnSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PauseOnSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostCheckSpec) DeepCopyInto(out *PostCheckSpec) {
+	*out = *in
+	if in.CriticalPods != nil {
+		in, out := &in.CriticalPods, &out.CriticalPods
+		*out = make([]CriticalPodSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SmokeTest != nil {
+		in, out := &in.SmokeTest, &out.SmokeTest
+		*out = new(SmokeTestSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostCheckSpec.
+func (in *PostCheckSpec) DeepCopy() *PostCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreflightSpec) DeepCopyInto(out *PreflightSpec) {
+	*out = *in
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreflightSpec.
+func (in *PreflightSpec) DeepCopy() *PreflightSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PreflightSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionSpec) DeepCopyInto(out *PromotionSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionSpec.
+func (in *PromotionSpec) DeepCopy() *PromotionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionStatus) DeepCopyInto(out *PromotionStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionStatus.
+func (in *PromotionStatus) DeepCopy() *PromotionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreJob) DeepCopyInto(out *RestoreJob) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreJob.
+func (in *RestoreJob) DeepCopy() *RestoreJob {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RestoreJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreJobList) DeepCopyInto(out *RestoreJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RestoreJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreJobList.
+func (in *RestoreJobList) DeepCopy() *RestoreJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RestoreJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreJobSpec) DeepCopyInto(out *RestoreJobSpec) {
+	*out = *in
+	in.CommonJobSpec.DeepCopyInto(&out.CommonJobSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreJobSpec.
+func (in *RestoreJobSpec) DeepCopy() *RestoreJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreJobStatus) DeepCopyInto(out *RestoreJobStatus) {
+	*out = *in
+	in.CommonJobStatus.DeepCopyInto(&out.CommonJobStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreJobStatus.
+func (in *RestoreJobStatus) DeepCopy() *RestoreJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollingStrategy) DeepCopyInto(out *RollingStrategy) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingStrategy.
+func (in *RollingStrategy) DeepCopy() *RollingStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RollingStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SmokeTestSpec) DeepCopyInto(out *SmokeTestSpec) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Probe != nil {
+		in, out := &in.Probe, &out.Probe
+		*out = new(VerificationProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SmokeTestSpec.
+func (in *SmokeTestSpec) DeepCopy() *SmokeTestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SmokeTestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StageTimeouts) DeepCopyInto(out *StageTimeouts) {
+	*out = *in
+	if in.Check != nil {
+		in, out := &in.Check, &out.Check
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.Rollback != nil {
+		in, out := &in.Rollback, &out.Rollback
+		*out = new(uint32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StageTimeouts.
+func (in *StageTimeouts) DeepCopy() *StageTimeouts {
+	if in == nil {
+		return nil
+	}
+	out := new(StageTimeouts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SupportBundleJob) DeepCopyInto(out *SupportBundleJob) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportBundleJob.
+func (in *SupportBundleJob) DeepCopy() *SupportBundleJob {
+	if in == nil {
+		return nil
+	}
+	out := new(SupportBundleJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SupportBundleJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SupportBundleJobList) DeepCopyInto(out *SupportBundleJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SupportBundleJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportBundleJobList.
+func (in *SupportBundleJobList) DeepCopy() *SupportBundleJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(SupportBundleJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SupportBundleJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SupportBundleJobSpec) DeepCopyInto(out *SupportBundleJobSpec) {
+	*out = *in
+	in.CommonJobSpec.DeepCopyInto(&out.CommonJobSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportBundleJobSpec.
+func (in *SupportBundleJobSpec) DeepCopy() *SupportBundleJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SupportBundleJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SupportBundleJobStatus) DeepCopyInto(out *SupportBundleJobStatus) {
+	*out = *in
+	in.CommonJobStatus.DeepCopyInto(&out.CommonJobStatus)
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = make([]NodeArtifact, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportBundleJobStatus.
+func (in *SupportBundleJobStatus) DeepCopy() *SupportBundleJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SupportBundleJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskCost) DeepCopyInto(out *TaskCost) {
+	*out = *in
+	out.CostSummary = in.CostSummary
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]CostSummary, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskCost.
+func (in *TaskCost) DeepCopy() *TaskCost {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskCost)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskCredentialSpec) DeepCopyInto(out *TaskCredentialSpec) {
+	*out = *in
+	if in.Audiences != nil {
+		in, out := &in.Audiences, &out.Audiences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExpirationSeconds != nil {
+		in, out := &in.ExpirationSeconds, &out.ExpirationSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskCredentialSpec.
+func (in *TaskCredentialSpec) DeepCopy() *TaskCredentialSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskCredentialSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskProgress) DeepCopyInto(out *TaskProgress) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskProgress.
+func (in *TaskProgress) DeepCopy() *TaskProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskStatus) DeepCopyInto(out *TaskStatus) {
+	*out = *in
+	if in.Environment != nil {
+		in, out := &in.Environment, &out.Environment
+		*out = new(NodeEnvironment)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cost != nil {
+		in, out := &in.Cost, &out.Cost
+		*out = new(NodeCost)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]NodeTransitionRecord, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskStatus.
+func (in *TaskStatus) DeepCopy() *TaskStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskTypeConfig) DeepCopyInto(out *TaskTypeConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskTypeConfig.
+func (in *TaskTypeConfig) DeepCopy() *TaskTypeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskTypeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TaskTypeConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskTypeConfigList) DeepCopyInto(out *TaskTypeConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TaskTypeConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskTypeConfigList.
+func (in *TaskTypeConfigList) DeepCopy() *TaskTypeConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskTypeConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TaskTypeConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskTypeConfigSpec) DeepCopyInto(out *TaskTypeConfigSpec) {
+	*out = *in
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.StageTimeouts != nil {
+		in, out := &in.StageTimeouts, &out.StageTimeouts
+		*out = make(map[string]uint32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Concurrency != nil {
+		in, out := &in.Concurrency, &out.Concurrency
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureTolerate != nil {
+		in, out := &in.FailureTolerate, &out.FailureTolerate
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.CheckItems != nil {
+		in, out := &in.CheckItems, &out.CheckItems
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PostCheck != nil {
+		in, out := &in.PostCheck, &out.PostCheck
+		*out = new(PostCheckSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskTypeConfigSpec.
+func (in *TaskTypeConfigSpec) DeepCopy() *TaskTypeConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskTypeConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskTypeConfigStatus) DeepCopyInto(out *TaskTypeConfigStatus) {
+	*out = *in
+	if in.Stages != nil {
+		in, out := &in.Stages, &out.Stages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskTypeConfigStatus.
+func (in *TaskTypeConfigStatus) DeepCopy() *TaskTypeConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskTypeConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerificationProbe) DeepCopyInto(out *VerificationProbe) {
+	*out = *in
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(corev1.ExecAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPGet != nil {
+		in, out := &in.HTTPGet, &out.HTTPGet
+		*out = new(corev1.HTTPGetAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TCPSocket != nil {
+		in, out := &in.TCPSocket, &out.TCPSocket
+		*out = new(corev1.TCPSocketAction)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerificationProbe.
+func (in *VerificationProbe) DeepCopy() *VerificationProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(VerificationProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerificationSpec) DeepCopyInto(out *VerificationSpec) {
+	*out = *in
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = make([]VerificationProbe, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerificationSpec.
+func (in *VerificationSpec) DeepCopy() *VerificationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VerificationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VersionMapping) DeepCopyInto(out *VersionMapping) {
+	*out = *in
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VersionMapping.
+func (in *VersionMapping) DeepCopy() *VersionMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(VersionMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VersionMappingStatus) DeepCopyInto(out *VersionMappingStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VersionMappingStatus.
+func (in *VersionMappingStatus) DeepCopy() *VersionMappingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VersionMappingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadReference) DeepCopyInto(out *WorkloadReference) {
+	*out = *in
+	if in.NodeNames != nil {
+		in, out := &in.NodeNames, &out.NodeNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadReference.
+func (in *WorkloadReference) DeepCopy() *WorkloadReference {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadReference)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -28,14 +28,46 @@ type FakeOperationsV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeOperationsV1alpha1) BackupJobs() v1alpha1.BackupJobInterface {
+	return &FakeBackupJobs{c}
+}
+
+func (c *FakeOperationsV1alpha1) ConfigUpdateJobs() v1alpha1.ConfigUpdateJobInterface {
+	return &FakeConfigUpdateJobs{c}
+}
+
+func (c *FakeOperationsV1alpha1) DiagnoseJobs() v1alpha1.DiagnoseJobInterface {
+	return &FakeDiagnoseJobs{c}
+}
+
 func (c *FakeOperationsV1alpha1) ImagePrePullJobs() v1alpha1.ImagePrePullJobInterface {
 	return &FakeImagePrePullJobs{c}
 }
 
+func (c *FakeOperationsV1alpha1) NodeRestartJobs() v1alpha1.NodeRestartJobInterface {
+	return &FakeNodeRestartJobs{c}
+}
+
 func (c *FakeOperationsV1alpha1) NodeUpgradeJobs() v1alpha1.NodeUpgradeJobInterface {
 	return &FakeNodeUpgradeJobs{c}
 }
 
+func (c *FakeOperationsV1alpha1) OSUpgradeJobs() v1alpha1.OSUpgradeJobInterface {
+	return &FakeOSUpgradeJobs{c}
+}
+
+func (c *FakeOperationsV1alpha1) RestoreJobs() v1alpha1.RestoreJobInterface {
+	return &FakeRestoreJobs{c}
+}
+
+func (c *FakeOperationsV1alpha1) SupportBundleJobs() v1alpha1.SupportBundleJobInterface {
+	return &FakeSupportBundleJobs{c}
+}
+
+func (c *FakeOperationsV1alpha1) TaskTypeConfigs() v1alpha1.TaskTypeConfigInterface {
+	return &FakeTaskTypeConfigs{c}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeOperationsV1alpha1) RESTClient() rest.Interface {
@@ -18,6 +18,22 @@ limitations under the License.
 
 package v1alpha1
 
+type BackupJobExpansion interface{}
+
+type ConfigUpdateJobExpansion interface{}
+
+type DiagnoseJobExpansion interface{}
+
 type ImagePrePullJobExpansion interface{}
 
+type NodeRestartJobExpansion interface{}
+
 type NodeUpgradeJobExpansion interface{}
+
+type OSUpgradeJobExpansion interface{}
+
+type RestoreJobExpansion interface{}
+
+type SupportBundleJobExpansion interface{}
+
+type TaskTypeConfigExpansion interface{}
@@ -28,8 +28,16 @@ import (
 
 type OperationsV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	BackupJobsGetter
+	ConfigUpdateJobsGetter
+	DiagnoseJobsGetter
 	ImagePrePullJobsGetter
+	NodeRestartJobsGetter
 	NodeUpgradeJobsGetter
+	OSUpgradeJobsGetter
+	RestoreJobsGetter
+	SupportBundleJobsGetter
+	TaskTypeConfigsGetter
 }
 
 // OperationsV1alpha1Client is used to interact with features provided by the operations group.
@@ -37,14 +45,46 @@ type OperationsV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *OperationsV1alpha1Client) BackupJobs() BackupJobInterface {
+	return newBackupJobs(c)
+}
+
+func (c *OperationsV1alpha1Client) ConfigUpdateJobs() ConfigUpdateJobInterface {
+	return newConfigUpdateJobs(c)
+}
+
+func (c *OperationsV1alpha1Client) DiagnoseJobs() DiagnoseJobInterface {
+	return newDiagnoseJobs(c)
+}
+
 func (c *OperationsV1alpha1Client) ImagePrePullJobs() ImagePrePullJobInterface {
 	return newImagePrePullJobs(c)
 }
 
+func (c *OperationsV1alpha1Client) NodeRestartJobs() NodeRestartJobInterface {
+	return newNodeRestartJobs(c)
+}
+
 func (c *OperationsV1alpha1Client) NodeUpgradeJobs() NodeUpgradeJobInterface {
 	return newNodeUpgradeJobs(c)
 }
 
+func (c *OperationsV1alpha1Client) OSUpgradeJobs() OSUpgradeJobInterface {
+	return newOSUpgradeJobs(c)
+}
+
+func (c *OperationsV1alpha1Client) RestoreJobs() RestoreJobInterface {
+	return newRestoreJobs(c)
+}
+
+func (c *OperationsV1alpha1Client) SupportBundleJobs() SupportBundleJobInterface {
+	return newSupportBundleJobs(c)
+}
+
+func (c *OperationsV1alpha1Client) TaskTypeConfigs() TaskTypeConfigInterface {
+	return newTaskTypeConfigs(c)
+}
+
 // NewForConfig creates a new OperationsV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
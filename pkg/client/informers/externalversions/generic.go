@@ -70,10 +70,26 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Devices().V1beta1().DeviceModels().Informer()}, nil
 
 		// Group=operations, Version=v1alpha1
+	case operationsv1alpha1.SchemeGroupVersion.WithResource("backupjobs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Operations().V1alpha1().BackupJobs().Informer()}, nil
+	case operationsv1alpha1.SchemeGroupVersion.WithResource("configupdatejobs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Operations().V1alpha1().ConfigUpdateJobs().Informer()}, nil
+	case operationsv1alpha1.SchemeGroupVersion.WithResource("diagnosejobs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Operations().V1alpha1().DiagnoseJobs().Informer()}, nil
 	case operationsv1alpha1.SchemeGroupVersion.WithResource("imageprepulljobs"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Operations().V1alpha1().ImagePrePullJobs().Informer()}, nil
+	case operationsv1alpha1.SchemeGroupVersion.WithResource("noderestartjobs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Operations().V1alpha1().NodeRestartJobs().Informer()}, nil
 	case operationsv1alpha1.SchemeGroupVersion.WithResource("nodeupgradejobs"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Operations().V1alpha1().NodeUpgradeJobs().Informer()}, nil
+	case operationsv1alpha1.SchemeGroupVersion.WithResource("osupgradejobs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Operations().V1alpha1().OSUpgradeJobs().Informer()}, nil
+	case operationsv1alpha1.SchemeGroupVersion.WithResource("restorejobs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Operations().V1alpha1().RestoreJobs().Informer()}, nil
+	case operationsv1alpha1.SchemeGroupVersion.WithResource("supportbundlejobs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Operations().V1alpha1().SupportBundleJobs().Informer()}, nil
+	case operationsv1alpha1.SchemeGroupVersion.WithResource("tasktypeconfigs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Operations().V1alpha1().TaskTypeConfigs().Informer()}, nil
 
 		// Group=policy.kubeedge.io, Version=v1alpha1
 	case policyv1alpha1.SchemeGroupVersion.WithResource("serviceaccountaccesses"):
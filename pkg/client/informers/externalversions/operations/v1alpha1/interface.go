@@ -24,10 +24,26 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// BackupJobs returns a BackupJobInformer.
+	BackupJobs() BackupJobInformer
+	// ConfigUpdateJobs returns a ConfigUpdateJobInformer.
+	ConfigUpdateJobs() ConfigUpdateJobInformer
+	// DiagnoseJobs returns a DiagnoseJobInformer.
+	DiagnoseJobs() DiagnoseJobInformer
 	// ImagePrePullJobs returns a ImagePrePullJobInformer.
 	ImagePrePullJobs() ImagePrePullJobInformer
+	// NodeRestartJobs returns a NodeRestartJobInformer.
+	NodeRestartJobs() NodeRestartJobInformer
 	// NodeUpgradeJobs returns a NodeUpgradeJobInformer.
 	NodeUpgradeJobs() NodeUpgradeJobInformer
+	// OSUpgradeJobs returns a OSUpgradeJobInformer.
+	OSUpgradeJobs() OSUpgradeJobInformer
+	// RestoreJobs returns a RestoreJobInformer.
+	RestoreJobs() RestoreJobInformer
+	// SupportBundleJobs returns a SupportBundleJobInformer.
+	SupportBundleJobs() SupportBundleJobInformer
+	// TaskTypeConfigs returns a TaskTypeConfigInformer.
+	TaskTypeConfigs() TaskTypeConfigInformer
 }
 
 type version struct {
@@ -41,12 +57,52 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// BackupJobs returns a BackupJobInformer.
+func (v *version) BackupJobs() BackupJobInformer {
+	return &backupJobInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// ConfigUpdateJobs returns a ConfigUpdateJobInformer.
+func (v *version) ConfigUpdateJobs() ConfigUpdateJobInformer {
+	return &configUpdateJobInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// DiagnoseJobs returns a DiagnoseJobInformer.
+func (v *version) DiagnoseJobs() DiagnoseJobInformer {
+	return &diagnoseJobInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // ImagePrePullJobs returns a ImagePrePullJobInformer.
 func (v *version) ImagePrePullJobs() ImagePrePullJobInformer {
 	return &imagePrePullJobInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// NodeRestartJobs returns a NodeRestartJobInformer.
+func (v *version) NodeRestartJobs() NodeRestartJobInformer {
+	return &nodeRestartJobInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // NodeUpgradeJobs returns a NodeUpgradeJobInformer.
 func (v *version) NodeUpgradeJobs() NodeUpgradeJobInformer {
 	return &nodeUpgradeJobInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
+
+// OSUpgradeJobs returns a OSUpgradeJobInformer.
+func (v *version) OSUpgradeJobs() OSUpgradeJobInformer {
+	return &oSUpgradeJobInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// RestoreJobs returns a RestoreJobInformer.
+func (v *version) RestoreJobs() RestoreJobInformer {
+	return &restoreJobInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// SupportBundleJobs returns a SupportBundleJobInformer.
+func (v *version) SupportBundleJobs() SupportBundleJobInformer {
+	return &supportBundleJobInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// TaskTypeConfigs returns a TaskTypeConfigInformer.
+func (v *version) TaskTypeConfigs() TaskTypeConfigInformer {
+	return &taskTypeConfigInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
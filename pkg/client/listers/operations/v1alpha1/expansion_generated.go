@@ -18,10 +18,42 @@ limitations under the License.
 
 package v1alpha1
 
+// BackupJobListerExpansion allows custom methods to be added to
+// BackupJobLister.
+type BackupJobListerExpansion interface{}
+
+// ConfigUpdateJobListerExpansion allows custom methods to be added to
+// ConfigUpdateJobLister.
+type ConfigUpdateJobListerExpansion interface{}
+
+// DiagnoseJobListerExpansion allows custom methods to be added to
+// DiagnoseJobLister.
+type DiagnoseJobListerExpansion interface{}
+
 // ImagePrePullJobListerExpansion allows custom methods to be added to
 // ImagePrePullJobLister.
 type ImagePrePullJobListerExpansion interface{}
 
+// NodeRestartJobListerExpansion allows custom methods to be added to
+// NodeRestartJobLister.
+type NodeRestartJobListerExpansion interface{}
+
 // NodeUpgradeJobListerExpansion allows custom methods to be added to
 // NodeUpgradeJobLister.
 type NodeUpgradeJobListerExpansion interface{}
+
+// OSUpgradeJobListerExpansion allows custom methods to be added to
+// OSUpgradeJobLister.
+type OSUpgradeJobListerExpansion interface{}
+
+// RestoreJobListerExpansion allows custom methods to be added to
+// RestoreJobLister.
+type RestoreJobListerExpansion interface{}
+
+// SupportBundleJobListerExpansion allows custom methods to be added to
+// SupportBundleJobLister.
+type SupportBundleJobListerExpansion interface{}
+
+// TaskTypeConfigListerExpansion allows custom methods to be added to
+// TaskTypeConfigLister.
+type TaskTypeConfigListerExpansion interface{}
@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federation
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+)
+
+const (
+	// TemplateLabelKey is set on exported task templates, its value is the name of the template.
+	// The tasks created from a template in member clusters keep the name of the template,
+	// so their status can be collected back into a fleet report.
+	TemplateLabelKey = "operations.kubeedge.io/fleet-template"
+
+	karmadaPolicyAPIVersion = "policy.karmada.io/v1alpha1"
+)
+
+// ExportTemplate reads the task from the cluster and returns it as a template to be propagated
+// to member clusters. The template keeps only the name, labels, annotations and spec of the task,
+// the returned warnings list the fields which are specific to the source cluster.
+func ExportTemplate(ctx context.Context, client crdClientset.Interface, taskType, taskName string) (runtime.Object, []string, error) {
+	var warnings []string
+	switch taskType {
+	case TaskTypeUpgrade:
+		job, err := client.OperationsV1alpha1().NodeUpgradeJobs().Get(ctx, taskName, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(job.Spec.NodeNames) != 0 {
+			warnings = append(warnings, "spec.nodeNames is specific to the source cluster, use spec.labelSelector in templates")
+		}
+		return &v1alpha1.NodeUpgradeJob{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "NodeUpgradeJob"},
+			ObjectMeta: templateObjectMeta(job.ObjectMeta),
+			Spec:       job.Spec,
+		}, warnings, nil
+	case TaskTypePrePull:
+		job, err := client.OperationsV1alpha1().ImagePrePullJobs().Get(ctx, taskName, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(job.Spec.ImagePrePullTemplate.NodeNames) != 0 {
+			warnings = append(warnings, "spec.imagePrePullTemplate.nodeNames is specific to the source cluster, use spec.imagePrePullTemplate.labelSelector in templates")
+		}
+		return &v1alpha1.ImagePrePullJob{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "ImagePrePullJob"},
+			ObjectMeta: templateObjectMeta(job.ObjectMeta),
+			Spec:       job.Spec,
+		}, warnings, nil
+	}
+	return nil, nil, fmt.Errorf("unsupported task type %s", taskType)
+}
+
+func templateObjectMeta(meta metav1.ObjectMeta) metav1.ObjectMeta {
+	labels := make(map[string]string, len(meta.Labels)+1)
+	for k, v := range meta.Labels {
+		labels[k] = v
+	}
+	labels[TemplateLabelKey] = meta.Name
+	var annotations map[string]string
+	for k, v := range meta.Annotations {
+		// the configuration applied by kubectl describes the object in the source cluster
+		if k == "kubectl.kubernetes.io/last-applied-configuration" {
+			continue
+		}
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[k] = v
+	}
+	return metav1.ObjectMeta{
+		Name:        meta.Name,
+		Labels:      labels,
+		Annotations: annotations,
+	}
+}
+
+// KarmadaPropagationPolicy returns the Karmada ClusterPropagationPolicy which propagates
+// the template to the member clusters, the task objects are cluster scoped.
+func KarmadaPropagationPolicy(template runtime.Object, clusters []string) (map[string]interface{}, error) {
+	accessor, err := meta.Accessor(template)
+	if err != nil {
+		return nil, err
+	}
+	gvk := template.GetObjectKind().GroupVersionKind()
+	names := make([]interface{}, 0, len(clusters))
+	for _, cluster := range clusters {
+		names = append(names, cluster)
+	}
+	return map[string]interface{}{
+		"apiVersion": karmadaPolicyAPIVersion,
+		"kind":       "ClusterPropagationPolicy",
+		"metadata": map[string]interface{}{
+			"name": accessor.GetName() + "-fleet",
+		},
+		"spec": map[string]interface{}{
+			"resourceSelectors": []interface{}{
+				map[string]interface{}{
+					"apiVersion": gvk.GroupVersion().String(),
+					"kind":       gvk.Kind,
+					"name":       accessor.GetName(),
+				},
+			},
+			"placement": map[string]interface{}{
+				"clusterAffinity": map[string]interface{}{
+					"clusterNames": names,
+				},
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federation
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/client/clientset/versioned/fake"
+)
+
+func TestExportTemplate(t *testing.T) {
+	job := &v1alpha1.NodeUpgradeJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "upgrade",
+			UID:             "uid",
+			ResourceVersion: "10",
+			Labels:          map[string]string{"app": "edge"},
+			Annotations:     map[string]string{"kubectl.kubernetes.io/last-applied-configuration": "{}"},
+		},
+		Spec: v1alpha1.NodeUpgradeJobSpec{
+			Version:   "v1.17.0",
+			NodeNames: []string{"edge-1"},
+		},
+		Status: v1alpha1.NodeUpgradeJobStatus{State: api.TaskSuccessful},
+	}
+	client := fake.NewSimpleClientset(job)
+
+	obj, warnings, err := ExportTemplate(context.Background(), client, TaskTypeUpgrade, "upgrade")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	template := obj.(*v1alpha1.NodeUpgradeJob)
+	if template.Kind != "NodeUpgradeJob" || template.APIVersion != v1alpha1.SchemeGroupVersion.String() {
+		t.Errorf("unexpected type meta %v", template.TypeMeta)
+	}
+	if template.UID != "" || template.ResourceVersion != "" || template.Annotations != nil {
+		t.Errorf("expected the metadata of the source cluster to be dropped, got %v", template.ObjectMeta)
+	}
+	if template.Labels["app"] != "edge" || template.Labels[TemplateLabelKey] != "upgrade" {
+		t.Errorf("unexpected labels %v", template.Labels)
+	}
+	if template.Status.State != "" || template.Spec.Version != "v1.17.0" {
+		t.Errorf("expected the spec without status, got %v", template)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("expected a warning about node names, got %v", warnings)
+	}
+
+	policy, err := KarmadaPropagationPolicy(obj, []string{"region-a"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy["kind"] != "ClusterPropagationPolicy" {
+		t.Errorf("unexpected policy %v", policy)
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package federation aggregates the status of tasks run by multiple independent
+// cloudcores, e.g. one per region, into a single fleet report.
+package federation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	crdClientset "github.com/kubeedge/kubeedge/pkg/client/clientset/versioned"
+)
+
+const (
+	// TaskTypeUpgrade is the task type of NodeUpgradeJob
+	TaskTypeUpgrade = "upgrade"
+	// TaskTypePrePull is the task type of ImagePrePullJob
+	TaskTypePrePull = "prepull"
+)
+
+// Cluster is a member cluster managed by its own cloudcore
+type Cluster struct {
+	Name   string
+	Client crdClientset.Interface
+}
+
+// ClusterReport is the status of the task in a member cluster
+type ClusterReport struct {
+	Cluster string `json:"cluster"`
+	// State is the state of the task in the cluster, it is empty if the task is not found.
+	State api.State `json:"state,omitempty"`
+	// Nodes is the number of nodes of the task in each state
+	Nodes map[api.State]int `json:"nodes,omitempty"`
+	// FailedNodes are the names of the failed nodes
+	FailedNodes []string `json:"failedNodes,omitempty"`
+	// Error is set if the status of the task could not be read from the cluster
+	Error string `json:"error,omitempty"`
+}
+
+// FleetReport is the consolidated status of a task rolled out across the member clusters
+type FleetReport struct {
+	TaskType string      `json:"taskType"`
+	TaskName string      `json:"taskName"`
+	Time     metav1.Time `json:"time"`
+	// Nodes is the number of nodes of the task in each state over all the clusters
+	Nodes    map[api.State]int `json:"nodes"`
+	Clusters []ClusterReport   `json:"clusters"`
+}
+
+// Collect reads the status of the task from every cluster concurrently and consolidates it,
+// a cluster which cannot be read is reported with its error instead of failing the report.
+func Collect(ctx context.Context, clusters []Cluster, taskType, taskName string) (*FleetReport, error) {
+	if taskType != TaskTypeUpgrade && taskType != TaskTypePrePull {
+		return nil, fmt.Errorf("unsupported task type %s", taskType)
+	}
+	reports := make([]ClusterReport, len(clusters))
+	var wg sync.WaitGroup
+	for i := range clusters {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reports[i] = collectCluster(ctx, clusters[i], taskType, taskName)
+		}(i)
+	}
+	wg.Wait()
+	return Consolidate(taskType, taskName, reports), nil
+}
+
+// Consolidate builds the fleet report from the reports of the member clusters
+func Consolidate(taskType, taskName string, reports []ClusterReport) *FleetReport {
+	fleet := &FleetReport{
+		TaskType: taskType,
+		TaskName: taskName,
+		Time:     metav1.Now(),
+		Nodes:    map[api.State]int{},
+		Clusters: reports,
+	}
+	for _, report := range reports {
+		for state, count := range report.Nodes {
+			fleet.Nodes[state] += count
+		}
+	}
+	sort.Slice(fleet.Clusters, func(i, j int) bool {
+		return fleet.Clusters[i].Cluster < fleet.Clusters[j].Cluster
+	})
+	return fleet
+}
+
+func collectCluster(ctx context.Context, cluster Cluster, taskType, taskName string) ClusterReport {
+	report := ClusterReport{Cluster: cluster.Name}
+	var nodes []v1alpha1.TaskStatus
+	switch taskType {
+	case TaskTypeUpgrade:
+		job, err := cluster.Client.OperationsV1alpha1().NodeUpgradeJobs().Get(ctx, taskName, metav1.GetOptions{})
+		if err != nil {
+			report.Error = err.Error()
+			return report
+		}
+		report.State = job.Status.State
+		nodes = job.Status.Status
+	case TaskTypePrePull:
+		job, err := cluster.Client.OperationsV1alpha1().ImagePrePullJobs().Get(ctx, taskName, metav1.GetOptions{})
+		if err != nil {
+			report.Error = err.Error()
+			return report
+		}
+		report.State = job.Status.State
+		for _, status := range job.Status.Status {
+			if status.TaskStatus != nil {
+				nodes = append(nodes, *status.TaskStatus)
+			}
+		}
+	}
+	report.Nodes = countNodes(nodes)
+	for _, node := range nodes {
+		if node.State == api.TaskFailed {
+			report.FailedNodes = append(report.FailedNodes, node.NodeName)
+		}
+	}
+	sort.Strings(report.FailedNodes)
+	return report
+}
+
+func countNodes(nodes []v1alpha1.TaskStatus) map[api.State]int {
+	counts := make(map[api.State]int)
+	for _, node := range nodes {
+		state := node.State
+		if state == "" {
+			state = api.TaskInit
+		}
+		counts[state]++
+	}
+	return counts
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federation
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/client/clientset/versioned/fake"
+)
+
+func TestCollect(t *testing.T) {
+	job := &v1alpha1.NodeUpgradeJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "upgrade"},
+		Status: v1alpha1.NodeUpgradeJobStatus{
+			State: api.NodeUpgrading,
+			Status: []v1alpha1.TaskStatus{
+				{NodeName: "edge-2", State: api.TaskFailed},
+				{NodeName: "edge-1", State: api.TaskSuccessful},
+				{NodeName: "edge-3"},
+			},
+		},
+	}
+	clusters := []Cluster{
+		{Name: "region-b", Client: fake.NewSimpleClientset()},
+		{Name: "region-a", Client: fake.NewSimpleClientset(job)},
+	}
+
+	report, err := Collect(context.Background(), clusters, TaskTypeUpgrade, "upgrade")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedNodes := map[api.State]int{api.TaskFailed: 1, api.TaskSuccessful: 1, api.TaskInit: 1}
+	if !reflect.DeepEqual(report.Nodes, expectedNodes) {
+		t.Errorf("expected nodes %v, got %v", expectedNodes, report.Nodes)
+	}
+	if len(report.Clusters) != 2 || report.Clusters[0].Cluster != "region-a" || report.Clusters[1].Cluster != "region-b" {
+		t.Fatalf("expected clusters sorted by name, got %v", report.Clusters)
+	}
+	if report.Clusters[0].State != api.NodeUpgrading || !reflect.DeepEqual(report.Clusters[0].FailedNodes, []string{"edge-2"}) {
+		t.Errorf("unexpected report of region-a: %v", report.Clusters[0])
+	}
+	if report.Clusters[1].Error == "" {
+		t.Errorf("expected the missing task in region-b to be reported as an error")
+	}
+
+	if _, err = Collect(context.Background(), clusters, "unknown", "upgrade"); err == nil {
+		t.Errorf("expected error for unsupported task type")
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package envelope encrypts payloads for a recipient identified by the key pair of its
+// certificate. A one-time data key is derived from an ephemeral ECDH exchange with the
+// recipient's public key and used to encrypt the payload with AES-GCM, so only the holder
+// of the private key can read it.
+package envelope
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// AlgorithmECDHAESGCM is ECDH key agreement, HKDF-SHA256 key derivation and AES-256-GCM encryption
+const AlgorithmECDHAESGCM = "ECDH-HKDF-SHA256-A256GCM"
+
+// info binds the derived data keys to this usage
+var info = []byte("kubeedge envelope v1")
+
+// Envelope is an encrypted payload
+type Envelope struct {
+	Algorithm string
+	// EphemeralKey is the public key of the ephemeral ECDH key pair of the sender
+	EphemeralKey []byte
+	Nonce        []byte
+	Ciphertext   []byte
+}
+
+// Seal encrypts the plaintext for the owner of the public key, only ECDSA keys are supported
+func Seal(publicKey crypto.PublicKey, plaintext []byte) (*Envelope, error) {
+	recipient, err := ecdhPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	ephemeral, err := recipient.Curve().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ephemeral key: %v", err)
+	}
+	secret, err := ephemeral.ECDH(recipient)
+	if err != nil {
+		return nil, err
+	}
+	ephemeralKey := ephemeral.PublicKey().Bytes()
+	aead, err := newAEAD(secret, ephemeralKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %v", err)
+	}
+	return &Envelope{
+		Algorithm:    AlgorithmECDHAESGCM,
+		EphemeralKey: ephemeralKey,
+		Nonce:        nonce,
+		Ciphertext:   aead.Seal(nil, nonce, plaintext, ephemeralKey),
+	}, nil
+}
+
+// Open decrypts the envelope with the private key it was sealed for
+func Open(privateKey crypto.PrivateKey, envelope *Envelope) ([]byte, error) {
+	if envelope.Algorithm != AlgorithmECDHAESGCM {
+		return nil, fmt.Errorf("unsupported envelope algorithm %q", envelope.Algorithm)
+	}
+	ecdsaKey, ok := privateKey.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", privateKey)
+	}
+	key, err := ecdsaKey.ECDH()
+	if err != nil {
+		return nil, err
+	}
+	ephemeral, err := key.Curve().NewPublicKey(envelope.EphemeralKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ephemeral key: %v", err)
+	}
+	secret, err := key.ECDH(ephemeral)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(secret, envelope.EphemeralKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(envelope.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce size %d", len(envelope.Nonce))
+	}
+	plaintext, err := aead.Open(nil, envelope.Nonce, envelope.Ciphertext, envelope.EphemeralKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt envelope: %v", err)
+	}
+	return plaintext, nil
+}
+
+func ecdhPublicKey(publicKey crypto.PublicKey) (*ecdh.PublicKey, error) {
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		return key.ECDH()
+	case *ecdh.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+}
+
+// newAEAD derives the data key from the shared secret
+func newAEAD(secret, salt []byte) (cipher.AEAD, error) {
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, info), dataKey); err != nil {
+		return nil, fmt.Errorf("failed to derive data key: %v", err)
+	}
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
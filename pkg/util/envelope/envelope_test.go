@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+)
+
+func TestSealOpen(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext := []byte(`{"Secret":"default/registry"}`)
+
+	sealed, err := Seal(key.Public(), plaintext)
+	if err != nil {
+		t.Fatalf("seal failed: %v", err)
+	}
+	if bytes.Contains(sealed.Ciphertext, plaintext) {
+		t.Errorf("ciphertext contains the plaintext")
+	}
+	opened, err := Open(key, sealed)
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	if !bytes.Equal(opened, plaintext) {
+		t.Errorf("expected %s, got %s", plaintext, opened)
+	}
+
+	if _, err = Open(other, sealed); err == nil {
+		t.Errorf("expected error when opening with another key")
+	}
+	sealed.Ciphertext[0] ^= 0xff
+	if _, err = Open(key, sealed); err == nil {
+		t.Errorf("expected error when the ciphertext is tampered with")
+	}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Seal(rsaKey.Public(), plaintext); err == nil {
+		t.Errorf("expected error for rsa public key")
+	}
+}
@@ -18,10 +18,13 @@ package fsm
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"k8s.io/klog/v2"
 
 	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/apis/operations/v1alpha1"
 )
 
 type FSM struct {
@@ -44,6 +47,16 @@ type Event struct {
 	Action          api.Action
 	Msg             string
 	ExternalMessage string
+	// Environment is the execution environment reported by the edge node
+	Environment *v1alpha1.NodeEnvironment
+	// BytesDownloaded is the size of the images pulled by the edge node in the stage
+	BytesDownloaded int64
+	// Metadata is the custom metadata of the task echoed back by the edge node
+	Metadata map[string]string
+	// CommandDigest is the digest of the record of the commands the edge node ran for the task
+	CommandDigest string
+	// Elapsed is how long the edge node ran the stage for as it reported, it is zero if unknown
+	Elapsed time.Duration
 }
 
 func (e Event) UniqueName() string {
@@ -94,10 +107,24 @@ func (F *FSM) transitCheck(event Event) (api.State, api.State, error) {
 	if F.guard == nil {
 		return "", "", fmt.Errorf("guard is nil ")
 	}
-	nextState, ok := F.guard[string(currentState)+"/"+event.UniqueName()]
+	action := event.Action
+	if action == api.ActionPartialSuccess {
+		action = api.ActionSuccess
+	}
+	key := event.Type + "/" + string(action)
+	nextState, ok := F.guard[string(currentState)+"/"+key]
+	if !ok && TaskFinish(currentState) {
+		return "", "", fmt.Errorf("%s/%s unsupported event, %s is terminal", currentState, event.UniqueName(), currentState)
+	}
+	if !ok {
+		nextState, ok = api.TaskTerminalRule[key]
+	}
 	if !ok {
 		return "", "", fmt.Errorf(string(currentState)+"/"+event.UniqueName(), " unsupported event")
 	}
+	if event.Action == api.ActionPartialSuccess && nextState == api.TaskSuccessful {
+		nextState = api.TaskPartiallySucceeded
+	}
 	return currentState, nextState, nil
 }
 
@@ -122,8 +149,32 @@ func (F *FSM) Transit(event Event) error {
 	return nil
 }
 
+// KnownState returns whether the state is defined by the guard or the stage sequence of the FSM
+func (F *FSM) KnownState(state api.State) bool {
+	switch state {
+	case "", api.TaskInit, api.TaskUnknown:
+		return true
+	}
+	if TaskFinish(state) {
+		return true
+	}
+	for from, to := range F.stageSequence {
+		if from == state || to == state {
+			return true
+		}
+	}
+	for rule, next := range F.guard {
+		if next == state || strings.HasPrefix(rule, string(state)+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 func TaskFinish(state api.State) bool {
-	return state == api.TaskFailed || state == api.TaskSuccessful
+	return state == api.TaskFailed || state == api.TaskSuccessful || state == api.TaskCancelled ||
+		state == api.TaskNodeRemoved || state == api.TaskSkipped ||
+		state == api.TaskPartiallySucceeded || state == api.TaskDeadlineExceeded || state == api.TaskReverted
 }
 
 func (F *FSM) TaskStagCompleted(state api.State) bool {
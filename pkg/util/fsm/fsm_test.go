@@ -0,0 +1,219 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fsm
+
+import (
+	"reflect"
+	"testing"
+
+	api "github.com/kubeedge/kubeedge/pkg/apis/fsm/v1alpha1"
+)
+
+func TestKnownState(t *testing.T) {
+	upgradeFSM := (&FSM{}).Guard(api.UpgradeRule).StageSequence(api.UpdateStageSequence)
+	prePullFSM := (&FSM{}).Guard(api.PrePullRule).StageSequence(api.PrePullStageSequence)
+
+	tests := []struct {
+		name  string
+		fsm   *FSM
+		state api.State
+		known bool
+	}{
+		{name: "empty", fsm: upgradeFSM, state: "", known: true},
+		{name: "unknown state", fsm: upgradeFSM, state: api.TaskUnknown, known: true},
+		{name: "upgrade stage", fsm: upgradeFSM, state: api.UpgradingState, known: true},
+		{name: "upgrade finished", fsm: upgradeFSM, state: api.TaskSuccessful, known: true},
+		{name: "pulling in upgrade", fsm: upgradeFSM, state: api.PullingState, known: false},
+		{name: "pulling in prepull", fsm: prePullFSM, state: api.PullingState, known: true},
+		{name: "cancelling", fsm: prePullFSM, state: api.TaskCancelling, known: true},
+		{name: "cancelled", fsm: upgradeFSM, state: api.TaskCancelled, known: true},
+		{name: "drifted state", fsm: prePullFSM, state: "Verifying", known: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if known := test.fsm.KnownState(test.state); known != test.known {
+				t.Errorf("expected known %v, got %v", test.known, known)
+			}
+		})
+	}
+}
+
+func TestTerminalTransitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		state   api.State
+		event   Event
+		next    api.State
+		invalid bool
+	}{
+		{name: "success", state: api.UpgradingState, event: Event{Type: "Upgrade", Action: api.ActionSuccess}, next: api.TaskSuccessful},
+		{name: "partial success", state: api.UpgradingState, event: Event{Type: "Upgrade", Action: api.ActionPartialSuccess}, next: api.TaskPartiallySucceeded},
+		{name: "partial success of a stage", state: api.TaskChecking, event: Event{Type: "Check", Action: api.ActionPartialSuccess}, next: api.BackingUpState},
+		{name: "deadline exceeded", state: api.BackingUpState, event: Event{Type: api.EventDeadlineExceeded, Action: api.ActionFailure}, next: api.TaskDeadlineExceeded},
+		{name: "deadline exceeded while cancelling", state: api.TaskCancelling, event: Event{Type: api.EventDeadlineExceeded, Action: api.ActionFailure}, next: api.TaskDeadlineExceeded},
+		{name: "terminal", state: api.TaskPartiallySucceeded, event: Event{Type: api.EventDeadlineExceeded, Action: api.ActionFailure}, invalid: true},
+		{name: "cancel terminal", state: api.TaskSuccessful, event: Event{Type: api.EventCancel, Action: api.ActionSuccess}, invalid: true},
+		{name: "cancel idle node", state: api.BackingUpState, event: Event{Type: api.EventCancelIdle, Action: api.ActionSuccess}, next: api.TaskCancelled},
+		{name: "cancel idle terminal", state: api.TaskFailed, event: Event{Type: api.EventCancelIdle, Action: api.ActionSuccess}, invalid: true},
+		{name: "revert upgraded node", state: api.TaskSuccessful, event: Event{Type: api.EventRevert, Action: api.ActionSuccess}, next: api.RevertingState},
+		{name: "reverted", state: api.RevertingState, event: Event{Type: "Rollback", Action: api.ActionSuccess}, next: api.TaskReverted},
+		{name: "revert reverted", state: api.TaskReverted, event: Event{Type: api.EventRevert, Action: api.ActionSuccess}, invalid: true},
+		{name: "canary upgraded", state: api.UpgradingState, event: Event{Type: api.EventCanaryUpgraded, Action: api.ActionSuccess}, next: api.WaitingApprovalState},
+		{name: "approved", state: api.WaitingApprovalState, event: Event{Type: api.EventApprove, Action: api.ActionSuccess}, next: api.UpgradingState},
+		{name: "deadline exceeded waiting for approval", state: api.WaitingApprovalState, event: Event{Type: api.EventDeadlineExceeded, Action: api.ActionFailure}, next: api.TaskDeadlineExceeded},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state := test.state
+			taskFSM := (&FSM{}).Guard(api.UpgradeRule).CurrentFunc(func(string, string) (api.State, error) {
+				return state, nil
+			})
+			_, next, err := taskFSM.transitCheck(test.event)
+			if test.invalid {
+				if err == nil {
+					t.Errorf("expected an error, got next state %s", next)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if next != test.next {
+				t.Errorf("expected next state %s, got %s", test.next, next)
+			}
+		})
+	}
+}
+
+func TestOSUpgradeReboot(t *testing.T) {
+	tests := []struct {
+		name    string
+		state   api.State
+		event   Event
+		next    api.State
+		invalid bool
+	}{
+		{name: "applied without reboot", state: api.OSUpgradingState, event: Event{Type: api.EventOSUpgrade, Action: api.ActionSuccess}, next: api.VerifyingState},
+		{name: "reboot", state: api.OSUpgradingState, event: Event{Type: api.EventReboot, Action: api.ActionSuccess}, next: api.RebootingState},
+		{name: "reconnected", state: api.RebootingState, event: Event{Type: api.EventReconnect, Action: api.ActionSuccess}, next: api.VerifyingState},
+		{name: "rebooted by the hook", state: api.OSUpgradingState, event: Event{Type: api.EventReconnect, Action: api.ActionSuccess}, next: api.VerifyingState},
+		{name: "did not reboot", state: api.RebootingState, event: Event{Type: api.EventReconnect, Action: api.ActionFailure}, next: api.TaskFailed},
+		{name: "not back in time", state: api.RebootingState, event: Event{Type: api.EventTimeOut, Action: api.ActionFailure}, next: api.TaskFailed},
+		{name: "applied while rebooting", state: api.RebootingState, event: Event{Type: api.EventOSUpgrade, Action: api.ActionSuccess}, invalid: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state := test.state
+			nodeFSM := (&FSM{}).Guard(api.OSUpgradeRule).CurrentFunc(func(string, string) (api.State, error) {
+				return state, nil
+			})
+			_, next, err := nodeFSM.transitCheck(test.event)
+			if test.invalid {
+				if err == nil {
+					t.Errorf("expected an error, got next state %s", next)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if next != test.next {
+				t.Errorf("expected next state %s, got %s", test.next, next)
+			}
+		})
+	}
+
+	// the rebooting node keeps its stage, it is waited for until the stage times out
+	taskFSM := (&FSM{}).Guard(api.OSUpgradeRule).StageSequence(api.OSUpgradeStageSequence).CurrentFunc(func(string, string) (api.State, error) {
+		return api.OSUpgradingState, nil
+	})
+	if !taskFSM.KnownState(api.RebootingState) {
+		t.Errorf("expected %s to be known", api.RebootingState)
+	}
+	if taskFSM.TaskStagCompleted(api.RebootingState) {
+		t.Errorf("expected %s not to complete the stage", api.RebootingState)
+	}
+}
+
+func TestStageGraph(t *testing.T) {
+	for name, graph := range map[string]api.StageGraph{
+		"upgrade": api.UpgradeStages, "prepull": api.PrePullStages, "backup": api.BackupStages, "restore": api.RestoreStages,
+		"diagnose": api.DiagnoseStages, "bundle": api.SupportBundleStages, "restart": api.RestartStages,
+		"config": api.ConfigUpdateStages, "os": api.OSUpgradeStages,
+	} {
+		if err := graph.Validate(); err != nil {
+			t.Errorf("invalid stages of %s: %v", name, err)
+		}
+	}
+
+	// a custom pipeline: the nodes are drained before they are flashed, the flashed nodes may be
+	// burned in, and the nodes which fail to flash are restored
+	graph := api.StageGraph{
+		Stages: []api.Stage{
+			{State: "Draining", Event: "Drain"},
+			{State: "Flashing", Event: "Flash", OnFailure: "Restoring"},
+			{State: "BurningIn", Event: "BurnIn", Optional: true, EnterEvent: "StartBurnIn"},
+			{State: "Restoring", Event: "Restore", Optional: true, EnterEvent: "StartRestore", OnFailure: api.TaskFailed},
+		},
+	}
+	if err := graph.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	nodeState := api.State("")
+	nodeFSM := (&FSM{}).Guard(graph.Rule()).StageSequence(graph.StageSequence()).CurrentFunc(func(string, string) (api.State, error) {
+		return nodeState, nil
+	})
+	tests := []struct {
+		current api.State
+		event   Event
+		next    api.State
+	}{
+		{current: api.TaskInit, event: Event{Type: "Init", Action: api.ActionSuccess}, next: "Draining"},
+		{current: "Draining", event: Event{Type: "Drain", Action: api.ActionSuccess}, next: "Flashing"},
+		{current: "Flashing", event: Event{Type: "Flash", Action: api.ActionSuccess}, next: api.TaskSuccessful},
+		{current: "Flashing", event: Event{Type: "StartBurnIn", Action: api.ActionSuccess}, next: "BurningIn"},
+		{current: "Flashing", event: Event{Type: "Flash", Action: api.ActionFailure}, next: "Restoring"},
+		{current: "Flashing", event: Event{Type: api.EventTimeOut, Action: api.ActionFailure}, next: "Restoring"},
+		{current: "BurningIn", event: Event{Type: "BurnIn", Action: api.ActionSuccess}, next: api.TaskSuccessful},
+		{current: "BurningIn", event: Event{Type: "StartRestore", Action: api.ActionSuccess}, next: "Restoring"},
+		{current: "Restoring", event: Event{Type: "Restore", Action: api.ActionFailure}, next: api.TaskFailed},
+		{current: "Restoring", event: Event{Type: api.EventCancel, Action: api.ActionSuccess}, next: api.TaskCancelling},
+	}
+	for _, test := range tests {
+		nodeState = test.current
+		_, next, err := nodeFSM.transitCheck(test.event)
+		if err != nil || next != test.next {
+			t.Errorf("expected %s/%s to move to %s, got %s: %v", test.current, test.event.UniqueName(), test.next, next, err)
+		}
+	}
+	expected := map[api.State]api.State{"": "Draining", api.TaskInit: "Draining", "Draining": "Flashing", "Flashing": "BurningIn", "BurningIn": "Restoring"}
+	if sequence := graph.StageSequence(); !reflect.DeepEqual(sequence, expected) {
+		t.Errorf("expected stage sequence %v, got %v", expected, sequence)
+	}
+
+	for name, invalid := range map[string]api.StageGraph{
+		"no stage":         {},
+		"reserved state":   {Stages: []api.Stage{{State: api.TaskSuccessful, Event: "Done"}}},
+		"duplicated state": {Stages: []api.Stage{{State: "Flashing", Event: "Flash"}, {State: "Flashing", Event: "Flash"}}},
+		"optional first":   {Stages: []api.Stage{{State: "Flashing", Event: "Flash", Optional: true}}},
+		"unknown failure":  {Stages: []api.Stage{{State: "Flashing", Event: "Flash", OnFailure: "Restoring"}}},
+	} {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("expected the stages with %s to be invalid", name)
+		}
+	}
+}
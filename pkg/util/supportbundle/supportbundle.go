@@ -0,0 +1,165 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package supportbundle builds the archives of support bundles collected from edge nodes
+// and cloudcore. Everything put in a bundle is meant to be attached to public bug reports,
+// so configs and logs are redacted before they are archived.
+package supportbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Redacted replaces the values of secrets in the bundle
+const Redacted = "<redacted>"
+
+// sensitiveWords are the parts of the config keys whose values are redacted
+var sensitiveWords = []string{"token", "password", "secret", "credential", "privatekey", "accesskey"}
+
+// sensitiveText matches secrets assigned in free text, e.g. token=xxx in a log line
+var sensitiveText = regexp.MustCompile(`(?i)((?:token|password|secret)["']?\s*[:=]\s*["']?)[^\s"',]+`)
+
+// sensitiveFlag matches secrets passed as command line flags, e.g. --token xxx
+var sensitiveFlag = regexp.MustCompile(`(?i)(--?[\w-]*(?:token|password|secret)[\w-]*\s+["']?)[^\s"'-][^\s"']*`)
+
+// RedactConfig returns the YAML or JSON config with the values of sensitive keys redacted,
+// the result is YAML.
+func RedactConfig(data []byte) ([]byte, error) {
+	var config interface{}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %v", err)
+	}
+	return yaml.Marshal(redact(config))
+}
+
+func redact(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if sensitiveKey(key) {
+				v[key] = Redacted
+				continue
+			}
+			v[key] = redact(item)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redact(v[i])
+		}
+	}
+	return value
+}
+
+// sensitiveKey returns whether the value of the key is a secret, keys of file
+// paths such as tlsPrivateKeyFile are kept as they only locate the secret.
+func sensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	if strings.HasSuffix(key, "file") {
+		return false
+	}
+	for _, word := range sensitiveWords {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactText redacts the secrets assigned in free text such as logs
+func RedactText(data []byte) []byte {
+	return sensitiveText.ReplaceAll(data, []byte("${1}"+Redacted))
+}
+
+// RedactCommand redacts the secrets passed to a command line, assigned or as the values of flags
+func RedactCommand(command string) string {
+	return sensitiveFlag.ReplaceAllString(string(RedactText([]byte(command))), "${1}"+Redacted)
+}
+
+// TailLines returns the last n lines of data
+func TailLines(data []byte, n int) []byte {
+	data = bytes.TrimRight(data, "\n")
+	for i := len(data) - 1; i >= 0; i-- {
+		if data[i] != '\n' {
+			continue
+		}
+		n--
+		if n == 0 {
+			return data[i+1:]
+		}
+	}
+	return data
+}
+
+// Writer writes the files of a bundle to a gzipped tar archive
+type Writer struct {
+	gz  *gzip.Writer
+	tar *tar.Writer
+	now time.Time
+}
+
+// NewWriter returns a Writer writing the archive to w, Close must be called to complete the archive
+func NewWriter(w io.Writer) *Writer {
+	gz := gzip.NewWriter(w)
+	return &Writer{
+		gz:  gz,
+		tar: tar.NewWriter(gz),
+		now: time.Now(),
+	}
+}
+
+// Add writes the file to the archive
+func (w *Writer) Add(name string, data []byte) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: w.now,
+	}
+	if err := w.tar.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write header of %s: %v", name, err)
+	}
+	if _, err := w.tar.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %v", name, err)
+	}
+	return nil
+}
+
+// AddJSON writes the value to the archive as an indented JSON file
+func (w *Writer) AddJSON(name string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %v", name, err)
+	}
+	return w.Add(name, data)
+}
+
+// Close completes the archive
+func (w *Writer) Close() error {
+	if err := w.tar.Close(); err != nil {
+		return err
+	}
+	return w.gz.Close()
+}
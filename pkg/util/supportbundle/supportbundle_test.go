@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package supportbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRedactConfig(t *testing.T) {
+	config := `
+modules:
+  edgeHub:
+    token: 0123456789abcdef
+    tlsPrivateKeyFile: /etc/kubeedge/certs/server.key
+    server: 10.0.0.1:10000
+  taskManager:
+    recordStore:
+      s3:
+        credentialsSecret: s3-keys
+`
+	data, err := RedactConfig([]byte(config))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	redacted := string(data)
+	for _, secret := range []string{"0123456789abcdef", "s3-keys"} {
+		if strings.Contains(redacted, secret) {
+			t.Errorf("secret %s is not redacted:\n%s", secret, redacted)
+		}
+	}
+	for _, kept := range []string{"/etc/kubeedge/certs/server.key", "10.0.0.1:10000"} {
+		if !strings.Contains(redacted, kept) {
+			t.Errorf("%s is redacted:\n%s", kept, redacted)
+		}
+	}
+}
+
+func TestRedactText(t *testing.T) {
+	log := `I1015 connect with token=0123456789abcdef to cloudcore
+I1015 "password": "hunter2", "user": "admin"`
+	redacted := string(RedactText([]byte(log)))
+	if strings.Contains(redacted, "0123456789abcdef") || strings.Contains(redacted, "hunter2") {
+		t.Errorf("secrets are not redacted: %s", redacted)
+	}
+	if !strings.Contains(redacted, "admin") {
+		t.Errorf("unexpected redaction: %s", redacted)
+	}
+}
+
+func TestRedactCommand(t *testing.T) {
+	command := "keadm join --token 0123456789abcdef --cloudcore-ipport 10.0.0.1:10000 --password=hunter2 --image kubeedge/installation-package:v1.19.0"
+	redacted := RedactCommand(command)
+	expected := "keadm join --token <redacted> --cloudcore-ipport 10.0.0.1:10000 --password=<redacted> --image kubeedge/installation-package:v1.19.0"
+	if redacted != expected {
+		t.Errorf("expected %q, got %q", expected, redacted)
+	}
+}
+
+func TestTailLines(t *testing.T) {
+	data := []byte("1\n2\n3\n4\n")
+	if got := string(TailLines(data, 2)); got != "3\n4" {
+		t.Errorf("expected last 2 lines, got %q", got)
+	}
+	if got := string(TailLines(data, 10)); got != "1\n2\n3\n4" {
+		t.Errorf("expected all lines, got %q", got)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := NewWriter(buf)
+	if err := w.Add("version.txt", []byte("v1.17.0")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.AddJSON("tasks.json", map[string]string{"upgrade": "Successful"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	gz, err := gzip.NewReader(buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reader := tar.NewReader(gz)
+	files := map[string]string{}
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		files[header.Name] = string(data)
+	}
+	if files["version.txt"] != "v1.17.0" || !strings.Contains(files["tasks.json"], `"upgrade": "Successful"`) {
+		t.Errorf("unexpected files %v", files)
+	}
+}